@@ -14,6 +14,8 @@ TRUSTED_CERTS=${CERTS_DIR}/trustedca
 ROOT_CA_DIR=${TRUSTED_CERTS}/root
 ENDORSEMENTS_CA_DIR=${CERTS_DIR}/endorsement
 PRIVACY_CA_DIR=${TRUSTED_CERTS}/privacy-ca
+FLAVOR_CA_DIR=${TRUSTED_CERTS}/flavor
+IMPORTED_FLAVOR_SIGNING_DIR=${TRUSTED_CERTS}/imported-flavor-signing
 TRUSTED_KEYS_DIR=${CONFIG_PATH}/trusted-keys
 CERTDIR_TRUSTEDJWTCERTS=${CERTS_DIR}/trustedjwt
 CREDENTIAL_PATH=$CONFIG_PATH/credentials
@@ -22,7 +24,7 @@ SCHEMA_PATH=$CONFIG_PATH/schema
 
 if [ ! -f $CONFIG_PATH/.setup_done ]; then
   for directory in $LOG_PATH $CONFIG_PATH $CERTS_DIR $TRUSTED_CERTS $ROOT_CA_DIR $ENDORSEMENTS_CA_DIR $PRIVACY_CA_DIR \
-   $TRUSTED_KEYS_DIR $CERTDIR_TRUSTEDJWTCERTS $CREDENTIAL_PATH $TEMPLATES_PATH $SCHEMA_PATH ; do
+   $FLAVOR_CA_DIR $IMPORTED_FLAVOR_SIGNING_DIR $TRUSTED_KEYS_DIR $CERTDIR_TRUSTEDJWTCERTS $CREDENTIAL_PATH $TEMPLATES_PATH $SCHEMA_PATH ; do
     mkdir -p $directory
     if [ $? -ne 0 ]; then
       echo "Cannot create directory: $directory"
@@ -45,11 +45,13 @@ TEMPLATES_PATH=$CONFIG_PATH/templates
 CERTDIR_TRUSTEDJWTCERTS=$CERTS_PATH/trustedjwt
 CERTDIR_TRUSTEDCAS=$CERTS_PATH/trustedca/root
 CERTDIR_TRUSTEDPCAS=$CERTS_PATH/trustedca/privacy-ca
+CERTDIR_TRUSTEDFLAVORCAS=$CERTS_PATH/trustedca/flavor
+CERTDIR_IMPORTEDFLAVORSIGNING=$CERTS_PATH/trustedca/imported-flavor-signing
 KEYS_PATH=$CONFIG_PATH/trusted-keys
 CERTDIR_ENDORSEMENTCA=$CERTS_PATH/endorsement
 CREDENTIAL_PATH=$CONFIG_PATH/credentials
 
-for directory in $BIN_PATH $LOG_PATH $CONFIG_PATH $CERTS_PATH $SCHEMA_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $CERTDIR_TRUSTEDPCAS $KEYS_PATH $CERTDIR_ENDORSEMENTCA $CREDENTIAL_PATH; do
+for directory in $BIN_PATH $LOG_PATH $CONFIG_PATH $CERTS_PATH $SCHEMA_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $CERTDIR_TRUSTEDPCAS $CERTDIR_TRUSTEDFLAVORCAS $CERTDIR_IMPORTEDFLAVORSIGNING $KEYS_PATH $CERTDIR_ENDORSEMENTCA $CREDENTIAL_PATH; do
   # mkdir -p will return 0 if directory exists or is a symlink to an existing directory or directory and parents can be created
   mkdir -p $directory
   if [ $? -ne 0 ]; then
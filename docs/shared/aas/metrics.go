@@ -0,0 +1,40 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package aas
+
+//
+// swagger:operation GET /metrics Metrics GetMetrics
+// ---
+// description: |
+//   GetMetrics exposes the metrics of the service in the Prometheus text exposition format.
+//   In addition to the Go runtime and process metrics, the request latency and status codes of every route are reported
+//   by isecl_http_request_duration_seconds and isecl_http_requests_total.
+//
+//   | Metric                                   | Description |
+//   |------------------------------------------|-------------|
+//   | isecl_aas_tokens_issued_total            | JWT tokens issued, by type of token (user, custom_claims) |
+//   | isecl_aas_defender_bans_total            | Users banned after exceeding the maximum login attempts |
+//   | isecl_aas_defender_rejected_logins_total | Login attempts rejected because the user is banned |
+//
+//   Returns - The metrics of the service.
+//
+// x-permissions: metrics:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//   - text/plain
+// responses:
+//   '200':
+//     description: Successfully retrieved the metrics.
+//     content: text/plain
+//   '401':
+//     description: The caller does not hold the metrics:retrieve permission.
+//
+// x-sample-call-endpoint: https://authservice.com:8443/aas/v1/metrics
+// x-sample-call-output: |
+//   # HELP isecl_http_requests_total Number of http requests served, by route, method and status code
+//   # TYPE isecl_http_requests_total counter
+//   isecl_http_requests_total{code="200",method="GET",route="/aas/v1/token",service="AAS"} 42
@@ -0,0 +1,91 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cms
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+
+// AcmeDirectory response payload
+// swagger:response AcmeDirectory
+type AcmeDirectory struct {
+	// in:body
+	Body cms.AcmeDirectory
+}
+
+// AcmeEabKey response payload
+// swagger:response AcmeEabKey
+type AcmeEabKey struct {
+	// in:body
+	Body cms.AcmeEabKey
+}
+
+// swagger:operation GET /acme/directory ACME GetAcmeDirectory
+// ---
+// description: |
+//   Retrieves the directory of the CMS ACME server. CMS implements the RFC 8555 ACME protocol for
+//   certificates issued by its TLS CA, so that standard ACME clients such as cert-manager can obtain and
+//   renew TLS certificates. The newNonce, newAccount, newOrder, order, authz, challenge, finalize and cert
+//   resources listed in the directory follow RFC 8555.
+//
+//   Accounts must be created with an external account binding key obtained from POST /acme/eab-keys.
+//   An account is authorized for the SANs of the TLS CertApprover roles of the user that created its
+//   binding key, so authorizations are valid without completing a challenge. The roles are honoured until
+//   the bearer token used to create the binding key expires, a new binding key and account are needed after
+//   that. The CSR submitted when finalizing an order is validated the same way as CSRs submitted to
+//   POST /certificates.
+//
+// produces:
+// - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the ACME directory.
+//     schema:
+//       "$ref": "#/definitions/AcmeDirectory"
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/acme/directory
+// x-sample-call-output: |
+//    {
+//        "newNonce": "https://cms.com:8445/cms/v1/acme/new-nonce",
+//        "newAccount": "https://cms.com:8445/cms/v1/acme/new-account",
+//        "newOrder": "https://cms.com:8445/cms/v1/acme/new-order",
+//        "meta": {
+//            "externalAccountRequired": true
+//        }
+//    }
+// ---
+
+// swagger:operation POST /acme/eab-keys ACME CreateAcmeEabKey
+// ---
+// description: |
+//   Creates an ACME external account binding key. The key can be used once to create an ACME account,
+//   which is then authorized for the SANs of the TLS CertApprover roles of the caller. A valid bearer token
+//   with at least one CertApprover role having certType=TLS in its context is required to authorize this
+//   REST call. The HMAC key is base64url encoded and is only returned in this response. The roles of the key
+//   and of the account created with it expire along with the bearer token, at roles_expire_at.
+//
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// responses:
+//   '201':
+//     description: Successfully created the external account binding key.
+//     schema:
+//       "$ref": "#/definitions/AcmeEabKey"
+//   '401':
+//     description: Unauthorized request
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/acme/eab-keys
+// x-sample-call-output: |
+//    {
+//        "key_id": "0wHyRFrFfBS1hf4VYz5-dQ",
+//        "hmac_key": "c2VjcmV0LWhtYWMta2V5LWJhc2U2NHVybC1lbmNvZGVk",
+//        "role_contexts": [
+//            "CN=WLS TLS Certificate;SAN=wls.example.com;certType=TLS"
+//        ],
+//        "roles_expire_at": "2021-07-14T09:01:55Z",
+//        "created_at": "2021-07-14T08:01:55.182611Z"
+//    }
+// ---
@@ -0,0 +1,132 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cms
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+
+// RevocationRequest request payload
+// swagger:parameters RevocationRequest
+type RevocationRequest struct {
+	// in:body
+	Body cms.RevocationRequest
+}
+
+// IssuedCertificate response payload
+// swagger:response IssuedCertificate
+type IssuedCertificate struct {
+	// in:body
+	Body cms.IssuedCertificate
+}
+
+// swagger:operation POST /certificates/{serialNumber}/revoke Certificate RevokeCertificate
+// ---
+// description: |
+//   Revokes a certificate issued by CMS. The revoked certificate is listed in the CRL of its issuing CA
+//   and reported as revoked by the OCSP responder. A valid bearer token with the CertRevoker role is
+//   required to authorize this REST call.
+//
+//   Valid revocation reasons are unspecified, keyCompromise, cACompromise, affiliationChanged, superseded,
+//   cessationOfOperation and privilegeWithdrawn. The reason defaults to unspecified.
+//
+// security:
+//  - bearerAuth: []
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: serialNumber
+//   description: Hex encoded serial number of the certificate to be revoked.
+//   in: path
+//   required: true
+//   type: string
+// - name: request body
+//   in: body
+//   required: false
+//   schema:
+//     "$ref": "#/definitions/RevocationRequest"
+// responses:
+//   '200':
+//     description: Successfully revoked the certificate.
+//     schema:
+//       "$ref": "#/definitions/IssuedCertificate"
+//   '400':
+//     description: Invalid serial number or revocation reason
+//   '401':
+//     description: Unauthorized request
+//   '404':
+//     description: Certificate was not issued by CMS
+//   '409':
+//     description: Certificate is already revoked
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/certificates/2a/revoke
+// x-sample-call-input: |
+//    {
+//        "reason": "keyCompromise"
+//    }
+// x-sample-call-output: |
+//    {
+//        "serial_number": "2a",
+//        "issuing_ca": "Signing",
+//        "subject": "CN=HVS Flavor Signing Certificate",
+//        "not_before": "2021-06-01T10:12:31Z",
+//        "not_after": "2022-06-01T10:12:31Z",
+//        "issued_at": "2021-06-01T10:12:31.409224Z",
+//        "revoked_at": "2021-07-14T08:01:55.182611Z",
+//        "revocation_reason": "keyCompromise"
+//    }
+// ---
+
+// swagger:operation GET /crl/{issuingCa} CRL GetCRL
+// ---
+// description: |
+//   Retrieves the DER encoded certificate revocation list of a CMS CA. The CRL is signed by the
+//   issuing CA and is published in the CRL distribution point extension of issued certificates.
+//
+// produces:
+// - application/pkix-crl
+// parameters:
+// - name: issuingCa
+//   description: Issuing CA such as root, TLS, TLS-Client and Signing.
+//   in: path
+//   required: true
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully retrieved the CRL.
+//   '404':
+//     description: Invalid issuing CA
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/crl/Signing
+// ---
+
+// swagger:operation POST /ocsp OCSP GetOCSPResponse
+// ---
+// description: |
+//   OCSP responder for certificates issued by CMS as described in RFC 6960. The response is signed by
+//   the CA that issued the certificate. The request can also be sent as a GET request with the base64
+//   encoded OCSP request appended to the path.
+//
+// consumes:
+// - application/ocsp-request
+// produces:
+// - application/ocsp-response
+// parameters:
+// - name: request body
+//   in: body
+//   required: true
+//   description: DER encoded OCSP request.
+//   schema:
+//     type: string
+//     format: binary
+// responses:
+//   '200':
+//     description: DER encoded OCSP response.
+//   '415':
+//     description: Content type not supported
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/ocsp
+// ---
@@ -121,7 +121,7 @@ type CaCertificateCollection struct {
 //     schema:
 //       $ref: "#/definitions/CaCertificate"
 //   '400':
-//     description: Invalid CACertificate in request body/Invalid type, only root or endorsement ca certificate can be added
+//     description: Invalid CACertificate in request body/Invalid type, only root, endorsement or flavor ca certificate can be added
 //   '415':
 //     description: Invalid Accept/Content-Type Header in Request - should be application/json
 //   '500':
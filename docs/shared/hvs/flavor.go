@@ -31,6 +31,27 @@ type SignedFlavorCollection struct {
 	Body hvs.SignedFlavorCollection
 }
 
+// Flavor lifecycle API request payload
+// swagger:parameters FlavorLifecycle
+type FlavorLifecycle struct {
+	// in:body
+	Body hvs.FlavorLifecycle
+}
+
+// Flavor export API response payload and flavor import API request payload
+// swagger:parameters FlavorBundle
+type FlavorBundle struct {
+	// in:body
+	Body hvs.FlavorBundle
+}
+
+// Flavor import API response payload
+// swagger:parameters FlavorImportReport
+type FlavorImportReport struct {
+	// in:body
+	Body hvs.FlavorImportReport
+}
+
 // ---
 //
 // swagger:operation GET /flavors Flavors Search-Flavors
@@ -40,7 +61,7 @@ type SignedFlavorCollection struct {
 //   A flavor is a set of measurements and metadata organized in a flexible format that allows for ease of further extension. The measurements included in the flavor pertain to various hardware, software and feature categories, and their respective metadata sections provide descriptive information.
 //
 //   The four current flavor categories:
-//   PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE (See the product guide for a detailed explanation)
+//   PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE, IMA (See the product guide for a detailed explanation)
 //
 //   When a flavor is created, it is associated with a flavor group. This means that the measurements for that flavor type are deemed acceptable to obtain a trusted status. If a host, associated with the same flavor group, matches the measurements contained within that flavor, the host is trusted for that particular flavor category (dependent on the flavor group policy). Searches for Flavor records. The identifying parameter can be specified as query to search flavors which will return flavor collection as a result.
 //
@@ -79,6 +100,23 @@ type SignedFlavorCollection struct {
 //   in: query
 //   type: string
 //   required: false
+// - name: limit
+//   description: Limits the number of flavors in a page of the response. When the page is full, the next link of the response retrieves the following page.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   required: false
+// - name: after
+//   description: Returns the flavors following the one with this ID, as set by the next link of the previous page.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: fields
+//   description: Comma separated list of the fields of the flavors to include in the response, all the fields are included by default.
+//   in: query
+//   type: string
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -163,7 +201,7 @@ type SignedFlavorCollection struct {
 //    | flavors                        | (Optional) A collection of flavors in the defined flavor format. No other parameters are needed in this case.
 //    | signed_flavors                 | (Optional) This is collection of signed flavors consisting of flavor and signature provided by user. |
 //    | flavorgroup_names              | (Optional) Flavor group names that the created flavor(s) will be associated with. If not provided, created flavor will be associated with automatic flavor group. |
-//    | partial_flavor_types           | (Optional) List array input of flavor types to be imported from a host. Partial flavor type can be any of the following: PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE. Can be provided with the host connection string. See the product guide for more details on how flavor types are broken down for each host type. |
+//    | partial_flavor_types           | (Optional) List array input of flavor types to be imported from a host. Partial flavor type can be any of the following: PLATFORM, OS, ASSET_TAG, HOST_UNIQUE, SOFTWARE, IMA. Can be provided with the host connection string. See the product guide for more details on how flavor types are broken down for each host type. IMA flavors are only created when requested and contain all the file measurements of the host's IMA log in their allowlist. |
 //
 // x-permissions: flavors:create
 // security:
@@ -192,6 +230,13 @@ type SignedFlavorCollection struct {
 //   required: true
 //   enum:
 //     - application/json
+// - name: Prefer
+//   description: With respond-async, the request is processed as a job and the response only holds the job, see GET /jobs/{job_id}
+//   in: header
+//   type: string
+//   required: false
+//   enum:
+//     - respond-async
 // responses:
 //   '201':
 //     description: Successfully created the flavors.
@@ -199,6 +244,12 @@ type SignedFlavorCollection struct {
 //       application/json
 //     schema:
 //       $ref: "#/definitions/SignedFlavorCollection"
+//   '202':
+//     description: Successfully started the job creating the flavors, its result is the SignedFlavorCollection, the Location header links to the job.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Job"
 //   '400':
 //     description: Invalid request body provided
 //   '415':
@@ -468,7 +519,8 @@ type SignedFlavorCollection struct {
 //
 // description: |
 //   Retrieves a flavor.
-//   Returns - The serialized Signed Flavor Go struct object that was retrieved.
+//   Returns - The serialized Signed Flavor Go struct object that was retrieved. The lifecycle is omitted for the active
+//   flavors without a superseding flavor or an expiry date.
 // x-permissions: flavors:retrieve
 // security:
 //  - bearerAuth: []
@@ -564,3 +616,304 @@ type SignedFlavorCollection struct {
 // x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/f66ac31d-124d-418e-8200-2abf414a9adf
 
 // ---
+
+// swagger:operation PUT /flavors/{flavor_id}/lifecycle Flavors Update-Flavor-Lifecycle
+// ---
+//
+// description: |
+//   Sets the lifecycle of a flavor. Flavors are immutable, their lifecycle is kept apart from the signed flavor
+//   content so that a flavor can be retired without deleting it. The hosts associated with the flavor are added to
+//   the flavor verification queue and their new trust reports flag the hosts still matching the flavor:
+//
+//    | State      | Hosts matching the flavor |
+//    |------------|---------------------------|
+//    | active     | are trusted. |
+//    | deprecated | stay trusted with a fault.FlavorDeprecated warning until the grace_period_end, if any, then become untrusted with a fault.FlavorGracePeriodEnded fault. |
+//    | revoked    | are untrusted with a fault.FlavorRevoked fault. |
+//
+//   Whatever the state, the hosts matching the flavor become untrusted with a fault.FlavorExpired fault once the
+//   expires_at date has passed. The superseded_by field records the flavor replacing this one, it is reported in the
+//   description of the faults. A revoked flavor cannot be reinstated.
+//
+// x-permissions: flavors:store
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: flavor_id
+//   description: Unique UUID of the flavor.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorLifecycle"
+// - name: Content-Type
+//   required: true
+//   in: header
+//   type: string
+// - name: Accept
+//   required: true
+//   in: header
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully updated the lifecycle of the flavor.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/SignedFlavor"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: No flavor with the provided flavor ID found.
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/f66ac31d-124d-418e-8200-2abf414a9adf/lifecycle
+// x-sample-call-input: |
+//    {
+//        "state": "deprecated",
+//        "grace_period_end": "2021-09-30T00:00:00Z",
+//        "superseded_by": "3d1a8b04-5fd3-4a0b-8a6c-b3e4e2e6c1f0"
+//    }
+// x-sample-call-output: |
+//    {
+//        "flavor": {
+//            "meta": {
+//                "id": "f66ac31d-124d-418e-8200-2abf414a9adf",
+//                "description": {
+//                    "flavor_part": "PLATFORM",
+//                    "label": "INTEL_IntelCorporation_SE5C620.86B.00.01.0014.070920180847_TPM2.0_07-09-2018"
+//                }
+//            },
+//            ...
+//        },
+//        "signature": "aas8/Nv7yYuwx2ZIOMrXFpNf333tBJgr87Dpo7Z5jjUR36Estlb8pYaTGN4Dz9JtbXZy2uIBLr1wjhkHVWm2r1FQq+2yJznXG...",
+//        "lifecycle": {
+//            "state": "deprecated",
+//            "grace_period_end": "2021-09-30T00:00:00Z",
+//            "superseded_by": "3d1a8b04-5fd3-4a0b-8a6c-b3e4e2e6c1f0"
+//        }
+//    }
+// ---
+
+// swagger:operation GET /flavors/export Flavors Export-Flavors
+// ---
+//
+// description: |
+//   Exports the selected flavors, flavorgroups and flavor templates as a flavor bundle to be imported in another HVS.
+//   The flavors of the selected flavorgroups and the flavor templates linked to them are exported along with the
+//   flavorgroups. HOST_UNIQUE and ASSET_TAG flavors are specific to a host and are not exported.
+//
+//   The flavors keep their original signatures. The content of the bundle is signed with the flavor signing key of
+//   the HVS and the bundle carries the flavor signing certificate chain, the HVS importing the bundle verifies it
+//   against its trusted flavor CAs. When the bundle holds flavors imported from another HVS, the imported flavor
+//   signing certificates are added to the bundle to verify them.
+//
+// x-permissions: flavors:export
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique UUID of a flavor to export, can be given more than once.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: flavorgroupId
+//   description: Unique UUID of a flavorgroup to export, can be given more than once.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: flavortemplateId
+//   description: Unique UUID of a flavor template to export, can be given more than once.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: Accept
+//   required: true
+//   in: header
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully exported the flavor bundle.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/FlavorBundle"
+//   '400':
+//     description: Invalid query parameters provided or nothing selected for export
+//   '404':
+//     description: A selected flavor, flavorgroup or flavor template was not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/export?flavorgroupId=a9d9e8a7-6ca2-4d3f-8c9f-3c0b5f8c1a11
+// x-sample-call-output: |
+//    {
+//        "content": {
+//            "exported_at": "2021-06-01T10:00:00Z",
+//            "signed_flavors": [
+//                {
+//                    "flavor": {
+//                        "meta": {
+//                            "id": "f66ac31d-124d-418e-8200-2abf414a9adf",
+//                            "description": {
+//                                "flavor_part": "PLATFORM",
+//                                "label": "INTEL_IntelCorporation_SE5C620.86B.00.01.0014.070920180847_TPM2.0_07-09-2018"
+//                            }
+//                        },
+//                        ...
+//                    },
+//                    "signature": "aas8/Nv7yYuwx2ZIOMrXFpNf333tBJgr87Dpo7Z5jjUR36Estlb8pYaTGN4Dz9JtbXZy2uIBLr1wjhkHVWm2r1FQq+2yJznXG..."
+//                }
+//            ],
+//            "flavorgroups": [
+//                {
+//                    "id": "a9d9e8a7-6ca2-4d3f-8c9f-3c0b5f8c1a11",
+//                    "name": "site-a",
+//                    "flavorIds": [
+//                        "f66ac31d-124d-418e-8200-2abf414a9adf"
+//                    ],
+//                    "flavor_match_policies": [
+//                        {
+//                            "flavor_part": "PLATFORM",
+//                            "match_policy": {
+//                                "match_type": "ANY_OF",
+//                                "required": "REQUIRED"
+//                            }
+//                        }
+//                    ]
+//                }
+//            ]
+//        },
+//        "signing_certificate": "-----BEGIN CERTIFICATE-----\nMIIEoDCCAwigAwIBAgIBAjANBgkqhkiG9w0BAQwFADBQMQswCQYDVQQGEwJVUzEL...\n-----END CERTIFICATE-----\n",
+//        "signature": "Q2VydGlmaWNhdGUgc2lnbmF0dXJlIG9mIHRoZSBmbGF2b3IgYnVuZGxlIGNvbnRlbnQ..."
+//    }
+// ---
+
+// swagger:operation POST /flavors/import Flavors Import-Flavors
+// ---
+//
+// description: |
+//   Imports a flavor bundle exported by another HVS. The signing certificate of the bundle must be issued by one of
+//   the trusted flavor CAs of the HVS, installed in /etc/hvs/certs/trustedca/flavor or added with the
+//   /ca-certificates API with the flavor type. The bundle must be signed with the key of that certificate. Each
+//   flavor must be signed with that key or with the key of another certificate of the bundle issued by a trusted
+//   flavor CA, such as the flavors the exporting HVS imported itself. The imported flavors keep their original
+//   signatures, the certificates of the bundle issued by a trusted flavor CA and their intermediate CAs are kept
+//   to verify them when the hosts are verified. The other certificates of the bundle are ignored.
+//
+//   Flavorgroups are matched by name, the existing flavorgroups are reused and the imported flavors and flavor
+//   templates are linked to them. Flavor templates already known to the HVS are reused, the deleted ones are
+//   skipped. A flavor of the bundle conflicts with an existing flavor having the same ID or label but a different
+//   signature, the conflict query parameter tells how the conflicts are resolved:
+//
+//    | Conflict | Behavior |
+//    |----------|----------|
+//    | fail     | The import is rejected with a 409 status and the import report. This is the default. |
+//    | skip     | The existing flavor is kept and linked to the imported flavorgroups in place of the flavor of the bundle. |
+//    | replace  | The existing flavor is deleted and the flavor of the bundle is linked to its flavorgroups, in a single transaction. The new flavors are created before any existing flavor is replaced. |
+//
+//   A dry run verifies the bundle and reports what the import would do without changing anything.
+//
+// x-permissions: flavors:import
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: dryRun
+//   description: When true, the import is only reported.
+//   in: query
+//   type: boolean
+//   required: false
+// - name: conflict
+//   description: How the conflicts with existing flavors are resolved, one of fail, skip or replace.
+//   in: query
+//   type: string
+//   required: false
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorBundle"
+// - name: Content-Type
+//   required: true
+//   in: header
+//   type: string
+// - name: Accept
+//   required: true
+//   in: header
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully imported the flavor bundle, or verified it on a dry run.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/FlavorImportReport"
+//   '400':
+//     description: Invalid query parameters or request body provided, or the flavor bundle could not be verified
+//   '409':
+//     description: Flavors of the bundle conflict with existing flavors, the import report lists them
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/FlavorImportReport"
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error or no trusted flavor CA configured
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/import?conflict=skip
+// x-sample-call-input: |
+//    {
+//        "content": {
+//            "exported_at": "2021-06-01T10:00:00Z",
+//            "signed_flavors": [ ... ],
+//            "flavorgroups": [ ... ]
+//        },
+//        "signing_certificate": "-----BEGIN CERTIFICATE-----\nMIIEoDCCAwigAwIBAgIBAjANBgkqhkiG9w0BAQwFADBQMQswCQYDVQQGEwJVUzEL...\n-----END CERTIFICATE-----\n",
+//        "signature": "Q2VydGlmaWNhdGUgc2lnbmF0dXJlIG9mIHRoZSBmbGF2b3IgYnVuZGxlIGNvbnRlbnQ..."
+//    }
+// x-sample-call-output: |
+//    {
+//        "dry_run": false,
+//        "conflict_mode": "skip",
+//        "flavors": [
+//            {
+//                "id": "f66ac31d-124d-418e-8200-2abf414a9adf",
+//                "name": "INTEL_IntelCorporation_SE5C620.86B.00.01.0014.070920180847_TPM2.0_07-09-2018",
+//                "action": "skip",
+//                "existing_id": "3d1a8b04-5fd3-4a0b-8a6c-b3e4e2e6c1f0",
+//                "message": "A different flavor with the same ID or label is kept"
+//            }
+//        ],
+//        "flavorgroups": [
+//            {
+//                "id": "a9d9e8a7-6ca2-4d3f-8c9f-3c0b5f8c1a11",
+//                "name": "site-a",
+//                "action": "create"
+//            }
+//        ],
+//        "flavor_templates": []
+//    }
+// ---
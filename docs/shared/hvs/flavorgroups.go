@@ -34,6 +34,34 @@ type FlavorgroupFlavorLink struct {
 	Body hvs.FlavorgroupFlavorLink
 }
 
+// RefreshSchedule request and response payload for the flavorgroup refresh schedule
+// swagger:parameters RefreshSchedule
+type RefreshSchedule struct {
+	// in:body
+	Body hvs.RefreshSchedule
+}
+
+// FlavorgroupHostSelector request and response payload for the flavorgroup host selector
+// swagger:parameters FlavorgroupHostSelector
+type FlavorgroupHostSelector struct {
+	// in:body
+	Body hvs.FlavorgroupHostSelector
+}
+
+// FlavorgroupSimulationRequest request payload for the flavorgroup trust simulation
+// swagger:parameters FlavorgroupSimulationRequest
+type FlavorgroupSimulationRequest struct {
+	// in:body
+	Body hvs.FlavorgroupSimulationRequest
+}
+
+// FlavorgroupSimulationReport response payload for the flavorgroup trust simulation
+// swagger:parameters FlavorgroupSimulationReport
+type FlavorgroupSimulationReport struct {
+	// in:body
+	Body hvs.FlavorgroupSimulationReport
+}
+
 // FlavorgroupFlavorLinkCollection response payload for SearchFlavors
 // swagger:parameters FlavorgroupFlavorLinkCollection
 type FlavorgroupFlavorLinkCollection struct {
@@ -61,6 +89,7 @@ type FlavorgroupFlavorLinkCollection struct {
 //       - ASSET_TAG
 //       - HOST_UNIQUE
 //       - SOFTWARE
+//       - IMA
 //
 //   <b>Match Policy</b>: The policy which defines how the host is verified against the flavors in the flavor group for
 //   the specified flavor part.
@@ -122,6 +151,23 @@ type FlavorgroupFlavorLinkCollection struct {
 //   in: query
 //   type: boolean
 //   required: false
+// - name: limit
+//   description: Limits the number of flavor groups in a page of the response. When the page is full, the next link of the response retrieves the following page.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   required: false
+// - name: after
+//   description: Returns the flavor groups following the one with this ID, as set by the next link of the previous page.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: fields
+//   description: Comma separated list of the fields of the flavor groups to include in the response, all the fields are included by default.
+//   in: query
+//   type: string
+//   required: false
 // - name: Accept
 //   required: true
 //   in: header
@@ -199,6 +245,8 @@ type FlavorgroupFlavorLinkCollection struct {
 //    | name                           | Name of the flavorgroup to be created. |
 //    | flavor_match_policy_collection | Collection of flavor match policies. Each flavor match policy contains two <br> parts: <br><b>flavor_part</b>:The type or classification of the flavor.<br> <b>match_policy</b>:The policy which defines how the host is verified against the <br> flavors in the flavor group for the specified flavor part. |
 //    | flavorTemplateIds              | (Optional) Flavor template ids that the created flavorgroup will be associated with. If not provided, created flavorgroup will be associated with all the templates associated with the automatic flavor group. |
+//    | refresh_schedule               | (Optional) Report refresh schedule of the hosts linked to the flavorgroup. Refer to the refresh schedule API for its attributes. |
+//    | host_selector                  | (Optional) Label selector of the hosts the flavorgroup is automatically linked to, e.g. "env=prod,rack!=r3". Refer to the host selector API for its syntax. |
 //
 // x-permissions: flavorgroups:create
 // security:
@@ -633,3 +681,321 @@ type FlavorgroupFlavorLinkCollection struct {
 //  }
 //  ]
 //  }
+
+// swagger:operation PUT /flavorgroups/{flavorgroup_id}/refresh-schedule Flavorgroups Update-RefreshSchedule
+// ---
+//
+// description: |
+//   Sets the report refresh schedule of a flavorgroup. The hosts linked to the flavorgroup are re-attested once their
+//   report is older than the refresh interval, unless the host overrides the schedule. When a host is linked to several
+//   flavorgroups with a schedule, the shortest interval applies.
+//
+//    | Attribute        | Description|
+//    |------------------|------------|
+//    | interval_seconds | (Optional) Maximum age of a host report in seconds before the host is re-attested. Zero disables the scheduled refresh. |
+//    | jitter_seconds   | (Optional) Spreads the refresh of the hosts, each host is delayed by a stable offset between zero and the jitter. Cannot exceed the interval. |
+//    | refresh_on_event | (Optional) Fetch new host data from the hosts when the flavors that apply to them change, instead of verifying their last host manifest. |
+//
+// x-permissions: flavorgroups:create
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/RefreshSchedule"
+// - name: Content-Type
+//   required: true
+//   in: header
+//   type: string
+// - name: Accept
+//   required: true
+//   in: header
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully updated the refresh schedule of the flavorgroup.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/RefreshSchedule"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Flavorgroup record not found
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/e5574593-0f92-41f0-8f2d-93b97cea9c06/refresh-schedule
+// x-sample-call-input: |
+//    {
+//        "interval_seconds": 3600,
+//        "jitter_seconds": 300,
+//        "refresh_on_event": true
+//    }
+// x-sample-call-output: |
+//    {
+//        "interval_seconds": 3600,
+//        "jitter_seconds": 300,
+//        "refresh_on_event": true
+//    }
+// ---
+
+// swagger:operation DELETE /flavorgroups/{flavorgroup_id}/refresh-schedule Flavorgroups Delete-RefreshSchedule
+// ---
+//
+// description: |
+//   Removes the report refresh schedule of a flavorgroup. The reports of the linked hosts are then only refreshed when
+//   they expire.
+// x-permissions: flavorgroups:delete
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully removed the refresh schedule of the flavorgroup.
+//   '404':
+//     description: Flavorgroup record not found
+//   '500':
+//     description: Internal server error
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/e5574593-0f92-41f0-8f2d-93b97cea9c06/refresh-schedule
+// ---
+
+// swagger:operation PUT /flavorgroups/{flavorgroup_id}/host-selector Flavorgroups Update-HostSelector
+// ---
+//
+// description: |
+//   Sets the host selector of a flavorgroup. The flavorgroup is linked to the hosts whose labels match the selector,
+//   both the hosts already registered and the hosts created or relabeled later on, and the newly linked hosts are added
+//   to the flavor verification queue. Links are only ever added: a host stays linked to the flavorgroup when its labels
+//   stop matching the selector or when the selector is changed, the host flavorgroup link API removes it.
+//
+//   The selector is a comma separated list of requirements on the labels of the hosts, all of which must be met:
+//
+//    | Requirement       | Matches the hosts |
+//    |-------------------|-------------------|
+//    | key=value         | with the label set to the value, key==value is equivalent. |
+//    | key!=value        | without the label or with the label set to another value. |
+//    | key in (v1,v2)    | with the label set to one of the values. |
+//    | key notin (v1,v2) | without the label or with the label set to none of the values. |
+//    | key               | with the label. |
+//    | !key              | without the label. |
+//
+// x-permissions: flavorgroups:store
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorgroupHostSelector"
+// - name: Content-Type
+//   required: true
+//   in: header
+//   type: string
+// - name: Accept
+//   required: true
+//   in: header
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully updated the host selector of the flavorgroup.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/FlavorgroupHostSelector"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Flavorgroup record not found
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/e5574593-0f92-41f0-8f2d-93b97cea9c06/host-selector
+// x-sample-call-input: |
+//    {
+//        "host_selector": "env=prod,rack!=r3"
+//    }
+// x-sample-call-output: |
+//    {
+//        "host_selector": "env=prod,rack!=r3"
+//    }
+// ---
+
+// swagger:operation DELETE /flavorgroups/{flavorgroup_id}/host-selector Flavorgroups Delete-HostSelector
+// ---
+//
+// description: |
+//   Removes the host selector of a flavorgroup. The hosts linked to the flavorgroup stay linked to it.
+// x-permissions: flavorgroups:delete
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully removed the host selector of the flavorgroup.
+//   '404':
+//     description: Flavorgroup record not found
+//   '500':
+//     description: Internal server error
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/e5574593-0f92-41f0-8f2d-93b97cea9c06/host-selector
+// ---
+
+// swagger:operation POST /flavorgroups/{flavorgroup_id}/simulate Flavorgroups Simulate
+// ---
+//
+// description: |
+//   Predicts which hosts linked to a flavorgroup would change their trust status if candidate flavors were added to
+//   the flavorgroup or if its flavor match policies were replaced. Nothing is persisted: the flavorgroup, its flavors,
+//   the trust cache and the reports of the hosts are left untouched.
+//
+//   Each linked host is verified against the flavorgroup from the last host manifest stored for it, once as the
+//   flavorgroup is and once with the candidate changes. Only the flavorgroup is evaluated, the trust of the host
+//   against its other flavorgroups is not taken into account. The hosts that are not in CONNECTED state are listed
+//   with an error.
+//
+//   A flavorgroup linked to more than 100 hosts is only simulated by a job, when the request has the
+//   "Prefer: respond-async" header.
+//
+//   The request holds at least one of:
+//     - flavor_ids: the existing flavors to be added to the flavorgroup.
+//     - flavor_collection: the content of new flavors to be added to the flavorgroup, they are not signed and are
+//       verified without checking their signature. The candidate flavors are considered the latest flavors of their
+//       flavor part.
+//     - flavor_match_policies: the match policies replacing those of the flavorgroup.
+//
+// x-permissions: flavorgroups:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorgroupSimulationRequest"
+// - name: Content-Type
+//   required: true
+//   in: header
+//   type: string
+// - name: Accept
+//   required: true
+//   in: header
+//   type: string
+// - name: Prefer
+//   description: With respond-async, the request is processed as a job and the response only holds the job, see GET /jobs/{job_id}.
+//   in: header
+//   type: string
+//   required: false
+//   enum:
+//     - respond-async
+// responses:
+//   '200':
+//     description: Successfully predicted the trust of the hosts linked to the flavorgroup.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/FlavorgroupSimulationReport"
+//   '202':
+//     description: Successfully started the job predicting the trust of the hosts, its result is the FlavorgroupSimulationReport. The Location header links to the job.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Job"
+//   '400':
+//     description: Invalid request body provided, or the flavorgroup is linked to too many hosts to be simulated without a job
+//   '404':
+//     description: Flavorgroup record not found
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/e5574593-0f92-41f0-8f2d-93b97cea9c06/simulate
+// x-sample-call-input: |
+//    {
+//        "flavor_match_policies": [
+//            {
+//                "flavor_part": "PLATFORM",
+//                "match_policy": {
+//                    "match_type": "ANY_OF",
+//                    "required": "REQUIRED"
+//                }
+//            },
+//            {
+//                "flavor_part": "OS",
+//                "match_policy": {
+//                    "match_type": "ANY_OF",
+//                    "required": "REQUIRED"
+//                }
+//            }
+//        ]
+//    }
+// x-sample-call-output: |
+//    {
+//        "flavorgroup_id": "e5574593-0f92-41f0-8f2d-93b97cea9c06",
+//        "hosts": [
+//            {
+//                "host_id": "fda6105d-a340-42da-bc35-0555e7a5e360",
+//                "current_trusted": true,
+//                "predicted_trusted": false,
+//                "trust_changed": true,
+//                "untrusted_flavor_parts": [
+//                    "OS"
+//                ]
+//            },
+//            {
+//                "host_id": "2bd09e36-9a4b-4f7c-8a0c-6c2bb2b0a1f4",
+//                "trust_changed": false,
+//                "error": "No host manifest stored for the host, it is not in CONNECTED state"
+//            }
+//        ]
+//    }
+// ---
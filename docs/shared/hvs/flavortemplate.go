@@ -164,6 +164,7 @@ type FlavorTemplateFlavorgroupCollection struct {
 //    |--------------------------------|------------|
 //    | Meta                           | Provides the template-author the option to populate arbitrary key/value pairs that will be copied to flavor-part’s “meta/description” entity. |
 //    | PcrRules                       | Instructs the flavor creation engine to copy PCR bank values from the host-manifest to the resulting flavor-part. |
+//    | CustomRules                    | (Optional) Declarative rules that are copied to the resulting flavor-part and evaluated against the host-manifest during verification. |
 //
 //   PcrRules: An array of verification rules that will be applied to a PCR.
 //
@@ -174,6 +175,16 @@ type FlavorTemplateFlavorgroupCollection struct {
 //    | EventLogEquals                 | Event log equals contains “eventlog_equals” section will update the flavor-part to enforce “PCR Event Log Equals” rules during verification.  The optional “excluding_tags” element can be used to omit events with a one or more “tags” during verification. |
 //    | EventLogIncludes               | EventLogInclude contains “eventlog_includes” section will update the flavor-part to enforce “PCR Event Log Includes” rules during verification. |
 //
+//   CustomRules: An array of rules that will be verified as “Custom Rule Matches” rules. Each rule requires either a
+//   jsonquery statement or a CEL expression. Hosts that do not satisfy a rule are reported with a “CustomRuleMismatch” fault.
+//
+//    | Attribute                      | Description|
+//    |--------------------------------|------------|
+//    | Name                           | Name of the rule, it has to be unique within the flavor-part. |
+//    | Description                    | (Optional) Description of the rule. |
+//    | JsonQuery                      | A 'jsonquery' statement that has to select at least one node of the host-manifest. For example, “//host_info/hardware_features/TPM/meta/tpm_version[text()='2.0']”. |
+//    | Cel                            | A CEL expression that has to evaluate to true, the host-manifest is available as “host_manifest”. For example, “host_manifest.host_info.hardware_features.UEFI.meta.secure_boot_enabled”. |
+//
 //   Creates a Flavor template and stores it in the database.
 //
 // x-permissions: flavor-template:create
@@ -239,6 +250,12 @@ type FlavorTemplateFlavorgroupCollection struct {
 //                       "pcr_matches": true,
 //                       "eventlog_equals": {}
 //                   }
+//               ],
+//               "custom_rules": [
+//                   {
+//                       "name": "secure_boot_enabled",
+//                       "cel": "host_manifest.host_info.hardware_features.UEFI.meta.secure_boot_enabled"
+//                   }
 //               ]
 //           },
 //           "OS": {
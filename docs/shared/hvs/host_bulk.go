@@ -0,0 +1,194 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+
+// HostBulkCreateRequest request payload
+// swagger:parameters HostBulkCreateRequest
+type HostBulkCreateRequest struct {
+	// in:body
+	Body hvs.HostBulkCreateRequest
+}
+
+// HostBulkSummary result payload of the HOST_BULK_CREATE job
+// swagger:parameters HostBulkSummary
+type HostBulkSummary struct {
+	// in:body
+	Body hvs.HostBulkSummary
+}
+
+// ---
+//
+// swagger:operation POST /hosts/bulk Hosts CreateHostsBulk
+// ---
+//
+// description: |
+//   Registers many hosts at once. The registration runs asynchronously as a HOST_BULK_CREATE job, the response only
+//   holds the job whose progress is retrieved with GET /hosts/bulk/{job_id} or GET /jobs/{job_id}. The result of the
+//   job is a HostBulkSummary holding the per host results. Every host is registered as with POST /hosts, the hosts
+//   that fail to register do not stop the job. All the hosts are validated before the registration starts and only
+//   the flavorgroups of the valid hosts are created. A request holds at most 10000 hosts and 16 MiB.
+//
+//   The hosts are provided either as a JSON HostBulkCreateRequest or as CSV. The first CSV record is a header naming
+//   the columns, host_name and connection_string are required while description and flavorgroup_names are optional.
+//   Flavorgroup names are separated by semicolons.
+//
+//   | onConflict | Hosts that are already registered |
+//   |------------|-----------------------------------|
+//   | fail       | Reported as failed |
+//   | skip       | Left untouched and reported as skipped |
+//   | update     | Description, connection string and flavorgroups are updated, requires the hosts:store permission |
+//
+//   Returns - The serialized Job Go struct object of the started job, the Location header links to the job.
+//
+// x-permissions: hosts:create
+// security:
+//  - bearerAuth: []
+// consumes:
+//  - application/json
+//  - text/csv
+// produces:
+//  - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/HostBulkCreateRequest"
+// - name: onConflict
+//   description: Handling of the hosts that are already registered.
+//   in: query
+//   type: string
+//   enum:
+//     - fail
+//     - skip
+//     - update
+//   default: fail
+//   required: false
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+//     - text/csv
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '202':
+//     description: Successfully started the bulk host registration job.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/Job"
+//   '400':
+//     description: Invalid request body or query parameter
+//   '401':
+//     description: Insufficient privileges to update existing hosts
+//   '413':
+//     description: Request body too large
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/bulk?onConflict=skip
+// x-sample-call-input: |
+//   host_name,connection_string,description,flavorgroup_names
+//   rack12-node1,https://rack12-node1.example.com:1443,Rack 12 node 1,automatic;rack12
+//   rack12-node2,https://rack12-node2.example.com:1443,Rack 12 node 2,automatic;rack12
+// x-sample-call-output: |
+//   {
+//       "id": "b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31",
+//       "type": "HOST_BULK_CREATE",
+//       "status": "Pending",
+//       "processed": 0,
+//       "total": 0,
+//       "created": "2021-03-02T10:55:41.147322Z",
+//       "updated": "2021-03-02T10:55:41.147322Z"
+//   }
+// ---
+
+// ---
+//
+// swagger:operation GET /hosts/bulk/{job_id} Hosts RetrieveHostsBulkJob
+// ---
+//
+// description: |
+//   Retrieves a bulk host registration job, its result is the HostBulkSummary holding the per host results. While the
+//   job is running, the result holds the hosts processed so far.
+//   Returns - The serialized Job Go struct object that was retrieved.
+//
+// x-permissions: hosts:create
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: job_id
+//   description: Unique ID of the bulk host registration job.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the bulk host registration job.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/Job"
+//   '404':
+//     description: Job does not exist
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/bulk/b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31
+// x-sample-call-output: |
+//   {
+//       "id": "b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31",
+//       "type": "HOST_BULK_CREATE",
+//       "status": "Completed",
+//       "processed": 2,
+//       "total": 2,
+//       "result": {
+//           "on_conflict": "skip",
+//           "total": 2,
+//           "processed": 2,
+//           "created": 1,
+//           "updated": 0,
+//           "skipped": 1,
+//           "failed": 0,
+//           "results": [
+//               {
+//                   "host_name": "rack12-node1",
+//                   "status": "Created",
+//                   "host_id": "47a3b602-f321-4e03-b3b2-8f3ca3cde128"
+//               },
+//               {
+//                   "host_name": "rack12-node2",
+//                   "status": "Skipped",
+//                   "host_id": "0f3a7e55-8a4e-4f4e-a9d0-3b2a1c5e6f7d"
+//               }
+//           ]
+//       },
+//       "created": "2021-03-02T10:55:41.147322Z",
+//       "updated": "2021-03-02T10:55:49.563118Z"
+//   }
+// ---
@@ -0,0 +1,219 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+
+// HostFetchQueueEntry response payload
+// swagger:parameters HostFetchQueueEntry
+type HostFetchQueueEntry struct {
+	// in:body
+	Body hvs.HostFetchQueueEntry
+}
+
+// HostFetchQueueEntryCollection response payload
+// swagger:parameters HostFetchQueueEntryCollection
+type HostFetchQueueEntryCollection struct {
+	// in:body
+	Body hvs.HostFetchQueueEntryCollection
+}
+
+// ---
+//
+// swagger:operation GET /host-fetch-queue HostFetchQueue SearchHostFetchQueue
+// ---
+//
+// description: |
+//   Searches the host data fetch queue. The queue holds the hosts whose data is to be fetched for a flavor verification,
+//   along with the retry state of the hosts that could not be reached.
+//
+//   | State       | Description |
+//   |-------------|-------------|
+//   | Pending     | Host data is to be fetched from the host |
+//   | Retrying    | The last fetch failed, the next attempt is delayed with an exponential backoff |
+//   | CircuitOpen | The host failed too many consecutive fetches and is not contacted again before the cool down elapses |
+//
+//   Returns - The serialized HostFetchQueueEntryCollection Go struct object that was retrieved.
+//
+// x-permissions: host_fetch_queue:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: hostId
+//   description: Host UUID
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: state
+//   description: Fetch state of the host.
+//   in: query
+//   type: string
+//   enum:
+//     - Pending
+//     - Retrying
+//     - CircuitOpen
+//   required: false
+// - name: limit
+//   description: Limits the number of entries in the response.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   default: 10000
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully searched the host fetch queue. Also returned when no results are found.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/HostFetchQueueEntryCollection"
+//   '400':
+//     description: Invalid values for search criteria
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/host-fetch-queue?state=CircuitOpen
+// x-sample-call-output: |
+//   {
+//       "host_fetch_queue": [
+//           {
+//               "host_id": "47a3b602-f321-4e03-b3b2-8f3ca3cde128",
+//               "state": "CircuitOpen",
+//               "prefer_hash_match": true,
+//               "attempts": 5,
+//               "next_attempt": "2021-03-02T11:34:12.318764Z",
+//               "last_error": "could not connect to host",
+//               "created": "2021-03-02T10:55:41.147322Z",
+//               "updated": "2021-03-02T11:04:12.318764Z"
+//           }
+//       ]
+//   }
+// ---
+
+// ---
+//
+// swagger:operation GET /host-fetch-queue/{host_id} HostFetchQueue RetrieveHostFetchQueueEntry
+// ---
+//
+// description: |
+//   Retrieves the host data fetch queue entry of a host.
+//   Returns - The serialized HostFetchQueueEntry Go struct object that was retrieved.
+//
+// x-permissions: host_fetch_queue:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the host fetch queue entry.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/HostFetchQueueEntry"
+//   '400':
+//     description: Invalid host ID
+//   '404':
+//     description: Host is not in the fetch queue
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/host-fetch-queue/47a3b602-f321-4e03-b3b2-8f3ca3cde128
+// x-sample-call-output: |
+//   {
+//       "host_id": "47a3b602-f321-4e03-b3b2-8f3ca3cde128",
+//       "state": "Retrying",
+//       "prefer_hash_match": true,
+//       "attempts": 2,
+//       "next_attempt": "2021-03-02T10:57:03.592717Z",
+//       "last_error": "could not connect to host",
+//       "created": "2021-03-02T10:55:41.147322Z",
+//       "updated": "2021-03-02T10:56:11.592717Z"
+//   }
+// ---
+
+// ---
+//
+// swagger:operation POST /host-fetch-queue/{host_id}/reset HostFetchQueue ResetHostFetchQueueEntry
+// ---
+//
+// description: |
+//   Clears the backoff of a host and closes its circuit breaker, so that its pending data fetch is attempted again
+//   right away.
+//   Returns - The serialized HostFetchQueueEntry Go struct object after the reset.
+//
+// x-permissions: host_fetch_queue:reset
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully reset the host fetch queue entry.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/HostFetchQueueEntry"
+//   '400':
+//     description: Invalid host ID
+//   '404':
+//     description: Host is not in the fetch queue
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/host-fetch-queue/47a3b602-f321-4e03-b3b2-8f3ca3cde128/reset
+// x-sample-call-output: |
+//   {
+//       "host_id": "47a3b602-f321-4e03-b3b2-8f3ca3cde128",
+//       "state": "Retrying",
+//       "prefer_hash_match": true,
+//       "attempts": 0,
+//       "next_attempt": "2021-03-02T11:10:27.004512Z",
+//       "last_error": "could not connect to host",
+//       "created": "2021-03-02T10:55:41.147322Z",
+//       "updated": "2021-03-02T11:10:27.004512Z"
+//   }
+// ---
@@ -48,6 +48,20 @@ type HostFlavorgroupCreateRequest struct {
 	Body hvs.HostFlavorgroupCreateRequest
 }
 
+// HostNonce response payload
+// swagger:parameters HostNonce
+type HostNonce struct {
+	// in:body
+	Body hvs.HostNonce
+}
+
+// HostEvidence request payload
+// swagger:parameters HostEvidence
+type HostEvidence struct {
+	// in:body
+	Body hvs.HostEvidence
+}
+
 // ---
 
 // swagger:operation POST /hosts Hosts CreateHost
@@ -77,6 +91,8 @@ type HostFlavorgroupCreateRequest struct {
 //    | connection_string | The host connection string. |
 //    | flavorgroup_names | List of flavor group names that the created host will be associated. |
 //    | description       | Host description. |
+//    | refresh_schedule  | (Optional) Report refresh schedule of the host, overrides the schedules of its flavor groups. An empty schedule removes the override on update, a schedule with disabled set to true turns off the refresh of the host. |
+//    | labels            | (Optional) Labels of the host as key/value pairs, such as {"rack": "r12", "env": "prod"}. The host is associated with the flavor groups whose host selector matches its labels. Empty labels remove the labels on update. |
 //
 // x-permissions: hosts:create
 // security:
@@ -125,7 +141,11 @@ type HostFlavorgroupCreateRequest struct {
 //        "host_name": "Purley host1",
 //        "connection_string": "intel:https://trustagent.server.com:1443",
 //        "flavorgroup_names": [""],
-//        "description": "RHEL TPM2.0 Purley"
+//        "description": "RHEL TPM2.0 Purley",
+//        "labels": {
+//            "rack": "r12",
+//            "env": "prod"
+//        }
 //    }
 // x-sample-call-output: |
 //    {
@@ -136,7 +156,11 @@ type HostFlavorgroupCreateRequest struct {
 //        "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e",
 //        "flavorgroup_names": [
 //            "automatic", "platform_software"
-//        ]
+//        ],
+//        "labels": {
+//            "rack": "r12",
+//            "env": "prod"
+//        }
 //    }
 
 // ---
@@ -218,6 +242,8 @@ type HostFlavorgroupCreateRequest struct {
 //    | connection_string | The host connection string. |
 //    | flavorgroup_names | List of flavor group names that the created host will be associated. |
 //    | description       | Host description. |
+//    | refresh_schedule  | (Optional) Report refresh schedule of the host, overrides the schedules of its flavor groups. An empty schedule removes the override on update, a schedule with disabled set to true turns off the refresh of the host. |
+//    | labels            | (Optional) Labels of the host as key/value pairs, such as {"rack": "r12", "env": "prod"}. The host is associated with the flavor groups whose host selector matches its labels. Empty labels remove the labels on update. |
 //
 //
 //
@@ -332,6 +358,7 @@ type HostFlavorgroupCreateRequest struct {
 //   <b>Searches for hosts.</b>
 //   <pre>
 //   Only one identifying parameter can be specified. The parameters listed here are in the order of priority that will be evaluated.</br>
+//   The labelSelector parameter can be combined with any of them.</br>
 //   </pre>
 //
 //   Returns - The serialized HostCollection Go struct object that was retrieved, which is a collection of serialized Host Go struct objects.
@@ -379,6 +406,11 @@ type HostFlavorgroupCreateRequest struct {
 //   in: query
 //   type: boolean
 //   required: false
+// - name: labelSelector
+//   description: Comma separated list of requirements on the labels of the hosts, all of which must be met, e.g. "env=prod,rack!=r3". The supported requirements are key=value, key!=value, key in (v1,v2), key notin (v1,v2), key and !key.
+//   in: query
+//   type: string
+//   required: false
 // - name: getTrustStatus
 //   description: Get trust status for host.
 //   in: query
@@ -397,6 +429,23 @@ type HostFlavorgroupCreateRequest struct {
 //      - asc
 //      - desc
 //   required: false
+// - name: limit
+//   description: Limits the number of hosts in a page of the response. When the page is full, the next link of the response retrieves the following page.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   required: false
+// - name: after
+//   description: Returns the hosts following the one with this ID, as set by the next link of the previous page.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: fields
+//   description: Comma separated list of the fields of the hosts to include in the response, all the fields are included by default.
+//   in: query
+//   type: string
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -649,3 +698,134 @@ type HostFlavorgroupCreateRequest struct {
 //            }
 //        ]
 //    }
+
+// ---
+
+// swagger:operation GET /hosts/{host_id}/nonce HostEvidence RetrieveHostNonce
+// ---
+//
+// description: |
+//   Issues a nonce to a host for push mode attestation. The host binds its next TPM quote to the nonce and submits it
+//   with POST /hosts/{host_id}/evidence before the nonce expires. Only the last nonce issued to a host is valid and it
+//   can be used once.
+//   Returns - The serialized HostNonce Go struct object.
+// x-permissions: host_nonces:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully issued the nonce.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/HostNonce"
+//   '404':
+//     description: Host record not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/nonce
+// x-sample-call-output: |
+//    {
+//        "host_id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//        "nonce": "tHgfRQED1+pYgEZpq3dZC9ONmBCZKdx10LErTZs1k/k=",
+//        "expires_at": "2021-03-15T10:25:13.417368-07:00"
+//    }
+
+// ---
+
+// swagger:operation POST /hosts/{host_id}/evidence HostEvidence SubmitHostEvidence
+// ---
+//
+// description: |
+//   Submits the attestation evidence of a host in push mode, so HVS does not need to connect to the trust agent.
+//   The TPM quote must be bound to the last nonce issued by GET /hosts/{host_id}/nonce. The quote is verified with
+//   the AIK certificate and the resulting host manifest is added to the flavor verification queue in backend.
+//   The hardware UUID of the host info is required and must match the hardware UUID of the registered host. The AIK
+//   certificate must be the AIK of the last report of the host, or be issued by the HVS Privacy CA when the host has
+//   not been attested yet.
+//
+//   The serialized HostEvidence Go struct object represents the content of the request body.
+//
+//    | Attribute               | Description |
+//    |-------------------------|-------------|
+//    | nonce                   | The nonce issued to the host. |
+//    | host_info               | The platform information of the host as reported by the trust agent. |
+//    | aik                     | Base64 encoded PEM AIK certificate. |
+//    | quote                   | Base64 encoded TPM quote. |
+//    | event_log               | The measured boot event log, either the trust agent JSON format or a base64 encoded binary TCG event log. |
+//    | tcb_measurements        | The application integrity measurement XMLs. |
+//    | selected_pcr_banks      | The PCR banks of the quote. |
+//    | is_tag_provisioned      | Whether the asset tag was included in the quote. |
+//    | asset_tag               | Base64 encoded asset tag digest. |
+//    | ima_log                 | The IMA ascii runtime measurement list. |
+//    | binding_key_certificate | Base64 encoded DER binding key certificate. |
+//
+// x-permissions: host_evidence:create
+// security:
+//  - bearerAuth: []
+// consumes:
+// - application/json
+// parameters:
+// - name: host_id
+//   description: Unique ID of the host.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/HostEvidence"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '202':
+//     description: Successfully accepted the evidence for flavor verification.
+//   '400':
+//     description: Invalid request body, nonce or TPM quote
+//   '404':
+//     description: Host record not found
+//   '415':
+//     description: Invalid Content-Type Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/hosts/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/evidence
+// x-sample-call-input: |
+//    {
+//        "nonce": "tHgfRQED1+pYgEZpq3dZC9ONmBCZKdx10LErTZs1k/k=",
+//        "host_info": {
+//            "os_name": "RedHatEnterprise",
+//            "hardware_uuid": "80ecce40-04b8-e811-906e-00163566263e",
+//            ...
+//        },
+//        "aik": "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t...",
+//        "quote": "AIv/VENHgBgAIgALqp0X...",
+//        "event_log": "W3sicGNyIjp7ImluZGV4IjowLCJiYW5rIjoiU0hBMjU2In0s...",
+//        "is_tag_provisioned": false
+//    }
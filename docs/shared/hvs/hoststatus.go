@@ -79,6 +79,11 @@ type HostStatusCollection struct {
 //        - tpm_not_present
 //        - unsupported_tpm
 //      required: false
+//    - name: labelSelector
+//      description: Comma separated list of requirements on the labels of the hosts, all of which must be met, e.g. "env=prod,rack!=r3".
+//      in: query
+//      type: string
+//      required: false
 //    - name: fromDate
 //      description: |
 //        Filters HostStatus records created after this date.
@@ -120,6 +125,17 @@ type HostStatusCollection struct {
 //      minimum: 1
 //      default: 10000
 //      required: false
+//    - name: after
+//      description: Returns the HostStatus records following the one with this ID, as set by the next link of the previous page. Only supported when searching the latest status of each host without a date filter.
+//      in: query
+//      type: string
+//      format: uuid
+//      required: false
+//    - name: fields
+//      description: Comma separated list of the fields of the HostStatus records to include in the response, all the fields are included by default.
+//      in: query
+//      type: string
+//      required: false
 //    - name: Accept
 //      description: Accept header
 //      in: header
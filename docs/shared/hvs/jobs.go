@@ -0,0 +1,233 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+
+// Job response payload
+// swagger:parameters Job
+type Job struct {
+	// in:body
+	Body hvs.Job
+}
+
+// JobCollection response payload
+// swagger:parameters JobCollection
+type JobCollection struct {
+	// in:body
+	Body hvs.JobCollection
+}
+
+// ---
+//
+// swagger:operation GET /jobs Jobs SearchJobs
+// ---
+//
+// description: |
+//   Searches the jobs, most recent first. Jobs process the long running operations in the background, they are
+//   started by POST /hosts/bulk and by POST /flavors, POST /reports, POST /rpc/deploy-tag-certificate and
+//   POST /flavorgroups/{flavorgroup_id}/simulate when the request has the "Prefer: respond-async" header.
+//
+//   | Type                   | Operation | Result |
+//   |------------------------|-----------|--------|
+//   | HOST_BULK_CREATE       | POST /hosts/bulk | HostBulkSummary |
+//   | FLAVOR_CREATE          | POST /flavors | SignedFlavorCollection |
+//   | REPORT_CREATE          | POST /reports | Report |
+//   | HOST_VERIFY            | POST /reports?process=async | List of HostVerifyResult, one per verified host |
+//   | TAG_CERTIFICATE_DEPLOY | POST /rpc/deploy-tag-certificate | SignedFlavor |
+//   | FLAVORGROUP_SIMULATE   | POST /flavorgroups/{flavorgroup_id}/simulate | FlavorgroupSimulationReport |
+//
+//   | Status    | Description |
+//   |-----------|-------------|
+//   | Pending   | Job is about to start |
+//   | Running   | Job is in progress, processed and total report its progress and result holds the partial result, if any |
+//   | Completed | Job succeeded, result holds its outcome |
+//   | Failed    | Job failed or was interrupted by a restart of the service instance running it, error holds the reason |
+//   | Cancelled | Job was cancelled |
+//
+//   Returns - The serialized JobCollection Go struct object that was retrieved.
+//
+// x-permissions: jobs:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: type
+//   description: Type of the jobs.
+//   in: query
+//   type: string
+//   enum:
+//     - HOST_BULK_CREATE
+//     - FLAVOR_CREATE
+//     - REPORT_CREATE
+//     - HOST_VERIFY
+//     - TAG_CERTIFICATE_DEPLOY
+//     - FLAVORGROUP_SIMULATE
+//   required: false
+// - name: status
+//   description: Comma separated statuses of the jobs.
+//   in: query
+//   type: string
+//   required: false
+// - name: limit
+//   description: Limits the number of jobs in the response.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   default: 10000
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully searched the jobs. Also returned when no results are found.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/JobCollection"
+//   '400':
+//     description: Invalid values for search criteria
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/jobs?status=Running,Pending
+// x-sample-call-output: |
+//   {
+//       "jobs": [
+//           {
+//               "id": "b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31",
+//               "type": "FLAVOR_CREATE",
+//               "status": "Running",
+//               "processed": 0,
+//               "total": 0,
+//               "created": "2021-03-02T10:55:41.147322Z",
+//               "updated": "2021-03-02T10:55:41.153894Z"
+//           }
+//       ]
+//   }
+// ---
+
+// ---
+//
+// swagger:operation GET /jobs/{job_id} Jobs RetrieveJob
+// ---
+//
+// description: |
+//   Retrieves the progress and the outcome of a job.
+//   Returns - The serialized Job Go struct object that was retrieved.
+//
+// x-permissions: jobs:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: job_id
+//   description: Unique ID of the job.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the job.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/Job"
+//   '400':
+//     description: Invalid job ID
+//   '404':
+//     description: Job does not exist
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/jobs/b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31
+// x-sample-call-output: |
+//   {
+//       "id": "b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31",
+//       "type": "REPORT_CREATE",
+//       "status": "Failed",
+//       "processed": 0,
+//       "total": 0,
+//       "error": "Host is not in CONNECTED state",
+//       "created": "2021-03-02T10:55:41.147322Z",
+//       "updated": "2021-03-02T10:55:49.563118Z"
+//   }
+// ---
+
+// ---
+//
+// swagger:operation POST /jobs/{job_id}/cancel Jobs CancelJob
+// ---
+//
+// description: |
+//   Cancels a job that is still pending or running. The job is stopped, the changes it already made and its partial
+//   result, if any, are kept.
+//   Returns - The serialized Job Go struct object after the cancellation.
+//
+// x-permissions: jobs:cancel
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: job_id
+//   description: Unique ID of the job.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully cancelled the job.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/Job"
+//   '400':
+//     description: Invalid job ID
+//   '404':
+//     description: Job does not exist
+//   '409':
+//     description: Job has already finished
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/jobs/b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31/cancel
+// x-sample-call-output: |
+//   {
+//       "id": "b5e3e8a1-57f6-4d3e-9b1a-5c1b5a0f2c31",
+//       "type": "HOST_BULK_CREATE",
+//       "status": "Cancelled",
+//       "processed": 120,
+//       "total": 500,
+//       "created": "2021-03-02T10:55:41.147322Z",
+//       "updated": "2021-03-02T10:57:02.410031Z"
+//   }
+// ---
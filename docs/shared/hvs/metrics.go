@@ -0,0 +1,41 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+//
+// swagger:operation GET /metrics Metrics GetMetrics
+// ---
+// description: |
+//   GetMetrics exposes the metrics of the service in the Prometheus text exposition format.
+//   In addition to the Go runtime and process metrics, the request latency and status codes of every route are reported
+//   by isecl_http_request_duration_seconds and isecl_http_requests_total.
+//
+//   | Metric                                  | Description |
+//   |-----------------------------------------|-------------|
+//   | isecl_hvs_queue_depth                   | Items waiting in the hosttrust and host-fetcher work queues, by queue |
+//   | isecl_hvs_host_fetch_duration_seconds   | Time taken to retrieve the host manifest from a host, by result |
+//   | isecl_hvs_host_verify_duration_seconds  | Time taken to verify a host manifest against its flavors |
+//   | isecl_hvs_hosts                         | Number of hosts by the trust status of their latest report |
+//
+//   Returns - The metrics of the service.
+//
+// x-permissions: metrics:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//   - text/plain
+// responses:
+//   '200':
+//     description: Successfully retrieved the metrics.
+//     content: text/plain
+//   '401':
+//     description: The caller does not hold the metrics:retrieve permission.
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/metrics
+// x-sample-call-output: |
+//   # HELP isecl_http_requests_total Number of http requests served, by route, method and status code
+//   # TYPE isecl_http_requests_total counter
+//   isecl_http_requests_total{code="200",method="GET",route="/hvs/v2/hosts",service="HVS"} 42
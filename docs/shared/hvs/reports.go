@@ -81,6 +81,11 @@ type ReportCreateRequest struct {
 //   type: string
 //   format: string
 //   required: false
+// - name: labelSelector
+//   description: Comma separated list of requirements on the labels of the hosts, all of which must be met, e.g. "env=prod,rack!=r3".
+//   in: query
+//   type: string
+//   required: false
 // - name: numberOfDays
 //   description: |
 //      Results returned will be restricted to between the current date and number of days prior. This option will override other date options.
@@ -123,6 +128,17 @@ type ReportCreateRequest struct {
 //   type: integer
 //   required: false
 //   default: 2000
+// - name: after
+//   description: Returns the reports following the one with this ID, as set by the next link of the previous page. Only supported when searching the latest report of each host without a date filter.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: fields
+//   description: Comma separated list of the fields of the reports to include in the response, all the fields are included by default.
+//   in: query
+//   type: string
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -252,6 +268,13 @@ type ReportCreateRequest struct {
 //   in: query
 //   enum:
 //     - async
+// - name: Prefer
+//   description: With respond-async, the request is processed as a job and the response only holds the job, see GET /jobs/{job_id}. Along with process=async, the job verifies the host instead of queuing it and holds the outcome of the verification.
+//   in: header
+//   type: string
+//   required: false
+//   enum:
+//     - respond-async
 // responses:
 //   '201':
 //     description: Successfully created the report.
@@ -259,6 +282,12 @@ type ReportCreateRequest struct {
 //       application/json
 //     schema:
 //       $ref: "#/definitions/Report"
+//   '202':
+//     description: Successfully started the job creating the report, its result is the Report, or, with process=async, the job verifying the host. The Location header links to the job.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Job"
 //   '400':
 //     description: Invalid search criteria provided
 //   '415':
@@ -426,3 +455,112 @@ type ReportCreateRequest struct {
 //       "expiration": "2018-07-23T17:39:52-0700"
 //     }
 //   }
+
+// swagger:operation GET /reports/{report_id}/diff Reports Diff-Report
+// ---
+//
+// description: |
+//   Compares a report with an earlier report of the same host. The report to compare with is either given by its ID
+//   or, with the value 'latest-trusted', the most recent trusted report of the host created before the report.
+//   Reports that have been replaced by a newer report of the host are looked up in the audit log.
+//   Returns - The rule results per flavor part that were added, removed or changed their trust status or faults, the
+//   PCR values that differ and the event log entries that were added or removed.
+// x-permissions: reports:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: report_id
+//   description: Unique ID of the Report.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: against
+//   description: Unique ID of the Report to compare with or 'latest-trusted'.
+//   in: query
+//   required: true
+//   type: string
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully compared the Reports.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/TrustReportDiff"
+//   '400':
+//     description: Invalid against parameter or Reports of different hosts.
+//   '404':
+//     description: No relevant report record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/reports/8a545a4f-d282-4d91-8ec5-bcbe439dcfbc/diff?against=latest-trusted
+// x-sample-call-output: |
+//   {
+//     "report_id": "8a545a4f-d282-4d91-8ec5-bcbe439dcfbc",
+//     "against_report_id": "3bcd1a0c-0cf3-43d4-b6d0-6b5c0d9ecc45",
+//     "host_id": "94824cb6-d6c8-4faf-83b0-125996ceebe2",
+//     "created": "2021-03-04T16:39:52-08:00",
+//     "against_created": "2021-03-03T16:39:52-08:00",
+//     "trusted": false,
+//     "against_trusted": true,
+//     "flavor_parts": [
+//       {
+//         "flavor_part": "PLATFORM",
+//         "trusted": false,
+//         "against_trusted": true,
+//         "rule_results": [
+//           {
+//             "rule_name": "rule.PcrMatchesConstant",
+//             "change": "changed",
+//             "result": {
+//               ...
+//             },
+//             "against_result": {
+//               ...
+//             }
+//           }
+//         ]
+//       }
+//     ],
+//     "pcr_values": [
+//       {
+//         "pcr_index": "pcr_0",
+//         "pcr_bank": "SHA256",
+//         "value": "5b3e1a5a7e2bb7c4cd8cbbf0b0fb8ee4e2b33f7a58d1b9c1c6fcff0e95a4f9a6",
+//         "against_value": "b6a2ffe9d5e4a8ef1a4ed8a1fdc8c8c7e8f3a2d19f5a2bd37e4e4c8f1d2c6a3b"
+//       }
+//     ],
+//     "event_logs": [
+//       {
+//         "pcr": {
+//           "index": 0,
+//           "bank": "SHA256"
+//         },
+//         "added_events": [
+//           {
+//             "type_id": "0x80000008",
+//             "type_name": "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+//             "measurement": "1f4b0a1d2b0b1e3c9f2a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e"
+//           }
+//         ],
+//         "removed_events": [
+//           {
+//             "type_id": "0x80000008",
+//             "type_name": "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+//             "measurement": "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d"
+//           }
+//         ]
+//       }
+//     ]
+//   }
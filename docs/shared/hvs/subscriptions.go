@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+
+// Subscription request/response payload
+// swagger:parameters Subscription
+type Subscription struct {
+	// in:body
+	Body hvs.Subscription
+}
+
+// SubscriptionCollection response payload
+// swagger:parameters SubscriptionCollection
+type SubscriptionCollection struct {
+	// in:body
+	Body hvs.SubscriptionCollection
+}
+
+// HostEvent payload delivered to the subscribers
+// swagger:parameters HostEvent
+type HostEvent struct {
+	// in:body
+	Body hvs.HostEvent
+}
+
+// ---
+//
+// swagger:operation POST /subscriptions Subscriptions CreateSubscription
+// ---
+//
+// description: |
+//   Registers a subscription to host events. The events are posted to an HTTPS webhook or published on a NATS
+//   subject, as a serialized HostEvent Go struct object. The NATS subject must be hvs.events or a subject under
+//   it, such as hvs.events.siem, and cannot hold wildcards.
+//
+//   | Event type         | Description |
+//   |--------------------|-------------|
+//   | trust_changed      | The trust status of the host changed, or the host got its first report |
+//   | connection_failure | The host data could not be fetched from the host |
+//   | new_report         | A new trust report was created for the host |
+//
+//   The filter restricts the delivered events to the given hosts, flavor groups and event types. An empty list
+//   matches everything.
+//
+//   Each delivery carries the headers X-Hvs-Event-Id, X-Hvs-Event-Type and X-Hvs-Signature. The signature has the
+//   form "t=<unix time>,v1=<hex>", where the hex value is the HMAC-SHA256 of "<unix time>.<body>" keyed with the
+//   secret of the subscription. A failed delivery is retried with an exponential backoff.
+//
+//   Returns - The serialized Subscription Go struct object that was created. The secret is only returned in this
+//   response.
+//
+// x-permissions: subscriptions:create
+// security:
+//  - bearerAuth: []
+// consumes:
+//  - application/json
+// produces:
+//  - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/Subscription"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '201':
+//     description: Successfully created the subscription.
+//     schema:
+//       "$ref": "#/definitions/Subscription"
+//   '400':
+//     description: Invalid request body provided
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions
+// x-sample-call-input: |
+//    {
+//        "delivery_type": "webhook",
+//        "endpoint": "https://siem.example.com/hvs/events",
+//        "filter": {
+//            "flavorgroup_ids": ["ee37c360-7eae-4250-a677-6ee12adce8e2"],
+//            "event_types": ["trust_changed", "connection_failure"]
+//        }
+//    }
+// x-sample-call-output: |
+//    {
+//        "id": "2a3f5c1e-9c7b-4d3e-8f0a-6b1d2c3e4f5a",
+//        "delivery_type": "webhook",
+//        "endpoint": "https://siem.example.com/hvs/events",
+//        "filter": {
+//            "flavorgroup_ids": ["ee37c360-7eae-4250-a677-6ee12adce8e2"],
+//            "event_types": ["trust_changed", "connection_failure"]
+//        },
+//        "secret": "5mT8Gm3vR2Nw7yZ1qK0xL4pH9cD6fJ8sA2bE7uI3oW0=",
+//        "created": "2021-03-02T10:55:41.147322Z"
+//    }
+// ---
+
+// ---
+//
+// swagger:operation GET /subscriptions Subscriptions SearchSubscriptions
+// ---
+//
+// description: |
+//   Searches for host event subscriptions.
+//   Returns - The serialized SubscriptionCollection Go struct object that was retrieved. The secrets are not returned.
+//
+// x-permissions: subscriptions:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: deliveryType
+//   description: Delivery type of the subscriptions.
+//   in: query
+//   type: string
+//   enum:
+//     - webhook
+//     - nats
+//   required: false
+// - name: limit
+//   description: Limits the number of subscriptions in the response.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   default: 10000
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully searched the subscriptions. Also returned when no results are found.
+//     schema:
+//       "$ref": "#/definitions/SubscriptionCollection"
+//   '400':
+//     description: Invalid values for search criteria
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions?deliveryType=nats
+// x-sample-call-output: |
+//    {
+//        "subscriptions": [
+//            {
+//                "id": "7d9a2e41-3b6c-4f8d-9e0a-1c2b3d4e5f60",
+//                "delivery_type": "nats",
+//                "endpoint": "hvs.events.siem",
+//                "filter": {
+//                    "event_types": ["trust_changed"]
+//                },
+//                "created": "2021-03-02T10:58:12.512736Z"
+//            }
+//        ]
+//    }
+// ---
+
+// ---
+//
+// swagger:operation GET /subscriptions/{subscription_id} Subscriptions RetrieveSubscription
+// ---
+//
+// description: |
+//   Retrieves a host event subscription.
+//   Returns - The serialized Subscription Go struct object that was retrieved. The secret is not returned.
+//
+// x-permissions: subscriptions:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: subscription_id
+//   description: Unique ID of the subscription.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the subscription.
+//     schema:
+//       "$ref": "#/definitions/Subscription"
+//   '404':
+//     description: Subscription not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions/7d9a2e41-3b6c-4f8d-9e0a-1c2b3d4e5f60
+// x-sample-call-output: |
+//    {
+//        "id": "7d9a2e41-3b6c-4f8d-9e0a-1c2b3d4e5f60",
+//        "delivery_type": "nats",
+//        "endpoint": "hvs.events.siem",
+//        "filter": {
+//            "event_types": ["trust_changed"]
+//        },
+//        "created": "2021-03-02T10:58:12.512736Z"
+//    }
+// ---
+
+// ---
+//
+// swagger:operation DELETE /subscriptions/{subscription_id} Subscriptions DeleteSubscription
+// ---
+//
+// description: |
+//   Deletes a host event subscription.
+//
+// x-permissions: subscriptions:delete
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: subscription_id
+//   description: Unique ID of the subscription.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully deleted the subscription.
+//   '404':
+//     description: Subscription not found
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions/7d9a2e41-3b6c-4f8d-9e0a-1c2b3d4e5f60
+// ---
@@ -104,6 +104,23 @@ type TagCertificateCollection struct {
 //   type: string
 //   format: uuid
 //   required: false
+// - name: limit
+//   description: Limits the number of tag certificates in a page of the response. When the page is full, the next link of the response retrieves the following page.
+//   in: query
+//   type: integer
+//   minimum: 1
+//   required: false
+// - name: after
+//   description: Returns the tag certificates following the one with this ID, as set by the next link of the previous page.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: fields
+//   description: Comma separated list of the fields of the tag certificates to include in the response, all the fields are included by default.
+//   in: query
+//   type: string
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
@@ -317,6 +334,13 @@ type TagCertificateCollection struct {
 //   required: true
 //   enum:
 //     - application/json
+// - name: Prefer
+//   description: With respond-async, the request is processed as a job and the response only holds the job, see GET /jobs/{job_id}
+//   in: header
+//   type: string
+//   required: false
+//   enum:
+//     - respond-async
 // responses:
 //   '200':
 //     description: Successfully deployed the TagCertificate to the host.
@@ -324,6 +348,12 @@ type TagCertificateCollection struct {
 //       application/json
 //     schema:
 //       $ref: "#/definitions/SignedFlavor"
+//   '202':
+//     description: Successfully started the job deploying the TagCertificate, its result is the SignedFlavor, the Location header links to the job.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Job"
 //   '400':
 //     description: Error decoding the TagCertificateDeployCriteria.
 //   '404':
@@ -29,6 +29,13 @@ type KeyCollection struct {
 	Body KeyResponses
 }
 
+// KeyState request payload
+// swagger:parameters KeyStateRequest
+type KeyStateRequest struct {
+	// in:body
+	Body kbs.KeyStateRequest
+}
+
 // KeyTransfer response payload
 // swagger:parameters KeyTransferAttributes
 type KeyTransferAttributes struct {
@@ -200,6 +207,11 @@ type KeyTransferAttributes struct {
 //   required: true
 //   type: string
 //   format: uuid
+// - name: version
+//   description: Version of the key to be transferred. The current version is transferred if not specified.
+//   in: query
+//   type: integer
+//   required: false
 // - name: Content-Type
 //   description: Content-Type header
 //   in: header
@@ -221,8 +233,12 @@ type KeyTransferAttributes struct {
 //       application/json
 //     schema:
 //       $ref: "#/definitions/KeyTransferAttributes"
+//   '400':
+//     description: Invalid version query param value
+//   '403':
+//     description: Key is not active
 //   '404':
-//     description: Key record not found
+//     description: Key record or key version not found
 //   '415':
 //     description: Invalid Content-Type/Accept Header in Request
 //   '500':
@@ -249,6 +265,153 @@ type KeyTransferAttributes struct {
 
 // ---
 
+// swagger:operation POST /keys/{id}/rotate Keys RotateKey
+// ---
+//
+// description: |
+//   Rotates a key. New key material is generated with the algorithm and key length of the key and becomes the
+//   current version of the key. Previous versions are retained and can be transferred by specifying the version.
+//   Returns - The serialized KeyResponse Go struct object that was rotated.
+// x-permissions: keys:rotate
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully rotated the key.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyResponse"
+//   '404':
+//     description: Key record not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/rotate
+// x-sample-call-output: |
+//    {
+//        "key_information": {
+//            "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//            "algorithm": "AES",
+//            "key_length": 256
+//        },
+//        "transfer_policy_id": "3ce27bbd-3c5f-4b15-8c0a-44310f0f83d9",
+//        "transfer_link": "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+//        "created_at": "2021-08-20T06:30:35.085644391Z",
+//        "current_version": 2,
+//        "versions": [
+//            {
+//                "version": 1,
+//                "created_at": "2021-08-20T06:30:35.085644391Z"
+//            },
+//            {
+//                "version": 2,
+//                "created_at": "2021-09-01T10:12:44.452319806Z",
+//                "current": true
+//            }
+//        ]
+//    }
+
+// ---
+
+// swagger:operation PUT /keys/{id}/state Keys UpdateKeyState
+// ---
+//
+// description: |
+//   Changes the lifecycle state of a key. Only active keys can be transferred. Pre-active keys can be activated,
+//   pre-active and active keys can be deactivated and any key can be marked as compromised. Deactivated and
+//   compromised keys can not be activated again. Keys are deactivated automatically once their not_after date passes.
+//   Returns - The serialized KeyResponse Go struct object that was updated.
+// x-permissions: keys:update
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// consumes:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/KeyStateRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully updated the key state.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyResponse"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Key record not found
+//   '409':
+//     description: Key can not be moved to the requested state
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/state
+// x-sample-call-input: |
+//    {
+//        "state": "compromised"
+//    }
+// x-sample-call-output: |
+//    {
+//        "key_information": {
+//            "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//            "algorithm": "AES",
+//            "key_length": 256
+//        },
+//        "transfer_policy_id": "3ce27bbd-3c5f-4b15-8c0a-44310f0f83d9",
+//        "transfer_link": "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+//        "created_at": "2021-08-20T06:30:35.085644391Z",
+//        "not_after": "2022-08-20T00:00:00Z",
+//        "state": "compromised"
+//    }
+
+// ---
+
 // swagger:operation DELETE /keys/{id} Keys DeleteKey
 // ---
 //
@@ -311,6 +474,24 @@ type KeyTransferAttributes struct {
 //   type: string
 //   format: uuid
 //   required: false
+// - name: state
+//   description: Lifecycle state of the key.
+//   in: query
+//   type: string
+//   required: false
+//   enum: [pre-active, active, deactivated, compromised]
+// - name: expiresBefore
+//   description: Returns keys that expire before the given time, RFC3339 formatted.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// - name: expiresAfter
+//   description: Returns keys that expire after the given time, RFC3339 formatted.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
 // - name: Accept
 //   description: Accept header
 //   in: header
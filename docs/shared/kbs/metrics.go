@@ -0,0 +1,38 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package kbs
+
+//
+// swagger:operation GET /metrics Metrics GetMetrics
+// ---
+// description: |
+//   GetMetrics exposes the metrics of the service in the Prometheus text exposition format.
+//   In addition to the Go runtime and process metrics, the request latency and status codes of every route are reported
+//   by isecl_http_request_duration_seconds and isecl_http_requests_total.
+//
+//   | Metric                         | Description |
+//   |--------------------------------|-------------|
+//   | isecl_kbs_key_transfers_total  | Key transfer requests by transfer method (envelope, saml, skc) and outcome |
+//
+//   Returns - The metrics of the service.
+//
+// x-permissions: metrics:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//   - text/plain
+// responses:
+//   '200':
+//     description: Successfully retrieved the metrics.
+//     content: text/plain
+//   '401':
+//     description: The caller does not hold the metrics:retrieve permission.
+//
+// x-sample-call-endpoint: https://kbs.com:8443/kbs/v1/metrics
+// x-sample-call-output: |
+//   # HELP isecl_http_requests_total Number of http requests served, by route, method and status code
+//   # TYPE isecl_http_requests_total counter
+//   isecl_http_requests_total{code="200",method="GET",route="/kbs/v1/keys",service="KBS"} 42
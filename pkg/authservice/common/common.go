@@ -14,8 +14,10 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/authservice/types"
 	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nkeys"
+	"github.com/prometheus/client_golang/prometheus"
 	"net/http"
 	"time"
 )
@@ -24,7 +26,24 @@ var defaultLog = log.GetDefaultLogger()
 
 var defend *defender.Defender
 
+var (
+	defenderBans = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "aas",
+		Name:      "defender_bans_total",
+		Help:      "Number of users banned after exceeding the maximum login attempts",
+	})
+	defenderRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "aas",
+		Name:      "defender_rejected_logins_total",
+		Help:      "Number of login attempts rejected because the user is banned",
+	})
+)
+
 func init() {
+	metrics.MustRegister(defenderBans, defenderRejections)
+
 	c, _ := config.LoadConfiguration()
 
 	defend = defender.New(c.AuthDefender.MaxAttempts,
@@ -50,6 +69,7 @@ func HttpHandleUserAuth(u domain.UserStore, username, password string) (int, err
 			if client.BanExpired() {
 				defend.RemoveClient(client.Key())
 			} else {
+				defenderRejections.Inc()
 				return http.StatusTooManyRequests, fmt.Errorf("Maximum login attempts exceeded for user : %s. Banned !", username)
 			}
 		}
@@ -62,6 +82,7 @@ func HttpHandleUserAuth(u domain.UserStore, username, password string) (int, err
 	}
 	if err := user.CheckPassword([]byte(password)); err != nil {
 		if defend.Inc(username) {
+			defenderBans.Inc()
 			return http.StatusTooManyRequests, fmt.Errorf("Authentication failure - maximum login attempts exceeded for user : %s. Banned !", username)
 		}
 		return http.StatusUnauthorized, fmt.Errorf("BasicAuth failure: password mismatch, user: %s, error : %s", username, err)
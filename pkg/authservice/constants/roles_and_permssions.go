@@ -26,5 +26,7 @@ const (
 
 	CredentialCreate = "credential:create"
 
+	MetricsRetrieve = "metrics:retrieve"
+
 	CredentialCreatorRoleName = "CredentialCreator"
 )
@@ -18,8 +18,21 @@ import (
 	"net/http"
 
 	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+var tokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "aas",
+	Name:      "tokens_issued_total",
+	Help:      "Number of JWT tokens issued, by type of token",
+}, []string{"type"})
+
+func init() {
+	metrics.MustRegister(tokensIssued)
+}
+
 type roleClaims struct {
 	Roles       types.Roles               `json:"roles"`
 	Permissions []aasModel.PermissionInfo `json:"permissions,omitempty"`
@@ -79,6 +92,7 @@ func (controller JwtTokenController) CreateJwtToken(w http.ResponseWriter, r *ht
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate token"}
 	}
 
+	tokensIssued.WithLabelValues("user").Inc()
 	secLog.Infof("%s: Return JWT token of user [%s] to: %s", commLogMsg.TokenIssued, uc.UserName, r.RemoteAddr)
 	return jwt, http.StatusOK, nil
 }
@@ -110,6 +124,7 @@ func (controller JwtTokenController) CreateCustomClaimsJwtToken(w http.ResponseW
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate token"}
 	}
 
+	tokensIssued.WithLabelValues("custom_claims").Inc()
 	secLog.Infof("%s: Created custom claims for user/subject %s with token valid for %d seconds", commLogMsg.TokenIssued, cc.Subject, cc.ValiditySecs)
 	return jwt, http.StatusOK, nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/authservice/postgres"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
 	cmw "github.com/intel-secl/intel-secl/v4/pkg/lib/common/middleware"
 )
 
@@ -35,6 +36,7 @@ func InitRoutes(cfg *config.Configuration, dataStore *postgres.PostgresDatabase,
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
+	router.Use(metrics.NewRequestMetrics(constants.ServiceName))
 	defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, tokenFactory)
 	return router
 }
@@ -60,6 +62,7 @@ func defineSubRoutes(router *mux.Router, service string, cfg *config.Configurati
 	subRouter = SetUsersRoutes(subRouter, dataStore)
 	subRouter = SetAuthJwtTokenRoutes(subRouter, dataStore, tokenFactory)
 	subRouter = SetCredentialsRoutes(subRouter, cfg.Nats.UserCredentialValidity)
+	subRouter = SetMetricsRoutes(subRouter)
 
 }
 
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 )
 
 type HTTPClientErr struct {
@@ -23,18 +25,18 @@ func (ucErr *HTTPClientErr) Error() string {
 }
 
 func HTTPClient() *http.Client {
-	return &http.Client{}
+	return &http.Client{Transport: tracing.NewTransport(nil)}
 }
 
 func HTTPClientTLSNoVerify() *http.Client {
 	//InsecureSkipVerify is set to true as connection is established from utility script and k8s plugin
 	return &http.Client{
-		Transport: &http.Transport{
+		Transport: tracing.NewTransport(&http.Transport{
 			TLSClientConfig: &tls.Config{
 				MinVersion:         tls.VersionTLS13,
 				InsecureSkipVerify: true},
 			Proxy: http.ProxyFromEnvironment,
-		},
+		}),
 	}
 }
 
@@ -45,7 +47,7 @@ func HTTPClientWithCA(caCertificates []x509.Certificate) (*http.Client, error) {
 		RootCAs:            GetCertPool(caCertificates),
 	}
 	tr := &http.Transport{TLSClientConfig: config, Proxy: http.ProxyFromEnvironment}
-	return &http.Client{Transport: tr}, nil
+	return &http.Client{Transport: tracing.NewTransport(tr)}, nil
 }
 
 func ResolvePath(baseURL, path string) string {
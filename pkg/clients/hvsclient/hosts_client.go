@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
@@ -86,6 +87,18 @@ func (client *hostsClientImpl) SearchHosts(hostFilterCriteria *hvs.HostFilterCri
 		query.Add("value", hostFilterCriteria.Value)
 	}
 
+	if hostFilterCriteria.LabelSelector != "" {
+		query.Add("labelSelector", hostFilterCriteria.LabelSelector)
+	}
+
+	if hostFilterCriteria.Limit > 0 {
+		query.Add("limit", strconv.Itoa(hostFilterCriteria.Limit))
+	}
+
+	if hostFilterCriteria.After != uuid.Nil {
+		query.Add("after", hostFilterCriteria.After.String())
+	}
+
 	request.URL.RawQuery = query.Encode()
 
 	log.Debugf("SearchHosts: %s", request.URL.RawQuery)
@@ -21,6 +21,7 @@ type HVSClientFactory interface {
 	ReportsClient() (ReportsClient, error)
 	CertifyHostKeysClient() (CertifyHostKeysClient, error)
 	CACertificatesClient() (CACertificatesClient, error)
+	SearchClient() (SearchClient, error)
 }
 
 type hvsClientConfig struct {
@@ -134,6 +135,15 @@ func (vsClientFactory *defaultVSClientFactory) CACertificatesClient() (CACertifi
 	return &caCertificatesClientImpl{httpClient, vsClientFactory.cfg}, nil
 }
 
+func (vsClientFactory *defaultVSClientFactory) SearchClient() (SearchClient, error) {
+	httpClient, err := vsClientFactory.createHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &searchClientImpl{httpClient, vsClientFactory.cfg}, nil
+}
+
 func (vsClientFactory *defaultVSClientFactory) createHttpClient() (*http.Client, error) {
 	log.Trace("hvsclient/hvsclient_factory:createHttpClient() Entering")
 	defer log.Trace("hvsclient/hvsclient_factory:createHttpClient() Leaving")
@@ -21,6 +21,7 @@ type MockedVSClientFactory struct {
 	MockedFlavorsClient         FlavorsClient
 	MockedManifestsClient       ManifestsClient
 	MockedPrivacyCAClient       PrivacyCAClient
+	MockedSearchClient          SearchClient
 }
 
 func (factory MockedVSClientFactory) HostsClient() (HostsClient, error) {
@@ -51,6 +52,10 @@ func (factory MockedVSClientFactory) ReportsClient() (ReportsClient, error) {
 	return factory.MockedReportsClient, nil
 }
 
+func (factory MockedVSClientFactory) SearchClient() (SearchClient, error) {
+	return factory.MockedSearchClient, nil
+}
+
 //-------------------------------------------------------------------------------------------------
 // Mocked Hosts interface
 //-------------------------------------------------------------------------------------------------
@@ -107,3 +112,18 @@ func (mock MockedManifestsClient) GetManifestXmlByLabel(manifestLabel string) ([
 	args := mock.Called(manifestLabel)
 	return args.Get(0).([]byte), args.Error(1)
 }
+
+//-------------------------------------------------------------------------------------------------
+// Mocked Search interface
+//-------------------------------------------------------------------------------------------------
+type MockedSearchClient struct {
+	mock.Mock
+}
+
+// Can be mocked in unit tests similar to...
+// mockedSearchClient := new(hvsclient.MockedSearchClient)
+// mockedSearchClient.On("GetPage", "flavors?limit=100", mock.Anything).Return("", nil)
+func (mock *MockedSearchClient) GetPage(link string, collection interface{}) (string, error) {
+	args := mock.Called(link, collection)
+	return args.String(0), args.Error(1)
+}
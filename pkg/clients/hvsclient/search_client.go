@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvsclient
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+//-------------------------------------------------------------------------------------------------
+// Public interface/structures
+//-------------------------------------------------------------------------------------------------
+
+type SearchClient interface {
+
+	// Retrieves the page of search results at the link and decodes it into the collection. The link is either an
+	// endpoint relative to the HVS base URL with its query, for example "flavors?limit=100", or the next link of a
+	// previous page. Returns the next link of the page, which is empty for the last page.
+	GetPage(link string, collection interface{}) (string, error)
+}
+
+// PageIterator iterates over the pages of the results of an HVS search endpoint, such as flavors, flavorgroups,
+// tag-certificates, reports or host-status, by following the next link of each page.
+//
+//	pages := hvsclient.NewPageIterator(searchClient, "flavors", url.Values{"limit": {"100"}})
+//	var flavors hvs.SignedFlavorCollection
+//	for pages.Next(&flavors) {
+//	    ...
+//	}
+//	if pages.Err() != nil {
+//	    ...
+//	}
+type PageIterator struct {
+	client SearchClient
+	link   string
+	err    error
+}
+
+// NewPageIterator returns an iterator over the pages of the results of the search endpoint with the query, which
+// holds the search criteria and usually the limit of the size of a page
+func NewPageIterator(client SearchClient, endpoint string, query url.Values) *PageIterator {
+	link := endpoint
+	if len(query) > 0 {
+		link = endpoint + "?" + query.Encode()
+	}
+	return &PageIterator{client: client, link: link}
+}
+
+// Next retrieves the next page of results into the collection. It returns false once all the pages were retrieved
+// or when retrieving a page fails, Err then returns the failure.
+func (it *PageIterator) Next(collection interface{}) bool {
+	if it.link == "" || it.err != nil {
+		return false
+	}
+	it.link, it.err = it.client.GetPage(it.link, collection)
+	return it.err == nil
+}
+
+// Err returns the failure to retrieve a page, if any
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// HostIterator iterates over the hosts matching search criteria, retrieving them a page at a time when the criteria
+// has a limit
+//
+//	hosts := hvsclient.NewHostIterator(hostsClient, &hvs.HostFilterCriteria{Limit: 100})
+//	for hosts.Next() {
+//	    host := hosts.Host()
+//	    ...
+//	}
+//	if hosts.Err() != nil {
+//	    ...
+//	}
+type HostIterator struct {
+	client   HostsClient
+	criteria hvs.HostFilterCriteria
+	hosts    []*hvs.Host
+	host     *hvs.Host
+	lastPage bool
+	err      error
+}
+
+// NewHostIterator returns an iterator over the hosts matching the criteria, starting after the host identified by
+// the After field of the criteria, if set
+func NewHostIterator(client HostsClient, criteria *hvs.HostFilterCriteria) *HostIterator {
+	it := &HostIterator{client: client}
+	if criteria != nil {
+		it.criteria = *criteria
+	}
+	return it
+}
+
+// Next moves to the next host, retrieving the next page of hosts when needed. It returns false once all the hosts
+// were iterated over or when retrieving a page fails, Err then returns the failure.
+func (it *HostIterator) Next() bool {
+	log.Trace("hvsclient/search_client:Next() Entering")
+	defer log.Trace("hvsclient/search_client:Next() Leaving")
+
+	it.host = nil
+	for len(it.hosts) == 0 {
+		if it.lastPage || it.err != nil {
+			return false
+		}
+		page, err := it.client.SearchHosts(&it.criteria)
+		if err != nil {
+			it.err = errors.Wrap(err, "hvsclient/search_client:Next() Error retrieving page of hosts")
+			return false
+		}
+		it.hosts = page.Hosts
+		if page.Next == "" || len(page.Hosts) == 0 {
+			it.lastPage = true
+		} else {
+			it.criteria.After = page.Hosts[len(page.Hosts)-1].Id
+		}
+	}
+	it.host, it.hosts = it.hosts[0], it.hosts[1:]
+	return true
+}
+
+// Host returns the current host
+func (it *HostIterator) Host() *hvs.Host {
+	return it.host
+}
+
+// Err returns the failure to retrieve a page of hosts, if any
+func (it *HostIterator) Err() error {
+	return it.err
+}
+
+//-------------------------------------------------------------------------------------------------
+// Implementation
+//-------------------------------------------------------------------------------------------------
+
+type searchClientImpl struct {
+	httpClient *http.Client
+	cfg        *hvsClientConfig
+}
+
+func (client *searchClientImpl) GetPage(link string, collection interface{}) (string, error) {
+	log.Trace("hvsclient/search_client:GetPage() Entering")
+	defer log.Trace("hvsclient/search_client:GetPage() Leaving")
+
+	baseURL := client.cfg.BaseURL
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL = baseURL + "/"
+	}
+	parsedUrl, err := url.Parse(baseURL)
+	if err != nil {
+		return "", errors.Wrap(err, "hvsclient/search_client:GetPage() error parsing base url")
+	}
+	pageUrl, err := parsedUrl.Parse(link)
+	if err != nil {
+		return "", errors.Wrapf(err, "hvsclient/search_client:GetPage() error parsing page link %s", link)
+	}
+	if pageUrl.Host != parsedUrl.Host {
+		return "", errors.Errorf("hvsclient/search_client:GetPage() page link %s is not an HVS link", link)
+	}
+
+	request, err := http.NewRequest("GET", pageUrl.String(), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "hvsclient/search_client:GetPage() error creating request")
+	}
+	request.Header.Set("Authorization", "Bearer "+client.cfg.BearerToken)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		secLog.Warn(message.BadConnection)
+		return "", errors.Wrapf(err, "hvsclient/search_client:GetPage() Error making request to %s", pageUrl)
+	}
+
+	defer func() {
+		derr := response.Body.Close()
+		if derr != nil {
+			log.WithError(derr).Error("Error closing response body")
+		}
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Errorf("hvsclient/search_client:GetPage() Request made to %s returned status %d", pageUrl, response.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "hvsclient/search_client:GetPage() Error reading response")
+	}
+
+	err = json.Unmarshal(data, collection)
+	if err != nil {
+		return "", errors.Wrap(err, "hvsclient/search_client:GetPage() Error while unmarshaling the response")
+	}
+
+	var page struct {
+		Next string `json:"next"`
+	}
+	err = json.Unmarshal(data, &page)
+	if err != nil {
+		return "", errors.Wrap(err, "hvsclient/search_client:GetPage() Error while unmarshaling the next link")
+	}
+	return page.Next, nil
+}
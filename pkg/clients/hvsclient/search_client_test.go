@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvsclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+var pagedHostIds = []uuid.UUID{
+	uuid.MustParse("0a1b2c3d-0000-4000-8000-000000000001"),
+	uuid.MustParse("0a1b2c3d-0000-4000-8000-000000000002"),
+	uuid.MustParse("0a1b2c3d-0000-4000-8000-000000000003"),
+}
+
+// mockPagingServer serves the hosts with the ids in pagedHostIds a page at a time, like the HVS search endpoints
+func mockPagingServer(t *testing.T) *httptest.Server {
+	router := mux.NewRouter()
+
+	router.HandleFunc("/hvs/v2/hosts", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		query := r.URL.Query()
+		start := 0
+		if after := query.Get("after"); after != "" {
+			for i, id := range pagedHostIds {
+				if id.String() == after {
+					start = i + 1
+				}
+			}
+		}
+		end := len(pagedHostIds)
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit > 0 && start+limit < end {
+			end = start + limit
+		}
+
+		hosts := hvs.HostCollection{Hosts: []*hvs.Host{}}
+		for _, id := range pagedHostIds[start:end] {
+			hosts.Hosts = append(hosts.Hosts, &hvs.Host{Id: id, HostName: "host-" + id.String()[32:]})
+		}
+		if limit > 0 && len(hosts.Hosts) == limit {
+			query.Set("after", pagedHostIds[end-1].String())
+			hosts.Next = (&url.URL{Path: r.URL.Path, RawQuery: query.Encode()}).String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(hosts))
+	}).Methods("GET")
+
+	return httptest.NewServer(router)
+}
+
+func TestHostIterator(t *testing.T) {
+	server := mockPagingServer(t)
+	defer server.Close()
+
+	cfg := &hvsClientConfig{BaseURL: server.URL + "/hvs/v2", BearerToken: "token"}
+	client := &hostsClientImpl{server.Client(), cfg}
+
+	for _, limit := range []int{0, 1, 2, 3} {
+		hosts := NewHostIterator(client, &hvs.HostFilterCriteria{Limit: limit})
+		var ids []uuid.UUID
+		for hosts.Next() {
+			ids = append(ids, hosts.Host().Id)
+		}
+		assert.NoError(t, hosts.Err())
+		assert.Equal(t, pagedHostIds, ids, "limit %d", limit)
+	}
+
+	hosts := NewHostIterator(client, &hvs.HostFilterCriteria{Limit: 1, After: pagedHostIds[1]})
+	assert.True(t, hosts.Next())
+	assert.Equal(t, pagedHostIds[2], hosts.Host().Id)
+	assert.False(t, hosts.Next())
+	assert.Nil(t, hosts.Host())
+}
+
+func TestHostIteratorError(t *testing.T) {
+	server := mockPagingServer(t)
+	defer server.Close()
+
+	cfg := &hvsClientConfig{BaseURL: server.URL + "/hvs/v1", BearerToken: "token"}
+	hosts := NewHostIterator(&hostsClientImpl{server.Client(), cfg}, &hvs.HostFilterCriteria{Limit: 1})
+	assert.False(t, hosts.Next())
+	assert.Error(t, hosts.Err())
+}
+
+func TestPageIterator(t *testing.T) {
+	server := mockPagingServer(t)
+	defer server.Close()
+
+	cfg := &hvsClientConfig{BaseURL: server.URL + "/hvs/v2/", BearerToken: "token"}
+	pages := NewPageIterator(&searchClientImpl{server.Client(), cfg}, "hosts", url.Values{"limit": {"2"}})
+
+	var pageSizes []int
+	var ids []uuid.UUID
+	var hosts hvs.HostCollection
+	for pages.Next(&hosts) {
+		pageSizes = append(pageSizes, len(hosts.Hosts))
+		for _, host := range hosts.Hosts {
+			ids = append(ids, host.Id)
+		}
+	}
+	assert.NoError(t, pages.Err())
+	assert.Equal(t, []int{2, 1}, pageSizes)
+	assert.Equal(t, pagedHostIds, ids)
+}
+
+func TestSearchClientRejectsForeignLinks(t *testing.T) {
+	server := mockPagingServer(t)
+	defer server.Close()
+
+	cfg := &hvsClientConfig{BaseURL: server.URL + "/hvs/v2/", BearerToken: "token"}
+	client := &searchClientImpl{server.Client(), cfg}
+
+	var hosts hvs.HostCollection
+	_, err := client.GetPage("https://attacker.example.com/hvs/v2/hosts", &hosts)
+	assert.Error(t, err)
+}
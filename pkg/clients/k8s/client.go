@@ -16,6 +16,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/clients"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
 	"github.com/pkg/errors"
 )
@@ -170,7 +171,7 @@ func (k8sClient *Client) getK8sHTTPClient() (*http.Client, error) {
 		log.Debug("K8s/client:getK8sHTTPClient() Creating Insecure K8s Client")
 		k8sHTTPClient = clients.HTTPClientTLSNoVerify()
 	}
-	k8sHTTPClient.Transport.(*http.Transport).TLSClientConfig.MinVersion = tls.VersionTLS12
+	tracing.BaseTransport(k8sHTTPClient.Transport).(*http.Transport).TLSClientConfig.MinVersion = tls.VersionTLS12
 	return k8sHTTPClient, nil
 
 }
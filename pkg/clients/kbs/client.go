@@ -17,6 +17,7 @@ var log = commLog.GetDefaultLogger()
 type KBSClient interface {
 	CreateKey(*kbs.KeyRequest) (*kbs.KeyResponse, error)
 	TransferKey(string, string) (*kbs.KeyTransferAttributes, error)
+	TransferKeyVersion(string, string, int) (*kbs.KeyTransferAttributes, error)
 	TransferKeyWithSaml(string, string) ([]byte, error)
 }
 
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/intel-secl/intel-secl/v4/pkg/clients/util"
@@ -58,10 +59,22 @@ func (k *kbsClient) TransferKey(keyId, pubKey string) (*kbs.KeyTransferAttribute
 	log.Trace("kbs/client:TransferKey() Entering")
 	defer log.Trace("kbs/client:TransferKey() Leaving")
 
+	return k.TransferKeyVersion(keyId, pubKey, 0)
+}
+
+// TransferKeyVersion performs a POST to /keys/{id}/transfer?version={version} to retrieve the key data of the given
+// version from the KBS, the current version of the key is transferred when version is 0
+func (k *kbsClient) TransferKeyVersion(keyId, pubKey string, version int) (*kbs.KeyTransferAttributes, error) {
+	log.Trace("kbs/client:TransferKeyVersion() Entering")
+	defer log.Trace("kbs/client:TransferKeyVersion() Leaving")
+
 	keyXferURL, err := url.Parse(fmt.Sprintf("keys/%s/transfer", keyId))
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed parsing key transfer URL")
 	}
+	if version > 0 {
+		keyXferURL.RawQuery = url.Values{"version": []string{strconv.Itoa(version)}}.Encode()
+	}
 
 	reqURL := k.BaseURL.ResolveReference(keyXferURL)
 	req, err := http.NewRequest("POST", reqURL.String(), strings.NewReader(pubKey))
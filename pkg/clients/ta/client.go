@@ -6,6 +6,7 @@ package ta
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
@@ -31,8 +32,17 @@ type TAClient interface {
 
 func NewTAClient(aasApiUrl string, taApiUrl *url.URL, serviceUserName, serviceUserPassword string,
 	trustedCaCerts []x509.Certificate) (TAClient, error) {
+	return NewTAClientWithContext(context.Background(), aasApiUrl, taApiUrl, serviceUserName, serviceUserPassword,
+		trustedCaCerts)
+}
+
+// NewTAClientWithContext returns a TAClient whose requests to the Trust Agent are bound to ctx, so that they are
+// cancelled along with ctx and carry the trace context it holds
+func NewTAClientWithContext(ctx context.Context, aasApiUrl string, taApiUrl *url.URL, serviceUserName,
+	serviceUserPassword string, trustedCaCerts []x509.Certificate) (TAClient, error) {
 
 	taClient := taClient{
+		ctx:             ctx,
 		AasURL:          aasApiUrl,
 		BaseURL:         taApiUrl,
 		ServiceUsername: serviceUserName,
@@ -44,6 +54,7 @@ func NewTAClient(aasApiUrl string, taApiUrl *url.URL, serviceUserName, serviceUs
 }
 
 type taClient struct {
+	ctx             context.Context
 	AasURL          string
 	BaseURL         *url.URL
 	ServiceUsername string
@@ -65,7 +76,7 @@ func (tc *taClient) GetHostInfo() (taModel.HostInfo, error) {
 		return hostInfo, errors.New("client/trust_agent_client:GetHostInfo() error forming GET host info URL")
 	}
 	log.Debug("client/trust_agent_client:GetHostInfo() Request URL created for host info")
-	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "GET", requestURL.String(), nil)
 	if err != nil {
 		return hostInfo, err
 	}
@@ -109,7 +120,7 @@ func (tc *taClient) GetTPMQuote(nonce string, pcrList []int, pcrBankList []strin
 	buffer := new(bytes.Buffer)
 	err = json.NewEncoder(buffer).Encode(quoteRequest)
 	secLog.Debugf("client/trust_agent_client:GetTPMQuote() TPM quote request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return quoteResponse, err
 	}
@@ -142,7 +153,7 @@ func (tc *taClient) GetAIK() ([]byte, error) {
 	}
 	log.Debug("clients/trust_agent_client:GetAIK() Request URL created for AIK certificate")
 
-	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "GET", requestURL.String(), nil)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -168,7 +179,7 @@ func (tc *taClient) GetBindingKeyCertificate() ([]byte, error) {
 			"certificate URL")
 	}
 	log.Debug("clients/trust_agent_client:GetBindingKeyCertificate() Request URL created for Binding Key certificate")
-	httpRequest, err := http.NewRequest("GET", requestURL.String(), nil)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "GET", requestURL.String(), nil)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -206,7 +217,7 @@ func (tc *taClient) DeployAssetTag(hardwareUUID, tag string) error {
 	buffer := new(bytes.Buffer)
 	err = json.NewEncoder(buffer).Encode(tagWriteRequest)
 	secLog.Debugf("TAG request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return err
 	}
@@ -235,7 +246,7 @@ func (tc *taClient) DeploySoftwareManifest(manifest taModel.Manifest) error {
 	buffer := new(bytes.Buffer)
 	err = xml.NewEncoder(buffer).Encode(manifest)
 	log.Debugf("Manifest request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return err
 	}
@@ -265,7 +276,7 @@ func (tc *taClient) GetMeasurementFromManifest(manifest taModel.Manifest) (taMod
 	buffer := new(bytes.Buffer)
 	err = xml.NewEncoder(buffer).Encode(manifest)
 	log.Debugf("Manifest request: %s", buffer.String())
-	httpRequest, err := http.NewRequest("POST", requestURL.String(), buffer)
+	httpRequest, err := http.NewRequestWithContext(tc.ctx, "POST", requestURL.String(), buffer)
 	if err != nil {
 		return measurement, err
 	}
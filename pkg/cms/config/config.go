@@ -18,6 +18,7 @@ import (
 type Configuration struct {
 	Log               commConfig.LogConfig    `yaml:"log" mapstructure:"log"`
 	AASApiUrl         string                  `yaml:"aas-base-url" mapstructure:"aas-base-url"`
+	CmsBaseUrl        string                  `yaml:"cms-base-url" mapstructure:"cms-base-url"`
 	CACert            CACertConfig            `yaml:"cms-ca" mapstructure:"cms-ca"`
 	TlsCertDigest     string                  `yaml:"tls-cert-digest" mapstructure:"tls-cert-digest"`
 	TlsSanList        string                  `yaml:"san-list" mapstructure:"san-list"`
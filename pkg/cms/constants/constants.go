@@ -32,6 +32,8 @@ const (
 	TLSCertPath                    = ConfigDir + "tls-cert.pem"
 	TLSKeyPath                     = ConfigDir + "tls.key"
 	SerialNumberPath               = ConfigDir + "serial-number"
+	IssuedCertsDirPath             = ConfigDir + "issued-certs/"
+	AcmeDirPath                    = ConfigDir + "acme/"
 	ServiceRemoveCmd               = "systemctl disable cms"
 	DefaultRootCACommonName        = "CMSCA"
 	DefaultPort                    = 8445
@@ -43,6 +45,8 @@ const (
 	DefaultKeyAlgorithm            = "rsa"
 	DefaultKeyAlgorithmLength      = 3072
 	CertApproverGroupName          = "CertApprover"
+	CertRevokerGroupName           = "CertRevoker"
+	MetricsRetrievePermission      = "metrics:retrieve"
 	DefaultAasJwtCn                = "AAS JWT Signing Certificate"
 	DefaultAasTlsCn                = "AAS TLS Certificate"
 	DefaultTlsSan                  = "127.0.0.1,localhost"
@@ -54,6 +58,9 @@ const (
 	DefaultIdleTimeout             = 10 * time.Second
 	DefaultMaxHeaderBytes          = 1 << 20
 	DefaultLogEntryMaxlength       = 300
+	DefaultCRLValidity             = 24 * time.Hour
+	DefaultAcmeOrderValidity       = 7 * 24 * time.Hour
+	DefaultAcmeNoncePruneInterval  = 5 * time.Minute
 )
 
 type CaAttrib struct {
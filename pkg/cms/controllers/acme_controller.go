@@ -0,0 +1,771 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/validation"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/auth"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	ct "github.com/intel-secl/intel-secl/v4/pkg/model/aas"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	acmeErrorPrefix    = "urn:ietf:params:acme:error:"
+	maxAcmeRequestSize = 1 << 16
+)
+
+// AcmeController implements the RFC 8555 ACME server for the CMS TLS CA. ACME accounts must be created with an
+// external account binding key issued to an AAS user, and are authorized for the SANs of that user's TLS CertApprover
+// roles, so their authorizations are valid without having to complete a challenge.
+type AcmeController struct {
+	Config *config.Configuration
+	Store  *utils.AcmeStore
+}
+
+//GetDirectory is used to get the ACME directory object
+func (controller AcmeController) GetDirectory(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetDirectory() Entering")
+	defer log.Trace("resource/acme:GetDirectory() Leaving")
+
+	directory := cms.AcmeDirectory{
+		NewNonce:   controller.acmeUrl("new-nonce"),
+		NewAccount: controller.acmeUrl("new-account"),
+		NewOrder:   controller.acmeUrl("new-order"),
+		Meta: cms.AcmeDirectoryMeta{
+			ExternalAccountRequired: true,
+		},
+	}
+	controller.writeAcmeResponse(httpWriter, http.StatusOK, directory, "")
+}
+
+//NewNonce is used to get a fresh anti-replay nonce in the Replay-Nonce header
+func (controller AcmeController) NewNonce(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:NewNonce() Entering")
+	defer log.Trace("resource/acme:NewNonce() Leaving")
+
+	httpWriter.Header().Set("Cache-Control", "no-store")
+	if httpRequest.Method == http.MethodHead {
+		controller.writeAcmeResponse(httpWriter, http.StatusOK, nil, "")
+	} else {
+		controller.writeAcmeResponse(httpWriter, http.StatusNoContent, nil, "")
+	}
+}
+
+//NewAccount is used to create an ACME account bound to an external account binding key or to look up the existing
+// account of the request key
+func (controller AcmeController) NewAccount(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:NewAccount() Entering")
+	defer log.Trace("resource/acme:NewAccount() Leaving")
+
+	jws, ok := controller.verifyAcmeRequest(httpWriter, httpRequest, true)
+	if !ok {
+		return
+	}
+	var accountRequest cms.AcmeAccountRequest
+	if err := json.Unmarshal(jws.Payload, &accountRequest); err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Unable to decode account request")
+		return
+	}
+	thumbprint, err := utils.JWKThumbprint(jws.JWK)
+	if err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badPublicKey", "Unsupported account key")
+		return
+	}
+
+	account, err := controller.Store.GetAccountByThumbprint(thumbprint)
+	if err == nil {
+		controller.writeAcmeResponse(httpWriter, http.StatusOK, controller.accountResource(account), controller.acmeUrl("account/"+account.ID))
+		return
+	} else if errors.Cause(err) != utils.ErrAcmeResourceNotFound {
+		log.WithError(err).Error("resource/acme:NewAccount() Failed to look up ACME account")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to look up account")
+		return
+	}
+	if accountRequest.OnlyReturnExisting {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "accountDoesNotExist", "No account exists with the provided key")
+		return
+	}
+	if len(accountRequest.ExternalAccountBinding) == 0 {
+		controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "externalAccountRequired", "External account binding is required")
+		return
+	}
+
+	eabKey, err := controller.Store.VerifyExternalAccountBinding(accountRequest.ExternalAccountBinding, jws.JWK, jws.URL)
+	if err != nil {
+		slog.WithError(err).Warning(commLogMsg.UnauthorizedAccess)
+		controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "unauthorized", "External account binding could not be verified")
+		return
+	}
+	if !time.Now().Before(eabKey.RolesExpireAt) {
+		slog.Warningf("%s: Roles of external account binding key %s have expired", commLogMsg.UnauthorizedAccess, eabKey.KeyID)
+		controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "unauthorized", "External account binding key has expired")
+		return
+	}
+	accountKey, err := json.Marshal(jws.JWK)
+	if err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badPublicKey", "Unsupported account key")
+		return
+	}
+	accountID, err := utils.NewAcmeID()
+	if err != nil {
+		log.WithError(err).Error("resource/acme:NewAccount() Failed to generate account ID")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to create account")
+		return
+	}
+	account = &utils.AcmeAccountRecord{
+		ID:            accountID,
+		Status:        cms.AcmeStatusValid,
+		Contact:       accountRequest.Contact,
+		Key:           accountKey,
+		KeyThumbprint: thumbprint,
+		EabKeyID:      eabKey.KeyID,
+		RoleContexts:  eabKey.RoleContexts,
+		RolesExpireAt: eabKey.RolesExpireAt,
+		CreatedAt:     time.Now().UTC(),
+	}
+	eabKey.AccountID = accountID
+	if err = controller.Store.SaveEabKey(eabKey); err == nil {
+		err = controller.Store.SaveAccount(account)
+	}
+	if err != nil {
+		log.WithError(err).Error("resource/acme:NewAccount() Failed to save ACME account")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to create account")
+		return
+	}
+	slog.Infof("resource/acme:NewAccount() Created ACME account %s bound to external account key %s", account.ID, eabKey.KeyID)
+	controller.writeAcmeResponse(httpWriter, http.StatusCreated, controller.accountResource(account), controller.acmeUrl("account/"+account.ID))
+}
+
+//UpdateAccount is used to get, update the contacts of or deactivate an ACME account
+func (controller AcmeController) UpdateAccount(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:UpdateAccount() Entering")
+	defer log.Trace("resource/acme:UpdateAccount() Leaving")
+
+	jws, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok {
+		return
+	}
+	if account.ID != mux.Vars(httpRequest)["id"] {
+		controller.writeAcmeProblem(httpWriter, http.StatusForbidden, "unauthorized", "Account does not match the request key")
+		return
+	}
+	if len(jws.Payload) > 0 {
+		var accountRequest cms.AcmeAccountRequest
+		if err := json.Unmarshal(jws.Payload, &accountRequest); err != nil {
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Unable to decode account request")
+			return
+		}
+		if accountRequest.Status != "" && accountRequest.Status != cms.AcmeStatusDeactivated {
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Account status can only be set to deactivated")
+			return
+		}
+		if accountRequest.Status == cms.AcmeStatusDeactivated {
+			account.Status = cms.AcmeStatusDeactivated
+		}
+		if accountRequest.Contact != nil {
+			account.Contact = accountRequest.Contact
+		}
+		if err := controller.Store.SaveAccount(account); err != nil {
+			log.WithError(err).Error("resource/acme:UpdateAccount() Failed to save ACME account")
+			controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to update account")
+			return
+		}
+	}
+	controller.writeAcmeResponse(httpWriter, http.StatusOK, controller.accountResource(account), controller.acmeUrl("account/"+account.ID))
+}
+
+//NewOrder is used to order a TLS certificate for identifiers that the ACME account is authorized for
+func (controller AcmeController) NewOrder(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:NewOrder() Entering")
+	defer log.Trace("resource/acme:NewOrder() Leaving")
+
+	jws, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok || !controller.verifyAccountRoles(httpWriter, account) {
+		return
+	}
+	var orderRequest cms.AcmeOrderRequest
+	if err := json.Unmarshal(jws.Payload, &orderRequest); err != nil || len(orderRequest.Identifiers) == 0 {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Unable to decode order request")
+		return
+	}
+	for _, identifier := range orderRequest.Identifiers {
+		if identifier.Type != cms.AcmeIdentifierDNS && identifier.Type != cms.AcmeIdentifierIP {
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "unsupportedIdentifier", "Unsupported identifier type "+identifier.Type)
+			return
+		}
+		if identifier.Type == cms.AcmeIdentifierIP && net.ParseIP(identifier.Value) == nil {
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Invalid IP address "+identifier.Value)
+			return
+		}
+		if err := validation.ValidateAcmeIdentifier(account.RoleContexts, identifier.Value); err != nil {
+			slog.WithError(err).Warning(commLogMsg.UnauthorizedAccess)
+			controller.writeAcmeProblem(httpWriter, http.StatusForbidden, "rejectedIdentifier", "Account is not authorized for identifier "+identifier.Value)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	order := utils.AcmeOrderRecord{
+		AccountID:   account.ID,
+		Status:      cms.AcmeStatusReady,
+		Expires:     now.Add(constants.DefaultAcmeOrderValidity),
+		Identifiers: orderRequest.Identifiers,
+	}
+	var err error
+	if order.ID, err = utils.NewAcmeID(); err != nil {
+		log.WithError(err).Error("resource/acme:NewOrder() Failed to generate order ID")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to create order")
+		return
+	}
+	for _, identifier := range orderRequest.Identifiers {
+		authz := utils.AcmeAuthorizationRecord{
+			AccountID:  account.ID,
+			Identifier: identifier,
+			Status:     cms.AcmeStatusValid,
+			Expires:    order.Expires,
+			Validated:  &now,
+		}
+		if authz.ID, err = utils.NewAcmeID(); err == nil {
+			if authz.Token, err = utils.NewAcmeID(); err == nil {
+				err = controller.Store.SaveAuthorization(&authz)
+			}
+		}
+		if err != nil {
+			log.WithError(err).Error("resource/acme:NewOrder() Failed to save ACME authorization")
+			controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to create order")
+			return
+		}
+		order.AuthorizationIDs = append(order.AuthorizationIDs, authz.ID)
+	}
+	if err = controller.Store.SaveOrder(&order); err != nil {
+		log.WithError(err).Error("resource/acme:NewOrder() Failed to save ACME order")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to create order")
+		return
+	}
+	controller.writeAcmeResponse(httpWriter, http.StatusCreated, controller.orderResource(&order), controller.acmeUrl("order/"+order.ID))
+}
+
+//GetOrder is used to get an ACME order of the account
+func (controller AcmeController) GetOrder(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetOrder() Entering")
+	defer log.Trace("resource/acme:GetOrder() Leaving")
+
+	_, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok {
+		return
+	}
+	order, ok := controller.getAccountOrder(httpWriter, mux.Vars(httpRequest)["id"], account)
+	if !ok {
+		return
+	}
+	controller.writeAcmeResponse(httpWriter, http.StatusOK, controller.orderResource(order), controller.acmeUrl("order/"+order.ID))
+}
+
+//GetAuthorization is used to get an ACME authorization of the account
+func (controller AcmeController) GetAuthorization(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetAuthorization() Entering")
+	defer log.Trace("resource/acme:GetAuthorization() Leaving")
+
+	_, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok {
+		return
+	}
+	authz, ok := controller.getAccountAuthorization(httpWriter, mux.Vars(httpRequest)["id"], account)
+	if !ok {
+		return
+	}
+	controller.writeAcmeResponse(httpWriter, http.StatusOK, controller.authorizationResource(authz), "")
+}
+
+//GetChallenge is used to get or respond to the challenge of an ACME authorization. Authorizations are granted through
+// the AAS roles bound to the account, so the challenge is already valid and responding to it has no further effect.
+func (controller AcmeController) GetChallenge(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetChallenge() Entering")
+	defer log.Trace("resource/acme:GetChallenge() Leaving")
+
+	_, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok {
+		return
+	}
+	authz, ok := controller.getAccountAuthorization(httpWriter, mux.Vars(httpRequest)["id"], account)
+	if !ok {
+		return
+	}
+	httpWriter.Header().Add("Link", "<"+controller.acmeUrl("authz/"+authz.ID)+">;rel=\"up\"")
+	controller.writeAcmeResponse(httpWriter, http.StatusOK, controller.authorizationResource(authz).Challenges[0], "")
+}
+
+//FinalizeOrder is used to submit the CSR of a ready ACME order and issue the certificate from the TLS CA
+func (controller AcmeController) FinalizeOrder(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:FinalizeOrder() Entering")
+	defer log.Trace("resource/acme:FinalizeOrder() Leaving")
+
+	jws, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok {
+		return
+	}
+	order, ok := controller.getAccountOrder(httpWriter, mux.Vars(httpRequest)["id"], account)
+	if !ok {
+		return
+	}
+	if order.Status != cms.AcmeStatusReady {
+		controller.writeAcmeProblem(httpWriter, http.StatusForbidden, "orderNotReady", "Order is "+order.Status)
+		return
+	}
+	if !controller.verifyAccountRoles(httpWriter, account) {
+		return
+	}
+
+	var finalizeRequest cms.AcmeFinalizeRequest
+	if err := json.Unmarshal(jws.Payload, &finalizeRequest); err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Unable to decode finalize request")
+		return
+	}
+	csrDer, err := base64.RawURLEncoding.DecodeString(finalizeRequest.Csr)
+	if err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badCSR", "CSR is not base64url encoded")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(csrDer)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badCSR", "Invalid CSR provided")
+		return
+	}
+	if !csrMatchesIdentifiers(csr, order.Identifiers) {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badCSR", "CSR SAN list does not match the order identifiers")
+		return
+	}
+	ctxMap := make(map[string]ct.RoleInfo)
+	for _, roleContext := range account.RoleContexts {
+		ctxMap[roleContext] = ct.RoleInfo{Service: constants.ServiceName, Name: constants.CertApproverGroupName, Context: roleContext}
+	}
+	if err = validation.ValidateCertificateRequest(controller.Config, csr, constants.Tls, &ctxMap); err != nil {
+		slog.WithError(err).Warning(commLogMsg.InvalidInputBadParam)
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badCSR", "Invalid CSR provided")
+		return
+	}
+
+	certificateChain, err := issueAcmeCertificate(controller.Config, csr)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:FinalizeOrder() Failed to issue certificate")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to issue certificate")
+		return
+	}
+	order.Status = cms.AcmeStatusValid
+	order.CertificateChain = string(certificateChain)
+	if err = controller.Store.SaveOrder(order); err != nil {
+		log.WithError(err).Error("resource/acme:FinalizeOrder() Failed to save ACME order")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to finalize order")
+		return
+	}
+	log.Infof("resource/acme:FinalizeOrder() Issued certificate for ACME order %s with CN - %v", order.ID, csr.Subject.String())
+	controller.writeAcmeResponse(httpWriter, http.StatusOK, controller.orderResource(order), controller.acmeUrl("order/"+order.ID))
+}
+
+//GetCertificate is used to download the certificate chain issued for an ACME order
+func (controller AcmeController) GetCertificate(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetCertificate() Entering")
+	defer log.Trace("resource/acme:GetCertificate() Leaving")
+
+	_, account, ok := controller.verifyAccountRequest(httpWriter, httpRequest)
+	if !ok {
+		return
+	}
+	order, ok := controller.getAccountOrder(httpWriter, mux.Vars(httpRequest)["id"], account)
+	if !ok {
+		return
+	}
+	if order.CertificateChain == "" {
+		controller.writeAcmeProblem(httpWriter, http.StatusNotFound, "malformed", "Certificate has not been issued")
+		return
+	}
+	controller.setReplayNonce(httpWriter)
+	httpWriter.Header().Set("Content-Type", "application/pem-certificate-chain")
+	httpWriter.WriteHeader(http.StatusOK)
+	_, err := httpWriter.Write([]byte(order.CertificateChain))
+	if err != nil {
+		log.WithError(err).Errorf("resource/acme:GetCertificate() Failed to write response")
+	}
+}
+
+//CreateEabKey is used to create an ACME external account binding key for the TLS CertApprover roles of the caller
+func (controller AcmeController) CreateEabKey(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:CreateEabKey() Entering")
+	defer log.Trace("resource/acme:CreateEabKey() Leaving")
+
+	privileges, err := context.GetUserRoles(httpRequest)
+	if err != nil {
+		slog.WithError(err).Warn("resource/acme:CreateEabKey() Failed to read roles and permissions")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Could not get user roles from http context")
+		return
+	}
+	ctxMap, foundRole := auth.ValidatePermissionAndGetRoleContext(privileges,
+		[]ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertApproverGroupName}},
+		false)
+	var roleContexts []string
+	for roleContext := range *ctxMap {
+		if strings.Contains(strings.ToUpper(roleContext), "CERTTYPE="+strings.ToUpper(constants.Tls)) {
+			roleContexts = append(roleContexts, roleContext)
+		}
+	}
+	if !foundRole || len(roleContexts) == 0 {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	sort.Strings(roleContexts)
+	// the roles are honoured until the token that granted them expires, like for any other REST call
+	rolesExpireAt, err := context.GetTokenExpiresAt(httpRequest)
+	if err != nil || rolesExpireAt.IsZero() {
+		slog.Warningf("%s: Bearer token does not expire", commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	hmacKey := make([]byte, 32)
+	if _, err = rand.Read(hmacKey); err != nil {
+		log.WithError(err).Error("resource/acme:CreateEabKey() Failed to generate HMAC key")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Failed to create external account binding key")
+		return
+	}
+	eabKey := cms.AcmeEabKey{
+		HmacKey:      base64.RawURLEncoding.EncodeToString(hmacKey),
+		RoleContexts:  roleContexts,
+		RolesExpireAt: rolesExpireAt.UTC(),
+		CreatedAt:     time.Now().UTC(),
+	}
+	if eabKey.KeyID, err = utils.NewAcmeID(); err == nil {
+		err = controller.Store.SaveEabKey(&eabKey)
+	}
+	if err != nil {
+		log.WithError(err).Error("resource/acme:CreateEabKey() Failed to save external account binding key")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Failed to create external account binding key")
+		return
+	}
+
+	responseBytes, err := json.Marshal(eabKey)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:CreateEabKey() Failed to marshal external account binding key")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Failed to marshal response")
+		return
+	}
+	httpWriter.Header().Set("Content-Type", "application/json")
+	httpWriter.WriteHeader(http.StatusCreated)
+	_, err = httpWriter.Write(responseBytes)
+	if err != nil {
+		log.WithError(err).Errorf("resource/acme:CreateEabKey() Failed to write response")
+	}
+	slog.Infof("resource/acme:CreateEabKey() Created ACME external account binding key %s", eabKey.KeyID)
+}
+
+// verifyAcmeRequest verifies the JWS of an ACME POST request and checks that it was sent to the URL it was signed for
+func (controller AcmeController) verifyAcmeRequest(httpWriter http.ResponseWriter, httpRequest *http.Request, newAccount bool) (*utils.AcmeJWS, bool) {
+	if httpRequest.Header.Get("Content-Type") != "application/jose+json" {
+		controller.writeAcmeProblem(httpWriter, http.StatusUnsupportedMediaType, "malformed", "Content type not supported")
+		return nil, false
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(httpWriter, httpRequest.Body, maxAcmeRequestSize))
+	if err != nil {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Cannot read http request body")
+		return nil, false
+	}
+
+	jws, err := controller.Store.ParseAcmeJWS(body, controller.accountKey)
+	if err != nil {
+		slog.WithError(err).Warning(commLogMsg.InvalidInputBadEncoding)
+		switch errors.Cause(err) {
+		case utils.ErrAcmeBadNonce:
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "badNonce", err.Error())
+		case utils.ErrAcmeBadSignature:
+			controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "unauthorized", err.Error())
+		case utils.ErrAcmeResourceNotFound:
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "accountDoesNotExist", "Account does not exist")
+		case utils.ErrAcmeMalformed:
+			controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", err.Error())
+		default:
+			log.WithError(err).Error("resource/acme:verifyAcmeRequest() Failed to verify ACME request")
+			controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to verify request")
+		}
+		return nil, false
+	}
+	if (jws.JWK != nil) != newAccount {
+		controller.writeAcmeProblem(httpWriter, http.StatusBadRequest, "malformed", "Request must be signed with the account key ID")
+		return nil, false
+	}
+	prefix := "/" + strings.ToLower(constants.ServiceName) + constants.ApiVersion + "/"
+	if jws.URL != utils.GetCmsBaseUrl(controller.Config)+strings.TrimPrefix(httpRequest.URL.Path, prefix) {
+		controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "unauthorized", "Request URL does not match the signed URL")
+		return nil, false
+	}
+	return jws, true
+}
+
+// verifyAccountRequest verifies an ACME request signed with the key of a valid account
+func (controller AcmeController) verifyAccountRequest(httpWriter http.ResponseWriter, httpRequest *http.Request) (*utils.AcmeJWS, *utils.AcmeAccountRecord, bool) {
+	jws, ok := controller.verifyAcmeRequest(httpWriter, httpRequest, false)
+	if !ok {
+		return nil, nil, false
+	}
+	account, err := controller.Store.GetAccount(strings.TrimPrefix(jws.KeyID, controller.acmeUrl("account/")))
+	if err != nil {
+		log.WithError(err).Error("resource/acme:verifyAccountRequest() Failed to load ACME account")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to load account")
+		return nil, nil, false
+	}
+	if account.Status != cms.AcmeStatusValid {
+		controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "unauthorized", "Account is "+account.Status)
+		return nil, nil, false
+	}
+	return jws, account, true
+}
+
+// verifyAccountRoles writes an unauthorized problem when the roles of the account are no longer honoured
+func (controller AcmeController) verifyAccountRoles(httpWriter http.ResponseWriter, account *utils.AcmeAccountRecord) bool {
+	if account.RolesValid() {
+		return true
+	}
+	slog.Warningf("%s: Roles of ACME account %s have expired", commLogMsg.UnauthorizedAccess, account.ID)
+	controller.writeAcmeProblem(httpWriter, http.StatusUnauthorized, "unauthorized",
+		"Account roles have expired, create a new account with a new external account binding key")
+	return false
+}
+
+func (controller AcmeController) accountKey(kid string) (*jose.JSONWebKey, error) {
+	if !strings.HasPrefix(kid, controller.acmeUrl("account/")) {
+		return nil, utils.ErrAcmeResourceNotFound
+	}
+	account, err := controller.Store.GetAccount(strings.TrimPrefix(kid, controller.acmeUrl("account/")))
+	if err != nil {
+		return nil, err
+	}
+	var key jose.JSONWebKey
+	if err = json.Unmarshal(account.Key, &key); err != nil {
+		return nil, errors.Wrap(err, "resource/acme:accountKey() Failed to unmarshal account key")
+	}
+	return &key, nil
+}
+
+func (controller AcmeController) getAccountOrder(httpWriter http.ResponseWriter, id string, account *utils.AcmeAccountRecord) (*utils.AcmeOrderRecord, bool) {
+	order, err := controller.Store.GetOrder(id)
+	if err != nil && errors.Cause(err) != utils.ErrAcmeResourceNotFound {
+		log.WithError(err).Error("resource/acme:getAccountOrder() Failed to load ACME order")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to load order")
+		return nil, false
+	}
+	if err != nil || order.AccountID != account.ID {
+		controller.writeAcmeProblem(httpWriter, http.StatusNotFound, "malformed", "Order not found")
+		return nil, false
+	}
+	if order.Status != cms.AcmeStatusValid && time.Now().After(order.Expires) {
+		order.Status = cms.AcmeStatusInvalid
+	}
+	return order, true
+}
+
+func (controller AcmeController) getAccountAuthorization(httpWriter http.ResponseWriter, id string, account *utils.AcmeAccountRecord) (*utils.AcmeAuthorizationRecord, bool) {
+	authz, err := controller.Store.GetAuthorization(id)
+	if err != nil && errors.Cause(err) != utils.ErrAcmeResourceNotFound {
+		log.WithError(err).Error("resource/acme:getAccountAuthorization() Failed to load ACME authorization")
+		controller.writeAcmeProblem(httpWriter, http.StatusInternalServerError, "serverInternal", "Failed to load authorization")
+		return nil, false
+	}
+	if err != nil || authz.AccountID != account.ID {
+		controller.writeAcmeProblem(httpWriter, http.StatusNotFound, "malformed", "Authorization not found")
+		return nil, false
+	}
+	return authz, true
+}
+
+func (controller AcmeController) accountResource(account *utils.AcmeAccountRecord) cms.AcmeAccount {
+	return cms.AcmeAccount{
+		Status:  account.Status,
+		Contact: account.Contact,
+	}
+}
+
+func (controller AcmeController) orderResource(order *utils.AcmeOrderRecord) cms.AcmeOrder {
+	resource := cms.AcmeOrder{
+		Status:      order.Status,
+		Expires:     order.Expires,
+		Identifiers: order.Identifiers,
+		Finalize:    controller.acmeUrl("order/" + order.ID + "/finalize"),
+		Error:       order.Error,
+	}
+	for _, authzID := range order.AuthorizationIDs {
+		resource.Authorizations = append(resource.Authorizations, controller.acmeUrl("authz/"+authzID))
+	}
+	if order.CertificateChain != "" {
+		resource.Certificate = controller.acmeUrl("cert/" + order.ID)
+	}
+	return resource
+}
+
+func (controller AcmeController) authorizationResource(authz *utils.AcmeAuthorizationRecord) cms.AcmeAuthorization {
+	return cms.AcmeAuthorization{
+		Identifier: authz.Identifier,
+		Status:     authz.Status,
+		Expires:    authz.Expires,
+		Challenges: []cms.AcmeChallenge{{
+			Type:      cms.AcmeChallengeHTTP1,
+			URL:       controller.acmeUrl("challenge/" + authz.ID),
+			Status:    authz.Status,
+			Token:     authz.Token,
+			Validated: authz.Validated,
+		}},
+	}
+}
+
+func (controller AcmeController) acmeUrl(path string) string {
+	return utils.GetCmsBaseUrl(controller.Config) + "acme/" + path
+}
+
+func (controller AcmeController) setReplayNonce(httpWriter http.ResponseWriter) {
+	nonce, err := controller.Store.NewNonce()
+	if err != nil {
+		log.WithError(err).Error("resource/acme:setReplayNonce() Failed to create nonce")
+		return
+	}
+	httpWriter.Header().Set("Replay-Nonce", nonce)
+	httpWriter.Header().Add("Link", "<"+controller.acmeUrl("directory")+">;rel=\"index\"")
+}
+
+func (controller AcmeController) writeAcmeResponse(httpWriter http.ResponseWriter, status int, body interface{}, location string) {
+	controller.setReplayNonce(httpWriter)
+	if location != "" {
+		httpWriter.Header().Set("Location", location)
+	}
+	if body == nil {
+		httpWriter.WriteHeader(status)
+		return
+	}
+	responseBytes, err := json.Marshal(body)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:writeAcmeResponse() Failed to marshal response")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	httpWriter.Header().Set("Content-Type", "application/json")
+	httpWriter.WriteHeader(status)
+	if _, err = httpWriter.Write(responseBytes); err != nil {
+		log.WithError(err).Errorf("resource/acme:writeAcmeResponse() Failed to write response")
+	}
+}
+
+func (controller AcmeController) writeAcmeProblem(httpWriter http.ResponseWriter, status int, problemType, detail string) {
+	controller.setReplayNonce(httpWriter)
+	responseBytes, err := json.Marshal(cms.AcmeProblem{Type: acmeErrorPrefix + problemType, Detail: detail, Status: status})
+	if err != nil {
+		log.WithError(err).Error("resource/acme:writeAcmeProblem() Failed to marshal problem")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	httpWriter.Header().Set("Content-Type", "application/problem+json")
+	httpWriter.WriteHeader(status)
+	if _, err = httpWriter.Write(responseBytes); err != nil {
+		log.WithError(err).Errorf("resource/acme:writeAcmeProblem() Failed to write response")
+	}
+}
+
+// csrMatchesIdentifiers checks that the SAN list of the CSR is exactly the set of order identifiers
+func csrMatchesIdentifiers(csr *x509.CertificateRequest, identifiers []cms.AcmeIdentifier) bool {
+	requested := make(map[string]bool)
+	for _, name := range csr.DNSNames {
+		requested[cms.AcmeIdentifierDNS+":"+strings.ToLower(name)] = true
+	}
+	for _, ip := range csr.IPAddresses {
+		requested[cms.AcmeIdentifierIP+":"+ip.String()] = true
+	}
+	ordered := make(map[string]bool)
+	for _, identifier := range identifiers {
+		value := strings.ToLower(identifier.Value)
+		if identifier.Type == cms.AcmeIdentifierIP {
+			value = net.ParseIP(identifier.Value).String()
+		}
+		ordered[identifier.Type+":"+value] = true
+	}
+	if len(requested) != len(ordered) {
+		return false
+	}
+	for key := range ordered {
+		if !requested[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// issueAcmeCertificate signs a TLS certificate for the CSR with the TLS CA and returns the PEM encoded chain of the
+// certificate and the issuing CA
+func issueAcmeCertificate(cfg *config.Configuration, csr *x509.CertificateRequest) ([]byte, error) {
+	serialNumber, err := utils.GetNextSerialNumber()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read next Serial Number")
+	}
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName: csr.Subject.CommonName,
+		},
+		DNSNames:    csr.DNSNames,
+		IPAddresses: csr.IPAddresses,
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().AddDate(1, 0, 0),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	utils.SetRevocationExtensions(&template, utils.GetCmsBaseUrl(cfg), constants.Tls)
+
+	caAttr := constants.GetCaAttribs(constants.Tls)
+	caCert, caPrivKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load Issuing CA")
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, caCert, csr.PublicKey, caPrivKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create certificate from CSR")
+	}
+	issuedCert, err := x509.ParseCertificate(certificate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot parse issued certificate")
+	}
+	if err = utils.SaveIssuedCertificate(issuedCert, constants.Tls); err != nil {
+		return nil, err
+	}
+
+	var chain bytes.Buffer
+	if err = pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: certificate}); err != nil {
+		return nil, errors.Wrap(err, "Cannot encode issued certificate")
+	}
+	if err = pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}); err != nil {
+		return nil, errors.Wrap(err, "Cannot encode Issuing CA")
+	}
+	return chain.Bytes(), nil
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/acme"
+)
+
+func createTestTlsCA(t *testing.T) *x509.Certificate {
+	assertions := assert.New(t)
+	assertions.NoError(os.MkdirAll(constants.IntermediateCADirPath, os.ModePerm))
+	if _, err := os.Stat(constants.SerialNumberPath); os.IsNotExist(err) {
+		assertions.NoError(utils.WriteSerialNumber(big.NewInt(1)))
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assertions.NoError(err)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: constants.GetCaAttribs(constants.Tls).CommonName},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, &template, &template, &caKey.PublicKey, caKey)
+	assertions.NoError(err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(caKey)
+	assertions.NoError(err)
+	caAttr := constants.GetCaAttribs(constants.Tls)
+	assertions.NoError(crypt.SavePrivateKeyAsPKCS8(keyDer, caAttr.KeyPath))
+	assertions.NoError(crypt.SavePemCert(caDer, caAttr.CertPath))
+	caCert, err := x509.ParseCertificate(caDer)
+	assertions.NoError(err)
+	return caCert
+}
+
+func TestAcmeCertificateIssuance(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	caCert := createTestTlsCA(t)
+	dir, err := ioutil.TempDir("", "acme")
+	assertions.NoError(err)
+	defer os.RemoveAll(dir)
+
+	store := utils.NewAcmeStore(dir)
+	hmacKey := []byte("0123456789abcdef0123456789abcdef")
+	assertions.NoError(store.SaveEabKey(&cms.AcmeEabKey{
+		KeyID:         "test-eab-key",
+		HmacKey:       base64.RawURLEncoding.EncodeToString(hmacKey),
+		RoleContexts:  []string{"CN=WLS TLS Certificate;SAN=wls.example.com;certType=TLS"},
+		RolesExpireAt: time.Now().Add(time.Hour),
+	}))
+	assertions.NoError(store.SaveEabKey(&cms.AcmeEabKey{
+		KeyID:         "expired-eab-key",
+		HmacKey:       base64.RawURLEncoding.EncodeToString(hmacKey),
+		RoleContexts:  []string{"CN=WLS TLS Certificate;SAN=wls.example.com;certType=TLS"},
+		RolesExpireAt: time.Now().Add(-time.Minute),
+	}))
+
+	acmeController := AcmeController{Config: &config.Configuration{}, Store: store}
+	router := mux.NewRouter()
+	subRouter := router.PathPrefix("/cms/v1").Subrouter()
+	subRouter.HandleFunc("/acme/directory", acmeController.GetDirectory).Methods("GET")
+	subRouter.HandleFunc("/acme/new-nonce", acmeController.NewNonce).Methods("HEAD", "GET")
+	subRouter.HandleFunc("/acme/new-account", acmeController.NewAccount).Methods("POST")
+	subRouter.HandleFunc("/acme/new-order", acmeController.NewOrder).Methods("POST")
+	subRouter.HandleFunc("/acme/order/{id}", acmeController.GetOrder).Methods("POST")
+	subRouter.HandleFunc("/acme/order/{id}/finalize", acmeController.FinalizeOrder).Methods("POST")
+	subRouter.HandleFunc("/acme/authz/{id}", acmeController.GetAuthorization).Methods("POST")
+	subRouter.HandleFunc("/acme/cert/{id}", acmeController.GetCertificate).Methods("POST")
+	server := httptest.NewServer(router)
+	defer server.Close()
+	acmeController.Config.CmsBaseUrl = server.URL + "/cms/v1"
+
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assertions.NoError(err)
+	client := &acme.Client{Key: accountKey, DirectoryURL: server.URL + "/cms/v1/acme/directory"}
+
+	// accounts can only be created with an external account binding key
+	_, err = client.Register(ctx, &acme.Account{}, acme.AcceptTOS)
+	assertions.Error(err)
+	_, err = client.Register(ctx, &acme.Account{
+		ExternalAccountBinding: &acme.ExternalAccountBinding{KID: "test-eab-key", Key: []byte("wrong key")},
+	}, acme.AcceptTOS)
+	assertions.Error(err)
+	account, err := client.Register(ctx, &acme.Account{
+		ExternalAccountBinding: &acme.ExternalAccountBinding{KID: "test-eab-key", Key: hmacKey},
+	}, acme.AcceptTOS)
+	assertions.NoError(err)
+	assertions.Equal(acme.StatusValid, account.Status)
+
+	// a second account cannot reuse the bound key
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assertions.NoError(err)
+	otherClient := &acme.Client{Key: otherKey, DirectoryURL: client.DirectoryURL}
+	_, err = otherClient.Register(ctx, &acme.Account{
+		ExternalAccountBinding: &acme.ExternalAccountBinding{KID: "test-eab-key", Key: hmacKey},
+	}, acme.AcceptTOS)
+	assertions.Error(err)
+	// nor use a key whose roles have expired
+	_, err = otherClient.Register(ctx, &acme.Account{
+		ExternalAccountBinding: &acme.ExternalAccountBinding{KID: "expired-eab-key", Key: hmacKey},
+	}, acme.AcceptTOS)
+	assertions.Error(err)
+
+	_, err = client.AuthorizeOrder(ctx, acme.DomainIDs("hvs.example.com"))
+	assertions.Error(err)
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs("wls.example.com"))
+	assertions.NoError(err)
+	assertions.Equal(acme.StatusReady, order.Status)
+	assertions.Len(order.AuthzURLs, 1)
+	authz, err := client.GetAuthorization(ctx, order.AuthzURLs[0])
+	assertions.NoError(err)
+	assertions.Equal(acme.StatusValid, authz.Status)
+
+	certKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assertions.NoError(err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: "WLS TLS Certificate"},
+		DNSNames:           []string{"wls.example.com"},
+		SignatureAlgorithm: x509.SHA384WithRSA,
+	}, certKey)
+	assertions.NoError(err)
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	assertions.NoError(err)
+	assertions.Len(chain, 2)
+
+	cert, err := x509.ParseCertificate(chain[0])
+	assertions.NoError(err)
+	assertions.NoError(cert.CheckSignatureFrom(caCert))
+	assertions.Equal([]string{"wls.example.com"}, cert.DNSNames)
+	assertions.Equal([]string{acmeController.Config.CmsBaseUrl + "/crl/" + constants.Tls}, cert.CRLDistributionPoints)
+
+	// the order cannot be finalized twice
+	_, _, err = client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	assertions.Error(err)
+
+	// the roles of the account are no longer honoured once the token that granted them has expired
+	order, err = client.AuthorizeOrder(ctx, acme.DomainIDs("wls.example.com"))
+	assertions.NoError(err)
+	accountRecord, err := store.GetAccount(strings.TrimPrefix(account.URI, acmeController.Config.CmsBaseUrl+"/acme/account/"))
+	assertions.NoError(err)
+	accountRecord.RolesExpireAt = time.Now().Add(-time.Minute)
+	assertions.NoError(store.SaveAccount(accountRecord))
+	_, _, err = client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	assertions.Error(err)
+	_, err = client.AuthorizeOrder(ctx, acme.DomainIDs("wls.example.com"))
+	assertions.Error(err)
+}
+
+func TestAcmeStoreNonces(t *testing.T) {
+	assertions := assert.New(t)
+	store := utils.NewAcmeStore("")
+
+	var nonces []string
+	for i := 0; i < 10001; i++ {
+		nonce, err := store.NewNonce()
+		assertions.NoError(err)
+		nonces = append(nonces, nonce)
+	}
+	// the number of nonces kept is bounded, the oldest ones are dropped first
+	assertions.False(store.ConsumeNonce(nonces[0]))
+	assertions.True(store.ConsumeNonce(nonces[len(nonces)-1]))
+	assertions.False(store.ConsumeNonce(nonces[len(nonces)-1]))
+}
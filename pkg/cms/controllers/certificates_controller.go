@@ -212,6 +212,7 @@ func (controller CertificatesController) GetCertificates(httpWriter http.Respons
 		}
 		return
 	}
+	utils.SetRevocationExtensions(&clientCRTTemplate, utils.GetCmsBaseUrl(controller.Config), issuingCa)
 	caAttr := constants.GetCaAttribs(issuingCa)
 
 	caCert, caPrivKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
@@ -234,6 +235,20 @@ func (controller CertificatesController) GetCertificates(httpWriter http.Respons
 		}
 	}
 
+	issuedCert, err := x509.ParseCertificate(certificate)
+	if err == nil {
+		err = utils.SaveIssuedCertificate(issuedCert, issuingCa)
+	}
+	if err != nil {
+		log.WithError(err).Error("resource/certificates:GetCertificates() Failed to record issued certificate")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		_, err = httpWriter.Write([]byte("Cannot record issued certificate"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+		}
+		return
+	}
+
 	httpWriter.Header().Add("Content-Type", "application/x-pem-file")
 	httpWriter.WriteHeader(http.StatusOK)
 	// encode the certificate first
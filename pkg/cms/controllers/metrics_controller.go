@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/auth"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/context"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
+	ct "github.com/intel-secl/intel-secl/v4/pkg/model/aas"
+)
+
+type MetricsController struct {
+}
+
+//GetMetrics writes the prometheus metrics of CMS for callers holding the metrics permission
+func (controller MetricsController) GetMetrics(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/metrics:GetMetrics() Entering")
+	defer log.Trace("resource/metrics:GetMetrics() Leaving")
+
+	privileges, err := context.GetUserPermissions(httpRequest)
+	if err != nil {
+		slog.WithError(err).Warn("resource/metrics:GetMetrics() Failed to read permissions")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Could not get user permissions from http context")
+		return
+	}
+	_, foundPermission := auth.ValidatePermissionAndGetPermissionsContext(privileges,
+		ct.PermissionInfo{Service: constants.ServiceName, Rules: []string{constants.MetricsRetrievePermission}},
+		true)
+	if !foundPermission {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	metrics.Handler().ServeHTTP(httpWriter, httpRequest)
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/auth"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/context"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	ct "github.com/intel-secl/intel-secl/v4/pkg/model/aas"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxOCSPRequestBytes bounds the size of OCSP requests read from the request body
+const maxOCSPRequestBytes = 1 << 16
+
+type RevocationController struct {
+}
+
+//RevokeCertificate is used to revoke a certificate issued by one of the CMS CAs
+func (controller RevocationController) RevokeCertificate(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/revocation:RevokeCertificate() Entering")
+	defer log.Trace("resource/revocation:RevokeCertificate() Leaving")
+
+	privileges, err := context.GetUserRoles(httpRequest)
+	if err != nil {
+		slog.WithError(err).Warn("resource/revocation:RevokeCertificate() Failed to read roles and permissions")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Could not get user roles from http context")
+		return
+	}
+	_, foundRole := auth.ValidatePermissionAndGetRoleContext(privileges,
+		[]ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertRevokerGroupName}},
+		false)
+	if !foundRole {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	serialNumber, ok := utils.ParseSerialNumber(mux.Vars(httpRequest)["serialNumber"])
+	if !ok {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.Error("resource/revocation:RevokeCertificate() Invalid serial number provided")
+		writeResponse(httpWriter, http.StatusBadRequest, "Invalid serial number provided")
+		return
+	}
+
+	var revocationRequest cms.RevocationRequest
+	if httpRequest.ContentLength != 0 {
+		dec := json.NewDecoder(httpRequest.Body)
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(&revocationRequest); err != nil {
+			slog.WithError(err).Warning(commLogMsg.InvalidInputBadEncoding)
+			writeResponse(httpWriter, http.StatusBadRequest, "Unable to decode JSON request body")
+			return
+		}
+	}
+
+	record, err := utils.RevokeCertificate(serialNumber, revocationRequest.Reason)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:RevokeCertificate() Failed to revoke certificate with serial number %s", serialNumber.Text(16))
+		switch errors.Cause(err) {
+		case utils.ErrInvalidRevocationReason:
+			writeResponse(httpWriter, http.StatusBadRequest, err.Error())
+		case utils.ErrCertificateNotFound:
+			writeResponse(httpWriter, http.StatusNotFound, err.Error())
+		case utils.ErrCertificateRevoked:
+			writeResponse(httpWriter, http.StatusConflict, err.Error())
+		default:
+			writeResponse(httpWriter, http.StatusInternalServerError, "Failed to revoke certificate")
+		}
+		return
+	}
+
+	responseBytes, err := json.Marshal(record)
+	if err != nil {
+		log.WithError(err).Error("resource/revocation:RevokeCertificate() Failed to marshal revoked certificate record")
+		writeResponse(httpWriter, http.StatusInternalServerError, "Failed to marshal response")
+		return
+	}
+	httpWriter.Header().Set("Content-Type", "application/json")
+	httpWriter.WriteHeader(http.StatusOK)
+	_, err = httpWriter.Write(responseBytes)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:RevokeCertificate() Failed to write response")
+	}
+	slog.Infof("resource/revocation:RevokeCertificate() Revoked certificate with serial number %s, subject %s, reason %s",
+		record.SerialNumber, record.Subject, record.RevocationReason)
+}
+
+//GetCRL is used to get the certificate revocation list of an issuing CA
+func (controller RevocationController) GetCRL(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/revocation:GetCRL() Entering")
+	defer log.Trace("resource/revocation:GetCRL() Leaving")
+
+	issuingCa := mux.Vars(httpRequest)["issuingCa"]
+	crl, err := utils.CreateCRL(issuingCa)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:GetCRL() Failed to create CRL for %v CA", issuingCa)
+		if errors.Cause(err) == utils.ErrIssuingCaNotFound {
+			slog.Warning(commLogMsg.InvalidInputBadParam)
+			writeResponse(httpWriter, http.StatusNotFound, "Invalid issuingCa provided")
+		} else {
+			writeResponse(httpWriter, http.StatusInternalServerError, "Cannot create CRL")
+		}
+		return
+	}
+
+	httpWriter.Header().Set("Content-Type", "application/pkix-crl")
+	httpWriter.WriteHeader(http.StatusOK)
+	_, err = httpWriter.Write(crl)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:GetCRL() Failed to write response")
+	}
+}
+
+//GetOCSPResponse is used to get the revocation status of a certificate for an OCSP request sent in the request
+// body (POST) or base64 encoded in the path (GET) as described in RFC 6960 Appendix A
+func (controller RevocationController) GetOCSPResponse(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/revocation:GetOCSPResponse() Entering")
+	defer log.Trace("resource/revocation:GetOCSPResponse() Leaving")
+
+	var request []byte
+	var err error
+	if httpRequest.Method == http.MethodGet {
+		var encoded string
+		encoded, err = url.PathUnescape(mux.Vars(httpRequest)["request"])
+		if err == nil {
+			request, err = base64.StdEncoding.DecodeString(encoded)
+		}
+	} else if httpRequest.Header.Get("Content-Type") != "application/ocsp-request" {
+		writeResponse(httpWriter, http.StatusUnsupportedMediaType, "Content type not supported")
+		return
+	} else {
+		request, err = ioutil.ReadAll(http.MaxBytesReader(httpWriter, httpRequest.Body, maxOCSPRequestBytes))
+	}
+
+	var response []byte
+	if err != nil {
+		slog.WithError(err).Warning(commLogMsg.InvalidInputBadEncoding)
+		response = ocsp.MalformedRequestErrorResponse
+	} else if response, err = utils.CreateOCSPResponse(request); err != nil {
+		log.WithError(err).Error("resource/revocation:GetOCSPResponse() Failed to create OCSP response")
+		if errors.Cause(err) == utils.ErrOCSPIssuerNotRecognized {
+			response = ocsp.UnauthorizedErrorResponse
+		} else if _, ok := errors.Cause(err).(ocsp.ParseError); ok {
+			response = ocsp.MalformedRequestErrorResponse
+		} else {
+			response = ocsp.InternalErrorErrorResponse
+		}
+	}
+
+	httpWriter.Header().Set("Content-Type", "application/ocsp-response")
+	httpWriter.WriteHeader(http.StatusOK)
+	_, err = httpWriter.Write(response)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:GetOCSPResponse() Failed to write response")
+	}
+}
+
+func writeResponse(httpWriter http.ResponseWriter, status int, message string) {
+	httpWriter.WriteHeader(status)
+	_, err := httpWriter.Write([]byte(message))
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:writeResponse() Failed to write response")
+	}
+}
@@ -49,7 +49,8 @@ func init() {
 func defaultConfig() *config.Configuration {
 	loadAlias()
 	return &config.Configuration{
-		AASApiUrl: viper.GetString("aas-base-url"),
+		AASApiUrl:  viper.GetString("aas-base-url"),
+		CmsBaseUrl: viper.GetString("cms-base-url"),
 		Log: commConfig.LogConfig{
 			MaxLength:    viper.GetInt("log-max-length"),
 			EnableStdout: viper.GetBool("log-enable-stdout"),
@@ -80,6 +81,7 @@ func loadAlias() {
 		"server-max-header-bytes":    "CMS_SERVER_MAX_HEADER_BYTES",
 		"log-enable-stdout":          "CMS_ENABLE_CONSOLE_LOG",
 		"aas-base-url":               "AAS_API_URL",
+		"cms-base-url":               "CMS_BASE_URL",
 	}
 	for k, v := range alias {
 		if env := os.Getenv(v); env != "" {
@@ -0,0 +1,40 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/controllers"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetAcmeRoutes is used to set the endpoints for the ACME server. ACME requests are authenticated by the JWS
+// signature of the account key and do not need a bearer token.
+func SetAcmeRoutes(router *mux.Router, acmeController controllers.AcmeController) *mux.Router {
+	log.Trace("router/acme:SetAcmeRoutes() Entering")
+	defer log.Trace("router/acme:SetAcmeRoutes() Leaving")
+
+	router.HandleFunc("/acme/directory", acmeController.GetDirectory).Methods("GET")
+	router.HandleFunc("/acme/new-nonce", acmeController.NewNonce).Methods("HEAD", "GET")
+	router.HandleFunc("/acme/new-account", acmeController.NewAccount).Methods("POST")
+	router.HandleFunc("/acme/account/{id}", acmeController.UpdateAccount).Methods("POST")
+	router.HandleFunc("/acme/new-order", acmeController.NewOrder).Methods("POST")
+	router.HandleFunc("/acme/order/{id}", acmeController.GetOrder).Methods("POST")
+	router.HandleFunc("/acme/order/{id}/finalize", acmeController.FinalizeOrder).Methods("POST")
+	router.HandleFunc("/acme/authz/{id}", acmeController.GetAuthorization).Methods("POST")
+	router.HandleFunc("/acme/challenge/{id}", acmeController.GetChallenge).Methods("POST")
+	router.HandleFunc("/acme/cert/{id}", acmeController.GetCertificate).Methods("POST")
+	return router
+}
+
+// SetAcmeEabKeyRoutes is used to set the endpoint for creating ACME external account binding keys
+func SetAcmeEabKeyRoutes(router *mux.Router, acmeController controllers.AcmeController) *mux.Router {
+	log.Trace("router/acme:SetAcmeEabKeyRoutes() Entering")
+	defer log.Trace("router/acme:SetAcmeEabKeyRoutes() Leaving")
+
+	router.HandleFunc("/acme/eab-keys", acmeController.CreateEabKey).Methods("POST")
+	return router
+}
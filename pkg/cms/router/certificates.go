@@ -19,5 +19,7 @@ func SetCertificatesRoutes(router *mux.Router, config *config.Configuration) *mu
 
 	certController := controllers.CertificatesController{Config: config}
 	router.HandleFunc("/certificates", certController.GetCertificates).Methods("POST")
+	revocationController := controllers.RevocationController{}
+	router.HandleFunc("/certificates/{serialNumber}/revoke", revocationController.RevokeCertificate).Methods("POST")
 	return router
 }
@@ -0,0 +1,21 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/controllers"
+)
+
+// SetMetricsRoutes is used to set the endpoint exposing the prometheus metrics
+func SetMetricsRoutes(router *mux.Router) *mux.Router {
+	defaultLog.Trace("router/metrics:SetMetricsRoutes() Entering")
+	defer defaultLog.Trace("router/metrics:SetMetricsRoutes() Leaving")
+
+	metricsController := controllers.MetricsController{}
+	router.HandleFunc("/metrics", metricsController.GetMetrics).Methods("GET")
+	return router
+}
@@ -0,0 +1,24 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/controllers"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetRevocationRoutes is used to set the public endpoints for CRL and OCSP APIs
+func SetRevocationRoutes(router *mux.Router) *mux.Router {
+	log.Trace("router/revocation:SetRevocationRoutes() Entering")
+	defer log.Trace("router/revocation:SetRevocationRoutes() Leaving")
+
+	revocationController := controllers.RevocationController{}
+	router.HandleFunc("/crl/{issuingCa}", revocationController.GetCRL).Methods("GET")
+	router.HandleFunc("/ocsp", revocationController.GetOCSPResponse).Methods("POST")
+	router.HandleFunc("/ocsp/{request:.+}", revocationController.GetOCSPResponse).Methods("GET")
+	return router
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v4/pkg/lib/common/os"
 	"github.com/pkg/errors"
@@ -36,6 +39,7 @@ func InitRoutes(cfg *config.Configuration) *mux.Router {
 	router := mux.NewRouter()
 
 	router.SkipClean(true)
+	router.Use(metrics.NewRequestMetrics(constants.ServiceName))
 	defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg)
 	return router
 }
@@ -45,15 +49,22 @@ func defineSubRoutes(router *mux.Router, service string, cfg *config.Configurati
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
 	serviceApi := "/" + service + constants.ApiVersion
+	acmeController := controllers.AcmeController{Config: cfg, Store: utils.NewAcmeStore(constants.AcmeDirPath)}
+	// the expired nonces are dropped for the lifetime of the service
+	go acmeController.Store.PruneNonces(constants.DefaultAcmeNoncePruneInterval, nil)
 	subRouter := router.PathPrefix(serviceApi).Subrouter()
 	subRouter = SetVersionRoutes(subRouter)
 	subRouter = SetCACertificatesRoutes(subRouter)
+	subRouter = SetRevocationRoutes(subRouter)
+	subRouter = SetAcmeRoutes(subRouter, acmeController)
 
 	subRouter = router.PathPrefix(serviceApi).Subrouter()
 	cfgRouter := Router{cfg: cfg}
 	subRouter.Use(middleware.NewTokenAuth(constants.TrustedJWTSigningCertsDir, constants.ConfigDir, cfgRouter.fnGetJwtCerts,
 		time.Minute*constants.DefaultJwtValidateCacheKeyMins))
 	subRouter = SetCertificatesRoutes(subRouter, cfg)
+	subRouter = SetAcmeEabKeyRoutes(subRouter, acmeController)
+	subRouter = SetMetricsRoutes(subRouter)
 }
 
 // Fetch JWT certificate from AAS
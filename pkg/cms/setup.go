@@ -13,6 +13,7 @@ import (
 
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/tasks"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
 	cos "github.com/intel-secl/intel-secl/v4/pkg/lib/common/os"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/setup"
 	"github.com/pkg/errors"
@@ -134,6 +135,7 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 	runner.AddTask("intermediate-ca", "", &tasks.IntermediateCa{
 		ConsoleWriter: a.consoleWriter(),
 		Config:        &a.Config.CACert,
+		CmsBaseUrl:    utils.GetCmsBaseUrl(a.Config),
 	})
 	runner.AddTask("tls", "", &tasks.TLS{
 		ConsoleWriter:    a.consoleWriter(),
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/setup"
 	"github.com/pkg/errors"
@@ -24,11 +25,13 @@ const intermediateCAEnvHelpPrompt = "Following environment variables are require
 type IntermediateCa struct {
 	ConsoleWriter io.Writer
 	Config        *config.CACertConfig
-	envPrefix     string
-	commandName   string
+	// CmsBaseUrl is published in the CRL distribution point and AIA extensions of the intermediate CA certificates
+	CmsBaseUrl  string
+	envPrefix   string
+	commandName string
 }
 
-func createIntermediateCACert(cfg *config.CACertConfig, cn string) (privKey crypto.PrivateKey, cert []byte, err error) {
+func createIntermediateCACert(cfg *config.CACertConfig, cn string, cmsBaseUrl string) (privKey crypto.PrivateKey, cert []byte, err error) {
 	log.Trace("tasks/intermediate_ca:createIntermediateCACert() Entering")
 	defer log.Trace("tasks/intermediate_ca:createIntermediateCACert() Leaving")
 
@@ -42,6 +45,9 @@ func createIntermediateCACert(cfg *config.CACertConfig, cn string) (privKey cryp
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "tasks/intermediate_ca:createIntermediateCACert() Could not generate Certificate Template")
 	}
+	if cmsBaseUrl != "" {
+		utils.SetRevocationExtensions(&caCertTemplate, cmsBaseUrl, constants.Root)
+	}
 
 	rootCert, rootCAPrivKey, err := crypt.LoadX509CertAndPrivateKey(rCaAttr.CertPath, rCaAttr.KeyPath)
 	if err != nil {
@@ -52,6 +58,15 @@ func createIntermediateCACert(cfg *config.CACertConfig, cn string) (privKey cryp
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "tasks/intermediate_ca:createIntermediateCACert() Could not create certificate")
 	}
+
+	caCert, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "tasks/intermediate_ca:createIntermediateCACert() Could not parse certificate")
+	}
+	err = utils.SaveIssuedCertificate(caCert, constants.Root)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "tasks/intermediate_ca:createIntermediateCACert() Could not record certificate")
+	}
 	return
 }
 
@@ -82,7 +97,7 @@ func (ca IntermediateCa) Run() error {
 	for _, interCa := range cas {
 		fmt.Fprintln(ca.ConsoleWriter, "Creating intermediate CA ", interCa)
 		caAttr := constants.GetCaAttribs(interCa)
-		privKey, cert, err := createIntermediateCACert(ca.Config, caAttr.CommonName)
+		privKey, cert, err := createIntermediateCACert(ca.Config, caAttr.CommonName, ca.CmsBaseUrl)
 		if err != nil {
 			return errors.Wrap(err, "tasks/intermediate_ca:Run() Could not create intemediate CA")
 		}
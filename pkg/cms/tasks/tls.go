@@ -131,6 +131,14 @@ func (ts TLS) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "tasks/tls:Run() Could not create TLS certificate")
 	}
+	tlsCert, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return errors.Wrap(err, "tasks/tls:Run() Could not parse TLS certificate")
+	}
+	err = utils.SaveIssuedCertificate(tlsCert, constants.Tls)
+	if err != nil {
+		return errors.Wrap(err, "tasks/tls:Run() Could not record TLS certificate")
+	}
 	err = crypt.SavePrivateKeyAsPKCS8(key, constants.TLSKeyPath)
 	if err != nil {
 		return errors.Wrap(err, "tasks/tls:Run() Could not save TLS private key")
@@ -8,6 +8,9 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -181,8 +184,17 @@ func TestTlsSetupTaskRun(t *testing.T) {
 	interCA := IntermediateCa{
 		ConsoleWriter: os.Stdout,
 		Config:        &c.CACert,
+		CmsBaseUrl:    "https://127.0.0.1:8445/cms/v1/",
 	}
 	err = interCA.Run()
+	assertions.NoError(err)
+
+	tlsCaCert, err := crypt.GetCertFromPemFile(constants.GetCaAttribs(constants.Tls).CertPath)
+	assertions.NoError(err)
+	assertions.Equal([]string{"https://127.0.0.1:8445/cms/v1/crl/" + constants.Root}, tlsCaCert.CRLDistributionPoints)
+	record, err := utils.GetIssuedCertificate(tlsCaCert.SerialNumber)
+	assertions.NoError(err)
+	assertions.Equal(constants.Root, record.IssuingCa)
 
 	ts := TLS{
 		ConsoleWriter:    os.Stdout,
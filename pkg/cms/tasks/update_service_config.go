@@ -30,6 +30,7 @@ var envHelp = map[string]string{
 	"LOG_MAX_LENGTH":             "Max length of log statement",
 	"LOG_ENABLE_STDOUT":          "Enable console log",
 	"AAS_BASE_URL":               "AAS Base URL",
+	"CMS_BASE_URL":               "CMS Base URL published in the CRL distribution point and AIA extensions of issued certificates",
 	"TOKEN_DURATION_MINS":        "Validity of token duration",
 	"SERVER_PORT":                "The Port on which Server Listens to",
 	"SERVER_READ_TIMEOUT":        "Request Read Timeout Duration in Seconds",
@@ -50,6 +51,7 @@ func (uc UpdateServiceConfig) Run() error {
 	}
 
 	(*uc.AppConfig).AASApiUrl = viper.GetString("aas-base-url")
+	(*uc.AppConfig).CmsBaseUrl = viper.GetString("cms-base-url")
 
 	(*uc.AppConfig).TokenDurationMins = viper.GetInt("token-duration-mins")
 	if uc.ServerConfig.Port < 1024 ||
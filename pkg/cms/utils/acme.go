@@ -0,0 +1,360 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	acmeNonceValidity  = time.Hour
+	acmeAccounts       = "accounts"
+	acmeOrders         = "orders"
+	acmeAuthorizations = "authorizations"
+	acmeEabKeys        = "eab-keys"
+)
+
+// acmeMaxNonces bounds the nonces kept in memory, the oldest nonce is dropped when a new one is issued beyond it
+const acmeMaxNonces = 10000
+
+var (
+	ErrAcmeResourceNotFound = errors.New("ACME resource not found")
+	ErrAcmeMalformed        = errors.New("Malformed ACME request")
+	ErrAcmeBadSignature     = errors.New("ACME request signature could not be verified")
+	ErrAcmeBadNonce         = errors.New("Invalid or reused ACME nonce")
+	ErrAcmeEabInvalid       = errors.New("Invalid external account binding")
+)
+
+// AcmeAccountRecord - Persisted ACME account along with the key that signs its requests
+type AcmeAccountRecord struct {
+	ID            string          `json:"id"`
+	Status        string          `json:"status"`
+	Contact       []string        `json:"contact,omitempty"`
+	Key           json.RawMessage `json:"key"`
+	KeyThumbprint string          `json:"key_thumbprint"`
+	EabKeyID      string          `json:"eab_key_id"`
+	RoleContexts  []string        `json:"role_contexts"`
+	RolesExpireAt time.Time       `json:"roles_expire_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// RolesValid returns true while the role contexts copied from the external account binding key are honoured
+func (account *AcmeAccountRecord) RolesValid() bool {
+	return time.Now().Before(account.RolesExpireAt)
+}
+
+// AcmeOrderRecord - Persisted ACME order
+type AcmeOrderRecord struct {
+	ID               string               `json:"id"`
+	AccountID        string               `json:"account_id"`
+	Status           string               `json:"status"`
+	Expires          time.Time            `json:"expires"`
+	Identifiers      []cms.AcmeIdentifier `json:"identifiers"`
+	AuthorizationIDs []string             `json:"authorization_ids"`
+	CertificateChain string               `json:"certificate_chain,omitempty"`
+	Error            *cms.AcmeProblem     `json:"error,omitempty"`
+}
+
+// AcmeAuthorizationRecord - Persisted ACME authorization
+type AcmeAuthorizationRecord struct {
+	ID         string             `json:"id"`
+	AccountID  string             `json:"account_id"`
+	Identifier cms.AcmeIdentifier `json:"identifier"`
+	Status     string             `json:"status"`
+	Expires    time.Time          `json:"expires"`
+	Token      string             `json:"token"`
+	Validated  *time.Time         `json:"validated,omitempty"`
+}
+
+// AcmeJWS - Verified content of the JWS that wraps every ACME POST request
+type AcmeJWS struct {
+	Payload []byte
+	URL     string
+	KeyID   string
+	JWK     *jose.JSONWebKey
+}
+
+// AcmeStore persists ACME accounts, orders, authorizations and external account binding keys as JSON files and
+// keeps the issued anti-replay nonces in memory
+type AcmeStore struct {
+	dir    string
+	mutex  sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewAcmeStore(dir string) *AcmeStore {
+	return &AcmeStore{
+		dir:    dir,
+		nonces: make(map[string]time.Time),
+	}
+}
+
+// NewAcmeID returns a random URL safe identifier for ACME resources, nonces and tokens
+func NewAcmeID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "utils/acme:NewAcmeID() Failed to read random bytes")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewNonce issues a nonce that can be used once in the protected header of an ACME request
+func (s *AcmeStore) NewNonce() (string, error) {
+	nonce, err := NewAcmeID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.nonces) >= acmeMaxNonces {
+		s.pruneNonces(now)
+	}
+	if len(s.nonces) >= acmeMaxNonces {
+		// the nonces are issued with the same validity, the oldest one expires first
+		var oldest string
+		for n, expiry := range s.nonces {
+			if oldest == "" || expiry.Before(s.nonces[oldest]) {
+				oldest = n
+			}
+		}
+		delete(s.nonces, oldest)
+	}
+	s.nonces[nonce] = now.Add(acmeNonceValidity)
+	return nonce, nil
+}
+
+// PruneNonces drops the expired nonces every interval until stop is closed
+func (s *AcmeStore) PruneNonces(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s.mutex.Lock()
+			s.pruneNonces(now)
+			s.mutex.Unlock()
+		}
+	}
+}
+
+// pruneNonces drops the nonces expired at now, the caller must hold the mutex
+func (s *AcmeStore) pruneNonces(now time.Time) {
+	for n, expiry := range s.nonces {
+		if now.After(expiry) {
+			delete(s.nonces, n)
+		}
+	}
+}
+
+// ConsumeNonce returns true when nonce was issued by the store and has neither expired nor been used before
+func (s *AcmeStore) ConsumeNonce(nonce string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	expiry, ok := s.nonces[nonce]
+	delete(s.nonces, nonce)
+	return ok && time.Now().Before(expiry)
+}
+
+func (s *AcmeStore) SaveEabKey(key *cms.AcmeEabKey) error {
+	return s.save(acmeEabKeys, key.KeyID, key)
+}
+
+func (s *AcmeStore) GetEabKey(keyID string) (*cms.AcmeEabKey, error) {
+	var key cms.AcmeEabKey
+	return &key, s.load(acmeEabKeys, keyID, &key)
+}
+
+func (s *AcmeStore) SaveAccount(account *AcmeAccountRecord) error {
+	return s.save(acmeAccounts, account.ID, account)
+}
+
+func (s *AcmeStore) GetAccount(id string) (*AcmeAccountRecord, error) {
+	var account AcmeAccountRecord
+	return &account, s.load(acmeAccounts, id, &account)
+}
+
+// GetAccountByThumbprint looks up the account registered with the key having the given JWK thumbprint
+func (s *AcmeStore) GetAccountByThumbprint(thumbprint string) (*AcmeAccountRecord, error) {
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, acmeAccounts))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrAcmeResourceNotFound
+		}
+		return nil, errors.Wrap(err, "utils/acme:GetAccountByThumbprint() Failed to read ACME accounts directory")
+	}
+	for _, file := range files {
+		account, err := s.GetAccount(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if account.KeyThumbprint == thumbprint {
+			return account, nil
+		}
+	}
+	return nil, ErrAcmeResourceNotFound
+}
+
+func (s *AcmeStore) SaveOrder(order *AcmeOrderRecord) error {
+	return s.save(acmeOrders, order.ID, order)
+}
+
+func (s *AcmeStore) GetOrder(id string) (*AcmeOrderRecord, error) {
+	var order AcmeOrderRecord
+	return &order, s.load(acmeOrders, id, &order)
+}
+
+func (s *AcmeStore) SaveAuthorization(authz *AcmeAuthorizationRecord) error {
+	return s.save(acmeAuthorizations, authz.ID, authz)
+}
+
+func (s *AcmeStore) GetAuthorization(id string) (*AcmeAuthorizationRecord, error) {
+	var authz AcmeAuthorizationRecord
+	return &authz, s.load(acmeAuthorizations, id, &authz)
+}
+
+func (s *AcmeStore) save(kind, id string, v interface{}) error {
+	dir := filepath.Join(s.dir, kind)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrapf(err, "utils/acme:save() Failed to create ACME %s directory", kind)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "utils/acme:save() Failed to marshal ACME %s record", kind)
+	}
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(dir, id+".json"), data, 0600),
+		"utils/acme:save() Failed to write ACME %s record", kind)
+}
+
+func (s *AcmeStore) load(kind, id string, v interface{}) error {
+	// identifiers are generated by NewAcmeID, anything else cannot name a stored resource
+	if id == "" || strings.ContainsAny(id, "/\\.") {
+		return ErrAcmeResourceNotFound
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, kind, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrAcmeResourceNotFound
+		}
+		return errors.Wrapf(err, "utils/acme:load() Failed to read ACME %s record", kind)
+	}
+	return errors.Wrapf(json.Unmarshal(data, v), "utils/acme:load() Failed to unmarshal ACME %s record", kind)
+}
+
+// ParseAcmeJWS verifies the flattened JWS of an ACME request. Requests for new accounts are signed with the key
+// embedded in the jwk header, all other requests carry the account URL in the kid header and are verified with the
+// key returned by accountKey.
+func (s *AcmeStore) ParseAcmeJWS(body []byte, accountKey func(kid string) (*jose.JSONWebKey, error)) (*AcmeJWS, error) {
+	jws, err := jose.ParseSigned(string(body))
+	if err != nil || len(jws.Signatures) != 1 {
+		return nil, ErrAcmeMalformed
+	}
+	header := jws.Signatures[0].Protected
+	if header.Algorithm == "" || header.Algorithm == "none" || strings.HasPrefix(header.Algorithm, "HS") {
+		return nil, ErrAcmeBadSignature
+	}
+	url, _ := header.ExtraHeaders["url"].(string)
+	if url == "" {
+		return nil, ErrAcmeMalformed
+	}
+	if !s.ConsumeNonce(header.Nonce) {
+		return nil, ErrAcmeBadNonce
+	}
+
+	result := AcmeJWS{URL: url, KeyID: header.KeyID, JWK: header.JSONWebKey}
+	var key *jose.JSONWebKey
+	switch {
+	case result.JWK != nil && result.KeyID == "":
+		if !result.JWK.Valid() || !result.JWK.IsPublic() {
+			return nil, ErrAcmeBadSignature
+		}
+		key = result.JWK
+	case result.JWK == nil && result.KeyID != "":
+		if key, err = accountKey(result.KeyID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrAcmeMalformed
+	}
+
+	result.Payload, err = jws.Verify(key)
+	if err != nil {
+		return nil, ErrAcmeBadSignature
+	}
+	return &result, nil
+}
+
+// VerifyExternalAccountBinding checks that the external account binding JWS of a new account request is signed with
+// an unused CMS issued HMAC key and binds the account key jwk
+func (s *AcmeStore) VerifyExternalAccountBinding(eab []byte, jwk *jose.JSONWebKey, url string) (*cms.AcmeEabKey, error) {
+	jws, err := jose.ParseSigned(string(eab))
+	if err != nil || len(jws.Signatures) != 1 {
+		return nil, ErrAcmeEabInvalid
+	}
+	header := jws.Signatures[0].Protected
+	if !strings.HasPrefix(header.Algorithm, "HS") || header.Nonce != "" {
+		return nil, ErrAcmeEabInvalid
+	}
+	if eabUrl, _ := header.ExtraHeaders["url"].(string); eabUrl != url {
+		return nil, ErrAcmeEabInvalid
+	}
+
+	eabKey, err := s.GetEabKey(header.KeyID)
+	if err != nil {
+		if errors.Cause(err) == ErrAcmeResourceNotFound {
+			return nil, ErrAcmeEabInvalid
+		}
+		return nil, err
+	}
+	if eabKey.AccountID != "" {
+		return nil, ErrAcmeEabInvalid
+	}
+	hmacKey, err := base64.RawURLEncoding.DecodeString(eabKey.HmacKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "utils/acme:VerifyExternalAccountBinding() Failed to decode HMAC key")
+	}
+	payload, err := jws.Verify(hmacKey)
+	if err != nil {
+		return nil, ErrAcmeEabInvalid
+	}
+
+	var boundKey jose.JSONWebKey
+	if err = json.Unmarshal(payload, &boundKey); err != nil {
+		return nil, ErrAcmeEabInvalid
+	}
+	boundThumbprint, err := JWKThumbprint(&boundKey)
+	if err != nil {
+		return nil, ErrAcmeEabInvalid
+	}
+	accountThumbprint, err := JWKThumbprint(jwk)
+	if err != nil || boundThumbprint != accountThumbprint {
+		return nil, ErrAcmeEabInvalid
+	}
+	return eabKey, nil
+}
+
+// JWKThumbprint returns the base64url encoded RFC 7638 SHA-256 thumbprint of a JWK
+func JWKThumbprint(jwk *jose.JSONWebKey) (string, error) {
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", errors.Wrap(err, "utils/acme:JWKThumbprint() Failed to compute JWK thumbprint")
+	}
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
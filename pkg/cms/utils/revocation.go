@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+var (
+	ErrCertificateNotFound     = errors.New("Certificate with given serial number was not issued by CMS")
+	ErrCertificateRevoked      = errors.New("Certificate is already revoked")
+	ErrInvalidRevocationReason = errors.New("Invalid revocation reason")
+	ErrIssuingCaNotFound       = errors.New("Issuing CA not found")
+	ErrOCSPIssuerNotRecognized = errors.New("OCSP request is not for a certificate issued by CMS")
+	issuedCertsDir             = constants.IssuedCertsDirPath
+	revocationReasonCodes      = map[string]int{
+		cms.RevocationReasonUnspecified:          ocsp.Unspecified,
+		cms.RevocationReasonKeyCompromise:        ocsp.KeyCompromise,
+		cms.RevocationReasonCACompromise:         ocsp.CACompromise,
+		cms.RevocationReasonAffiliationChanged:   ocsp.AffiliationChanged,
+		cms.RevocationReasonSuperseded:           ocsp.Superseded,
+		cms.RevocationReasonCessationOfOperation: ocsp.CessationOfOperation,
+		cms.RevocationReasonPrivilegeWithdrawn:   ocsp.PrivilegeWithdrawn,
+	}
+)
+
+// GetCmsBaseUrl returns the externally reachable CMS API URL that is published in the CRL distribution point and
+// AIA extensions. When cms-base-url is not configured, it is derived from the first SAN of the CMS TLS certificate.
+func GetCmsBaseUrl(cfg *config.Configuration) string {
+	if cfg.CmsBaseUrl != "" {
+		return strings.TrimSuffix(cfg.CmsBaseUrl, "/") + "/"
+	}
+	host := strings.TrimSpace(strings.Split(cfg.TlsSanList, ",")[0])
+	port := cfg.Server.Port
+	if port == 0 {
+		port = constants.DefaultPort
+	}
+	return fmt.Sprintf("https://%s:%d/%s%s/", host, port, strings.ToLower(constants.ServiceName), constants.ApiVersion)
+}
+
+// SetRevocationExtensions adds the CRL distribution point and AIA (OCSP responder and CA issuer) locations of the
+// issuing CA to the certificate template
+func SetRevocationExtensions(template *x509.Certificate, cmsBaseUrl, issuingCa string) {
+	template.CRLDistributionPoints = []string{cmsBaseUrl + "crl/" + issuingCa}
+	template.OCSPServer = []string{cmsBaseUrl + "ocsp"}
+	template.IssuingCertificateURL = []string{cmsBaseUrl + "ca-certificates?issuingCa=" + issuingCa}
+}
+
+// SaveIssuedCertificate persists a record of a certificate issued by issuingCa so that it can be revoked later
+func SaveIssuedCertificate(cert *x509.Certificate, issuingCa string) error {
+	record := cms.IssuedCertificate{
+		SerialNumber: cert.SerialNumber.Text(16),
+		IssuingCa:    issuingCa,
+		Subject:      cert.Subject.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		IssuedAt:     time.Now().UTC(),
+	}
+	return errors.Wrap(writeIssuedCertificate(&record), "utils/revocation:SaveIssuedCertificate() Failed to save issued certificate record")
+}
+
+// GetIssuedCertificate retrieves the record of an issued certificate by its serial number
+func GetIssuedCertificate(serialNumber *big.Int) (*cms.IssuedCertificate, error) {
+	data, err := ioutil.ReadFile(issuedCertificatePath(serialNumber.Text(16)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, errors.Wrap(err, "utils/revocation:GetIssuedCertificate() Failed to read issued certificate record")
+	}
+	var record cms.IssuedCertificate
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, errors.Wrap(err, "utils/revocation:GetIssuedCertificate() Failed to unmarshal issued certificate record")
+	}
+	return &record, nil
+}
+
+// RevokeCertificate marks an issued certificate as revoked. An empty reason is recorded as unspecified.
+func RevokeCertificate(serialNumber *big.Int, reason string) (*cms.IssuedCertificate, error) {
+	if reason == "" {
+		reason = cms.RevocationReasonUnspecified
+	}
+	if _, ok := revocationReasonCodes[reason]; !ok {
+		return nil, ErrInvalidRevocationReason
+	}
+	record, err := GetIssuedCertificate(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if record.RevokedAt != nil {
+		return nil, ErrCertificateRevoked
+	}
+	revokedAt := time.Now().UTC()
+	record.RevokedAt = &revokedAt
+	record.RevocationReason = reason
+	if err = writeIssuedCertificate(record); err != nil {
+		return nil, errors.Wrap(err, "utils/revocation:RevokeCertificate() Failed to save revoked certificate record")
+	}
+	return record, nil
+}
+
+// GetRevokedCertificates returns the records of all unexpired certificates revoked under issuingCa
+func GetRevokedCertificates(issuingCa string) ([]cms.IssuedCertificate, error) {
+	files, err := ioutil.ReadDir(issuedCertsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "utils/revocation:GetRevokedCertificates() Failed to read issued certificates directory")
+	}
+	now := time.Now()
+	var revoked []cms.IssuedCertificate
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(issuedCertsDir, file.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "utils/revocation:GetRevokedCertificates() Failed to read issued certificate record %s", file.Name())
+		}
+		var record cms.IssuedCertificate
+		if err = json.Unmarshal(data, &record); err != nil {
+			return nil, errors.Wrapf(err, "utils/revocation:GetRevokedCertificates() Failed to unmarshal issued certificate record %s", file.Name())
+		}
+		if record.IssuingCa == issuingCa && record.RevokedAt != nil && record.NotAfter.After(now) {
+			revoked = append(revoked, record)
+		}
+	}
+	return revoked, nil
+}
+
+// CreateCRL builds a DER encoded certificate revocation list for issuingCa, signed by the CA key
+func CreateCRL(issuingCa string) ([]byte, error) {
+	caCert, caSigner, err := loadIssuingCa(issuingCa)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := GetRevokedCertificates(issuingCa)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	template := x509.RevocationList{
+		Number:     big.NewInt(now.Unix()),
+		ThisUpdate: now,
+		NextUpdate: now.Add(constants.DefaultCRLValidity),
+	}
+	for _, record := range revoked {
+		serialNumber, ok := new(big.Int).SetString(record.SerialNumber, 16)
+		if !ok {
+			return nil, errors.Errorf("utils/revocation:CreateCRL() Invalid serial number %s in issued certificate record", record.SerialNumber)
+		}
+		entry := pkix.RevokedCertificate{
+			SerialNumber:   serialNumber,
+			RevocationTime: *record.RevokedAt,
+		}
+		if code := revocationReasonCodes[record.RevocationReason]; code != ocsp.Unspecified {
+			reason, err := asn1.Marshal(asn1.Enumerated(code))
+			if err != nil {
+				return nil, errors.Wrap(err, "utils/revocation:CreateCRL() Failed to encode revocation reason")
+			}
+			entry.Extensions = []pkix.Extension{{Id: asn1.ObjectIdentifier{2, 5, 29, 21}, Value: reason}}
+		}
+		template.RevokedCertificates = append(template.RevokedCertificates, entry)
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &template, caCert, caSigner)
+	if err != nil {
+		return nil, errors.Wrap(err, "utils/revocation:CreateCRL() Failed to create CRL")
+	}
+	return crl, nil
+}
+
+// CreateOCSPResponse answers a DER encoded OCSP request with a response signed by the CA that issued the certificate
+func CreateOCSPResponse(request []byte) ([]byte, error) {
+	ocspRequest, err := ocsp.ParseRequest(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "utils/revocation:CreateOCSPResponse() Failed to parse OCSP request")
+	}
+
+	issuingCa, caCert, caSigner, err := findOCSPIssuer(ocspRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: ocspRequest.SerialNumber,
+		IssuerHash:   ocspRequest.HashAlgorithm,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(constants.DefaultCRLValidity),
+	}
+	record, err := GetIssuedCertificate(ocspRequest.SerialNumber)
+	if err == ErrCertificateNotFound || (err == nil && record.IssuingCa != issuingCa) {
+		template.Status = ocsp.Unknown
+	} else if err != nil {
+		return nil, err
+	} else if record.RevokedAt != nil {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = *record.RevokedAt
+		template.RevocationReason = revocationReasonCodes[record.RevocationReason]
+	}
+
+	response, err := ocsp.CreateResponse(caCert, caCert, template, caSigner)
+	if err != nil {
+		return nil, errors.Wrap(err, "utils/revocation:CreateOCSPResponse() Failed to create OCSP response")
+	}
+	return response, nil
+}
+
+// findOCSPIssuer matches the issuer name and key hashes of the request against the CMS CAs
+func findOCSPIssuer(request *ocsp.Request) (string, *x509.Certificate, crypto.Signer, error) {
+	if !request.HashAlgorithm.Available() {
+		return "", nil, nil, ErrOCSPIssuerNotRecognized
+	}
+	for _, issuingCa := range append([]string{constants.Root}, constants.GetIntermediateCAs()...) {
+		caCert, err := crypt.GetCertFromPemFile(constants.GetCaAttribs(issuingCa).CertPath)
+		if err != nil {
+			// CAs that have not been created yet cannot be the issuer
+			continue
+		}
+		var spki struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}
+		if _, err = asn1.Unmarshal(caCert.RawSubjectPublicKeyInfo, &spki); err != nil {
+			return "", nil, nil, errors.Wrap(err, "utils/revocation:findOCSPIssuer() Failed to parse CA public key")
+		}
+		nameHash := request.HashAlgorithm.New()
+		nameHash.Write(caCert.RawSubject)
+		keyHash := request.HashAlgorithm.New()
+		keyHash.Write(spki.PublicKey.RightAlign())
+		if bytes.Equal(nameHash.Sum(nil), request.IssuerNameHash) && bytes.Equal(keyHash.Sum(nil), request.IssuerKeyHash) {
+			caCert, caSigner, err := loadIssuingCa(issuingCa)
+			return issuingCa, caCert, caSigner, err
+		}
+	}
+	return "", nil, nil, ErrOCSPIssuerNotRecognized
+}
+
+func loadIssuingCa(issuingCa string) (*x509.Certificate, crypto.Signer, error) {
+	caAttr := constants.GetCaAttribs(issuingCa)
+	if caAttr.CommonName == "" {
+		return nil, nil, ErrIssuingCaNotFound
+	}
+	caCert, caKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "utils/revocation:loadIssuingCa() Could not load %s CA", issuingCa)
+	}
+	caSigner, ok := caKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.Errorf("utils/revocation:loadIssuingCa() %s CA key cannot be used for signing", issuingCa)
+	}
+	return caCert, caSigner, nil
+}
+
+func writeIssuedCertificate(record *cms.IssuedCertificate) error {
+	if err := os.MkdirAll(issuedCertsDir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(issuedCertificatePath(record.SerialNumber), data, 0600)
+}
+
+func issuedCertificatePath(serialNumberHex string) string {
+	return filepath.Join(issuedCertsDir, serialNumberHex+".json")
+}
+
+// ParseSerialNumber parses a hex encoded certificate serial number, optionally prefixed with 0x
+func ParseSerialNumber(serialNumber string) (*big.Int, bool) {
+	serialNumber = strings.TrimPrefix(strings.ToLower(serialNumber), "0x")
+	if serialNumber == "" {
+		return nil, false
+	}
+	return new(big.Int).SetString(serialNumber, 16)
+}
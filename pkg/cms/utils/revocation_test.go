@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/cms"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+func createTestRootCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	assertions := assert.New(t)
+	assertions.NoError(os.MkdirAll(constants.RootCADirPath, os.ModePerm))
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assertions.NoError(err)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: constants.DefaultRootCACommonName},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, &template, &template, &caKey.PublicKey, caKey)
+	assertions.NoError(err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(caKey)
+	assertions.NoError(err)
+	assertions.NoError(crypt.SavePrivateKeyAsPKCS8(keyDer, constants.RootCAKeyPath))
+	assertions.NoError(crypt.SavePemCert(caDer, constants.RootCACertPath))
+	caCert, err := x509.ParseCertificate(caDer)
+	assertions.NoError(err)
+	return caCert, caKey
+}
+
+func TestRevokeCertificate(t *testing.T) {
+	assertions := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "issued-certs")
+	assertions.NoError(err)
+	defer os.RemoveAll(dir)
+	issuedCertsDir = dir
+	defer func() { issuedCertsDir = constants.IssuedCertsDirPath }()
+
+	caCert, caKey := createTestRootCA(t)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assertions.NoError(err)
+	leafTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(0x2a),
+		Subject:      pkix.Name{CommonName: "HVS Flavor Signing Certificate"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	SetRevocationExtensions(&leafTemplate, GetCmsBaseUrl(&config.Configuration{TlsSanList: "cms.example.com,127.0.0.1"}), constants.Root)
+	assertions.Equal([]string{"https://cms.example.com:8445/cms/v1/crl/root"}, leafTemplate.CRLDistributionPoints)
+	assertions.Equal([]string{"https://cms.example.com:8445/cms/v1/ocsp"}, leafTemplate.OCSPServer)
+
+	leafDer, err := x509.CreateCertificate(rand.Reader, &leafTemplate, caCert, &leafKey.PublicKey, caKey)
+	assertions.NoError(err)
+	leafCert, err := x509.ParseCertificate(leafDer)
+	assertions.NoError(err)
+	assertions.NoError(SaveIssuedCertificate(leafCert, constants.Root))
+
+	// certificates that have not been revoked are reported good
+	ocspRequest, err := ocsp.CreateRequest(leafCert, caCert, nil)
+	assertions.NoError(err)
+	ocspResponse, err := CreateOCSPResponse(ocspRequest)
+	assertions.NoError(err)
+	response, err := ocsp.ParseResponseForCert(ocspResponse, leafCert, caCert)
+	assertions.NoError(err)
+	assertions.Equal(ocsp.Good, response.Status)
+
+	_, err = RevokeCertificate(leafCert.SerialNumber, "bogus")
+	assertions.Equal(ErrInvalidRevocationReason, err)
+	_, err = RevokeCertificate(big.NewInt(0x2b), cms.RevocationReasonKeyCompromise)
+	assertions.Equal(ErrCertificateNotFound, err)
+
+	record, err := RevokeCertificate(leafCert.SerialNumber, cms.RevocationReasonKeyCompromise)
+	assertions.NoError(err)
+	assertions.Equal("2a", record.SerialNumber)
+	assertions.NotNil(record.RevokedAt)
+	_, err = RevokeCertificate(leafCert.SerialNumber, "")
+	assertions.Equal(ErrCertificateRevoked, err)
+
+	crlDer, err := CreateCRL(constants.Root)
+	assertions.NoError(err)
+	crl, err := x509.ParseRevocationList(crlDer)
+	assertions.NoError(err)
+	assertions.NoError(crl.CheckSignatureFrom(caCert))
+	assertions.Len(crl.RevokedCertificates, 1)
+	assertions.Equal(0, crl.RevokedCertificates[0].SerialNumber.Cmp(leafCert.SerialNumber))
+
+	ocspResponse, err = CreateOCSPResponse(ocspRequest)
+	assertions.NoError(err)
+	response, err = ocsp.ParseResponseForCert(ocspResponse, leafCert, caCert)
+	assertions.NoError(err)
+	assertions.Equal(ocsp.Revoked, response.Status)
+	assertions.Equal(ocsp.KeyCompromise, response.RevocationReason)
+
+	_, err = CreateCRL("unknown")
+	assertions.Equal(ErrIssuingCaNotFound, err)
+}
+
+func TestParseSerialNumber(t *testing.T) {
+	assertions := assert.New(t)
+
+	serialNumber, ok := ParseSerialNumber("0x2A")
+	assertions.True(ok)
+	assertions.Equal(int64(42), serialNumber.Int64())
+	_, ok = ParseSerialNumber("xyz")
+	assertions.False(ok)
+	_, ok = ParseSerialNumber("")
+	assertions.False(ok)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package validation
+
+import (
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/search"
+	"github.com/pkg/errors"
+)
+
+//ValidateAcmeIdentifier is used to check that one of the TLS CertApprover role contexts bound to an ACME account
+// allows the identifier in its SAN list
+func ValidateAcmeIdentifier(roleContexts []string, identifier string) error {
+	log.Trace("validation/validate_acme:ValidateAcmeIdentifier() Entering")
+	defer log.Trace("validation/validate_acme:ValidateAcmeIdentifier() Leaving")
+
+	for _, roleContext := range roleContexts {
+		var sanList string
+		isTlsRole := false
+		for _, param := range strings.Split(roleContext, ";") {
+			param = strings.TrimSpace(param)
+			if strings.EqualFold(param, "CERTTYPE="+constants.Tls) {
+				isTlsRole = true
+			} else if len(param) > 4 && strings.EqualFold(param[:4], "SAN=") {
+				sanList = param[4:]
+			}
+		}
+		if !isTlsRole {
+			continue
+		}
+		for _, san := range strings.Split(sanList, ",") {
+			san = strings.TrimSpace(san)
+			if san != "" && (strings.EqualFold(san, identifier) || search.WildcardMatched(strings.ToLower(identifier), strings.ToLower(san))) {
+				return nil
+			}
+		}
+	}
+	return errors.New("validation/validate_acme:ValidateAcmeIdentifier() No role associated with identifier - " + identifier)
+}
@@ -36,14 +36,15 @@ type Configuration struct {
 	Dek             string `yaml:"data-encryption-key" mapstructure:"data-encryption-key"`
 	AikCertValidity int    `yaml:"aik-certificate-validity-years" mapstructure:"aik-certificate-validity-years"`
 
-	Server                   commConfig.ServerConfig `yaml:"server" mapstructure:"server"`
-	Log                      commConfig.LogConfig    `yaml:"log" mapstructure:"log"`
-	DB                       commConfig.DBConfig     `yaml:"db" mapstructure:"db"`
-	HRRS                     hrrs.HRRSConfig         `yaml:"hrrs" mapstructure:"hrrs"`
-	FVS                      FVSConfig               `yaml:"fvs" mapstructure:"fvs"`
-	VCSS                     VCSSConfig              `yaml:"vcss" mapstructure:"vcss"`
-	NATS                     NatsConfig              `yaml:"nats" mapstructure:"nats"`
-	EnableEkCertRevokeChecks bool                    `yaml:"enable-ekcert-revoke-check" mapstructure:"enable-ekcert-revoke-check"`
+	Server                   commConfig.ServerConfig  `yaml:"server" mapstructure:"server"`
+	Log                      commConfig.LogConfig     `yaml:"log" mapstructure:"log"`
+	DB                       commConfig.DBConfig      `yaml:"db" mapstructure:"db"`
+	HRRS                     hrrs.HRRSConfig          `yaml:"hrrs" mapstructure:"hrrs"`
+	FVS                      FVSConfig                `yaml:"fvs" mapstructure:"fvs"`
+	VCSS                     VCSSConfig               `yaml:"vcss" mapstructure:"vcss"`
+	NATS                     NatsConfig               `yaml:"nats" mapstructure:"nats"`
+	Tracing                  commConfig.TracingConfig `yaml:"tracing" mapstructure:"tracing"`
+	EnableEkCertRevokeChecks bool                     `yaml:"enable-ekcert-revoke-check" mapstructure:"enable-ekcert-revoke-check"`
 }
 
 type FVSConfig struct {
@@ -51,6 +52,11 @@ type FVSConfig struct {
 	NumberOfDataFetchers            int  `yaml:"number-of-data-fetchers" mapstructure:"number-of-data-fetchers"`
 	SkipFlavorSignatureVerification bool `yaml:"skip-flavor-signature-verification" mapstructure:"skip-flavor-signature-verification"`
 	HostTrustCacheThreshold         int  `yaml:"host-trust-cache-threshold" mapstructure:"host-trust-cache-threshold"`
+	// fetch retry backoff and per host circuit breaker of the host data fetcher
+	RetryBackoffBase        time.Duration `yaml:"retry-backoff-base" mapstructure:"retry-backoff-base"`
+	RetryBackoffMax         time.Duration `yaml:"retry-backoff-max" mapstructure:"retry-backoff-max"`
+	CircuitBreakerThreshold int           `yaml:"circuit-breaker-threshold" mapstructure:"circuit-breaker-threshold"`
+	CircuitBreakerCooldown  time.Duration `yaml:"circuit-breaker-cooldown" mapstructure:"circuit-breaker-cooldown"`
 }
 
 type SAMLConfig struct {
@@ -61,6 +61,11 @@ const (
 	FlavorSigningCertFile = TrustedCaCertsDir + "flavor-signing.pem"
 	FlavorSigningKeyFile  = TrustedKeysDir + "flavor-signing.key"
 
+	// CAs trusted to issue the signing certificates of imported flavors, and the signing certificates of the
+	// imported flavors
+	TrustedFlavorCACertsDir       = TrustedCaCertsDir + "flavor/"
+	ImportedFlavorSigningCertsDir = TrustedCaCertsDir + "imported-flavor-signing/"
+
 	// privacy ca key and cert
 	PrivacyCACertFile = TrustedCaCertsDir + "privacy-ca/privacy-ca-cert.pem"
 	PrivacyCAKeyFile  = TrustedKeysDir + "privacy-ca.key"
@@ -127,6 +132,12 @@ const (
 	DefaultMaxHeaderBytes    = 1 << 20
 )
 
+// push mode attestation constants
+const (
+	HostNonceSize            = 20
+	DefaultHostNonceValidity = 5 * time.Minute
+)
+
 // db constants
 const (
 	DBTypePostgres = "postgres"
@@ -159,6 +170,33 @@ const (
 	DefaultFvsNumberOfDataFetchers         = 20
 	DefaultSkipFlavorSignatureVerification = false
 	DefaultHostTrustCacheThreshold         = 100000
+	DefaultFvsRetryBackoffBase             = 30 * time.Second
+	DefaultFvsRetryBackoffMax              = 5 * time.Minute
+	DefaultFvsCircuitBreakerThreshold      = 5
+	DefaultFvsCircuitBreakerCooldown       = 30 * time.Minute
+)
+
+// bulk host registration constants
+const (
+	// HostBulkMaxHosts is the maximum number of hosts of a single bulk registration request
+	HostBulkMaxHosts = 10000
+	// HostBulkMaxRequestSize is the maximum size in bytes of the body of a bulk registration request
+	HostBulkMaxRequestSize = 16 << 20
+	// HostBulkWorkers is the number of hosts of a bulk registration job that are registered concurrently
+	HostBulkWorkers = 10
+)
+
+// flavorgroup trust simulation constants
+const (
+	// FlavorgroupSimulationMaxHosts is the maximum number of hosts evaluated by a simulation answered synchronously,
+	// the simulations of the flavorgroups linked to more hosts must be run as a job
+	FlavorgroupSimulationMaxHosts = 100
+)
+
+// tracing constants
+const (
+	DefaultTracingExporter    = "none"
+	DefaultTracingSampleRatio = 1.0
 )
 
 //VCSS constants
@@ -192,9 +230,20 @@ const (
 	FvsNumberOfDataFetchers            = "fvs-number-of-data-fetchers"
 	FvsSkipFlavorSignatureVerification = "fvs-skip-flavor-signature-verification"
 	FvsHostTrustCacheThreshold         = "fvs-host-trust-cache-threshold"
+	FvsRetryBackoffBase                = "fvs-retry-backoff-base"
+	FvsRetryBackoffMax                 = "fvs-retry-backoff-max"
+	FvsCircuitBreakerThreshold         = "fvs-circuit-breaker-threshold"
+	FvsCircuitBreakerCooldown          = "fvs-circuit-breaker-cooldown"
 	HrrsRefreshPeriod                  = "hrrs-refresh-period"
 	VcssRefreshPeriod                  = "vcss-refresh-period"
+	TracingExporter                    = "tracing-exporter"
+	TracingEndpoint                    = "tracing-endpoint"
+	TracingInsecure                    = "tracing-insecure"
+	TracingSampleRatio                 = "tracing-sample-ratio"
 )
 
 // EnableEKCertRevokeCheck
 const EnableEKCertRevokeCheck = "enable-ekcert-revoke-check"
+
+// ReportDiffLatestTrusted compares a report with the latest trusted report of the host in report diff requests
+const ReportDiffLatestTrusted = "latest-trusted"
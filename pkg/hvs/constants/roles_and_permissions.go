@@ -12,6 +12,7 @@ const (
 	FlavorGroupCreate   = "flavorgroups:create"
 	FlavorGroupRetrieve = "flavorgroups:retrieve"
 	FlavorGroupSearch   = "flavorgroups:search"
+	FlavorGroupUpdate   = "flavorgroups:store"
 	FlavorGroupDelete   = "flavorgroups:delete"
 
 	CertifyAik = "host_aiks:certify"
@@ -19,6 +20,21 @@ const (
 	HostStatusRetrieve = "host_status:retrieve"
 	HostStatusSearch   = "host_status:search"
 
+	HostFetchQueueSearch   = "host_fetch_queue:search"
+	HostFetchQueueRetrieve = "host_fetch_queue:retrieve"
+	HostFetchQueueReset    = "host_fetch_queue:reset"
+
+	SubscriptionCreate   = "subscriptions:create"
+	SubscriptionRetrieve = "subscriptions:retrieve"
+	SubscriptionSearch   = "subscriptions:search"
+	SubscriptionDelete   = "subscriptions:delete"
+
+	MetricsRetrieve = "metrics:retrieve"
+
+	JobSearch   = "jobs:search"
+	JobRetrieve = "jobs:retrieve"
+	JobCancel   = "jobs:cancel"
+
 	CaCertificatesCreate = "cacertificates:create"
 
 	CertifyHostSigningKey = "host_signing_key_certificates:create"
@@ -29,6 +45,9 @@ const (
 	HostDelete   = "hosts:delete"
 	HostSearch   = "hosts:search"
 
+	HostNonceRetrieve  = "host_nonces:retrieve"
+	HostEvidenceCreate = "host_evidence:create"
+
 	//FlavorTemplate Permissions.
 	FlavorTemplateCreate   = "flavor-template:create"
 	FlavorTemplateRetrieve = "flavor-template:retrieve"
@@ -38,7 +57,10 @@ const (
 	FlavorCreate   = "flavors:create"
 	FlavorRetrieve = "flavors:retrieve"
 	FlavorSearch   = "flavors:search"
+	FlavorUpdate   = "flavors:store"
 	FlavorDelete   = "flavors:delete"
+	FlavorExport   = "flavors:export"
+	FlavorImport   = "flavors:import"
 
 	TagFlavorCreate        = "tag_flavors:create"
 	HostUniqueFlavorCreate = "host_unique_flavors:create"
@@ -20,6 +20,10 @@ const (
 	RuleXmlMeasurementLogEquals     = RulePrefix + "XmlMeasurementLogEquals"
 	RulePcrEventLogEqualsExcluding  = RulePrefix + "PcrEventLogEqualsExcluding"
 	RuleXmlMeasurementLogIntegrity  = RulePrefix + "XmlMeasurementLogIntegrity"
+	RuleCustomRuleMatches           = RulePrefix + "CustomRuleMatches"
+	RuleImaLogIntegrity             = RulePrefix + "ImaLogIntegrity"
+	RuleImaMeasurementsAllowed      = RulePrefix + "ImaMeasurementsAllowed"
+	RuleFlavorActive                = RulePrefix + "FlavorActive"
 )
 
 // Verifier Faults
@@ -57,6 +61,16 @@ const (
 	FaultXmlMeasurementLogValueMismatchEntries384   = FaultPrefix + "XmlMeasurementLogValueMismatchEntriesSha384"
 	FaultXmlMeasurementsDigestValueMismatch         = FaultPrefix + "XmlMeasurementsDigestValueMismatch"
 	FaultXmlMeasurementValueMismatch                = FaultPrefix + "XmlMeasurementValueMismatch"
+	FaultCustomRuleMismatch                         = FaultPrefix + "CustomRuleMismatch"
+	FaultCustomRuleEvaluationFailed                 = FaultPrefix + "CustomRuleEvaluationFailed"
+	FaultImaLogMissing                              = FaultPrefix + "ImaLogMissing"
+	FaultImaLogInvalid                              = FaultPrefix + "ImaLogInvalid"
+	FaultImaMeasurementUnknown                      = FaultPrefix + "ImaMeasurementUnknown"
+	FaultImaMeasurementDenied                       = FaultPrefix + "ImaMeasurementDenied"
+	FaultFlavorDeprecated                           = FaultPrefix + "FlavorDeprecated"
+	FaultFlavorGracePeriodEnded                     = FaultPrefix + "FlavorGracePeriodEnded"
+	FaultFlavorRevoked                              = FaultPrefix + "FlavorRevoked"
+	FaultFlavorExpired                              = FaultPrefix + "FlavorExpired"
 	PcrEventLogUnexpectedFields                     = "PcrEventLogUnexpectedFields"
 	PcrEventLogMissingFields                        = "PcrEventLogMissingFields"
 )
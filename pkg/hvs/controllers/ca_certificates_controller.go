@@ -153,8 +153,9 @@ func validateCaCertificates(caCertificate hvs.CaCertificate) (*x509.Certificate,
 
 	if !(models.CaCertTypesRootCa.String() == caCertificate.Type ||
 		models.CaCertTypesEndorsementCa.String() == caCertificate.Type ||
-		models.CaCertTypesEkCa.String() == caCertificate.Type) {
-		return nil, errors.Errorf("Invalid type, only root or endorsement ca certificate can be added")
+		models.CaCertTypesEkCa.String() == caCertificate.Type ||
+		models.CaCertTypesFlavorCa.String() == caCertificate.Type) {
+		return nil, errors.Errorf("Invalid type, only root, endorsement or flavor ca certificate can be added")
 	}
 
 	certificate, err := x509.ParseCertificate(caCertificate.Certificate)
@@ -6,13 +6,18 @@
 package controllers
 
 import (
+	"context"
 	"crypto"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/antchfx/jsonquery"
 	"github.com/google/uuid"
@@ -25,6 +30,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/auth"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
 	comctx "github.com/intel-secl/intel-secl/v4/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
@@ -47,9 +53,12 @@ type FlavorController struct {
 	HostCon   HostController
 	FTStore   domain.FlavorTemplateStore
 	IsExsi    bool
+	// JobManager, when set, runs the flavor creation as a job for the clients asking for an asynchronous response
+	JobManager domain.JobManager
 }
 
-var flavorSearchParams = map[string]bool{"id": true, "key": true, "value": true, "flavorgroupId": true, "flavorParts": true}
+var flavorSearchParams = map[string]bool{"id": true, "key": true, "value": true, "flavorgroupId": true, "flavorParts": true,
+	"limit": true, "after": true, "fields": true}
 
 func NewFlavorController(fs domain.FlavorStore, fgs domain.FlavorGroupStore, hs domain.HostStore, tcs domain.TagCertificateStore, htm domain.HostTrustManager, certStore *dm.CertificatesStore, hcConfig domain.HostControllerConfig, fts domain.FlavorTemplateStore) *FlavorController {
 	// certStore should have an entry for Flavor Signing CA
@@ -101,8 +110,6 @@ func (fcon *FlavorController) Create(w http.ResponseWriter, r *http.Request) (in
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Could not get user permissions from http context"}
 	}
 
-	var signedFlavors []hvs.SignedFlavor
-
 	if len(flavorCreateReq.FlavorParts) == 0 {
 		if !checkValidFlavorPermission(privileges, []string{consts.FlavorCreate}) {
 			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Insufficient privileges to access /v2/hvs/flavors"}
@@ -129,9 +136,38 @@ func (fcon *FlavorController) Create(w http.ResponseWriter, r *http.Request) (in
 		}
 	}
 
-	signedFlavors, err = fcon.createFlavors(flavorCreateReq)
+	// flavors created from a host can take a while, the client can ask for them to be created by a job
+	if fcon.JobManager != nil && preferAsync(r) {
+		return submitJob(w, r, fcon.JobManager, hvs.JobTypeFlavorCreate,
+			func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+				signedFlavorCollection, _, err := fcon.createFlavorCollection(ctx, flavorCreateReq)
+				if err != nil {
+					return nil, err
+				}
+				return signedFlavorCollection, nil
+			})
+	}
+
+	signedFlavorCollection, status, err := fcon.createFlavorCollection(r.Context(), flavorCreateReq)
 	if err != nil {
-		defaultLog.WithError(err).Error("controllers/flavor_controller:Create() Error creating flavors")
+		return nil, status, err
+	}
+	secLog.Info("Flavors created successfully")
+	return signedFlavorCollection, http.StatusCreated, nil
+}
+
+// createFlavorCollection creates the flavors of the request, ordered as per the requested flavor parts. The returned
+// error is safe to be reported to the client.
+func (fcon *FlavorController) createFlavorCollection(ctx context.Context, flavorCreateReq dm.FlavorCreateRequest) (*hvs.SignedFlavorCollection, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:createFlavorCollection() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:createFlavorCollection() Leaving")
+
+	signedFlavors, err := fcon.createFlavors(ctx, flavorCreateReq)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:createFlavorCollection() Error creating flavors")
+		if errors.Cause(err) == context.Canceled {
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Flavor creation was cancelled"}
+		}
 		if strings.Contains(err.Error(), "duplicate key") {
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with same id/label already exists"}
 		}
@@ -154,11 +190,12 @@ func (fcon *FlavorController) Create(w http.ResponseWriter, r *http.Request) (in
 	if flavorCreateReq.FlavorParts != nil && len(flavorCreateReq.FlavorParts) > 0 {
 		signedFlavorCollection = orderFlavorsPerFlavorParts(flavorCreateReq.FlavorParts, signedFlavorCollection)
 	}
-	secLog.Info("Flavors created successfully")
-	return signedFlavorCollection, http.StatusCreated, nil
+	return &signedFlavorCollection, http.StatusCreated, nil
 }
 
-func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([]hvs.SignedFlavor, error) {
+// createFlavors creates the flavors of the request and adds them to their flavorgroups. The flavors are not created
+// once ctx is cancelled.
+func (fcon *FlavorController) createFlavors(ctx context.Context, flavorReq dm.FlavorCreateRequest) ([]hvs.SignedFlavor, error) {
 	defaultLog.Trace("controllers/flavor_controller:createFlavors() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:createFlavors() Leaving")
 
@@ -169,6 +206,10 @@ func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([
 	// add all flavorparts to default flavorgroups if flavorgroup name is not given
 	if flavorReq.FlavorgroupNames == nil && len(flavorReq.FlavorParts) == 0 {
 		for _, flavorPart := range hvs.GetFlavorTypes() {
+			// IMA flavors hold the allowlist of a host and are only created when requested
+			if flavorPart == hvs.FlavorPartIma {
+				continue
+			}
 			flavorParts = append(flavorParts, flavorPart)
 		}
 	}
@@ -200,7 +241,7 @@ func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([
 		}
 		defaultLog.Debug("Getting manifest from host...")
 
-		hostManifest, err := fcon.getHostManifest(connectionString)
+		hostManifest, err := fcon.getHostManifest(ctx, connectionString)
 		if err != nil {
 			defaultLog.Error("controllers/flavor_controller:CreateFlavors() Error getting host manifest")
 			return nil, errors.Wrap(err, "Error getting host manifest")
@@ -315,6 +356,10 @@ func (fcon *FlavorController) createFlavors(flavorReq dm.FlavorCreateRequest) ([
 		defaultLog.Error("controllers/flavor_controller:createFlavors() Cannot create flavors")
 		return nil, errors.New("Unable to create Flavors")
 	}
+	if err := ctx.Err(); err != nil {
+		defaultLog.Info("controllers/flavor_controller:createFlavors() Flavor creation cancelled")
+		return nil, errors.Wrap(err, "Flavor creation cancelled")
+	}
 	return fcon.addFlavorToFlavorgroup(flavorFlavorPartMap, flavorgroups)
 }
 
@@ -365,10 +410,11 @@ func orderFlavorsPerFlavorParts(parts []hvs.FlavorPartName, signedFlavorCollecti
 	}
 }
 
-func (fcon *FlavorController) getHostManifest(cs string) (*hvs.HostManifest, error) {
+// getHostManifest retrieves the manifest of the host, the requests to the host are bound to ctx
+func (fcon *FlavorController) getHostManifest(ctx context.Context, cs string) (*hvs.HostManifest, error) {
 	defaultLog.Trace("controllers/flavor_controller:getHostManifest() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:getHostManifest() Leaving")
-	hostConnector, err := fcon.HostCon.HCConfig.HostConnectorProvider.NewHostConnector(cs)
+	hostConnector, err := fcon.HostCon.HCConfig.HostConnectorProvider.NewHostConnectorWithContext(ctx, cs)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not instantiate host connector")
 	}
@@ -532,7 +578,7 @@ func (fcon *FlavorController) addFlavorToFlavorgroup(flavorFlavorPartMap map[hvs
 					}
 					fetchHostData = true
 
-				} else if flavorPart == hvs.FlavorPartPlatform || flavorPart == hvs.FlavorPartOs {
+				} else if flavorPart == hvs.FlavorPartPlatform || flavorPart == hvs.FlavorPartOs || flavorPart == hvs.FlavorPartIma {
 					flavorgroups = fgs
 					flavorgroupsForQueue = append(flavorgroupsForQueue, flavorgroups...)
 				}
@@ -576,7 +622,7 @@ func (fcon *FlavorController) addFlavorToFlavorgroup(flavorFlavorPartMap map[hvs
 	return returnSignedFlavors, nil
 }
 
-//purgeLatestMatchEntriesFromHTC clears entries from HostTrust Cache ensuring that
+// purgeLatestMatchEntriesFromHTC clears entries from HostTrust Cache ensuring that
 // reports verifications flows use the latest flavor for that flavorPart
 func (fcon *FlavorController) purgeLatestMatchEntriesFromHTC(newFGFlvrMap map[uuid.UUID][]uuid.UUID,
 	newFlavorpartFlavorMap map[hvs.FlavorPartName][]hvs.SignedFlavor,
@@ -684,7 +730,7 @@ func (fcon FlavorController) addFlavorgroupHostsToFlavorVerifyQueue(fgs []hvs.Fl
 	defaultLog.Debugf("Found %v hosts to be added to flavor-verify queue", len(hostIdsForQueue))
 	// adding all the host linked to flavorgroup to flavor-verify queue
 	if len(hostIdsForQueue) >= 1 {
-		err := fcon.HTManager.VerifyHostsAsync(hostIdsForQueue, forceUpdate, false)
+		err := verifyHostsOnFlavorChange(fcon.HTManager, fcon.HStore, fcon.FGStore, hostIdsForQueue, forceUpdate)
 		if err != nil {
 			defaultLog.Error("controllers/flavor_controller:addFlavorToFlavorgroup() Host to Flavor Verify Queue addition failed")
 		}
@@ -754,6 +800,11 @@ func (fcon *FlavorController) Search(w http.ResponseWriter, r *http.Request) (in
 		secLog.Errorf("controllers/flavor_controller:Search()  %s", err.Error())
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
+	filterCriteria.Limit, filterCriteria.After, err = getPageCriteria(r.URL.Query())
+	if err != nil {
+		secLog.Errorf("controllers/flavor_controller:Search()  %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
 
 	signedFlavors, err := fcon.FStore.Search(&dm.FlavorVerificationFC{
 		FlavorFC: *filterCriteria,
@@ -762,9 +813,20 @@ func (fcon *FlavorController) Search(w http.ResponseWriter, r *http.Request) (in
 		secLog.WithError(err).Error("controllers/flavor_controller:Search() Flavor get all failed")
 		return nil, http.StatusInternalServerError, errors.Errorf("Unable to search Flavors")
 	}
+	signedFlavorCollection := hvs.SignedFlavorCollection{SignedFlavors: signedFlavors}
+	if len(signedFlavors) > 0 {
+		signedFlavorCollection.Next = nextPageLink(r, filterCriteria.Limit, len(signedFlavors),
+			signedFlavors[len(signedFlavors)-1].Flavor.Meta.ID)
+	}
+
+	result, err := selectFields(r.URL.Query(), signedFlavorCollection)
+	if err != nil {
+		secLog.Errorf("controllers/flavor_controller:Search()  %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
 
 	secLog.Infof("%s: Return flavor query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
-	return hvs.SignedFlavorCollection{SignedFlavors: signedFlavors}, http.StatusOK, nil
+	return result, http.StatusOK, nil
 }
 
 func (fcon *FlavorController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -802,7 +864,7 @@ func (fcon *FlavorController) Delete(w http.ResponseWriter, r *http.Request) (in
 	defaultLog.Debugf("Found %v hosts to be added to flavor-verify queue", len(hostIdsForQueue))
 	// adding all the host linked to flavor to flavor-verify queue
 	if len(hostIdsForQueue) >= 1 {
-		err := fcon.HTManager.VerifyHostsAsync(hostIdsForQueue, false, false)
+		err := verifyHostsOnFlavorChange(fcon.HTManager, fcon.HStore, fcon.FGStore, hostIdsForQueue, false)
 		if err != nil {
 			defaultLog.Error("controllers/flavor_controller:Delete() Host to Flavor Verify Queue addition failed")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
@@ -812,6 +874,97 @@ func (fcon *FlavorController) Delete(w http.ResponseWriter, r *http.Request) (in
 	return nil, http.StatusNoContent, nil
 }
 
+// UpdateLifecycle deprecates, supersedes, revokes or sets the expiry date of a flavor. Unlike Delete, the flavor is
+// kept: the hosts associated with it are re-verified and their reports flag the flavor according to its new state.
+func (fcon *FlavorController) UpdateLifecycle(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:UpdateLifecycle() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:UpdateLifecycle() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_controller:UpdateLifecycle() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var lifecycle hvs.FlavorLifecycle
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&lifecycle); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:UpdateLifecycle() %s :  Failed to decode request body as FlavorLifecycle", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+	if err := lifecycle.Validate(); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:UpdateLifecycle() %s Invalid flavor lifecycle", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	flavorId := uuid.MustParse(mux.Vars(r)["id"])
+	signedFlavor, err := fcon.FStore.Retrieve(flavorId)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", flavorId).Info(
+				"controllers/flavor_controller:UpdateLifecycle() Flavor with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Flavor with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", flavorId).Error(
+			"controllers/flavor_controller:UpdateLifecycle() Failed to retrieve Flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update Flavor lifecycle"}
+	}
+	if signedFlavor.Lifecycle != nil && signedFlavor.Lifecycle.State == hvs.FlavorStateRevoked &&
+		lifecycle.State != hvs.FlavorStateRevoked {
+		secLog.WithField("id", flavorId).Errorf("controllers/flavor_controller:UpdateLifecycle() %s Revoked flavor cannot be reinstated", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "A revoked Flavor cannot be reinstated"}
+	}
+	if lifecycle.SupersededBy != nil {
+		if *lifecycle.SupersededBy == flavorId {
+			secLog.WithField("id", flavorId).Errorf("controllers/flavor_controller:UpdateLifecycle() %s Flavor superseded by itself", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "A Flavor cannot be superseded by itself"}
+		}
+		if _, err := fcon.FStore.Retrieve(*lifecycle.SupersededBy); err != nil {
+			secLog.WithError(err).WithField("id", *lifecycle.SupersededBy).Errorf("controllers/flavor_controller:UpdateLifecycle() %s Superseding flavor not found", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Superseding Flavor with given ID does not exist"}
+		}
+	}
+
+	hostIdsForQueue, err := getHostsAssociatedWithFlavor(fcon.HStore, fcon.FGStore, signedFlavor)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:UpdateLifecycle() Failed to retrieve hosts " +
+			"associated with flavor")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve hosts " +
+			"associated with flavor for trust re-verification"}
+	}
+
+	if err := fcon.FStore.UpdateLifecycle(flavorId, lifecycle); err != nil {
+		defaultLog.WithError(err).WithField("id", flavorId).Error(
+			"controllers/flavor_controller:UpdateLifecycle() Failed to update Flavor lifecycle")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update Flavor lifecycle"}
+	}
+	signedFlavor.Lifecycle = &lifecycle
+
+	// the flavor is dropped from the trust cache of all the hosts so that their new reports apply its lifecycle
+	if err := fcon.HStore.RemoveTrustCacheFlavors(uuid.Nil, []uuid.UUID{flavorId}); err != nil {
+		defaultLog.WithError(err).WithField("id", flavorId).Error(
+			"controllers/flavor_controller:UpdateLifecycle() Failed to remove Flavor from the trust cache")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+			"associated with Flavor"}
+	}
+
+	defaultLog.Debugf("Found %v hosts to be added to flavor-verify queue", len(hostIdsForQueue))
+	if len(hostIdsForQueue) >= 1 {
+		if err := verifyHostsOnFlavorChange(fcon.HTManager, fcon.HStore, fcon.FGStore, hostIdsForQueue, false); err != nil {
+			defaultLog.WithError(err).Error("controllers/flavor_controller:UpdateLifecycle() Host to Flavor Verify Queue addition failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to re-verify hosts " +
+				"associated with Flavor"}
+		}
+	}
+
+	secLog.WithField("id", flavorId).Infof("%s: Flavor lifecycle set to %s by: %s", commLogMsg.PrivilegeModified, lifecycle.State, r.RemoteAddr)
+	return signedFlavor, http.StatusOK, nil
+}
+
 func getHostsAssociatedWithFlavor(hStore domain.HostStore, fgStore domain.FlavorGroupStore, flavor *hvs.SignedFlavor) ([]uuid.UUID, error) {
 	defaultLog.Trace("controllers/flavor_controller:getHostsAssociatedWithFlavor() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:getHostsAssociatedWithFlavor() Leaving")
@@ -853,6 +1006,77 @@ func getHostsAssociatedWithFlavor(hStore domain.HostStore, fgStore domain.Flavor
 	return hostIdsForQueue, nil
 }
 
+// verifyHostsOnFlavorChange adds the hosts to the flavor verify queue after the flavors that apply to them changed.
+// The hosts whose effective refresh schedule sets refresh on event are re-attested with new host data, the others
+// are verified with their last host manifest unless fetchHostData is set.
+func verifyHostsOnFlavorChange(htm domain.HostTrustManager, hStore domain.HostStore, fgStore domain.FlavorGroupStore,
+	hostIds []uuid.UUID, fetchHostData bool) error {
+	defaultLog.Trace("controllers/flavor_controller:verifyHostsOnFlavorChange() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:verifyHostsOnFlavorChange() Leaving")
+
+	if fetchHostData {
+		return htm.VerifyHostsAsync(hostIds, true, false)
+	}
+
+	var fetchHostIds, verifyHostIds []uuid.UUID
+	fgSchedules := make(map[uuid.UUID]*hvs.RefreshSchedule)
+	for _, hostId := range hostIds {
+		schedule, err := getEffectiveRefreshSchedule(hStore, fgStore, hostId, fgSchedules)
+		if err != nil {
+			// the host is still re-verified with its last host manifest
+			defaultLog.WithError(err).WithField("id", hostId).Warn("controllers/flavor_controller:verifyHostsOnFlavorChange() " +
+				"Failed to retrieve the refresh schedule of Host")
+		}
+		if schedule != nil && schedule.RefreshOnEvent {
+			fetchHostIds = append(fetchHostIds, hostId)
+		} else {
+			verifyHostIds = append(verifyHostIds, hostId)
+		}
+	}
+
+	if len(fetchHostIds) > 0 {
+		if err := htm.VerifyHostsAsync(fetchHostIds, true, false); err != nil {
+			return err
+		}
+	}
+	if len(verifyHostIds) > 0 {
+		return htm.VerifyHostsAsync(verifyHostIds, false, false)
+	}
+	return nil
+}
+
+// getEffectiveRefreshSchedule returns the refresh schedule that applies to the host, the override of the host or else
+// the schedule of its flavorgroups. The schedules of the flavorgroups are cached in fgSchedules across hosts.
+func getEffectiveRefreshSchedule(hStore domain.HostStore, fgStore domain.FlavorGroupStore, hostId uuid.UUID,
+	fgSchedules map[uuid.UUID]*hvs.RefreshSchedule) (*hvs.RefreshSchedule, error) {
+	host, err := hStore.Retrieve(hostId, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve Host")
+	}
+	if !host.RefreshSchedule.IsEmpty() {
+		return host.RefreshSchedule, nil
+	}
+
+	fgIds, err := hStore.SearchFlavorgroups(hostId)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve the flavorgroups of Host")
+	}
+	var schedules []*hvs.RefreshSchedule
+	for _, fgId := range fgIds {
+		schedule, ok := fgSchedules[fgId]
+		if !ok {
+			flavorgroup, err := fgStore.Retrieve(fgId)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to retrieve flavorgroup %s", fgId)
+			}
+			schedule = flavorgroup.RefreshSchedule
+			fgSchedules[fgId] = schedule
+		}
+		schedules = append(schedules, schedule)
+	}
+	return hvs.GetEffectiveRefreshSchedule(host.RefreshSchedule, schedules), nil
+}
+
 func (fcon *FlavorController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/flavor_controller:Retrieve() Entering")
 	defer defaultLog.Trace("controllers/flavor_controller:Retrieve() Leaving")
@@ -1059,3 +1283,594 @@ func checkValidFlavorPermission(privileges []ct.PermissionInfo, requiredPermissi
 	}
 	return true
 }
+
+var flavorExportParams = map[string]bool{"id": true, "flavorgroupId": true, "flavortemplateId": true}
+var flavorImportParams = map[string]bool{"dryRun": true, "conflict": true}
+
+// Export returns a bundle of the selected flavors, flavor groups and flavor templates. The bundle is signed with the
+// flavor signing key of the HVS and the flavors keep their own signatures, so that another HVS trusting the flavor CA
+// can import them.
+func (fcon *FlavorController) Export(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:Export() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:Export() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), flavorExportParams); err != nil {
+		secLog.Errorf("controllers/flavor_controller:Export() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	flavorIds, err := parseExportIds(r.URL.Query()["id"], "flavor")
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Export() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	flavorgroupIds, err := parseExportIds(r.URL.Query()["flavorgroupId"], "flavorgroup")
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Export() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	flavorTemplateIds, err := parseExportIds(r.URL.Query()["flavortemplateId"], "flavor template")
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Export() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if len(flavorIds) == 0 && len(flavorgroupIds) == 0 && len(flavorTemplateIds) == 0 {
+		secLog.Errorf("controllers/flavor_controller:Export() %s : Nothing selected for export", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "At least one flavor, flavorgroup or flavor template must be selected"}
+	}
+
+	content := hvs.FlavorBundleContent{
+		ExportedAt:    time.Now().UTC(),
+		SignedFlavors: []hvs.SignedFlavor{},
+	}
+	exportedFlavors := make(map[uuid.UUID]bool)
+	for _, flavorId := range flavorIds {
+		if exportedFlavors[flavorId] {
+			continue
+		}
+		signedFlavor, err := fcon.FStore.Retrieve(flavorId)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				secLog.WithError(err).WithField("id", flavorId).Info(
+					"controllers/flavor_controller:Export() Flavor with given ID does not exist")
+				return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Flavor with given ID does not exist"}
+			}
+			defaultLog.WithError(err).WithField("id", flavorId).Error("controllers/flavor_controller:Export() Failed to retrieve Flavor")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to export Flavors"}
+		}
+		if isHostBoundFlavor(&signedFlavor.Flavor) {
+			secLog.WithField("id", flavorId).Errorf("controllers/flavor_controller:Export() %s : Host bound flavor selected for export", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "HOST_UNIQUE and ASSET_TAG flavors cannot be exported"}
+		}
+		content.SignedFlavors = append(content.SignedFlavors, *signedFlavor)
+		exportedFlavors[flavorId] = true
+	}
+
+	for _, flavorgroupId := range flavorgroupIds {
+		flavorgroup, err := fcon.FGStore.Retrieve(flavorgroupId)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				secLog.WithError(err).WithField("id", flavorgroupId).Info(
+					"controllers/flavor_controller:Export() Flavorgroup with given ID does not exist")
+				return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Flavorgroup with given ID does not exist"}
+			}
+			defaultLog.WithError(err).WithField("id", flavorgroupId).Error("controllers/flavor_controller:Export() Failed to retrieve Flavorgroup")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to export Flavorgroups"}
+		}
+		groupFlavors, err := fcon.searchFlavorgroupFlavors(flavorgroupId)
+		if err != nil {
+			defaultLog.WithError(err).WithField("id", flavorgroupId).Error("controllers/flavor_controller:Export() Failed to retrieve Flavorgroup flavors")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to export Flavorgroups"}
+		}
+		groupTemplateIds, err := fcon.FGStore.SearchFlavorTemplatesByFlavorGroup(flavorgroupId)
+		if err != nil {
+			defaultLog.WithError(err).WithField("id", flavorgroupId).Error("controllers/flavor_controller:Export() Failed to retrieve Flavorgroup flavor templates")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to export Flavorgroups"}
+		}
+
+		// the host bound flavors are not exported, the hosts they are created for are not known to the importing HVS
+		exportedFlavorgroup := hvs.FlavorGroup{
+			ID:                flavorgroup.ID,
+			Name:              flavorgroup.Name,
+			MatchPolicies:     flavorgroup.MatchPolicies,
+			FlavorTemplateIds: groupTemplateIds,
+		}
+		for _, signedFlavor := range groupFlavors {
+			if isHostBoundFlavor(&signedFlavor.Flavor) {
+				continue
+			}
+			exportedFlavorgroup.FlavorIds = append(exportedFlavorgroup.FlavorIds, signedFlavor.Flavor.Meta.ID)
+			if !exportedFlavors[signedFlavor.Flavor.Meta.ID] {
+				content.SignedFlavors = append(content.SignedFlavors, signedFlavor)
+				exportedFlavors[signedFlavor.Flavor.Meta.ID] = true
+			}
+		}
+		content.Flavorgroups = append(content.Flavorgroups, exportedFlavorgroup)
+		flavorTemplateIds = append(flavorTemplateIds, groupTemplateIds...)
+	}
+
+	exportedTemplates := make(map[uuid.UUID]bool)
+	for _, flavorTemplateId := range flavorTemplateIds {
+		if exportedTemplates[flavorTemplateId] {
+			continue
+		}
+		flavorTemplate, err := fcon.FTStore.Retrieve(flavorTemplateId, false)
+		if err != nil {
+			if _, ok := err.(*commErr.StatusNotFoundError); ok {
+				secLog.WithError(err).WithField("id", flavorTemplateId).Info(
+					"controllers/flavor_controller:Export() Flavor template with given ID does not exist")
+				return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Flavor template with given ID does not exist or has been deleted"}
+			}
+			defaultLog.WithError(err).WithField("id", flavorTemplateId).Error("controllers/flavor_controller:Export() Failed to retrieve Flavor template")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to export Flavor templates"}
+		}
+		content.FlavorTemplates = append(content.FlavorTemplates, *flavorTemplate)
+		exportedTemplates[flavorTemplateId] = true
+	}
+
+	signingKey, signingCertificates, err := (*fcon.CertStore).GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+	rsaSigningKey, ok := signingKey.(*rsa.PrivateKey)
+	if err != nil || !ok {
+		defaultLog.WithError(err).Errorf("controllers/flavor_controller:Export() %s : Flavor Signing Key not found in CertStore", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to sign the flavor bundle"}
+	}
+	// the flavors imported from another HVS keep the signature of its flavor signing key, the imported signing
+	// certificates are added to the bundle for the importing HVS to verify them
+	bundleCertificates := append([]x509.Certificate{}, signingCertificates...)
+	for _, signedFlavor := range content.SignedFlavors {
+		if signedFlavor.Verify(&rsaSigningKey.PublicKey) != nil {
+			_, importedCertificates, _ := (*fcon.CertStore).GetKeyAndCertificates(dm.CertTypesImportedFlavorSigning.String())
+			bundleCertificates = append(bundleCertificates, importedCertificates...)
+			break
+		}
+	}
+	bundle, err := hvs.NewFlavorBundle(content, rsaSigningKey, bundleCertificates)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:Export() Failed to sign the flavor bundle")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to sign the flavor bundle"}
+	}
+
+	secLog.Infof("%s: Flavor bundle with %d flavors exported by: %s", commLogMsg.AuthorizedAccess, len(content.SignedFlavors), r.RemoteAddr)
+	return bundle, http.StatusOK, nil
+}
+
+// Import stores the flavors, flavor groups and flavor templates of a bundle exported by another HVS. The bundle and
+// its flavors must be signed with a key certified by one of the trusted flavor CAs. The flavors already existing in
+// the HVS are handled according to the conflict mode, a dry run only reports what the import would do.
+func (fcon *FlavorController) Import(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_controller:Import() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:Import() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+	if err := utils.ValidateQueryParams(r.URL.Query(), flavorImportParams); err != nil {
+		secLog.Errorf("controllers/flavor_controller:Import() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	dryRun := false
+	if dryRunParam := r.URL.Query().Get("dryRun"); dryRunParam != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(dryRunParam); err != nil {
+			secLog.WithError(err).Errorf("controllers/flavor_controller:Import() %s : Invalid dryRun query parameter", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid dryRun query parameter, it must be true or false"}
+		}
+	}
+	var conflictMode hvs.FlavorImportConflictMode
+	if err := conflictMode.Parse(r.URL.Query().Get("conflict")); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Import() %s : Invalid conflict query parameter", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_controller:Import() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+	var bundle hvs.FlavorBundle
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Import() %s :  Failed to decode request body as FlavorBundle", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	_, flavorCACertificates, _ := (*fcon.CertStore).GetKeyAndCertificates(dm.CaCertTypesFlavorCa.String())
+	if len(flavorCACertificates) == 0 {
+		defaultLog.Errorf("controllers/flavor_controller:Import() %s : Trusted flavor CA not found in CertStore", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "No trusted flavor CA is configured"}
+	}
+	if (*fcon.CertStore)[dm.CertTypesImportedFlavorSigning.String()] == nil {
+		defaultLog.Errorf("controllers/flavor_controller:Import() %s : Imported flavor signing certificate store not found in CertStore", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "No imported flavor signing certificate store is configured"}
+	}
+	content, signingCertificates, err := bundle.Verify(crypt.GetCertPool(flavorCACertificates))
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Import() %s : Flavor bundle verification failed", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The flavor bundle could not be verified"}
+	}
+	if err := validateFlavorBundleContent(content); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_controller:Import() %s : Invalid flavor bundle content", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	plan, err := fcon.planFlavorImport(content, conflictMode)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:Import() Failed to compare the flavor bundle with the stored flavors")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to import the flavor bundle"}
+	}
+	plan.report.DryRun = dryRun
+	if dryRun {
+		secLog.Infof("%s: Flavor bundle import dry run by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+		return plan.report, http.StatusOK, nil
+	}
+	if plan.report.HasConflicts() {
+		secLog.Errorf("controllers/flavor_controller:Import() %s : Flavor bundle conflicts with existing flavors", commLogMsg.InvalidInputBadParam)
+		return plan.report, http.StatusConflict, nil
+	}
+
+	if err := fcon.saveImportedFlavorSigningCertificates(signingCertificates); err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:Import() Failed to store the flavor bundle signing certificates")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to import the flavor bundle"}
+	}
+	if err := fcon.applyFlavorImport(plan); err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_controller:Import() Failed to import the flavor bundle")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to import the flavor bundle"}
+	}
+
+	secLog.Infof("%s: Flavor bundle with %d flavors imported by: %s", commLogMsg.PrivilegeModified, len(content.SignedFlavors), r.RemoteAddr)
+	return plan.report, http.StatusOK, nil
+}
+
+// flavorImportPlan holds the changes the import of a flavor bundle makes to the HVS along with their report
+type flavorImportPlan struct {
+	report hvs.FlavorImportReport
+	// flavors are the flavors of the bundle to be stored
+	flavors []hvs.SignedFlavor
+	// replacedFlavors maps the ID of the flavors of the bundle to the existing flavors they replace
+	replacedFlavors map[uuid.UUID]hvs.SignedFlavor
+	flavorTemplates []hvs.FlavorTemplate
+	// flavorgroups are the flavor groups of the bundle, with the ID of the existing flavor group of the same name or
+	// a nil ID for the ones to be created. Their flavors and templates are those to be linked once imported.
+	flavorgroups []hvs.FlavorGroup
+}
+
+func (fcon *FlavorController) planFlavorImport(content *hvs.FlavorBundleContent, conflictMode hvs.FlavorImportConflictMode) (*flavorImportPlan, error) {
+	defaultLog.Trace("controllers/flavor_controller:planFlavorImport() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:planFlavorImport() Leaving")
+
+	plan := flavorImportPlan{
+		report: hvs.FlavorImportReport{
+			ConflictMode:    conflictMode,
+			Flavors:         []hvs.FlavorImportResult{},
+			Flavorgroups:    []hvs.FlavorImportResult{},
+			FlavorTemplates: []hvs.FlavorImportResult{},
+		},
+		replacedFlavors: make(map[uuid.UUID]hvs.SignedFlavor),
+	}
+
+	// importedFlavorIds maps the ID of the flavors of the bundle to the ID of the flavor holding them once imported
+	importedFlavorIds := make(map[uuid.UUID]uuid.UUID)
+	for _, signedFlavor := range content.SignedFlavors {
+		flavorId := signedFlavor.Flavor.Meta.ID
+		if _, ok := importedFlavorIds[flavorId]; ok {
+			continue
+		}
+		result := hvs.FlavorImportResult{ID: flavorId, Name: signedFlavor.Flavor.Meta.Description[hvs.Label].(string)}
+		existing, err := fcon.retrieveExistingFlavor(&signedFlavor)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case existing == nil:
+			result.Action = hvs.FlavorImportActionCreate
+			plan.flavors = append(plan.flavors, signedFlavor)
+			importedFlavorIds[flavorId] = flavorId
+		case existing.Flavor.Meta.ID == flavorId && existing.Signature == signedFlavor.Signature:
+			result.Action = hvs.FlavorImportActionReuse
+			importedFlavorIds[flavorId] = flavorId
+		default:
+			existingId := existing.Flavor.Meta.ID
+			result.ExistingID = &existingId
+			switch conflictMode {
+			case hvs.FlavorImportConflictSkip:
+				result.Action = hvs.FlavorImportActionSkip
+				result.Message = "A different flavor with the same ID or label is kept"
+				importedFlavorIds[flavorId] = existingId
+			case hvs.FlavorImportConflictReplace:
+				result.Action = hvs.FlavorImportActionReplace
+				plan.flavors = append(plan.flavors, signedFlavor)
+				plan.replacedFlavors[flavorId] = *existing
+				importedFlavorIds[flavorId] = flavorId
+			default:
+				result.Action = hvs.FlavorImportActionConflict
+				result.Message = "A different flavor with the same ID or label exists"
+			}
+		}
+		plan.report.Flavors = append(plan.report.Flavors, result)
+	}
+
+	importedTemplates := make(map[uuid.UUID]bool)
+	for _, flavorTemplate := range content.FlavorTemplates {
+		if _, ok := importedTemplates[flavorTemplate.ID]; ok {
+			continue
+		}
+		result := hvs.FlavorImportResult{ID: flavorTemplate.ID, Name: flavorTemplate.Label}
+		_, err := fcon.FTStore.Retrieve(flavorTemplate.ID, false)
+		if err == nil {
+			result.Action = hvs.FlavorImportActionReuse
+		} else if _, ok := err.(*commErr.StatusNotFoundError); !ok {
+			return nil, errors.Wrapf(err, "Failed to retrieve flavor template %s", flavorTemplate.ID)
+		} else if _, err = fcon.FTStore.Retrieve(flavorTemplate.ID, true); err == nil {
+			result.Action = hvs.FlavorImportActionSkip
+			result.Message = "The flavor template has been deleted from this HVS"
+		} else if _, ok := err.(*commErr.StatusNotFoundError); !ok {
+			return nil, errors.Wrapf(err, "Failed to retrieve flavor template %s", flavorTemplate.ID)
+		} else {
+			result.Action = hvs.FlavorImportActionCreate
+			plan.flavorTemplates = append(plan.flavorTemplates, flavorTemplate)
+		}
+		importedTemplates[flavorTemplate.ID] = result.Action != hvs.FlavorImportActionSkip
+		plan.report.FlavorTemplates = append(plan.report.FlavorTemplates, result)
+	}
+
+	for _, flavorgroup := range content.Flavorgroups {
+		result := hvs.FlavorImportResult{ID: flavorgroup.ID, Name: flavorgroup.Name}
+		existing, err := fcon.FGStore.Search(&dm.FlavorGroupFilterCriteria{NameEqualTo: flavorgroup.Name})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to search flavorgroup %s", flavorgroup.Name)
+		}
+		var importedFlavorgroup hvs.FlavorGroup
+		if len(existing) > 0 {
+			result.Action = hvs.FlavorImportActionReuse
+			result.ExistingID = &existing[0].ID
+			importedFlavorgroup = hvs.FlavorGroup{ID: existing[0].ID, Name: existing[0].Name}
+		} else {
+			result.Action = hvs.FlavorImportActionCreate
+			importedFlavorgroup = utils.CreateFlavorGroupByName(flavorgroup.Name)
+			if len(flavorgroup.MatchPolicies) > 0 {
+				importedFlavorgroup.MatchPolicies = flavorgroup.MatchPolicies
+			}
+		}
+		for _, flavorId := range flavorgroup.FlavorIds {
+			if importedId, ok := importedFlavorIds[flavorId]; ok {
+				importedFlavorgroup.FlavorIds = append(importedFlavorgroup.FlavorIds, importedId)
+			}
+		}
+		for _, flavorTemplateId := range flavorgroup.FlavorTemplateIds {
+			if importedTemplates[flavorTemplateId] {
+				importedFlavorgroup.FlavorTemplateIds = append(importedFlavorgroup.FlavorTemplateIds, flavorTemplateId)
+			}
+		}
+		plan.flavorgroups = append(plan.flavorgroups, importedFlavorgroup)
+		plan.report.Flavorgroups = append(plan.report.Flavorgroups, result)
+	}
+	return &plan, nil
+}
+
+func (fcon *FlavorController) applyFlavorImport(plan *flavorImportPlan) error {
+	defaultLog.Trace("controllers/flavor_controller:applyFlavorImport() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:applyFlavorImport() Leaving")
+
+	// the hosts of the replaced flavors and of their flavor groups are verified again once the bundle is imported
+	var hostIdsForQueue []uuid.UUID
+	var replacedFlavorgroups []hvs.FlavorGroup
+	queuedFlavorgroups := make(map[uuid.UUID]bool)
+	for _, flavorgroup := range plan.flavorgroups {
+		if flavorgroup.ID != uuid.Nil {
+			queuedFlavorgroups[flavorgroup.ID] = true
+		}
+	}
+	for _, replacedFlavor := range plan.replacedFlavors {
+		replacedFlavorId := replacedFlavor.Flavor.Meta.ID
+		hostIds, err := getHostsAssociatedWithFlavor(fcon.HStore, fcon.FGStore, &replacedFlavor)
+		if err != nil {
+			return err
+		}
+		hostIdsForQueue = append(hostIdsForQueue, hostIds...)
+		flavorgroups, err := fcon.FGStore.Search(&dm.FlavorGroupFilterCriteria{FlavorId: &replacedFlavorId})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to retrieve the flavorgroups of flavor %s", replacedFlavorId)
+		}
+		for _, flavorgroup := range flavorgroups {
+			if !queuedFlavorgroups[flavorgroup.ID] {
+				queuedFlavorgroups[flavorgroup.ID] = true
+				replacedFlavorgroups = append(replacedFlavorgroups, hvs.FlavorGroup{ID: flavorgroup.ID, Name: flavorgroup.Name})
+			}
+		}
+	}
+
+	// the new flavors and templates are created before any existing flavor is replaced, each replacement deletes the
+	// replaced flavor and stores the flavor of the bundle in a single transaction
+	for i := range plan.flavors {
+		if _, ok := plan.replacedFlavors[plan.flavors[i].Flavor.Meta.ID]; ok {
+			continue
+		}
+		if _, err := fcon.FStore.Create(&plan.flavors[i]); err != nil {
+			return errors.Wrapf(err, "Failed to create flavor %s", plan.flavors[i].Flavor.Meta.ID)
+		}
+	}
+	for i := range plan.flavorTemplates {
+		if _, err := fcon.FTStore.Create(&plan.flavorTemplates[i]); err != nil {
+			return errors.Wrapf(err, "Failed to create flavor template %s", plan.flavorTemplates[i].ID)
+		}
+	}
+	for i := range plan.flavors {
+		replacedFlavor, ok := plan.replacedFlavors[plan.flavors[i].Flavor.Meta.ID]
+		if !ok {
+			continue
+		}
+		if err := fcon.FStore.Replace(replacedFlavor.Flavor.Meta.ID, &plan.flavors[i]); err != nil {
+			return errors.Wrapf(err, "Failed to replace flavor %s", replacedFlavor.Flavor.Meta.ID)
+		}
+	}
+
+	for i := range plan.flavorgroups {
+		flavorgroup := &plan.flavorgroups[i]
+		flavorIds := flavorgroup.FlavorIds
+		flavorTemplateIds := flavorgroup.FlavorTemplateIds
+		if flavorgroup.ID == uuid.Nil {
+			newFlavorgroup := hvs.FlavorGroup{Name: flavorgroup.Name, MatchPolicies: flavorgroup.MatchPolicies}
+			if _, err := fcon.FGStore.Create(&newFlavorgroup); err != nil {
+				return errors.Wrapf(err, "Failed to create flavorgroup %s", flavorgroup.Name)
+			}
+			flavorgroup.ID = newFlavorgroup.ID
+		} else {
+			linkedFlavorIds, err := fcon.FGStore.SearchFlavors(flavorgroup.ID)
+			if err != nil && !strings.Contains(err.Error(), commErr.RowsNotFound) {
+				return errors.Wrapf(err, "Failed to retrieve the flavors of flavorgroup %s", flavorgroup.Name)
+			}
+			flavorIds = excludeIds(flavorIds, linkedFlavorIds)
+			linkedTemplateIds, err := fcon.FGStore.SearchFlavorTemplatesByFlavorGroup(flavorgroup.ID)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to retrieve the flavor templates of flavorgroup %s", flavorgroup.Name)
+			}
+			flavorTemplateIds = excludeIds(flavorTemplateIds, linkedTemplateIds)
+		}
+		if len(flavorIds) > 0 {
+			if _, err := fcon.FGStore.AddFlavors(flavorgroup.ID, flavorIds); err != nil {
+				return errors.Wrapf(err, "Failed to link flavors to flavorgroup %s", flavorgroup.Name)
+			}
+		}
+		if len(flavorTemplateIds) > 0 {
+			if err := fcon.FGStore.AddFlavorTemplates(flavorgroup.ID, flavorTemplateIds); err != nil {
+				return errors.Wrapf(err, "Failed to link flavor templates to flavorgroup %s", flavorgroup.Name)
+			}
+		}
+	}
+	flavorgroups := append(plan.flavorgroups, replacedFlavorgroups...)
+
+	if fcon.HTManager != nil {
+		if len(hostIdsForQueue) > 0 {
+			if err := verifyHostsOnFlavorChange(fcon.HTManager, fcon.HStore, fcon.FGStore, hostIdsForQueue, false); err != nil {
+				return errors.Wrap(err, "Failed to add the hosts of the replaced flavors to the flavor-verify queue")
+			}
+		}
+		go fcon.addFlavorgroupHostsToFlavorVerifyQueue(flavorgroups, nil, false)
+	}
+	return nil
+}
+
+// retrieveExistingFlavor returns the stored flavor having the ID or the label of the given flavor, nil when none does
+func (fcon *FlavorController) retrieveExistingFlavor(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
+	existing, err := fcon.FStore.Retrieve(signedFlavor.Flavor.Meta.ID)
+	if err == nil {
+		return existing, nil
+	}
+	if !strings.Contains(err.Error(), commErr.RowsNotFound) {
+		return nil, errors.Wrapf(err, "Failed to retrieve flavor %s", signedFlavor.Flavor.Meta.ID)
+	}
+	signedFlavors, err := fcon.FStore.Search(&dm.FlavorVerificationFC{
+		FlavorFC: dm.FlavorFilterCriteria{Key: hvs.Label, Value: signedFlavor.Flavor.Meta.Description[hvs.Label].(string)},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to search the flavors with the label of flavor %s", signedFlavor.Flavor.Meta.ID)
+	}
+	if len(signedFlavors) > 0 {
+		return &signedFlavors[0], nil
+	}
+	return nil, nil
+}
+
+// saveImportedFlavorSigningCertificates stores the certificates a flavor bundle is signed with, they verify the
+// signature of the imported flavors when the hosts are verified
+func (fcon *FlavorController) saveImportedFlavorSigningCertificates(certificates []x509.Certificate) error {
+	defaultLog.Trace("controllers/flavor_controller:saveImportedFlavorSigningCertificates() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:saveImportedFlavorSigningCertificates() Leaving")
+
+	_, flavorCACertificates, _ := (*fcon.CertStore).GetKeyAndCertificates(dm.CaCertTypesFlavorCa.String())
+	_, importedCertificates, _ := (*fcon.CertStore).GetKeyAndCertificates(dm.CertTypesImportedFlavorSigning.String())
+	knownCertificates := append(append([]x509.Certificate{}, flavorCACertificates...), importedCertificates...)
+	for i := range certificates {
+		known := false
+		for _, knownCertificate := range knownCertificates {
+			if knownCertificate.Equal(&certificates[i]) {
+				known = true
+				break
+			}
+		}
+		if known {
+			continue
+		}
+		fingerprint := sha256.Sum256(certificates[i].Raw)
+		if err := fcon.CertStore.AddCertificatesToStore(dm.CertTypesImportedFlavorSigning.String(),
+			hex.EncodeToString(fingerprint[:]), &certificates[i]); err != nil {
+			return errors.Wrap(err, "Failed to store imported flavor signing certificate")
+		}
+	}
+	return nil
+}
+
+// searchFlavorgroupFlavors returns the flavors linked to a flavor group
+func (fcon *FlavorController) searchFlavorgroupFlavors(flavorgroupId uuid.UUID) ([]hvs.SignedFlavor, error) {
+	flavorIds, err := fcon.FGStore.SearchFlavors(flavorgroupId)
+	if err != nil && !strings.Contains(err.Error(), commErr.RowsNotFound) {
+		return nil, err
+	}
+	if len(flavorIds) == 0 {
+		return nil, nil
+	}
+	return fcon.FStore.Search(&dm.FlavorVerificationFC{
+		FlavorFC: dm.FlavorFilterCriteria{Ids: flavorIds},
+	})
+}
+
+func validateFlavorBundleContent(content *hvs.FlavorBundleContent) error {
+	defaultLog.Trace("controllers/flavor_controller:validateFlavorBundleContent() Entering")
+	defer defaultLog.Trace("controllers/flavor_controller:validateFlavorBundleContent() Leaving")
+
+	for _, signedFlavor := range content.SignedFlavors {
+		if err := validateFlavorMetaContent(&signedFlavor.Flavor.Meta); err != nil {
+			return err
+		}
+		if isHostBoundFlavor(&signedFlavor.Flavor) {
+			return errors.New("HOST_UNIQUE and ASSET_TAG flavors cannot be imported")
+		}
+	}
+	for _, flavorgroup := range content.Flavorgroups {
+		if flavorgroup.Name == "" || validation.ValidateStrings([]string{flavorgroup.Name}) != nil {
+			return errors.New("Valid flavorgroup names must be specified")
+		}
+		if flavorgroup.Name == dm.FlavorGroupsHostUnique.String() {
+			return errors.New("The host_unique flavorgroup cannot be imported")
+		}
+	}
+	for _, flavorTemplate := range content.FlavorTemplates {
+		if flavorTemplate.ID == uuid.Nil || validation.ValidateStrings([]string{flavorTemplate.Label}) != nil {
+			return errors.New("Valid flavor template IDs and labels must be specified")
+		}
+	}
+	return nil
+}
+
+func parseExportIds(ids []string, name string) ([]uuid.UUID, error) {
+	var parsedIds []uuid.UUID
+	for _, id := range ids {
+		parsedId, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errors.Errorf("Invalid UUID format of the %s identifier", name)
+		}
+		parsedIds = append(parsedIds, parsedId)
+	}
+	return parsedIds, nil
+}
+
+// isHostBoundFlavor returns true for the flavors created for a single host
+func isHostBoundFlavor(flavor *hvs.Flavor) bool {
+	flavorPart, _ := flavor.Meta.Description[hvs.FlavorPartDescription].(string)
+	return flavorPart == hvs.FlavorPartHostUnique.String() || flavorPart == hvs.FlavorPartAssetTag.String()
+}
+
+// excludeIds returns the IDs that are not excluded
+func excludeIds(ids, excludedIds []uuid.UUID) []uuid.UUID {
+	excluded := make(map[uuid.UUID]bool)
+	for _, id := range excludedIds {
+		excluded[id] = true
+	}
+	var remainingIds []uuid.UUID
+	for _, id := range ids {
+		if !excluded[id] {
+			remainingIds = append(remainingIds, id)
+			excluded[id] = true
+		}
+	}
+	return remainingIds
+}
@@ -5,16 +5,27 @@
 package controllers_test
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/json"
+	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	dm "github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
 	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
@@ -22,8 +33,18 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
+// replaceFailingFlavorStore fails to replace the flavors
+type replaceFailingFlavorStore struct {
+	*mocks.MockFlavorStore
+}
+
+func (store replaceFailingFlavorStore) Replace(uuid.UUID, *hvs.SignedFlavor) error {
+	return errors.New("transaction rolled back")
+}
+
 var _ = Describe("FlavorController", func() {
 	var router *mux.Router
 	var w *httptest.ResponseRecorder
@@ -201,6 +222,405 @@ var _ = Describe("FlavorController", func() {
 		})
 	})
 
+	// Specs for HTTP Put to "/flavors/{flavorId}/lifecycle"
+	Describe("Update Flavor lifecycle", func() {
+		Context("Deprecate a Flavor superseded by another Flavor", func() {
+			It("Should update the Flavor lifecycle", func() {
+				router.Handle("/flavors/{id}/lifecycle", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.UpdateLifecycle))).Methods("PUT")
+				body := `{"state": "deprecated", "grace_period_end": "2030-01-01T00:00:00Z", "superseded_by": "e6612219-bbd5-4259-8c7e-991e43729a86"}`
+				req, err := http.NewRequest("PUT", "/flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3/lifecycle", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var sf hvs.SignedFlavor
+				err = json.Unmarshal(w.Body.Bytes(), &sf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sf.Lifecycle.State).To(Equal(hvs.FlavorStateDeprecated))
+				Expect(sf.Lifecycle.SupersededBy.String()).To(Equal("e6612219-bbd5-4259-8c7e-991e43729a86"))
+
+				stored, err := flavorStore.Retrieve(uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.Lifecycle.State).To(Equal(hvs.FlavorStateDeprecated))
+				Expect(stored.Lifecycle.GracePeriodEnd).NotTo(BeNil())
+			})
+		})
+		Context("Provide an invalid Flavor state", func() {
+			It("Should fail to update the Flavor lifecycle", func() {
+				router.Handle("/flavors/{id}/lifecycle", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.UpdateLifecycle))).Methods("PUT")
+				req, err := http.NewRequest("PUT", "/flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3/lifecycle", strings.NewReader(`{"state": "retired"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Supersede a Flavor by a non-existent Flavor", func() {
+			It("Should fail to update the Flavor lifecycle", func() {
+				router.Handle("/flavors/{id}/lifecycle", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.UpdateLifecycle))).Methods("PUT")
+				body := `{"state": "deprecated", "superseded_by": "73755fda-c910-46be-821f-e8ddeab189e9"}`
+				req, err := http.NewRequest("PUT", "/flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3/lifecycle", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Reinstate a revoked Flavor", func() {
+			It("Should fail to update the Flavor lifecycle", func() {
+				err := flavorStore.UpdateLifecycle(uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3"), hvs.FlavorLifecycle{State: hvs.FlavorStateRevoked})
+				Expect(err).NotTo(HaveOccurred())
+
+				router.Handle("/flavors/{id}/lifecycle", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.UpdateLifecycle))).Methods("PUT")
+				req, err := http.NewRequest("PUT", "/flavors/c36b5412-8c02-4e08-8a74-8bfa40425cf3/lifecycle", strings.NewReader(`{"state": "active"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Update the lifecycle of a non-existent Flavor", func() {
+			It("Should fail to update the Flavor lifecycle", func() {
+				router.Handle("/flavors/{id}/lifecycle", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.UpdateLifecycle))).Methods("PUT")
+				req, err := http.NewRequest("PUT", "/flavors/73755fda-c910-46be-821f-e8ddeab189e9/lifecycle", strings.NewReader(`{"state": "revoked"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/flavors/export" and HTTP Post to "/flavors/import"
+	Describe("Export and import Flavor bundles", func() {
+		var caCertificate *x509.Certificate
+		var caKey *rsa.PrivateKey
+		var signingKey *rsa.PrivateKey
+		var importController *controllers.FlavorController
+		var importFlavorStore *mocks.MockFlavorStore
+		var importFlavorgroupStore *mocks.MockFlavorgroupStore
+		var exportedFlavor *hvs.SignedFlavor
+		var exportedFlavorgroup *hvs.FlavorGroup
+		var importedSigningCertsDir string
+
+		BeforeEach(func() {
+			var signingCertificate *x509.Certificate
+			caCertificate, caKey = newFlavorBundleTestCertificate("Flavor CA", nil, nil)
+			signingCertificate, signingKey = newFlavorBundleTestCertificate("Flavor Signing", caCertificate, caKey)
+
+			var err error
+			importedSigningCertsDir, err = ioutil.TempDir("", "imported-flavor-signing")
+			Expect(err).NotTo(HaveOccurred())
+			certStore := mocks.NewFakeCertificatesStore()
+			(*certStore)[dm.CertTypesFlavorSigning.String()].Key = signingKey
+			(*certStore)[dm.CertTypesFlavorSigning.String()].Certificates = []x509.Certificate{*signingCertificate, *caCertificate}
+			(*certStore)[dm.CaCertTypesFlavorCa.String()] = &dm.CertificateStore{
+				Certificates: []x509.Certificate{*caCertificate},
+			}
+			(*certStore)[dm.CertTypesImportedFlavorSigning.String()] = &dm.CertificateStore{
+				CertPath: importedSigningCertsDir + "/",
+			}
+			flavorController.CertStore = certStore
+			flavorController.FTStore = mocks.NewFakeFlavorTemplateStore()
+
+			// the exported flavor is signed by the exporting HVS
+			storedFlavor, err := flavorStore.Retrieve(uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3"))
+			Expect(err).NotTo(HaveOccurred())
+			flavor := storedFlavor.Flavor
+			flavor.Meta.ID = uuid.MustParse("0f6c8c1e-6a3d-4b8c-9c57-3b6d0e3a7f21")
+			flavor.Meta.Description = map[string]interface{}{
+				hvs.Label:                 "site_a_platform",
+				hvs.FlavorPartDescription: hvs.FlavorPartPlatform.String(),
+			}
+			exportedFlavor, err = hvs.NewSignedFlavor(&flavor, signingKey)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = flavorStore.Create(exportedFlavor)
+			Expect(err).NotTo(HaveOccurred())
+			exportedFlavorgroup, err = flavorGroupStore.Create(&hvs.FlavorGroup{
+				Name:          "site-a",
+				MatchPolicies: []hvs.FlavorMatchPolicy{hvs.NewFlavorMatchPolicy(hvs.FlavorPartPlatform, hvs.NewMatchPolicy(hvs.MatchTypeAnyOf, hvs.FlavorRequired))},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = flavorGroupStore.AddFlavors(exportedFlavorgroup.ID, []uuid.UUID{exportedFlavor.Flavor.Meta.ID})
+			Expect(err).NotTo(HaveOccurred())
+
+			importFlavorStore = mocks.NewMockFlavorStore()
+			importFlavorgroupStore = mocks.NewFakeFlavorgroupStore()
+			importController = &controllers.FlavorController{
+				FStore:    importFlavorStore,
+				FGStore:   importFlavorgroupStore,
+				HStore:    mocks.NewMockHostStore(),
+				CertStore: certStore,
+				FTStore:   mocks.NewFakeFlavorTemplateStore(),
+			}
+			router.Handle("/flavors/export", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorController.Export))).Methods("GET")
+			router.Handle("/flavors/import", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(importController.Import))).Methods("POST")
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(importedSigningCertsDir)).To(Succeed())
+		})
+
+		exportFlavorgroup := func() []byte {
+			req, err := http.NewRequest("GET", "/flavors/export?flavorgroupId="+exportedFlavorgroup.ID.String(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			return w.Body.Bytes()
+		}
+
+		importBundle := func(query string, bundle []byte) *hvs.FlavorImportReport {
+			req, err := http.NewRequest("POST", "/flavors/import"+query, bytes.NewReader(bundle))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			var report hvs.FlavorImportReport
+			if w.Code == http.StatusOK || w.Code == http.StatusConflict {
+				Expect(json.Unmarshal(w.Body.Bytes(), &report)).To(Succeed())
+			}
+			return &report
+		}
+
+		Context("Export a Flavorgroup", func() {
+			It("Should return a bundle signed by the flavor signing key with the Flavorgroup and its flavors", func() {
+				var bundle hvs.FlavorBundle
+				Expect(json.Unmarshal(exportFlavorgroup(), &bundle)).To(Succeed())
+
+				trustedCAs := x509.NewCertPool()
+				trustedCAs.AddCert(caCertificate)
+				content, _, err := bundle.Verify(trustedCAs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(content.SignedFlavors).To(HaveLen(1))
+				Expect(content.SignedFlavors[0].Signature).To(Equal(exportedFlavor.Signature))
+				Expect(content.Flavorgroups).To(HaveLen(1))
+				Expect(content.Flavorgroups[0].Name).To(Equal("site-a"))
+				Expect(content.Flavorgroups[0].FlavorIds).To(Equal([]uuid.UUID{exportedFlavor.Flavor.Meta.ID}))
+			})
+		})
+		Context("Export a Flavorgroup holding a flavor imported from another HVS", func() {
+			It("Should add the signing certificate of the imported flavor to the bundle", func() {
+				otherSigningCertificate, otherSigningKey := newFlavorBundleTestCertificate("Other HVS Flavor Signing", caCertificate, caKey)
+				flavor := exportedFlavor.Flavor
+				flavor.Meta.ID = uuid.MustParse("5b2f4e43-5a3c-4f3b-9d3e-0cbd6a2a2c11")
+				flavor.Meta.Description = map[string]interface{}{
+					hvs.Label:                 "site_a_imported_platform",
+					hvs.FlavorPartDescription: hvs.FlavorPartPlatform.String(),
+				}
+				importedFlavor, err := hvs.NewSignedFlavor(&flavor, otherSigningKey)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = flavorStore.Create(importedFlavor)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = flavorGroupStore.AddFlavors(exportedFlavorgroup.ID, []uuid.UUID{importedFlavor.Flavor.Meta.ID})
+				Expect(err).NotTo(HaveOccurred())
+				importedSigningStore := (*flavorController.CertStore)[dm.CertTypesImportedFlavorSigning.String()]
+				importedSigningStore.Certificates = append(importedSigningStore.Certificates, *otherSigningCertificate)
+
+				bundleBytes := exportFlavorgroup()
+				var bundle hvs.FlavorBundle
+				Expect(json.Unmarshal(bundleBytes, &bundle)).To(Succeed())
+				trustedCAs := x509.NewCertPool()
+				trustedCAs.AddCert(caCertificate)
+				content, certificates, err := bundle.Verify(trustedCAs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(content.SignedFlavors).To(HaveLen(2))
+				Expect(certificates).To(HaveLen(2))
+				Expect(certificates[1].Raw).To(Equal(otherSigningCertificate.Raw))
+				_, signingCertificates, _ := (*flavorController.CertStore).GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+				Expect(signingCertificates).To(HaveLen(2))
+
+				report := importBundle("", bundleBytes)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(report.Flavors).To(HaveLen(2))
+			})
+		})
+		Context("Export without selecting anything", func() {
+			It("Should fail to export the bundle", func() {
+				req, err := http.NewRequest("GET", "/flavors/export", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Export a non-existent Flavorgroup", func() {
+			It("Should fail to export the bundle", func() {
+				req, err := http.NewRequest("GET", "/flavors/export?flavorgroupId=73755fda-c910-46be-821f-e8ddeab189e9", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Dry run the import of an exported bundle", func() {
+			It("Should report the import without storing the flavors", func() {
+				report := importBundle("?dryRun=true", exportFlavorgroup())
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(report.DryRun).To(BeTrue())
+				Expect(report.Flavors).To(HaveLen(1))
+				Expect(report.Flavors[0].Action).To(Equal(hvs.FlavorImportActionCreate))
+				Expect(report.Flavorgroups[0].Action).To(Equal(hvs.FlavorImportActionCreate))
+
+				_, err := importFlavorStore.Retrieve(exportedFlavor.Flavor.Meta.ID)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+		Context("Import an exported bundle", func() {
+			It("Should store the flavors with their original signatures", func() {
+				report := importBundle("", exportFlavorgroup())
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(report.DryRun).To(BeFalse())
+
+				imported, err := importFlavorStore.Retrieve(exportedFlavor.Flavor.Meta.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(imported.Signature).To(Equal(exportedFlavor.Signature))
+				flavorgroups, err := importFlavorgroupStore.Search(&dm.FlavorGroupFilterCriteria{NameEqualTo: "site-a"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorgroups).To(HaveLen(1))
+				Expect(importFlavorgroupStore.FlavorgroupFlavorStore[flavorgroups[0].ID]).To(Equal([]uuid.UUID{exportedFlavor.Flavor.Meta.ID}))
+
+				// the signing certificate is kept to verify the imported flavors, the flavor CA is already trusted
+				_, importedCertificates, _ := (*importController.CertStore).GetKeyAndCertificates(dm.CertTypesImportedFlavorSigning.String())
+				Expect(importedCertificates).To(HaveLen(1))
+				Expect(importedCertificates[0].Subject.CommonName).To(Equal("Flavor Signing"))
+
+				// importing the bundle again reuses the imported flavors
+				report = importBundle("", exportFlavorgroup())
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(report.Flavors[0].Action).To(Equal(hvs.FlavorImportActionReuse))
+				Expect(report.Flavorgroups[0].Action).To(Equal(hvs.FlavorImportActionReuse))
+			})
+		})
+		Context("Import a bundle signed by an untrusted CA", func() {
+			It("Should fail to import the bundle", func() {
+				otherCaCertificate, otherCaKey := newFlavorBundleTestCertificate("Other CA", nil, nil)
+				otherSigningCertificate, otherSigningKey := newFlavorBundleTestCertificate("Other Signing", otherCaCertificate, otherCaKey)
+				signedFlavor, err := hvs.NewSignedFlavor(&exportedFlavor.Flavor, otherSigningKey)
+				Expect(err).NotTo(HaveOccurred())
+				bundle, err := hvs.NewFlavorBundle(hvs.FlavorBundleContent{SignedFlavors: []hvs.SignedFlavor{*signedFlavor}},
+					otherSigningKey, []x509.Certificate{*otherSigningCertificate, *otherCaCertificate})
+				Expect(err).NotTo(HaveOccurred())
+				bundleBytes, err := json.Marshal(bundle)
+				Expect(err).NotTo(HaveOccurred())
+
+				importBundle("", bundleBytes)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Import a bundle holding a certificate that is not issued by a trusted CA", func() {
+			It("Should import the bundle without keeping the untrusted certificate", func() {
+				otherCaCertificate, otherCaKey := newFlavorBundleTestCertificate("Other CA", nil, nil)
+				otherSigningCertificate, _ := newFlavorBundleTestCertificate("Other Signing", otherCaCertificate, otherCaKey)
+				signingStore := (*flavorController.CertStore)[dm.CertTypesFlavorSigning.String()]
+				signingStore.Certificates = append(signingStore.Certificates, *otherSigningCertificate, *otherCaCertificate)
+
+				importBundle("", exportFlavorgroup())
+				Expect(w.Code).To(Equal(http.StatusOK))
+				_, importedCertificates, _ := (*importController.CertStore).GetKeyAndCertificates(dm.CertTypesImportedFlavorSigning.String())
+				Expect(importedCertificates).To(HaveLen(1))
+				Expect(importedCertificates[0].Subject.CommonName).To(Equal("Flavor Signing"))
+			})
+		})
+		Context("Import a bundle with an invalid conflict mode", func() {
+			It("Should fail to import the bundle", func() {
+				importBundle("?conflict=merge", exportFlavorgroup())
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Import a bundle when the imported flavor signing certificate store is not configured", func() {
+			It("Should fail to import the bundle", func() {
+				bundleBytes := exportFlavorgroup()
+				delete(*importController.CertStore, dm.CertTypesImportedFlavorSigning.String())
+
+				importBundle("", bundleBytes)
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+				Expect(w.Body.String()).To(ContainSubstring("No imported flavor signing certificate store is configured"))
+			})
+		})
+		Context("Import a bundle with a flavor conflicting with an existing flavor", func() {
+			var conflictingBundle []byte
+			existingId := uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3")
+
+			BeforeEach(func() {
+				existing, err := importFlavorStore.Retrieve(existingId)
+				Expect(err).NotTo(HaveOccurred())
+				signedFlavor, err := hvs.NewSignedFlavor(&existing.Flavor, signingKey)
+				Expect(err).NotTo(HaveOccurred())
+				signingKey, signingCertificates, _ := (*importController.CertStore).GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+				bundle, err := hvs.NewFlavorBundle(hvs.FlavorBundleContent{
+					SignedFlavors: []hvs.SignedFlavor{*signedFlavor},
+					Flavorgroups:  []hvs.FlavorGroup{{Name: "site-b", FlavorIds: []uuid.UUID{existingId}}},
+				}, signingKey.(*rsa.PrivateKey), signingCertificates)
+				Expect(err).NotTo(HaveOccurred())
+				conflictingBundle, err = json.Marshal(bundle)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should reject the import by default", func() {
+				report := importBundle("", conflictingBundle)
+				Expect(w.Code).To(Equal(http.StatusConflict))
+				Expect(report.Flavors[0].Action).To(Equal(hvs.FlavorImportActionConflict))
+				flavorgroups, err := importFlavorgroupStore.Search(&dm.FlavorGroupFilterCriteria{NameEqualTo: "site-b"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorgroups).To(BeEmpty())
+			})
+			It("Should keep the existing flavor when skipping conflicts", func() {
+				existing, err := importFlavorStore.Retrieve(existingId)
+				Expect(err).NotTo(HaveOccurred())
+
+				report := importBundle("?conflict=skip", conflictingBundle)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(report.Flavors[0].Action).To(Equal(hvs.FlavorImportActionSkip))
+				stored, err := importFlavorStore.Retrieve(existingId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.Signature).To(Equal(existing.Signature))
+				flavorgroups, err := importFlavorgroupStore.Search(&dm.FlavorGroupFilterCriteria{NameEqualTo: "site-b"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(importFlavorgroupStore.FlavorgroupFlavorStore[flavorgroups[0].ID]).To(Equal([]uuid.UUID{existingId}))
+			})
+			It("Should store the flavor of the bundle when replacing conflicts", func() {
+				report := importBundle("?conflict=replace", conflictingBundle)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(report.Flavors[0].Action).To(Equal(hvs.FlavorImportActionReplace))
+				Expect(*report.Flavors[0].ExistingID).To(Equal(existingId))
+				var bundle hvs.FlavorBundle
+				Expect(json.Unmarshal(conflictingBundle, &bundle)).To(Succeed())
+				var content hvs.FlavorBundleContent
+				Expect(json.Unmarshal(bundle.Content, &content)).To(Succeed())
+				stored, err := importFlavorStore.Retrieve(existingId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.Signature).To(Equal(content.SignedFlavors[0].Signature))
+			})
+			It("Should keep the existing flavor when its replacement fails", func() {
+				existing, err := importFlavorStore.Retrieve(existingId)
+				Expect(err).NotTo(HaveOccurred())
+				importController.FStore = replaceFailingFlavorStore{importFlavorStore}
+
+				importBundle("?conflict=replace", conflictingBundle)
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+				stored, err := importFlavorStore.Retrieve(existingId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.Signature).To(Equal(existing.Signature))
+			})
+		})
+	})
+
 	// Specs for HTTP Post to "/flavor"
 	Describe("Create a new flavor", func() {
 		Context("Provide a invalid Create request with XSS Attack Strings", func() {
@@ -822,3 +1242,27 @@ var _ = Describe("FlavorController", func() {
 		})
 	})
 })
+
+// newFlavorBundleTestCertificate issues a certificate for a new RSA key, the certificate is a self signed CA
+// certificate when no issuer is given
+func newFlavorBundleTestCertificate(name string, issuer *x509.Certificate, issuerKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  issuer == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if issuer == nil {
+		issuer, issuerKey = template, key
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	Expect(err).NotTo(HaveOccurred())
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	Expect(err).NotTo(HaveOccurred())
+	return certificate, key
+}
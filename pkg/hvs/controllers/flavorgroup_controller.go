@@ -5,9 +5,12 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/utils"
@@ -18,6 +21,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/pkg/errors"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -27,10 +31,14 @@ type FlavorgroupController struct {
 	FlavorTemplateStore domain.FlavorTemplateStore
 	FlavorStore         domain.FlavorStore
 	HostStore           domain.HostStore
+	HostStatusStore     domain.HostStatusStore
 	HTManager           domain.HostTrustManager
+	// JobManager, when set, runs the trust simulation as a job for the clients asking for an asynchronous response
+	JobManager domain.JobManager
 }
 
-var flavorGroupSearchParams = map[string]bool{"id": true, "nameEqualTo": true, "nameContains": true, "includeFlavorContent": true}
+var flavorGroupSearchParams = map[string]bool{"id": true, "nameEqualTo": true, "nameContains": true, "includeFlavorContent": true,
+	"limit": true, "after": true, "fields": true}
 
 func (controller FlavorgroupController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/flavorgroup_controller:Create() Entering")
@@ -76,6 +84,11 @@ func (controller FlavorgroupController) Create(w http.ResponseWriter, r *http.Re
 		return nil, http.StatusInternalServerError, errors.Errorf("Error while inserting a new Flavorgroup")
 	}
 
+	if err := controller.linkSelectedHosts(newFlavorGroup); err != nil {
+		defaultLog.WithError(err).Error("controllers/flavorgroup_controller:Create() Flavorgroup Host association failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Flavorgroup with hosts"}
+	}
+
 	secLog.WithField("Name", reqFlavorGroup.Name).Infof("%s: FlavorGroup created by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
 	return newFlavorGroup, http.StatusCreated, nil
 }
@@ -121,6 +134,19 @@ func (controller FlavorgroupController) Search(w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	limit, after, err := getPageCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:Search()  %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if limit > 0 || after != uuid.Nil {
+		if filter == nil {
+			filter = &models.FlavorGroupFilterCriteria{}
+		}
+		filter.Limit = limit
+		filter.After = after
+	}
+
 	flavorgroups, err := controller.FlavorGroupStore.Search(filter)
 	if err != nil {
 		secLog.WithError(err).Error("controllers/flavorgroup_controller:Search() Flavorgroup get all failed")
@@ -133,9 +159,18 @@ func (controller FlavorgroupController) Search(w http.ResponseWriter, r *http.Re
 			"associated with flavor group")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{"Unable to search Flavorgroups"}
 	}
+	if len(flavorgroups) > 0 {
+		flavorgroupCollection.Next = nextPageLink(r, limit, len(flavorgroups), flavorgroups[len(flavorgroups)-1].ID)
+	}
+
+	result, err := selectFields(r.URL.Query(), flavorgroupCollection)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:Search()  %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
 
 	secLog.Infof("%s: Return flavorgroup query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
-	return flavorgroupCollection, http.StatusOK, nil
+	return result, http.StatusOK, nil
 }
 
 func (controller FlavorgroupController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -242,6 +277,18 @@ func (controller FlavorgroupController) ValidateFlavorGroup(flavorGroup hvs.Flav
 	if len(flavorGroup.MatchPolicies) == 0 {
 		return errors.New("Flavor Type Match Policy Collection must be specified")
 	}
+
+	if flavorGroup.RefreshSchedule != nil {
+		if err := validateFlavorgroupRefreshSchedule(flavorGroup.RefreshSchedule); err != nil {
+			return errors.Wrap(err, "Valid refresh schedule must be specified")
+		}
+	}
+
+	if flavorGroup.HostSelector != "" {
+		if _, err := hvs.ParseLabelSelector(flavorGroup.HostSelector); err != nil {
+			return errors.Wrap(err, "Valid host selector must be specified")
+		}
+	}
 	return nil
 }
 
@@ -349,8 +396,9 @@ func (controller FlavorgroupController) AddFlavor(w http.ResponseWriter, r *http
 		return nil, http.StatusInternalServerError, errors.Errorf("Error while inserting a new Flavorgroup-Flavor link")
 	}
 
-	// Since the host has been updated, add it to the verify queue
-	err = controller.HTManager.VerifyHostsAsync(linkedHosts, false, false)
+	// Since the host has been updated, add it to the verify queue, fetching new host data if the refresh schedule
+	// of the host asks for it
+	err = verifyHostsOnFlavorChange(controller.HTManager, controller.HostStore, controller.FlavorGroupStore, linkedHosts, false)
 	if err != nil {
 		defaultLog.WithError(err).WithField("linkedHosts", linkedHosts).Error("controllers/host_controller:AddFlavor() Addition of Host to Flavor Verify Queue failed")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while inserting a new Flavorgroup-Flavor link"}
@@ -405,8 +453,9 @@ func (controller FlavorgroupController) RemoveFlavor(w http.ResponseWriter, r *h
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while removing Flavorgroup-Flavor links"}
 	}
 
-	// Since the host has been updated, add it to the verify queue
-	err = controller.HTManager.VerifyHostsAsync(linkedHosts, false, false)
+	// Since the host has been updated, add it to the verify queue, fetching new host data if the refresh schedule
+	// of the host asks for it
+	err = verifyHostsOnFlavorChange(controller.HTManager, controller.HostStore, controller.FlavorGroupStore, linkedHosts, false)
 	if err != nil {
 		defaultLog.WithError(err).WithField("linkedHosts", linkedHosts).Error("controllers/host_controller:RemoveFlavor() Addition of Host to Flavor Verify Queue failed")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while removing Flavorgroup-Flavor links"}
@@ -490,6 +539,213 @@ func (controller FlavorgroupController) RetrieveFlavor(w http.ResponseWriter, r
 	return fgl, http.StatusOK, nil
 }
 
+// UpdateRefreshSchedule sets the report refresh schedule of a FlavorGroup
+func (controller FlavorgroupController) UpdateRefreshSchedule(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:UpdateRefreshSchedule() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:UpdateRefreshSchedule() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavorgroup_controller:UpdateRefreshSchedule() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var schedule hvs.RefreshSchedule
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&schedule)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:UpdateRefreshSchedule() %s :  Failed to decode request body as RefreshSchedule", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateFlavorgroupRefreshSchedule(&schedule); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:UpdateRefreshSchedule() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	if status, err := controller.setRefreshSchedule(fgID, &schedule); err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: FlavorGroup refresh schedule updated by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return schedule, http.StatusOK, nil
+}
+
+// validateFlavorgroupRefreshSchedule validates the refresh schedule of a FlavorGroup, which is removed rather than
+// disabled
+func validateFlavorgroupRefreshSchedule(schedule *hvs.RefreshSchedule) error {
+	if schedule.Disabled {
+		return errors.New("The refresh schedule of a flavor group cannot be disabled, it must be deleted instead")
+	}
+	return schedule.Validate()
+}
+
+// DeleteRefreshSchedule removes the report refresh schedule of a FlavorGroup
+func (controller FlavorgroupController) DeleteRefreshSchedule(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:DeleteRefreshSchedule() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:DeleteRefreshSchedule() Leaving")
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	if status, err := controller.setRefreshSchedule(fgID, nil); err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: FlavorGroup refresh schedule deleted by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+func (controller FlavorgroupController) setRefreshSchedule(fgID uuid.UUID, schedule *hvs.RefreshSchedule) (int, error) {
+	_, err := controller.FlavorGroupStore.Retrieve(fgID)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:setRefreshSchedule() %s : FlavorGroup %s does not exist", commLogMsg.AppRuntimeErr, fgID)
+			return http.StatusNotFound, &commErr.ResourceError{Message: "FlavorGroup does not exist"}
+		}
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:setRefreshSchedule() %s : Error retrieving FlavorGroup", commLogMsg.AppRuntimeErr)
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update FlavorGroup refresh schedule"}
+	}
+
+	if err := controller.FlavorGroupStore.UpdateRefreshSchedule(fgID, schedule); err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:setRefreshSchedule() %s : FlavorGroup refresh schedule update failed", commLogMsg.AppRuntimeErr)
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update FlavorGroup refresh schedule"}
+	}
+	return http.StatusOK, nil
+}
+
+// UpdateHostSelector sets the label selector of the hosts a FlavorGroup is automatically linked to and links the
+// FlavorGroup to the hosts already matching it
+func (controller FlavorgroupController) UpdateHostSelector(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:UpdateHostSelector() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:UpdateHostSelector() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavorgroup_controller:UpdateHostSelector() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqSelector hvs.FlavorgroupHostSelector
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&reqSelector)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:UpdateHostSelector() %s :  Failed to decode request body as FlavorgroupHostSelector", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if strings.TrimSpace(reqSelector.HostSelector) == "" {
+		secLog.Errorf("controllers/flavorgroup_controller:UpdateHostSelector() %s : Empty host selector", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Host selector must be specified"}
+	}
+	if _, err := hvs.ParseLabelSelector(reqSelector.HostSelector); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:UpdateHostSelector() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	flavorGroup, status, err := controller.setHostSelector(fgID, reqSelector.HostSelector)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if err := controller.linkSelectedHosts(flavorGroup); err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Error("controllers/flavorgroup_controller:UpdateHostSelector() Flavorgroup Host association failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Flavorgroup with hosts"}
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: FlavorGroup host selector updated by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return reqSelector, http.StatusOK, nil
+}
+
+// DeleteHostSelector removes the host selector of a FlavorGroup, the hosts linked to the FlavorGroup stay linked
+func (controller FlavorgroupController) DeleteHostSelector(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:DeleteHostSelector() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:DeleteHostSelector() Leaving")
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	if _, status, err := controller.setHostSelector(fgID, ""); err != nil {
+		return nil, status, err
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: FlavorGroup host selector deleted by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+func (controller FlavorgroupController) setHostSelector(fgID uuid.UUID, hostSelector string) (*hvs.FlavorGroup, int, error) {
+	flavorGroup, err := controller.FlavorGroupStore.Retrieve(fgID)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:setHostSelector() %s : FlavorGroup %s does not exist", commLogMsg.AppRuntimeErr, fgID)
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "FlavorGroup does not exist"}
+		}
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:setHostSelector() %s : Error retrieving FlavorGroup", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update FlavorGroup host selector"}
+	}
+
+	if err := controller.FlavorGroupStore.UpdateHostSelector(fgID, hostSelector); err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:setHostSelector() %s : FlavorGroup host selector update failed", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update FlavorGroup host selector"}
+	}
+	flavorGroup.HostSelector = hostSelector
+	return flavorGroup, http.StatusOK, nil
+}
+
+// linkSelectedHosts links the FlavorGroup to the hosts matching its host selector and queues the newly linked hosts
+// for verification. Links are only ever added, a host stays linked to the FlavorGroup when its labels no longer match
+// the selector.
+func (controller FlavorgroupController) linkSelectedHosts(flavorGroup *hvs.FlavorGroup) error {
+	defaultLog.Trace("controllers/flavorgroup_controller:linkSelectedHosts() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:linkSelectedHosts() Leaving")
+
+	if flavorGroup.HostSelector == "" {
+		return nil
+	}
+	selector, err := hvs.ParseLabelSelector(flavorGroup.HostSelector)
+	if err != nil {
+		return errors.Wrap(err, "Invalid host selector")
+	}
+
+	hosts, err := controller.HostStore.Search(&models.HostFilterCriteria{LabelSelector: selector}, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error searching the hosts matching the host selector")
+	}
+	linkedHostIds, err := controller.FlavorGroupStore.SearchHostsByFlavorGroup(flavorGroup.ID)
+	if err != nil {
+		return errors.Wrap(err, "Error searching the hosts linked to the flavorgroup")
+	}
+	linkedHosts := make(map[uuid.UUID]bool, len(linkedHostIds))
+	for _, hostId := range linkedHostIds {
+		linkedHosts[hostId] = true
+	}
+
+	var newHostIds []uuid.UUID
+	for _, host := range hosts {
+		if linkedHosts[host.Id] {
+			continue
+		}
+		if err := controller.HostStore.AddFlavorgroups(host.Id, []uuid.UUID{flavorGroup.ID}); err != nil {
+			return errors.Wrapf(err, "Error linking host %s to the flavorgroup", host.Id)
+		}
+		newHostIds = append(newHostIds, host.Id)
+	}
+
+	if len(newHostIds) == 0 {
+		return nil
+	}
+	defaultLog.Debugf("Linked flavorgroup %s with hosts %+q", flavorGroup.Name, newHostIds)
+	return verifyHostsOnFlavorChange(controller.HTManager, controller.HostStore, controller.FlavorGroupStore, newHostIds, false)
+}
+
 func (controller FlavorgroupController) getAssociatedFlavorTemplates(flavorGroupID uuid.UUID) ([]uuid.UUID, error) {
 	defaultLog.Trace("controllers/flavorgroup_controller:getAssociatedFlavorTemplates() Entering")
 	defer defaultLog.Trace("controllers/flavorgroup_controller:getAssociatedFlavorTemplates() Leaving")
@@ -536,3 +792,243 @@ func (controller FlavorgroupController) getAssociatedFlavorAndTemplates(flavorgr
 	flavorgroupCollection := &hvs.FlavorgroupCollection{Flavorgroups: flavorgroupList}
 	return flavorgroupCollection, nil
 }
+
+// Simulate predicts which hosts linked to a FlavorGroup would change their trust status if candidate flavors were
+// added to it or its match policies were replaced. The hosts are evaluated from their last stored host manifest and
+// nothing is persisted: the FlavorGroup, the trust cache and the reports of the hosts are left untouched. Only the
+// FlavorGroups linked to a few hosts are simulated synchronously, the others must be simulated by a job.
+func (controller FlavorgroupController) Simulate(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:Simulate() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:Simulate() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavorgroup_controller:Simulate() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var simulationRequest hvs.FlavorgroupSimulationRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&simulationRequest)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:Simulate() %s :  Failed to decode request body as FlavorgroupSimulationRequest", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateFlavorgroupSimulationRequest(simulationRequest); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavorgroup_controller:Simulate() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	flavorGroup, err := controller.FlavorGroupStore.Retrieve(fgID)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:Simulate() %s : FlavorGroup %s does not exist", commLogMsg.AppRuntimeErr, fgID)
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "FlavorGroup does not exist"}
+		}
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:Simulate() %s : Error retrieving FlavorGroup", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to simulate FlavorGroup changes"}
+	}
+
+	candidateFlavors, status, err := controller.getCandidateFlavors(simulationRequest)
+	if err != nil {
+		return nil, status, err
+	}
+
+	candidateFlavorGroup := *flavorGroup
+	if len(simulationRequest.MatchPolicies) > 0 {
+		candidateFlavorGroup.MatchPolicies = simulationRequest.MatchPolicies
+	}
+
+	linkedHosts, err := controller.FlavorGroupStore.SearchHostsByFlavorGroup(fgID)
+	if err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:Simulate() %s : Failed to fetch hosts linked to FlavorGroup", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to simulate FlavorGroup changes"}
+	}
+
+	// each host is evaluated twice, the client can ask for the hosts to be evaluated by a job
+	if controller.JobManager != nil && preferAsync(r) {
+		return submitJob(w, r, controller.JobManager, hvs.JobTypeFlavorgroupSimulate,
+			func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+				return controller.simulateHostsTrust(ctx, linkedHosts, *flavorGroup, candidateFlavorGroup, candidateFlavors, report)
+			})
+	}
+	if len(linkedHosts) > constants.FlavorgroupSimulationMaxHosts {
+		defaultLog.WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:Simulate() %s : Too many hosts linked to FlavorGroup", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: fmt.Sprintf("FlavorGroup is linked to more than %d hosts, the simulation must be requested with the \"%s: %s\" header",
+			constants.FlavorgroupSimulationMaxHosts, preferHeader, preferRespondAsync)}
+	}
+
+	simulationReport, err := controller.simulateHostsTrust(r.Context(), linkedHosts, *flavorGroup, candidateFlavorGroup, candidateFlavors, nil)
+	if err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/flavorgroup_controller:Simulate() %s : FlavorGroup changes simulation interrupted", commLogMsg.AppRuntimeErr)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to simulate FlavorGroup changes"}
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: FlavorGroup changes simulated by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return simulationReport, http.StatusOK, nil
+}
+
+// simulateHostsTrust predicts the trust of the hosts one after the other and reports the progress, if report is set.
+// The hosts left once ctx is done are not evaluated.
+func (controller FlavorgroupController) simulateHostsTrust(ctx context.Context, hostIds []uuid.UUID, flavorGroup, candidateFlavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor, report domain.JobProgressFunc) (*hvs.FlavorgroupSimulationReport, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:simulateHostsTrust() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:simulateHostsTrust() Leaving")
+
+	simulationReport := &hvs.FlavorgroupSimulationReport{
+		FlavorGroupID: flavorGroup.ID,
+		Hosts:         make([]hvs.HostTrustSimulation, 0, len(hostIds)),
+	}
+	for i, hostId := range hostIds {
+		if err := ctx.Err(); err != nil {
+			return simulationReport, err
+		}
+		simulationReport.Hosts = append(simulationReport.Hosts,
+			controller.simulateHostTrust(hostId, flavorGroup, candidateFlavorGroup, candidateFlavors))
+		if report != nil {
+			report(i+1, len(hostIds), simulationReport)
+		}
+	}
+	return simulationReport, nil
+}
+
+// getCandidateFlavors retrieves the existing candidate flavors and wraps the content of the new ones, which are left
+// unsigned: they are verified without checking their signature
+func (controller FlavorgroupController) getCandidateFlavors(simulationRequest hvs.FlavorgroupSimulationRequest) ([]hvs.SignedFlavor, int, error) {
+	defaultLog.Trace("controllers/flavorgroup_controller:getCandidateFlavors() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:getCandidateFlavors() Leaving")
+
+	var candidateFlavors []hvs.SignedFlavor
+	for _, flavorId := range simulationRequest.FlavorIds {
+		signedFlavor, err := controller.FlavorStore.Retrieve(flavorId)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:getCandidateFlavors() %s : Flavor %s does not exist", commLogMsg.AppRuntimeErr, flavorId)
+				return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor " + flavorId.String() + " does not exist"}
+			}
+			defaultLog.WithError(err).WithField("flavor", flavorId).Errorf("controllers/flavorgroup_controller:getCandidateFlavors() %s : Error retrieving flavor", commLogMsg.AppRuntimeErr)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to simulate FlavorGroup changes"}
+		}
+		candidateFlavors = append(candidateFlavors, *signedFlavor)
+	}
+
+	for _, flavor := range simulationRequest.FlavorCollection.Flavors {
+		candidateFlavor := flavor.Flavor
+		// new flavors get their ID when they are created, it never matches a stored flavor
+		candidateFlavor.Meta.ID = uuid.New()
+		candidateFlavors = append(candidateFlavors, hvs.SignedFlavor{Flavor: candidateFlavor})
+	}
+	return candidateFlavors, http.StatusOK, nil
+}
+
+// simulateHostTrust evaluates the trust of a host against the FlavorGroup as it is and with the candidate changes,
+// from the last host manifest stored for the host
+func (controller FlavorgroupController) simulateHostTrust(hostId uuid.UUID, flavorGroup, candidateFlavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) hvs.HostTrustSimulation {
+	defaultLog.Trace("controllers/flavorgroup_controller:simulateHostTrust() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:simulateHostTrust() Leaving")
+
+	hostSimulation := hvs.HostTrustSimulation{HostID: hostId}
+
+	hostStatusCollection, err := controller.HostStatusStore.Search(&models.HostStatusFilterCriteria{
+		HostId:        hostId,
+		LatestPerHost: true,
+		Limit:         1,
+	})
+	if err != nil || len(hostStatusCollection) == 0 || hostStatusCollection[0].HostStatusInformation.HostState != hvs.HostStateConnected {
+		defaultLog.WithError(err).Warnf("controllers/flavorgroup_controller:simulateHostTrust() No host manifest stored for host %s", hostId)
+		hostSimulation.Error = "No host manifest stored for the host, it is not in CONNECTED state"
+		return hostSimulation
+	}
+	hostManifest := &hostStatusCollection[0].HostManifest
+
+	currentReport, err := controller.HTManager.SimulateHostTrust(hostId, hostManifest, flavorGroup, nil)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:simulateHostTrust() %s : Error evaluating the trust of host %s", commLogMsg.AppRuntimeErr, hostId)
+		hostSimulation.Error = "Failed to evaluate the current trust of the host"
+		return hostSimulation
+	}
+	predictedReport, err := controller.HTManager.SimulateHostTrust(hostId, hostManifest, candidateFlavorGroup, candidateFlavors)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("controllers/flavorgroup_controller:simulateHostTrust() %s : Error predicting the trust of host %s", commLogMsg.AppRuntimeErr, hostId)
+		hostSimulation.Error = "Failed to predict the trust of the host"
+		return hostSimulation
+	}
+
+	hostSimulation.CurrentTrusted = &currentReport.Trusted
+	hostSimulation.PredictedTrusted = &predictedReport.Trusted
+	hostSimulation.TrustChanged = currentReport.Trusted != predictedReport.Trusted
+	hostSimulation.UntrustedFlavorParts = getUntrustedFlavorParts(predictedReport)
+	return hostSimulation
+}
+
+// getUntrustedFlavorParts returns the sorted flavor parts of the untrusted rule results of a trust report
+func getUntrustedFlavorParts(trustReport *hvs.TrustReport) []string {
+	untrustedFlavorParts := make(map[string]bool)
+	for _, result := range trustReport.Results {
+		if result.IsTrusted() {
+			continue
+		}
+		for _, marker := range result.Rule.Markers {
+			untrustedFlavorParts[marker.String()] = true
+		}
+	}
+
+	var flavorParts []string
+	for flavorPart := range untrustedFlavorParts {
+		flavorParts = append(flavorParts, flavorPart)
+	}
+	sort.Strings(flavorParts)
+	return flavorParts
+}
+
+func validateFlavorgroupSimulationRequest(simulationRequest hvs.FlavorgroupSimulationRequest) error {
+	defaultLog.Trace("controllers/flavorgroup_controller:validateFlavorgroupSimulationRequest() Entering")
+	defer defaultLog.Trace("controllers/flavorgroup_controller:validateFlavorgroupSimulationRequest() Leaving")
+
+	if len(simulationRequest.FlavorIds) == 0 && len(simulationRequest.FlavorCollection.Flavors) == 0 &&
+		len(simulationRequest.MatchPolicies) == 0 {
+		return errors.New("Candidate flavors or flavor match policies must be specified")
+	}
+	for _, flavorId := range simulationRequest.FlavorIds {
+		if flavorId == uuid.Nil {
+			return errors.New("Valid candidate flavor IDs must be specified")
+		}
+	}
+	for _, flavor := range simulationRequest.FlavorCollection.Flavors {
+		label, _ := flavor.Flavor.Meta.Description[hvs.Label].(string)
+		flavorPartName, _ := flavor.Flavor.Meta.Description[hvs.FlavorPartDescription].(string)
+		var flavorPart hvs.FlavorPartName
+		if label == "" || (&flavorPart).Parse(flavorPartName) != nil {
+			return errors.New("Valid candidate flavor content must be specified, with a flavor label and flavor part")
+		}
+	}
+
+	flavorParts := make(map[hvs.FlavorPartName]bool)
+	for _, policy := range simulationRequest.MatchPolicies {
+		var flavorPart hvs.FlavorPartName
+		if err := (&flavorPart).Parse(policy.FlavorPart.String()); err != nil || flavorPart != policy.FlavorPart {
+			return errors.Errorf("Invalid flavor part %q in flavor match policies", policy.FlavorPart)
+		}
+		if flavorParts[flavorPart] {
+			return errors.Errorf("Flavor part %s has more than one flavor match policy", flavorPart)
+		}
+		flavorParts[flavorPart] = true
+		switch policy.MatchPolicy.MatchType {
+		case hvs.MatchTypeAnyOf, hvs.MatchTypeAllOf, hvs.MatchTypeLatest:
+		default:
+			return errors.Errorf("Invalid match type %q for flavor part %s", policy.MatchPolicy.MatchType, flavorPart)
+		}
+		switch policy.MatchPolicy.Required {
+		case hvs.FlavorRequired, hvs.FlavorRequiredIfDefined:
+		default:
+			return errors.Errorf("Invalid required policy %q for flavor part %s", policy.MatchPolicy.Required, flavorPart)
+		}
+	}
+	return nil
+}
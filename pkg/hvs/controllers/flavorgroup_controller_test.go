@@ -9,13 +9,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
 	mocks2 "github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/jobs"
 	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
@@ -123,6 +126,28 @@ var _ = Describe("FlavorgroupController", func() {
 				Expect(len(fgCollection.Flavorgroups)).To(Equal(2))
 			})
 		})
+		Context("Get all FlavorGroups with the fields parameter", func() {
+			It("Should get list of FlavorGroups with only the selected fields", func() {
+				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/flavorgroups?fields=name,flavor_match_policy_collection", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var fgCollection struct {
+					Flavorgroups []map[string]interface{} `json:"flavorgroups"`
+				}
+				err = json.Unmarshal(w.Body.Bytes(), &fgCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(fgCollection.Flavorgroups)).To(Equal(2))
+				for _, fg := range fgCollection.Flavorgroups {
+					Expect(fg).To(HaveKey("name"))
+					Expect(fg).NotTo(HaveKey("id"))
+				}
+			})
+		})
 		Context("Search FlavorGroups with invalid parameter", func() {
 			It("Should get error:400", func() {
 				router.Handle("/flavorgroups", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Search))).Methods("GET")
@@ -754,6 +779,196 @@ var _ = Describe("FlavorgroupController", func() {
 		})
 	})
 
+	// Specs for HTTP PUT/DELETE to "/flavorgroups/{flavorgroup_id}/refresh-schedule"
+	Describe("Update FlavorGroup refresh schedule", func() {
+		Context("Update the refresh schedule of an existing FlavorGroup", func() {
+			It("Should store the refresh schedule and return 200 response code", func() {
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/refresh-schedule", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateRefreshSchedule))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/refresh-schedule",
+					strings.NewReader(`{"interval_seconds": 3600, "jitter_seconds": 300, "refresh_on_event": true}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				fg, err := flavorgroupStore.Retrieve(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fg.RefreshSchedule).To(Equal(&hvs.RefreshSchedule{IntervalSeconds: 3600, JitterSeconds: 300, RefreshOnEvent: true}))
+			})
+		})
+
+		Context("Update the refresh schedule with a jitter exceeding the interval", func() {
+			It("Should return 400 response code", func() {
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/refresh-schedule", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateRefreshSchedule))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/refresh-schedule",
+					strings.NewReader(`{"interval_seconds": 60, "jitter_seconds": 300}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Disable the refresh schedule of a FlavorGroup", func() {
+			It("Should return 400 response code", func() {
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/refresh-schedule", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateRefreshSchedule))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/refresh-schedule",
+					strings.NewReader(`{"disabled": true}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Update the refresh schedule of a non-existent FlavorGroup", func() {
+			It("Should return 404 response code", func() {
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/refresh-schedule", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateRefreshSchedule))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/9c41f744-cf17-4c53-8d49-888ebb6af99f/refresh-schedule",
+					strings.NewReader(`{"interval_seconds": 3600}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("Delete the refresh schedule of an existing FlavorGroup", func() {
+			It("Should remove the refresh schedule and return 204 response code", func() {
+				fgId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				Expect(flavorgroupStore.UpdateRefreshSchedule(fgId, &hvs.RefreshSchedule{IntervalSeconds: 3600})).To(Succeed())
+
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/refresh-schedule", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(flavorgroupController.DeleteRefreshSchedule))).Methods("DELETE")
+				req, err := http.NewRequest(
+					"DELETE",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/refresh-schedule",
+					nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				fg, err := flavorgroupStore.Retrieve(fgId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fg.RefreshSchedule).To(BeNil())
+			})
+		})
+	})
+
+	// Specs for HTTP PUT/DELETE to "/flavorgroups/{flavorgroup_id}/host-selector"
+	Describe("Update FlavorGroup host selector", func() {
+		Context("Update the host selector of an existing FlavorGroup", func() {
+			It("Should store the host selector, link the matching hosts and return 200 response code", func() {
+				hostId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				host, err := hostStore.Retrieve(hostId, nil)
+				Expect(err).NotTo(HaveOccurred())
+				host.Labels = hvs.Labels{"env": "prod", "rack": "r12"}
+				fgId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				_, err = hostStore.RetrieveFlavorgroup(hostId, fgId)
+				Expect(err).To(HaveOccurred())
+
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/host-selector", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateHostSelector))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/host-selector",
+					strings.NewReader(`{"host_selector": "env=prod,rack!=r3"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				fg, err := flavorgroupStore.Retrieve(fgId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fg.HostSelector).To(Equal("env=prod,rack!=r3"))
+
+				_, err = hostStore.RetrieveFlavorgroup(hostId, fgId)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = hostStore.RetrieveFlavorgroup(uuid.MustParse("e57e5ea0-d465-461e-882d-1600090caa0d"), fgId)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("Update the host selector with an invalid selector", func() {
+			It("Should return 400 response code", func() {
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/host-selector", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateHostSelector))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/host-selector",
+					strings.NewReader(`{"host_selector": "env in (prod"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Update the host selector of a non-existent FlavorGroup", func() {
+			It("Should return 404 response code", func() {
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/host-selector", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.UpdateHostSelector))).Methods("PUT")
+				req, err := http.NewRequest(
+					"PUT",
+					"/flavorgroups/9c41f744-cf17-4c53-8d49-888ebb6af99f/host-selector",
+					strings.NewReader(`{"host_selector": "env=prod"}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("Delete the host selector of an existing FlavorGroup", func() {
+			It("Should remove the host selector and return 204 response code", func() {
+				fgId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				Expect(flavorgroupStore.UpdateHostSelector(fgId, "env=prod")).To(Succeed())
+
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/host-selector", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(flavorgroupController.DeleteHostSelector))).Methods("DELETE")
+				req, err := http.NewRequest(
+					"DELETE",
+					"/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/host-selector",
+					nil,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				fg, err := flavorgroupStore.Retrieve(fgId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fg.HostSelector).To(BeEmpty())
+			})
+		})
+	})
+
 	// FlavorGroupFlavor Search links API tests
 	// Specs for HTTP GET to "flavorgroups/{flavorgroup_id}/flavors"
 	Describe("Search FlavorGroupFlavor Links", func() {
@@ -845,4 +1060,165 @@ var _ = Describe("FlavorgroupController", func() {
 			})
 		})
 	})
+
+	// Specs for HTTP POST to "/flavorgroups/{flavorgroup_id}/simulate"
+	Describe("Simulate FlavorGroup changes", func() {
+		var connectedHostId, disconnectedHostId, fgId uuid.UUID
+		BeforeEach(func() {
+			connectedHostId = uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+			disconnectedHostId = uuid.MustParse("13885605-a0ee-41f2-b6fc-fd82edc487ad")
+			fgId = uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+			flavorgroupStore.HostFlavorgroupStore = append(flavorgroupStore.HostFlavorgroupStore,
+				&hvs.HostFlavorgroup{HostId: connectedHostId, FlavorgroupId: fgId},
+				&hvs.HostFlavorgroup{HostId: disconnectedHostId, FlavorgroupId: fgId})
+			flavorgroupController.HostStatusStore = mocks2.NewMockHostStatusStore()
+			router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/simulate", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Simulate))).Methods("POST")
+		})
+
+		simulate := func(fgId uuid.UUID, body string) *hvs.FlavorgroupSimulationReport {
+			req, err := http.NewRequest("POST", "/flavorgroups/"+fgId.String()+"/simulate", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				return nil
+			}
+			var simulationReport hvs.FlavorgroupSimulationReport
+			Expect(json.Unmarshal(w.Body.Bytes(), &simulationReport)).To(Succeed())
+			return &simulationReport
+		}
+
+		Context("Simulate new flavor match policies", func() {
+			It("Should predict the trust of the linked hosts and return 200 response code", func() {
+				simulationReport := simulate(fgId, `{"flavor_match_policies": [{"flavor_part": "PLATFORM", "match_policy": {"match_type": "ALL_OF", "required": "REQUIRED"}}]}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(simulationReport.FlavorGroupID).To(Equal(fgId))
+				Expect(simulationReport.Hosts).To(HaveLen(2))
+
+				Expect(simulationReport.Hosts[0].HostID).To(Equal(connectedHostId))
+				Expect(simulationReport.Hosts[0].Error).To(BeEmpty())
+				Expect(*simulationReport.Hosts[0].CurrentTrusted).To(BeTrue())
+				Expect(*simulationReport.Hosts[0].PredictedTrusted).To(BeTrue())
+				Expect(simulationReport.Hosts[0].TrustChanged).To(BeFalse())
+
+				Expect(simulationReport.Hosts[1].HostID).To(Equal(disconnectedHostId))
+				Expect(simulationReport.Hosts[1].Error).NotTo(BeEmpty())
+				Expect(simulationReport.Hosts[1].PredictedTrusted).To(BeNil())
+			})
+		})
+
+		Context("Simulate adding an existing flavor", func() {
+			It("Should report the hosts whose trust changes and return 200 response code", func() {
+				simulationReport := simulate(fgId, `{"flavor_ids": ["c36b5412-8c02-4e08-8a74-8bfa40425cf3"]}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(simulationReport.Hosts).To(HaveLen(2))
+				Expect(*simulationReport.Hosts[0].CurrentTrusted).To(BeTrue())
+				Expect(*simulationReport.Hosts[0].PredictedTrusted).To(BeFalse())
+				Expect(simulationReport.Hosts[0].TrustChanged).To(BeTrue())
+			})
+		})
+
+		Context("Simulate adding new flavor content", func() {
+			It("Should leave the FlavorGroup untouched and return 200 response code", func() {
+				simulationReport := simulate(fgId, `{"flavor_collection": {"flavors": [{"flavor": {"meta": {"description": {"flavor_part": "PLATFORM", "label": "candidate_platform_flavor"}}}}]}}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(simulationReport.Hosts[0].TrustChanged).To(BeTrue())
+
+				flavorIds, err := flavorgroupStore.SearchFlavors(fgId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorIds).To(Equal([]uuid.UUID{uuid.MustParse("c36b5412-8c02-4e08-8a74-8bfa40425cf3")}))
+			})
+		})
+
+		Context("Simulate adding new flavor content without a flavor label", func() {
+			It("Should return 400 response code", func() {
+				simulate(fgId, `{"flavor_collection": {"flavors": [{"flavor": {"meta": {"description": {"flavor_part": "PLATFORM"}}}}]}}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Simulate without candidate flavors or flavor match policies", func() {
+			It("Should return 400 response code", func() {
+				simulate(fgId, `{}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Simulate flavor match policies with an invalid match type", func() {
+			It("Should return 400 response code", func() {
+				simulate(fgId, `{"flavor_match_policies": [{"flavor_part": "PLATFORM", "match_policy": {"match_type": "SOME_OF", "required": "REQUIRED"}}]}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Simulate adding a non-existent flavor", func() {
+			It("Should return 400 response code", func() {
+				simulate(fgId, `{"flavor_ids": ["73755fda-c910-46be-821f-e8ddeab189e9"]}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Simulate the changes of a FlavorGroup linked to too many hosts", func() {
+			It("Should return 400 response code", func() {
+				for i := 0; i < constants.FlavorgroupSimulationMaxHosts; i++ {
+					flavorgroupStore.HostFlavorgroupStore = append(flavorgroupStore.HostFlavorgroupStore,
+						&hvs.HostFlavorgroup{HostId: uuid.New(), FlavorgroupId: fgId})
+				}
+				simulate(fgId, `{"flavor_ids": ["c36b5412-8c02-4e08-8a74-8bfa40425cf3"]}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("respond-async"))
+			})
+		})
+
+		Context("Simulate the changes of a FlavorGroup linked to many hosts in a job", func() {
+			It("Should predict the trust of the linked hosts in a job and return 202 response code", func() {
+				for i := 0; i < constants.FlavorgroupSimulationMaxHosts; i++ {
+					flavorgroupStore.HostFlavorgroupStore = append(flavorgroupStore.HostFlavorgroupStore,
+						&hvs.HostFlavorgroup{HostId: uuid.New(), FlavorgroupId: fgId})
+				}
+				jobStore := mocks2.NewMockJobStore()
+				jobManager, err := jobs.NewManager(jobStore, "hvs-test")
+				Expect(err).NotTo(HaveOccurred())
+				defer jobManager.Stop()
+				flavorgroupController.JobManager = jobManager
+				router = mux.NewRouter()
+				router.Handle("/flavorgroups/{fgID:"+validation.UUIDReg+"}/simulate", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorgroupController.Simulate))).Methods("POST")
+
+				req, err := http.NewRequest("POST", "/flavorgroups/"+fgId.String()+"/simulate",
+					strings.NewReader(`{"flavor_ids": ["c36b5412-8c02-4e08-8a74-8bfa40425cf3"]}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				req.Header.Set("Prefer", "respond-async")
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusAccepted))
+
+				var job hvs.Job
+				Expect(json.Unmarshal(w.Body.Bytes(), &job)).To(Succeed())
+				Expect(job.Type).To(Equal(hvs.JobTypeFlavorgroupSimulate))
+				Eventually(func() hvs.JobStatus {
+					current, err := jobStore.Retrieve(job.Id)
+					Expect(err).NotTo(HaveOccurred())
+					job = *current
+					return job.Status
+				}, 5*time.Second, 20*time.Millisecond).Should(Equal(hvs.JobStatusCompleted))
+
+				var simulationReport hvs.FlavorgroupSimulationReport
+				Expect(json.Unmarshal(job.Result, &simulationReport)).To(Succeed())
+				Expect(simulationReport.FlavorGroupID).To(Equal(fgId))
+				Expect(simulationReport.Hosts).To(HaveLen(constants.FlavorgroupSimulationMaxHosts + 2))
+				Expect(simulationReport.Hosts[0].TrustChanged).To(BeTrue())
+			})
+		})
+
+		Context("Simulate the changes of a non-existent FlavorGroup", func() {
+			It("Should return 404 response code", func() {
+				simulate(uuid.MustParse("9c41f744-cf17-4c53-8d49-888ebb6af99f"), `{"flavor_ids": ["c36b5412-8c02-4e08-8a74-8bfa40425cf3"]}`)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
 })
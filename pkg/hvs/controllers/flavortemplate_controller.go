@@ -24,6 +24,7 @@ import (
 	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/verifier/rules"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/pkg/errors"
 	"github.com/xeipuuv/gojsonschema"
@@ -535,6 +536,24 @@ func (ftc *FlavorTemplateController) ValidateFlavorTemplateCreateRequest(FlvrTem
 		}
 	}
 
+	//Validate the custom rules, each rule needs a unique name within the flavor part and a valid expression
+	flavorPartNames := []hvs.FlavorPartName{hvs.FlavorPartPlatform, hvs.FlavorPartOs, hvs.FlavorPartHostUnique}
+	for i, flavorPart := range flavorParts {
+		if flavorPart == nil {
+			continue
+		}
+		ruleNames := make(map[string]bool)
+		for _, customRule := range flavorPart.CustomRules {
+			if _, ok := ruleNames[customRule.Name]; ok {
+				return "Template has duplicate custom rule names in flavor part " + flavorPartNames[i].String(), errors.Errorf("controllers/flavortemplate_controller:validateFlavorTemplateCreateRequest() Template has duplicate custom rule name : %s", customRule.Name)
+			}
+			ruleNames[customRule.Name] = true
+			if _, err := rules.NewCustomRule(customRule, flavorPartNames[i]); err != nil {
+				return "Invalid custom rule " + customRule.Name, errors.Wrapf(err, "controllers/flavortemplate_controller:validateFlavorTemplateCreateRequest() Invalid custom rule : %s", customRule.Name)
+			}
+		}
+	}
+
 	return "", nil
 }
 
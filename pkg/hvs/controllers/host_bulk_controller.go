@@ -0,0 +1,393 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/utils"
+	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	comctx "github.com/intel-secl/intel-secl/v4/pkg/lib/common/context"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// columns of the CSV form of a bulk host registration request, flavorgroup names are separated by semicolons
+const (
+	hostBulkCsvHostName         = "host_name"
+	hostBulkCsvConnectionString = "connection_string"
+	hostBulkCsvDescription      = "description"
+	hostBulkCsvFlavorgroupNames = "flavorgroup_names"
+)
+
+var hostBulkCreateParams = map[string]bool{"onConflict": true}
+
+// HostBulkController registers hosts in bulk. Each request is processed asynchronously as a HOST_BULK_CREATE job
+// whose result is the HostBulkSummary of the registration.
+type HostBulkController struct {
+	HController *HostController
+	JobStore    domain.JobStore
+	JobManager  domain.JobManager
+	Workers     int
+}
+
+func NewHostBulkController(hc *HostController, js domain.JobStore, jm domain.JobManager, workers int) *HostBulkController {
+	return &HostBulkController{
+		HController: hc,
+		JobStore:    js,
+		JobManager:  jm,
+		Workers:     workers,
+	}
+}
+
+// Create starts a job registering the hosts of a JSON or CSV request and returns the job
+func (controller *HostBulkController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_bulk_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/host_bulk_controller:Create() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), hostBulkCreateParams); err != nil {
+		secLog.Errorf("controllers/host_bulk_controller:Create() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	onConflict := hvs.HostBulkConflictFail
+	if param := r.URL.Query().Get("onConflict"); param != "" {
+		onConflict = hvs.HostBulkConflictPolicy(strings.ToLower(param))
+		if !onConflict.Valid() {
+			secLog.Errorf("controllers/host_bulk_controller:Create() %s Invalid onConflict query param value", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid onConflict query param value, must be fail, skip or update"}
+		}
+	}
+
+	if onConflict == hvs.HostBulkConflictUpdate {
+		privileges, err := comctx.GetUserPermissions(r)
+		if err != nil {
+			secLog.Errorf("controllers/host_bulk_controller:Create() %s", commLogMsg.AuthenticationFailed)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Could not get user permissions from http context"}
+		}
+		if !checkValidFlavorPermission(privileges, []string{constants.HostUpdate}) {
+			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Insufficient privileges to update existing hosts"}
+		}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/host_bulk_controller:Create() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+	if r.ContentLength > constants.HostBulkMaxRequestSize {
+		secLog.Errorf("controllers/host_bulk_controller:Create() %s The request body is too large", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusRequestEntityTooLarge, &commErr.ResourceError{Message: "The request body is too large"}
+	}
+	// bodies of unknown length are cut at the limit and fail to decode
+	r.Body = http.MaxBytesReader(w, r.Body, constants.HostBulkMaxRequestSize)
+
+	var reqHosts []hvs.HostCreateRequest
+	var err error
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case consts.HTTPMediaTypeJson:
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		var reqBulk hvs.HostBulkCreateRequest
+		err = dec.Decode(&reqBulk)
+		reqHosts = reqBulk.Hosts
+	case consts.HTTPMediaTypeCsv:
+		reqHosts, err = parseHostBulkCsv(r.Body)
+	default:
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/host_bulk_controller:Create() %s : Failed to decode request body", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode request body"}
+	}
+
+	if len(reqHosts) == 0 {
+		secLog.Errorf("controllers/host_bulk_controller:Create() %s No host provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "At least one host must be provided"}
+	}
+	if len(reqHosts) > constants.HostBulkMaxHosts {
+		secLog.Errorf("controllers/host_bulk_controller:Create() %s Too many hosts provided", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Too many hosts provided in a single request"}
+	}
+
+	hostCount := len(reqHosts)
+	remoteAddr := r.RemoteAddr
+	status, code, err := submitJob(w, r, controller.JobManager, hvs.JobTypeHostBulkCreate,
+		func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+			secLog.Infof("%s: Bulk host registration of %d hosts started by: %s", commLogMsg.PrivilegeModified, hostCount, remoteAddr)
+			return controller.runJob(ctx, reqHosts, onConflict, report), nil
+		})
+	return status, code, err
+}
+
+// Retrieve returns the job of a bulk host registration, its result holds the progress and the per host results
+func (controller *HostBulkController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_bulk_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/host_bulk_controller:Retrieve() Leaving")
+
+	job, status, err := retrieveJob(controller.JobStore, mux.Vars(r)["id"])
+	if err != nil {
+		return nil, status, err
+	}
+	if job.Type != hvs.JobTypeHostBulkCreate {
+		defaultLog.WithField("id", job.Id).Error("controllers/host_bulk_controller:Retrieve() Job with specified id is not a bulk host registration job")
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Bulk host registration job with specified id does not exist"}
+	}
+
+	secLog.WithField("job", job.Id).Infof("%s: Bulk host registration job retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return job, http.StatusOK, nil
+}
+
+// runJob registers the hosts of the request and returns the summary of the registration. The hosts that are not
+// processed yet when ctx is cancelled are reported as failed.
+func (controller *HostBulkController) runJob(ctx context.Context, reqHosts []hvs.HostCreateRequest, onConflict hvs.HostBulkConflictPolicy, report domain.JobProgressFunc) *hvs.HostBulkSummary {
+	defaultLog.Trace("controllers/host_bulk_controller:runJob() Entering")
+	defer defaultLog.Trace("controllers/host_bulk_controller:runJob() Leaving")
+
+	summary := &hvs.HostBulkSummary{
+		OnConflict: onConflict,
+		Total:      len(reqHosts),
+		Results:    make([]hvs.HostBulkResult, len(reqHosts)),
+	}
+	for i, reqHost := range reqHosts {
+		summary.Results[i] = hvs.HostBulkResult{HostName: reqHost.HostName, Status: hvs.HostBulkResultPending}
+	}
+	var mu sync.Mutex
+	setResult := func(index int, result hvs.HostBulkResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		setHostBulkResult(summary, index, result)
+		report(summary.Processed, summary.Total, copyHostBulkSummary(summary))
+	}
+	report(0, summary.Total, copyHostBulkSummary(summary))
+
+	// validate the hosts upfront, a host name listed more than once is registered only the first time
+	seen := map[string]bool{}
+	var accepted []int
+	for index, reqHost := range reqHosts {
+		if err := validateHostBulkRequest(reqHost); err != nil {
+			setResult(index, hvs.HostBulkResult{HostName: reqHost.HostName,
+				Status: hvs.HostBulkResultFailed, Error: err.Error()})
+			continue
+		}
+		if seen[reqHost.HostName] {
+			setResult(index, hvs.HostBulkResult{HostName: reqHost.HostName,
+				Status: hvs.HostBulkResultFailed, Error: "Host is listed more than once in the request"})
+			continue
+		}
+		seen[reqHost.HostName] = true
+		accepted = append(accepted, index)
+	}
+
+	// create the flavorgroups of the accepted hosts upfront so that concurrent registrations do not race to create
+	// the same ones
+	fgNames := map[string]bool{}
+	var uniqueFgNames []string
+	for _, index := range accepted {
+		for _, fgName := range reqHosts[index].FlavorgroupNames {
+			if fgName != "" && !fgNames[fgName] {
+				fgNames[fgName] = true
+				uniqueFgNames = append(uniqueFgNames, fgName)
+			}
+		}
+	}
+	if len(uniqueFgNames) > 0 {
+		if _, err := CreateMissingFlavorgroups(controller.HController.FGStore, uniqueFgNames, nil); err != nil {
+			defaultLog.WithError(err).Warn("controllers/host_bulk_controller:runJob() Could not create the flavorgroups of the hosts")
+		}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	workers := controller.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if ctx.Err() != nil {
+					setResult(index, hvs.HostBulkResult{HostName: reqHosts[index].HostName,
+						Status: hvs.HostBulkResultFailed, Error: "Bulk host registration was interrupted"})
+					continue
+				}
+				setResult(index, controller.registerHost(reqHosts[index], onConflict))
+			}
+		}()
+	}
+	for _, index := range accepted {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	defaultLog.Infof("controllers/host_bulk_controller:runJob() Bulk host registration completed: %d created, "+
+		"%d updated, %d skipped, %d failed", summary.Created, summary.Updated, summary.Skipped, summary.Failed)
+	return summary
+}
+
+// registerHost creates the host of the request, or handles the already registered host according to onConflict
+func (controller *HostBulkController) registerHost(reqHost hvs.HostCreateRequest, onConflict hvs.HostBulkConflictPolicy) hvs.HostBulkResult {
+	defaultLog.Trace("controllers/host_bulk_controller:registerHost() Entering")
+	defer defaultLog.Trace("controllers/host_bulk_controller:registerHost() Leaving")
+
+	hc := controller.HController
+	result := hvs.HostBulkResult{HostName: reqHost.HostName, Status: hvs.HostBulkResultFailed}
+
+	existingHosts, err := hc.HStore.Search(&models.HostFilterCriteria{NameEqualTo: reqHost.HostName}, nil)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/host_bulk_controller:registerHost() Host search failed")
+		result.Error = "Failed to search existing Host"
+		return result
+	}
+
+	if len(existingHosts) > 0 {
+		hostId := existingHosts[0].Id
+		result.HostId = &hostId
+		switch onConflict {
+		case hvs.HostBulkConflictSkip:
+			result.Status = hvs.HostBulkResultSkipped
+			return result
+		case hvs.HostBulkConflictUpdate:
+			_, _, err := hc.UpdateHost(hvs.Host{
+				Id:               hostId,
+				HostName:         reqHost.HostName,
+				Description:      reqHost.Description,
+				ConnectionString: reqHost.ConnectionString,
+				FlavorgroupNames: reqHost.FlavorgroupNames,
+				RefreshSchedule:  reqHost.RefreshSchedule,
+				Labels:           reqHost.Labels,
+			})
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			result.Status = hvs.HostBulkResultUpdated
+			return result
+		default:
+			result.Error = "Host with this name already exist"
+			return result
+		}
+	}
+
+	createdHost, _, err := hc.CreateHost(reqHost)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if host, ok := createdHost.(*hvs.Host); ok {
+		result.HostId = &host.Id
+	}
+	result.Status = hvs.HostBulkResultCreated
+	return result
+}
+
+// validateHostBulkRequest checks a host of a bulk registration request before any of the hosts is registered
+func validateHostBulkRequest(reqHost hvs.HostCreateRequest) error {
+	if reqHost.HostName == "" || reqHost.ConnectionString == "" {
+		return errors.New("Host connection string and host name must be specified")
+	}
+	return validateHostCreateCriteria(reqHost)
+}
+
+func setHostBulkResult(summary *hvs.HostBulkSummary, index int, result hvs.HostBulkResult) {
+	summary.Results[index] = result
+	summary.Processed++
+	switch result.Status {
+	case hvs.HostBulkResultCreated:
+		summary.Created++
+	case hvs.HostBulkResultUpdated:
+		summary.Updated++
+	case hvs.HostBulkResultSkipped:
+		summary.Skipped++
+	default:
+		summary.Failed++
+	}
+}
+
+func copyHostBulkSummary(summary *hvs.HostBulkSummary) *hvs.HostBulkSummary {
+	summaryCopy := *summary
+	summaryCopy.Results = append([]hvs.HostBulkResult(nil), summary.Results...)
+	return &summaryCopy
+}
+
+// parseHostBulkCsv reads the hosts of a CSV bulk registration request. The first record is a header naming the
+// columns, host_name and connection_string are required while description and flavorgroup_names are optional.
+func parseHostBulkCsv(r io.Reader) ([]hvs.HostCreateRequest, error) {
+	defaultLog.Trace("controllers/host_bulk_controller:parseHostBulkCsv() Entering")
+	defer defaultLog.Trace("controllers/host_bulk_controller:parseHostBulkCsv() Leaving")
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read CSV header")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		switch name {
+		case hostBulkCsvHostName, hostBulkCsvConnectionString, hostBulkCsvDescription, hostBulkCsvFlavorgroupNames:
+		default:
+			return nil, errors.Errorf("Unknown CSV column %s", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, errors.Errorf("Duplicate CSV column %s", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns[hostBulkCsvHostName]; !ok {
+		return nil, errors.New("CSV column host_name is missing")
+	}
+	if _, ok := columns[hostBulkCsvConnectionString]; !ok {
+		return nil, errors.New("CSV column connection_string is missing")
+	}
+
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var reqHosts []hvs.HostCreateRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not read CSV record")
+		}
+		reqHost := hvs.HostCreateRequest{
+			HostName:         column(record, hostBulkCsvHostName),
+			ConnectionString: column(record, hostBulkCsvConnectionString),
+			Description:      column(record, hostBulkCsvDescription),
+		}
+		if fgNames := column(record, hostBulkCsvFlavorgroupNames); fgNames != "" {
+			for _, fgName := range strings.Split(fgNames, ";") {
+				reqHost.FlavorgroupNames = append(reqHost.FlavorgroupNames, strings.TrimSpace(fgName))
+			}
+		}
+		reqHosts = append(reqHosts, reqHost)
+	}
+	return reqHosts, nil
+}
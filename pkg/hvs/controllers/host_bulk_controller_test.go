@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
+	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/jobs"
+	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	mocks2 "github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HostBulkController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var hostStore *mocks.MockHostStore
+	var flavorgroupStore *mocks.MockFlavorgroupStore
+	var hostBulkController *controllers.HostBulkController
+	var jobManager *jobs.Manager
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		hostStore = mocks.NewMockHostStore()
+		flavorgroupStore = mocks.NewFakeFlavorgroupStore()
+
+		dek, err := base64.StdEncoding.DecodeString("gcXqH8YwuJZ3Rx4qVzA/zhVvkTw2TL+iRAC9T3E6lII=")
+		Expect(err).NotTo(HaveOccurred())
+		hostController := &controllers.HostController{
+			HStore:    hostStore,
+			HSStore:   mocks.NewMockHostStatusStore(),
+			FStore:    mocks.NewMockFlavorStore(),
+			FGStore:   flavorgroupStore,
+			HCStore:   mocks.NewMockHostCredentialStore(),
+			HTManager: &smocks.MockHostTrustManager{},
+			HCConfig: domain.HostControllerConfig{
+				HostConnectorProvider: mocks2.MockHostConnectorFactory{},
+				DataEncryptionKey:     dek,
+				Username:              "fakeuser",
+				Password:              "fakepassword",
+			},
+		}
+		jobStore := mocks.NewMockJobStore()
+		jobManager, err = jobs.NewManager(jobStore, "hvs-test")
+		Expect(err).NotTo(HaveOccurred())
+		hostBulkController = controllers.NewHostBulkController(hostController, jobStore, jobManager, 1)
+		router.Handle("/hosts/bulk", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostBulkController.Create))).Methods("POST")
+		router.Handle("/hosts/bulk/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostBulkController.Retrieve))).Methods("GET")
+	})
+
+	AfterEach(func() {
+		jobManager.Stop()
+	})
+
+	createJob := func(url, contentType, body string) *hvs.Job {
+		req, err := http.NewRequest("POST", url, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", contentType)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+
+		var job hvs.Job
+		Expect(json.Unmarshal(w.Body.Bytes(), &job)).To(Succeed())
+		Expect(job.Type).To(Equal(hvs.JobTypeHostBulkCreate))
+		Expect(w.Header().Get("Location")).To(Equal("/jobs/" + job.Id.String()))
+		return &job
+	}
+
+	waitForJob := func(id string) *hvs.HostBulkSummary {
+		var job hvs.Job
+		Eventually(func() hvs.JobStatus {
+			req, err := http.NewRequest("GET", "/hosts/bulk/"+id, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(json.Unmarshal(w.Body.Bytes(), &job)).To(Succeed())
+			return job.Status
+		}, 5*time.Second, 50*time.Millisecond).Should(Equal(hvs.JobStatusCompleted))
+
+		var summary hvs.HostBulkSummary
+		Expect(json.Unmarshal(job.Result, &summary)).To(Succeed())
+		return &summary
+	}
+
+	// Specs for HTTP Post to "/hosts/bulk"
+	Describe("Register hosts in bulk", func() {
+		Context("Provide a valid JSON request", func() {
+			It("Should create the new hosts and report the existing one as failed", func() {
+				body := `{"hosts": [
+					{"host_name": "bulkhost1", "connection_string": "intel:https://bulk1.ta.ip.com:1443", "flavorgroup_names": ["rack12"]},
+					{"host_name": "bulkhost2", "connection_string": "intel:https://bulk2.ta.ip.com:1443"},
+					{"host_name": "localhost1", "connection_string": "intel:https://ta.ip.com:1443"}
+				]}`
+				job := waitForJob(createJob("/hosts/bulk", consts.HTTPMediaTypeJson, body).Id.String())
+				Expect(job.Total).To(Equal(3))
+				Expect(job.Processed).To(Equal(3))
+				Expect(job.Created).To(Equal(2))
+				Expect(job.Failed).To(Equal(1))
+				Expect(job.Results[0].Status).To(Equal(hvs.HostBulkResultCreated))
+				Expect(job.Results[0].HostId).NotTo(BeNil())
+				Expect(job.Results[2].Status).To(Equal(hvs.HostBulkResultFailed))
+				Expect(job.Results[2].Error).NotTo(BeEmpty())
+			})
+		})
+		Context("Provide a valid CSV request skipping existing hosts", func() {
+			It("Should create the new hosts and skip the existing one", func() {
+				body := "host_name,connection_string,description,flavorgroup_names\n" +
+					"bulkhost3,intel:https://bulk3.ta.ip.com:1443,Rack 12 host,automatic;rack12\n" +
+					"localhost2,intel:https://ta.ip.com:1443,,\n" +
+					"bulkhost3,intel:https://bulk3.ta.ip.com:1443,,\n"
+				job := waitForJob(createJob("/hosts/bulk?onConflict=skip", consts.HTTPMediaTypeCsv, body).Id.String())
+				Expect(job.Created).To(Equal(1))
+				Expect(job.Skipped).To(Equal(1))
+				Expect(job.Failed).To(Equal(1))
+				Expect(job.Results[1].Status).To(Equal(hvs.HostBulkResultSkipped))
+				Expect(job.Results[2].Status).To(Equal(hvs.HostBulkResultFailed))
+			})
+		})
+		Context("Provide a request with an invalid host", func() {
+			It("Should fail the invalid host without creating its flavorgroups", func() {
+				body := `{"hosts": [
+					{"host_name": "bulkhost5", "connection_string": "intel:https://bulk5.ta.ip.com:1443", "flavorgroup_names": ["rack13"]},
+					{"host_name": "bulkhost6", "flavorgroup_names": ["rack14"]}
+				]}`
+				job := waitForJob(createJob("/hosts/bulk", consts.HTTPMediaTypeJson, body).Id.String())
+				Expect(job.Created).To(Equal(1))
+				Expect(job.Failed).To(Equal(1))
+				Expect(job.Results[1].Status).To(Equal(hvs.HostBulkResultFailed))
+
+				flavorgroups, err := flavorgroupStore.Search(&models.FlavorGroupFilterCriteria{NameEqualTo: "rack13"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorgroups).To(HaveLen(1))
+				flavorgroups, err = flavorgroupStore.Search(&models.FlavorGroupFilterCriteria{NameEqualTo: "rack14"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flavorgroups).To(BeEmpty())
+			})
+		})
+		Context("Provide a request body exceeding the size limit", func() {
+			It("Should fail to start the job", func() {
+				req, err := http.NewRequest("POST", "/hosts/bulk", strings.NewReader(`{"hosts": []}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.ContentLength = constants.HostBulkMaxRequestSize + 1
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			})
+		})
+		Context("Provide a CSV request with an unknown column", func() {
+			It("Should fail to start the job", func() {
+				req, err := http.NewRequest("POST", "/hosts/bulk", strings.NewReader("host_name,rack\nbulkhost4,r12\n"))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeCsv)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Provide an invalid conflict policy", func() {
+			It("Should fail to start the job", func() {
+				req, err := http.NewRequest("POST", "/hosts/bulk?onConflict=replace", strings.NewReader(`{"hosts": []}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/hosts/bulk/{id}"
+	Describe("Retrieve a bulk registration job", func() {
+		Context("Retrieve a job that does not exist", func() {
+			It("Should return not found", func() {
+				req, err := http.NewRequest("GET", "/hosts/bulk/73755fda-c910-46be-821f-e8ddeab189e9", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -52,7 +52,8 @@ func NewHostController(hs domain.HostStore, hss domain.HostStatusStore, fs domai
 }
 
 var hostSearchParams = map[string]bool{"id": true, "nameEqualTo": true, "nameContains": true, "hostHardwareId": true,
-	"key": true, "value": true, "trusted": true, "getTrustStatus": true, "getHostStatus": true, "orderBy": true}
+	"key": true, "value": true, "trusted": true, "getTrustStatus": true, "getHostStatus": true, "orderBy": true,
+	"limit": true, "after": true, "fields": true, "labelSelector": true}
 
 var hostRetrieveParams = map[string]bool{"getReport": true, "getHostStatus": true}
 
@@ -144,6 +145,8 @@ func (hc *HostController) Update(w http.ResponseWriter, r *http.Request) (interf
 		Description:      reqHost.Description,
 		ConnectionString: reqHost.ConnectionString,
 		FlavorgroupNames: reqHost.FlavorgroupNames,
+		RefreshSchedule:  reqHost.RefreshSchedule,
+		Labels:           reqHost.Labels,
 	}
 
 	if err := validateHostCreateCriteria(criteria); err != nil {
@@ -157,14 +160,6 @@ func (hc *HostController) Update(w http.ResponseWriter, r *http.Request) (interf
 		return nil, status, err
 	}
 
-	defaultLog.Debugf("Adding host %v to flavor-verify queue", reqHost.Id)
-	// Since the host has been updated, add it to the verify queue
-	err = hc.HTManager.VerifyHostsAsync([]uuid.UUID{reqHost.Id}, true, false)
-	if err != nil {
-		defaultLog.WithError(err).Error("controllers/host_controller:Update() Host to Flavor Verify Queue addition failed")
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to add Host to Flavor Verify Queue"}
-	}
-
 	secLog.WithField("host", updatedHost).Infof("%s: Host updated by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
 	return updatedHost, status, nil
 }
@@ -233,9 +228,18 @@ func (hc *HostController) Search(w http.ResponseWriter, r *http.Request) (interf
 		return nil, http.StatusInternalServerError, errors.Errorf("Failed to search Hosts")
 	}
 	hostCollection := hvs.HostCollection{Hosts: hosts}
+	if len(hosts) > 0 {
+		hostCollection.Next = nextPageLink(r, hostFilterCriteria.Limit, len(hosts), hosts[len(hosts)-1].Id)
+	}
+
+	result, err := selectFields(r.URL.Query(), hostCollection)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/host_controller:Search() %s Invalid fields", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
 
 	secLog.Infof("%s: Hosts searched by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
-	return hostCollection, http.StatusOK, nil
+	return result, http.StatusOK, nil
 }
 
 func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}, int, error) {
@@ -317,6 +321,8 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 		ConnectionString: csWithoutCredentials,
 		HardwareUuid:     hwUuid,
 		FlavorgroupNames: fgNames,
+		RefreshSchedule:  reqHost.RefreshSchedule,
+		Labels:           reqHost.Labels,
 	}
 
 	createdHost, err := hc.HStore.Create(host)
@@ -324,6 +330,13 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 		defaultLog.WithError(err).Error("controllers/host_controller:CreateHost() Host create failed")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create Host"}
 	}
+	// the host is removed when its registration cannot be completed so that it can be registered again, its
+	// credential and flavorgroup links are deleted along with it
+	rollback := func() {
+		if err := hc.HStore.Delete(createdHost.Id); err != nil {
+			defaultLog.WithError(err).Errorf("controllers/host_controller:CreateHost() Failed to remove host %s after its registration failed", createdHost.Id)
+		}
+	}
 
 	// create credential
 	var hostCredential models.HostCredential
@@ -337,6 +350,7 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 	_, err = hc.HCStore.Create(&hostCredential)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/host_controller:CreateHost() Host Credential create failed")
+		rollback()
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create Host Credential"}
 	}
 
@@ -344,13 +358,22 @@ func (hc *HostController) CreateHost(reqHost hvs.HostCreateRequest) (interface{}
 	if len(fgNames) > 0 {
 		if err := hc.linkFlavorgroupsToHost(fgNames, createdHost.Id); err != nil {
 			defaultLog.WithError(err).Error("controllers/host_controller:CreateHost() Host FlavorGroup association failed")
+			rollback()
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Host with flavorgroups"}
 		}
 	}
 
+	defaultLog.Debugf("Associating host %s with the flavorgroups selecting its labels", reqHost.HostName)
+	if err := hc.linkSelectingFlavorgroupsToHost(createdHost); err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:CreateHost() Host FlavorGroup association failed")
+		rollback()
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Host with flavorgroups"}
+	}
+
 	defaultLog.Debugf("Associating host %s with all host unique flavors", reqHost.HostName)
 	if err := hc.linkHostUniqueFlavorsToHost(createdHost); err != nil {
 		defaultLog.WithError(err).Error("controllers/host_controller:CreateHost() Host Unique flavor association failed")
+		rollback()
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Host with host unique flavors"}
 	}
 
@@ -434,6 +457,22 @@ func (hc *HostController) UpdateHost(reqHost hvs.Host) (interface{}, int, error)
 		updatedHost.FlavorgroupNames = reqHost.FlavorgroupNames
 	}
 
+	if reqHost.Labels != nil {
+		defaultLog.Debugf("Associating host %s with the flavorgroups selecting its labels", updatedHost.HostName)
+		if err := hc.linkSelectingFlavorgroupsToHost(updatedHost); err != nil {
+			defaultLog.WithError(err).Error("controllers/host_controller:UpdateHost() Host FlavorGroup association failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to associate Host with flavorgroups"}
+		}
+	}
+
+	defaultLog.Debugf("Adding host %v to flavor-verify queue", updatedHost.Id)
+	// Since the connection string, the flavorgroups or the labels selecting flavorgroups of the host may have changed,
+	// add it to the verify queue
+	if err := hc.HTManager.VerifyHostsAsync([]uuid.UUID{updatedHost.Id}, true, false); err != nil {
+		defaultLog.WithError(err).Error("controllers/host_controller:UpdateHost() Host to Flavor Verify Queue addition failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to add Host to Flavor Verify Queue"}
+	}
+
 	return updatedHost, http.StatusOK, nil
 }
 
@@ -558,6 +597,50 @@ func (hc *HostController) linkFlavorgroupsToHost(flavorgroupNames []string, host
 	return nil
 }
 
+// linkSelectingFlavorgroupsToHost links the host to the flavorgroups whose host selector matches its labels. Links are
+// only ever added, a host stays linked to a flavorgroup whose selector no longer matches its labels.
+func (hc *HostController) linkSelectingFlavorgroupsToHost(host *hvs.Host) error {
+	defaultLog.Trace("controllers/host_controller:linkSelectingFlavorgroupsToHost() Entering")
+	defer defaultLog.Trace("controllers/host_controller:linkSelectingFlavorgroupsToHost() Leaving")
+
+	flavorgroups, err := hc.FGStore.Search(nil)
+	if err != nil {
+		return errors.Wrap(err, "Could not search flavorgroups")
+	}
+
+	var flavorgroupIds []uuid.UUID
+	for _, flavorgroup := range flavorgroups {
+		if flavorgroup.HostSelector == "" {
+			continue
+		}
+		selector, err := hvs.ParseLabelSelector(flavorgroup.HostSelector)
+		if err != nil {
+			defaultLog.WithError(err).Warnf("controllers/host_controller:linkSelectingFlavorgroupsToHost() Ignoring "+
+				"invalid host selector of flavorgroup %s", flavorgroup.Name)
+			continue
+		}
+		if !selector.Matches(host.Labels) {
+			continue
+		}
+		linkExists, err := hc.flavorGroupHostLinkExists(host.Id, flavorgroup.ID)
+		if err != nil {
+			return errors.Wrap(err, "Could not check host-flavorgroup link existence")
+		}
+		if !linkExists {
+			flavorgroupIds = append(flavorgroupIds, flavorgroup.ID)
+		}
+	}
+
+	if len(flavorgroupIds) == 0 {
+		return nil
+	}
+	defaultLog.Debugf("Linking host %v with flavorgroups %+q", host.Id, flavorgroupIds)
+	if err := hc.HStore.AddFlavorgroups(host.Id, flavorgroupIds); err != nil {
+		return errors.Wrap(err, "Could not create host-flavorgroup links")
+	}
+	return nil
+}
+
 func (hc *HostController) linkHostUniqueFlavorsToHost(newHost *hvs.Host) error {
 	defaultLog.Trace("controllers/host_controller:linkHostUniqueFlavorsToHost() Entering")
 	defer defaultLog.Trace("controllers/host_controller:linkHostUniqueFlavorsToHost() Leaving")
@@ -666,6 +749,16 @@ func validateHostCreateCriteria(host hvs.HostCreateRequest) error {
 			return errors.Wrap(err, "Valid Host Description must be specified")
 		}
 	}
+	if host.RefreshSchedule != nil {
+		if err := host.RefreshSchedule.Validate(); err != nil {
+			return errors.Wrap(err, "Valid refresh schedule must be specified")
+		}
+	}
+	if host.Labels != nil {
+		if err := host.Labels.Validate(); err != nil {
+			return errors.Wrap(err, "Valid labels must be specified")
+		}
+	}
 	if len(host.FlavorgroupNames) != 0 {
 		for _, flavorgroup := range host.FlavorgroupNames {
 			if flavorgroup == "" {
@@ -737,6 +830,21 @@ func populateHostFilterCriteria(params url.Values) (*models.HostFilterCriteria,
 		criteria.OrderBy = orderType
 	}
 
+	if params.Get("labelSelector") != "" {
+		selector, err := hvs.ParseLabelSelector(params.Get("labelSelector"))
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid labelSelector query param value")
+		}
+		criteria.LabelSelector = selector
+	}
+
+	limit, after, err := getPageCriteria(params)
+	if err != nil {
+		return nil, err
+	}
+	criteria.Limit = limit
+	criteria.After = after
+
 	return &criteria, nil
 }
 
@@ -842,7 +950,8 @@ func (hc *HostController) AddFlavorgroup(w http.ResponseWriter, r *http.Request)
 	}
 
 	defaultLog.Debugf("Adding host %v to flavor-verify queue", hId)
-	err = hc.HTManager.VerifyHostsAsync([]uuid.UUID{hId}, false, false)
+	// fetch new host data if the refresh schedule of the host asks for it
+	err = verifyHostsOnFlavorChange(hc.HTManager, hc.HStore, hc.FGStore, []uuid.UUID{hId}, false)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/host_controller:AddFlavorgroup() Host to Flavor Verify Queue addition failed")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to add Host to Flavor Verify Queue"}
@@ -10,6 +10,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
 	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
@@ -17,13 +18,36 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
+// linkFailingHostStore fails to link the hosts to their flavorgroups
+type linkFailingHostStore struct {
+	*mocks.MockHostStore
+}
+
+func (store linkFailingHostStore) AddFlavorgroups(hId uuid.UUID, fgIds []uuid.UUID) error {
+	return errors.New("could not link host to flavorgroups")
+}
+
+// verifyQueueRecorder records the hosts added to the flavor verify queue
+type verifyQueueRecorder struct {
+	smocks.MockHostTrustManager
+	hostIds []uuid.UUID
+}
+
+func (recorder *verifyQueueRecorder) VerifyHostsAsync(hostIds []uuid.UUID, fetchHostData, preferHashMatch bool) error {
+	recorder.hostIds = append(recorder.hostIds, hostIds...)
+	return nil
+}
+
 var _ = Describe("HostController", func() {
 	var router *mux.Router
 	var w *httptest.ResponseRecorder
@@ -240,6 +264,95 @@ var _ = Describe("HostController", func() {
 		})
 	})
 
+	Describe("Create a new Host with labels", func() {
+		Context("Provide a valid Create request with labels", func() {
+			It("Should create a new Host linked to the Flavorgroups selecting its labels", func() {
+				fgId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				Expect(flavorGroupStore.UpdateHostSelector(fgId, "env=prod,rack!=r3")).To(Succeed())
+
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost3",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"labels": {"env": "prod", "rack": "r12"}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var host hvs.Host
+				err = json.Unmarshal(w.Body.Bytes(), &host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(host.Labels).To(Equal(hvs.Labels{"env": "prod", "rack": "r12"}))
+
+				_, err = hostStore.RetrieveFlavorgroup(host.Id, fgId)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+		Context("Provide a Create request with labels for which the flavorgroups cannot be linked", func() {
+			It("Should not leave the Host registered", func() {
+				fgId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				Expect(flavorGroupStore.UpdateHostSelector(fgId, "env=prod")).To(Succeed())
+				hostController.HStore = linkFailingHostStore{hostStore}
+
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost3",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"flavorgroup_names": [],
+								"labels": {"env": "prod"}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+
+				hosts, err := hostStore.Search(&models.HostFilterCriteria{NameEqualTo: "localhost3"}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hosts).To(BeEmpty())
+			})
+		})
+		Context("Provide a Create request that contains invalid labels", func() {
+			It("Should fail to create new Host", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Create))).Methods("POST")
+				hostJson := `{
+								"host_name": "localhost3",
+								"connection_string": "intel:https://another.ta.ip.com:1443",
+								"labels": {"env": "prod west"}
+							}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/hosts",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
 	// Specs for HTTP Get to "/hosts/{hId}"
 	Describe("Retrieve an existing Host", func() {
 		Context("Retrieve Host by ID", func() {
@@ -267,6 +380,40 @@ var _ = Describe("HostController", func() {
 	})
 
 	// Specs for HTTP Put to "/hosts/{hId}"
+	Describe("Update the labels of an existing Host", func() {
+		Context("Provide new labels", func() {
+			It("Should add the Host to the flavor verify queue", func() {
+				recorder := &verifyQueueRecorder{}
+				hostController.HTManager = recorder
+				fgId := uuid.MustParse("e57e5ea0-d465-461e-882d-1600090caa0d")
+				Expect(flavorGroupStore.UpdateHostSelector(fgId, "env=prod")).To(Succeed())
+
+				router.Handle("/hosts/{hId}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Update))).Methods("PUT")
+				hostJson := `{
+								"host_name": "localhost1",
+								"labels": {"env": "prod"}
+							}`
+
+				req, err := http.NewRequest(
+					"PUT",
+					"/hosts/ee37c360-7eae-4250-a677-6ee12adce8e2",
+					strings.NewReader(hostJson),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				hostId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+				_, err = hostStore.RetrieveFlavorgroup(hostId, fgId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recorder.hostIds).To(Equal([]uuid.UUID{hostId}))
+			})
+		})
+	})
+
 	Describe("Update an existing Host", func() {
 		Context("Provide a valid Host data", func() {
 			It("Should update an existing Host", func() {
@@ -526,6 +673,127 @@ var _ = Describe("HostController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Get the Hosts a page at a time", func() {
+			It("Should get each page of Hosts and the link to the next page", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts?limit=1", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var hostCollection hvs.HostCollection
+				err = json.Unmarshal(w.Body.Bytes(), &hostCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(hostCollection.Hosts)).To(Equal(1))
+				Expect(hostCollection.Next).To(ContainSubstring("after=" + hostCollection.Hosts[0].Id.String()))
+				firstHostId := hostCollection.Hosts[0].Id
+
+				req, err = http.NewRequest("GET", hostCollection.Next, nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				hostCollection = hvs.HostCollection{}
+				err = json.Unmarshal(w.Body.Bytes(), &hostCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(hostCollection.Hosts)).To(Equal(1))
+				Expect(hostCollection.Hosts[0].Id).NotTo(Equal(firstHostId))
+
+				req, err = http.NewRequest("GET", hostCollection.Next, nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				hostCollection = hvs.HostCollection{}
+				err = json.Unmarshal(w.Body.Bytes(), &hostCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(hostCollection.Hosts)).To(Equal(0))
+				Expect(hostCollection.Next).To(BeEmpty())
+			})
+		})
+		Context("Get all the Hosts with the fields param", func() {
+			It("Should get list of all the Hosts with only the selected fields", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts?fields=id,host_name", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var hostCollection struct {
+					Hosts []map[string]interface{} `json:"hosts"`
+				}
+				err = json.Unmarshal(w.Body.Bytes(), &hostCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(hostCollection.Hosts)).To(Equal(2))
+				for _, host := range hostCollection.Hosts {
+					Expect(host).To(HaveLen(2))
+					Expect(host).To(HaveKey("id"))
+					Expect(host).To(HaveKey("host_name"))
+				}
+			})
+		})
+		Context("Get all the Hosts with the labelSelector param", func() {
+			It("Should get list of the Hosts whose labels match the selector", func() {
+				host, err := hostStore.Retrieve(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"), nil)
+				Expect(err).NotTo(HaveOccurred())
+				host.Labels = hvs.Labels{"env": "prod", "rack": "r12"}
+
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts?labelSelector="+url.QueryEscape("env=prod,rack!=r3"), nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var hostCollection hvs.HostCollection
+				err = json.Unmarshal(w.Body.Bytes(), &hostCollection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(hostCollection.Hosts)).To(Equal(1))
+				Expect(hostCollection.Hosts[0].Id).To(Equal(host.Id))
+			})
+		})
+		Context("Get all the Hosts with invalid labelSelector param", func() {
+			It("Should fail to get Hosts", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts?labelSelector="+url.QueryEscape("env in (prod"), nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Get all the Hosts with invalid limit param", func() {
+			It("Should fail to get Hosts", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts?limit=0", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Get all the Hosts with invalid fields param", func() {
+			It("Should fail to get Hosts", func() {
+				router.Handle("/hosts", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/hosts?fields=id,password", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 
 	// Specs for HTTP Post to "/hosts/{hId}/flavorgroups"
@@ -0,0 +1,243 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	hcUtil "github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector/util"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+)
+
+// HostEvidenceController implements push mode attestation, where hosts request a nonce and submit a TPM quote bound
+// to it instead of HVS retrieving the quote from the trust agent
+type HostEvidenceController struct {
+	HStore        domain.HostStore
+	HSStore       domain.HostStatusStore
+	HTManager     domain.HostTrustManager
+	CertStore     *models.CertificatesStore
+	NonceValidity time.Duration
+
+	// nonces holds the last nonce issued to each host, a nonce can only be used once
+	nonces   map[uuid.UUID]hvs.HostNonce
+	nonceMtx sync.Mutex
+}
+
+func NewHostEvidenceController(hs domain.HostStore, hss domain.HostStatusStore, htm domain.HostTrustManager,
+	certStore *models.CertificatesStore, nonceValidity time.Duration) *HostEvidenceController {
+	if nonceValidity <= 0 {
+		nonceValidity = constants.DefaultHostNonceValidity
+	}
+	return &HostEvidenceController{
+		HStore:        hs,
+		HSStore:       hss,
+		HTManager:     htm,
+		CertStore:     certStore,
+		NonceValidity: nonceValidity,
+		nonces:        make(map[uuid.UUID]hvs.HostNonce),
+	}
+}
+
+func (controller *HostEvidenceController) RetrieveNonce(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_evidence_controller:RetrieveNonce() Entering")
+	defer defaultLog.Trace("controllers/host_evidence_controller:RetrieveNonce() Leaving")
+
+	hostId := uuid.MustParse(mux.Vars(r)["hId"])
+	if _, status, err := controller.retrieveHost(hostId); err != nil {
+		return nil, status, err
+	}
+
+	nonce, err := hcUtil.GenerateNonce(constants.HostNonceSize)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/host_evidence_controller:RetrieveNonce() Error generating nonce")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to generate nonce"}
+	}
+
+	hostNonce := hvs.HostNonce{
+		HostId:    hostId,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(controller.NonceValidity),
+	}
+
+	controller.nonceMtx.Lock()
+	defer controller.nonceMtx.Unlock()
+	// drop the nonces of hosts that never submitted evidence
+	for id, issued := range controller.nonces {
+		if time.Now().After(issued.ExpiresAt) {
+			delete(controller.nonces, id)
+		}
+	}
+	controller.nonces[hostId] = hostNonce
+
+	secLog.WithField("host_id", hostId).Infof("%s: Host nonce issued to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hostNonce, http.StatusOK, nil
+}
+
+func (controller *HostEvidenceController) SubmitEvidence(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_evidence_controller:SubmitEvidence() Entering")
+	defer defaultLog.Trace("controllers/host_evidence_controller:SubmitEvidence() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/host_evidence_controller:SubmitEvidence() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	var evidence hvs.HostEvidence
+	if err := dec.Decode(&evidence); err != nil {
+		secLog.WithError(err).Errorf("controllers/host_evidence_controller:SubmitEvidence() %s :  Failed to decode request body as HostEvidence", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	hostId := uuid.MustParse(mux.Vars(r)["hId"])
+	host, status, err := controller.retrieveHost(hostId)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if !controller.consumeNonce(hostId, evidence.Nonce) {
+		secLog.WithField("host_id", hostId).Errorf("controllers/host_evidence_controller:SubmitEvidence() %s : The evidence is not bound to a valid nonce", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The nonce is invalid or has expired"}
+	}
+
+	hardwareUuid, err := uuid.Parse(evidence.HostInfo.HardwareUUID)
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/host_evidence_controller:SubmitEvidence() %s : Invalid hardware UUID", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid or missing hardware UUID in host info"}
+	}
+	if host.HardwareUuid == nil || hardwareUuid != *host.HardwareUuid {
+		secLog.WithField("host_id", hostId).Errorf("controllers/host_evidence_controller:SubmitEvidence() %s : The hardware UUID does not match the registered host", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The hardware UUID does not match the registered host"}
+	}
+
+	if status, err := controller.verifyHostAik(hostId, evidence.Aik); err != nil {
+		return nil, status, err
+	}
+
+	hostManifest, err := hcUtil.GetHostManifestFromTpmQuote(evidence.Nonce, evidence.GetTpmQuoteResponse())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/host_evidence_controller:SubmitEvidence() %s : Error verifying TPM quote", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to verify the TPM quote"}
+	}
+	hostManifest.HostInfo = evidence.HostInfo
+	hostManifest.BindingKeyCertificate = evidence.BindingKeyCertificate
+
+	if err := controller.HTManager.VerifyHostDataAsync(*host, &hostManifest, false); err != nil {
+		defaultLog.WithError(err).Error("controllers/host_evidence_controller:SubmitEvidence() Host to Flavor Verify Queue addition failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to add Host to Flavor Verify Queue"}
+	}
+
+	secLog.WithField("host_id", hostId).Infof("%s: Host evidence submitted by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return nil, http.StatusAccepted, nil
+}
+
+// consumeNonce checks that the nonce is the last unexpired nonce issued to the host. The issued nonce is discarded
+// on any attempt, so a host has to request a new one after a failed submission.
+func (controller *HostEvidenceController) consumeNonce(hostId uuid.UUID, nonce string) bool {
+	controller.nonceMtx.Lock()
+	defer controller.nonceMtx.Unlock()
+
+	issued, ok := controller.nonces[hostId]
+	if !ok {
+		return false
+	}
+	delete(controller.nonces, hostId)
+	return nonce != "" && time.Now().Before(issued.ExpiresAt) &&
+		subtle.ConstantTimeCompare([]byte(issued.Nonce), []byte(nonce)) == 1
+}
+
+// verifyHostAik checks that the evidence is quoted with the AIK of the host. The AIK must be the AIK of the last report
+// of the host, a host that has not been attested yet must present an AIK issued by the Privacy CA.
+func (controller *HostEvidenceController) verifyHostAik(hostId uuid.UUID, aik string) (int, error) {
+	var aikBlock *pem.Block
+	aikPem, err := base64.StdEncoding.DecodeString(aik)
+	if err == nil {
+		aikBlock, _ = pem.Decode(aikPem)
+	}
+	if aikBlock == nil {
+		secLog.WithField("host_id", hostId).Errorf("controllers/host_evidence_controller:verifyHostAik() %s : Invalid AIK certificate", commLogMsg.InvalidInputBadParam)
+		return http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid AIK certificate in the TPM quote"}
+	}
+
+	hostStatuses, err := controller.HSStore.Search(&models.HostStatusFilterCriteria{
+		HostId:        hostId,
+		LatestPerHost: true,
+		Limit:         1,
+	})
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", hostId).Error("controllers/host_evidence_controller:verifyHostAik() Host status search failed")
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve the status of the Host"}
+	}
+
+	if len(hostStatuses) > 0 && hostStatuses[0].HostManifest.AIKCertificate != "" {
+		hostAik, err := base64.StdEncoding.DecodeString(hostStatuses[0].HostManifest.AIKCertificate)
+		if err != nil {
+			defaultLog.WithError(err).WithField("id", hostId).Error("controllers/host_evidence_controller:verifyHostAik() Invalid AIK certificate in the host status")
+			return http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve the AIK of the Host"}
+		}
+		if !bytes.Equal(hostAik, aikBlock.Bytes) {
+			secLog.WithField("host_id", hostId).Errorf("controllers/host_evidence_controller:verifyHostAik() %s : The AIK does not match the AIK of the registered host", commLogMsg.InvalidInputBadParam)
+			return http.StatusBadRequest, &commErr.ResourceError{Message: "The AIK does not match the AIK of the registered host"}
+		}
+		return http.StatusOK, nil
+	}
+
+	aikCertificate, err := x509.ParseCertificate(aikBlock.Bytes)
+	if err != nil {
+		secLog.WithError(err).WithField("host_id", hostId).Errorf("controllers/host_evidence_controller:verifyHostAik() %s : Invalid AIK certificate", commLogMsg.InvalidInputBadParam)
+		return http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid AIK certificate in the TPM quote"}
+	}
+	var privacyCAs []x509.Certificate
+	if controller.CertStore != nil {
+		if privacyCA, ok := (*controller.CertStore)[models.CaCertTypesPrivacyCa.String()]; ok && privacyCA != nil {
+			privacyCAs = privacyCA.Certificates
+		}
+	}
+	if _, err := aikCertificate.Verify(x509.VerifyOptions{
+		Roots:     crypt.GetCertPool(privacyCAs),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		secLog.WithError(err).WithField("host_id", hostId).Errorf("controllers/host_evidence_controller:verifyHostAik() %s : The AIK is not issued by the Privacy CA", commLogMsg.InvalidInputBadParam)
+		return http.StatusBadRequest, &commErr.ResourceError{Message: "The AIK is not issued by the Privacy CA"}
+	}
+	return http.StatusOK, nil
+}
+
+func (controller *HostEvidenceController) retrieveHost(hostId uuid.UUID) (*hvs.Host, int, error) {
+	host, err := controller.HStore.Retrieve(hostId, nil)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).WithField("id", hostId).Error("controllers/host_evidence_controller:retrieveHost() Host with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Host with specified id does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", hostId).Error("controllers/host_evidence_controller:retrieveHost() Host retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Host from database"}
+	}
+	return host, http.StatusOK, nil
+}
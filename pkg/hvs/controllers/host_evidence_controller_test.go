@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
+	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
+	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HostEvidenceController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var hostEvidenceController *controllers.HostEvidenceController
+
+	const hostId = "ee37c360-7eae-4250-a677-6ee12adce8e2"
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		hostEvidenceController = controllers.NewHostEvidenceController(mocks.NewMockHostStore(),
+			mocks.NewMockHostStatusStore(), &smocks.MockHostTrustManager{}, mocks.NewFakeCertificatesStore(), time.Minute)
+		router.Handle("/hosts/{hId}/nonce", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostEvidenceController.RetrieveNonce))).Methods("GET")
+		router.Handle("/hosts/{hId}/evidence", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(hostEvidenceController.SubmitEvidence))).Methods("POST")
+	})
+
+	retrieveNonce := func(id string) *hvs.HostNonce {
+		req, err := http.NewRequest("GET", "/hosts/"+id+"/nonce", nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return nil
+		}
+		var hostNonce hvs.HostNonce
+		Expect(json.Unmarshal(w.Body.Bytes(), &hostNonce)).To(Succeed())
+		return &hostNonce
+	}
+
+	submitEvidence := func(id string, evidence hvs.HostEvidence) {
+		evidenceJson, err := json.Marshal(evidence)
+		Expect(err).NotTo(HaveOccurred())
+		req, err := http.NewRequest("POST", "/hosts/"+id+"/evidence", strings.NewReader(string(evidenceJson)))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	// hostAik returns the base64 encoded PEM AIK certificate of the last report of the host
+	hostAik := func() string {
+		var hostStatus hvs.HostStatus
+		Expect(json.Unmarshal([]byte(mocks.HostStatus1), &hostStatus)).To(Succeed())
+		aikDer, err := base64.StdEncoding.DecodeString(hostStatus.HostManifest.AIKCertificate)
+		Expect(err).NotTo(HaveOccurred())
+		return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: aikDer}))
+	}
+
+	// Specs for HTTP Get to "/hosts/{hId}/nonce"
+	Describe("Retrieve a nonce for a host", func() {
+		Context("Retrieve a nonce for a registered host", func() {
+			It("Should issue a nonce that expires", func() {
+				hostNonce := retrieveNonce(hostId)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(hostNonce.HostId.String()).To(Equal(hostId))
+				Expect(hostNonce.Nonce).NotTo(BeEmpty())
+				Expect(hostNonce.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Minute), 5*time.Second))
+
+				Expect(retrieveNonce(hostId).Nonce).NotTo(Equal(hostNonce.Nonce))
+			})
+		})
+		Context("Retrieve a nonce for a non-existent host", func() {
+			It("Should fail to issue a nonce", func() {
+				retrieveNonce("73755fda-c910-46be-821f-e8ddeab189e9")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/hosts/{hId}/evidence"
+	Describe("Submit evidence for a host", func() {
+		Context("Submit evidence without requesting a nonce", func() {
+			It("Should reject the evidence", func() {
+				submitEvidence(hostId, hvs.HostEvidence{Nonce: "ZGVhZGJlZWZkZWFkYmVlZmRlYWRiZWVmZGVhZGJlZWY="})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Submit evidence bound to a different nonce", func() {
+			It("Should reject the evidence and discard the issued nonce", func() {
+				hostNonce := retrieveNonce(hostId)
+				submitEvidence(hostId, hvs.HostEvidence{Nonce: "ZGVhZGJlZWZkZWFkYmVlZmRlYWRiZWVmZGVhZGJlZWY="})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+				evidence := hvs.HostEvidence{Nonce: hostNonce.Nonce}
+				evidence.HostInfo.HardwareUUID = "e57e5ea0-d465-461e-882d-1600090caa0d"
+				submitEvidence(hostId, evidence)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("nonce"))
+			})
+		})
+		Context("Submit evidence from a host with a different hardware UUID", func() {
+			It("Should reject the evidence", func() {
+				evidence := hvs.HostEvidence{Nonce: retrieveNonce(hostId).Nonce}
+				evidence.HostInfo.HardwareUUID = "73755fda-c910-46be-821f-e8ddeab189e9"
+				submitEvidence(hostId, evidence)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("hardware UUID"))
+			})
+		})
+		Context("Submit evidence without a hardware UUID", func() {
+			It("Should reject the evidence", func() {
+				submitEvidence(hostId, hvs.HostEvidence{Nonce: retrieveNonce(hostId).Nonce, Aik: hostAik()})
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("hardware UUID"))
+			})
+		})
+		Context("Submit evidence quoted with an AIK that is not the AIK of the host", func() {
+			It("Should reject the evidence", func() {
+				var tpmQuoteResponse taModel.TpmQuoteResponse
+				tpmQuoteXml, err := ioutil.ReadFile("../../lib/host-connector/test/sample_tpm_quote.xml")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(xml.Unmarshal(tpmQuoteXml, &tpmQuoteResponse)).To(Succeed())
+
+				evidence := hvs.HostEvidence{
+					Nonce: retrieveNonce(hostId).Nonce,
+					Aik:   tpmQuoteResponse.Aik,
+					Quote: tpmQuoteResponse.Quote,
+				}
+				evidence.HostInfo.HardwareUUID = "e57e5ea0-d465-461e-882d-1600090caa0d"
+				submitEvidence(hostId, evidence)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("AIK"))
+			})
+		})
+		Context("Submit evidence with an invalid quote", func() {
+			It("Should reject the evidence", func() {
+				evidence := hvs.HostEvidence{
+					Nonce: retrieveNonce(hostId).Nonce,
+					Aik:   hostAik(),
+					Quote: "aW52YWxpZA==",
+				}
+				evidence.HostInfo.HardwareUUID = "e57e5ea0-d465-461e-882d-1600090caa0d"
+				submitEvidence(hostId, evidence)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("TPM quote"))
+			})
+		})
+		Context("Submit evidence for a non-existent host", func() {
+			It("Should fail to find the host", func() {
+				submitEvidence("73755fda-c910-46be-821f-e8ddeab189e9", hvs.HostEvidence{Nonce: "bm9uY2U="})
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/utils"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// HostFetchQueueController contains logic for handling host data fetch queue API requests
+type HostFetchQueueController struct {
+	Store domain.HostFetchQueueStore
+}
+
+var hostFetchQueueSearchParams = map[string]bool{"hostId": true, "state": true, "limit": true}
+
+// Search returns the host data fetch queue entries matching the filter criteria
+func (controller HostFetchQueueController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_fetch_queue_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/host_fetch_queue_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), hostFetchQueueSearchParams); err != nil {
+		secLog.Errorf("controllers/host_fetch_queue_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getHostFetchQueueFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/host_fetch_queue_controller:Search() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	entries, err := controller.Store.Search(filter)
+	if err != nil {
+		defaultLog.WithError(err).Warn("controllers/host_fetch_queue_controller:Search() Host fetch queue search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Host fetch queue search operation failed"}
+	}
+	if entries == nil {
+		entries = []hvs.HostFetchQueueEntry{}
+	}
+
+	secLog.Infof("%s: Return Host Fetch Queue Search query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.HostFetchQueueEntryCollection{Entries: entries}, http.StatusOK, nil
+}
+
+// Retrieve returns the host data fetch queue entry of a host
+func (controller HostFetchQueueController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_fetch_queue_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/host_fetch_queue_controller:Retrieve() Leaving")
+
+	entry, status, err := controller.retrieveEntry(r)
+	if err != nil {
+		return nil, status, err
+	}
+	return entry, http.StatusOK, nil
+}
+
+// Reset closes the circuit breaker of a host and clears its backoff, so that its pending fetch is attempted again
+// right away
+func (controller HostFetchQueueController) Reset(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/host_fetch_queue_controller:Reset() Entering")
+	defer defaultLog.Trace("controllers/host_fetch_queue_controller:Reset() Leaving")
+
+	entry, status, err := controller.retrieveEntry(r)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// the entry stays in Retrying state so that the retry scheduler picks it up on its next run
+	if entry.State != hvs.HostFetchStatePending {
+		entry.State = hvs.HostFetchStateRetrying
+	}
+	entry.Attempts = 0
+	entry.NextAttempt = time.Now()
+	if err = controller.Store.Persist(entry); err != nil {
+		defaultLog.WithError(err).WithField("id", entry.HostId).Error(
+			"controllers/host_fetch_queue_controller:Reset() Failed to reset host fetch queue entry")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to reset host fetch queue entry"}
+	}
+
+	secLog.WithField("id", entry.HostId).Infof("%s: Host fetch queue entry reset by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return entry, http.StatusOK, nil
+}
+
+func (controller HostFetchQueueController) retrieveEntry(r *http.Request) (*hvs.HostFetchQueueEntry, int, error) {
+	id, err := uuid.Parse(mux.Vars(r)["hId"])
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", mux.Vars(r)["hId"]).Warn(
+			"controllers/host_fetch_queue_controller:retrieveEntry() Invalid UUID format of the identifier provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid UUID format of the identifier provided"}
+	}
+
+	entry, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).WithField("id", id).Warn(
+				"controllers/host_fetch_queue_controller:retrieveEntry() Host with given ID is not in the fetch queue")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Host with given ID is not in the fetch queue"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Warn(
+			"controllers/host_fetch_queue_controller:retrieveEntry() Failed to retrieve host fetch queue entry")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve host fetch queue entry"}
+	}
+	return entry, http.StatusOK, nil
+}
+
+// getHostFetchQueueFilterCriteria checks for set filter params in the Search request and returns a valid
+// HostFetchQueueFilterCriteria
+func getHostFetchQueueFilterCriteria(params url.Values) (*models.HostFetchQueueFilterCriteria, error) {
+	defaultLog.Trace("controllers/host_fetch_queue_controller:getHostFetchQueueFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/host_fetch_queue_controller:getHostFetchQueueFilterCriteria() Leaving")
+
+	filter := models.HostFetchQueueFilterCriteria{}
+
+	if hostId := strings.TrimSpace(params.Get("hostId")); hostId != "" {
+		id, err := uuid.Parse(hostId)
+		if err != nil {
+			return nil, errors.New("Invalid UUID format of the Host Identifier specified")
+		}
+		filter.HostId = id
+	}
+
+	if state := strings.TrimSpace(params.Get("state")); state != "" {
+		filter.State = hvs.HostFetchState(state)
+		if !filter.State.Valid() {
+			return nil, errors.New("state must be one of Pending, Retrying or CircuitOpen")
+		}
+	}
+
+	if rowLimit := strings.TrimSpace(params.Get("limit")); rowLimit != "" {
+		limit, err := strconv.Atoi(rowLimit)
+		if err != nil || limit <= 0 {
+			return nil, errors.New("Limit must be an integer > 0")
+		}
+		filter.Limit = limit
+	} else {
+		filter.Limit = constants.DefaultSearchResultRowLimit
+	}
+
+	return &filter, nil
+}
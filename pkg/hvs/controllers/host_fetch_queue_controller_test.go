@@ -0,0 +1,181 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HostFetchQueueController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var hostFetchQueueStore *mocks2.MockHostFetchQueueStore
+	var hostFetchQueueController *controllers.HostFetchQueueController
+
+	retryingHostId := uuid.MustParse("47a3b602-f321-4e03-b3b2-8f3ca3cde128")
+	circuitOpenHostId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		hostFetchQueueStore = mocks2.NewMockHostFetchQueueStore()
+		hostFetchQueueController = &controllers.HostFetchQueueController{Store: hostFetchQueueStore}
+
+		Expect(hostFetchQueueStore.Persist(&hvs.HostFetchQueueEntry{
+			HostId:      retryingHostId,
+			State:       hvs.HostFetchStateRetrying,
+			Attempts:    2,
+			NextAttempt: time.Now().Add(time.Minute),
+			LastError:   "could not connect to host",
+		})).To(Succeed())
+		Expect(hostFetchQueueStore.Persist(&hvs.HostFetchQueueEntry{
+			HostId:      circuitOpenHostId,
+			State:       hvs.HostFetchStateCircuitOpen,
+			Attempts:    5,
+			NextAttempt: time.Now().Add(30 * time.Minute),
+			LastError:   "could not connect to host",
+		})).To(Succeed())
+	})
+
+	// Specs for HTTP Get to "/host-fetch-queue"
+	Describe("Search host fetch queue", func() {
+		Context("When no filter arguments are passed", func() {
+			It("All host fetch queue entries are returned", func() {
+				router.Handle("/host-fetch-queue", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-fetch-queue", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.HostFetchQueueEntryCollection
+				err = json.Unmarshal(w.Body.Bytes(), &collection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(collection.Entries)).To(Equal(2))
+			})
+		})
+
+		Context("When filtered by state", func() {
+			It("Should get the entries in that state", func() {
+				router.Handle("/host-fetch-queue", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-fetch-queue?state=CircuitOpen", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.HostFetchQueueEntryCollection
+				err = json.Unmarshal(w.Body.Bytes(), &collection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(collection.Entries)).To(Equal(1))
+				Expect(collection.Entries[0].HostId).To(Equal(circuitOpenHostId))
+			})
+		})
+
+		Context("When filtered by an invalid state", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/host-fetch-queue", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-fetch-queue?state=Broken", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When invalid filter arguments are passed", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/host-fetch-queue", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-fetch-queue?badParam=true", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/host-fetch-queue/{hId}"
+	Describe("Retrieve host fetch queue entry", func() {
+		Context("When the host is in the fetch queue", func() {
+			It("Should get the entry of the host", func() {
+				router.Handle("/host-fetch-queue/{hId}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-fetch-queue/"+retryingHostId.String(), nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var entry hvs.HostFetchQueueEntry
+				err = json.Unmarshal(w.Body.Bytes(), &entry)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entry.State).To(Equal(hvs.HostFetchStateRetrying))
+				Expect(entry.Attempts).To(Equal(2))
+			})
+		})
+
+		Context("When the host is not in the fetch queue", func() {
+			It("Should get a 404 error", func() {
+				router.Handle("/host-fetch-queue/{hId}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/host-fetch-queue/73755fda-c910-46be-821f-e8ddeab189e9", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/host-fetch-queue/{hId}/reset"
+	Describe("Reset host fetch queue entry", func() {
+		Context("When the circuit of the host is open", func() {
+			It("Should clear the backoff and close the circuit", func() {
+				router.Handle("/host-fetch-queue/{hId}/reset", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Reset))).Methods("POST")
+				req, err := http.NewRequest("POST", "/host-fetch-queue/"+circuitOpenHostId.String()+"/reset", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				entry, err := hostFetchQueueStore.Retrieve(circuitOpenHostId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entry.State).To(Equal(hvs.HostFetchStateRetrying))
+				Expect(entry.Attempts).To(Equal(0))
+				Expect(entry.NextAttempt.After(time.Now())).To(BeFalse())
+			})
+		})
+
+		Context("When the host is not in the fetch queue", func() {
+			It("Should get a 404 error", func() {
+				router.Handle("/host-fetch-queue/{hId}/reset", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(hostFetchQueueController.Reset))).Methods("POST")
+				req, err := http.NewRequest("POST", "/host-fetch-queue/73755fda-c910-46be-821f-e8ddeab189e9/reset", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -30,7 +30,8 @@ type HostStatusController struct {
 }
 
 var hostStatusSearchParams = map[string]bool{"id": true, "hostId": true, "hostHardwareId": true, "hostName": true, "hostStatus": true,
-	"fromDate": true, "toDate": true, "latestPerHost": true, "numberOfDays": true, "limit": true}
+	"fromDate": true, "toDate": true, "latestPerHost": true, "numberOfDays": true, "limit": true, "after": true, "fields": true,
+	"labelSelector": true}
 
 // Search returns a collection of HostStatus based on HostStatusFilter criteria
 func (controller HostStatusController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -55,8 +56,26 @@ func (controller HostStatusController) Search(w http.ResponseWriter, r *http.Req
 		return nil, http.StatusInternalServerError, errors.Errorf("Host Status search operation failed")
 	}
 
+	hsCollection := hvs.HostStatusCollection{HostStatuses: hostStatusCollection}
+	if len(hostStatusCollection) > 0 && latestHostStatusSearch(filter) {
+		hsCollection.Next = nextPageLink(r, filter.Limit, len(hostStatusCollection),
+			hostStatusCollection[len(hostStatusCollection)-1].ID)
+	}
+
+	result, err := selectFields(r.URL.Query(), hsCollection)
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/hoststatus_controller:Search() %s ", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
 	secLog.Infof("%s: Return Host Status Search query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
-	return hvs.HostStatusCollection{HostStatuses: hostStatusCollection}, http.StatusOK, nil
+	return result, http.StatusOK, nil
+}
+
+// latestHostStatusSearch returns true when the criteria select the latest status of each host, the only host statuses
+// that can be paged by cursor, the history of the host statuses is read from the audit log
+func latestHostStatusSearch(hfc *models.HostStatusFilterCriteria) bool {
+	return hfc.LatestPerHost && hfc.FromDate.IsZero() && hfc.ToDate.IsZero()
 }
 
 // Retrieve returns an existing HostStatus entry from the HostStatusStore
@@ -142,6 +161,16 @@ func getHSFilterCriteria(params url.Values) (*models.HostStatusFilterCriteria, e
 		hfc.HostStatus = hostState
 	}
 
+	// Host labels
+	labelSelector := strings.TrimSpace(params.Get("labelSelector"))
+	if labelSelector != "" {
+		selector, err := hvs.ParseLabelSelector(labelSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "Valid contents for labelSelector must be specified")
+		}
+		hfc.LabelSelector = selector
+	}
+
 	// fromDate
 	fromDate := strings.TrimSpace(params.Get("fromDate"))
 	if fromDate != "" {
@@ -201,5 +230,17 @@ func getHSFilterCriteria(params url.Values) (*models.HostStatusFilterCriteria, e
 		hfc.Limit = constants.DefaultSearchResultRowLimit
 	}
 
+	// after - the host statuses are paged by cursor only when searching the latest status of each host
+	if cursor := strings.TrimSpace(params.Get("after")); cursor != "" {
+		after, err := uuid.Parse(cursor)
+		if err != nil {
+			return nil, errors.New("Invalid UUID format of the after HostStatus Identifier specified")
+		}
+		if !latestHostStatusSearch(&hfc) {
+			return nil, errors.New("after is only supported when searching the latest status of each host")
+		}
+		hfc.After = after
+	}
+
 	return &hfc, nil
 }
@@ -0,0 +1,229 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/utils"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// preferHeader is the request header asking, with the respond-async preference, for a long running operation to
+	// be processed as a job
+	preferHeader       = "Prefer"
+	preferRespondAsync = "respond-async"
+	preferenceApplied  = "Preference-Applied"
+	jobsEndpointPath   = "/jobs/"
+)
+
+// JobController contains logic for handling job API requests
+type JobController struct {
+	Store   domain.JobStore
+	Manager domain.JobManager
+}
+
+var jobSearchParams = map[string]bool{"type": true, "status": true, "limit": true}
+
+// Search returns the jobs matching the filter criteria, most recent first
+func (controller JobController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/job_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/job_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), jobSearchParams); err != nil {
+		secLog.Errorf("controllers/job_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getJobFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/job_controller:Search() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	jobs, err := controller.Store.Search(filter)
+	if err != nil {
+		defaultLog.WithError(err).Warn("controllers/job_controller:Search() Job search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Job search operation failed"}
+	}
+	if jobs == nil {
+		jobs = []hvs.Job{}
+	}
+
+	secLog.Infof("%s: Return Job Search query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.JobCollection{Jobs: jobs}, http.StatusOK, nil
+}
+
+// Retrieve returns the progress and the outcome of a job
+func (controller JobController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/job_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/job_controller:Retrieve() Leaving")
+
+	job, status, err := retrieveJob(controller.Store, mux.Vars(r)["id"])
+	if err != nil {
+		return nil, status, err
+	}
+	secLog.WithField("id", job.Id).Infof("%s: Job retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return job, http.StatusOK, nil
+}
+
+// Cancel stops a job that is still pending or running
+func (controller JobController) Cancel(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/job_controller:Cancel() Entering")
+	defer defaultLog.Trace("controllers/job_controller:Cancel() Leaving")
+
+	job, status, err := retrieveJob(controller.Store, mux.Vars(r)["id"])
+	if err != nil {
+		return nil, status, err
+	}
+	if job.Status.Done() {
+		defaultLog.WithField("id", job.Id).Warn("controllers/job_controller:Cancel() Job has already finished")
+		return nil, http.StatusConflict, &commErr.ResourceError{Message: "Job has already finished"}
+	}
+
+	job, err = controller.Manager.Cancel(job.Id)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/job_controller:Cancel() Failed to cancel job")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to cancel job"}
+	}
+
+	secLog.WithField("id", job.Id).Infof("%s: Job cancelled by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return job, http.StatusOK, nil
+}
+
+func retrieveJob(store domain.JobStore, jobId string) (*hvs.Job, int, error) {
+	id, err := uuid.Parse(jobId)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", jobId).Warn(
+			"controllers/job_controller:retrieveJob() Invalid UUID format of the identifier provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid UUID format of the identifier provided"}
+	}
+
+	job, err := store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			defaultLog.WithError(err).WithField("id", id).Warn(
+				"controllers/job_controller:retrieveJob() Job with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Job with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Warn(
+			"controllers/job_controller:retrieveJob() Failed to retrieve job")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve job"}
+	}
+	return job, http.StatusOK, nil
+}
+
+// getJobFilterCriteria checks for set filter params in the Search request and returns a valid JobFilterCriteria
+func getJobFilterCriteria(params url.Values) (*models.JobFilterCriteria, error) {
+	defaultLog.Trace("controllers/job_controller:getJobFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/job_controller:getJobFilterCriteria() Leaving")
+
+	filter := models.JobFilterCriteria{}
+
+	if jobType := strings.TrimSpace(params.Get("type")); jobType != "" {
+		filter.Type = hvs.JobType(strings.ToUpper(jobType))
+	}
+
+	if statuses := strings.TrimSpace(params.Get("status")); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			jobStatus := hvs.JobStatus(strings.TrimSpace(status))
+			if !jobStatus.Valid() {
+				return nil, errors.New("status must be one of Pending, Running, Completed, Failed or Cancelled")
+			}
+			filter.Status = append(filter.Status, jobStatus)
+		}
+	}
+
+	if rowLimit := strings.TrimSpace(params.Get("limit")); rowLimit != "" {
+		limit, err := strconv.Atoi(rowLimit)
+		if err != nil || limit <= 0 {
+			return nil, errors.New("Limit must be an integer > 0")
+		}
+		filter.Limit = limit
+	} else {
+		filter.Limit = constants.DefaultSearchResultRowLimit
+	}
+
+	return &filter, nil
+}
+
+// preferAsync returns true when the client asks, with the "Prefer: respond-async" header, for the request to be
+// processed as a job
+func preferAsync(r *http.Request) bool {
+	for _, header := range r.Header.Values(preferHeader) {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), preferRespondAsync) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// submitJob runs fn as a job and returns the 202 Accepted response holding the job, the Location header links to the
+// job resource
+func submitJob(w http.ResponseWriter, r *http.Request, jm domain.JobManager, jobType hvs.JobType, fn domain.JobFunc) (interface{}, int, error) {
+	defaultLog.Trace("controllers/job_controller:submitJob() Entering")
+	defer defaultLog.Trace("controllers/job_controller:submitJob() Leaving")
+
+	job, err := jm.Submit(jobType, fn)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("controllers/job_controller:submitJob() Failed to submit job of type %s", jobType)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to start job"}
+	}
+
+	w.Header().Set("Location", jobLink(r, job.Id))
+	w.Header().Set(preferenceApplied, preferRespondAsync)
+	secLog.WithField("job", job.Id).Infof("%s: Job of type %s started by: %s", commLogMsg.PrivilegeModified, jobType, r.RemoteAddr)
+	return job, http.StatusAccepted, nil
+}
+
+// jobLink returns the path of a job resource under the same service prefix as the request
+func jobLink(r *http.Request, id uuid.UUID) string {
+	path := r.URL.Path
+	if i := strings.Index(path, constants.ApiVersion+"/"); i >= 0 {
+		return path[:i+len(constants.ApiVersion)] + jobsEndpointPath + id.String()
+	}
+	return jobsEndpointPath + id.String()
+}
+
+// verifyHostsJob returns the job verifying the hosts the way VerifyHostsAsync does in the background. Unlike the
+// flavor verify queue, the job verifies the hosts one after the other so that it reports the outcome of each of them,
+// the hosts left once the job is cancelled are not verified.
+func verifyHostsJob(htm domain.HostTrustManager, hostIds []uuid.UUID, fetchHostData, preferHashMatch bool) domain.JobFunc {
+	return func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		results := make([]hvs.HostVerifyResult, 0, len(hostIds))
+		for i, hostId := range hostIds {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			result := hvs.HostVerifyResult{HostId: hostId}
+			hvsReport, err := htm.VerifyHost(ctx, hostId, fetchHostData, preferHashMatch)
+			if err != nil {
+				defaultLog.WithError(err).Errorf("controllers/job_controller:verifyHostsJob() Failed to verify host %s", hostId)
+				result.Error = "Failed to verify host"
+			} else if hvsReport != nil {
+				trusted := hvsReport.TrustReport.IsTrusted()
+				result.Trusted = &trusted
+			}
+			results = append(results, result)
+			report(i+1, len(hostIds), results)
+		}
+		return results, nil
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/jobs"
+	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JobController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var jobStore *mocks.MockJobStore
+	var jobManager *jobs.Manager
+
+	BeforeEach(func() {
+		var err error
+		router = mux.NewRouter()
+		jobStore = mocks.NewMockJobStore()
+		jobManager, err = jobs.NewManager(jobStore, "hvs-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		jobController := controllers.JobController{Store: jobStore, Manager: jobManager}
+		router.Handle("/jobs", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(jobController.Search))).Methods("GET")
+		router.Handle("/jobs/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(jobController.Retrieve))).Methods("GET")
+		router.Handle("/jobs/{id}/cancel", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(jobController.Cancel))).Methods("POST")
+	})
+
+	AfterEach(func() {
+		jobManager.Stop()
+	})
+
+	serve := func(method, url string) {
+		req, err := http.NewRequest(method, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	waitForStatus := func(id string, status hvs.JobStatus) *hvs.Job {
+		var job hvs.Job
+		Eventually(func() hvs.JobStatus {
+			serve("GET", "/jobs/"+id)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(json.Unmarshal(w.Body.Bytes(), &job)).To(Succeed())
+			return job.Status
+		}, 5*time.Second, 20*time.Millisecond).Should(Equal(status))
+		return &job
+	}
+
+	// Specs for HTTP Get to "/jobs/{id}"
+	Describe("Retrieve a job", func() {
+		Context("Retrieve a completed job", func() {
+			It("Should return the result of the job", func() {
+				job, err := jobManager.Submit(hvs.JobTypeFlavorCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+					report(1, 2, nil)
+					return hvs.SignedFlavorCollection{SignedFlavors: []hvs.SignedFlavor{}}, nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				completed := waitForStatus(job.Id.String(), hvs.JobStatusCompleted)
+				Expect(completed.Type).To(Equal(hvs.JobTypeFlavorCreate))
+				Expect(completed.Processed).To(Equal(2))
+				Expect(completed.Total).To(Equal(2))
+				Expect(string(completed.Result)).To(Equal(`{"signed_flavors":[]}`))
+			})
+		})
+		Context("Retrieve a job that does not exist", func() {
+			It("Should return not found", func() {
+				serve("GET", "/jobs/73755fda-c910-46be-821f-e8ddeab189e9")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/jobs/{id}/cancel"
+	Describe("Cancel a job", func() {
+		Context("Cancel a running job", func() {
+			It("Should stop the job and mark it as cancelled", func() {
+				stopped := make(chan struct{})
+				job, err := jobManager.Submit(hvs.JobTypeTagDeploy, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+					<-ctx.Done()
+					close(stopped)
+					return nil, ctx.Err()
+				})
+				Expect(err).NotTo(HaveOccurred())
+				waitForStatus(job.Id.String(), hvs.JobStatusRunning)
+
+				serve("POST", "/jobs/"+job.Id.String()+"/cancel")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Eventually(stopped).Should(BeClosed())
+				waitForStatus(job.Id.String(), hvs.JobStatusCancelled)
+
+				serve("POST", "/jobs/"+job.Id.String()+"/cancel")
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/jobs"
+	Describe("Search jobs", func() {
+		Context("Search the failed jobs", func() {
+			It("Should only return the failed jobs", func() {
+				failed, err := jobManager.Submit(hvs.JobTypeReportCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+					return nil, context.DeadlineExceeded
+				})
+				Expect(err).NotTo(HaveOccurred())
+				completed, err := jobManager.Submit(hvs.JobTypeReportCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+					return nil, nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+				waitForStatus(failed.Id.String(), hvs.JobStatusFailed)
+				waitForStatus(completed.Id.String(), hvs.JobStatusCompleted)
+
+				serve("GET", "/jobs?type=report_create&status=Failed")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var jobCollection hvs.JobCollection
+				Expect(json.Unmarshal(w.Body.Bytes(), &jobCollection)).To(Succeed())
+				Expect(jobCollection.Jobs).To(HaveLen(1))
+				Expect(jobCollection.Jobs[0].Id).To(Equal(failed.Id))
+				Expect(jobCollection.Jobs[0].Error).To(Equal(context.DeadlineExceeded.Error()))
+			})
+		})
+		Context("Search with an invalid status", func() {
+			It("Should return bad request", func() {
+				serve("GET", "/jobs?status=Done")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
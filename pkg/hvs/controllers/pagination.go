@@ -0,0 +1,182 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// limitParam, afterParam and fieldsParam are the query parameters of the search endpoints selecting a page of the
+	// results and the fields of the items in the page
+	limitParam  = "limit"
+	afterParam  = "after"
+	fieldsParam = "fields"
+)
+
+// getPageCriteria parses the limit and after query parameters of a search request, the page is not limited when the
+// limit is 0 and starts at the first result when after is uuid.Nil
+func getPageCriteria(params url.Values) (int, uuid.UUID, error) {
+	defaultLog.Trace("controllers/pagination:getPageCriteria() Entering")
+	defer defaultLog.Trace("controllers/pagination:getPageCriteria() Leaving")
+
+	var limit int
+	var after uuid.UUID
+	var err error
+
+	if rowLimit := strings.TrimSpace(params.Get(limitParam)); rowLimit != "" {
+		limit, err = strconv.Atoi(rowLimit)
+		if err != nil || limit <= 0 {
+			return 0, uuid.Nil, errors.New("Limit must be an integer > 0")
+		}
+	}
+
+	if cursor := strings.TrimSpace(params.Get(afterParam)); cursor != "" {
+		after, err = uuid.Parse(cursor)
+		if err != nil {
+			return 0, uuid.Nil, errors.New("Invalid after query param value, must be UUID")
+		}
+	}
+	return limit, after, nil
+}
+
+// nextPageLink returns the link to the page following a page of count results ending with the result identified by
+// last. The link is empty when the page is not limited or not full, in which case it is the last page.
+func nextPageLink(r *http.Request, limit, count int, last uuid.UUID) string {
+	if limit <= 0 || count < limit {
+		return ""
+	}
+	query := r.URL.Query()
+	query.Set(afterParam, last.String())
+	next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return next.String()
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// selectFields applies the fields query parameter of a search request to a collection. When the parameter is set, the
+// items of the collection are reduced to the comma separated list of top level fields it names.
+func selectFields(params url.Values, collection interface{}) (interface{}, error) {
+	defaultLog.Trace("controllers/pagination:selectFields() Entering")
+	defer defaultLog.Trace("controllers/pagination:selectFields() Leaving")
+
+	fieldList := strings.TrimSpace(params.Get(fieldsParam))
+	if fieldList == "" {
+		return collection, nil
+	}
+
+	itemsKey, itemFields := collectionItemFields(reflect.TypeOf(collection))
+	if itemsKey == "" {
+		return nil, errors.New("Field selection is not supported for this collection")
+	}
+	selected := map[string]bool{}
+	for _, field := range strings.Split(fieldList, ",") {
+		field = strings.TrimSpace(field)
+		// the fields of items with a custom JSON encoding are not known, only the keys of the items are filtered
+		if itemFields != nil && !itemFields[field] {
+			return nil, errors.Errorf("Invalid fields query param value, unknown field %s", field)
+		}
+		selected[field] = true
+	}
+
+	data, err := json.Marshal(collection)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to serialize collection")
+	}
+	var result map[string]json.RawMessage
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, errors.Wrap(err, "Failed to deserialize collection")
+	}
+	var items []map[string]json.RawMessage
+	if err = json.Unmarshal(result[itemsKey], &items); err != nil {
+		return nil, errors.Wrap(err, "Failed to deserialize collection items")
+	}
+	for _, item := range items {
+		for field := range item {
+			if !selected[field] {
+				delete(item, field)
+			}
+		}
+	}
+	if items == nil {
+		items = []map[string]json.RawMessage{}
+	}
+	if result[itemsKey], err = json.Marshal(items); err != nil {
+		return nil, errors.Wrap(err, "Failed to serialize collection items")
+	}
+	return result, nil
+}
+
+// collectionItemFields returns the JSON name of the list of items of a collection and the JSON names of the fields of
+// an item, the names of the fields are nil when the items have a custom JSON encoding
+func collectionItemFields(t reflect.Type) (string, map[string]bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Slice {
+			continue
+		}
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		if field.Type.Elem().Implements(jsonMarshalerType) || reflect.PtrTo(field.Type.Elem()).Implements(jsonMarshalerType) {
+			return name, nil
+		}
+		itemFields := map[string]bool{}
+		addJSONFieldNames(field.Type.Elem(), itemFields)
+		return name, itemFields
+	}
+	return "", nil
+}
+
+// addJSONFieldNames adds the JSON names of the fields of a struct, including the fields of the embedded structs, to
+// names
+func addJSONFieldNames(t reflect.Type, names map[string]bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			addJSONFieldNames(field.Type, names)
+			continue
+		}
+		if name := jsonFieldName(field); name != "" {
+			names[name] = true
+		}
+	}
+}
+
+// jsonFieldName returns the name of a struct field in its JSON encoding, it is empty when the field is not encoded
+func jsonFieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+// queueRecorder records the hosts added to the flavor verify queue
+type queueRecorder struct {
+	smocks.MockHostTrustManager
+	fetched  []uuid.UUID
+	verified []uuid.UUID
+}
+
+func (recorder *queueRecorder) VerifyHostsAsync(hostIds []uuid.UUID, fetchHostData, preferHashMatch bool) error {
+	if fetchHostData {
+		recorder.fetched = append(recorder.fetched, hostIds...)
+	} else {
+		recorder.verified = append(recorder.verified, hostIds...)
+	}
+	return nil
+}
+
+func TestVerifyHostsOnFlavorChange(t *testing.T) {
+	// the linked host belongs to the flavorgroup, the other host has no flavorgroup
+	linkedHostId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+	otherHostId := uuid.MustParse("e57e5ea0-d465-461e-882d-1600090caa0d")
+	fgId := uuid.MustParse("e57e5ea0-d465-461e-882d-1600090caa0d")
+
+	hostStore := mocks.NewMockHostStore()
+	fgStore := mocks.NewFakeFlavorgroupStore()
+	assert.NoError(t, fgStore.UpdateRefreshSchedule(fgId, &hvs.RefreshSchedule{RefreshOnEvent: true}))
+	hostIds := []uuid.UUID{linkedHostId, otherHostId}
+
+	// the refresh on event of the flavorgroup applies to its hosts
+	recorder := &queueRecorder{}
+	assert.NoError(t, verifyHostsOnFlavorChange(recorder, hostStore, fgStore, hostIds, false))
+	assert.Equal(t, []uuid.UUID{linkedHostId}, recorder.fetched)
+	assert.Equal(t, []uuid.UUID{otherHostId}, recorder.verified)
+
+	// the override of a host takes precedence over the schedule of its flavorgroups
+	linkedHost, err := hostStore.Retrieve(linkedHostId, nil)
+	assert.NoError(t, err)
+	linkedHost.RefreshSchedule = &hvs.RefreshSchedule{IntervalSeconds: 3600}
+	otherHost, err := hostStore.Retrieve(otherHostId, nil)
+	assert.NoError(t, err)
+	otherHost.RefreshSchedule = &hvs.RefreshSchedule{RefreshOnEvent: true}
+
+	recorder = &queueRecorder{}
+	assert.NoError(t, verifyHostsOnFlavorChange(recorder, hostStore, fgStore, hostIds, false))
+	assert.Equal(t, []uuid.UUID{otherHostId}, recorder.fetched)
+	assert.Equal(t, []uuid.UUID{linkedHostId}, recorder.verified)
+
+	// a disabled override turns off the refresh on event of the flavorgroups of the host
+	linkedHost.RefreshSchedule = &hvs.RefreshSchedule{Disabled: true}
+	otherHost.RefreshSchedule = nil
+	recorder = &queueRecorder{}
+	assert.NoError(t, verifyHostsOnFlavorChange(recorder, hostStore, fgStore, hostIds, false))
+	assert.Empty(t, recorder.fetched)
+	assert.Equal(t, hostIds, recorder.verified)
+
+	// the caller can still force all the hosts to fetch new host data
+	recorder = &queueRecorder{}
+	assert.NoError(t, verifyHostsOnFlavorChange(recorder, hostStore, fgStore, hostIds, true))
+	assert.Equal(t, hostIds, recorder.fetched)
+	assert.Empty(t, recorder.verified)
+}
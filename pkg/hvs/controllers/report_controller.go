@@ -5,6 +5,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -15,24 +16,30 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
 	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 )
 
+var reportDiffParams = map[string]bool{"against": true}
+
 type ReportController struct {
 	ReportStore     domain.ReportStore
 	HostStore       domain.HostStore
 	HostStatusStore domain.HostStatusStore
 	HTManager       domain.HostTrustManager
+	// JobManager, when set, runs the report creation as a job for the clients asking for an asynchronous response
+	JobManager domain.JobManager
 }
 
 func NewReportController(rs domain.ReportStore, hs domain.HostStore, hsts domain.HostStatusStore, ht domain.HostTrustManager) *ReportController {
-	return &ReportController{rs, hs, hsts, ht}
+	return &ReportController{ReportStore: rs, HostStore: hs, HostStatusStore: hsts, HTManager: ht}
 }
 
 func (controller ReportController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -69,7 +76,33 @@ func (controller ReportController) Create(w http.ResponseWriter, r *http.Request
 	if requestType != "" && requestType == "async" {
 		async = true
 	}
-	hvsReport, err := controller.createReport(reqReportCreateRequest, async)
+
+	// with process=async, the job verifies the host and holds the outcome of the verification instead of only queuing
+	// the host for verification. Otherwise, the job creates the report and holds it as its result.
+	if async && controller.JobManager != nil && preferAsync(r) {
+		hsCriteria := getHostFilterCriteria(reqReportCreateRequest)
+		hosts, err := controller.HostStore.Search(&hsCriteria, nil)
+		if err != nil {
+			defaultLog.WithError(err).Error("controllers/report_controller:Create() Error while searching host")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while searching host"}
+		}
+		if len(hosts) == 0 {
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Host for given criteria does not exist"}
+		}
+		return submitJob(w, r, controller.JobManager, hvs.JobTypeHostVerify,
+			verifyHostsJob(controller.HTManager, []uuid.UUID{hosts[0].Id}, true, true))
+	}
+	if !async && controller.JobManager != nil && preferAsync(r) {
+		return submitJob(w, r, controller.JobManager, hvs.JobTypeReportCreate,
+			func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+				hvsReport, err := controller.createReport(ctx, reqReportCreateRequest, false)
+				if err != nil {
+					return nil, err
+				}
+				return ConvertToReport(hvsReport), nil
+			})
+	}
+	hvsReport, err := controller.createReport(r.Context(), reqReportCreateRequest, async)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/report_controller:Create() Error while creating report")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
@@ -90,9 +123,12 @@ func (controller ReportController) Create(w http.ResponseWriter, r *http.Request
 	return report, http.StatusCreated, nil
 }
 
-func (controller ReportController) createReport(rsCriteria hvs.ReportCreateRequest, async bool) (*models.HVSReport, error) {
+func (controller ReportController) createReport(ctx context.Context, rsCriteria hvs.ReportCreateRequest, async bool) (_ *models.HVSReport, err error) {
 	defaultLog.Trace("controllers/report_controller:createReport() Entering")
 	defer defaultLog.Trace("controllers/report_controller:createReport() Leaving")
+	ctx, span := tracing.StartSpan(ctx, "controllers.createReport", attribute.Bool("report.async", async))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	hsCriteria := getHostFilterCriteria(rsCriteria)
 	hosts, err := controller.HostStore.Search(&hsCriteria, nil)
 	if err != nil {
@@ -105,6 +141,7 @@ func (controller ReportController) createReport(rsCriteria hvs.ReportCreateReque
 
 	//Always only one record is returned for the particular criteria
 	hostId := hosts[0].Id
+	span.SetAttributes(attribute.String("host.id", hostId.String()))
 	// if async is true, process the request to create the report asynchronously by adding the host to queue
 	if async {
 		defaultLog.Debugf("controllers/report_controller:createReport() Adding host %s to queue to process request to create sync report", hostId.String())
@@ -116,8 +153,10 @@ func (controller ReportController) createReport(rsCriteria hvs.ReportCreateReque
 		return nil, nil
 	}
 
-	hvsReport, err := controller.HTManager.VerifyHost(hostId, true, true)
-	if err != nil {
+	hvsReport, err := controller.HTManager.VerifyHost(ctx, hostId, true, true)
+	if err != nil && ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "Report creation cancelled")
+	} else if err != nil {
 		defaultLog.WithError(err).Errorf("controllers/report_controller:createReport() Failed to create a trust report, flavor verification failed")
 	} else if hvsReport == nil {
 		return nil, errors.New("Error while creating a report, no rules to be applied")
@@ -167,7 +206,7 @@ func (controller ReportController) CreateSaml(w http.ResponseWriter, r *http.Req
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Bad input given in input request"}
 	}
 
-	hvsReport, err := controller.createReport(reqReportCreateRequest, false)
+	hvsReport, err := controller.createReport(r.Context(), reqReportCreateRequest, false)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/report_controller:CreateSaml() Error while creating SAML report")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
@@ -205,6 +244,88 @@ func (controller ReportController) Retrieve(w http.ResponseWriter, r *http.Reque
 	return report, http.StatusOK, nil
 }
 
+// Diff compares a report with another report of the same host or, when 'against' is 'latest-trusted', with the
+// most recent trusted report of the host that was created before it
+func (controller ReportController) Diff(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/report_controller:Diff() Entering")
+	defer defaultLog.Trace("controllers/report_controller:Diff() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), reportDiffParams); err != nil {
+		secLog.Errorf("controllers/report_controller:Diff() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	hvsReport, status, err := controller.retrieveReport(id)
+	if err != nil {
+		return nil, status, err
+	}
+
+	var againstReport *models.HVSReport
+	against := strings.TrimSpace(r.URL.Query().Get("against"))
+	switch against {
+	case "":
+		secLog.Errorf("controllers/report_controller:Diff() %s : against query parameter is required", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Report to compare with must be specified with the against query parameter"}
+	case consts.ReportDiffLatestTrusted:
+		againstReport, err = controller.ReportStore.FindLatestTrustedReport(hvsReport.HostID, hvsReport.CreatedAt)
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RowsNotFound) {
+				return nil, http.StatusNotFound, &commErr.ResourceError{Message: "No trusted report of the host exists before the given report"}
+			}
+			defaultLog.WithError(err).WithField("id", id).Error("controllers/report_controller:Diff() Failed to retrieve latest trusted report")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve latest trusted report"}
+		}
+	default:
+		againstId, err := uuid.Parse(against)
+		if err != nil {
+			secLog.WithError(err).Errorf("controllers/report_controller:Diff() %s : Invalid against query parameter", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid UUID format of the report to compare with"}
+		}
+		againstReport, status, err = controller.retrieveReport(againstId)
+		if err != nil {
+			return nil, status, err
+		}
+		if againstReport.HostID != hvsReport.HostID {
+			secLog.Errorf("controllers/report_controller:Diff() %s : Reports belong to different hosts", commLogMsg.InvalidInputProtocolViolation)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Reports to compare must belong to the same host"}
+		}
+	}
+
+	diff := hvsReport.TrustReport.Diff(&againstReport.TrustReport)
+	diff.ReportID = hvsReport.ID
+	diff.AgainstReportID = againstReport.ID
+	diff.HostID = hvsReport.HostID
+	diff.CreatedAt = hvsReport.CreatedAt
+	diff.AgainstCreatedAt = againstReport.CreatedAt
+
+	secLog.WithField("id", id).Infof("%s: Report diff retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return diff, http.StatusOK, nil
+}
+
+// retrieveReport looks up a report among the latest reports of the hosts and falls back to the reports that have
+// been replaced since
+func (controller ReportController) retrieveReport(id uuid.UUID) (*models.HVSReport, int, error) {
+	defaultLog.Trace("controllers/report_controller:retrieveReport() Entering")
+	defer defaultLog.Trace("controllers/report_controller:retrieveReport() Leaving")
+
+	hvsReport, err := controller.ReportStore.Retrieve(id)
+	if err != nil && strings.Contains(err.Error(), commErr.RowsNotFound) {
+		hvsReport, err = controller.ReportStore.RetrieveFromAuditLog(id)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Info(
+				"controllers/report_controller:retrieveReport() Report with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Report with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/report_controller:retrieveReport() failed to retrieve Report")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Report"}
+	}
+	return hvsReport, http.StatusOK, nil
+}
+
 func (controller ReportController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/report_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/report_controller:Search() Leaving")
@@ -233,8 +354,18 @@ func (controller ReportController) Search(w http.ResponseWriter, r *http.Request
 	for _, hvsReport := range hvsReportCollection {
 		reportCollection.Reports = append(reportCollection.Reports, ConvertToReport(&hvsReport))
 	}
+	if len(hvsReportCollection) > 0 && latestReportSearch(reportFilterCriteria) {
+		reportCollection.Next = nextPageLink(r, reportFilterCriteria.Limit, len(hvsReportCollection),
+			hvsReportCollection[len(hvsReportCollection)-1].ID)
+	}
+
+	result, err := selectFields(r.URL.Query(), reportCollection)
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/report_controller:Search() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
 	secLog.Infof("%s: Reports searched by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
-	return reportCollection, http.StatusOK, nil
+	return result, http.StatusOK, nil
 }
 
 func (controller ReportController) SearchSaml(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -331,6 +462,16 @@ func getReportFilterCriteria(params url.Values) (*models.ReportFilterCriteria, e
 		rfc.HostStatus = hostState
 	}
 
+	// Host labels
+	labelSelector := strings.TrimSpace(params.Get("labelSelector"))
+	if labelSelector != "" {
+		selector, err := hvs.ParseLabelSelector(labelSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "Valid contents for labelSelector must be specified")
+		}
+		rfc.LabelSelector = selector
+	}
+
 	// fromDate
 	fromDate := strings.TrimSpace(params.Get("fromDate"))
 	if fromDate != "" {
@@ -384,9 +525,27 @@ func getReportFilterCriteria(params url.Values) (*models.ReportFilterCriteria, e
 		rfc.Limit = consts.DefaultSearchResultRowLimit
 	}
 
+	// after - the reports are paged by cursor only when searching the latest report of each host
+	if cursor := strings.TrimSpace(params.Get("after")); cursor != "" {
+		after, err := uuid.Parse(cursor)
+		if err != nil {
+			return nil, errors.New("Invalid UUID format of the after Report Identifier specified")
+		}
+		if !latestReportSearch(&rfc) {
+			return nil, errors.New("after is only supported when searching the latest report of each host")
+		}
+		rfc.After = after
+	}
+
 	return &rfc, nil
 }
 
+// latestReportSearch returns true when the criteria select the latest report of each host, the only reports that can be
+// paged by cursor, the history of the reports is read from the audit log
+func latestReportSearch(rfc *models.ReportFilterCriteria) bool {
+	return rfc.LatestPerHost && rfc.FromDate.IsZero() && rfc.ToDate.IsZero() && rfc.NumberOfDays == 0
+}
+
 func validateReportCreateCriteria(re hvs.ReportCreateRequest) error {
 	defaultLog.Trace("controllers/report_controller:validateReportCreateCriteria() Entering")
 	defer defaultLog.Trace("controllers/report_controller:validateReportCreateCriteria() Leaving")
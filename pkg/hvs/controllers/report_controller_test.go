@@ -12,6 +12,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
 	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/jobs"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	. "github.com/onsi/ginkgo"
@@ -19,6 +20,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 )
 
 var _ = Describe("ReportController", func() {
@@ -63,6 +65,49 @@ var _ = Describe("ReportController", func() {
 			})
 		})
 
+		Context("Provide a valid asynchronous Create request asking for a job", func() {
+			It("Should verify the host in a job", func() {
+				jobStore := mocks.NewMockJobStore()
+				jobManager, err := jobs.NewManager(jobStore, "hvs-test")
+				Expect(err).NotTo(HaveOccurred())
+				defer jobManager.Stop()
+				reportController.JobManager = jobManager
+				router.Handle("/reports", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Create))).Methods("POST")
+				body := `{
+							"host_name": "localhost1"
+						}`
+
+				req, err := http.NewRequest(
+					"POST",
+					"/reports?process=async",
+					strings.NewReader(body),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				req.Header.Set("Prefer", "respond-async")
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusAccepted))
+
+				var job hvs.Job
+				Expect(json.Unmarshal(w.Body.Bytes(), &job)).To(Succeed())
+				Expect(job.Type).To(Equal(hvs.JobTypeHostVerify))
+				Eventually(func() hvs.JobStatus {
+					current, err := jobStore.Retrieve(job.Id)
+					Expect(err).NotTo(HaveOccurred())
+					job = *current
+					return job.Status
+				}, 5*time.Second, 20*time.Millisecond).Should(Equal(hvs.JobStatusCompleted))
+
+				var results []hvs.HostVerifyResult
+				Expect(json.Unmarshal(job.Result, &results)).To(Succeed())
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Error).To(BeEmpty())
+				Expect(results[0].Trusted).NotTo(BeNil())
+			})
+		})
+
 		Context("Provide a valid Create request for which host is not registered", func() {
 			It("Should return bad request", func() {
 				router.Handle("/reports", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Create))).Methods("POST")
@@ -174,6 +219,65 @@ var _ = Describe("ReportController", func() {
 		})
 	})
 
+	// Specs for HTTP Get to "/reports/{id}/diff"
+	Describe("Diff Reports", func() {
+		Context("Diff Report against itself", func() {
+			It("Should return an empty diff", func() {
+				router.Handle("/reports/{id}/diff", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Diff))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff?against=15701f03-7b1d-49f9-ac62-6b9b0728bdb3", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var diff hvs.TrustReportDiff
+				err = json.Unmarshal(w.Body.Bytes(), &diff)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(diff.AgainstReportID.String()).To(Equal("15701f03-7b1d-49f9-ac62-6b9b0728bdb3"))
+				Expect(diff.FlavorParts).To(BeEmpty())
+				Expect(diff.PcrValues).To(BeEmpty())
+				Expect(diff.EventLogs).To(BeEmpty())
+			})
+		})
+
+		Context("Diff Report against a Report of another host", func() {
+			It("Should fail to diff Reports", func() {
+				router.Handle("/reports/{id}/diff", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Diff))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff?against=15701f03-7b1d-49f9-ac62-6b9b0728bdb4", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Diff Report against the latest trusted Report when there is none", func() {
+			It("Should fail to find the latest trusted Report", func() {
+				router.Handle("/reports/{id}/diff", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Diff))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff?against=latest-trusted", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("Diff Report without the against parameter", func() {
+			It("Should fail to diff Reports", func() {
+				router.Handle("/reports/{id}/diff", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Diff))).Methods("GET")
+				req, err := http.NewRequest("GET", "/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
 	// Specs for HTTP Get to "/reports"
 	Describe("Search for all the Reports", func() {
 		Context("Get all the Reports", func() {
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/utils"
+	consts "github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// SubscriptionController contains logic for handling event subscription API requests
+type SubscriptionController struct {
+	Store domain.SubscriptionStore
+}
+
+const (
+	subscriptionSecretLength   = 32
+	maxSubscriptionEndpointLen = 2048
+)
+
+var (
+	subscriptionSearchParams = map[string]bool{"deliveryType": true, "limit": true}
+
+	// NATS subjects are dot separated tokens, wildcards are not allowed for publishing. The events are only
+	// published under hvs.events, so that they cannot be sent on the subjects of the trust agents or other services.
+	natsSubjectReg = regexp.MustCompile(`^hvs\.events(\.[A-Za-z0-9_\-]+)*$`)
+)
+
+// Create registers a new event subscription. The returned subscription holds the secret of the delivery signatures,
+// it is not returned again afterwards.
+func (controller SubscriptionController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Create() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/subscription_controller:Create() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqSubscription hvs.Subscription
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&reqSubscription); err != nil {
+		secLog.WithError(err).Errorf("controllers/subscription_controller:Create() %s : Failed to decode request body as Subscription", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateSubscription(&reqSubscription); err != nil {
+		secLog.WithError(err).Errorf("controllers/subscription_controller:Create() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	secret := make([]byte, subscriptionSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		defaultLog.WithError(err).Error("controllers/subscription_controller:Create() Failed to generate subscription secret")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create subscription"}
+	}
+	reqSubscription.Secret = base64.StdEncoding.EncodeToString(secret)
+
+	newSubscription, err := controller.Store.Create(&reqSubscription)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/subscription_controller:Create() Subscription save failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create subscription"}
+	}
+
+	secLog.WithField("id", newSubscription.Id).Infof("%s: Subscription created by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return newSubscription, http.StatusCreated, nil
+}
+
+// Search returns the event subscriptions matching the filter criteria
+func (controller SubscriptionController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), subscriptionSearchParams); err != nil {
+		secLog.Errorf("controllers/subscription_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getSubscriptionFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/subscription_controller:Search() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	subscriptions, err := controller.Store.Search(filter)
+	if err != nil {
+		defaultLog.WithError(err).Warn("controllers/subscription_controller:Search() Subscription search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Subscription search operation failed"}
+	}
+	if subscriptions == nil {
+		subscriptions = []hvs.Subscription{}
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	secLog.Infof("%s: Return Subscription Search query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.SubscriptionCollection{Subscriptions: subscriptions}, http.StatusOK, nil
+}
+
+// Retrieve returns an event subscription
+func (controller SubscriptionController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Retrieve() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	subscription, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Warn(
+				"controllers/subscription_controller:Retrieve() Subscription with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Subscription with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/subscription_controller:Retrieve() Failed to retrieve Subscription")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Subscription"}
+	}
+	subscription.Secret = ""
+	return subscription, http.StatusOK, nil
+}
+
+// Delete removes an event subscription, the pending deliveries of the subscription are still attempted
+func (controller SubscriptionController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Delete() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Delete() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	if err := controller.Store.Delete(id); err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Warn(
+				"controllers/subscription_controller:Delete() Subscription with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Subscription with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Error(
+			"controllers/subscription_controller:Delete() Failed to delete Subscription")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete Subscription"}
+	}
+	secLog.WithField("id", id).Infof("Subscription deleted by: %s", r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+func validateSubscription(s *hvs.Subscription) error {
+	defaultLog.Trace("controllers/subscription_controller:validateSubscription() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:validateSubscription() Leaving")
+
+	if s.Id != uuid.Nil || !s.Created.IsZero() {
+		return errors.New("id and created cannot be specified")
+	}
+	if s.Secret != "" {
+		return errors.New("secret cannot be specified, it is generated by HVS")
+	}
+	if len(s.Endpoint) == 0 || len(s.Endpoint) > maxSubscriptionEndpointLen {
+		return errors.New("endpoint must be specified")
+	}
+
+	switch s.DeliveryType {
+	case hvs.SubscriptionDeliveryWebhook:
+		endpoint, err := url.Parse(s.Endpoint)
+		if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+			return errors.New("endpoint of a webhook must be an https URL")
+		}
+	case hvs.SubscriptionDeliveryNats:
+		if !natsSubjectReg.MatchString(s.Endpoint) {
+			return errors.New("endpoint of a nats subscription must be hvs.events or a subject under it, without wildcards")
+		}
+	default:
+		return errors.New("delivery_type must be webhook or nats")
+	}
+
+	for _, eventType := range s.Filter.EventTypes {
+		if !eventType.Valid() {
+			return errors.Errorf("event type %s is not supported", eventType)
+		}
+	}
+	return nil
+}
+
+// getSubscriptionFilterCriteria checks for set filter params in the Search request and returns a valid
+// SubscriptionFilterCriteria
+func getSubscriptionFilterCriteria(params url.Values) (*models.SubscriptionFilterCriteria, error) {
+	defaultLog.Trace("controllers/subscription_controller:getSubscriptionFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:getSubscriptionFilterCriteria() Leaving")
+
+	filter := models.SubscriptionFilterCriteria{}
+
+	if deliveryType := strings.TrimSpace(params.Get("deliveryType")); deliveryType != "" {
+		filter.DeliveryType = hvs.SubscriptionDeliveryType(deliveryType)
+		if !filter.DeliveryType.Valid() {
+			return nil, errors.New("deliveryType must be webhook or nats")
+		}
+	}
+
+	if rowLimit := strings.TrimSpace(params.Get("limit")); rowLimit != "" {
+		limit, err := strconv.Atoi(rowLimit)
+		if err != nil || limit <= 0 {
+			return nil, errors.New("Limit must be an integer > 0")
+		}
+		filter.Limit = limit
+	} else {
+		filter.Limit = constants.DefaultSearchResultRowLimit
+	}
+
+	return &filter, nil
+}
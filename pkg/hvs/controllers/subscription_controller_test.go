@@ -0,0 +1,253 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v4/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SubscriptionController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var subscriptionStore *mocks2.MockSubscriptionStore
+	var subscriptionController *controllers.SubscriptionController
+	var existing *hvs.Subscription
+
+	BeforeEach(func() {
+		var err error
+		router = mux.NewRouter()
+		subscriptionStore = mocks2.NewMockSubscriptionStore()
+		subscriptionController = &controllers.SubscriptionController{Store: subscriptionStore}
+
+		existing, err = subscriptionStore.Create(&hvs.Subscription{
+			DeliveryType: hvs.SubscriptionDeliveryNats,
+			Endpoint:     "hvs.events",
+			Secret:       "secret",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// Specs for HTTP Post to "/subscriptions"
+	Describe("Create Subscription", func() {
+		Context("When a valid webhook subscription is provided", func() {
+			It("Should create the subscription and return its secret", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				body := `{
+					"delivery_type": "webhook",
+					"endpoint": "https://siem.example.com/hvs",
+					"filter": {
+						"flavorgroup_ids": ["ee37c360-7eae-4250-a677-6ee12adce8e2"],
+						"event_types": ["trust_changed", "connection_failure"]
+					}
+				}`
+				req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var subscription hvs.Subscription
+				err = json.Unmarshal(w.Body.Bytes(), &subscription)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(subscription.Secret).NotTo(BeEmpty())
+				Expect(subscription.Filter.EventTypes).To(HaveLen(2))
+			})
+		})
+
+		Context("When the webhook endpoint is not an https URL", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				body := `{"delivery_type": "webhook", "endpoint": "http://siem.example.com/hvs"}`
+				req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When the NATS subject has wildcards", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				body := `{"delivery_type": "nats", "endpoint": "hvs.events.*"}`
+				req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When a valid NATS subscription is provided", func() {
+			It("Should create the subscription", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				body := `{"delivery_type": "nats", "endpoint": "hvs.events.siem"}`
+				req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+			})
+		})
+
+		Context("When the NATS subject is not under hvs.events", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				for _, subject := range []string{"trust-agent.ee37c360-7eae-4250-a677-6ee12adce8e2.deploy-manifest", "hvs", "hvs.eventsx"} {
+					body := `{"delivery_type": "nats", "endpoint": "` + subject + `"}`
+					req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+					req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+					w = httptest.NewRecorder()
+					router.ServeHTTP(w, req)
+					Expect(w.Code).To(Equal(http.StatusBadRequest))
+				}
+			})
+		})
+
+		Context("When an unknown event type is provided", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				body := `{"delivery_type": "nats", "endpoint": "hvs.events", "filter": {"event_types": ["host_deleted"]}}`
+				req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When a secret is provided", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods("POST")
+				body := `{"delivery_type": "nats", "endpoint": "hvs.events", "secret": "mysecret"}`
+				req, err := http.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/subscriptions"
+	Describe("Search Subscriptions", func() {
+		Context("When filtered by delivery type", func() {
+			It("Should get the subscriptions without their secret", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/subscriptions?deliveryType=nats", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.SubscriptionCollection
+				err = json.Unmarshal(w.Body.Bytes(), &collection)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(collection.Subscriptions).To(HaveLen(1))
+				Expect(collection.Subscriptions[0].Secret).To(BeEmpty())
+			})
+		})
+
+		Context("When an invalid delivery type is provided", func() {
+			It("Should get a 400 error", func() {
+				router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/subscriptions?deliveryType=email", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/subscriptions/{id}"
+	Describe("Retrieve Subscription", func() {
+		Context("When the subscription exists", func() {
+			It("Should get the subscription without its secret", func() {
+				router.Handle("/subscriptions/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/subscriptions/"+existing.Id.String(), nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var subscription hvs.Subscription
+				err = json.Unmarshal(w.Body.Bytes(), &subscription)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(subscription.Endpoint).To(Equal("hvs.events"))
+				Expect(subscription.Secret).To(BeEmpty())
+			})
+		})
+
+		Context("When the subscription does not exist", func() {
+			It("Should get a 404 error", func() {
+				router.Handle("/subscriptions/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Retrieve))).Methods("GET")
+				req, err := http.NewRequest("GET", "/subscriptions/73755fda-c910-46be-821f-e8ddeab189e9", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Delete to "/subscriptions/{id}"
+	Describe("Delete Subscription", func() {
+		Context("When the subscription exists", func() {
+			It("Should delete the subscription", func() {
+				router.Handle("/subscriptions/{id}", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(subscriptionController.Delete))).Methods("DELETE")
+				req, err := http.NewRequest("DELETE", "/subscriptions/"+existing.Id.String(), nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				_, err = subscriptionStore.Retrieve(existing.Id)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("When the subscription does not exist", func() {
+			It("Should get a 404 error", func() {
+				router.Handle("/subscriptions/{id}", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(subscriptionController.Delete))).Methods("DELETE")
+				req, err := http.NewRequest("DELETE", "/subscriptions/73755fda-c910-46be-821f-e8ddeab189e9", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -5,6 +5,7 @@
 package controllers
 
 import (
+	"context"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
@@ -48,6 +49,8 @@ type TagCertificateController struct {
 	FlavorController FlavorController
 	// HostConnectorProvider is required for providing a HostConnector for connecting to the host during the Deploy Tag Certificate workflow
 	HostConnectorProvider hostConnector.HostConnectorProvider
+	// JobManager, when set, runs the Deploy Tag Certificate workflow as a job for the clients asking for an asynchronous response
+	JobManager domain.JobManager
 }
 
 func NewTagCertificateController(tc domain.TagCertControllerConfig, certStore models.CertificatesStore, tcs domain.TagCertificateStore,
@@ -179,7 +182,8 @@ func (controller TagCertificateController) Search(w http.ResponseWriter, r *http
 	defer defaultLog.Trace("controllers/tagcertificate_controller:Search() Leaving")
 
 	var tagCertSearchParams = map[string]bool{"id": true, "hardwareUuid": true, "subjectContains": true, "subjectEqualTo": true,
-		"issuerContains": true, "issuerEqualTo": true, "validOn": true, "validBefore": true, "validAfter": true}
+		"issuerContains": true, "issuerEqualTo": true, "validOn": true, "validBefore": true, "validAfter": true,
+		"limit": true, "after": true, "fields": true}
 
 	if err := utils.ValidateQueryParams(r.URL.Query(), tagCertSearchParams); err != nil {
 		secLog.Errorf("controllers/tagcertificate_controller:Search() %s", err.Error())
@@ -205,9 +209,18 @@ func (controller TagCertificateController) Search(w http.ResponseWriter, r *http
 	}
 
 	tagCertCollection := hvs.TagCertificateCollection{TagCertificates: tagCertResultSet}
+	if len(tagCertResultSet) > 0 {
+		tagCertCollection.Next = nextPageLink(r, filter.Limit, len(tagCertResultSet), tagCertResultSet[len(tagCertResultSet)-1].ID)
+	}
+
+	result, err := selectFields(r.URL.Query(), tagCertCollection)
+	if err != nil {
+		defaultLog.Errorf("controllers/tagcertificate_controller:Search() %s : %s", commLogMsg.InvalidInputBadParam, err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
 
 	secLog.Infof("%s: Return TagCertificate Search query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
-	return tagCertCollection, http.StatusOK, nil
+	return result, http.StatusOK, nil
 }
 
 // Delete deletes an existing TagCertificate from the backend by its unique ID
@@ -356,6 +369,13 @@ func getTCFilterCriteria(params url.Values) (*models.TagCertificateFilterCriteri
 		tagCertFc.HardwareUUID = hwUUID
 	}
 
+	limit, after, err := getPageCriteria(params)
+	if err != nil {
+		return nil, err
+	}
+	tagCertFc.Limit = limit
+	tagCertFc.After = after
+
 	return &tagCertFc, nil
 }
 
@@ -439,13 +459,41 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	targetHost := hosts[0]
 	defaultLog.WithField("HardwareUUID", targetHost.HardwareUuid).Debugf("controllers/tagcertificate_controller:Deploy() Found Host with ID %s", targetHost.Id)
 
+	// deploying the tag and creating its flavor can take a while, the client can ask for it to be processed by a job
+	if controller.JobManager != nil && preferAsync(r) {
+		remoteAddr := r.RemoteAddr
+		return submitJob(w, r, controller.JobManager, hvs.JobTypeTagDeploy,
+			func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+				sf, _, err := controller.deployTagCertificate(ctx, tc, targetHost)
+				if err != nil {
+					return nil, err
+				}
+				secLog.WithField("Certid", tc.ID).WithField("HardwareUUID", targetHost.HardwareUuid).Infof("%s: TagCertificate deployed by: %s", commLogMsg.PrivilegeModified, remoteAddr)
+				return sf, nil
+			})
+	}
+
+	sf, status, err := controller.deployTagCertificate(r.Context(), tc, targetHost)
+	if err != nil {
+		return nil, status, err
+	}
+	secLog.WithField("Certid", dtcReq.CertID).WithField("HardwareUUID", targetHost.HardwareUuid).Infof("%s: TagCertificate deployed by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return sf, http.StatusOK, nil
+}
+
+// deployTagCertificate deploys the asset tag certificate to the host, then creates the ASSET_TAG flavor of the host and
+// queues the host for flavor verification
+func (controller TagCertificateController) deployTagCertificate(ctx context.Context, tc *hvs.TagCertificate, targetHost *hvs.Host) (*hvs.SignedFlavor, int, error) {
+	defaultLog.Trace("controllers/tagcertificate_controller:deployTagCertificate() Entering")
+	defer defaultLog.Trace("controllers/tagcertificate_controller:deployTagCertificate() Leaving")
+
 	// populate service credentials for AAS
 	hostConnStr := fmt.Sprintf("%s;u=%s;p=%s", targetHost.ConnectionString, controller.Config.ServiceUsername, controller.Config.ServicePassword)
 
 	// initialize HostConnector and test connectivity
-	hc, err := controller.HostConnectorProvider.NewHostConnector(hostConnStr)
+	hc, err := controller.HostConnectorProvider.NewHostConnectorWithContext(ctx, hostConnStr)
 	if err != nil {
-		defaultLog.WithError(err).WithField("Certid", dtcReq.CertID).Error("controllers/tagcertificate_controller:Deploy() Failed "+
+		defaultLog.WithError(err).WithField("Certid", tc.ID).Error("controllers/tagcertificate_controller:deployTagCertificate() Failed "+
 			"to initialize HostConnector for host with hardware UUID %s", tc.HardwareUUID.String())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure: Target Host connection failed"}
 	}
@@ -453,7 +501,7 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	// DeployAssetTag
 	err = asset_tag.NewAssetTag().DeployAssetTag(hc, tc.TagCertDigest, targetHost.HardwareUuid.String())
 	if err != nil {
-		defaultLog.WithError(err).WithField("Certid", dtcReq.CertID).Error("controllers/tagcertificate_controller:Deploy() Failed "+
+		defaultLog.WithError(err).WithField("Certid", tc.ID).Error("controllers/tagcertificate_controller:deployTagCertificate() Failed "+
 			"to deploy Asset Tag on Host %s", targetHost.HardwareUuid)
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
@@ -461,28 +509,28 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	// get Host Manifest
 	hmanifest, err := hc.GetHostManifest(nil)
 	if err != nil {
-		defaultLog.WithField("id", dtcReq.CertID).Error("controllers/tagcertificate_controller:Deploy() Failed "+
+		defaultLog.WithField("id", tc.ID).Error("controllers/tagcertificate_controller:deployTagCertificate() Failed "+
 			"to get the HostManifest from Host %s", targetHost.HardwareUuid.String())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
 
 	newX509TC, err := x509.ParseCertificate(tc.Certificate)
 	if err != nil {
-		defaultLog.WithField("Certid", dtcReq.CertID).Errorf("controllers/tagcertificate_controller:Deploy() %s : Failed to parse x509.Certificate from TagCert %s", commLogMsg.AppRuntimeErr, err.Error())
+		defaultLog.WithField("Certid", tc.ID).Errorf("controllers/tagcertificate_controller:deployTagCertificate() %s : Failed to parse x509.Certificate from TagCert %s", commLogMsg.AppRuntimeErr, err.Error())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
 
 	// Create AssetTag Flavor for the Host
 	fProvider, err := flavor.NewPlatformFlavorProvider(&hmanifest, newX509TC, nil)
 	if err != nil {
-		defaultLog.WithField("Certid", dtcReq.CertID).Errorf("controllers/tagcertificate_controller:Deploy() %s : Failed to initialize FlavorProvider %s", commLogMsg.AppRuntimeErr, err.Error())
+		defaultLog.WithField("Certid", tc.ID).Errorf("controllers/tagcertificate_controller:deployTagCertificate() %s : Failed to initialize FlavorProvider %s", commLogMsg.AppRuntimeErr, err.Error())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
 
 	// get the asset tag flavor
 	assetTagFlavor, err := fProvider.GetPlatformFlavor()
 	if err != nil {
-		defaultLog.WithField("Certid", dtcReq.CertID).Errorf("controllers/tagcertificate_controller:Deploy() %s : Failed to generate AssetTag Flavor %s", commLogMsg.AppRuntimeErr, err.Error())
+		defaultLog.WithField("Certid", tc.ID).Errorf("controllers/tagcertificate_controller:deployTagCertificate() %s : Failed to generate AssetTag Flavor %s", commLogMsg.AppRuntimeErr, err.Error())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
 
@@ -492,13 +540,13 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 	// get the signed flavor
 	unsignedFlavors, err := (*assetTagFlavor).GetFlavorPartRaw(hvs.FlavorPartAssetTag)
 	if err != nil {
-		defaultLog.WithField("Certid", dtcReq.CertID).Errorf("controllers/tagcertificate_controller:Deploy() %s : Error while getting unsigned Flavor %s", commLogMsg.AppRuntimeErr, err.Error())
+		defaultLog.WithField("Certid", tc.ID).Errorf("controllers/tagcertificate_controller:deployTagCertificate() %s : Error while getting unsigned Flavor %s", commLogMsg.AppRuntimeErr, err.Error())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
 
 	sf, err := util.PlatformFlavorUtil{}.GetSignedFlavor(&unsignedFlavors[0], flavorSignKey.(*rsa.PrivateKey))
 	if err != nil {
-		defaultLog.WithField("Certid", dtcReq.CertID).Errorf("controllers/tagcertificate_controller:Deploy() %s : Error while getting signed Flavor %s", commLogMsg.AppRuntimeErr, err.Error())
+		defaultLog.WithField("Certid", tc.ID).Errorf("controllers/tagcertificate_controller:deployTagCertificate() %s : Error while getting signed Flavor %s", commLogMsg.AppRuntimeErr, err.Error())
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Tag Certificate Deploy failure"}
 	}
 
@@ -508,8 +556,8 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 
 	linkedSf, err := controller.FlavorController.addFlavorToFlavorgroup(flavorPartMap, nil)
 	if err != nil || linkedSf == nil {
-		defaultLog.WithError(err).WithField("Certid", dtcReq.CertID).WithField("flavorID", sf.Flavor.Meta.ID).
-			Errorf("controllers/tagcertificate_controller:Deploy() %s : Failed to link SignedFlavor to Host "+
+		defaultLog.WithError(err).WithField("Certid", tc.ID).WithField("flavorID", sf.Flavor.Meta.ID).
+			Errorf("controllers/tagcertificate_controller:deployTagCertificate() %s : Failed to link SignedFlavor to Host "+
 				"Unique FlavorGroup", commLogMsg.AppRuntimeErr)
 		if strings.Contains(err.Error(), "duplicate key") {
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavor with same id/label already exists"}
@@ -517,8 +565,7 @@ func (controller TagCertificateController) Deploy(w http.ResponseWriter, r *http
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error during Tag Certificate Deploy"}
 	}
 
-	defaultLog.WithField("Certid", dtcReq.CertID).WithField("flavorID", sf.Flavor.Meta.ID).Debugf("controllers/tagcertificate_controller:Deploy() : Created Asset Tag Deploy Cert")
+	defaultLog.WithField("Certid", tc.ID).WithField("flavorID", sf.Flavor.Meta.ID).Debugf("controllers/tagcertificate_controller:deployTagCertificate() : Created Asset Tag Deploy Cert")
 
-	secLog.WithField("Certid", dtcReq.CertID).WithField("HardwareUUID", targetHost.HardwareUuid).Infof("%s: TagCertificate deployed by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
 	return sf, http.StatusOK, nil
 }
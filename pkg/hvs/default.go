@@ -91,10 +91,17 @@ func init() {
 	viper.SetDefault(constants.FvsNumberOfDataFetchers, constants.DefaultFvsNumberOfDataFetchers)
 	viper.SetDefault(constants.FvsSkipFlavorSignatureVerification, constants.DefaultSkipFlavorSignatureVerification)
 	viper.SetDefault(constants.FvsHostTrustCacheThreshold, constants.DefaultHostTrustCacheThreshold)
+	viper.SetDefault(constants.FvsRetryBackoffBase, constants.DefaultFvsRetryBackoffBase)
+	viper.SetDefault(constants.FvsRetryBackoffMax, constants.DefaultFvsRetryBackoffMax)
+	viper.SetDefault(constants.FvsCircuitBreakerThreshold, constants.DefaultFvsCircuitBreakerThreshold)
+	viper.SetDefault(constants.FvsCircuitBreakerCooldown, constants.DefaultFvsCircuitBreakerCooldown)
 
 	viper.SetDefault(constants.HrrsRefreshPeriod, hrrs.DefaultRefreshPeriod)
 
 	viper.SetDefault(constants.VcssRefreshPeriod, constants.DefaultVcssRefreshPeriod)
+
+	viper.SetDefault(constants.TracingExporter, constants.DefaultTracingExporter)
+	viper.SetDefault(constants.TracingSampleRatio, constants.DefaultTracingSampleRatio)
 }
 
 func defaultConfig() *config.Configuration {
@@ -172,6 +179,16 @@ func defaultConfig() *config.Configuration {
 			NumberOfDataFetchers:            viper.GetInt(constants.FvsNumberOfDataFetchers),
 			SkipFlavorSignatureVerification: viper.GetBool(constants.FvsSkipFlavorSignatureVerification),
 			HostTrustCacheThreshold:         viper.GetInt(constants.FvsHostTrustCacheThreshold),
+			RetryBackoffBase:                viper.GetDuration(constants.FvsRetryBackoffBase),
+			RetryBackoffMax:                 viper.GetDuration(constants.FvsRetryBackoffMax),
+			CircuitBreakerThreshold:         viper.GetInt(constants.FvsCircuitBreakerThreshold),
+			CircuitBreakerCooldown:          viper.GetDuration(constants.FvsCircuitBreakerCooldown),
+		},
+		Tracing: commConfig.TracingConfig{
+			Exporter:    viper.GetString(constants.TracingExporter),
+			Endpoint:    viper.GetString(constants.TracingEndpoint),
+			Insecure:    viper.GetBool(constants.TracingInsecure),
+			SampleRatio: viper.GetFloat64(constants.TracingSampleRatio),
 		},
 		EnableEkCertRevokeChecks: viper.GetBool(constants.EnableEKCertRevokeCheck),
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/saml"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/verifier"
+	"time"
 )
 
 type HostTrustVerifierConfig struct {
@@ -23,6 +24,8 @@ type HostTrustVerifierConfig struct {
 	SamlIssuerConfig                saml.IssuerConfiguration
 	SkipFlavorSignatureVerification bool
 	HostTrustCache                  *lru.Cache
+	// EventPublisher is notified of the new reports and trust changes, it is optional
+	EventPublisher HostEventPublisher
 }
 
 type HostTrustMgrConfig struct {
@@ -37,12 +40,22 @@ type HostTrustMgrConfig struct {
 type HostDataFetcherConfig struct {
 	HostConnectorProvider host_connector.HostConnectorProvider
 	HostConnectionConfig  HostConnectionConfig
-	RetryTimeMinutes      int
-	HostStatusStore       HostStatusStore
-	HostStore             HostStore
-	FlavorGroupStore      FlavorGroupStore
-	FlavorStore           FlavorStore
-	HostTrustCache        *lru.Cache
+	// RetryBackoffBase is the delay before the first retry of a failed fetch, it doubles with each consecutive
+	// failure up to RetryBackoffMax
+	RetryBackoffBase time.Duration
+	RetryBackoffMax  time.Duration
+	// CircuitBreakerThreshold is the number of consecutive failed fetches after which a host is not contacted
+	// again before CircuitBreakerCooldown elapses
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+	HostStatusStore         HostStatusStore
+	HostStore               HostStore
+	FlavorGroupStore        FlavorGroupStore
+	FlavorStore             FlavorStore
+	HostFetchQueueStore     HostFetchQueueStore
+	HostTrustCache          *lru.Cache
+	// EventPublisher is notified of the failed fetches, it is optional
+	EventPublisher HostEventPublisher
 }
 
 type HostControllerConfig struct {
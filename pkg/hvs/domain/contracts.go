@@ -11,8 +11,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
 )
 
+// ErrJobCancelled is returned by JobStore.Update when the job was cancelled, a cancelled job is never overwritten
+var ErrJobCancelled = errors.New("job was cancelled")
+
 type (
 	FlavorGroupStore interface {
 		Create(*hvs.FlavorGroup) (*hvs.FlavorGroup, error)
@@ -28,6 +32,8 @@ type (
 		SearchFlavorTemplatesByFlavorGroup(fgID uuid.UUID) ([]uuid.UUID, error)
 		GetFlavorTypesInFlavorGroup(flvGrpId uuid.UUID) (map[hvs.FlavorPartName]bool, error)
 		AddFlavorTemplates(uuid.UUID, []uuid.UUID) error
+		UpdateRefreshSchedule(uuid.UUID, *hvs.RefreshSchedule) error
+		UpdateHostSelector(uuid.UUID, string) error
 	}
 
 	HostStore interface {
@@ -69,6 +75,8 @@ type (
 		Retrieve(uuid.UUID) (*hvs.SignedFlavor, error)
 		Search(*models.FlavorVerificationFC) ([]hvs.SignedFlavor, error)
 		Delete(uuid.UUID) error
+		Replace(uuid.UUID, *hvs.SignedFlavor) error
+		UpdateLifecycle(uuid.UUID, hvs.FlavorLifecycle) error
 	}
 
 	TpmEndorsementStore interface {
@@ -110,6 +118,23 @@ type (
 		Delete(uuid.UUID) error
 	}
 
+	// HostFetchQueueStore persists the pending host data fetches and their retry state
+	HostFetchQueueStore interface {
+		Persist(*hvs.HostFetchQueueEntry) error
+		Retrieve(uuid.UUID) (*hvs.HostFetchQueueEntry, error)
+		Search(*models.HostFetchQueueFilterCriteria) ([]hvs.HostFetchQueueEntry, error)
+		Delete(uuid.UUID) error
+	}
+
+	// JobStore persists the jobs of the long running operations along with their progress and outcome. Update
+	// returns ErrJobCancelled instead of updating a job that was cancelled.
+	JobStore interface {
+		Create(*hvs.Job) (*hvs.Job, error)
+		Retrieve(uuid.UUID) (*hvs.Job, error)
+		Search(*models.JobFilterCriteria) ([]hvs.Job, error)
+		Update(*hvs.Job) error
+	}
+
 	ReportStore interface {
 		Search(*models.ReportFilterCriteria) ([]models.HVSReport, error)
 		Retrieve(uuid.UUID) (*models.HVSReport, error)
@@ -117,6 +142,10 @@ type (
 		Update(*models.HVSReport) (*models.HVSReport, error)
 		Delete(uuid.UUID) error
 		FindHostIdsFromExpiredReports(fromTime time.Time, toTime time.Time) ([]uuid.UUID, error)
+		RetrieveFromAuditLog(uuid.UUID) (*models.HVSReport, error)
+		FindLatestTrustedReport(hostId uuid.UUID, before time.Time) (*models.HVSReport, error)
+		FindHostRefreshSchedules() ([]models.HostRefreshSchedule, error)
+		CountHostsByTrust() (trusted int, untrusted int, err error)
 	}
 
 	ESXiClusterStore interface {
@@ -139,7 +168,7 @@ type (
 	HostTrustManager interface {
 		// Verify the trust of the a host.
 		//Returns the host trust report. For now marking this as interface since we have not defined the report structure
-		VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error)
+		VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error)
 
 		// This method is an asynchronous method meant to do the verify the trust of the host
 		// asynchronously. The requests are persisted to Store in case the server is taken down.
@@ -150,6 +179,15 @@ type (
 		//                   doing a full report.
 		VerifyHostsAsync(hostIds []uuid.UUID, fetchHostData, preferHashMatch bool) error
 
+		// This method is an asynchronous method meant to verify the trust of a host with data the host submitted
+		// itself (push mode). The data is stored as the latest host status and handed to the verification queue
+		// through the HostDataReceiver path, replacing any pending job of the host.
+		VerifyHostDataAsync(host hvs.Host, hostData *hvs.HostManifest, preferHashMatch bool) error
+
+		// Predicts the trust of a host against a flavorgroup with candidate flavors and match policies, from the given
+		// host data. Nothing is persisted: the trust cache and the reports of the host are left untouched.
+		SimulateHostTrust(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error)
+
 		//Process all records stuck in queue post service restart
 		ProcessQueue() error
 	}
@@ -160,7 +198,7 @@ type (
 
 	HostDataFetcher interface {
 		// Synchronous method that blocks till the data is retrieved from the host.
-		Retrieve(host hvs.Host) (*hvs.HostManifest, error)
+		Retrieve(ctx context.Context, host hvs.Host) (*hvs.HostManifest, error)
 
 		// Asynchronous method to be used to fetch data from hosts. As soon as the request is registered,
 		// the method returns. The result is returned individually as they are processed.
@@ -173,7 +211,8 @@ type (
 	}
 
 	HostTrustVerifier interface {
-		Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData bool, preferHashMatch bool) (*models.HVSReport, error)
+		Verify(ctx context.Context, hostId uuid.UUID, hostData *hvs.HostManifest, newData bool, preferHashMatch bool) (*models.HVSReport, error)
+		Simulate(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error)
 	}
 
 	AuditLogWriter interface {
@@ -190,4 +229,30 @@ type (
 		Update(*models.AuditLogEntry) (*models.AuditLogEntry, error)
 		Delete(uuid.UUID) error
 	}
+	SubscriptionStore interface {
+		Create(*hvs.Subscription) (*hvs.Subscription, error)
+		Retrieve(uuid.UUID) (*hvs.Subscription, error)
+		Search(*models.SubscriptionFilterCriteria) ([]hvs.Subscription, error)
+		Delete(uuid.UUID) error
+	}
+
+	// JobManager runs long running operations in the background as jobs whose progress and outcome are persisted
+	JobManager interface {
+		// Submit creates a job of the given type and runs fn in the background. fn should stop when ctx is cancelled
+		// and can report its progress, along with a partial result, through report.
+		Submit(jobType hvs.JobType, fn JobFunc) (*hvs.Job, error)
+		// Cancel stops a job that is still pending or running
+		Cancel(id uuid.UUID) (*hvs.Job, error)
+	}
+
+	// HostEventPublisher notifies the subscribers of host events. Publish must not block the caller.
+	HostEventPublisher interface {
+		Publish(event hvs.HostEvent)
+	}
 )
+
+// JobFunc is the operation run by a job. It returns the result of the job, which is serialized to JSON.
+type JobFunc func(ctx context.Context, report JobProgressFunc) (interface{}, error)
+
+// JobProgressFunc reports the progress of a job. partial, when not nil, is stored as the current result of the job.
+type JobProgressFunc func(processed, total int, partial interface{})
@@ -606,12 +606,39 @@ func (store *MockFlavorStore) Search(criteria *models.FlavorVerificationFC) ([]h
 	return sfs, nil
 }
 
+// Replace stores a Flavor in place of an existing one
+func (store *MockFlavorStore) Replace(replacedId uuid.UUID, sf *hvs.SignedFlavor) error {
+	for i, f := range store.flavorStore {
+		if f.Flavor.Meta.ID == replacedId {
+			store.flavorStore[i] = hvs.SignedFlavor{
+				Flavor:    sf.Flavor,
+				Signature: sf.Signature,
+				Lifecycle: sf.Lifecycle,
+			}
+			return nil
+		}
+	}
+	return errors.New(commErr.RowsNotFound)
+}
+
+// UpdateLifecycle sets the lifecycle of a Flavor
+func (store *MockFlavorStore) UpdateLifecycle(id uuid.UUID, lifecycle hvs.FlavorLifecycle) error {
+	for i, f := range store.flavorStore {
+		if f.Flavor.Meta.ID == id {
+			store.flavorStore[i].Lifecycle = &lifecycle
+			return nil
+		}
+	}
+	return errors.New(commErr.RecordNotFound)
+}
+
 // Create inserts a Flavor
 func (store *MockFlavorStore) Create(sf *hvs.SignedFlavor) (*hvs.SignedFlavor, error) {
 	//It is not right way to directly append the pointer, reference will be copied. Copy only the values.
 	rec := hvs.SignedFlavor{
 		Flavor:    sf.Flavor,
 		Signature: sf.Signature,
+		Lifecycle: sf.Lifecycle,
 	}
 	store.flavorStore = append(store.flavorStore, rec)
 	return sf, nil
@@ -51,7 +51,7 @@ func (store *MockFlavorgroupStore) Search(criteria *models.FlavorGroupFilterCrit
 		flvrGroups = append(flvrGroups, *fg)
 	}
 
-	if criteria == nil {
+	if criteria == nil || (len(criteria.Ids) == 0 && criteria.NameEqualTo == "" && criteria.NameContains == "") {
 		return flvrGroups, nil
 	} else if len(criteria.Ids) > 0 {
 		flavorgroups := []hvs.FlavorGroup{}
@@ -184,6 +184,29 @@ func (store *MockFlavorgroupStore) AddFlavorTemplates(fgId uuid.UUID, ftIds []uu
 	return nil
 }
 
+// UpdateRefreshSchedule sets the refresh schedule of a Flavorgroup
+func (store *MockFlavorgroupStore) UpdateRefreshSchedule(fgId uuid.UUID, schedule *hvs.RefreshSchedule) error {
+	fg, ok := store.FlavorgroupStore[fgId]
+	if !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	if schedule.IsEmpty() {
+		schedule = nil
+	}
+	fg.RefreshSchedule = schedule
+	return nil
+}
+
+// UpdateHostSelector sets the host selector of a Flavorgroup
+func (store *MockFlavorgroupStore) UpdateHostSelector(fgId uuid.UUID, hostSelector string) error {
+	fg, ok := store.FlavorgroupStore[fgId]
+	if !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	fg.HostSelector = hostSelector
+	return nil
+}
+
 // NewFakeFlavorgroupStore provides two dummy data for Flavorgroups
 func NewFakeFlavorgroupStore() *MockFlavorgroupStore {
 	store := &MockFlavorgroupStore{
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockHostFetchQueueStore provides a mocked implementation of interface domain.HostFetchQueueStore
+type MockHostFetchQueueStore struct {
+	mtx     sync.Mutex
+	entries map[uuid.UUID]hvs.HostFetchQueueEntry
+}
+
+func NewMockHostFetchQueueStore() *MockHostFetchQueueStore {
+	return &MockHostFetchQueueStore{entries: make(map[uuid.UUID]hvs.HostFetchQueueEntry)}
+}
+
+// Persist creates or replaces the fetch queue entry of a host
+func (store *MockHostFetchQueueStore) Persist(entry *hvs.HostFetchQueueEntry) error {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	if entry == nil || entry.HostId == uuid.Nil || !entry.State.Valid() {
+		return errors.New("invalid host fetch queue entry")
+	}
+	entry.Updated = time.Now().UTC()
+	if existing, ok := store.entries[entry.HostId]; ok {
+		entry.Created = existing.Created
+	} else {
+		entry.Created = entry.Updated
+	}
+	store.entries[entry.HostId] = *entry
+	return nil
+}
+
+// Retrieve returns the fetch queue entry of a host
+func (store *MockHostFetchQueueStore) Retrieve(hostId uuid.UUID) (*hvs.HostFetchQueueEntry, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	entry, ok := store.entries[hostId]
+	if !ok {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return &entry, nil
+}
+
+// Search returns the fetch queue entries matching the filter criteria, ordered by their next attempt
+func (store *MockHostFetchQueueStore) Search(criteria *models.HostFetchQueueFilterCriteria) ([]hvs.HostFetchQueueEntry, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	entries := []hvs.HostFetchQueueEntry{}
+	for _, entry := range store.entries {
+		if criteria != nil {
+			if criteria.HostId != uuid.Nil && entry.HostId != criteria.HostId {
+				continue
+			}
+			if criteria.State != "" && entry.State != criteria.State {
+				continue
+			}
+			if !criteria.DueBefore.IsZero() && entry.NextAttempt.After(criteria.DueBefore) {
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].NextAttempt.Before(entries[j].NextAttempt)
+	})
+	if criteria != nil && criteria.Limit > 0 && len(entries) > criteria.Limit {
+		entries = entries[:criteria.Limit]
+	}
+	return entries, nil
+}
+
+// Delete removes the fetch queue entry of a host
+func (store *MockHostFetchQueueStore) Delete(hostId uuid.UUID) error {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	delete(store.entries, hostId)
+	return nil
+}
@@ -40,6 +40,10 @@ func (store *MockHostStore) Retrieve(id uuid.UUID, criteria *models.HostInfoFetc
 func (store *MockHostStore) Update(host *hvs.Host) error {
 	for i, h := range store.hostStore {
 		if h.Id == host.Id {
+			// nil labels keep the labels of the host, like the postgres store
+			if host.Labels == nil {
+				host.Labels = h.Labels
+			}
 			store.hostStore[i] = host
 			return nil
 		}
@@ -75,7 +79,9 @@ func (store *MockHostStore) Search(criteria *models.HostFilterCriteria, hostInfo
 	}
 
 	var hosts []*hvs.Host
-	if criteria.Id != uuid.Nil {
+	if reflect.DeepEqual(*criteria, models.HostFilterCriteria{Limit: criteria.Limit, After: criteria.After, LabelSelector: criteria.LabelSelector}) {
+		hosts = store.hostStore
+	} else if criteria.Id != uuid.Nil {
 		h, _ := store.Retrieve(criteria.Id, hostInfoFetchCriteria)
 		if h != nil {
 			hosts = append(hosts, h)
@@ -99,7 +105,34 @@ func (store *MockHostStore) Search(criteria *models.HostFilterCriteria, hostInfo
 			}
 		}
 	}
-	return hosts, nil
+	if len(criteria.LabelSelector) > 0 {
+		var selected []*hvs.Host
+		for _, h := range hosts {
+			if criteria.LabelSelector.Matches(h.Labels) {
+				selected = append(selected, h)
+			}
+		}
+		hosts = selected
+	}
+	return pageHosts(hosts, criteria.After, criteria.Limit), nil
+}
+
+// pageHosts returns at most limit hosts following the host identified by after, in the order of the store
+func pageHosts(hosts []*hvs.Host, after uuid.UUID, limit int) []*hvs.Host {
+	if after != uuid.Nil {
+		var next []*hvs.Host
+		for i, h := range hosts {
+			if h.Id == after {
+				next = hosts[i+1:]
+				break
+			}
+		}
+		hosts = next
+	}
+	if limit > 0 && len(hosts) > limit {
+		hosts = hosts[:limit]
+	}
+	return hosts
 }
 
 // AddFlavorgroups associate a Host with specified flavorgroups
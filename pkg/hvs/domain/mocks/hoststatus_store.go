@@ -148,7 +148,7 @@ func (store *MockHostStatusStore) Search(criteria *models.HostStatusFilterCriter
 	store.Mock.MatchExpectationsInOrder(false)
 
 	// Search No filters
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" ORDER BY host_status\.id asc LIMIT (.+)`).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs1.ID.String(), hs1.HostID.String(), hsi1, hsm1, hs1.Created).
 			AddRow(hs2.ID.String(), hs2.HostID.String(), hsi2, hsm2, hs2.Created).
@@ -156,19 +156,19 @@ func (store *MockHostStatusStore) Search(criteria *models.HostStatusFilterCriter
 			AddRow(hs4.ID.String(), hs4.HostID.String(), hsi4, hsm4, hs4.Created))
 
 	// Search by ID
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(id = (.+) LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(id = (.+) ORDER BY host_status\.id asc LIMIT (.+)`).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs1.ID.String(), hs1.HostID.String(), hsi1, hsm1, hs1.Created))
 
 	// Search by an existing Host ID
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\) LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\) ORDER BY host_status\.id asc LIMIT (.+)`).
 		WithArgs("47a3b602-f321-4e03-b3b2-8f3ca3cde128").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs1.ID.String(), "47a3b602-f321-4e03-b3b2-8f3ca3cde128", hsi1, hsm1, hs1.Created).
 			AddRow(hs2.ID.String(), "47a3b602-f321-4e03-b3b2-8f3ca3cde128", hsi2, hsm2, hs2.Created))
 
 	// Search by a non-existent Host ID - empty result
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\) LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\) ORDER BY host_status\.id asc LIMIT (.+)`).
 		WithArgs("13885605-a0ee-41f2-b6fc-fd82edc487ad").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}))
 
@@ -178,36 +178,36 @@ func (store *MockHostStatusStore) Search(criteria *models.HostStatusFilterCriter
 	}
 	// Mock query for Reports Controller
 	// Scenario: Host in Connected State
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\) LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(host_id = \$1\) ORDER BY host_status\.id asc LIMIT (.+)`).
 		WithArgs("ee37c360-7eae-4250-a677-6ee12adce8e2").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(newUuid, "e57e5ea0-d465-461e-882d-1600090caa0d", hsi1, hsm1, hs1.Created))
 
 	// Search by existing HostHardareUUID
-	store.Mock.ExpectQuery(`SELECT "host_status"\.\* FROM "host_status" INNER JOIN host h on h\.id = host_id WHERE \(h\.hardware_uuid = \$1\) LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT "host_status"\.\* FROM "host_status" INNER JOIN host h on h\.id = host_id WHERE \(h\.hardware_uuid = \$1\) ORDER BY host_status\.id asc LIMIT (.+)`).
 		WithArgs("1ad9c003-b0e0-4319-b2b3-06053dfd1407").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs1.ID.String(), hs1.HostID.String(), hsi1, hsm1, hs1.Created).
 			AddRow(hs2.ID.String(), hs2.HostID.String(), hsi2, hsm2, hs2.Created))
 
 	// Search by non-existent HostHardareUUID
-	store.Mock.ExpectQuery(`SELECT "host_status"\.\* FROM "host_status" INNER JOIN host h on h\.id = host_id WHERE \(h\.hardware_uuid = \$1\) LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT "host_status"\.\* FROM "host_status" INNER JOIN host h on h\.id = host_id WHERE \(h\.hardware_uuid = \$1\) ORDER BY host_status\.id asc LIMIT (.+)`).
 		WithArgs("7f71bff0-3c12-4f92-9a77-d380eb9ad2e2").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}))
 
 	// Search by HostStatus
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(status(.+)host_state(.+)CONNECTED(.+)LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(status(.+)host_state(.+)CONNECTED(.+)ORDER BY host_status\.id asc LIMIT (.+)`).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs1.ID.String(), hs1.HostID.String(), hsi1, hsm1, hs1.Created).
 			AddRow(hs3.ID.String(), hs3.HostID.String(), hsi3, hsm3, hs3.Created))
 
 	// Search by HostState UNKNOWN
-	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(status(.+)host_state(.+)UNKNOWN(.+)LIMIT (.+)`).
+	store.Mock.ExpectQuery(`SELECT \* FROM "host_status" WHERE \(status(.+)host_state(.+)UNKNOWN(.+)ORDER BY host_status\.id asc LIMIT (.+)`).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs4.ID.String(), hs4.HostID.String(), hsi4, hsm4, hs4.Created))
 
 	// Search by HostName
-	store.Mock.ExpectQuery(`SELECT "host_status"\.\* FROM "host_status" INNER JOIN host h on h\.id = host_id WHERE \(h\.name = \$1\) LIMIT 10000`).
+	store.Mock.ExpectQuery(`SELECT "host_status"\.\* FROM "host_status" INNER JOIN host h on h\.id = host_id WHERE \(h\.name = \$1\) ORDER BY host_status\.id asc LIMIT 10000`).
 		WithArgs("computepurley1").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "host_id", "status", "host_report", "created"}).
 			AddRow(hs1.ID.String(), hs1.HostID.String(), hsi1, hsm1, hs1.Created).
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockJobStore provides a mocked implementation of interface domain.JobStore
+type MockJobStore struct {
+	mtx  sync.Mutex
+	jobs map[uuid.UUID]hvs.Job
+}
+
+func NewMockJobStore() *MockJobStore {
+	return &MockJobStore{jobs: make(map[uuid.UUID]hvs.Job)}
+}
+
+// Create persists a new job
+func (store *MockJobStore) Create(j *hvs.Job) (*hvs.Job, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	if j == nil || j.Type == "" || !j.Status.Valid() {
+		return nil, errors.New("invalid job")
+	}
+	created := *j
+	if created.Id == uuid.Nil {
+		created.Id = uuid.New()
+	}
+	created.Created = time.Now().UTC()
+	created.Updated = created.Created
+	store.jobs[created.Id] = created
+	return &created, nil
+}
+
+// Retrieve returns the job with the given id
+func (store *MockJobStore) Retrieve(id uuid.UUID) (*hvs.Job, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	j, ok := store.jobs[id]
+	if !ok {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return &j, nil
+}
+
+// Search returns the jobs matching the filter criteria, most recent first
+func (store *MockJobStore) Search(criteria *models.JobFilterCriteria) ([]hvs.Job, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	jobs := []hvs.Job{}
+	for _, j := range store.jobs {
+		if criteria != nil {
+			if criteria.Type != "" && j.Type != criteria.Type {
+				continue
+			}
+			if len(criteria.Status) > 0 && !containsJobStatus(criteria.Status, j.Status) {
+				continue
+			}
+			if criteria.Owner != "" && j.Owner != criteria.Owner {
+				continue
+			}
+		}
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Created.After(jobs[k].Created)
+	})
+	if criteria != nil && criteria.Limit > 0 && len(jobs) > criteria.Limit {
+		jobs = jobs[:criteria.Limit]
+	}
+	return jobs, nil
+}
+
+// Update stores the status, progress and outcome of a job
+func (store *MockJobStore) Update(j *hvs.Job) error {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	existing, ok := store.jobs[j.Id]
+	if !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	if existing.Status == hvs.JobStatusCancelled {
+		return domain.ErrJobCancelled
+	}
+	j.Created = existing.Created
+	j.Updated = time.Now().UTC()
+	store.jobs[j.Id] = *j
+	return nil
+}
+
+func containsJobStatus(statuses []hvs.JobStatus, status hvs.JobStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
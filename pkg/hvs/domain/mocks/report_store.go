@@ -21,7 +21,16 @@ import (
 
 // MockReportStore provides a mocked implementation of interface postgres.ReportStore
 type MockReportStore struct {
-	reportStore map[uuid.UUID]models.HVSReport
+	reportStore      map[uuid.UUID]models.HVSReport
+	refreshSchedules map[uuid.UUID]hvs.RefreshSchedule
+}
+
+// SetRefreshSchedule sets the effective refresh schedule of a host
+func (store *MockReportStore) SetRefreshSchedule(hostId uuid.UUID, schedule hvs.RefreshSchedule) {
+	if store.refreshSchedules == nil {
+		store.refreshSchedules = make(map[uuid.UUID]hvs.RefreshSchedule)
+	}
+	store.refreshSchedules[hostId] = schedule
 }
 
 // Create inserts a HVSReport
@@ -136,6 +145,57 @@ func (store *MockReportStore) FindHostIdsFromExpiredReports(fromTime time.Time,
 	return hostIDs, nil
 }
 
+// RetrieveFromAuditLog returns HVSReport, the mock does not keep replaced reports
+func (store *MockReportStore) RetrieveFromAuditLog(id uuid.UUID) (*models.HVSReport, error) {
+	return store.Retrieve(id)
+}
+
+// FindLatestTrustedReport returns the most recent trusted HVSReport of a host created before the given time
+func (store *MockReportStore) FindLatestTrustedReport(hostId uuid.UUID, before time.Time) (*models.HVSReport, error) {
+	var latest *models.HVSReport
+	for _, r := range store.reportStore {
+		if r.HostID == hostId && r.TrustReport.Trusted && r.CreatedAt.Before(before) &&
+			(latest == nil || r.CreatedAt.After(latest.CreatedAt)) {
+			report := r
+			latest = &report
+		}
+	}
+	if latest == nil {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return latest, nil
+}
+
+// FindHostRefreshSchedules returns the refresh schedules set on the hosts that have a report
+func (store *MockReportStore) FindHostRefreshSchedules() ([]models.HostRefreshSchedule, error) {
+	var schedules []models.HostRefreshSchedule
+	for _, r := range store.reportStore {
+		schedule, ok := store.refreshSchedules[r.HostID]
+		if !ok || schedule.IntervalSeconds <= 0 {
+			continue
+		}
+		schedules = append(schedules, models.HostRefreshSchedule{
+			HostID:      r.HostID,
+			Schedule:    schedule,
+			LastRefresh: r.CreatedAt,
+		})
+	}
+	return schedules, nil
+}
+
+// CountHostsByTrust returns the number of hosts with a trusted and an untrusted report
+func (store *MockReportStore) CountHostsByTrust() (int, int, error) {
+	var trusted, untrusted int
+	for _, r := range store.reportStore {
+		if r.TrustReport.Trusted {
+			trusted++
+		} else {
+			untrusted++
+		}
+	}
+	return trusted, untrusted, nil
+}
+
 // NewMockReportStore provides two dummy data for Reports
 func NewMockReportStore() *MockReportStore {
 	//TODO add more data
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockSubscriptionStore provides a mocked implementation of interface domain.SubscriptionStore
+type MockSubscriptionStore struct {
+	mtx           sync.Mutex
+	subscriptions map[uuid.UUID]hvs.Subscription
+}
+
+func NewMockSubscriptionStore() *MockSubscriptionStore {
+	return &MockSubscriptionStore{subscriptions: make(map[uuid.UUID]hvs.Subscription)}
+}
+
+// Create adds a subscription to the store
+func (store *MockSubscriptionStore) Create(s *hvs.Subscription) (*hvs.Subscription, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	if s == nil || !s.DeliveryType.Valid() || s.Endpoint == "" {
+		return nil, errors.New("invalid subscription")
+	}
+	created := *s
+	created.Id = uuid.New()
+	created.Created = time.Now().UTC()
+	store.subscriptions[created.Id] = created
+	return &created, nil
+}
+
+// Retrieve returns a subscription from the store
+func (store *MockSubscriptionStore) Retrieve(id uuid.UUID) (*hvs.Subscription, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	s, ok := store.subscriptions[id]
+	if !ok {
+		return nil, errors.New(commErr.RowsNotFound)
+	}
+	return &s, nil
+}
+
+// Search returns the subscriptions matching the filter criteria, oldest first
+func (store *MockSubscriptionStore) Search(criteria *models.SubscriptionFilterCriteria) ([]hvs.Subscription, error) {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	subscriptions := []hvs.Subscription{}
+	for _, s := range store.subscriptions {
+		if criteria != nil && criteria.DeliveryType != "" && s.DeliveryType != criteria.DeliveryType {
+			continue
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].Created.Before(subscriptions[j].Created)
+	})
+	if criteria != nil && criteria.Limit > 0 && len(subscriptions) > criteria.Limit {
+		subscriptions = subscriptions[:criteria.Limit]
+	}
+	return subscriptions, nil
+}
+
+// Delete removes a subscription from the store
+func (store *MockSubscriptionStore) Delete(id uuid.UUID) error {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	if _, ok := store.subscriptions[id]; !ok {
+		return errors.New(commErr.RowsNotFound)
+	}
+	delete(store.subscriptions, id)
+	return nil
+}
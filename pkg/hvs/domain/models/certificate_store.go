@@ -11,10 +11,16 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 	"github.com/pkg/errors"
 	"strings"
+	"sync"
 )
 
 var defaultLog = log.GetDefaultLogger()
 
+// certificatesMtx guards the certificates of the stores, that are added while the hosts are verified. The
+// certificates are replaced rather than appended to, so that the slices returned by GetKeyAndCertificates are
+// never modified.
+var certificatesMtx sync.RWMutex
+
 // CertificatesStore reads and caches map of certificate type and CertificateStore in application
 type CertificatesStore map[string]*CertificateStore
 
@@ -51,7 +57,10 @@ func (cs *CertificatesStore) AddCertificatesToStore(certType, certFile string, c
 	}
 
 	// Add certificate to store
-	certStore.Certificates = append(certStore.Certificates, *certificate)
+	certificatesMtx.Lock()
+	defer certificatesMtx.Unlock()
+	certificates := make([]x509.Certificate, 0, len(certStore.Certificates)+1)
+	certStore.Certificates = append(append(certificates, certStore.Certificates...), *certificate)
 
 	return nil
 }
@@ -62,6 +71,8 @@ func (cs *CertificatesStore) GetKeyAndCertificates(certType string) (crypto.Priv
 
 	certStore := (*cs)[certType]
 	if certStore != nil {
+		certificatesMtx.RLock()
+		defer certificatesMtx.RUnlock()
 		return certStore.Key, certStore.Certificates, nil
 	}
 	return nil, nil, errors.Errorf("Certificate store is empty for certType: %s", certType)
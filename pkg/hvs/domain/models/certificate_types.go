@@ -14,6 +14,7 @@ const (
 	CaCertTypesPrivacyCa     CaCertTypes = "privacy"
 	CaCertTypesAikCa         CaCertTypes = "aik" //privacy is used instead to store cert
 	CaCertTypesTagCa         CaCertTypes = "tag"
+	CaCertTypesFlavorCa      CaCertTypes = "flavor"
 )
 
 func (cct CaCertTypes) String() string {
@@ -27,7 +28,8 @@ func GetCaCertTypes() []CaCertTypes {
 		CaCertTypesEkCa,
 		CaCertTypesPrivacyCa,
 		CaCertTypesAikCa,
-		CaCertTypesTagCa}
+		CaCertTypesTagCa,
+		CaCertTypesFlavorCa}
 }
 
 // CaCertTypes is an enumerated set of certificate types
@@ -37,6 +39,8 @@ const (
 	CertTypesSaml          CertTypes = "saml"
 	CertTypesTls           CertTypes = "tls"
 	CertTypesFlavorSigning CertTypes = "flavor-signing"
+	// CertTypesImportedFlavorSigning holds the signing certificates of the flavors imported from other HVS
+	CertTypesImportedFlavorSigning CertTypes = "imported-flavor-signing"
 )
 
 func (ct CertTypes) String() string {
@@ -55,7 +59,9 @@ func GetUniqueCertTypes() []string {
 		CaCertTypesTagCa.String(),
 		CertTypesSaml.String(),
 		CertTypesTls.String(),
-		CertTypesFlavorSigning.String()}
+		CertTypesFlavorSigning.String(),
+		CaCertTypesFlavorCa.String(),
+		CertTypesImportedFlavorSigning.String()}
 }
 
 // GetUniqueCertTypes returns a list of unique certificate types as strings
@@ -0,0 +1,22 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+)
+
+// HostFetchQueueFilterCriteria holds the filter criteria for the host data fetch queue
+type HostFetchQueueFilterCriteria struct {
+	HostId uuid.UUID
+	State  hvs.HostFetchState
+	// DueBefore selects the entries whose next attempt is not after the given time
+	DueBefore time.Time
+	Limit     int
+}
@@ -7,6 +7,7 @@ package models
 import (
 	"errors"
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 )
 
 type HostFilterCriteria struct {
@@ -19,6 +20,9 @@ type HostFilterCriteria struct {
 	IdList         []uuid.UUID
 	Trusted        *bool
 	OrderBy        OrderType
+	Limit          int
+	After          uuid.UUID
+	LabelSelector  hvs.LabelSelector
 }
 
 type OrderType string
@@ -0,0 +1,20 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+)
+
+// HostRefreshSchedule is the effective refresh schedule of a host along with the creation time of its current report
+type HostRefreshSchedule struct {
+	HostID      uuid.UUID
+	Schedule    hvs.RefreshSchedule
+	LastRefresh time.Time
+}
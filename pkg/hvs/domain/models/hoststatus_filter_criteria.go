@@ -6,6 +6,7 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"time"
 )
 
@@ -21,4 +22,6 @@ type HostStatusFilterCriteria struct {
 	LatestPerHost  bool
 	NumberOfDays   int
 	Limit          int
+	After          uuid.UUID
+	LabelSelector  hvs.LabelSelector
 }
@@ -0,0 +1,16 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+
+// JobFilterCriteria holds the filter criteria for the jobs
+type JobFilterCriteria struct {
+	Type   hvs.JobType
+	Status []hvs.JobStatus
+	Owner  string
+	Limit  int
+}
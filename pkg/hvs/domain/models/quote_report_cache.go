@@ -4,10 +4,17 @@
  */
 package models
 
-import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+import (
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+)
 
 type QuoteReportCache struct {
 	QuoteDigest  string
 	TrustPcrList []int
 	TrustReport  *hvs.TrustReport
+	// LifecycleDeadline is the earliest time at which the lifecycle of a flavor of the report changes the trust of
+	// the host, the report cannot be reused after it
+	LifecycleDeadline *time.Time
 }
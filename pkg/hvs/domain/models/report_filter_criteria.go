@@ -7,6 +7,7 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"time"
 )
 
@@ -21,6 +22,8 @@ type ReportFilterCriteria struct {
 	ToDate         time.Time
 	LatestPerHost  bool
 	Limit          int
+	After          uuid.UUID
+	LabelSelector  hvs.LabelSelector
 }
 
 type ReportLocator struct {
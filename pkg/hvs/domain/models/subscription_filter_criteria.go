@@ -0,0 +1,14 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import "github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+
+// SubscriptionFilterCriteria holds the filter criteria for the event subscriptions
+type SubscriptionFilterCriteria struct {
+	DeliveryType hvs.SubscriptionDeliveryType
+	Limit        int
+}
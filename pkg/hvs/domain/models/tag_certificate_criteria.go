@@ -23,6 +23,9 @@ type TagCertificateFilterCriteria struct {
 	ValidAfter      time.Time `json:"validAfter"`
 	// swagger:strfmt uuid
 	HardwareUUID uuid.UUID `json:"hardwareUuid"`
+	Limit        int       `json:"limit"`
+	// swagger:strfmt uuid
+	After uuid.UUID `json:"after"`
 }
 
 // TagCertificateCreateCriteria holds the data used to create a TagCertificate
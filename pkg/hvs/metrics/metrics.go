@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// metrics package declares the HVS specific collectors that are exposed through the /metrics endpoint
+package metrics
+
+import (
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const subsystem = "hvs"
+
+// Names of the work queues whose depth is reported
+const (
+	QueueHostTrustVerify = "hosttrust_verify"
+	QueueHostTrustFetch  = "hosttrust_fetch"
+	QueueHostFetcher     = "host_fetcher"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+var (
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "queue_depth",
+		Help:      "Number of items waiting in the work queues of the hosttrust and host-fetcher services",
+	}, []string{"queue"})
+
+	hostFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "host_fetch_duration_seconds",
+		Help:      "Time taken to retrieve the host manifest from a host, by result",
+		Buckets:   []float64{.25, .5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	}, []string{"result"})
+
+	hostVerifyDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "host_verify_duration_seconds",
+		Help:      "Time taken to verify a host manifest against the flavors of the host",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	metrics.MustRegister(queueDepth, hostFetchDuration, hostVerifyDuration)
+}
+
+// QueueDepthObserver returns a callback that records the depth of the named work queue
+func QueueDepthObserver(queue string) func(int) {
+	gauge := queueDepth.WithLabelValues(queue)
+	return func(depth int) {
+		gauge.Set(float64(depth))
+	}
+}
+
+// ObserveHostFetch records the time taken by a host data fetch that was started at start
+func ObserveHostFetch(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	hostFetchDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+// ObserveHostVerify records the time taken by a host verification that was started at start
+func ObserveHostVerify(start time.Time) {
+	hostVerifyDuration.Observe(time.Since(start).Seconds())
+}
+
+// hostTrustCollector reports the number of trusted and untrusted hosts. The counts are read from the report store
+// on every scrape so that they stay accurate across HVS instances sharing the database
+type hostTrustCollector struct {
+	reportStore domain.ReportStore
+	desc        *prometheus.Desc
+}
+
+// RegisterHostTrustCollector registers the collector reporting the trusted and untrusted host counts
+func RegisterHostTrustCollector(reportStore domain.ReportStore) error {
+	return metrics.Register(newHostTrustCollector(reportStore))
+}
+
+func newHostTrustCollector(reportStore domain.ReportStore) *hostTrustCollector {
+	return &hostTrustCollector{
+		reportStore: reportStore,
+		desc: prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, subsystem, "hosts"),
+			"Number of hosts by the trust status of their latest report", []string{"trusted"}, nil),
+	}
+}
+
+func (c *hostTrustCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *hostTrustCollector) Collect(ch chan<- prometheus.Metric) {
+	trusted, untrusted, err := c.reportStore.CountHostsByTrust()
+	if err != nil {
+		defaultLog.WithError(err).Error("metrics/metrics:Collect() Failed to count hosts by trust status")
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(trusted), "true")
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(untrusted), "false")
+}
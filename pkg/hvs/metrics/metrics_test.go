@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostTrustCollector(t *testing.T) {
+	a := assert.New(t)
+
+	reportStore := mocks.NewMockReportStore()
+	trusted, untrusted, err := reportStore.CountHostsByTrust()
+	a.NoError(err)
+
+	a.NoError(RegisterHostTrustCollector(reportStore))
+	a.NoError(RegisterHostTrustCollector(reportStore))
+
+	expected := fmt.Sprintf(`# HELP isecl_hvs_hosts Number of hosts by the trust status of their latest report
+# TYPE isecl_hvs_hosts gauge
+isecl_hvs_hosts{trusted="false"} %d
+isecl_hvs_hosts{trusted="true"} %d
+`, untrusted, trusted)
+	a.NoError(testutil.CollectAndCompare(newHostTrustCollector(reportStore), strings.NewReader(expected)))
+}
+
+func TestQueueDepthObserver(t *testing.T) {
+	a := assert.New(t)
+
+	observe := QueueDepthObserver(QueueHostFetcher)
+	observe(3)
+	a.Equal(float64(3), testutil.ToFloat64(queueDepth.WithLabelValues(QueueHostFetcher)))
+	observe(0)
+	a.Equal(float64(0), testutil.ToFloat64(queueDepth.WithLabelValues(QueueHostFetcher)))
+}
+
+func TestObserveHostFetch(t *testing.T) {
+	a := assert.New(t)
+
+	ObserveHostFetch(time.Now(), nil)
+	a.Equal(1, testutil.CollectAndCount(hostFetchDuration))
+}
@@ -40,6 +40,49 @@ func (f *FlavorStore) Create(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor,
 		signedFlavor.Flavor.Meta.ID = newUuid
 	}
 
+	if err := f.Store.Db.Create(newDbFlavor(signedFlavor)).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/flavor_store:Create() failed to create flavor")
+	}
+	return signedFlavor, nil
+}
+
+// Replace deletes a flavor and stores the given one in its place in a single transaction, the replacing flavor is
+// linked to the flavor groups of the replaced flavor. It can have the ID or the label of the replaced flavor.
+func (f *FlavorStore) Replace(replacedFlavorId uuid.UUID, signedFlavor *hvs.SignedFlavor) error {
+	defaultLog.Trace("postgres/flavor_store:Replace() Entering")
+	defer defaultLog.Trace("postgres/flavor_store:Replace() Leaving")
+	if signedFlavor == nil || signedFlavor.Flavor.Meta.ID == uuid.Nil || signedFlavor.Signature == "" ||
+		signedFlavor.Flavor.Meta.Description[hvs.Label].(string) == "" {
+		return errors.New("postgres/flavor_store:Replace()- invalid input : must have ID, content, signature and the label for the flavor")
+	}
+
+	return f.Store.Db.Transaction(func(tx *gorm.DB) error {
+		var flavorgroupIds []uuid.UUID
+		if err := tx.Model(&flavorgroupFlavor{}).Where("flavor_id = ?", replacedFlavorId).Pluck("flavorgroup_id", &flavorgroupIds).Error; err != nil {
+			return errors.Wrap(err, "postgres/flavor_store:Replace() failed to retrieve the flavorgroups of the replaced flavor")
+		}
+		db := tx.Where(&flavor{ID: replacedFlavorId}).Delete(&flavor{ID: replacedFlavorId})
+		if db.Error != nil {
+			return errors.Wrap(db.Error, "postgres/flavor_store:Replace() failed to delete the replaced flavor")
+		}
+		if db.RowsAffected != 1 {
+			return errors.New("postgres/flavor_store:Replace() - no rows affected - Record not found = id :  " + replacedFlavorId.String())
+		}
+		if err := tx.Create(newDbFlavor(signedFlavor)).Error; err != nil {
+			return errors.Wrap(err, "postgres/flavor_store:Replace() failed to create flavor")
+		}
+		for _, flavorgroupId := range flavorgroupIds {
+			link := flavorgroupFlavor{FlavorgroupId: flavorgroupId, FlavorId: signedFlavor.Flavor.Meta.ID}
+			if err := tx.Create(&link).Error; err != nil {
+				return errors.Wrap(err, "postgres/flavor_store:Replace() failed to link the flavor to the flavorgroups of the replaced flavor")
+			}
+		}
+		return nil
+	})
+}
+
+// newDbFlavor returns the record of a signed flavor in the flavor table
+func newDbFlavor(signedFlavor *hvs.SignedFlavor) *flavor {
 	dbf := flavor{
 		ID:         signedFlavor.Flavor.Meta.ID,
 		Content:    PGFlavorContent(signedFlavor.Flavor),
@@ -47,12 +90,15 @@ func (f *FlavorStore) Create(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor,
 		Label:      signedFlavor.Flavor.Meta.Description[hvs.Label].(string),
 		FlavorPart: signedFlavor.Flavor.Meta.Description[hvs.FlavorPartDescription].(string),
 		Signature:  signedFlavor.Signature,
+		State:      hvs.FlavorStateActive.String(),
 	}
-
-	if err := f.Store.Db.Create(&dbf).Error; err != nil {
-		return nil, errors.Wrap(err, "postgres/flavor_store:Create() failed to create flavor")
+	if signedFlavor.Lifecycle != nil {
+		dbf.State = signedFlavor.Lifecycle.State.String()
+		dbf.GracePeriodEnd = signedFlavor.Lifecycle.GracePeriodEnd
+		dbf.SupersededBy = signedFlavor.Lifecycle.SupersededBy
+		dbf.ExpiresAt = signedFlavor.Lifecycle.ExpiresAt
 	}
-	return signedFlavor, nil
+	return &dbf
 }
 
 func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.SignedFlavor, error) {
@@ -62,7 +108,7 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 	var tx *gorm.DB
 	var err error
 
-	tx = f.Store.Db.Table("flavor f").Select("f.id, f.content, f.signature")
+	tx = f.Store.Db.Table("flavor f").Select("f.id, f.content, f.signature, " + flavorLifecycleFields("f"))
 	// build partial query with all the given flavor Id's
 	if len(flavorFilter.FlavorFC.Ids) > 0 {
 		var flavorIds []string
@@ -89,6 +135,13 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 			" object in flavor Search function")
 	}
 
+	// the flavor part queries are combined with OR conditions, the page is selected from their result
+	if flavorFilter.FlavorFC.After != uuid.Nil || flavorFilter.FlavorFC.Limit > 0 {
+		tx = f.Store.Db.Table("flavor fp").Select("fp.id, fp.content, fp.signature, "+flavorLifecycleFields("fp")).
+			Where("fp.id IN ?", tx.Select("f.id").SubQuery())
+		tx = paginate(tx, "flavor", "fp", "", false, flavorFilter.FlavorFC.After, flavorFilter.FlavorFC.Limit)
+	}
+
 	rows, err := tx.Rows()
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/flavor_store:Search() failed to retrieve records from db")
@@ -104,9 +157,12 @@ func (f *FlavorStore) Search(flavorFilter *models.FlavorVerificationFC) ([]hvs.S
 
 	for rows.Next() {
 		sf := hvs.SignedFlavor{}
-		if err := rows.Scan(&sf.Flavor.Meta.ID, (*PGFlavorContent)(&sf.Flavor), &sf.Signature); err != nil {
+		lifecycle := hvs.FlavorLifecycle{}
+		if err := rows.Scan(append([]interface{}{&sf.Flavor.Meta.ID, (*PGFlavorContent)(&sf.Flavor), &sf.Signature},
+			flavorLifecycleDest(&lifecycle)...)...); err != nil {
 			return nil, errors.Wrap(err, "postgres/flavor_store:Search() failed to scan record")
 		}
+		sf.Lifecycle = flavorLifecycleOrNil(lifecycle)
 		signedFlavors = append(signedFlavors, sf)
 	}
 	return signedFlavors, nil
@@ -121,6 +177,7 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 	var aTagQuery *gorm.DB
 	var softwareQuery *gorm.DB
 	var hostUniqueQuery *gorm.DB
+	var imaQuery *gorm.DB
 
 	if flavorPartsWithLatest != nil && len(flavorPartsWithLatest) >= 1 {
 		for flavorPart := range flavorPartsWithLatest {
@@ -193,6 +250,19 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 					aTagQuery = aTagQuery.Order("f.created_at desc").Limit(1)
 				}
 
+			case hvs.FlavorPartIma:
+				imaQuery = f.Store.Db
+				imaQuery = buildFlavorPartQueryStringWithFlavorParts(hvs.FlavorPartIma.String(), fgId.String(), imaQuery)
+				// build IMA Query with all the IMA flavor query attributes from host manifest
+				imafQueryAttributes := flavorMetaInfo[hvs.FlavorPartIma]
+				for _, imafQueryAttribute := range imafQueryAttributes {
+					imaQuery = imaQuery.Where(convertToPgJsonqueryString("f.content", imafQueryAttribute.Key)+" = ?", imafQueryAttribute.Value)
+				}
+				// apply limit if latest
+				if flavorPartsWithLatest[hvs.FlavorPartIma] {
+					imaQuery = imaQuery.Order("f.created_at desc").Limit(1)
+				}
+
 			default:
 				defaultLog.Error("postgres/flavor_store:buildMultipleFlavorPartQueryString() Invalid flavor part")
 				return nil
@@ -242,8 +312,17 @@ func (f *FlavorStore) buildMultipleFlavorPartQueryString(tx *gorm.DB, fgId uuid.
 			subQuery = subQuery.Where("f.id IN ?", hostUniqueSubQuery)
 		}
 	}
+	// add IMA query to sub query
+	if imaQuery != nil {
+		imaSubQuery := imaQuery.SubQuery()
+		if biosQuery != nil || osQuery != nil || softwareQuery != nil || aTagQuery != nil || hostUniqueQuery != nil {
+			subQuery = subQuery.Or("f.id IN ?", imaSubQuery)
+		} else {
+			subQuery = subQuery.Where("f.id IN ?", imaSubQuery)
+		}
+	}
 	// check if none of the flavor part queries are not formed,
-	if subQuery != nil && (biosQuery != nil || aTagQuery != nil || softwareQuery != nil || hostUniqueQuery != nil || osQuery != nil) {
+	if subQuery != nil && (biosQuery != nil || aTagQuery != nil || softwareQuery != nil || hostUniqueQuery != nil || osQuery != nil || imaQuery != nil) {
 		tx = subQuery
 	} else if fgId != uuid.Nil {
 		fgSubQuery := buildFlavorPartQueryStringWithFlavorgroup(fgId.String(), tx).SubQuery()
@@ -318,13 +397,57 @@ func (f *FlavorStore) Retrieve(flavorId uuid.UUID) (*hvs.SignedFlavor, error) {
 	defer defaultLog.Trace("postgres/flavor_store:Retrieve() Leaving")
 
 	sf := hvs.SignedFlavor{}
-	row := f.Store.Db.Model(flavor{}).Select("content, signature").Where(&flavor{ID: flavorId}).Row()
-	if err := row.Scan((*PGFlavorContent)(&sf.Flavor), &sf.Signature); err != nil {
+	lifecycle := hvs.FlavorLifecycle{}
+	row := f.Store.Db.Model(flavor{}).Select("content, signature, " + flavorLifecycleFields("flavor")).Where(&flavor{ID: flavorId}).Row()
+	if err := row.Scan(append([]interface{}{(*PGFlavorContent)(&sf.Flavor), &sf.Signature},
+		flavorLifecycleDest(&lifecycle)...)...); err != nil {
 		return nil, errors.Wrap(err, "postgres/flavor_store:Retrieve() - Could not scan record ")
 	}
+	sf.Lifecycle = flavorLifecycleOrNil(lifecycle)
 	return &sf, nil
 }
 
+// UpdateLifecycle sets the lifecycle of a flavor, the content and the signature of the flavor are left untouched
+func (f *FlavorStore) UpdateLifecycle(flavorId uuid.UUID, lifecycle hvs.FlavorLifecycle) error {
+	defaultLog.Trace("postgres/flavor_store:UpdateLifecycle() Entering")
+	defer defaultLog.Trace("postgres/flavor_store:UpdateLifecycle() Leaving")
+
+	db := f.Store.Db.Model(&flavor{ID: flavorId}).Updates(map[string]interface{}{
+		"state":            lifecycle.State.String(),
+		"grace_period_end": lifecycle.GracePeriodEnd,
+		"superseded_by":    lifecycle.SupersededBy,
+		"expires_at":       lifecycle.ExpiresAt,
+	})
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/flavor_store:UpdateLifecycle() failed to update lifecycle of Flavor")
+	}
+	if db.RowsAffected != 1 {
+		return errors.New("postgres/flavor_store:UpdateLifecycle() - no rows affected - Record not found = id :  " + flavorId.String())
+	}
+	return nil
+}
+
+// flavorLifecycleFields returns the lifecycle columns of the flavor table with the given alias, in the order of
+// flavorLifecycleDest
+func flavorLifecycleFields(alias string) string {
+	return fmt.Sprintf("%[1]s.state, %[1]s.grace_period_end, %[1]s.superseded_by, %[1]s.expires_at", alias)
+}
+
+// flavorLifecycleDest returns the scan destinations of the columns of flavorLifecycleFields
+func flavorLifecycleDest(lifecycle *hvs.FlavorLifecycle) []interface{} {
+	return []interface{}{&lifecycle.State, &lifecycle.GracePeriodEnd, &lifecycle.SupersededBy, &lifecycle.ExpiresAt}
+}
+
+// flavorLifecycleOrNil returns nil for the active flavors without any date, which is the lifecycle of a flavor
+// that was never changed
+func flavorLifecycleOrNil(lifecycle hvs.FlavorLifecycle) *hvs.FlavorLifecycle {
+	if (lifecycle.State == "" || lifecycle.State == hvs.FlavorStateActive) && lifecycle.GracePeriodEnd == nil &&
+		lifecycle.SupersededBy == nil && lifecycle.ExpiresAt == nil {
+		return nil
+	}
+	return &lifecycle
+}
+
 // delete flavors
 func (f *FlavorStore) Delete(flavorId uuid.UUID) error {
 	defaultLog.Trace("postgres/flavor_store:Delete() Entering")
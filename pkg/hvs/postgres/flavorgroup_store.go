@@ -40,6 +40,10 @@ func (f *FlavorGroupStore) Create(fg *hvs.FlavorGroup) (*hvs.FlavorGroup, error)
 		ID:                    fg.ID,
 		Name:                  fg.Name,
 		FlavorTypeMatchPolicy: PGFlavorMatchPolicies(fg.MatchPolicies),
+		HostSelector:          fg.HostSelector,
+	}
+	if !fg.RefreshSchedule.IsEmpty() {
+		dbFlavorGroup.RefreshSchedule = PGRefreshSchedule{Schedule: fg.RefreshSchedule}
 	}
 
 	if err = f.Store.Db.Create(&dbFlavorGroup).Error; err != nil {
@@ -73,10 +77,12 @@ func (f *FlavorGroupStore) Retrieve(flavorGroupId uuid.UUID) (*hvs.FlavorGroup,
 	defer defaultLog.Trace("postgres/flavorgroup_store:Retrieve() Leaving")
 
 	fg := hvs.FlavorGroup{}
+	refreshSchedule := PGRefreshSchedule{}
 	row := f.Store.Db.Model(&flavorGroup{}).Where(&flavorGroup{ID: flavorGroupId}).Row()
-	if err := row.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies)); err != nil {
+	if err := row.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies), &refreshSchedule, &fg.HostSelector); err != nil {
 		return nil, errors.Wrap(err, "postgres/flavorgroup_store:Retrieve() failed to scan record")
 	}
+	fg.RefreshSchedule = refreshSchedule.Schedule
 	return &fg, nil
 }
 
@@ -117,9 +123,11 @@ func (f *FlavorGroupStore) Search(fgFilter *models.FlavorGroupFilterCriteria) ([
 	flavorgroupList := []hvs.FlavorGroup{}
 	for rows.Next() {
 		fg := hvs.FlavorGroup{}
-		if err := rows.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies)); err != nil {
+		refreshSchedule := PGRefreshSchedule{}
+		if err := rows.Scan(&fg.ID, &fg.Name, (*PGFlavorMatchPolicies)(&fg.MatchPolicies), &refreshSchedule, &fg.HostSelector); err != nil {
 			return nil, errors.Wrap(err, "postgres/flavorgroup_store:Search() failed to scan record")
 		}
+		fg.RefreshSchedule = refreshSchedule.Schedule
 		flavorgroupList = append(flavorgroupList, fg)
 	}
 
@@ -179,7 +187,7 @@ func buildFlavorGroupSearchQuery(tx *gorm.DB, fgFilter *models.FlavorGroupFilter
 	} else if fgFilter.NameContains != "" {
 		tx = tx.Where("name like ? ", "%"+fgFilter.NameContains+"%")
 	}
-	return tx
+	return paginate(tx, "flavor_group", "flavor_group", "name", false, fgFilter.After, fgFilter.Limit)
 }
 
 func (f *FlavorGroupStore) removeFlavorTypesCacheEntry(fgId uuid.UUID) {
@@ -433,3 +441,38 @@ func (f *FlavorGroupStore) AddFlavorTemplates(fgId uuid.UUID, ftIds []uuid.UUID)
 	defaultLog.Debugf("postgres/flavorgroup_store:AddFlavorTemplates() Linking flavor-template completed for flavorgroup %v ", fgId)
 	return nil
 }
+
+// UpdateRefreshSchedule sets the refresh schedule of a flavorgroup, a nil or empty schedule removes it
+func (f *FlavorGroupStore) UpdateRefreshSchedule(fgId uuid.UUID, schedule *hvs.RefreshSchedule) error {
+	defaultLog.Trace("postgres/flavorgroup_store:UpdateRefreshSchedule() Entering")
+	defer defaultLog.Trace("postgres/flavorgroup_store:UpdateRefreshSchedule() Leaving")
+
+	var value interface{} = gorm.Expr("NULL")
+	if !schedule.IsEmpty() {
+		value = PGRefreshSchedule{Schedule: schedule}
+	}
+	db := f.Store.Db.Model(&flavorGroup{ID: fgId}).Update("refresh_schedule", value)
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/flavorgroup_store:UpdateRefreshSchedule() failed to update refresh schedule of Flavorgroup")
+	}
+	if db.RowsAffected != 1 {
+		return errors.New("postgres/flavorgroup_store:UpdateRefreshSchedule() - no rows affected - Record not found = id :  " + fgId.String())
+	}
+	return nil
+}
+
+// UpdateHostSelector sets the label selector of the hosts a flavorgroup is automatically linked to, an empty selector
+// removes it
+func (f *FlavorGroupStore) UpdateHostSelector(fgId uuid.UUID, hostSelector string) error {
+	defaultLog.Trace("postgres/flavorgroup_store:UpdateHostSelector() Entering")
+	defer defaultLog.Trace("postgres/flavorgroup_store:UpdateHostSelector() Leaving")
+
+	db := f.Store.Db.Model(&flavorGroup{ID: fgId}).Update("host_selector", hostSelector)
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/flavorgroup_store:UpdateHostSelector() failed to update host selector of Flavorgroup")
+	}
+	if db.RowsAffected != 1 {
+		return errors.New("postgres/flavorgroup_store:UpdateHostSelector() - no rows affected - Record not found = id :  " + fgId.String())
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type HostFetchQueueStore struct {
+	Store *DataStore
+}
+
+func NewHostFetchQueueStore(store *DataStore) *HostFetchQueueStore {
+	return &HostFetchQueueStore{store}
+}
+
+// Persist creates the fetch queue entry of a host or replaces the existing one
+func (hfq *HostFetchQueueStore) Persist(entry *hvs.HostFetchQueueEntry) error {
+	defaultLog.Trace("postgres/host_fetch_queue_store:Persist() Entering")
+	defer defaultLog.Trace("postgres/host_fetch_queue_store:Persist() Leaving")
+
+	if entry == nil || entry.HostId == uuid.Nil || !entry.State.Valid() {
+		return errors.New("postgres/host_fetch_queue_store:Persist() - invalid input, must have HostId and valid State")
+	}
+
+	dbEntry := hostFetchQueue{
+		HostId:          entry.HostId,
+		State:           string(entry.State),
+		PreferHashMatch: entry.PreferHashMatch,
+		Attempts:        entry.Attempts,
+		NextAttempt:     entry.NextAttempt.UTC(),
+		LastError:       entry.LastError,
+		UpdatedAt:       time.Now().UTC(),
+	}
+
+	existing := hostFetchQueue{}
+	err := hfq.Store.Db.Where(&hostFetchQueue{HostId: entry.HostId}).First(&existing).Error
+	if err != nil {
+		if !gorm.IsRecordNotFoundError(err) {
+			return errors.Wrap(err, "postgres/host_fetch_queue_store:Persist() failed to retrieve host fetch queue entry")
+		}
+		dbEntry.CreatedAt = dbEntry.UpdatedAt
+		if err := hfq.Store.Db.Create(&dbEntry).Error; err != nil {
+			return errors.Wrap(err, "postgres/host_fetch_queue_store:Persist() failed to create host fetch queue entry")
+		}
+	} else {
+		dbEntry.CreatedAt = existing.CreatedAt
+		if err := hfq.Store.Db.Save(&dbEntry).Error; err != nil {
+			return errors.Wrap(err, "postgres/host_fetch_queue_store:Persist() failed to update host fetch queue entry")
+		}
+	}
+
+	entry.Created = dbEntry.CreatedAt
+	entry.Updated = dbEntry.UpdatedAt
+	return nil
+}
+
+// Retrieve returns the fetch queue entry of a host
+func (hfq *HostFetchQueueStore) Retrieve(hostId uuid.UUID) (*hvs.HostFetchQueueEntry, error) {
+	defaultLog.Trace("postgres/host_fetch_queue_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/host_fetch_queue_store:Retrieve() Leaving")
+
+	dbEntry := hostFetchQueue{}
+	err := hfq.Store.Db.Where(&hostFetchQueue{HostId: hostId}).First(&dbEntry).Error
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.Wrap(errors.New(commErr.RowsNotFound), "postgres/host_fetch_queue_store:Retrieve() - host fetch queue entry not found")
+		}
+		return nil, errors.Wrap(err, "postgres/host_fetch_queue_store:Retrieve() failed to retrieve host fetch queue entry")
+	}
+	entry := toHostFetchQueueEntry(dbEntry)
+	return &entry, nil
+}
+
+// Search returns the fetch queue entries matching the filter criteria, ordered by their next attempt
+func (hfq *HostFetchQueueStore) Search(criteria *models.HostFetchQueueFilterCriteria) ([]hvs.HostFetchQueueEntry, error) {
+	defaultLog.Trace("postgres/host_fetch_queue_store:Search() Entering")
+	defer defaultLog.Trace("postgres/host_fetch_queue_store:Search() Leaving")
+
+	tx := hfq.Store.Db.Model(&hostFetchQueue{})
+	limit := constants.DefaultSearchResultRowLimit
+	if criteria != nil {
+		if criteria.HostId != uuid.Nil {
+			tx = tx.Where("host_id = ?", criteria.HostId)
+		}
+		if criteria.State != "" {
+			tx = tx.Where("state = ?", string(criteria.State))
+		}
+		if !criteria.DueBefore.IsZero() {
+			tx = tx.Where("next_attempt <= ?", criteria.DueBefore.UTC())
+		}
+		if criteria.Limit > 0 {
+			limit = criteria.Limit
+		}
+	}
+
+	var dbEntries []hostFetchQueue
+	if err := tx.Order("next_attempt").Limit(limit).Find(&dbEntries).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/host_fetch_queue_store:Search() failed to retrieve host fetch queue entries")
+	}
+
+	entries := []hvs.HostFetchQueueEntry{}
+	for _, dbEntry := range dbEntries {
+		entries = append(entries, toHostFetchQueueEntry(dbEntry))
+	}
+	return entries, nil
+}
+
+// Delete removes the fetch queue entry of a host
+func (hfq *HostFetchQueueStore) Delete(hostId uuid.UUID) error {
+	defaultLog.Trace("postgres/host_fetch_queue_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/host_fetch_queue_store:Delete() Leaving")
+
+	if hostId == uuid.Nil {
+		return errors.New("postgres/host_fetch_queue_store:Delete() - HostId is invalid")
+	}
+	if err := hfq.Store.Db.Delete(&hostFetchQueue{HostId: hostId}).Error; err != nil {
+		return errors.Wrap(err, "postgres/host_fetch_queue_store:Delete() failed to delete host fetch queue entry")
+	}
+	return nil
+}
+
+func toHostFetchQueueEntry(dbEntry hostFetchQueue) hvs.HostFetchQueueEntry {
+	return hvs.HostFetchQueueEntry{
+		HostId:          dbEntry.HostId,
+		State:           hvs.HostFetchState(dbEntry.State),
+		PreferHashMatch: dbEntry.PreferHashMatch,
+		Attempts:        dbEntry.Attempts,
+		NextAttempt:     dbEntry.NextAttempt,
+		LastError:       dbEntry.LastError,
+		Created:         dbEntry.CreatedAt,
+		Updated:         dbEntry.UpdatedAt,
+	}
+}
@@ -25,7 +25,7 @@ func NewHostStore(store *DataStore) *HostStore {
 }
 
 const (
-	hostFields = "host.id, host.name, host.description, host.connection_string, host.hardware_uuid"
+	hostFields = "host.id, host.name, host.description, host.connection_string, host.hardware_uuid, host.refresh_schedule, host.labels"
 )
 
 func (hs *HostStore) Create(h *hvs.Host) (*hvs.Host, error) {
@@ -47,6 +47,10 @@ func (hs *HostStore) Create(h *hvs.Host) (*hvs.Host, error) {
 	if h.HardwareUuid != nil {
 		dbHost.HardwareUuid = models.NewHwUUID(*h.HardwareUuid)
 	}
+	if !h.RefreshSchedule.IsEmpty() {
+		dbHost.RefreshSchedule = PGRefreshSchedule{Schedule: h.RefreshSchedule}
+	}
+	dbHost.Labels = PGLabels(h.Labels)
 
 	if err := hs.Store.Db.Create(&dbHost).Error; err != nil {
 		return nil, errors.Wrap(err, "postgres/host_store:Create() failed to create Host")
@@ -63,34 +67,36 @@ func (hs *HostStore) Retrieve(id uuid.UUID, criteria *models.HostInfoFetchCriter
 	h := hvs.Host{}
 	report := hvs.TrustReport{}
 	connectionStatus := hvs.HostStatusInformation{}
+	refreshSchedule := PGRefreshSchedule{}
 
 	if criteria != nil && (criteria.GetReport || criteria.GetHostStatus) {
 		row := buildInfoFetchQuery(tx, criteria, nil).Row()
 		if criteria.GetReport && criteria.GetHostStatus {
-			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid,
+			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &refreshSchedule, (*PGLabels)(&h.Labels),
 				(*PGTrustReport)(&report), (*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 			}
 			h.Report = &report
 			h.ConnectionStatus = &connectionStatus
 		} else if criteria.GetReport {
-			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid,
+			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &refreshSchedule, (*PGLabels)(&h.Labels),
 				(*PGTrustReport)(&report)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 			}
 			h.Report = &report
 		} else if criteria.GetHostStatus {
-			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid,
+			if err := row.Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &refreshSchedule, (*PGLabels)(&h.Labels),
 				(*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 			}
 			h.ConnectionStatus = &connectionStatus
 		}
 	} else {
-		if err := tx.Row().Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid); err != nil {
+		if err := tx.Row().Scan(&h.Id, &h.HostName, &h.Description, &h.ConnectionString, &h.HardwareUuid, &refreshSchedule, (*PGLabels)(&h.Labels)); err != nil {
 			return nil, errors.Wrap(err, "postgres/host_store:Retrieve() failed to scan record")
 		}
 	}
+	h.RefreshSchedule = refreshSchedule.Schedule
 
 	return &h, nil
 }
@@ -110,6 +116,13 @@ func (hs *HostStore) Update(h *hvs.Host) error {
 		dbHost.HardwareUuid = models.NewHwUUID(*h.HardwareUuid)
 	}
 
+	if h.RefreshSchedule != nil && !h.RefreshSchedule.IsEmpty() {
+		dbHost.RefreshSchedule = PGRefreshSchedule{Schedule: h.RefreshSchedule}
+	}
+
+	// nil labels are skipped by Updates and keep the labels of the host, empty labels remove them below
+	dbHost.Labels = PGLabels(h.Labels)
+
 	if db := hs.Store.Db.Model(&dbHost).Updates(&dbHost); db.Error != nil || db.RowsAffected != 1 {
 		if db.Error != nil {
 			return errors.Wrap(db.Error, "postgres/host_store:Update() failed to update Host  "+dbHost.Id.String())
@@ -117,6 +130,19 @@ func (hs *HostStore) Update(h *hvs.Host) error {
 			return errors.New("postgres/host_store:Update() - no rows affected - Record not found = id :  " + dbHost.Id.String())
 		}
 	}
+
+	if h.Labels != nil && len(h.Labels) == 0 {
+		if err := hs.Store.Db.Model(&dbHost).Update("labels", gorm.Expr("NULL")).Error; err != nil {
+			return errors.Wrap(err, "postgres/host_store:Update() failed to remove labels of Host "+dbHost.Id.String())
+		}
+	}
+
+	// an empty schedule removes the refresh schedule override of the host, blank fields are skipped by Updates
+	if h.RefreshSchedule != nil && h.RefreshSchedule.IsEmpty() {
+		if err := hs.Store.Db.Model(&dbHost).Update("refresh_schedule", gorm.Expr("NULL")).Error; err != nil {
+			return errors.Wrap(err, "postgres/host_store:Update() failed to remove refresh schedule of Host "+dbHost.Id.String())
+		}
+	}
 	return nil
 }
 
@@ -171,9 +197,11 @@ func (hs *HostStore) Search(filterCriteria *models.HostFilterCriteria, infoFetch
 	} else {
 		for rows.Next() {
 			host := hvs.Host{}
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid); err != nil {
+			refreshSchedule := PGRefreshSchedule{}
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &refreshSchedule, (*PGLabels)(&host.Labels)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
+			host.RefreshSchedule = refreshSchedule.Schedule
 			hosts = append(hosts, &host)
 		}
 	}
@@ -192,8 +220,7 @@ func buildHostSearchQuery(tx *gorm.DB, criteria *models.HostFilterCriteria) *gor
 	tx = tx.Model(&host{})
 
 	if criteria == nil || reflect.DeepEqual(*criteria, models.HostFilterCriteria{}) {
-		tx = tx.Order("name asc")
-		return tx
+		return paginate(tx, "host", "host", "name", false, uuid.Nil, 0)
 	}
 
 	if criteria.Id != uuid.Nil {
@@ -210,12 +237,12 @@ func buildHostSearchQuery(tx *gorm.DB, criteria *models.HostFilterCriteria) *gor
 		tx = tx.Joins("join report on report.host_id = host.id AND report.trusted = ?", criteria.Trusted)
 	}
 
-	if criteria.OrderBy == models.Descending {
-		tx = tx.Order("name desc")
-	} else {
-		tx = tx.Order("name asc")
+	if len(criteria.LabelSelector) > 0 {
+		query, args := labelSelectorQuery("host.labels", criteria.LabelSelector)
+		tx = tx.Where(query, args...)
 	}
-	return tx
+
+	return paginate(tx, "host", "host", "name", criteria.OrderBy == models.Descending, criteria.After, criteria.Limit)
 }
 
 func buildInfoFetchQuery(tx *gorm.DB, infoFetchCriteria *models.HostInfoFetchCriteria,
@@ -255,24 +282,26 @@ func getAdditionalHostInfo(criteria *models.HostInfoFetchCriteria, rows *sql.Row
 	for rows.Next() {
 		host := hvs.Host{}
 		connectionStatus := hvs.HostStatusInformation{}
+		refreshSchedule := PGRefreshSchedule{}
 		if criteria.GetTrustStatus && criteria.GetHostStatus {
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid,
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &refreshSchedule, (*PGLabels)(&host.Labels),
 				&host.Trusted, (*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
 			host.ConnectionStatus = &connectionStatus
 		} else if criteria.GetTrustStatus {
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid,
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &refreshSchedule, (*PGLabels)(&host.Labels),
 				&host.Trusted); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
 		} else if criteria.GetHostStatus {
-			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid,
+			if err := rows.Scan(&host.Id, &host.HostName, &host.Description, &host.ConnectionString, &host.HardwareUuid, &refreshSchedule, (*PGLabels)(&host.Labels),
 				(*PGHostStatusInformation)(&connectionStatus)); err != nil {
 				return nil, errors.Wrap(err, "postgres/host_store:Search() failed to scan record")
 			}
 			host.ConnectionStatus = &connectionStatus
 		}
+		host.RefreshSchedule = refreshSchedule.Schedule
 		hosts = append(hosts, &host)
 	}
 	return hosts, nil
@@ -310,6 +310,14 @@ func buildHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFilterCr
 		additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, hostStateQueryString)
 	}
 
+	//Build label selector partial query string and add it to the additional options query string
+	var args []interface{}
+	if len(hsFilter.LabelSelector) > 0 {
+		var labelSelectorQueryString string
+		labelSelectorQueryString, args = hostLabelSelectorQuery(auditLogAbbrv+".data -> 'Columns' -> 1 ->> 'Value'", hsFilter.LabelSelector)
+		additionalOptionsQueryString = fmt.Sprintf("%s AND %s", additionalOptionsQueryString, labelSelectorQueryString)
+	}
+
 	//Build host status ID partial query string and add it to the additional options query string
 	if hsFilter.Id != uuid.Nil {
 		hostStatusIDQueryString := fmt.Sprintf("%s.entity_id = '%s'", auditLogAbbrv, hsFilter.Id.String())
@@ -356,7 +364,7 @@ func buildHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFilterCr
 	}
 
 	// finalize query
-	tx = tx.Raw(formattedQuery).Limit(hsFilter.Limit)
+	tx = tx.Raw(formattedQuery, args...).Limit(hsFilter.Limit)
 
 	return tx
 }
@@ -409,15 +417,19 @@ func buildLatestHostStatusSearchQuery(tx *gorm.DB, hsFilter *models.HostStatusFi
 		tx = tx.Where(`status @> '{"host_state": "` + strings.ToUpper(hsFilter.HostStatus) + `"}'`)
 	}
 
+	// Host labels
+	if len(hsFilter.LabelSelector) > 0 {
+		query, args := hostLabelSelectorQuery("host_status.host_id", hsFilter.LabelSelector)
+		tx = tx.Where(query, args...)
+	}
+
 	// Apply default row limit when called internally
 	if hsFilter.Limit == 0 {
 		hsFilter.Limit = constants.DefaultSearchResultRowLimit
 	}
 
-	// apply result limits
-	tx = tx.Limit(hsFilter.Limit)
-
-	return tx
+	// apply the page criteria
+	return paginate(tx, "host_status", "host_status", "", false, hsFilter.After, hsFilter.Limit)
 }
 
 func auditlogEntryToHostStatus(auRecord models.AuditLogEntry) (*hvs.HostStatus, error) {
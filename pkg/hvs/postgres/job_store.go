@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type JobStore struct {
+	Store *DataStore
+}
+
+func NewJobStore(store *DataStore) *JobStore {
+	return &JobStore{store}
+}
+
+// Create persists a new job
+func (js *JobStore) Create(j *hvs.Job) (*hvs.Job, error) {
+	defaultLog.Trace("postgres/job_store:Create() Entering")
+	defer defaultLog.Trace("postgres/job_store:Create() Leaving")
+
+	if j == nil || j.Type == "" || !j.Status.Valid() {
+		return nil, errors.New("postgres/job_store:Create() - invalid input, must have Type and valid Status")
+	}
+
+	dbJob := job{
+		Id:        j.Id,
+		Type:      string(j.Type),
+		Status:    string(j.Status),
+		Processed: j.Processed,
+		Total:     j.Total,
+		Result:    PGJobResult(j.Result),
+		Error:     j.Error,
+		Owner:     j.Owner,
+		CreatedAt: time.Now().UTC(),
+	}
+	if dbJob.Id == uuid.Nil {
+		dbJob.Id = uuid.New()
+	}
+	dbJob.UpdatedAt = dbJob.CreatedAt
+
+	if err := js.Store.Db.Create(&dbJob).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/job_store:Create() failed to create job")
+	}
+	createdJob := toJob(dbJob)
+	return &createdJob, nil
+}
+
+// Retrieve returns the job with the given id
+func (js *JobStore) Retrieve(id uuid.UUID) (*hvs.Job, error) {
+	defaultLog.Trace("postgres/job_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/job_store:Retrieve() Leaving")
+
+	dbJob := job{}
+	err := js.Store.Db.Where(&job{Id: id}).First(&dbJob).Error
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.Wrap(errors.New(commErr.RowsNotFound), "postgres/job_store:Retrieve() - job not found")
+		}
+		return nil, errors.Wrap(err, "postgres/job_store:Retrieve() failed to retrieve job")
+	}
+	j := toJob(dbJob)
+	return &j, nil
+}
+
+// Search returns the jobs matching the filter criteria, most recent first
+func (js *JobStore) Search(criteria *models.JobFilterCriteria) ([]hvs.Job, error) {
+	defaultLog.Trace("postgres/job_store:Search() Entering")
+	defer defaultLog.Trace("postgres/job_store:Search() Leaving")
+
+	tx := js.Store.Db.Model(&job{})
+	limit := constants.DefaultSearchResultRowLimit
+	if criteria != nil {
+		if criteria.Type != "" {
+			tx = tx.Where("type = ?", string(criteria.Type))
+		}
+		if len(criteria.Status) > 0 {
+			statuses := make([]string, len(criteria.Status))
+			for i, status := range criteria.Status {
+				statuses[i] = string(status)
+			}
+			tx = tx.Where("status IN (?)", statuses)
+		}
+		if criteria.Owner != "" {
+			tx = tx.Where("owner = ?", criteria.Owner)
+		}
+		if criteria.Limit > 0 {
+			limit = criteria.Limit
+		}
+	}
+
+	var dbJobs []job
+	if err := tx.Order("created desc").Limit(limit).Find(&dbJobs).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/job_store:Search() failed to retrieve jobs")
+	}
+
+	jobs := []hvs.Job{}
+	for _, dbJob := range dbJobs {
+		jobs = append(jobs, toJob(dbJob))
+	}
+	return jobs, nil
+}
+
+// Update stores the status, progress and outcome of a job
+func (js *JobStore) Update(j *hvs.Job) error {
+	defaultLog.Trace("postgres/job_store:Update() Entering")
+	defer defaultLog.Trace("postgres/job_store:Update() Leaving")
+
+	if j == nil || j.Id == uuid.Nil || !j.Status.Valid() {
+		return errors.New("postgres/job_store:Update() - invalid input, must have Id and valid Status")
+	}
+
+	j.Updated = time.Now().UTC()
+	// Updates with a map so that the zero values of the progress and the cleared error are stored as well. A job
+	// cancelled by another instance is left untouched so that the instance running it does not overwrite the status.
+	tx := js.Store.Db.Model(&job{Id: j.Id}).Where("status <> ?", string(hvs.JobStatusCancelled)).Updates(map[string]interface{}{
+		"status":    string(j.Status),
+		"processed": j.Processed,
+		"total":     j.Total,
+		"result":    PGJobResult(j.Result),
+		"error":     j.Error,
+		"updated":   j.Updated,
+	})
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "postgres/job_store:Update() failed to update job")
+	}
+	if tx.RowsAffected == 0 {
+		if _, err := js.Retrieve(j.Id); err != nil {
+			return errors.Wrap(err, "postgres/job_store:Update() failed to retrieve job")
+		}
+		return errors.Wrap(domain.ErrJobCancelled, "postgres/job_store:Update() - job is cancelled")
+	}
+	return nil
+}
+
+func toJob(dbJob job) hvs.Job {
+	var result json.RawMessage
+	if len(dbJob.Result) > 0 {
+		result = json.RawMessage(dbJob.Result)
+	}
+	return hvs.Job{
+		Id:        dbJob.Id,
+		Type:      hvs.JobType(dbJob.Type),
+		Status:    hvs.JobStatus(dbJob.Status),
+		Processed: dbJob.Processed,
+		Total:     dbJob.Total,
+		Result:    result,
+		Error:     dbJob.Error,
+		Owner:     dbJob.Owner,
+		Created:   dbJob.CreatedAt,
+		Updated:   dbJob.UpdatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+)
+
+// labelSelectorQuery returns the condition selecting the rows whose JSONB labels column meets all the requirements of
+// a label selector, along with the arguments of the condition. The keys and values of the requirements are passed as
+// arguments, the JSONB ? operator is avoided since gorm would take it for a placeholder.
+func labelSelectorQuery(column string, selector hvs.LabelSelector) (string, []interface{}) {
+	defaultLog.Trace("postgres/label_selector:labelSelectorQuery() Entering")
+	defer defaultLog.Trace("postgres/label_selector:labelSelectorQuery() Leaving")
+
+	var conditions []string
+	var args []interface{}
+	for _, requirement := range selector {
+		switch requirement.Operator {
+		case hvs.SelectorOpExists:
+			conditions = append(conditions, fmt.Sprintf("%s ->> ? IS NOT NULL", column))
+			args = append(args, requirement.Key)
+		case hvs.SelectorOpDoesNotExist:
+			conditions = append(conditions, fmt.Sprintf("%s ->> ? IS NULL", column))
+			args = append(args, requirement.Key)
+		case hvs.SelectorOpEquals, hvs.SelectorOpIn:
+			conditions = append(conditions, fmt.Sprintf("%s ->> ? IN (?)", column))
+			args = append(args, requirement.Key, requirement.Values)
+		case hvs.SelectorOpNotEquals, hvs.SelectorOpNotIn:
+			conditions = append(conditions, fmt.Sprintf("(%s ->> ? IS NULL OR %s ->> ? NOT IN (?))", column, column))
+			args = append(args, requirement.Key, requirement.Key, requirement.Values)
+		}
+	}
+	if len(conditions) == 0 {
+		return "TRUE", nil
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// hostLabelSelectorQuery returns the condition selecting the rows whose host, identified by the hostIdColumn, has
+// labels that meet all the requirements of a label selector, along with the arguments of the condition
+func hostLabelSelectorQuery(hostIdColumn string, selector hvs.LabelSelector) (string, []interface{}) {
+	query, args := labelSelectorQuery("labels", selector)
+	return fmt.Sprintf("CAST(%s AS VARCHAR) IN (SELECT CAST(id AS VARCHAR) FROM host WHERE %s)", hostIdColumn, query), args
+}
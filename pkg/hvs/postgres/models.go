@@ -25,11 +25,22 @@ type (
 	PGHostStatusInformation hvs.HostStatusInformation
 	PGFlavorContent         hvs.Flavor
 	PGFlavorTemplateContent hvs.FlavorTemplate
+	PGSubscriptionFilter    hvs.SubscriptionFilter
+	PGLabels                hvs.Labels
+	// PGJobResult maps the JSON result of a job to a nullable JSONB column
+	PGJobResult json.RawMessage
+
+	// PGRefreshSchedule maps a nullable refresh schedule to a JSONB column
+	PGRefreshSchedule struct {
+		Schedule *hvs.RefreshSchedule
+	}
 
 	flavorGroup struct {
 		ID                    uuid.UUID             `json:"id" gorm:"primary_key;type:uuid"`
 		Name                  string                `json:"name" gorm:"type:varchar(255);not null;index:idx_flavorgroup_name"`
 		FlavorTypeMatchPolicy PGFlavorMatchPolicies `json:"flavor_type_match_policy,omitempty" sql:"type:JSONB"`
+		RefreshSchedule       PGRefreshSchedule     `json:"refresh_schedule,omitempty" sql:"type:JSONB"`
+		HostSelector          string                `json:"host_selector,omitempty" gorm:"not null;default:''"`
 	}
 
 	flavor struct {
@@ -39,14 +50,21 @@ type (
 		Label      string          `gorm:"unique;not null"`
 		FlavorPart string          `json:"flavor_part"`
 		Signature  string          `json:"signature"`
+		// the lifecycle is kept out of the signed content
+		State          string     `gorm:"not null;default:'active'"`
+		GracePeriodEnd *time.Time `json:"grace_period_end,omitempty"`
+		SupersededBy   *uuid.UUID `json:"superseded_by,omitempty" gorm:"type:uuid"`
+		ExpiresAt      *time.Time `json:"expires_at,omitempty"`
 	}
 
 	host struct {
 		Id               uuid.UUID `gorm:"primary_key;type:uuid"`
 		Name             string    `gorm:"unique;type:varchar(255);not null"`
 		Description      string
-		ConnectionString string        `gorm:"not null"`
-		HardwareUuid     models.HwUUID `gorm:"type:uuid;index:idx_host_hardware_uuid"`
+		ConnectionString string            `gorm:"not null"`
+		HardwareUuid     models.HwUUID     `gorm:"type:uuid;index:idx_host_hardware_uuid"`
+		RefreshSchedule  PGRefreshSchedule `sql:"type:JSONB"`
+		Labels           PGLabels          `sql:"type:JSONB"`
 	}
 
 	hostFlavorgroup struct {
@@ -131,6 +149,42 @@ type (
 		Saml        string        `gorm:"column:saml;not null"`
 	}
 
+	// hostFetchQueue holds the pending host data fetches and their retry state, one record per host
+	hostFetchQueue struct {
+		HostId          uuid.UUID `gorm:"primary_key;type:uuid REFERENCES host(Id) ON UPDATE CASCADE ON DELETE CASCADE"`
+		State           string    `gorm:"not null"`
+		PreferHashMatch bool      `gorm:"not null"`
+		Attempts        int       `gorm:"not null"`
+		NextAttempt     time.Time `gorm:"not null;index:idx_host_fetch_queue_next_attempt"`
+		LastError       string
+		CreatedAt       time.Time `gorm:"column:created;not null"`
+		UpdatedAt       time.Time `gorm:"column:updated;not null"`
+	}
+
+	// subscription holds the endpoints that are notified of host events
+	subscription struct {
+		Id           uuid.UUID            `gorm:"primary_key;type:uuid"`
+		DeliveryType string               `gorm:"not null"`
+		Endpoint     string               `gorm:"not null"`
+		Filter       PGSubscriptionFilter `sql:"type:JSONB NOT NULL DEFAULT '{}'::JSONB"`
+		Secret       string               `gorm:"not null"`
+		CreatedAt    time.Time            `gorm:"column:created;not null"`
+	}
+
+	// job holds the long running operations processed in the background along with their progress and outcome
+	job struct {
+		Id        uuid.UUID   `gorm:"primary_key;type:uuid"`
+		Type      string      `gorm:"not null;index:idx_job_type"`
+		Status    string      `gorm:"not null;index:idx_job_status"`
+		Processed int         `gorm:"not null"`
+		Total     int         `gorm:"not null"`
+		Result    PGJobResult `sql:"type:JSONB"`
+		Error     string
+		Owner     string    `gorm:"index:idx_job_owner"`
+		CreatedAt time.Time `gorm:"column:created;not null"`
+		UpdatedAt time.Time `gorm:"column:updated;not null"`
+	}
+
 	tpmEndorsement struct {
 		ID                uuid.UUID `gorm:"primary_key;type:uuid"`
 		HardwareUUID      uuid.UUID `gorm:"column:hardware_uuid;not null;type:uuid"`
@@ -216,6 +270,45 @@ func (fmp *PGFlavorMatchPolicies) Scan(value interface{}) error {
 	return json.Unmarshal(b, &fmp)
 }
 
+func (rs PGRefreshSchedule) Value() (driver.Value, error) {
+	if rs.Schedule == nil {
+		return nil, nil
+	}
+	return json.Marshal(rs.Schedule)
+}
+
+func (rs *PGRefreshSchedule) Scan(value interface{}) error {
+	if value == nil {
+		rs.Schedule = nil
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGRefreshSchedule_Scan() - type assertion to []byte failed")
+	}
+	rs.Schedule = &hvs.RefreshSchedule{}
+	return json.Unmarshal(b, rs.Schedule)
+}
+
+func (labels PGLabels) Value() (driver.Value, error) {
+	if labels == nil {
+		return nil, nil
+	}
+	return json.Marshal(labels)
+}
+
+func (labels *PGLabels) Scan(value interface{}) error {
+	if value == nil {
+		*labels = nil
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGLabels_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, labels)
+}
+
 func (trp PGTrustReport) Value() (driver.Value, error) {
 	return json.Marshal(trp)
 }
@@ -263,3 +356,35 @@ func (fl *PGFlavorTemplateContent) Scan(value interface{}) error {
 	}
 	return json.Unmarshal(b, &fl)
 }
+
+func (sf PGSubscriptionFilter) Value() (driver.Value, error) {
+	return json.Marshal(sf)
+}
+
+func (sf *PGSubscriptionFilter) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGSubscriptionFilter_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &sf)
+}
+
+func (jr PGJobResult) Value() (driver.Value, error) {
+	if len(jr) == 0 {
+		return nil, nil
+	}
+	return []byte(jr), nil
+}
+
+func (jr *PGJobResult) Scan(value interface{}) error {
+	if value == nil {
+		*jr = nil
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGJobResult_Scan() - type assertion to []byte failed")
+	}
+	*jr = append((*jr)[:0], b...)
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// paginate orders the rows of a search query by the sort column, if any, and then by id so that the order is stable.
+// When after is set, only the rows that follow the row with this id in that order are selected, this is the cursor of
+// the page. When limit is set, at most limit rows are selected.
+func paginate(tx *gorm.DB, table, alias, sortColumn string, descending bool, after uuid.UUID, limit int) *gorm.DB {
+	defaultLog.Trace("postgres/pagination:paginate() Entering")
+	defer defaultLog.Trace("postgres/pagination:paginate() Leaving")
+
+	if tx == nil {
+		return nil
+	}
+
+	direction, comparison := "asc", ">"
+	if descending {
+		direction, comparison = "desc", "<"
+	}
+
+	keys, cursorKeys := alias+".id", "id"
+	if sortColumn != "" {
+		keys = fmt.Sprintf("%s.%s, %s", alias, sortColumn, keys)
+		cursorKeys = sortColumn + ", id"
+		tx = tx.Order(fmt.Sprintf("%s.%s %s", alias, sortColumn, direction))
+	}
+	tx = tx.Order(fmt.Sprintf("%s.id %s", alias, direction))
+
+	if after != uuid.Nil {
+		tx = tx.Where(fmt.Sprintf("(%s) %s (SELECT %s FROM %s WHERE id = ?)", keys, comparison, cursorKeys, table), after)
+	}
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	return tx
+}
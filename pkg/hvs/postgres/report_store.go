@@ -17,6 +17,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
@@ -164,7 +166,7 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 
 	var tx *gorm.DB
 	if fromDate.IsZero() && toDate.IsZero() && criteria.LatestPerHost {
-		tx = buildLatestReportSearchQuery(r.Store.Db, reportID, hostID, hostHardwareUUID, hostName, hostStatus, criteria.Limit)
+		tx = buildLatestReportSearchQuery(r.Store.Db, reportID, hostID, hostHardwareUUID, hostName, hostStatus, criteria.LabelSelector, criteria.After, criteria.Limit)
 
 		if tx == nil {
 			return nil, errors.New("postgres/report_store:Search() Unexpected Error. Could not build" +
@@ -195,7 +197,7 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 
 		return reports, nil
 	} else {
-		tx = buildReportSearchQuery(r.Store.Db, hostID, hostHardwareUUID, hostName, hostStatus, fromDate, toDate, latestPerHost, criteria.Limit)
+		tx = buildReportSearchQuery(r.Store.Db, hostID, hostHardwareUUID, hostName, hostStatus, criteria.LabelSelector, fromDate, toDate, latestPerHost, criteria.Limit)
 		if tx == nil {
 			return nil, errors.New("postgres/report_store:Search() Unexpected Error. Could not build" +
 				" a gorm query object in HVSReport Search function.")
@@ -232,6 +234,58 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 	}
 }
 
+// RetrieveFromAuditLog fetches a report that may have been replaced by a newer report of the host from the audit log
+func (r *ReportStore) RetrieveFromAuditLog(reportId uuid.UUID) (*models.HVSReport, error) {
+	defaultLog.Trace("postgres/report_store:RetrieveFromAuditLog() Entering")
+	defer defaultLog.Trace("postgres/report_store:RetrieveFromAuditLog() Leaving")
+
+	tx := r.Store.Db.Table("audit_log_entry au").Select("au.*").
+		Where("au.entity_type = 'report' AND au.entity_id = ?", reportId).
+		Order("au.created").Limit(1)
+	return scanAuditLogReport(tx)
+}
+
+// FindLatestTrustedReport fetches the most recent trusted report of a host that was created before the given time
+// from the audit log
+func (r *ReportStore) FindLatestTrustedReport(hostId uuid.UUID, before time.Time) (*models.HVSReport, error) {
+	defaultLog.Trace("postgres/report_store:FindLatestTrustedReport() Entering")
+	defer defaultLog.Trace("postgres/report_store:FindLatestTrustedReport() Leaving")
+
+	tx := r.Store.Db.Table("audit_log_entry au").Select("au.*").
+		Where("au.entity_type = 'report'").
+		Where("au.data -> 'Columns' -> 1 ->> 'Value' = ?", hostId.String()).
+		Where("au.data -> 'Columns' -> 2 -> 'Value' ->> 'trusted' = 'true'").
+		Where("CAST(au.created AS TIMESTAMP) < CAST(? AS TIMESTAMP)", before).
+		Order("au.created DESC").Limit(1)
+	return scanAuditLogReport(tx)
+}
+
+// scanAuditLogReport converts the single audit log entry selected by the query into a report
+func scanAuditLogReport(tx *gorm.DB) (*models.HVSReport, error) {
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/report_store:scanAuditLogReport() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	for rows.Next() {
+		result := models.AuditLogEntry{}
+		if err := rows.Scan(&result.ID, &result.EntityID, &result.EntityType, &result.CreatedAt, &result.Action, (*PGAuditLogData)(&result.Data)); err != nil {
+			return nil, errors.Wrap(err, "postgres/report_store:scanAuditLogReport() failed to scan record")
+		}
+		if len(result.Data.Columns) < 6 {
+			continue
+		}
+		return auditlogEntryToReport(result)
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
 // FindHostIdsFromExpiredReports searches the report table for reports that have an
 // 'expiration' between 'fromTime' and 'toTime'.
 // It also discovers hosts that do not have a corresponding report in the table.
@@ -268,6 +322,96 @@ func (r *ReportStore) FindHostIdsFromExpiredReports(fromTime time.Time, toTime t
 	return hostIDs, nil
 }
 
+// CountHostsByTrust returns the number of hosts whose latest report is trusted and untrusted respectively
+func (r *ReportStore) CountHostsByTrust() (int, int, error) {
+	defaultLog.Trace("postgres/report_store:CountHostsByTrust() Entering")
+	defer defaultLog.Trace("postgres/report_store:CountHostsByTrust() Leaving")
+
+	rows, err := r.Store.Db.Raw("SELECT trusted, COUNT(DISTINCT host_id) FROM report GROUP BY trusted").Rows()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "postgres/report_store:CountHostsByTrust() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	var trusted, untrusted int
+	for rows.Next() {
+		var isTrusted bool
+		var count int
+		if err := rows.Scan(&isTrusted, &count); err != nil {
+			return 0, 0, errors.Wrap(err, "postgres/report_store:CountHostsByTrust() failed to scan record")
+		}
+		if isTrusted {
+			trusted = count
+		} else {
+			untrusted = count
+		}
+	}
+	return trusted, untrusted, nil
+}
+
+// FindHostRefreshSchedules returns the effective refresh schedule of the hosts that have a report and are not already
+// queued for verification. The schedule of a host overrides the schedules of its flavor groups.
+func (r *ReportStore) FindHostRefreshSchedules() ([]models.HostRefreshSchedule, error) {
+	defaultLog.Trace("postgres/report_store:FindHostRefreshSchedules() Entering")
+	defer defaultLog.Trace("postgres/report_store:FindHostRefreshSchedules() Leaving")
+
+	rows, err := r.Store.Db.Raw("SELECT h.id, h.refresh_schedule, fg.refresh_schedule, r.created FROM host h " +
+		"INNER JOIN report r ON h.id = r.host_id " +
+		"LEFT JOIN host_flavorgroup hf ON h.id = hf.host_id " +
+		"LEFT JOIN flavor_group fg ON hf.flavorgroup_id = fg.id " +
+		"WHERE (h.refresh_schedule IS NOT NULL OR fg.refresh_schedule IS NOT NULL) " +
+		"AND h.id NOT IN (SELECT CAST(params ->> 'host_id' AS uuid) from queue)").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/report_store:FindHostRefreshSchedules() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing rows")
+		}
+	}()
+
+	// a host is returned once for each of its flavor groups
+	var hostIDs []uuid.UUID
+	hostSchedules := make(map[uuid.UUID]*hvs.RefreshSchedule)
+	flavorgroupSchedules := make(map[uuid.UUID][]*hvs.RefreshSchedule)
+	lastRefresh := make(map[uuid.UUID]time.Time)
+	for rows.Next() {
+		hostID := uuid.UUID{}
+		hostSchedule := PGRefreshSchedule{}
+		flavorgroupSchedule := PGRefreshSchedule{}
+		var created time.Time
+		if err := rows.Scan(&hostID, &hostSchedule, &flavorgroupSchedule, &created); err != nil {
+			return nil, errors.Wrap(err, "postgres/report_store:FindHostRefreshSchedules() failed to scan record")
+		}
+		if _, ok := lastRefresh[hostID]; !ok {
+			hostIDs = append(hostIDs, hostID)
+		}
+		hostSchedules[hostID] = hostSchedule.Schedule
+		flavorgroupSchedules[hostID] = append(flavorgroupSchedules[hostID], flavorgroupSchedule.Schedule)
+		lastRefresh[hostID] = created
+	}
+
+	var schedules []models.HostRefreshSchedule
+	for _, hostID := range hostIDs {
+		schedule := hvs.GetEffectiveRefreshSchedule(hostSchedules[hostID], flavorgroupSchedules[hostID])
+		if schedule == nil || schedule.IntervalSeconds <= 0 {
+			continue
+		}
+		schedules = append(schedules, models.HostRefreshSchedule{
+			HostID:      hostID,
+			Schedule:    *schedule,
+			LastRefresh: lastRefresh[hostID],
+		})
+	}
+	return schedules, nil
+}
+
 func auditlogEntryToReport(auRecord models.AuditLogEntry) (*models.HVSReport, error) {
 	defaultLog.Trace("postgres/report_store:auditlogEntryToReport() Entering")
 	defer defaultLog.Trace("postgres/report_store:auditlogEntryToReport() Leaving")
@@ -343,7 +487,7 @@ func (r *ReportStore) Delete(reportId uuid.UUID) error {
 }
 
 // buildReportSearchQuery is a helper function to build the query object for a report search.
-func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostName, hostState string, fromDate, toDate time.Time, latestPerHost bool, limit int) *gorm.DB {
+func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostName, hostState string, labelSelector hvs.LabelSelector, fromDate, toDate time.Time, latestPerHost bool, limit int) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildReportSearchQuery() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildReportSearchQuery() Leaving")
 	if tx == nil {
@@ -352,20 +496,20 @@ func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostN
 	if latestPerHost {
 		entity := "auj"
 		txSubQuery := tx.Table("audit_log_entry auj").Select("data -> 'Columns' -> 1 ->> 'Value' AS host_id, max(auj.created) AS max_date ")
-		txSubQuery = buildReportSearchQueryWithCriteria(txSubQuery, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
+		txSubQuery = buildReportSearchQueryWithCriteria(txSubQuery, hostHardwareID, hostID, entity, hostName, hostState, labelSelector, fromDate, toDate)
 		txSubQuery = txSubQuery.Group("host_id")
 		subQuery := txSubQuery.SubQuery()
 		tx = tx.Table("audit_log_entry au").Select("au.*").Joins("INNER JOIN ? a ON a.host_id = au.data -> 'Columns' -> 1 ->> 'Value' AND a.max_date = au.created", subQuery)
 	} else {
 		entity := "au"
 		tx = tx.Table("audit_log_entry au").Select("au.*")
-		tx = buildReportSearchQueryWithCriteria(tx, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
+		tx = buildReportSearchQueryWithCriteria(tx, hostHardwareID, hostID, entity, hostName, hostState, labelSelector, fromDate, toDate)
 	}
 	tx = tx.Limit(limit)
 	return tx
 }
 
-func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, entity, hostName string, hostState string, fromDate, toDate time.Time) *gorm.DB {
+func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, entity, hostName string, hostState string, labelSelector hvs.LabelSelector, fromDate, toDate time.Time) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildReportSearchQueryWithCriteria() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildReportSearchQueryWithCriteria() Leaving")
 
@@ -396,6 +540,11 @@ func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid
 		tx = tx.Where("hs.status ->> 'host_state' = ?", strings.ToUpper(hostState))
 	}
 
+	if len(labelSelector) > 0 {
+		query, args := hostLabelSelectorQuery(entity+".data -> 'Columns' -> 1 ->> 'Value'", labelSelector)
+		tx = tx.Where(query, args...)
+	}
+
 	if !fromDate.IsZero() {
 		tx = tx.Where("CAST("+entity+".created AS TIMESTAMP) >= CAST(? AS TIMESTAMP)", fromDate)
 	}
@@ -408,7 +557,7 @@ func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid
 }
 
 // buildLatestReportSearchQuery is a helper function to build the query object for a latest report search.
-func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID uuid.UUID, hostName, hostState string, limit int) *gorm.DB {
+func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID uuid.UUID, hostName, hostState string, labelSelector hvs.LabelSelector, after uuid.UUID, limit int) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildLatestReportSearchQuery() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildLatestReportSearchQuery() Leaving")
 
@@ -444,6 +593,10 @@ func buildLatestReportSearchQuery(tx *gorm.DB, reportID, hostID, hostHardwareID
 		tx = tx.Where("host_id = ?", hostID.String())
 	}
 
-	tx = tx.Limit(limit)
-	return tx
+	if len(labelSelector) > 0 {
+		query, args := hostLabelSelectorQuery("report.host_id", labelSelector)
+		tx = tx.Where(query, args...)
+	}
+
+	return paginate(tx, "report", "report", "", false, after, limit)
 }
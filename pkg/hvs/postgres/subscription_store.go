@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type SubscriptionStore struct {
+	Store *DataStore
+}
+
+func NewSubscriptionStore(store *DataStore) *SubscriptionStore {
+	return &SubscriptionStore{store}
+}
+
+// Create persists a new event subscription
+func (ss *SubscriptionStore) Create(s *hvs.Subscription) (*hvs.Subscription, error) {
+	defaultLog.Trace("postgres/subscription_store:Create() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Create() Leaving")
+
+	if s == nil || !s.DeliveryType.Valid() || s.Endpoint == "" {
+		return nil, errors.New("postgres/subscription_store:Create() - invalid input, must have DeliveryType and Endpoint")
+	}
+
+	dbSubscription := subscription{
+		Id:           uuid.New(),
+		DeliveryType: string(s.DeliveryType),
+		Endpoint:     s.Endpoint,
+		Filter:       PGSubscriptionFilter(s.Filter),
+		Secret:       s.Secret,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := ss.Store.Db.Create(&dbSubscription).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/subscription_store:Create() failed to create subscription")
+	}
+	created := toSubscription(dbSubscription)
+	return &created, nil
+}
+
+// Retrieve returns an event subscription
+func (ss *SubscriptionStore) Retrieve(id uuid.UUID) (*hvs.Subscription, error) {
+	defaultLog.Trace("postgres/subscription_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Retrieve() Leaving")
+
+	dbSubscription := subscription{}
+	err := ss.Store.Db.Where(&subscription{Id: id}).First(&dbSubscription).Error
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.Wrap(errors.New(commErr.RowsNotFound), "postgres/subscription_store:Retrieve() - subscription not found")
+		}
+		return nil, errors.Wrap(err, "postgres/subscription_store:Retrieve() failed to retrieve subscription")
+	}
+	s := toSubscription(dbSubscription)
+	return &s, nil
+}
+
+// Search returns the event subscriptions matching the filter criteria, oldest first
+func (ss *SubscriptionStore) Search(criteria *models.SubscriptionFilterCriteria) ([]hvs.Subscription, error) {
+	defaultLog.Trace("postgres/subscription_store:Search() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Search() Leaving")
+
+	tx := ss.Store.Db.Model(&subscription{})
+	limit := constants.DefaultSearchResultRowLimit
+	if criteria != nil {
+		if criteria.DeliveryType != "" {
+			tx = tx.Where("delivery_type = ?", string(criteria.DeliveryType))
+		}
+		if criteria.Limit > 0 {
+			limit = criteria.Limit
+		}
+	}
+
+	var dbSubscriptions []subscription
+	if err := tx.Order("created").Limit(limit).Find(&dbSubscriptions).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/subscription_store:Search() failed to retrieve subscriptions")
+	}
+
+	subscriptions := []hvs.Subscription{}
+	for _, dbSubscription := range dbSubscriptions {
+		subscriptions = append(subscriptions, toSubscription(dbSubscription))
+	}
+	return subscriptions, nil
+}
+
+// Delete removes an event subscription
+func (ss *SubscriptionStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/subscription_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Delete() Leaving")
+
+	db := ss.Store.Db.Delete(&subscription{Id: id})
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/subscription_store:Delete() failed to delete subscription")
+	}
+	if db.RowsAffected != 1 {
+		return errors.Wrap(errors.New(commErr.RowsNotFound), "postgres/subscription_store:Delete() - subscription not found")
+	}
+	return nil
+}
+
+func toSubscription(dbSubscription subscription) hvs.Subscription {
+	return hvs.Subscription{
+		Id:           dbSubscription.Id,
+		DeliveryType: hvs.SubscriptionDeliveryType(dbSubscription.DeliveryType),
+		Endpoint:     dbSubscription.Endpoint,
+		Filter:       hvs.SubscriptionFilter(dbSubscription.Filter),
+		Secret:       dbSubscription.Secret,
+		Created:      dbSubscription.CreatedAt,
+	}
+}
@@ -172,8 +172,6 @@ func buildTagCertificateSearchQuery(tx *gorm.DB, tcFilter *models.TagCertificate
 		tx = tx.Where("CAST(? as timestamp) <= notafter", validAfterTs)
 	}
 
-	// ORDER BY
-	tx = tx.Order("subject")
-
-	return tx
+	// ORDER BY subject and apply the page criteria
+	return paginate(tx, "tag_certificate", "tag_certificate", "subject", false, tcFilter.After, tcFilter.Limit)
 }
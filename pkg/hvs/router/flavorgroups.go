@@ -15,19 +15,22 @@ import (
 )
 
 // SetFlavorGroupRoutes registers routes for flavorgroups
-func SetFlavorGroupRoutes(router *mux.Router, store *postgres.DataStore, flavorgroupStore *postgres.FlavorGroupStore, hostTrustManager domain.HostTrustManager) *mux.Router {
+func SetFlavorGroupRoutes(router *mux.Router, store *postgres.DataStore, flavorgroupStore *postgres.FlavorGroupStore, hostTrustManager domain.HostTrustManager, jobManager domain.JobManager) *mux.Router {
 	defaultLog.Trace("router/flavorgroups:SetFlavorGroupRoutes() Entering")
 	defer defaultLog.Trace("router/flavorgroups:SetFlavorGroupRoutes() Leaving")
 
 	flavorStore := postgres.NewFlavorStore(store)
 	hostStore := postgres.NewHostStore(store)
+	hostStatusStore := postgres.NewHostStatusStore(store)
 	flavorTemplateStore := postgres.NewFlavorTemplateStore(store)
 	flavorgroupController := controllers.FlavorgroupController{
 		FlavorGroupStore:    flavorgroupStore,
 		FlavorTemplateStore: flavorTemplateStore,
 		FlavorStore:         flavorStore,
 		HostStore:           hostStore,
+		HostStatusStore:     hostStatusStore,
 		HTManager:           hostTrustManager,
+		JobManager:          jobManager,
 	}
 
 	flavorGroupIdExpr := fmt.Sprintf("%s%s", "/flavorgroups/", validation.IdReg)
@@ -67,5 +70,34 @@ func SetFlavorGroupRoutes(router *mux.Router, store *postgres.DataStore, flavorg
 		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorgroupController.SearchFlavors),
 			[]string{constants.FlavorGroupSearch}))).Methods("GET")
 
+	// routes for the FlavorGroup refresh schedule APIs
+	fgRefreshScheduleExpr := fmt.Sprintf("/flavorgroups/{fgID:%s}/refresh-schedule", validation.UUIDReg)
+
+	router.Handle(fgRefreshScheduleExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorgroupController.UpdateRefreshSchedule),
+			[]string{constants.FlavorGroupCreate}))).Methods("PUT")
+
+	router.Handle(fgRefreshScheduleExpr,
+		ErrorHandler(permissionsHandler(ResponseHandler(flavorgroupController.DeleteRefreshSchedule),
+			[]string{constants.FlavorGroupDelete}))).Methods("DELETE")
+
+	// routes for the FlavorGroup host selector APIs
+	fgHostSelectorExpr := fmt.Sprintf("/flavorgroups/{fgID:%s}/host-selector", validation.UUIDReg)
+
+	router.Handle(fgHostSelectorExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorgroupController.UpdateHostSelector),
+			[]string{constants.FlavorGroupUpdate}))).Methods("PUT")
+
+	router.Handle(fgHostSelectorExpr,
+		ErrorHandler(permissionsHandler(ResponseHandler(flavorgroupController.DeleteHostSelector),
+			[]string{constants.FlavorGroupDelete}))).Methods("DELETE")
+
+	// route for the FlavorGroup trust simulation API
+	fgSimulateExpr := fmt.Sprintf("/flavorgroups/{fgID:%s}/simulate", validation.UUIDReg)
+
+	router.Handle(fgSimulateExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorgroupController.Simulate),
+			[]string{constants.FlavorGroupRetrieve}))).Methods("POST")
+
 	return router
 }
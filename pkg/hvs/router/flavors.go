@@ -17,7 +17,7 @@ import (
 )
 
 // SetFlavorRoutes registers routes for flavors
-func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, flavorControllerConfig domain.HostControllerConfig) *mux.Router {
+func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, flavorControllerConfig domain.HostControllerConfig, jobManager domain.JobManager) *mux.Router {
 	defaultLog.Trace("router/flavors:SetFlavorRoutes() Entering")
 	defer defaultLog.Trace("router/flavors:SetFlavorRoutes() Leaving")
 
@@ -26,6 +26,9 @@ func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupS
 	tagCertStore := postgres.NewTagCertificateStore(store)
 	flavorTemplateStore := postgres.NewFlavorTemplateStore(store)
 	flavorController := controllers.NewFlavorController(flavorStore, flavorGroupStore, hostStore, tagCertStore, hostTrustManager, certStore, flavorControllerConfig, flavorTemplateStore)
+	if flavorController != nil {
+		flavorController.JobManager = jobManager
+	}
 
 	flavorIdExpr := fmt.Sprintf("%s%s", "/flavors/", validation.IdReg)
 
@@ -38,6 +41,14 @@ func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupS
 		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.Search),
 			[]string{constants.FlavorSearch}))).Methods("GET")
 
+	router.Handle("/flavors/export",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.Export),
+			[]string{constants.FlavorExport}))).Methods("GET")
+
+	router.Handle("/flavors/import",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.Import),
+			[]string{constants.FlavorImport}))).Methods("POST")
+
 	router.Handle(flavorIdExpr,
 		ErrorHandler(permissionsHandler(ResponseHandler(flavorController.Delete),
 			[]string{constants.FlavorDelete}))).Methods("DELETE")
@@ -46,5 +57,9 @@ func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupS
 		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.Retrieve),
 			[]string{constants.FlavorRetrieve}))).Methods("GET")
 
+	router.Handle(flavorIdExpr+"/lifecycle",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(flavorController.UpdateLifecycle),
+			[]string{constants.FlavorUpdate}))).Methods("PUT")
+
 	return router
 }
@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
+)
+
+// SetHostFetchQueueRoutes registers routes for the host data fetch queue APIs
+func SetHostFetchQueueRoutes(router *mux.Router, store *postgres.DataStore) *mux.Router {
+	defaultLog.Trace("router/host_fetch_queue:SetHostFetchQueueRoutes() Entering")
+	defer defaultLog.Trace("router/host_fetch_queue:SetHostFetchQueueRoutes() Leaving")
+
+	hostFetchQueueStore := postgres.NewHostFetchQueueStore(store)
+	hostFetchQueueController := controllers.HostFetchQueueController{Store: hostFetchQueueStore}
+
+	router.Handle("/host-fetch-queue", ErrorHandler(permissionsHandler(JsonResponseHandler(hostFetchQueueController.Search),
+		[]string{constants.HostFetchQueueSearch}))).Methods("GET")
+
+	hostIdExpr := fmt.Sprintf("%s/{hId:%s}", "/host-fetch-queue", validation.UUIDReg)
+	router.Handle(hostIdExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostFetchQueueController.Retrieve),
+		[]string{constants.HostFetchQueueRetrieve}))).Methods("GET")
+	router.Handle(hostIdExpr+"/reset", ErrorHandler(permissionsHandler(JsonResponseHandler(hostFetchQueueController.Reset),
+		[]string{constants.HostFetchQueueReset}))).Methods("POST")
+
+	return router
+}
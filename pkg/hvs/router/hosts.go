@@ -11,12 +11,13 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
 )
 
 // SetHostRoutes registers routes for hosts
-func SetHostRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) *mux.Router {
+func SetHostRoutes(router *mux.Router, store *postgres.DataStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, jobManager domain.JobManager) *mux.Router {
 	defaultLog.Trace("router/hosts:SetHostRoutes() Entering")
 	defer defaultLog.Trace("router/hosts:SetHostRoutes() Leaving")
 
@@ -30,8 +31,18 @@ func SetHostRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManag
 		flavorStore, flavorGroupStore, hostCredentialStore,
 		hostTrustManager, hostControllerConfig)
 
+	hostBulkController := controllers.NewHostBulkController(hostController, postgres.NewJobStore(store), jobManager,
+		constants.HostBulkWorkers)
+
+	hostEvidenceController := controllers.NewHostEvidenceController(hostStore, hostStatusStore, hostTrustManager,
+		certStore, constants.DefaultHostNonceValidity)
+
 	hostExpr := "/hosts"
 	hostIdExpr := fmt.Sprintf("%s/{hId:%s}", hostExpr, validation.UUIDReg)
+	nonceExpr := fmt.Sprintf("%s/nonce", hostIdExpr)
+	evidenceExpr := fmt.Sprintf("%s/evidence", hostIdExpr)
+	bulkExpr := fmt.Sprintf("%s/bulk", hostExpr)
+	bulkIdExpr := fmt.Sprintf("%s/{id:%s}", bulkExpr, validation.UUIDReg)
 	flavorgroupExpr := fmt.Sprintf("%s/flavorgroups", hostIdExpr)
 	flavorgroupIdExpr := fmt.Sprintf("%s/{fgId:%s}", flavorgroupExpr, validation.UUIDReg)
 
@@ -46,6 +57,11 @@ func SetHostRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManag
 	router.Handle(hostExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.Search),
 		[]string{constants.HostSearch}))).Methods("GET")
 
+	router.Handle(bulkExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostBulkController.Create),
+		[]string{constants.HostCreate}))).Methods("POST")
+	router.Handle(bulkIdExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostBulkController.Retrieve),
+		[]string{constants.HostCreate}))).Methods("GET")
+
 	router.Handle(flavorgroupExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.AddFlavorgroup),
 		[]string{constants.HostCreate}))).Methods("POST")
 	router.Handle(flavorgroupIdExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.RetrieveFlavorgroup),
@@ -55,5 +71,10 @@ func SetHostRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManag
 	router.Handle(flavorgroupExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostController.SearchFlavorgroups),
 		[]string{constants.HostSearch}))).Methods("GET")
 
+	router.Handle(nonceExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(hostEvidenceController.RetrieveNonce),
+		[]string{constants.HostNonceRetrieve}))).Methods("GET")
+	router.Handle(evidenceExpr, ErrorHandler(permissionsHandler(ResponseHandler(hostEvidenceController.SubmitEvidence),
+		[]string{constants.HostEvidenceCreate}))).Methods("POST")
+
 	return router
 }
@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
+)
+
+// SetJobRoutes registers routes for the job APIs
+func SetJobRoutes(router *mux.Router, store *postgres.DataStore, jobManager domain.JobManager) *mux.Router {
+	defaultLog.Trace("router/jobs:SetJobRoutes() Entering")
+	defer defaultLog.Trace("router/jobs:SetJobRoutes() Leaving")
+
+	jobController := controllers.JobController{Store: postgres.NewJobStore(store), Manager: jobManager}
+
+	router.Handle("/jobs", ErrorHandler(permissionsHandler(JsonResponseHandler(jobController.Search),
+		[]string{constants.JobSearch}))).Methods("GET")
+
+	jobIdExpr := fmt.Sprintf("%s/{id:%s}", "/jobs", validation.UUIDReg)
+	router.Handle(jobIdExpr, ErrorHandler(permissionsHandler(JsonResponseHandler(jobController.Retrieve),
+		[]string{constants.JobRetrieve}))).Methods("GET")
+	router.Handle(jobIdExpr+"/cancel", ErrorHandler(permissionsHandler(JsonResponseHandler(jobController.Cancel),
+		[]string{constants.JobCancel}))).Methods("POST")
+
+	return router
+}
@@ -0,0 +1,22 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
+)
+
+// SetMetricsRoutes registers the route exposing the prometheus metrics
+func SetMetricsRoutes(router *mux.Router) *mux.Router {
+	defaultLog.Trace("router/metrics:SetMetricsRoutes() Entering")
+	defer defaultLog.Trace("router/metrics:SetMetricsRoutes() Leaving")
+
+	router.Handle("/metrics",
+		ErrorHandler(permissionsHandler(metrics.WriteMetrics,
+			[]string{constants.MetricsRetrieve}))).Methods("GET")
+	return router
+}
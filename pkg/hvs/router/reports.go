@@ -17,7 +17,7 @@ import (
 )
 
 // SetReportRoutes registers routes for reports
-func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManager domain.HostTrustManager) *mux.Router {
+func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustManager domain.HostTrustManager, jobManager domain.JobManager) *mux.Router {
 	defaultLog.Trace("router/reports:SetReportRoutes() Entering")
 	defer defaultLog.Trace("router/reports:SetReportRoutes() Leaving")
 
@@ -25,6 +25,7 @@ func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustMan
 	hostStore := postgres.NewHostStore(store)
 	hostStatusStore := postgres.NewHostStatusStore(store)
 	reportController := controllers.NewReportController(reportStore, hostStore, hostStatusStore, hostTrustManager)
+	reportController.JobManager = jobManager
 
 	reportIdExpr := fmt.Sprintf("%s%s", "/reports/", validation.IdReg)
 
@@ -44,6 +45,10 @@ func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustMan
 		ErrorHandler(permissionsHandler(JsonResponseHandler(reportController.Retrieve),
 			[]string{constants.ReportRetrieve}))).Methods("GET")
 
+	router.Handle(reportIdExpr+"/diff",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(reportController.Diff),
+			[]string{constants.ReportRetrieve}))).Methods("GET")
+
 	router.Handle("/reports",
 		ErrorHandler(permissionsHandler(JsonResponseHandler(reportController.Search),
 			[]string{constants.ReportSearch}))).Methods("GET")
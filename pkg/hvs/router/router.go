@@ -21,8 +21,10 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
 	cmw "github.com/intel-secl/intel-secl/v4/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v4/pkg/lib/common/os"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 	"github.com/pkg/errors"
 )
 
@@ -34,7 +36,7 @@ type Router struct {
 }
 
 // InitRoutes registers all routes for the application.
-func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) (*mux.Router, error) {
+func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, jobManager domain.JobManager) (*mux.Router, error) {
 	defaultLog.Trace("router/router:InitRoutes() Entering")
 	defer defaultLog.Trace("router/router:InitRoutes() Leaving")
 
@@ -43,19 +45,21 @@ func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *p
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
+	router.Use(metrics.NewRequestMetrics(constants.ServiceName))
+	router.Use(tracing.NewMiddleware(constants.ServiceName))
 
-	err := defineSubRoutes(router, constants.OldServiceName, cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	err := defineSubRoutes(router, constants.OldServiceName, cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, jobManager)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
 	}
-	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, jobManager)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
 	}
 	return router, nil
 }
 
-func defineSubRoutes(router *mux.Router, service string, cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) error {
+func defineSubRoutes(router *mux.Router, service string, cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, jobManager domain.JobManager) error {
 	defaultLog.Trace("router/router:defineSubRoutes() Entering")
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
@@ -73,17 +77,21 @@ func defineSubRoutes(router *mux.Router, service string, cfg *config.Configurati
 	subRouter.Use(cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
 		constants.TrustedRootCACertsDir, cfgRouter.fnGetJwtCerts,
 		cacheTime))
-	subRouter = SetFlavorGroupRoutes(subRouter, dataStore, fgs, hostTrustManager)
+	subRouter = SetFlavorGroupRoutes(subRouter, dataStore, fgs, hostTrustManager, jobManager)
 	subRouter = SetFlavorTemplateRoutes(subRouter, dataStore, fgs)
-	subRouter = SetFlavorRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	subRouter = SetFlavorRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, jobManager)
 	subRouter = SetTpmEndorsementRoutes(subRouter, dataStore)
 	subRouter = SetCertifyAiksRoutes(subRouter, dataStore, certStore, cfg.AikCertValidity, cfg.EnableEkCertRevokeChecks)
 	subRouter = SetHostStatusRoutes(subRouter, dataStore)
+	subRouter = SetHostFetchQueueRoutes(subRouter, dataStore)
+	subRouter = SetSubscriptionRoutes(subRouter, dataStore)
+	subRouter = SetJobRoutes(subRouter, dataStore, jobManager)
+	subRouter = SetMetricsRoutes(subRouter)
 	subRouter = SetCertifyHostKeysRoutes(subRouter, certStore)
-	subRouter = SetHostRoutes(subRouter, dataStore, hostTrustManager, hostControllerConfig)
-	subRouter = SetReportRoutes(subRouter, dataStore, hostTrustManager)
+	subRouter = SetHostRoutes(subRouter, dataStore, certStore, hostTrustManager, hostControllerConfig, jobManager)
+	subRouter = SetReportRoutes(subRouter, dataStore, hostTrustManager, jobManager)
 	subRouter = SetCreateCaCertificatesRoutes(subRouter, certStore)
-	subRouter = SetTagCertificateRoutes(subRouter, cfg, fgs, certStore, hostTrustManager, dataStore)
+	subRouter = SetTagCertificateRoutes(subRouter, cfg, fgs, certStore, hostTrustManager, dataStore, jobManager)
 	subRouter = SetESXiClusterRoutes(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetDeploySoftwareManifestRoute(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetManifestsRoute(subRouter, dataStore)
@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/validation"
+)
+
+// SetSubscriptionRoutes registers routes for the host event subscription APIs
+func SetSubscriptionRoutes(router *mux.Router, store *postgres.DataStore) *mux.Router {
+	defaultLog.Trace("router/subscriptions:SetSubscriptionRoutes() Entering")
+	defer defaultLog.Trace("router/subscriptions:SetSubscriptionRoutes() Leaving")
+
+	subscriptionStore := postgres.NewSubscriptionStore(store)
+	subscriptionController := controllers.SubscriptionController{Store: subscriptionStore}
+
+	subscriptionIdExpr := fmt.Sprintf("%s%s", "/subscriptions/", validation.IdReg)
+	router.Handle("/subscriptions",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(subscriptionController.Create),
+			[]string{constants.SubscriptionCreate}))).Methods("POST")
+
+	router.Handle("/subscriptions",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(subscriptionController.Search),
+			[]string{constants.SubscriptionSearch}))).Methods("GET")
+
+	router.Handle(subscriptionIdExpr,
+		ErrorHandler(permissionsHandler(JsonResponseHandler(subscriptionController.Retrieve),
+			[]string{constants.SubscriptionRetrieve}))).Methods("GET")
+
+	router.Handle(subscriptionIdExpr,
+		ErrorHandler(permissionsHandler(ResponseHandler(subscriptionController.Delete),
+			[]string{constants.SubscriptionDelete}))).Methods("DELETE")
+
+	return router
+}
@@ -25,7 +25,7 @@ const (
 )
 
 // SetTagCertificateRoutes registers routes for tag-certificates API
-func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, store *postgres.DataStore) *mux.Router {
+func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flavorGroupStore *postgres.FlavorGroupStore, certStore *models.CertificatesStore, hostTrustManager domain.HostTrustManager, store *postgres.DataStore, jobManager domain.JobManager) *mux.Router {
 	defaultLog.Trace("router/tag_certificates:SetTagCertificateRoutes() Entering")
 	defer defaultLog.Trace("router/tag_certificates:SetTagCertificateRoutes() Leaving")
 
@@ -53,6 +53,7 @@ func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flav
 	tagCertificateController := controllers.NewTagCertificateController(tcConfig, *certStore, tagCertificateStore, hostTrustManager, hostStore,
 		flavorStore, flavorGroupStore, hcp)
 	if tagCertificateController != nil {
+		tagCertificateController.JobManager = jobManager
 		tagCertificateIdExpr := fmt.Sprintf("%s%s", TagCertificateEndpointPath+"/", validation.IdReg)
 		router.Handle(TagCertificateEndpointPath,
 			ErrorHandler(permissionsHandler(JsonResponseHandler(tagCertificateController.Create),
@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -23,11 +24,14 @@ import (
 
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/config"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	hvsMetrics "github.com/intel-secl/intel-secl/v4/pkg/hvs/metrics"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/auditlog"
 	hostfetcher "github.com/intel-secl/intel-secl/v4/pkg/hvs/services/host-fetcher"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hrrs"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/jobs"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/notifier"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	hostconnector "github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/saml"
@@ -43,6 +47,7 @@ import (
 
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 )
 
 var defaultLog = commLog.GetDefaultLogger()
@@ -61,6 +66,12 @@ func (a *App) startServer() error {
 		return err
 	}
 
+	// Initialize tracing, spans are only exported when an exporter is configured
+	shutdownTracing, err := tracing.Init(constants.ServiceName, c.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing tracing")
+	}
+
 	// Initialize Database
 	dataStore, err := postgres.InitDatabase(&c.DB)
 	if err != nil {
@@ -74,14 +85,23 @@ func (a *App) startServer() error {
 	// Load Certificates
 	certStore := utils.LoadCertificates(a.loadCertPathStore())
 
+	// Initialize the notifier of the host event subscriptions
+	eventNotifier, err := initEventNotifier(c, dataStore, certStore)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing event notifier")
+	}
+
 	// Initialize Host trust manager
 	fgs := postgres.NewFlavorGroupStore(dataStore)
-	hostTrustManager := initHostTrustManager(c, dataStore, fgs, certStore, alw)
+	hostTrustManager := initHostTrustManager(c, dataStore, fgs, certStore, alw, eventNotifier)
 	go hostTrustManager.ProcessQueue()
 
 	// create an instance of the HRRS and start it...
 	reportStore := postgres.NewReportStore(dataStore)
 	reportStore.AuditLogWriter = alw
+	if err = hvsMetrics.RegisterHostTrustCollector(reportStore); err != nil {
+		return errors.Wrap(err, "An error occurred while registering host trust metrics")
+	}
 	reportRefresher, err := hrrs.NewHostReportRefresher(c.HRRS, reportStore, hostTrustManager)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing HRRS")
@@ -106,8 +126,19 @@ func (a *App) startServer() error {
 		return errors.Wrap(err, "An error occurred while initializing vCenter Cluster Syncer")
 	}
 
+	// Initialize the job manager, the jobs of this instance interrupted by a previous shutdown are marked as failed.
+	// The instance is identified by its host name, which is stable across restarts.
+	instanceName, err := os.Hostname()
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while retrieving the host name of the instance")
+	}
+	jobManager, err := jobs.NewManager(postgres.NewJobStore(dataStore), instanceName)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing job manager")
+	}
+
 	// Initialize routes
-	routes, err := router.InitRoutes(c, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	routes, err := router.InitRoutes(c, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, jobManager)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing routes")
 	}
@@ -155,14 +186,24 @@ func (a *App) startServer() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// stop accepting requests first so that no job or verification is submitted to the services being stopped
+	shutdownErr := h.Shutdown(ctx)
+	if shutdownErr != nil {
+		defaultLog.WithError(shutdownErr).Info("Failed to gracefully shutdown webserver")
+	}
+
 	err = reportRefresher.Stop()
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while stopping Report Refresher")
 	}
+	eventNotifier.Stop()
+	jobManager.Stop()
 
-	if err := h.Shutdown(ctx); err != nil {
-		defaultLog.WithError(err).Info("Failed to gracefully shutdown webserver")
-		return err
+	if shutdownErr != nil {
+		return shutdownErr
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		defaultLog.WithError(err).Warn("Failed to export the pending spans")
 	}
 	secLog.Info(commLogMsg.ServiceStop)
 	return nil
@@ -196,7 +237,33 @@ func getDecodedDek(cfg *config.Configuration) []byte {
 	return dek
 }
 
-func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, alw domain.AuditLogWriter) domain.HostTrustManager {
+func initEventNotifier(cfg *config.Configuration, dataStore *postgres.DataStore, certStore *models.CertificatesStore) (*notifier.Service, error) {
+	defaultLog.Trace("server:initEventNotifier() Entering")
+	defer defaultLog.Trace("server:initEventNotifier() Leaving")
+
+	// webhooks are usually served with certificates of public CAs, the HVS root CAs are trusted as well
+	certPool, _ := x509.SystemCertPool()
+	if certPool == nil {
+		certPool = x509.NewCertPool()
+	}
+	rootCAs := (*certStore)[models.CaCertTypesRootCa.String()]
+	for i := range rootCAs.Certificates {
+		certPool.AddCert(&rootCAs.Certificates[i])
+	}
+
+	return notifier.NewService(notifier.Config{
+		SubscriptionStore: postgres.NewSubscriptionStore(dataStore),
+		HostStore:         postgres.NewHostStore(dataStore),
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    certPool,
+		},
+		NatsServers:     cfg.NATS.Servers,
+		NatsCredentials: constants.NatsCredentials,
+	})
+}
+
+func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataStore, fgs *postgres.FlavorGroupStore, certStore *models.CertificatesStore, alw domain.AuditLogWriter, eventPublisher domain.HostEventPublisher) domain.HostTrustManager {
 	defaultLog.Trace("server:InitHostTrustManager() Entering")
 	defer defaultLog.Trace("server:InitHostTrustManager() Leaving")
 
@@ -251,6 +318,7 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 		SamlIssuerConfig:                samlIssuerConfig,
 		SkipFlavorSignatureVerification: cfg.FVS.SkipFlavorSignatureVerification,
 		HostTrustCache:                  hostQuoteTrustCache,
+		EventPublisher:                  eventPublisher,
 	}
 
 	// Initialize Host Fetcher service
@@ -263,12 +331,17 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 			ServiceUsername: cfg.HVS.Username,
 			ServicePassword: cfg.HVS.Password,
 		},
-		RetryTimeMinutes: 5,
-		HostStatusStore:  hss,
-		HostStore:        hs,
-		FlavorGroupStore: fgs,
-		FlavorStore:      fs,
-		HostTrustCache:   hostQuoteTrustCache,
+		RetryBackoffBase:        cfg.FVS.RetryBackoffBase,
+		RetryBackoffMax:         cfg.FVS.RetryBackoffMax,
+		CircuitBreakerThreshold: cfg.FVS.CircuitBreakerThreshold,
+		CircuitBreakerCooldown:  cfg.FVS.CircuitBreakerCooldown,
+		HostStatusStore:         hss,
+		HostStore:               hs,
+		FlavorGroupStore:        fgs,
+		FlavorStore:             fs,
+		HostFetchQueueStore:     postgres.NewHostFetchQueueStore(dataStore),
+		HostTrustCache:          hostQuoteTrustCache,
+		EventPublisher:          eventPublisher,
 	}
 	_, hf, err := hostfetcher.NewService(c, cfg.FVS.NumberOfDataFetchers)
 	if err != nil {
@@ -321,5 +394,13 @@ func (a *App) loadCertPathStore() *models.CertificatesPathStore {
 			KeyFile:  constants.FlavorSigningKeyFile,
 			CertPath: constants.FlavorSigningCertFile,
 		},
+		models.CaCertTypesFlavorCa.String(): models.CertLocation{
+			KeyFile:  "",
+			CertPath: constants.TrustedFlavorCACertsDir,
+		},
+		models.CertTypesImportedFlavorSigning.String(): models.CertLocation{
+			KeyFile:  "",
+			CertPath: constants.ImportedFlavorSigningCertsDir,
+		},
 	}
 }
@@ -9,9 +9,12 @@ import (
 	"context"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models/taskstage"
+	commErr "github.com/intel-secl/intel-secl/v4/pkg/lib/common/err"
 	"golang.org/x/sync/syncmap"
+	"math/rand"
 	"reflect"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,15 +25,23 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/metrics"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/chnlworkq"
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 	hc "github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/pkg/errors"
 )
 
 const (
-	defaultRetryIntervalMins = 5
+	defaultRetryBackoffBase        = 30 * time.Second
+	defaultRetryBackoffMax         = 5 * time.Minute
+	defaultCircuitBreakerThreshold = 5
+	defaultCircuitBreakerCooldown  = 30 * time.Minute
+
+	// retryPollInterval is how often the fetch queue is checked for hosts that are due for a retry
+	retryPollInterval = 5 * time.Second
 )
 
 var defaultLog = commLog.GetDefaultLogger()
@@ -54,9 +65,6 @@ type Service struct {
 	// work items (their id) is pulled out of a queue and fed to the workers
 	workChan chan interface{}
 
-	retryRqstChan chan interface{}
-	retryWorkChan chan interface{}
-
 	// map that holds all hosts that need to be fetched.
 	// The reason this is a map is that redundant requests can come in
 	// that could theoretically be consolidated
@@ -64,16 +72,29 @@ type Service struct {
 	// waitgroup used to wait for workers to finish up when signal for shutdown comes in
 	wg sync.WaitGroup
 
-	quit              chan struct{}
-	serviceDone       bool
-	retryIntervalMins int
-	hcCfg             domain.HostConnectionConfig
-	hcf               hc.HostConnectorProvider
-	hss               domain.HostStatusStore
-	hs                domain.HostStore
-	fgs               domain.FlavorGroupStore
-	fs                domain.FlavorStore
-	hostTrustCache    *lru.Cache
+	// hosts that are being fetched or that are queued for a retry. A host is only fetched by a single worker at a
+	// time, the requests that come in meanwhile are served by the ongoing fetch, by its retry or by the fetch that is
+	// queued once it is done
+	inProgress syncmap.Map
+
+	quit           chan struct{}
+	serviceDone    bool
+	hcCfg          domain.HostConnectionConfig
+	hcf            hc.HostConnectorProvider
+	hss            domain.HostStatusStore
+	hs             domain.HostStore
+	fgs            domain.FlavorGroupStore
+	fs             domain.FlavorStore
+	hfqs           domain.HostFetchQueueStore
+	eventPublisher domain.HostEventPublisher
+	hostTrustCache *lru.Cache
+
+	retryBackoffBase        time.Duration
+	retryBackoffMax         time.Duration
+	circuitBreakerThreshold int
+	circuitBreakerCooldown  time.Duration
+	jitter                  *rand.Rand
+	jitterMtx               sync.Mutex
 }
 
 func NewService(cfg domain.HostDataFetcherConfig, workers int) (*Service, domain.HostDataFetcher, error) {
@@ -83,15 +104,21 @@ func NewService(cfg domain.HostDataFetcherConfig, workers int) (*Service, domain
 	// setting size of channel to the same as number of workers.
 	// this way, go routine can start work as soon as a current work is done
 	svc := &Service{workMap: syncmap.Map{},
-		quit:              make(chan struct{}),
-		hcf:               cfg.HostConnectorProvider,
-		retryIntervalMins: cfg.RetryTimeMinutes,
-		hss:               cfg.HostStatusStore,
-		hcCfg:             cfg.HostConnectionConfig,
-		hs:                cfg.HostStore,
-		fgs:               cfg.FlavorGroupStore,
-		fs:                cfg.FlavorStore,
-		hostTrustCache:    cfg.HostTrustCache,
+		quit:                    make(chan struct{}),
+		hcf:                     cfg.HostConnectorProvider,
+		hss:                     cfg.HostStatusStore,
+		hcCfg:                   cfg.HostConnectionConfig,
+		hs:                      cfg.HostStore,
+		fgs:                     cfg.FlavorGroupStore,
+		fs:                      cfg.FlavorStore,
+		hfqs:                    cfg.HostFetchQueueStore,
+		eventPublisher:          cfg.EventPublisher,
+		hostTrustCache:          cfg.HostTrustCache,
+		retryBackoffBase:        cfg.RetryBackoffBase,
+		retryBackoffMax:         cfg.RetryBackoffMax,
+		circuitBreakerThreshold: cfg.CircuitBreakerThreshold,
+		circuitBreakerCooldown:  cfg.CircuitBreakerCooldown,
+		jitter:                  rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 	if svc.hss == nil {
 		return nil, nil, errors.New("host status store cannot be empty")
@@ -99,22 +126,35 @@ func NewService(cfg domain.HostDataFetcherConfig, workers int) (*Service, domain
 	if svc.hs == nil {
 		return nil, nil, errors.New("host store cannot be empty")
 	}
-	if svc.retryIntervalMins == 0 {
-		svc.retryIntervalMins = defaultRetryIntervalMins
+	if svc.hfqs == nil {
+		return nil, nil, errors.New("host fetch queue store cannot be empty")
+	}
+	if svc.retryBackoffBase <= 0 {
+		svc.retryBackoffBase = defaultRetryBackoffBase
+	}
+	if svc.retryBackoffMax < svc.retryBackoffBase {
+		svc.retryBackoffMax = defaultRetryBackoffMax
+		if svc.retryBackoffMax < svc.retryBackoffBase {
+			svc.retryBackoffMax = svc.retryBackoffBase
+		}
+	}
+	if svc.circuitBreakerThreshold <= 0 {
+		svc.circuitBreakerThreshold = defaultCircuitBreakerThreshold
+	}
+	if svc.circuitBreakerCooldown <= 0 {
+		svc.circuitBreakerCooldown = defaultCircuitBreakerCooldown
 	}
 
 	svc.Fetcher = svc
 	var err error
-	if svc.rqstChan, svc.workChan, err = chnlworkq.New(workers, workers, svc.addWorkToMap, nil, svc.quit, &svc.wg); err != nil {
+	if svc.rqstChan, svc.workChan, err = chnlworkq.NewObserved(workers, workers, svc.addWorkToMap, nil,
+		metrics.QueueDepthObserver(metrics.QueueHostFetcher), svc.quit, &svc.wg); err != nil {
 		return nil, nil, errors.New("hostfetcher:NewService:error starting work queue")
 	}
-	if svc.retryRqstChan, svc.retryWorkChan, err = chnlworkq.New(workers, workers, nil, nil, svc.quit, &svc.wg); err != nil {
-		return nil, nil, errors.New("hostfetcher:NewService:error starting retry queue")
-	}
 
 	// start workers.. individual workers are spawned as go routines
 	svc.startWorkers(workers)
-	svc.startRetryChannelProcessor(cfg.RetryTimeMinutes)
+	svc.startRetryScheduler()
 	return svc, svc.Fetcher, nil
 }
 
@@ -131,11 +171,12 @@ func (svc *Service) Shutdown() error {
 	return nil
 }
 
-func (svc *Service) startRetryChannelProcessor(retryMins int) {
-	defaultLog.Trace("hostfetcher/Service:startRetryChannelProcessor() Entering")
-	defer defaultLog.Trace("hostfetcher/Service:startRetryChannelProcessor() Leaving")
+// startRetryScheduler periodically queues the hosts whose retry is due. The retry state is kept in the host fetch
+// queue store, so the backoff of the hosts that could not be reached survives a restart of the service.
+func (svc *Service) startRetryScheduler() {
+	defaultLog.Trace("hostfetcher/Service:startRetryScheduler() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:startRetryScheduler() Leaving")
 
-	// start worker go routines
 	svc.wg.Add(1)
 	go func() {
 		defer func() {
@@ -145,23 +186,47 @@ func (svc *Service) startRetryChannelProcessor(retryMins int) {
 			}
 			svc.wg.Done()
 		}()
+		ticker := time.NewTicker(retryPollInterval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-svc.quit:
 				return
-			case r := <-svc.retryWorkChan:
-				retry := r.(retryRequest)
-				select {
-				case <-svc.quit:
-					return
-				case <-time.After(retry.retryTime.Sub(time.Now())):
-					svc.workChan <- retry.hostId
-				}
+			case <-ticker.C:
+				svc.queueDueRetries(time.Now())
 			}
 		}
 	}()
 }
 
+// queueDueRetries queues a retry for the hosts whose next attempt is due and that still have requests waiting for
+// their data. After a restart, the requests are submitted again by the host trust manager from its own queue.
+func (svc *Service) queueDueRetries(now time.Time) {
+	defaultLog.Trace("hostfetcher/Service:queueDueRetries() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:queueDueRetries() Leaving")
+
+	for _, state := range []hvs.HostFetchState{hvs.HostFetchStateRetrying, hvs.HostFetchStateCircuitOpen} {
+		entries, err := svc.hfqs.Search(&models.HostFetchQueueFilterCriteria{State: state, DueBefore: now})
+		if err != nil {
+			defaultLog.WithError(err).Error("hostfetcher/Service:queueDueRetries() Failed to search host fetch queue")
+			return
+		}
+		for _, entry := range entries {
+			if _, ok := svc.workMap.Load(entry.HostId); !ok {
+				continue
+			}
+			if _, busy := svc.inProgress.LoadOrStore(entry.HostId, true); busy {
+				continue
+			}
+			select {
+			case <-svc.quit:
+				return
+			case svc.rqstChan <- retryRequest{retryTime: entry.NextAttempt, hostId: entry.HostId}:
+			}
+		}
+	}
+}
+
 func (svc *Service) startWorkers(workers int) {
 	defaultLog.Trace("hostfetcher/Service:startWorkers() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:startWorkers() Leaving")
@@ -193,7 +258,7 @@ func (svc *Service) addWorkToMap(wrk interface{}) interface{} {
 		return v.host.Id
 
 	case retryRequest:
-		return v.hostId
+		return v
 	default:
 		defaultLog.Error("unexpected type in request channel")
 		return nil
@@ -224,12 +289,21 @@ func (svc *Service) doWork() {
 			// we have received a quit. Don't process anymore items - just return
 			return
 		case id := <-svc.workChan:
-			hId, ok := id.(uuid.UUID)
-			defaultLog.Debugf("hostfetcher/fetcher:doWork() host - %s", hId.String())
-			var connUrl string
-			if !ok {
+			var hId uuid.UUID
+			// retries have already been marked as in progress when they were queued
+			isRetry := false
+			switch v := id.(type) {
+			case uuid.UUID:
+				hId = v
+			case retryRequest:
+				hId = v.hostId
+				isRetry = true
+			default:
 				defaultLog.Error("hostfetcher:doWork:expecting uuid from channel - but got different type")
+				continue
 			}
+			defaultLog.Debugf("hostfetcher/fetcher:doWork() host - %s", hId.String())
+			var connUrl string
 			// iterate through work requests for this host. Usually, there will only be a single element in the
 			// work list.
 			var preferHashMatch bool
@@ -253,21 +327,57 @@ func (svc *Service) doWork() {
 				svc.workMap.Store(hId, frs)
 			}
 
-			if getData {
-				svc.FetchDataAndRespond(hId, connUrl, preferHashMatch)
-			} else {
+			if !getData {
 				defaultLog.Info("Fetch data for ", hId, "cancelled")
+				if isRetry {
+					svc.inProgress.Delete(hId)
+				}
+				continue
+			}
+			if !isRetry && !svc.startFetch(hId) {
+				continue
 			}
+			svc.FetchDataAndRespond(hId, connUrl, preferHashMatch)
+			svc.inProgress.Delete(hId)
+			svc.requeueWaitingRequests(hId)
 		}
 	}
 }
 
-func (svc *Service) Retrieve(host hvs.Host) (*hvs.HostManifest, error) {
+// requeueWaitingRequests queues another fetch of the host when requests came in while it was being fetched. The
+// fetch only serves the requests it took from the work map when it got the host data, the requests that came in
+// afterwards were dropped by startFetch and are left waiting in the work map.
+func (svc *Service) requeueWaitingRequests(hId uuid.UUID) {
+	defaultLog.Trace("hostfetcher/Service:requeueWaitingRequests() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:requeueWaitingRequests() Leaving")
+
+	workEntry, ok := svc.workMap.Load(hId)
+	if !ok || len(workEntry.([]*fetchRequest)) == 0 {
+		return
+	}
+	// the requests of a host that is backing off are served by the retry scheduler
+	if entry, err := svc.hfqs.Retrieve(hId); err == nil && entry.NextAttempt.After(time.Now()) {
+		return
+	}
+	if _, busy := svc.inProgress.LoadOrStore(hId, true); busy {
+		return
+	}
+	// the request is queued from another go routine, a worker must not block on the request channel
+	go func() {
+		select {
+		case <-svc.quit:
+			svc.inProgress.Delete(hId)
+		case svc.rqstChan <- retryRequest{retryTime: time.Now(), hostId: hId}:
+		}
+	}()
+}
+
+func (svc *Service) Retrieve(ctx context.Context, host hvs.Host) (*hvs.HostManifest, error) {
 	defaultLog.Trace("hostfetcher/Service:Retrieve() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:Retrieve() Leaving")
 
 	trustPcrList := svc.getTrustPcrListFromCache(host.Id)
-	hostData, err := svc.GetHostData(host.ConnectionString, trustPcrList)
+	hostData, err := svc.GetHostData(ctx, host.ConnectionString, trustPcrList)
 	hostStatus := &hvs.HostStatus{
 		HostID:                host.Id,
 		HostStatusInformation: hvs.HostStatusInformation{},
@@ -300,6 +410,9 @@ func (svc *Service) RetrieveAsync(ctx context.Context, host hvs.Host, preferHash
 	if svc.serviceDone {
 		return errors.New("Host Fetcher has been shut down - cannot accept any more requests")
 	}
+	if err := svc.queuePendingFetch(host.Id, preferHashMatch); err != nil {
+		return errors.Wrap(err, "Could not add host to the host fetch queue")
+	}
 	fr := &fetchRequest{ctx, host, rcvrs, preferHashMatch}
 	// queue up the request
 	svc.rqstChan <- fr
@@ -313,11 +426,14 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 	defaultLog.Debugf("hostfetcher/fetcher:FetchDataAndRespond()  start for host - %s", hId.String())
 
 	trustPcrList := svc.getTrustPcrListFromCache(hId)
-	hostData, err := svc.GetHostData(connUrl, trustPcrList)
+	fetchStart := time.Now()
+	hostData, err := svc.GetHostData(context.Background(), connUrl, trustPcrList)
+	metrics.ObserveHostFetch(fetchStart, err)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("hostfetcher/Service:FetchDataAndRespond() Failed to get data for host %s", hId.String())
 		// we have an error. Make sure that the host still exists.
 		if hosts, err := svc.hs.Search(&models.HostFilterCriteria{Id: hId}, nil); err == nil && len(hosts) == 0 {
+			svc.removeFromFetchQueue(hId)
 			workEntry, _ := svc.workMap.Load(hId)
 			frs := workEntry.([]*fetchRequest)
 			svc.workMap.Delete(hId)
@@ -338,10 +454,7 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 			return
 		}
 		//TODO - presume that error is due to connection failure and we need to retry operation
-		svc.retryRqstChan <- retryRequest{
-			retryTime: time.Now().Add(time.Duration(svc.retryIntervalMins) * time.Minute),
-			hostId:    hId,
-		}
+		svc.scheduleRetry(hId, preferHashMatch, err)
 		hostState := utils.DetermineHostState(err)
 		defaultLog.Warnf("hostfetcher/Service:FetchDataAndRespond() Could not connect to host : %s", hostState.String())
 
@@ -354,6 +467,13 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 		if err != nil {
 			defaultLog.WithError(err).Errorf("could not persist host status for host %s", hId.String())
 		}
+		if svc.eventPublisher != nil {
+			svc.eventPublisher.Publish(hvs.HostEvent{
+				Type:      hvs.HostEventConnectionFailure,
+				HostId:    hId,
+				HostState: hostState.String(),
+			})
+		}
 		return
 	}
 
@@ -365,6 +485,7 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 		frs = workEntry.([]*fetchRequest)
 	}
 	svc.workMap.Delete(hId)
+	svc.removeFromFetchQueue(hId)
 	svc.updateMissingHostDetails(hId, hostData)
 	err = svc.hss.Persist(&hvs.HostStatus{
 		HostID: hId,
@@ -395,6 +516,98 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 
 }
 
+// queuePendingFetch records the fetch request of a host in the fetch queue store. A host that is already queued
+// keeps its retry state, so that new requests do not bypass the backoff or the circuit breaker.
+func (svc *Service) queuePendingFetch(hId uuid.UUID, preferHashMatch bool) error {
+	defaultLog.Trace("hostfetcher/Service:queuePendingFetch() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:queuePendingFetch() Leaving")
+
+	_, err := svc.hfqs.Retrieve(hId)
+	if err == nil {
+		return nil
+	}
+	if !strings.Contains(err.Error(), commErr.RowsNotFound) {
+		return err
+	}
+	return svc.hfqs.Persist(&hvs.HostFetchQueueEntry{
+		HostId:          hId,
+		State:           hvs.HostFetchStatePending,
+		PreferHashMatch: preferHashMatch,
+		NextAttempt:     time.Now(),
+	})
+}
+
+// startFetch marks the host as in progress, unless it is already being fetched or its next attempt is not due yet.
+// The requests of a host that is backing off are served by the retry scheduler.
+func (svc *Service) startFetch(hId uuid.UUID) bool {
+	defaultLog.Trace("hostfetcher/Service:startFetch() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:startFetch() Leaving")
+
+	if _, busy := svc.inProgress.LoadOrStore(hId, true); busy {
+		defaultLog.Debugf("hostfetcher/Service:startFetch() Host %s is already being fetched", hId.String())
+		return false
+	}
+	entry, err := svc.hfqs.Retrieve(hId)
+	if err == nil && entry.NextAttempt.After(time.Now()) {
+		defaultLog.Debugf("hostfetcher/Service:startFetch() Fetch for host %s is deferred until %s", hId.String(), entry.NextAttempt)
+		svc.inProgress.Delete(hId)
+		return false
+	}
+	return true
+}
+
+// scheduleRetry records a failed fetch of the host. The retry is delayed with an exponential backoff and jitter, and
+// after CircuitBreakerThreshold consecutive failures the circuit of the host opens: it is not contacted again before
+// the cool down elapses, when a single attempt decides whether the circuit closes.
+func (svc *Service) scheduleRetry(hId uuid.UUID, preferHashMatch bool, fetchErr error) {
+	defaultLog.Trace("hostfetcher/Service:scheduleRetry() Entering")
+	defer defaultLog.Trace("hostfetcher/Service:scheduleRetry() Leaving")
+
+	entry, err := svc.hfqs.Retrieve(hId)
+	if err != nil {
+		entry = &hvs.HostFetchQueueEntry{HostId: hId}
+	}
+	entry.Attempts++
+	entry.PreferHashMatch = preferHashMatch
+	entry.LastError = fetchErr.Error()
+	if entry.Attempts >= svc.circuitBreakerThreshold {
+		entry.State = hvs.HostFetchStateCircuitOpen
+		entry.NextAttempt = time.Now().Add(svc.circuitBreakerCooldown)
+		defaultLog.Warnf("hostfetcher/Service:scheduleRetry() Host %s failed %d consecutive fetches, next attempt at %s",
+			hId.String(), entry.Attempts, entry.NextAttempt)
+	} else {
+		entry.State = hvs.HostFetchStateRetrying
+		entry.NextAttempt = time.Now().Add(svc.retryBackoff(entry.Attempts))
+	}
+
+	if err := svc.hfqs.Persist(entry); err != nil {
+		defaultLog.WithError(err).Errorf("hostfetcher/Service:scheduleRetry() Could not persist retry of host %s", hId.String())
+	}
+}
+
+// retryBackoff returns the delay before the retry that follows the given number of consecutive failures. The delay
+// doubles with each failure up to RetryBackoffMax, and a random jitter of up to half the delay is subtracted so that
+// the hosts that failed together are not retried together.
+func (svc *Service) retryBackoff(attempts int) time.Duration {
+	backoff := svc.retryBackoffBase
+	for i := 1; i < attempts && backoff < svc.retryBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > svc.retryBackoffMax {
+		backoff = svc.retryBackoffMax
+	}
+
+	svc.jitterMtx.Lock()
+	defer svc.jitterMtx.Unlock()
+	return backoff - time.Duration(svc.jitter.Int63n(int64(backoff/2)+1))
+}
+
+func (svc *Service) removeFromFetchQueue(hId uuid.UUID) {
+	if err := svc.hfqs.Delete(hId); err != nil {
+		defaultLog.WithError(err).Errorf("hostfetcher/Service:removeFromFetchQueue() Could not remove host %s from fetch queue", hId.String())
+	}
+}
+
 func (svc *Service) getTrustPcrListFromCache(hId uuid.UUID) []int {
 	defaultLog.Trace("hostfetcher/Service:getTrustPcrListFromCache() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:getTrustPcrListFromCache() Leaving")
@@ -412,9 +625,11 @@ func (svc *Service) getTrustPcrListFromCache(hId uuid.UUID) []int {
 	return trustPcrList
 }
 
-func (svc *Service) GetHostData(connUrl string, pcrList []int) (*hvs.HostManifest, error) {
+func (svc *Service) GetHostData(ctx context.Context, connUrl string, pcrList []int) (_ *hvs.HostManifest, err error) {
 	defaultLog.Trace("hostfetcher/Service:GetHostData() Entering")
 	defer defaultLog.Trace("hostfetcher/Service:GetHostData() Leaving")
+	ctx, span := tracing.StartSpan(ctx, "hostfetcher.GetHostData")
+	defer func() { tracing.EndSpan(span, err) }()
 
 	defaultLog.Debugf("hostfetcher/fetcher:GetHostData()  start for conn url - %s", connUrl)
 
@@ -427,7 +642,7 @@ func (svc *Service) GetHostData(connUrl string, pcrList []int) (*hvs.HostManifes
 		return nil, err
 	}
 
-	connector, err := svc.hcf.NewHostConnector(connectionString)
+	connector, err := svc.hcf.NewHostConnectorWithContext(ctx, connectionString)
 	if err != nil {
 		return nil, err
 	}
@@ -10,15 +10,19 @@ import (
 	"runtime/debug"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models/taskstage"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/metrics"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/chnlworkq"
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/syncmap"
 )
 
@@ -81,10 +85,10 @@ func NewService(cfg domain.HostTrustMgrConfig) (*Service, domain.HostTrustManage
 	}
 	var err error
 	nw := cfg.Verifiers
-	if svc.rqstChan, svc.workChan, err = chnlworkq.New(nw, nw, nil, nil, svc.quit, &svc.wg); err != nil {
+	if svc.rqstChan, svc.workChan, err = chnlworkq.NewObserved(nw, nw, nil, nil, metrics.QueueDepthObserver(metrics.QueueHostTrustVerify), svc.quit, &svc.wg); err != nil {
 		return nil, nil, errors.New("hosttrust:NewService:Error starting work queue")
 	}
-	if svc.hfRqstChan, svc.hfWorkChan, err = chnlworkq.New(nw, nw, nil, nil, svc.quit, &svc.wg); err != nil {
+	if svc.hfRqstChan, svc.hfWorkChan, err = chnlworkq.NewObserved(nw, nw, nil, nil, metrics.QueueDepthObserver(metrics.QueueHostTrustFetch), svc.quit, &svc.wg); err != nil {
 		return nil, nil, errors.New("hosttrust:NewService:Error starting work queue")
 	}
 
@@ -117,17 +121,21 @@ func (svc *Service) startWorkers(workers int) {
 	}
 }
 
-func (svc *Service) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
+func (svc *Service) VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (report *models.HVSReport, err error) {
+	ctx, span := tracing.StartSpan(ctx, "hosttrust.VerifyHost", attribute.String("host.id", hostId.String()),
+		attribute.Bool("host.fetch_data", fetchHostData))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	var hostData *hvs.HostManifest
 
 	if fetchHostData {
 		var host *hvs.Host
-		host, err := svc.hostStore.Retrieve(hostId, nil)
+		host, err = svc.hostStore.Retrieve(hostId, nil)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not retrieve host id "+hostId.String())
 		}
 
-		hostData, err = svc.hdFetcher.Retrieve(hvs.Host{
+		hostData, err = svc.hdFetcher.Retrieve(ctx, hvs.Host{
 			Id:               host.Id,
 			ConnectionString: host.ConnectionString})
 	} else {
@@ -141,8 +149,21 @@ func (svc *Service) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashM
 
 		hostData = &hostStatusCollection[0].HostManifest
 	}
+	// the report is not created once the caller gave up on the verification
+	if err = ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "verification cancelled for host id "+hostId.String())
+	}
 	newData := fetchHostData
-	return svc.verifier.Verify(hostId, hostData, newData, preferHashMatch)
+	return svc.verifier.Verify(ctx, hostId, hostData, newData, preferHashMatch)
+}
+
+// SimulateHostTrust predicts the trust of a host against a flavorgroup with candidate flavors and match policies
+// without persisting anything
+func (svc *Service) SimulateHostTrust(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/manager:SimulateHostTrust() Entering")
+	defer defaultLog.Trace("hosttrust/manager:SimulateHostTrust() Leaving")
+
+	return svc.verifier.Simulate(hostId, hostData, flavorGroup, candidateFlavors)
 }
 
 func (svc *Service) ProcessQueue() error {
@@ -262,6 +283,55 @@ func (svc *Service) VerifyHostsAsync(hostIds []uuid.UUID, fetchHostData, preferH
 	return nil
 }
 
+func (svc *Service) VerifyHostDataAsync(host hvs.Host, hostData *hvs.HostManifest, preferHashMatch bool) error {
+	defaultLog.Trace("hosttrust/manager:VerifyHostDataAsync() Entering")
+	defer defaultLog.Trace("hosttrust/manager:VerifyHostDataAsync() Leaving")
+
+	if hostData == nil {
+		return errors.New("hosttrust/manager:VerifyHostDataAsync() Host data must be provided")
+	}
+
+	// the host status is persisted first so that the queue record, which does not require new host data, can be
+	// processed from the store after a restart
+	err := svc.hostStatusStore.Persist(&hvs.HostStatus{
+		HostID: host.Id,
+		HostStatusInformation: hvs.HostStatusInformation{
+			HostState:         hvs.HostStateConnected,
+			LastTimeConnected: time.Now(),
+		},
+		HostManifest: *hostData,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "hosttrust/manager:VerifyHostDataAsync() Could not persist host status for host %s", host.Id.String())
+	}
+
+	svc.syncMtx.Lock()
+	if svc.serviceDone {
+		svc.syncMtx.Unlock()
+		return errors.New("hosttrust/manager:VerifyHostDataAsync() Service already shutdown")
+	}
+
+	adds := map[uuid.UUID]bool{}
+	updates := map[uuid.UUID]bool{}
+	// the submitted data supersedes any pending job of the host
+	if vt, found := svc.hosts.Load(host.Id); found {
+		vt.(*verifyTrustJob).cancelFn()
+		updates[host.Id] = preferHashMatch
+	} else {
+		adds[host.Id] = preferHashMatch
+	}
+	if err := svc.persistToStore(adds, updates, false, preferHashMatch); err != nil {
+		svc.syncMtx.Unlock()
+		return errors.Wrap(err, "hosttrust/manager:VerifyHostDataAsync() persistRequest - error in Persisting to Store")
+	}
+	vt, _ := svc.hosts.Load(host.Id)
+	vtj := vt.(*verifyTrustJob)
+	vtj.host = &host
+	svc.syncMtx.Unlock()
+
+	return svc.ProcessHostData(vtj.ctx, host, hostData, preferHashMatch, nil)
+}
+
 func (svc *Service) submitHostDataFetch(hostLists map[uuid.UUID]bool) {
 	defaultLog.Trace("hosttrust/manager:submitHostDataFetch() Entering")
 	defer defaultLog.Trace("hosttrust/manager:submitHostDataFetch() Leaving")
@@ -504,7 +574,7 @@ func (svc *Service) verifyHostData(hostId uuid.UUID, data *hvs.HostManifest, new
 		taskstage.StoreInContext(vtj.ctx, taskstage.FlavorVerifyStarted)
 	}
 
-	_, err := svc.verifier.Verify(hostId, data, newData, preferHashMatch)
+	_, err := svc.verifier.Verify(vtj.ctx, hostId, data, newData, preferHashMatch)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("hosttrust/manager:verifyHostData() Error while verification: %s", hostId.String())
 	}
@@ -6,6 +6,7 @@
 package hosttrust_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -70,12 +71,13 @@ func SetupManagerTests() {
 			ServiceUsername: "serviceUsername",
 			ServicePassword: "servicePassword",
 		},
-		RetryTimeMinutes: 7,
-		HostStatusStore:  hss,
-		HostStore:        hs,
-		FlavorGroupStore: fgs,
-		FlavorStore:      fs,
-		HostTrustCache:   flavorCache,
+		RetryBackoffBase:    7 * time.Minute,
+		HostStatusStore:     hss,
+		HostStore:           hs,
+		FlavorGroupStore:    fgs,
+		FlavorStore:         fs,
+		HostFetchQueueStore: mocks.NewMockHostFetchQueueStore(),
+		HostTrustCache:      flavorCache,
 	}
 
 	_, f, _ = hostfetcher.NewService(cfg, 5)
@@ -172,7 +174,7 @@ func TestHostTrustManagerNewService(t *testing.T) {
 
 func TestVerifier_Verify_UntrustedHost(t *testing.T) {
 	SetupManagerTests()
-	report, err := v.Verify(hostId, &hostManifest, false, false)
+	report, err := v.Verify(context.Background(), hostId, &hostManifest, false, false)
 	assert.NoError(t, err)
 	fmt.Println(report.TrustReport.Trusted)
 	assert.Equal(t, report.TrustReport.Trusted, false)
@@ -180,6 +182,21 @@ func TestVerifier_Verify_UntrustedHost(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestVerifier_Simulate(t *testing.T) {
+	SetupManagerTests()
+	flavorGroup, err := fgs.Retrieve(uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+	assert.NoError(t, err)
+
+	trustReport, err := v.Simulate(hostId, &hostManifest, *flavorGroup, nil)
+	assert.NoError(t, err)
+	report, err := v.Verify(context.Background(), hostId, &hostManifest, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, report.TrustReport.Trusted, trustReport.Trusted)
+
+	_, err = v.Simulate(hostId, nil, *flavorGroup, nil)
+	assert.Equal(t, hosttrust.ErrInvalidHostManiFest, err)
+}
+
 func TestHostTrustManagerShutdown(t *testing.T) {
 	SetupManagerTests()
 	hwUuid, err := uuid.NewRandom()
@@ -211,13 +228,13 @@ func TestHostTrustManagerShutdown(t *testing.T) {
 func TestManager_VerifyHostSyncWithHostDataFetch(t *testing.T) {
 	SetupManagerTests()
 
-	_, err := service.VerifyHost(hostId, true, false)
+	_, err := service.VerifyHost(context.Background(), hostId, true, false)
 	assert.NoError(t, err, "VerifyHost should not return an error when HostData is fetched")
 }
 
 func TestManager_VerifyHostSyncWithoutHostDataFetch(t *testing.T) {
 	SetupManagerTests()
-	_, err := service.VerifyHost(hostId, false, false)
+	_, err := service.VerifyHost(context.Background(), hostId, false, false)
 	assert.Error(t, err, "VerifyHost should error out when the Host manifest is not present in HostStatus")
 }
 
@@ -259,11 +276,11 @@ func TestManager_VerifyNonExistentHost(t *testing.T) {
 
 	newId, err := uuid.NewRandom()
 	assert.NoError(t, err)
-	_, err = service.VerifyHost(newId, true, false)
+	_, err = service.VerifyHost(context.Background(), newId, true, false)
 	assert.Error(t, err, "VerifyHost should error out when the Host does not exist")
 	newId, err = uuid.NewRandom()
 	assert.NoError(t, err)
-	_, err = service.VerifyHost(newId, false, false)
+	_, err = service.VerifyHost(context.Background(), newId, false, false)
 	assert.Error(t, err, "VerifyHost should error out when the Host does not exist")
 	newId, err = uuid.NewRandom()
 	assert.NoError(t, err)
@@ -272,3 +289,62 @@ func TestManager_VerifyNonExistentHost(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, service.VerifyHostsAsync([]uuid.UUID{newId}, false, false), "VerifyHostsAsync should error out when the Host does not exist")
 }
+
+func TestManager_VerifyHostDataAsync(t *testing.T) {
+	SetupManagerTests()
+	host, err := hs.Retrieve(hostId, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.VerifyHostDataAsync(*host, &hostManifest, false),
+		"VerifyHostDataAsync should not return an error")
+
+	assert.Error(t, service.VerifyHostDataAsync(*host, nil, false),
+		"VerifyHostDataAsync should error out when the host data is missing")
+
+	assert.NoError(t, service.Shutdown())
+	assert.Error(t, service.VerifyHostDataAsync(*host, &hostManifest, false),
+		"VerifyHostDataAsync should error out post shutdown")
+}
+
+// deliveryRecorder is a host data receiver that holds the delivery of the host data until it is released
+type deliveryRecorder struct {
+	delivered chan struct{}
+	release   chan struct{}
+}
+
+func newDeliveryRecorder() *deliveryRecorder {
+	return &deliveryRecorder{delivered: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (recorder *deliveryRecorder) ProcessHostData(ctx context.Context, host hvs.Host, data *hvs.HostManifest, preferHashMatch bool, err error) error {
+	recorder.delivered <- struct{}{}
+	<-recorder.release
+	return nil
+}
+
+func TestHostFetcher_RequestDuringDelivery(t *testing.T) {
+	SetupManagerTests()
+	host, err := hs.Retrieve(hostId, nil)
+	assert.NoError(t, err)
+
+	first := newDeliveryRecorder()
+	assert.NoError(t, f.RetrieveAsync(context.Background(), *host, false, first))
+	select {
+	case <-first.delivered:
+	case <-time.After(10 * time.Second):
+		assert.FailNow(t, "The host data was not delivered to the first request")
+	}
+
+	// the second request comes in while the host data of the first one is being delivered
+	second := newDeliveryRecorder()
+	close(second.release)
+	assert.NoError(t, f.RetrieveAsync(context.Background(), *host, false, second))
+	time.Sleep(500 * time.Millisecond)
+	close(first.release)
+
+	select {
+	case <-second.delivered:
+	case <-time.After(10 * time.Second):
+		assert.FailNow(t, "The host data was not delivered to the request that came in during the delivery")
+	}
+}
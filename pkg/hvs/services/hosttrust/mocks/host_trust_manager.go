@@ -5,15 +5,18 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"time"
 )
 
 type MockHostTrustManager struct{}
 
-func (mock *MockHostTrustManager) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
+func (mock *MockHostTrustManager) VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
 	store := mocks.NewMockReportStore()
 	report, _ := store.Search(&models.ReportFilterCriteria{HostID: hostId})
 	return &report[0], nil
@@ -25,6 +28,18 @@ func (mock *MockHostTrustManager) VerifyHostsAsync(hostIds []uuid.UUID, fetchHos
 	return nil
 }
 
+func (mock *MockHostTrustManager) VerifyHostDataAsync(host hvs.Host, hostData *hvs.HostManifest, preferHashMatch bool) error {
+	return nil
+}
+
+// SimulateHostTrust predicts the host to be untrusted when candidate flavors are given
+func (mock *MockHostTrustManager) SimulateHostTrust(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	return &hvs.TrustReport{
+		HostManifest: *hostData,
+		Trusted:      len(candidateFlavors) == 0,
+	}, nil
+}
+
 func (mock *MockHostTrustManager) ProcessQueue() error {
 	return nil
 }
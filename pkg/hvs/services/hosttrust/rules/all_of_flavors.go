@@ -6,6 +6,7 @@
 package rules
 
 import (
+	"github.com/google/uuid"
 	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 	flavorVerifier "github.com/intel-secl/intel-secl/v4/pkg/lib/verifier"
@@ -13,20 +14,26 @@ import (
 	"github.com/pkg/errors"
 )
 
+// VerifierCertsProvider returns the certificates verifying the signature of a flavor
+type VerifierCertsProvider func(signedFlavor *hvs.SignedFlavor) flavorVerifier.VerifierCertificates
+
 type AllOfFlavors struct {
 	AllOfFlavors                    []hvs.SignedFlavor
 	Result                          *hvs.RuleResult
 	Markers                         []hvs.FlavorPartName
 	SkipFlavorSignatureVerification bool
-	verifierCerts                   flavorVerifier.VerifierCertificates
+	// UnsignedFlavors are verified without checking their signature, they hold the content of the candidate flavors
+	// of a simulation
+	UnsignedFlavors  map[uuid.UUID]bool
+	getVerifierCerts VerifierCertsProvider
 }
 
-func NewAllOfFlavors(flavors []hvs.SignedFlavor, markers []hvs.FlavorPartName, skipFlavorSignatureVerification bool, verifierCerts flavorVerifier.VerifierCertificates) AllOfFlavors {
+func NewAllOfFlavors(flavors []hvs.SignedFlavor, markers []hvs.FlavorPartName, skipFlavorSignatureVerification bool, getVerifierCerts VerifierCertsProvider) AllOfFlavors {
 	return AllOfFlavors{
 		AllOfFlavors:                    flavors,
 		Markers:                         markers,
 		SkipFlavorSignatureVerification: skipFlavorSignatureVerification,
-		verifierCerts:                   verifierCerts,
+		getVerifierCerts:                getVerifierCerts,
 	}
 }
 
@@ -41,7 +48,7 @@ func (aof *AllOfFlavors) AddFaults(report *hvs.TrustReport) (*hvs.TrustReport, e
 	hostManifest := &report.HostManifest
 	aofMissingFlavorParts := make(map[string]bool)
 	for _, flavor := range aof.AllOfFlavors {
-		ruleFactory := flavorVerifier.NewRuleFactory(aof.verifierCerts, hostManifest, &flavor, aof.SkipFlavorSignatureVerification)
+		ruleFactory := flavorVerifier.NewRuleFactory(aof.getVerifierCerts(&flavor), hostManifest, &flavor, aof.skipSignatureVerification(&flavor))
 		policyRules, _, err := ruleFactory.GetVerificationRules()
 		if err != nil {
 			return nil, err
@@ -85,6 +92,10 @@ func (aof *AllOfFlavors) AddFaults(report *hvs.TrustReport) (*hvs.TrustReport, e
 	return report, nil
 }
 
+func (aof *AllOfFlavors) skipSignatureVerification(flavor *hvs.SignedFlavor) bool {
+	return aof.SkipFlavorSignatureVerification || aof.UnsignedFlavors[flavor.Flavor.Meta.ID]
+}
+
 // RuleAllOfFlavors.java: 81
 // checkAllOfFlavorsExist(TrustReport trustReport)
 // this function does the same apply operations as addFaults
@@ -97,7 +108,7 @@ func (aof *AllOfFlavors) CheckAllOfFlavorsExist(report *hvs.TrustReport) bool {
 	}
 	hostManifest := &report.HostManifest
 	for _, flavor := range aof.AllOfFlavors {
-		ruleFactory := flavorVerifier.NewRuleFactory(aof.verifierCerts, hostManifest, &flavor, aof.SkipFlavorSignatureVerification)
+		ruleFactory := flavorVerifier.NewRuleFactory(aof.getVerifierCerts(&flavor), hostManifest, &flavor, aof.skipSignatureVerification(&flavor))
 		policyRules, _, err := ruleFactory.GetVerificationRules()
 		if err != nil {
 			defaultLog.WithError(err).Debug("hosttrust/all_of_flavors:checkAllOfFlavorsExist() Error applying vendor trust policy rule")
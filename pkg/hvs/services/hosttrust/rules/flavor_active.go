@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+)
+
+// FlavorActive applies the lifecycle of a flavor to the report of a host matching it. A deprecated flavor adds a
+// warning fault that leaves the host trusted, a revoked or expired flavor, or a deprecated flavor past its grace
+// period, makes the report untrusted.
+type FlavorActive struct {
+	SignedFlavor *hvs.SignedFlavor
+	Now          time.Time
+}
+
+func NewFlavorActive(signedFlavor *hvs.SignedFlavor, now time.Time) *FlavorActive {
+	return &FlavorActive{
+		SignedFlavor: signedFlavor,
+		Now:          now,
+	}
+}
+
+func (r *FlavorActive) Apply(trustReport hvs.TrustReport) *hvs.TrustReport {
+	// the lifecycle only matters to the hosts matching the flavor
+	if r.SignedFlavor.Lifecycle == nil || !trustReport.Trusted {
+		return &trustReport
+	}
+	fault := r.getFault(*r.SignedFlavor.Lifecycle)
+	if fault == nil {
+		return &trustReport
+	}
+
+	flavorId := r.SignedFlavor.Flavor.Meta.ID
+	flavorPart, _ := r.SignedFlavor.Flavor.Meta.Description[hvs.FlavorPartDescription].(string)
+	fault.FlavorId = &flavorId
+	ruleResult := hvs.RuleResult{
+		Rule: hvs.RuleInfo{
+			Name:     constants.RuleFlavorActive,
+			Markers:  []hvs.FlavorPartName{hvs.FlavorPartName(flavorPart)},
+			FlavorID: &flavorId,
+		},
+		FlavorId: &flavorId,
+		Faults:   []hvs.Fault{*fault},
+	}
+	ruleResult.Trusted = ruleResult.IsTrusted()
+	defaultLog.Debugf("Flavor [%s] matched by the host is not active: %s", flavorId, fault.Description)
+
+	trustReport.AddResult(ruleResult)
+	trustReport.Trusted = ruleResult.Trusted
+	return &trustReport
+}
+
+// getFault returns the fault raised by the lifecycle of the flavor, nil when the flavor is active
+func (r *FlavorActive) getFault(lifecycle hvs.FlavorLifecycle) *hvs.Fault {
+	flavorId := r.SignedFlavor.Flavor.Meta.ID
+	var fault hvs.Fault
+	if lifecycle.IsExpired(r.Now) {
+		fault = hvs.Fault{
+			Name:        constants.FaultFlavorExpired,
+			Description: fmt.Sprintf("Flavor %s expired on %s", flavorId, lifecycle.ExpiresAt.Format(time.RFC3339)),
+		}
+	} else if lifecycle.State == hvs.FlavorStateRevoked {
+		fault = hvs.Fault{
+			Name:        constants.FaultFlavorRevoked,
+			Description: fmt.Sprintf("Flavor %s is revoked", flavorId),
+		}
+	} else if lifecycle.IsGracePeriodOver(r.Now) {
+		fault = hvs.Fault{
+			Name: constants.FaultFlavorGracePeriodEnded,
+			Description: fmt.Sprintf("The grace period of deprecated flavor %s ended on %s", flavorId,
+				lifecycle.GracePeriodEnd.Format(time.RFC3339)),
+		}
+	} else if lifecycle.State == hvs.FlavorStateDeprecated {
+		fault = hvs.Fault{
+			Name:        constants.FaultFlavorDeprecated,
+			Description: fmt.Sprintf("Flavor %s is deprecated", flavorId),
+			Severity:    hvs.FaultSeverityWarning,
+		}
+		if lifecycle.GracePeriodEnd != nil {
+			fault.Description += " until " + lifecycle.GracePeriodEnd.Format(time.RFC3339)
+		}
+	} else {
+		return nil
+	}
+
+	if lifecycle.SupersededBy != nil {
+		fault.Description += fmt.Sprintf(", it is superseded by flavor %s", *lifecycle.SupersededBy)
+	}
+	return &fault
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLifecycleFlavor(lifecycle *hvs.FlavorLifecycle) *hvs.SignedFlavor {
+	return &hvs.SignedFlavor{
+		Flavor: hvs.Flavor{
+			Meta: hvs.Meta{
+				ID:          uuid.New(),
+				Description: map[string]interface{}{hvs.FlavorPartDescription: hvs.FlavorPartPlatform.String()},
+			},
+		},
+		Lifecycle: lifecycle,
+	}
+}
+
+func newTrustedReport() hvs.TrustReport {
+	return hvs.TrustReport{
+		Trusted: true,
+		Results: []hvs.RuleResult{{
+			Rule:    hvs.RuleInfo{Name: constants.RulePcrMatchesConstant, Markers: []hvs.FlavorPartName{hvs.FlavorPartPlatform}},
+			Trusted: true,
+		}},
+	}
+}
+
+func TestFlavorActiveApply(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	supersededBy := uuid.New()
+
+	for name, test := range map[string]struct {
+		lifecycle *hvs.FlavorLifecycle
+		fault     string
+		trusted   bool
+	}{
+		"no lifecycle":          {nil, "", true},
+		"active":                {&hvs.FlavorLifecycle{State: hvs.FlavorStateActive, ExpiresAt: &future}, "", true},
+		"deprecated":            {&hvs.FlavorLifecycle{State: hvs.FlavorStateDeprecated, SupersededBy: &supersededBy}, constants.FaultFlavorDeprecated, true},
+		"deprecated with grace": {&hvs.FlavorLifecycle{State: hvs.FlavorStateDeprecated, GracePeriodEnd: &future}, constants.FaultFlavorDeprecated, true},
+		"grace period ended":    {&hvs.FlavorLifecycle{State: hvs.FlavorStateDeprecated, GracePeriodEnd: &past}, constants.FaultFlavorGracePeriodEnded, false},
+		"revoked":               {&hvs.FlavorLifecycle{State: hvs.FlavorStateRevoked}, constants.FaultFlavorRevoked, false},
+		"expired":               {&hvs.FlavorLifecycle{State: hvs.FlavorStateActive, ExpiresAt: &past}, constants.FaultFlavorExpired, false},
+	} {
+		signedFlavor := newLifecycleFlavor(test.lifecycle)
+		report := NewFlavorActive(signedFlavor, now).Apply(newTrustedReport())
+
+		assert.Equal(t, test.trusted, report.Trusted, name)
+		assert.Equal(t, test.trusted, report.IsTrusted(), name)
+		if test.fault == "" {
+			assert.Len(t, report.Results, 1, name)
+			continue
+		}
+		assert.Len(t, report.Results, 2, name)
+		result := report.Results[1]
+		assert.Equal(t, constants.RuleFlavorActive, result.Rule.Name, name)
+		assert.Equal(t, test.fault, result.Faults[0].Name, name)
+		assert.Equal(t, signedFlavor.Flavor.Meta.ID, *result.Faults[0].FlavorId, name)
+		assert.Equal(t, test.trusted, result.Faults[0].IsWarning(), name)
+	}
+}
+
+func TestFlavorActiveApplyUntrustedReport(t *testing.T) {
+	signedFlavor := newLifecycleFlavor(&hvs.FlavorLifecycle{State: hvs.FlavorStateRevoked})
+	report := newTrustedReport()
+	report.Trusted = false
+
+	// the lifecycle is not reported for the flavors the host does not match
+	assert.Len(t, NewFlavorActive(signedFlavor, time.Now()).Apply(report).Results, 1)
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hosttrust
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// Simulate predicts the trust of a host against a flavorgroup without storing anything. The host data is verified
+// against the flavors of the flavorgroup along with the candidate flavors, following the match policies of the given
+// flavorgroup rather than the stored ones. The trust cache of the host is neither used nor updated and no report is
+// saved. The candidate flavors are considered the latest flavors of their flavor part, those that are not signed hold
+// the content of new flavors and are verified without checking their signature.
+func (v *Verifier) Simulate(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/simulation:Simulate() Entering")
+	defer defaultLog.Trace("hosttrust/simulation:Simulate() Leaving")
+
+	if hostData == nil {
+		return nil, ErrInvalidHostManiFest
+	}
+
+	hwUuid, err := uuid.Parse(hostData.HostInfo.HardwareUUID)
+	if err != nil || hwUuid == uuid.Nil {
+		return nil, ErrManifestMissingHwUUID
+	}
+
+	// the stored flavors are still verified with their signature
+	unsignedFlavors := make(map[uuid.UUID]bool)
+	for _, candidate := range candidateFlavors {
+		if candidate.Signature == "" {
+			unsignedFlavors[candidate.Flavor.Meta.ID] = true
+		}
+	}
+	sv := *v
+	sv.unsignedFlavors = unsignedFlavors
+
+	hostUniqueFlavorParts, err := v.HostStore.RetrieveDistinctUniqueFlavorParts(hostId)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while retrieving host unique flavor parts")
+	}
+	hostUniqueFlavorPartsMap := make(map[hvs.FlavorPartName]bool)
+	for _, flavorPart := range hostUniqueFlavorParts {
+		hostUniqueFlavorPartsMap[hvs.FlavorPartName(flavorPart)] = true
+	}
+
+	reqs, err := NewFlvGrpHostTrustReqs(hostId, hostUniqueFlavorPartsMap, flavorGroup, v.FlavorStore, v.FlavorGroupStore, hostData, v.SkipFlavorSignatureVerification)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while retrieving NewFlvGrpHostTrustReqs")
+	}
+	if err := addCandidateFlavorReqs(reqs, hostData, candidateFlavors); err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while adding the requirements of the candidate flavors")
+	}
+
+	latestReqAndDefFlavorTypes := reqs.GetLatestFlavorTypeMap()
+	flavorParts := make([]hvs.FlavorPartName, 0, len(latestReqAndDefFlavorTypes))
+	for flavorPart := range latestReqAndDefFlavorTypes {
+		flavorParts = append(flavorParts, flavorPart)
+	}
+	hostManifestMap, err := getHostManifestMap(hostData, flavorParts)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while creating host manifest map")
+	}
+	flavorsToVerify, err := v.findFlavors(flavorGroup.ID, latestReqAndDefFlavorTypes, hostManifestMap)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while finding flavors")
+	}
+	flavorsToVerify, err = addCandidateFlavors(flavorsToVerify, candidateFlavors, latestReqAndDefFlavorTypes, hostManifestMap)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while matching candidate flavors")
+	}
+
+	// the flavors are evaluated the way verifyFlavors does, without saving the trust cache of the host
+	trustReport, _, err := sv.evaluateFlavors(hostId, flavorsToVerify, hostData, *reqs)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error while verifying flavors")
+	}
+	trustReport, err = sv.applyFlavorGroupRules(trustReport, *reqs)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/simulation:Simulate() Error applying flavorgroup rules")
+	}
+	trustReport.Trusted = trustReport.IsTrusted()
+	defaultLog.Debugf("hosttrust/simulation:Simulate() Predicted trust status for host id %s for flavorgroup %s is %t", hostId, flavorGroup.ID, trustReport.Trusted)
+	return trustReport, nil
+}
+
+// addCandidateFlavorReqs adds the candidate flavors to the requirements of the flavorgroup: their flavor parts become
+// defined and the candidates matching the host are added to the ALL_OF flavors
+func addCandidateFlavorReqs(reqs *flvGrpHostTrustReqs, hostData *hvs.HostManifest, candidateFlavors []hvs.SignedFlavor) error {
+	defaultLog.Trace("hosttrust/simulation:addCandidateFlavorReqs() Entering")
+	defer defaultLog.Trace("hosttrust/simulation:addCandidateFlavorReqs() Leaving")
+
+	for _, candidate := range candidateFlavors {
+		flavorPart := getFlavorPart(&candidate)
+		if policy, exists := reqs.FlavorPartMatchPolicy[flavorPart]; exists && policy.Required == hvs.FlavorRequiredIfDefined {
+			reqs.DefinedAndRequiredFlavorTypes[flavorPart] = true
+		}
+	}
+
+	allOfFlavorParts := reqs.MatchTypeFlavorParts[hvs.MatchTypeAllOf]
+	if len(allOfFlavorParts) == 0 || len(candidateFlavors) == 0 {
+		return nil
+	}
+	hostManifestMap, err := getHostManifestMap(hostData, allOfFlavorParts)
+	if err != nil {
+		return errors.Wrap(err, "error while creating host manifest map")
+	}
+	for _, flavorPart := range allOfFlavorParts {
+		for _, candidate := range candidateFlavors {
+			matches, err := matchesHostManifest(&candidate, flavorPart, hostManifestMap[flavorPart])
+			if err != nil {
+				return err
+			}
+			if matches {
+				reqs.AllOfFlavors = append(reqs.AllOfFlavors, candidate)
+			}
+		}
+	}
+	return nil
+}
+
+// addCandidateFlavors adds the candidate flavors matching the host to the flavors of the flavorgroup retrieved for
+// verification. For the flavor parts with the LATEST match type, the latest matching candidate replaces the stored
+// flavors.
+func addCandidateFlavors(flavors, candidateFlavors []hvs.SignedFlavor, latestReqAndDefFlavorTypes map[hvs.FlavorPartName]bool, hostManifestMap map[hvs.FlavorPartName][]models.FlavorMetaKv) ([]hvs.SignedFlavor, error) {
+	defaultLog.Trace("hosttrust/simulation:addCandidateFlavors() Entering")
+	defer defaultLog.Trace("hosttrust/simulation:addCandidateFlavors() Leaving")
+
+	latestCandidates := make(map[hvs.FlavorPartName]hvs.SignedFlavor)
+	var matchingCandidates []hvs.SignedFlavor
+	for _, candidate := range candidateFlavors {
+		flavorPart := getFlavorPart(&candidate)
+		// the flavor store returns all the flavors of the flavorgroup when no flavor part is required
+		if len(latestReqAndDefFlavorTypes) > 0 {
+			latest, required := latestReqAndDefFlavorTypes[flavorPart]
+			if !required {
+				continue
+			}
+			matches, err := matchesHostManifest(&candidate, flavorPart, hostManifestMap[flavorPart])
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				continue
+			}
+			if latest {
+				latestCandidates[flavorPart] = candidate
+				continue
+			}
+		}
+		matchingCandidates = append(matchingCandidates, candidate)
+	}
+
+	result := make([]hvs.SignedFlavor, 0, len(flavors)+len(matchingCandidates)+len(latestCandidates))
+	for _, signedFlavor := range flavors {
+		if _, replaced := latestCandidates[getFlavorPart(&signedFlavor)]; !replaced {
+			result = append(result, signedFlavor)
+		}
+	}
+	result = append(result, matchingCandidates...)
+	for _, candidate := range latestCandidates {
+		result = append(result, candidate)
+	}
+	return result, nil
+}
+
+// matchesHostManifest returns true when the flavor is of the given flavor part and holds the values of the host
+// manifest attributes, it matches the flavor the way the flavor store searches them
+func matchesHostManifest(signedFlavor *hvs.SignedFlavor, flavorPart hvs.FlavorPartName, attributes []models.FlavorMetaKv) (bool, error) {
+	if getFlavorPart(signedFlavor) != flavorPart {
+		return false, nil
+	}
+	if len(attributes) == 0 {
+		return true, nil
+	}
+
+	flavorJSON, err := json.Marshal(signedFlavor.Flavor)
+	if err != nil {
+		return false, errors.Wrap(err, "error while marshalling flavor")
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(flavorJSON, &content); err != nil {
+		return false, errors.Wrap(err, "error while unmarshalling flavor")
+	}
+
+	for _, attribute := range attributes {
+		// software flavors are matched on their label
+		if labels, ok := attribute.Value.([]string); ok {
+			label, _ := signedFlavor.Flavor.Meta.Description[hvs.Label].(string)
+			if !containsString(labels, label) {
+				return false, nil
+			}
+			continue
+		}
+		value, exists := getJSONPathValue(content, attribute.Key)
+		if !exists || fmt.Sprint(value) != fmt.Sprint(attribute.Value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getJSONPathValue returns the value at the dotted path of the JSON content
+func getJSONPathValue(content map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = content
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok || value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func getFlavorPart(signedFlavor *hvs.SignedFlavor) hvs.FlavorPartName {
+	flavorPart, _ := signedFlavor.Flavor.Meta.Description[hvs.FlavorPartDescription].(string)
+	return hvs.FlavorPartName(flavorPart)
+}
+
+func containsString(values []string, value string) bool {
+	for _, s := range values {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
@@ -43,7 +43,7 @@ func (v *Verifier) CreateFlavorGroupReport(hostId uuid.UUID, reqs flvGrpHostTrus
 		return v.createTrustReport(hostId, hostData, reqs, trustCache, missingRequiredFlavorPartsWithLatest)
 	}
 
-	ruleAllOfFlavors := rules.NewAllOfFlavors(reqs.AllOfFlavors, reqs.getAllOfMarkers(), v.SkipFlavorSignatureVerification, v.FlavorVerifier.GetVerifierCerts())
+	ruleAllOfFlavors := rules.NewAllOfFlavors(reqs.AllOfFlavors, reqs.getAllOfMarkers(), v.SkipFlavorSignatureVerification, v.getVerifierCerts)
 	if areAllOfFlavorsMissingInCachedTrustReport(trustCache.trustReport, ruleAllOfFlavors) {
 		defaultLog.Trace("hosttrust/trust_report:CreateFlavorGroupReport() All Of Flavors Missing In Cached TrustReport")
 		return v.createTrustReport(hostId, hostData, reqs, trustCache, latestReqAndDefFlavorTypes)
@@ -105,18 +105,32 @@ func (v *Verifier) createTrustReport(hostId uuid.UUID, hostData *hvs.HostManifes
 		trustReport.AddResults(trustCache.trustReport.Results)
 	}
 
+	trustReport, err = v.applyFlavorGroupRules(trustReport, reqs)
+	if err != nil {
+		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error applying flavorgroup rules")
+	}
+	return *trustReport, nil
+}
+
+// applyFlavorGroupRules adds the faults of the required flavor types missing from the report and of the ALL_OF
+// flavors the host does not match
+func (v *Verifier) applyFlavorGroupRules(trustReport *hvs.TrustReport, reqs flvGrpHostTrustReqs) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/trust_report:applyFlavorGroupRules() Entering")
+	defer defaultLog.Trace("hosttrust/trust_report:applyFlavorGroupRules() Leaving")
+
 	for flavorPart := range reqs.DefinedAndRequiredFlavorTypes {
 		rule := rules.NewRequiredFlavorTypeExists(flavorPart)
 		trustReport = rule.Apply(*trustReport)
 	}
 
-	ruleAllOfFlavors := rules.NewAllOfFlavors(reqs.AllOfFlavors, reqs.getAllOfMarkers(), v.SkipFlavorSignatureVerification, v.FlavorVerifier.GetVerifierCerts())
+	ruleAllOfFlavors := rules.NewAllOfFlavors(reqs.AllOfFlavors, reqs.getAllOfMarkers(), v.SkipFlavorSignatureVerification, v.getVerifierCerts)
+	ruleAllOfFlavors.UnsignedFlavors = v.unsignedFlavors
 
-	trustReport, err = ruleAllOfFlavors.AddFaults(trustReport)
+	trustReport, err := ruleAllOfFlavors.AddFaults(trustReport)
 	if err != nil {
-		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error applying ruleAllOfFlavors")
+		return nil, errors.Wrap(err, "Error applying ruleAllOfFlavors")
 	}
-	return *trustReport, nil
+	return trustReport, nil
 }
 
 func getMatchPolicy(flvMatchPolicies hvs.FlavorMatchPolicies, part hvs.FlavorPartName) *hvs.MatchPolicy {
@@ -144,6 +158,28 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 	defaultLog.Trace("hosttrust/trust_report:verifyFlavors() Entering")
 	defer defaultLog.Trace("hosttrust/trust_report:verifyFlavors() Leaving")
 
+	trustReport, newTrustCaches, err := v.evaluateFlavors(hostID, flavors, hostData, hostTrustReqs)
+	if err != nil {
+		return &hvs.TrustReport{}, err
+	}
+	if len(trustReport.Results) == 0 {
+		return trustReport, nil
+	}
+	// save the trust cache // ignore error since it is just a cache.
+	if _, err := v.HostStore.AddTrustCacheFlavors(hostID, newTrustCaches); err != nil {
+		log.Error("hosttrust/trust_report:verifyFlavors() error while adding flavor trust cache to store for host id ", hostID, "error - ", err)
+	}
+
+	return trustReport, nil
+}
+
+// evaluateFlavors verifies the host data against the flavors and combines their reports following the match policies
+// of the flavorgroup. It returns the collective trust report along with the IDs of the flavors it was built from,
+// which make up the trust cache of the host.
+func (v *Verifier) evaluateFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, hostData *hvs.HostManifest, hostTrustReqs flvGrpHostTrustReqs) (*hvs.TrustReport, []uuid.UUID, error) {
+	defaultLog.Trace("hosttrust/trust_report:evaluateFlavors() Entering")
+	defer defaultLog.Trace("hosttrust/trust_report:evaluateFlavors() Leaving")
+
 	collectiveTrustReport := hvs.TrustReport{}
 
 	// need to create a map to hold all the untrusted individual reports and group them by the flavor part/type.
@@ -164,9 +200,9 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 			flvPart := signedFlavor.Flavor.Meta.Description[hvs.FlavorPartDescription].(string)
 			if flvPart == flvMatchPolicy.FlavorPart.String() {
 
-				individualTrustReport, err := v.FlavorVerifier.Verify(hostData, &signedFlavor, v.SkipFlavorSignatureVerification)
+				individualTrustReport, err := v.verifyFlavor(hostData, &signedFlavor)
 				if err != nil {
-					return &hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:verifyFlavors() Error verifying flavor")
+					return nil, nil, errors.Wrap(err, "hosttrust/trust_report:evaluateFlavors() Error verifying flavor")
 				}
 				if individualTrustReport.Trusted {
 					if reflect.DeepEqual(collectiveTrustReport, hvs.TrustReport{}) {
@@ -199,11 +235,11 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 	}
 
 	for flavPart, flavPartReports := range untrusted.flavorPartMap {
-		log.Debug("hosttrust/trust_report:verifyFlavors() Processing untrusted trust report for flavor part:", flavPart)
+		log.Debug("hosttrust/trust_report:evaluateFlavors() Processing untrusted trust report for flavor part:", flavPart)
 		if hostTrustReqs.DefinedAndRequiredFlavorTypes[flavPart] &&
 			len(collectiveTrustReport.Results) == 0 || !collectiveTrustReport.IsTrustedForMarker(flavPart.String()) {
 			if matchPolicy, matchPolicyExists := hostTrustReqs.FlavorPartMatchPolicy[flavPart]; matchPolicyExists && matchPolicy.MatchType == hvs.MatchTypeAllOf {
-				log.Debug("hosttrust/trust_report:verifyFlavors() Flavor Part :", flavPart, " requires ALL_OF policy - each untrusted flavor needs to be added to collective report")
+				log.Debug("hosttrust/trust_report:evaluateFlavors() Flavor Part :", flavPart, " requires ALL_OF policy - each untrusted flavor needs to be added to collective report")
 				for _, flavorReport := range flavPartReports {
 					log.Debug("Adding untrusted trust report to collective report for ALL_OF flavor part", flavPart, " with flavor ID ", flavorReport.id)
 					collectiveTrustReport.AddResults(flavorReport.report.Results)
@@ -212,7 +248,7 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 
 			} else if matchPolicy, matchPolicyExists := hostTrustReqs.FlavorPartMatchPolicy[flavPart]; matchPolicyExists && (matchPolicy.MatchType == hvs.MatchTypeAnyOf ||
 				matchPolicy.MatchType == hvs.MatchTypeLatest) {
-				log.Debug("hosttrust/trust_report:verifyFlavors() Flavor part requires ANY_OF policy, untrusted flavor report with least faults must be added to the collective report", flavPart)
+				log.Debug("hosttrust/trust_report:evaluateFlavors() Flavor part requires ANY_OF policy, untrusted flavor report with least faults must be added to the collective report", flavPart)
 				var leastFaultReport *flavorReport
 				for _, flavorReport := range flavPartReports {
 
@@ -222,7 +258,7 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 					}
 				}
 				if leastFaultReport != nil {
-					log.Debug("hosttrust/trust_report:verifyFlavors() Adding untrusted trust report to collective report for ANY_OF flavor part, ",
+					log.Debug("hosttrust/trust_report:evaluateFlavors() Adding untrusted trust report to collective report for ANY_OF flavor part, ",
 						leastFaultReport.flavorPart, "with flavor ID ", leastFaultReport.id)
 					collectiveTrustReport.AddResults(leastFaultReport.report.Results)
 					newTrustCaches = append(newTrustCaches, leastFaultReport.id)
@@ -234,14 +270,10 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 		//TODO - check if we return an error here
 		return &hvs.TrustReport{
 			HostManifest: *hostData,
-		}, nil
-	}
-	// save the trust cache // ignore error since it is just a cache.
-	if _, err := v.HostStore.AddTrustCacheFlavors(hostID, newTrustCaches); err != nil {
-		log.Error("hosttrust/trust_report:verifyFlavors() error while adding flavor trust cache to store for host id ", hostID, "error - ", err)
+		}, nil, nil
 	}
 
-	return &collectiveTrustReport, nil
+	return &collectiveTrustReport, newTrustCaches, nil
 }
 
 // FlavorVerify.java: 684
@@ -396,6 +428,9 @@ func getHostManifestMap(hostManifest *hvs.HostManifest, flavorParts []hvs.Flavor
 					})
 				}
 				hostInfoValues[hvs.FlavorPartSoftware] = sfQueryAttrs
+			} else if fp == hvs.FlavorPartIma {
+				// IMA flavors are not specific to a host, all the IMA flavors of the flavorgroup are matched
+				hostInfoValues[hvs.FlavorPartIma] = []models.FlavorMetaKv{}
 			} else {
 				return nil, errors.New("Invalid flavor part - " + fp.String())
 			}
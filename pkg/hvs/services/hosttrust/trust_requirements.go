@@ -10,7 +10,6 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/rules"
-	flavorVerifier "github.com/intel-secl/intel-secl/v4/pkg/lib/verifier"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	"github.com/pkg/errors"
 	"reflect"
@@ -116,7 +115,7 @@ func (r *flvGrpHostTrustReqs) GetLatestFlavorTypeMap() map[hvs.FlavorPartName]bo
 	return result
 }
 
-func (r *flvGrpHostTrustReqs) MeetsFlavorGroupReqs(trustCache hostTrustCache, verifierCerts flavorVerifier.VerifierCertificates) bool {
+func (r *flvGrpHostTrustReqs) MeetsFlavorGroupReqs(trustCache hostTrustCache, getVerifierCerts rules.VerifierCertsProvider) bool {
 	defaultLog.Trace("hosttrust/trust_requirements:MeetsFlavorGroupReqs() Entering")
 	defer defaultLog.Trace("hosttrust/trust_requirements:MeetsFlavorGroupReqs() Leaving")
 
@@ -132,7 +131,7 @@ func (r *flvGrpHostTrustReqs) MeetsFlavorGroupReqs(trustCache hostTrustCache, ve
 		return false
 	}
 
-	ruleAllOfFlavors := rules.NewAllOfFlavors(r.AllOfFlavors, r.getAllOfMarkers(), r.SkipFlavorSignatureVerification, verifierCerts)
+	ruleAllOfFlavors := rules.NewAllOfFlavors(r.AllOfFlavors, r.getAllOfMarkers(), r.SkipFlavorSignatureVerification, getVerifierCerts)
 
 	if areAllOfFlavorsMissingInCachedTrustReport(trustCache.trustReport, ruleAllOfFlavors) {
 		defaultLog.Debugf("All of flavors exist in policy for host: %s", r.HostId.String())
@@ -6,17 +6,28 @@
 package hosttrust
 
 import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+
 	"github.com/google/uuid"
 	lru "github.com/hashicorp/golang-lru"
+	faultsConst "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/metrics"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/services/hosttrust/rules"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/tracing"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/saml"
 	flavorVerifier "github.com/intel-secl/intel-secl/v4/pkg/lib/verifier"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
 	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
 )
 
 var ErrInvalidHostManiFest = errors.New("invalid host data")
@@ -33,6 +44,10 @@ type Verifier struct {
 	SkipFlavorSignatureVerification bool
 	hostQuoteReportCache            map[uuid.UUID]*models.QuoteReportCache
 	HostTrustCache                  *lru.Cache
+	EventPublisher                  domain.HostEventPublisher
+	// unsignedFlavors are the candidate flavors of a simulation holding the content of new flavors, they are verified
+	// without checking their signature
+	unsignedFlavors map[uuid.UUID]bool
 }
 
 func NewVerifier(cfg domain.HostTrustVerifierConfig) domain.HostTrustVerifier {
@@ -46,6 +61,7 @@ func NewVerifier(cfg domain.HostTrustVerifierConfig) domain.HostTrustVerifier {
 		SamlIssuer:                      cfg.SamlIssuerConfig,
 		SkipFlavorSignatureVerification: cfg.SkipFlavorSignatureVerification,
 		HostTrustCache:                  cfg.HostTrustCache,
+		EventPublisher:                  cfg.EventPublisher,
 		hostQuoteReportCache:            make(map[uuid.UUID]*models.QuoteReportCache),
 	}
 }
@@ -57,13 +73,22 @@ func getTrustPcrListReport(hostInfo taModel.HostInfo, report *hvs.TrustReport) [
 	trustPcrMap := make(map[int]struct{})
 	var trustPcrList []int
 
+	addPcr := func(pcrIndex int) {
+		if _, ok := trustPcrMap[pcrIndex]; !ok {
+			trustPcrMap[pcrIndex] = struct{}{}
+			trustPcrList = append(trustPcrList, pcrIndex)
+		}
+	}
 	for _, result := range report.Results {
 		if result.Rule.ExpectedPcr != nil {
-			pcrIndex := result.Rule.ExpectedPcr.Pcr.Index
-			if _, ok := trustPcrMap[pcrIndex]; !ok {
-				trustPcrMap[pcrIndex] = struct{}{}
-				trustPcrList = append(trustPcrList, pcrIndex)
-			}
+			addPcr(result.Rule.ExpectedPcr.Pcr.Index)
+		}
+		// the IMA rules replay the IMA log against the PCR it extends, which is not part of the expected PCRs. The
+		// PCR is recorded by the rule only once the log was found, so it is added for every IMA rule.
+		if result.Rule.PCR != nil {
+			addPcr(result.Rule.PCR.Index)
+		} else if isImaRule(result.Rule) {
+			addPcr(int(hvs.ImaPcrIndex))
 		}
 	}
 	if len(trustPcrList) > 0 && utils.IsLinuxHost(&hostInfo) {
@@ -72,9 +97,18 @@ func getTrustPcrListReport(hostInfo taModel.HostInfo, report *hvs.TrustReport) [
 	return trustPcrList
 }
 
-func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData bool, preferHashMatch bool) (*models.HVSReport, error) {
+// isImaRule returns true for the rules verifying the IMA runtime measurement list
+func isImaRule(rule hvs.RuleInfo) bool {
+	return rule.Name == faultsConst.RuleImaLogIntegrity || rule.Name == faultsConst.RuleImaMeasurementsAllowed
+}
+
+func (v *Verifier) Verify(ctx context.Context, hostId uuid.UUID, hostData *hvs.HostManifest, newData bool, preferHashMatch bool) (_ *models.HVSReport, err error) {
 	defaultLog.Trace("hosttrust/verifier:Verify() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:Verify() Leaving")
+	defer metrics.ObserveHostVerify(time.Now())
+	_, span := tracing.StartSpan(ctx, "hosttrust.Verify", attribute.String("host.id", hostId.String()),
+		attribute.Bool("host.new_data", newData))
+	defer func() { tracing.EndSpan(span, err) }()
 
 	defaultLog.Debugf("hosttrust/verifier:Verify() host - %s", hostId.String())
 
@@ -94,7 +128,9 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData
 		// check if the PCR Values are unchanged.
 		if ok {
 			cachedQuote := cacheEntry.(*models.QuoteReportCache)
-			if cachedQuote.QuoteDigest != "" && hostData.QuoteDigest == cachedQuote.QuoteDigest {
+			if cachedQuote.LifecycleDeadline != nil && !time.Now().Before(*cachedQuote.LifecycleDeadline) {
+				log.Debugf("hosttrust/verifier:Verify() Lifecycle of a flavor changed since the cached report of host %s was built - verifying the flavors again", hostId.String())
+			} else if cachedQuote.QuoteDigest != "" && hostData.QuoteDigest == cachedQuote.QuoteDigest {
 				// retrieve the stored report
 				log.Debugf("hosttrust/verifier:Verify() Quote values matches cached value for host %s - skipping flavor verification", hostId.String())
 				if report, err := v.refreshTrustReport(hostId, cachedQuote); err == nil {
@@ -154,7 +190,7 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData
 		}
 
 		fgTrustReport := fgTrustCache.trustReport
-		if !fgTrustReqs.MeetsFlavorGroupReqs(fgTrustCache, v.FlavorVerifier.GetVerifierCerts()) {
+		if !fgTrustReqs.MeetsFlavorGroupReqs(fgTrustCache, v.getVerifierCerts) {
 			log.Debug("hosttrust/verifier:Verify() Trust cache doesn't meet flavorgroup requirements")
 			finalReportValid = false
 			fgTrustReport, err = v.CreateFlavorGroupReport(hostId, *fgTrustReqs, hostData, fgTrustCache)
@@ -180,9 +216,10 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData
 		trustPcrList := getTrustPcrListReport(hostData.HostInfo, &finalTrustReport)
 		defaultLog.Infof("hosttrust/verifier:add() PCR List %v for host %v ", hostId, trustPcrList)
 		newCacheEntry := &models.QuoteReportCache{
-			QuoteDigest:  hostData.QuoteDigest,
-			TrustPcrList: trustPcrList,
-			TrustReport:  &finalTrustReport,
+			QuoteDigest:       hostData.QuoteDigest,
+			TrustPcrList:      trustPcrList,
+			TrustReport:       &finalTrustReport,
+			LifecycleDeadline: v.getLifecycleDeadline(&finalTrustReport, time.Now()),
 		}
 		v.HostTrustCache.Add(hostId, newCacheEntry)
 		hvsReport = v.storeTrustReport(hostId, &finalTrustReport, &samlReport)
@@ -193,6 +230,39 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData
 	return hvsReport, nil
 }
 
+// getLifecycleDeadline returns the earliest time at which a flavor of the report expires or ends its grace period.
+// A flavor that cannot be retrieved makes the deadline now so that the report is not reused.
+func (v *Verifier) getLifecycleDeadline(trustReport *hvs.TrustReport, now time.Time) *time.Time {
+	defaultLog.Trace("hosttrust/verifier:getLifecycleDeadline() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:getLifecycleDeadline() Leaving")
+
+	var deadline *time.Time
+	flavorIds := make(map[uuid.UUID]struct{})
+	for _, result := range trustReport.Results {
+		if result.FlavorId == nil {
+			continue
+		}
+		if _, ok := flavorIds[*result.FlavorId]; ok {
+			continue
+		}
+		flavorIds[*result.FlavorId] = struct{}{}
+
+		signedFlavor, err := v.FlavorStore.Retrieve(*result.FlavorId)
+		if err != nil {
+			log.WithError(err).Warnf("hosttrust/verifier:getLifecycleDeadline() Failed to retrieve flavor %s", *result.FlavorId)
+			return &now
+		}
+		if signedFlavor.Lifecycle == nil {
+			continue
+		}
+		if flavorDeadline := signedFlavor.Lifecycle.NextDeadline(now); flavorDeadline != nil &&
+			(deadline == nil || flavorDeadline.Before(*deadline)) {
+			deadline = flavorDeadline
+		}
+	}
+	return deadline
+}
+
 func (v *Verifier) getCachedFlavors(hostId uuid.UUID, flavGrpId uuid.UUID) ([]hvs.SignedFlavor, error) {
 	defaultLog.Trace("hosttrust/verifier:getCachedFlavors() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:getCachedFlavors() Leaving")
@@ -223,7 +293,7 @@ func (v *Verifier) validateCachedFlavors(hostId uuid.UUID,
 	var trustCachesToDelete []uuid.UUID
 	for _, cachedFlavor := range cachedFlavors {
 		//TODO: change the signature verification depending on decision on signed flavors
-		report, err := v.FlavorVerifier.Verify(hostData, &cachedFlavor, v.SkipFlavorSignatureVerification)
+		report, err := v.verifyFlavor(hostData, &cachedFlavor)
 		if err != nil {
 			return hostTrustCache{}, errors.Wrap(err, "hosttrust/verifier:validateCachedFlavors() Error from flavor verifier")
 		}
@@ -245,6 +315,80 @@ func (v *Verifier) validateCachedFlavors(hostId uuid.UUID,
 	return htc, nil
 }
 
+// verifyFlavor verifies the host data against a flavor, then applies the lifecycle of the flavor to the report
+func (v *Verifier) verifyFlavor(hostData *hvs.HostManifest, signedFlavor *hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/verifier:verifyFlavor() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:verifyFlavor() Leaving")
+
+	fv := v.FlavorVerifier
+	if verifierCerts, imported := v.importedFlavorVerifierCerts(signedFlavor); imported {
+		var err error
+		if fv, err = flavorVerifier.NewVerifier(verifierCerts); err != nil {
+			return nil, errors.Wrap(err, "could not create the verifier of an imported flavor")
+		}
+	}
+	report, err := fv.Verify(hostData, signedFlavor, v.skipSignatureVerification(signedFlavor))
+	if err != nil {
+		return nil, err
+	}
+	return rules.NewFlavorActive(signedFlavor, time.Now()).Apply(*report), nil
+}
+
+// skipSignatureVerification returns true when the flavor is verified without checking its signature
+func (v *Verifier) skipSignatureVerification(signedFlavor *hvs.SignedFlavor) bool {
+	return v.SkipFlavorSignatureVerification || v.unsignedFlavors[signedFlavor.Flavor.Meta.ID]
+}
+
+// getVerifierCerts returns the certificates verifying the signature of a flavor
+func (v *Verifier) getVerifierCerts(signedFlavor *hvs.SignedFlavor) flavorVerifier.VerifierCertificates {
+	verifierCerts, _ := v.importedFlavorVerifierCerts(signedFlavor)
+	return verifierCerts
+}
+
+// importedFlavorVerifierCerts returns the certificates verifying the signature of a flavor and whether the flavor was
+// imported from another HVS. The imported flavors keep the signature of the HVS that created them, it is checked
+// against the imported flavor signing certificate matching it, which must chain to the trusted flavor CAs. The
+// imported CA certificates are only used as intermediates of that chain, never as roots.
+func (v *Verifier) importedFlavorVerifierCerts(signedFlavor *hvs.SignedFlavor) (flavorVerifier.VerifierCertificates, bool) {
+	defaultLog.Trace("hosttrust/verifier:importedFlavorVerifierCerts() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:importedFlavorVerifierCerts() Leaving")
+
+	verifierCerts := v.FlavorVerifier.GetVerifierCerts()
+	if v.SkipFlavorSignatureVerification {
+		return verifierCerts, false
+	}
+	_, importedSigningCerts, err := v.CertsStore.GetKeyAndCertificates(models.CertTypesImportedFlavorSigning.String())
+	if err != nil || len(importedSigningCerts) == 0 {
+		return verifierCerts, false
+	}
+	if verifierCerts.FlavorSigningCertificate != nil {
+		publicKey, ok := verifierCerts.FlavorSigningCertificate.PublicKey.(*rsa.PublicKey)
+		if ok && signedFlavor.Verify(publicKey) == nil {
+			return verifierCerts, false
+		}
+	}
+
+	for i := range importedSigningCerts {
+		signingCert := &importedSigningCerts[i]
+		publicKey, ok := signingCert.PublicKey.(*rsa.PublicKey)
+		if signingCert.IsCA || !ok || signedFlavor.Verify(publicKey) != nil {
+			continue
+		}
+		_, flavorCAs, _ := v.CertsStore.GetKeyAndCertificates(models.CaCertTypesFlavorCa.String())
+		intermediatePool := x509.NewCertPool()
+		for j := range importedSigningCerts {
+			if importedSigningCerts[j].IsCA {
+				intermediatePool.AddCert(&importedSigningCerts[j])
+			}
+		}
+		verifierCerts.FlavorSigningCertificate = signingCert
+		verifierCerts.FlavorCACertificates = crypt.GetCertPool(flavorCAs)
+		verifierCerts.FlavorIntermediateCertificates = intermediatePool
+		return verifierCerts, true
+	}
+	return verifierCerts, false
+}
+
 func (v *Verifier) refreshTrustReport(hostID uuid.UUID, cache *models.QuoteReportCache) (*models.HVSReport, error) {
 	defaultLog.Trace("hosttrust/verifier:refreshTrustReport() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:refreshTrustReport() Leaving")
@@ -267,9 +411,47 @@ func (v *Verifier) storeTrustReport(hostID uuid.UUID, trustReport *hvs.TrustRepo
 		Expiration:  samlReport.ExpiryTime,
 		Saml:        samlReport.Assertion,
 	}
+
+	// the trust status of the report being replaced tells whether the trust of the host changed
+	var previousTrusted *bool
+	if v.EventPublisher != nil {
+		previousReports, err := v.ReportStore.Search(&models.ReportFilterCriteria{HostID: hostID, LatestPerHost: true})
+		if err != nil {
+			log.WithError(err).Warnf("hosttrust/verifier:storeTrustReport() Failed to retrieve previous report of host %s", hostID)
+		} else if len(previousReports) > 0 {
+			trusted := previousReports[0].TrustReport.Trusted
+			previousTrusted = &trusted
+		}
+	}
+
 	report, err := v.ReportStore.Update(&hvsReport)
 	if err != nil {
 		log.WithError(err).Errorf("hosttrust/verifier:storeTrustReport() Failed to store Report")
+	} else if v.EventPublisher != nil {
+		v.publishReportEvents(report, previousTrusted)
 	}
 	return report
 }
+
+// publishReportEvents notifies the subscribers of a new report, and of a trust change when the report is the first
+// one of the host or when its trust status differs from the previous report
+func (v *Verifier) publishReportEvents(report *models.HVSReport, previousTrusted *bool) {
+	defaultLog.Trace("hosttrust/verifier:publishReportEvents() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:publishReportEvents() Leaving")
+
+	trusted := report.TrustReport.Trusted
+	reportId := report.ID
+	event := hvs.HostEvent{
+		Type:            hvs.HostEventNewReport,
+		HostId:          report.HostID,
+		Trusted:         &trusted,
+		PreviousTrusted: previousTrusted,
+		ReportId:        &reportId,
+	}
+	v.EventPublisher.Publish(event)
+
+	if previousTrusted == nil || *previousTrusted != trusted {
+		event.Type = hvs.HostEventTrustChanged
+		v.EventPublisher.Publish(event)
+	}
+}
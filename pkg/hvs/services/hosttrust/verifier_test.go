@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hosttrust
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru"
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
+	flavorVerifier "github.com/intel-secl/intel-secl/v4/pkg/lib/verifier"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/verifier/rules"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+const testImaLog = `10 91f34b5c671d73504b274a919661cf80dab1e127 ima-ng sha1:1801e1be3e65ef1eaa5c16617bec8f1274eaf6b3 boot_aggregate
+10 8b11bd7aa4efd3e9e8c1c3b1c5cb3f1d3e4bb0e5 ima-ng sha256:2c7dd3a1b0df0b1e0e09c6d1d2cd4e7c6a4b05a3e93e7e1e0ab0a21d9bc0a1f2 /usr/bin/bash`
+
+// newImaHostManifest returns the manifest of a linux host quoting PCR 0 and the PCR extended by its IMA log
+func newImaHostManifest(t *testing.T) *hvs.HostManifest {
+	imaLog, err := hvs.ParseImaLog(testImaLog)
+	assert.NoError(t, err)
+	_, imaPcrValue, err := imaLog.Replay("")
+	assert.NoError(t, err)
+
+	hostManifest := hvs.HostManifest{
+		HostInfo: taModel.HostInfo{OSName: "RedHatEnterprise"},
+		ImaLog:   imaLog,
+	}
+	hostManifest.PcrManifest.Sha1Pcrs = []hvs.HostManifestPcrs{
+		{Index: hvs.PCR0, Value: "3f95ecbb0bb8e66e54d3f9e4dbae8fe57fed96f0", PcrBank: hvs.SHA1},
+		{Index: hvs.PCR10, Value: imaPcrValue, PcrBank: hvs.SHA1},
+	}
+	return &hostManifest
+}
+
+// quotePcrs keeps the PCRs of the manifest that the host quotes when it is asked for the PCRs of the trust PCR list
+func quotePcrs(hostManifest *hvs.HostManifest, trustPcrList []int) {
+	var quotedPcrs []hvs.HostManifestPcrs
+	for _, pcr := range hostManifest.PcrManifest.Sha1Pcrs {
+		for _, pcrIndex := range trustPcrList {
+			if int(pcr.Index) == pcrIndex {
+				quotedPcrs = append(quotedPcrs, pcr)
+			}
+		}
+	}
+	hostManifest.PcrManifest.Sha1Pcrs = quotedPcrs
+}
+
+func TestGetTrustPcrListReportIma(t *testing.T) {
+	imaLogIntegrity, err := rules.NewImaLogIntegrity(hvs.FlavorPartIma)
+	assert.NoError(t, err)
+
+	hostManifest := newImaHostManifest(t)
+	imaResult, err := imaLogIntegrity.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.True(t, imaResult.Trusted)
+
+	platformResult := hvs.RuleResult{Trusted: true}
+	platformResult.Rule.ExpectedPcr = &hvs.FlavorPcrs{Pcr: hvs.Pcr{Index: int(hvs.PCR0), Bank: string(hvs.SHA1)}}
+	report := hvs.TrustReport{Results: []hvs.RuleResult{platformResult, *imaResult}}
+
+	trustPcrList := getTrustPcrListReport(hostManifest.HostInfo, &report)
+	assert.ElementsMatch(t, []int{int(hvs.PCR0), int(hvs.ImaPcrIndex), int(hvs.PCR15)}, trustPcrList)
+
+	// the refresh after the first report quotes the PCRs of the trust PCR list only
+	quotePcrs(hostManifest, trustPcrList)
+	imaResult, err = imaLogIntegrity.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.True(t, imaResult.Trusted)
+	assert.Len(t, imaResult.Faults, 0)
+
+	// the IMA PCR is quoted even when the first report did not find the IMA log
+	hostManifest = newImaHostManifest(t)
+	hostManifest.ImaLog = nil
+	imaResult, err = imaLogIntegrity.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Nil(t, imaResult.Rule.PCR)
+	report = hvs.TrustReport{Results: []hvs.RuleResult{platformResult, *imaResult}}
+	assert.Contains(t, getTrustPcrListReport(hostManifest.HostInfo, &report), int(hvs.ImaPcrIndex))
+}
+
+func TestVerifyCachedReportLifecycleDeadline(t *testing.T) {
+	now := time.Now()
+	gracePeriodEnd := now.Add(time.Hour)
+	expiresAt := now.Add(2 * time.Hour)
+	deprecatedFlavorId := uuid.New()
+	expiringFlavorId := uuid.New()
+
+	flavorStore := mocks.NewMockFlavorStore()
+	deprecatedFlavor := hvs.SignedFlavor{Lifecycle: &hvs.FlavorLifecycle{State: hvs.FlavorStateDeprecated, GracePeriodEnd: &gracePeriodEnd}}
+	deprecatedFlavor.Flavor.Meta.ID = deprecatedFlavorId
+	_, err := flavorStore.Create(&deprecatedFlavor)
+	assert.NoError(t, err)
+	expiringFlavor := hvs.SignedFlavor{Lifecycle: &hvs.FlavorLifecycle{State: hvs.FlavorStateActive, ExpiresAt: &expiresAt}}
+	expiringFlavor.Flavor.Meta.ID = expiringFlavorId
+	_, err = flavorStore.Create(&expiringFlavor)
+	assert.NoError(t, err)
+
+	hostTrustCache, err := lru.New(5)
+	assert.NoError(t, err)
+	v := &Verifier{
+		FlavorStore:      flavorStore,
+		FlavorGroupStore: &mocks.MockFlavorgroupStore{},
+		HostStore:        mocks.NewMockHostStore(),
+		ReportStore:      mocks.NewMockReportStore(),
+		HostTrustCache:   hostTrustCache,
+	}
+
+	// the deadline of the report is the earliest one of its flavors
+	trustReport := hvs.TrustReport{Trusted: true, Results: []hvs.RuleResult{
+		{FlavorId: &expiringFlavorId, Trusted: true},
+		{FlavorId: &deprecatedFlavorId, Trusted: true},
+		{FlavorId: &deprecatedFlavorId, Trusted: true},
+	}}
+	assert.Equal(t, &gracePeriodEnd, v.getLifecycleDeadline(&trustReport, now))
+	assert.Nil(t, v.getLifecycleDeadline(&hvs.TrustReport{}, now))
+
+	// a flavor that cannot be retrieved prevents the report from being reused
+	unknownFlavorId := uuid.New()
+	unknownReport := hvs.TrustReport{Results: []hvs.RuleResult{{FlavorId: &unknownFlavorId}}}
+	assert.Equal(t, &now, v.getLifecycleDeadline(&unknownReport, now))
+
+	// the cached report is not reused once the grace period of one of its flavors has ended, the flavors of the
+	// host are verified again and the host without flavorgroups gets no report
+	hostId := uuid.New()
+	hostManifest := hvs.HostManifest{QuoteDigest: "quote-digest"}
+	hostManifest.HostInfo.HardwareUUID = uuid.New().String()
+	passedDeadline := now.Add(-time.Minute)
+	hostTrustCache.Add(hostId, &models.QuoteReportCache{
+		QuoteDigest:       hostManifest.QuoteDigest,
+		TrustReport:       &trustReport,
+		LifecycleDeadline: &passedDeadline,
+	})
+	report, err := v.Verify(context.Background(), hostId, &hostManifest, false, true)
+	assert.NoError(t, err)
+	assert.Nil(t, report)
+}
+
+// newTestCertificate issues a certificate for a new RSA key, the certificate is self signed when no issuer is given
+func newTestCertificate(t *testing.T, name string, isCA bool, issuer *x509.Certificate, issuerKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if issuer == nil {
+		issuer, issuerKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return certificate, key
+}
+
+func TestImportedFlavorVerifierCerts(t *testing.T) {
+	flavorCaCertificate, flavorCaKey := newTestCertificate(t, "Flavor CA", true, nil, nil)
+	signingCertificate, _ := newTestCertificate(t, "Flavor Signing", false, flavorCaCertificate, flavorCaKey)
+	intermediateCertificate, intermediateKey := newTestCertificate(t, "Intermediate CA", true, flavorCaCertificate, flavorCaKey)
+	importedCertificate, importedKey := newTestCertificate(t, "Other HVS Flavor Signing", false, intermediateCertificate, intermediateKey)
+	rogueCaCertificate, rogueCaKey := newTestCertificate(t, "Rogue CA", true, nil, nil)
+	rogueCertificate, rogueKey := newTestCertificate(t, "Rogue Flavor Signing", false, rogueCaCertificate, rogueCaKey)
+
+	flavorCAs := crypt.GetCertPool([]x509.Certificate{*flavorCaCertificate})
+	fv, err := flavorVerifier.NewVerifier(flavorVerifier.VerifierCertificates{
+		PrivacyCACertificates:    x509.NewCertPool(),
+		AssetTagCACertificates:   x509.NewCertPool(),
+		FlavorSigningCertificate: signingCertificate,
+		FlavorCACertificates:     flavorCAs,
+	})
+	assert.NoError(t, err)
+	// a CA certificate stored along with the imported flavor signing certificates is never trusted as a root
+	v := &Verifier{
+		FlavorVerifier: fv,
+		CertsStore: models.CertificatesStore{
+			models.CaCertTypesFlavorCa.String(): &models.CertificateStore{
+				Certificates: []x509.Certificate{*flavorCaCertificate},
+			},
+			models.CertTypesImportedFlavorSigning.String(): &models.CertificateStore{
+				Certificates: []x509.Certificate{*importedCertificate, *intermediateCertificate, *rogueCertificate, *rogueCaCertificate},
+			},
+		},
+	}
+
+	newFlavor := func(key *rsa.PrivateKey) *hvs.SignedFlavor {
+		flavor := hvs.Flavor{}
+		flavor.Meta.ID = uuid.New()
+		signedFlavor, err := hvs.NewSignedFlavor(&flavor, key)
+		assert.NoError(t, err)
+		return signedFlavor
+	}
+	applyFlavorTrusted := func(signedFlavor *hvs.SignedFlavor) *hvs.RuleResult {
+		verifierCerts, imported := v.importedFlavorVerifierCerts(signedFlavor)
+		assert.True(t, imported)
+		rule, err := rules.NewFlavorTrustedWithIntermediates(signedFlavor, verifierCerts.FlavorSigningCertificate,
+			verifierCerts.FlavorCACertificates, verifierCerts.FlavorIntermediateCertificates, hvs.FlavorPartPlatform)
+		assert.NoError(t, err)
+		result, err := rule.Apply(&hvs.HostManifest{})
+		assert.NoError(t, err)
+		return result
+	}
+
+	assert.Empty(t, applyFlavorTrusted(newFlavor(importedKey)).Faults)
+	assert.NotEmpty(t, applyFlavorTrusted(newFlavor(rogueKey)).Faults)
+}
+
+func TestVerifyUnsignedCandidateFlavor(t *testing.T) {
+	flavorCaCertificate, flavorCaKey := newTestCertificate(t, "Flavor CA", true, nil, nil)
+	signingCertificate, _ := newTestCertificate(t, "Flavor Signing", false, flavorCaCertificate, flavorCaKey)
+	fv, err := flavorVerifier.NewVerifier(flavorVerifier.VerifierCertificates{
+		PrivacyCACertificates:    x509.NewCertPool(),
+		AssetTagCACertificates:   x509.NewCertPool(),
+		FlavorSigningCertificate: signingCertificate,
+		FlavorCACertificates:     crypt.GetCertPool([]x509.Certificate{*flavorCaCertificate}),
+	})
+	assert.NoError(t, err)
+	v := &Verifier{FlavorVerifier: fv, CertsStore: models.CertificatesStore{}}
+
+	candidate := hvs.SignedFlavor{}
+	candidate.Flavor.Meta.ID = uuid.New()
+	candidate.Flavor.Meta.Description = map[string]interface{}{
+		hvs.FlavorPartDescription: hvs.FlavorPartIma.String(),
+		hvs.Label:                 "candidate_ima_flavor",
+	}
+	candidate.Flavor.Ima = &hvs.Ima{}
+	flavorTrustedFaults := func() int {
+		hostManifest := newImaHostManifest(t)
+		hostManifest.HostInfo.OSType = taModel.OsTypeLinux
+		report, err := v.verifyFlavor(hostManifest, &candidate)
+		assert.NoError(t, err)
+		faults := 0
+		for _, result := range report.Results {
+			if result.Rule.Name == constants.RuleFlavorTrusted {
+				faults += len(result.Faults)
+			}
+		}
+		return faults
+	}
+
+	// the signature of a flavor is checked unless it is the content of a new candidate flavor of a simulation
+	assert.NotZero(t, flavorTrustedFaults())
+	v.unsignedFlavors = map[uuid.UUID]bool{candidate.Flavor.Meta.ID: true}
+	assert.Zero(t, flavorTrustedFaults())
+}
@@ -10,6 +10,7 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 
@@ -18,7 +19,9 @@ import (
 
 // HostReportRefresher runs in the background and periodically queries HVS'
 // reports to see if they have been expired.  If so, they are passed to
-// the HostTrustManager queue to be updated.
+// the HostTrustManager queue to be updated.  Hosts with a refresh schedule,
+// set on the host or on its flavor groups, are also queued once their report
+// is older than the refresh interval of the schedule.
 type HostReportRefresher interface {
 	Run() error
 	Stop() error
@@ -27,6 +30,9 @@ type HostReportRefresher interface {
 var (
 	firstFromTime, _ = time.Parse(time.RFC3339, "1970-01-01T00:00:00Z") // i.e. epoch
 	defaultLog       = commLog.GetDefaultLogger()
+
+	// minScheduledRefreshWait keeps HRRS from polling the database continuously when refresh schedules are due
+	minScheduledRefreshWait = time.Second
 )
 
 func NewHostReportRefresher(cfg HRRSConfig, reportStore domain.ReportStore, hostTrustManager domain.HostTrustManager) (HostReportRefresher, error) {
@@ -36,6 +42,7 @@ func NewHostReportRefresher(cfg HRRSConfig, reportStore domain.ReportStore, host
 		hostTrustManager: hostTrustManager,
 		cfg:              cfg,
 		fromTime:         firstFromTime,
+		scheduledAt:      make(map[uuid.UUID]time.Time),
 	}, nil
 }
 
@@ -45,6 +52,11 @@ type hostReportRefresherImpl struct {
 	cfg              HRRSConfig
 	ctx              context.Context
 	fromTime         time.Time
+	// nextExpiredRefresh is the time of the next search for expired reports
+	nextExpiredRefresh time.Time
+	// scheduledAt holds the last time a host was queued because of its refresh schedule, so that it is not queued
+	// again before its new report is created
+	scheduledAt map[uuid.UUID]time.Time
 }
 
 func (refresher *hostReportRefresherImpl) Run() error {
@@ -66,14 +78,10 @@ func (refresher *hostReportRefresherImpl) Run() error {
 			}
 		}()
 		for {
-			err := refresher.refreshReports()
-			if err != nil {
-				// log any errors, but do not stop trying to refresh reports
-				defaultLog.Errorf("HRRS encountered an error while refreshing reports...\n%+v\n", err)
-			}
+			wait := refresher.refresh(time.Now().UTC())
 
 			select {
-			case <-time.After(refresher.cfg.RefreshPeriod):
+			case <-time.After(wait):
 				// continue with the loop and refresh reports again
 			case <-refresher.ctx.Done():
 				defaultLog.Info("The HRRS has been stopped and will now exit")
@@ -94,6 +102,78 @@ func (refresher *hostReportRefresherImpl) Stop() error {
 	return nil
 }
 
+// refresh queues the hosts with expired reports when the refresh period has elapsed and the hosts that are due
+// according to their refresh schedule.  It returns how long to wait before the next refresh.
+func (refresher *hostReportRefresherImpl) refresh(now time.Time) time.Duration {
+
+	if !now.Before(refresher.nextExpiredRefresh) {
+		err := refresher.refreshReports()
+		if err != nil {
+			// log any errors, but do not stop trying to refresh reports
+			defaultLog.Errorf("HRRS encountered an error while refreshing reports...\n%+v\n", err)
+		}
+		refresher.nextExpiredRefresh = now.Add(refresher.cfg.RefreshPeriod)
+	}
+
+	next := refresher.nextExpiredRefresh
+	nextScheduled, err := refresher.refreshScheduledReports(now)
+	if err != nil {
+		defaultLog.Errorf("HRRS encountered an error while refreshing scheduled reports...\n%+v\n", err)
+	} else if !nextScheduled.IsZero() && nextScheduled.Before(next) {
+		next = nextScheduled
+	}
+
+	wait := next.Sub(now)
+	if wait < minScheduledRefreshWait {
+		wait = minScheduledRefreshWait
+	}
+	return wait
+}
+
+// refreshScheduledReports queues the hosts whose report is older than the refresh interval of their schedule, each
+// host being delayed by its offset within the jitter of the schedule.  It returns the time the next host is due, or
+// the zero time when no host has a refresh schedule.
+func (refresher *hostReportRefresherImpl) refreshScheduledReports(now time.Time) (time.Time, error) {
+
+	schedules, err := refresher.reportStore.FindHostRefreshSchedules()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "An error occurred while HRRS searched for host refresh schedules")
+	}
+
+	var hostIDs []uuid.UUID
+	var next time.Time
+	scheduledAt := make(map[uuid.UUID]time.Time, len(schedules))
+	for _, schedule := range schedules {
+		lastRefresh := schedule.LastRefresh
+		if queued, ok := refresher.scheduledAt[schedule.HostID]; ok && queued.After(lastRefresh) {
+			lastRefresh = queued
+			scheduledAt[schedule.HostID] = queued
+		}
+
+		dueAt := lastRefresh.Add(schedule.Schedule.Interval() + schedule.Schedule.JitterOffset(schedule.HostID))
+		if !dueAt.After(now) {
+			hostIDs = append(hostIDs, schedule.HostID)
+			scheduledAt[schedule.HostID] = now
+			dueAt = now.Add(schedule.Schedule.Interval() + schedule.Schedule.JitterOffset(schedule.HostID))
+		}
+		if next.IsZero() || dueAt.Before(next) {
+			next = dueAt
+		}
+	}
+	// hosts that no longer have a schedule are forgotten
+	refresher.scheduledAt = scheduledAt
+
+	if len(hostIDs) > 0 {
+		err = refresher.hostTrustManager.VerifyHostsAsync(hostIDs, true, true)
+		if err != nil {
+			return next, errors.Wrap(err, "HRRS encountered an error calling the host trust manager")
+		}
+		defaultLog.Infof("HRRS queued %d hosts from their refresh schedule", len(hostIDs))
+	}
+
+	return next, nil
+}
+
 // Uses an 'expiration time window' to find expired reports.
 //
 // - On the first pass, the window is from the epoch to the next 'refresh period' from now.
@@ -6,6 +6,8 @@
 package hrrs
 
 import (
+	"context"
+
 	log "github.com/sirupsen/logrus"
 	"testing"
 	"time"
@@ -100,6 +102,60 @@ func TestHostReportRefresher(t *testing.T) {
 	}
 }
 
+func TestHostReportRefresherSchedule(t *testing.T) {
+
+	cfg := HRRSConfig{
+		RefreshPeriod: twentyFourHours,
+	}
+
+	reportStore := mocks.NewEmptyMockReportStore().(*mocks.MockReportStore)
+	now := time.Now().UTC()
+
+	// the report of the first host is older than its refresh interval and does not expire before the next search
+	// for expired reports, expect that the host is queued because of its schedule
+	staleHostID := uuid.New()
+	staleCreated := now.Add(-time.Hour)
+	_, _ = reportStore.Create(&models.HVSReport{
+		ID:         uuid.New(),
+		HostID:     staleHostID,
+		CreatedAt:  staleCreated,
+		Expiration: now.Add(twentyFourHours * 2),
+	})
+	reportStore.SetRefreshSchedule(staleHostID, hvs.RefreshSchedule{IntervalSeconds: 600})
+
+	// the report of the second host is newer than its refresh interval
+	freshHostID := uuid.New()
+	freshCreated := now.Add(-time.Minute)
+	_, _ = reportStore.Create(&models.HVSReport{
+		ID:         uuid.New(),
+		HostID:     freshHostID,
+		CreatedAt:  freshCreated,
+		Expiration: now.Add(twentyFourHours * 2),
+	})
+	freshSchedule := hvs.RefreshSchedule{IntervalSeconds: 600, JitterSeconds: 60}
+	reportStore.SetRefreshSchedule(freshHostID, freshSchedule)
+
+	hostTrustManager := MockHostTrustManager{
+		reportStore: reportStore,
+	}
+	refresher, err := NewHostReportRefresher(cfg, reportStore, hostTrustManager)
+	assert.NoError(t, err)
+
+	next, err := refresher.(*hostReportRefresherImpl).refreshScheduledReports(now)
+	assert.NoError(t, err)
+	assert.Equal(t, freshCreated.Add(freshSchedule.Interval()+freshSchedule.JitterOffset(freshHostID)), next)
+
+	reports, err := reportStore.Search(&models.ReportFilterCriteria{HostID: staleHostID})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.True(t, reports[0].CreatedAt.After(staleCreated))
+
+	reports, err = reportStore.Search(&models.ReportFilterCriteria{HostID: freshHostID})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, freshCreated, reports[0].CreatedAt)
+}
+
 //-------------------------------------------------------------------------------------------------
 // M O C K   H O S T   T R U S T   M A N A G E R
 //-------------------------------------------------------------------------------------------------
@@ -107,7 +163,7 @@ type MockHostTrustManager struct {
 	reportStore domain.ReportStore
 }
 
-func (htm MockHostTrustManager) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
+func (htm MockHostTrustManager) VerifyHost(ctx context.Context, hostId uuid.UUID, fetchHostData bool, preferHashMatch bool) (*models.HVSReport, error) {
 	return nil, errors.New("VerifyHost is not implemented")
 }
 
@@ -115,6 +171,14 @@ func (htm MockHostTrustManager) ProcessQueue() error {
 	return errors.New("ProcessQueue is not implemented")
 }
 
+func (htm MockHostTrustManager) VerifyHostDataAsync(host hvs.Host, hostData *hvs.HostManifest, preferHashMatch bool) error {
+	return errors.New("VerifyHostDataAsync is not implemented")
+}
+
+func (htm MockHostTrustManager) SimulateHostTrust(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroup hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	return nil, errors.New("SimulateHostTrust is not implemented")
+}
+
 func (htm MockHostTrustManager) VerifyHostsAsync(hostIDs []uuid.UUID, fetchHostData, preferHashMatch bool) error {
 
 	for _, hostID := range hostIDs {
@@ -0,0 +1,339 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/models"
+	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+const (
+	// defaultProgressInterval is the minimum delay between two progress updates of a job in the store
+	defaultProgressInterval = time.Second
+	// defaultCancelPollInterval is the delay between two checks of the store for the cancellation of a running job
+	defaultCancelPollInterval = 5 * time.Second
+
+	interruptedJobError = "Job was interrupted by a restart of the service"
+	stoppedJobError     = "Job was interrupted by the shutdown of the service"
+)
+
+// Manager runs the jobs in the background and persists their progress and outcome in the JobStore. The jobs are
+// only run by the instance that accepted them and are recorded with the owner of the Manager, the jobs the owner left
+// unfinished before a restart are marked as failed when the Manager is created. A job cancelled through another
+// instance is stopped by the instance running it once it finds the job cancelled in the store.
+type Manager struct {
+	store              domain.JobStore
+	owner              string
+	progressInterval   time.Duration
+	cancelPollInterval time.Duration
+
+	mtx     sync.Mutex
+	running map[uuid.UUID]*runningJob
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// runningJob holds the state of a job run by this instance, mtx serializes the updates of the job in the store
+type runningJob struct {
+	mtx         sync.Mutex
+	job         hvs.Job
+	cancel      context.CancelFunc
+	cancelled   bool
+	lastPersist time.Time
+}
+
+// NewManager creates a Manager for the given owner, which identifies the service instance across restarts, and marks
+// the jobs the owner left pending or running as failed. The jobs of the other instances are left untouched.
+func NewManager(store domain.JobStore, owner string) (*Manager, error) {
+	defaultLog.Trace("jobs/manager:NewManager() Entering")
+	defer defaultLog.Trace("jobs/manager:NewManager() Leaving")
+
+	if owner == "" {
+		return nil, errors.New("jobs/manager:NewManager() Job owner must be provided")
+	}
+	m := &Manager{
+		store:              store,
+		owner:              owner,
+		progressInterval:   defaultProgressInterval,
+		cancelPollInterval: defaultCancelPollInterval,
+		running:            map[uuid.UUID]*runningJob{},
+	}
+	if err := m.failInterruptedJobs(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Submit creates a job of the given type and runs fn in the background
+func (m *Manager) Submit(jobType hvs.JobType, fn domain.JobFunc) (*hvs.Job, error) {
+	defaultLog.Trace("jobs/manager:Submit() Entering")
+	defer defaultLog.Trace("jobs/manager:Submit() Leaving")
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.stopped {
+		return nil, errors.New("jobs/manager:Submit() Job manager is stopped")
+	}
+
+	job, err := m.store.Create(&hvs.Job{
+		Id:     uuid.New(),
+		Type:   jobType,
+		Status: hvs.JobStatusPending,
+		Owner:  m.owner,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "jobs/manager:Submit() Failed to create job")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rj := &runningJob{job: *job, cancel: cancel}
+	m.running[job.Id] = rj
+	m.wg.Add(1)
+	go m.run(ctx, rj, fn)
+
+	defaultLog.Infof("jobs/manager:Submit() Job %s of type %s submitted", job.Id, job.Type)
+	return job, nil
+}
+
+// Cancel stops a job that is still pending or running and marks it as cancelled. A job that is not run by this
+// instance is only marked as cancelled, the instance running it stops it when it finds it cancelled in the store.
+func (m *Manager) Cancel(id uuid.UUID) (*hvs.Job, error) {
+	defaultLog.Trace("jobs/manager:Cancel() Entering")
+	defer defaultLog.Trace("jobs/manager:Cancel() Leaving")
+
+	m.mtx.Lock()
+	rj, ok := m.running[id]
+	m.mtx.Unlock()
+
+	if !ok {
+		job, err := m.store.Retrieve(id)
+		if err != nil {
+			return nil, errors.Wrap(err, "jobs/manager:Cancel() Failed to retrieve job")
+		}
+		if job.Status.Done() {
+			return nil, errors.Errorf("jobs/manager:Cancel() Job %s has already finished", id)
+		}
+		job.Status = hvs.JobStatusCancelled
+		if err = m.store.Update(job); err != nil {
+			return nil, errors.Wrap(err, "jobs/manager:Cancel() Failed to update job")
+		}
+		return job, nil
+	}
+
+	rj.mtx.Lock()
+	defer rj.mtx.Unlock()
+	if rj.job.Status.Done() {
+		return nil, errors.Errorf("jobs/manager:Cancel() Job %s has already finished", id)
+	}
+	rj.cancelled = true
+	rj.cancel()
+	rj.job.Status = hvs.JobStatusCancelled
+	// the job may already have been cancelled through another instance
+	if err := m.store.Update(&rj.job); err != nil && errors.Cause(err) != domain.ErrJobCancelled {
+		return nil, errors.Wrap(err, "jobs/manager:Cancel() Failed to update job")
+	}
+	defaultLog.Infof("jobs/manager:Cancel() Job %s cancelled", id)
+	job := rj.job
+	return &job, nil
+}
+
+// Stop cancels the running jobs and waits for them to return, the jobs that are interrupted are marked as failed
+func (m *Manager) Stop() {
+	defaultLog.Trace("jobs/manager:Stop() Entering")
+	defer defaultLog.Trace("jobs/manager:Stop() Leaving")
+
+	m.mtx.Lock()
+	m.stopped = true
+	for _, rj := range m.running {
+		rj.cancel()
+	}
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
+
+func (m *Manager) run(ctx context.Context, rj *runningJob, fn domain.JobFunc) {
+	defer m.wg.Done()
+	defer func() {
+		m.mtx.Lock()
+		delete(m.running, rj.job.Id)
+		m.mtx.Unlock()
+		rj.cancel()
+	}()
+
+	rj.mtx.Lock()
+	if !rj.cancelled {
+		rj.job.Status = hvs.JobStatusRunning
+		m.persist(rj)
+	}
+	rj.mtx.Unlock()
+
+	m.wg.Add(1)
+	go m.pollCancellation(ctx, rj)
+
+	result, err := m.call(ctx, rj, fn)
+
+	rj.mtx.Lock()
+	defer rj.mtx.Unlock()
+	if rj.cancelled {
+		// the job was cancelled, its result is dropped
+		return
+	}
+
+	if result != nil {
+		b, merr := json.Marshal(result)
+		if merr == nil {
+			rj.job.Result = b
+		} else if err == nil {
+			err = errors.Wrap(merr, "Failed to serialize job result")
+		}
+	}
+	m.mtx.Lock()
+	stopped := m.stopped
+	m.mtx.Unlock()
+	switch {
+	case err != nil && stopped && ctx.Err() != nil:
+		rj.job.Status = hvs.JobStatusFailed
+		rj.job.Error = stoppedJobError
+	case err != nil:
+		rj.job.Status = hvs.JobStatusFailed
+		rj.job.Error = err.Error()
+	default:
+		rj.job.Status = hvs.JobStatusCompleted
+		rj.job.Error = ""
+		if rj.job.Total > 0 {
+			rj.job.Processed = rj.job.Total
+		}
+	}
+	m.persist(rj)
+	defaultLog.Infof("jobs/manager:run() Job %s of type %s finished with status %s", rj.job.Id, rj.job.Type, rj.job.Status)
+}
+
+// call runs fn, turning a panic into an error so that the job is marked as failed
+func (m *Manager) call(ctx context.Context, rj *runningJob, fn domain.JobFunc) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			defaultLog.Errorf("jobs/manager:call() Job %s panicked: %v\n%s", rj.job.Id, r, debug.Stack())
+			result, err = nil, errors.New(fmt.Sprintf("Job failed unexpectedly: %v", r))
+		}
+	}()
+	return fn(ctx, func(processed, total int, partial interface{}) {
+		m.report(rj, processed, total, partial)
+	})
+}
+
+// report updates the progress of a job, the store is updated at most once per progress interval
+func (m *Manager) report(rj *runningJob, processed, total int, partial interface{}) {
+	rj.mtx.Lock()
+	defer rj.mtx.Unlock()
+	if rj.cancelled {
+		return
+	}
+
+	rj.job.Processed = processed
+	rj.job.Total = total
+	if partial != nil {
+		if result, err := json.Marshal(partial); err != nil {
+			defaultLog.WithError(err).Warnf("jobs/manager:report() Failed to serialize partial result of job %s", rj.job.Id)
+		} else {
+			rj.job.Result = result
+		}
+	}
+	if time.Since(rj.lastPersist) >= m.progressInterval {
+		m.persist(rj)
+	}
+}
+
+// persist stores the current state of a running job, the caller must hold the lock of the job. The job is stopped
+// if it was cancelled in the store.
+func (m *Manager) persist(rj *runningJob) {
+	if err := m.store.Update(&rj.job); err != nil {
+		if errors.Cause(err) == domain.ErrJobCancelled {
+			m.cancelled(rj)
+			return
+		}
+		defaultLog.WithError(err).Errorf("jobs/manager:persist() Failed to update job %s", rj.job.Id)
+		return
+	}
+	rj.lastPersist = time.Now()
+}
+
+// pollCancellation checks the store until the job returns and stops the job once it is found cancelled, which
+// happens when the job is cancelled through another instance
+func (m *Manager) pollCancellation(ctx context.Context, rj *runningJob) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.cancelPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		job, err := m.store.Retrieve(rj.job.Id)
+		if err != nil {
+			defaultLog.WithError(err).Warnf("jobs/manager:pollCancellation() Failed to retrieve job %s", rj.job.Id)
+			continue
+		}
+		if job.Status == hvs.JobStatusCancelled {
+			rj.mtx.Lock()
+			m.cancelled(rj)
+			rj.mtx.Unlock()
+			return
+		}
+	}
+}
+
+// cancelled stops a job that was cancelled in the store, the caller must hold the lock of the job
+func (m *Manager) cancelled(rj *runningJob) {
+	if rj.cancelled {
+		return
+	}
+	rj.cancelled = true
+	rj.cancel()
+	rj.job.Status = hvs.JobStatusCancelled
+	defaultLog.Infof("jobs/manager:cancelled() Job %s was cancelled by another instance", rj.job.Id)
+}
+
+func (m *Manager) failInterruptedJobs() error {
+	defaultLog.Trace("jobs/manager:failInterruptedJobs() Entering")
+	defer defaultLog.Trace("jobs/manager:failInterruptedJobs() Leaving")
+
+	for {
+		jobs, err := m.store.Search(&models.JobFilterCriteria{
+			Status: []hvs.JobStatus{hvs.JobStatusPending, hvs.JobStatusRunning},
+			Owner:  m.owner,
+		})
+		if err != nil {
+			return errors.Wrap(err, "jobs/manager:failInterruptedJobs() Failed to search interrupted jobs")
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+		for i := range jobs {
+			jobs[i].Status = hvs.JobStatusFailed
+			jobs[i].Error = interruptedJobError
+			if err = m.store.Update(&jobs[i]); err != nil {
+				return errors.Wrap(err, "jobs/manager:failInterruptedJobs() Failed to update interrupted job")
+			}
+			defaultLog.Warnf("jobs/manager:failInterruptedJobs() Job %s of type %s was interrupted", jobs[i].Id, jobs[i].Type)
+		}
+	}
+}
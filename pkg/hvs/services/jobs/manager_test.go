@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJobOwner = "hvs-test"
+
+func waitForStatus(t *testing.T, store domain.JobStore, job *hvs.Job, status hvs.JobStatus) *hvs.Job {
+	var current *hvs.Job
+	assert.Eventually(t, func() bool {
+		var err error
+		current, err = store.Retrieve(job.Id)
+		return err == nil && current.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return current
+}
+
+func TestManagerFailsInterruptedJobs(t *testing.T) {
+	store := mocks.NewMockJobStore()
+	running, err := store.Create(&hvs.Job{Type: hvs.JobTypeFlavorCreate, Status: hvs.JobStatusRunning, Owner: testJobOwner})
+	assert.NoError(t, err)
+	completed, err := store.Create(&hvs.Job{Type: hvs.JobTypeFlavorCreate, Status: hvs.JobStatusCompleted, Owner: testJobOwner})
+	assert.NoError(t, err)
+	// the jobs run by another instance are not interrupted by the restart of this instance
+	otherRunning, err := store.Create(&hvs.Job{Type: hvs.JobTypeFlavorCreate, Status: hvs.JobStatusRunning, Owner: "hvs-other"})
+	assert.NoError(t, err)
+
+	_, err = NewManager(store, testJobOwner)
+	assert.NoError(t, err)
+
+	job, err := store.Retrieve(running.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusFailed, job.Status)
+	assert.Equal(t, interruptedJobError, job.Error)
+
+	job, err = store.Retrieve(completed.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusCompleted, job.Status)
+
+	job, err = store.Retrieve(otherRunning.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusRunning, job.Status)
+
+	_, err = NewManager(store, "")
+	assert.Error(t, err)
+}
+
+func TestManagerReportsProgress(t *testing.T) {
+	store := mocks.NewMockJobStore()
+	m, err := NewManager(store, testJobOwner)
+	assert.NoError(t, err)
+	m.progressInterval = 0
+	defer m.Stop()
+
+	proceed := make(chan struct{})
+	job, err := m.Submit(hvs.JobTypeHostBulkCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		report(1, 3, []string{"host1"})
+		<-proceed
+		return []string{"host1", "host2", "host3"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusPending, job.Status)
+	assert.Equal(t, testJobOwner, job.Owner)
+
+	assert.Eventually(t, func() bool {
+		current, err := store.Retrieve(job.Id)
+		return err == nil && current.Processed == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	current, _ := store.Retrieve(job.Id)
+	assert.Equal(t, hvs.JobStatusRunning, current.Status)
+	assert.Equal(t, 3, current.Total)
+	assert.JSONEq(t, `["host1"]`, string(current.Result))
+
+	close(proceed)
+	current = waitForStatus(t, store, job, hvs.JobStatusCompleted)
+	assert.Equal(t, 3, current.Processed)
+	var result []string
+	assert.NoError(t, json.Unmarshal(current.Result, &result))
+	assert.Len(t, result, 3)
+}
+
+func TestManagerFailsJobs(t *testing.T) {
+	store := mocks.NewMockJobStore()
+	m, err := NewManager(store, testJobOwner)
+	assert.NoError(t, err)
+	defer m.Stop()
+
+	failed, err := m.Submit(hvs.JobTypeReportCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		return nil, errors.New("Host is not in CONNECTED state")
+	})
+	assert.NoError(t, err)
+	panicked, err := m.Submit(hvs.JobTypeReportCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		panic("unexpected")
+	})
+	assert.NoError(t, err)
+
+	job := waitForStatus(t, store, failed, hvs.JobStatusFailed)
+	assert.Equal(t, "Host is not in CONNECTED state", job.Error)
+	job = waitForStatus(t, store, panicked, hvs.JobStatusFailed)
+	assert.Contains(t, job.Error, "unexpected")
+}
+
+func TestManagerCancelAndStop(t *testing.T) {
+	store := mocks.NewMockJobStore()
+	m, err := NewManager(store, testJobOwner)
+	assert.NoError(t, err)
+
+	blocking := func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	cancelled, err := m.Submit(hvs.JobTypeTagDeploy, blocking)
+	assert.NoError(t, err)
+	stopped, err := m.Submit(hvs.JobTypeTagDeploy, blocking)
+	assert.NoError(t, err)
+	waitForStatus(t, store, cancelled, hvs.JobStatusRunning)
+
+	job, err := m.Cancel(cancelled.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusCancelled, job.Status)
+	_, err = m.Cancel(cancelled.Id)
+	assert.Error(t, err)
+
+	m.Stop()
+	job, err = store.Retrieve(cancelled.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusCancelled, job.Status)
+	job, err = store.Retrieve(stopped.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusFailed, job.Status)
+	assert.Equal(t, stoppedJobError, job.Error)
+
+	_, err = m.Submit(hvs.JobTypeTagDeploy, blocking)
+	assert.Error(t, err)
+}
+
+func TestManagerStopsJobsCancelledByAnotherInstance(t *testing.T) {
+	store := mocks.NewMockJobStore()
+	m, err := NewManager(store, testJobOwner)
+	assert.NoError(t, err)
+	m.progressInterval = 0
+	m.cancelPollInterval = 10 * time.Millisecond
+	other, err := NewManager(store, "hvs-other")
+	assert.NoError(t, err)
+
+	returned := make(chan struct{})
+	polled, err := m.Submit(hvs.JobTypeTagDeploy, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		defer close(returned)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.NoError(t, err)
+	waitForStatus(t, store, polled, hvs.JobStatusRunning)
+
+	job, err := other.Cancel(polled.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, hvs.JobStatusCancelled, job.Status)
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job cancelled by another instance was not stopped")
+	}
+
+	// a job reporting its progress finds out about the cancellation without waiting for the store to be polled
+	m.cancelPollInterval = time.Hour
+	proceed := make(chan struct{})
+	reported, err := m.Submit(hvs.JobTypeHostBulkCreate, func(ctx context.Context, report domain.JobProgressFunc) (interface{}, error) {
+		<-proceed
+		report(1, 2, []string{"host1"})
+		if ctx.Err() == nil {
+			return nil, errors.New("job was not stopped")
+		}
+		return []string{"host1"}, nil
+	})
+	assert.NoError(t, err)
+	waitForStatus(t, store, reported, hvs.JobStatusRunning)
+	_, err = other.Cancel(reported.Id)
+	assert.NoError(t, err)
+	close(proceed)
+
+	m.Stop()
+	for _, submitted := range []*hvs.Job{polled, reported} {
+		job, err = store.Retrieve(submitted.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, hvs.JobStatusCancelled, job.Status)
+		assert.Empty(t, job.Error)
+		assert.Equal(t, 0, job.Processed)
+	}
+}
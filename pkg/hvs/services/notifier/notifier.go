@@ -0,0 +1,349 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain"
+	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+const (
+	// SignatureHeader carries the signature of a delivery in the form "t=<unix time>,v1=<hex HMAC-SHA256>". The
+	// HMAC is computed with the secret of the subscription over "<unix time>.<body>".
+	SignatureHeader = "X-Hvs-Signature"
+	EventIdHeader   = "X-Hvs-Event-Id"
+	EventTypeHeader = "X-Hvs-Event-Type"
+
+	defaultMaxAttempts     = 5
+	defaultRetryBackoff    = 10 * time.Second
+	defaultWorkers         = 4
+	defaultEventBufferSize = 1000
+
+	deliveryTimeout = 10 * time.Second
+)
+
+// Config holds the stores and the connection settings of the notifier
+type Config struct {
+	SubscriptionStore domain.SubscriptionStore
+	HostStore         domain.HostStore
+	// TLSConfig is used to connect to the webhooks and to the NATS servers
+	TLSConfig       *tls.Config
+	NatsServers     []string
+	NatsCredentials string
+	// MaxAttempts is the number of times a delivery is attempted, the delay between the attempts starts at
+	// RetryBackoff and doubles after each failure
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Workers      int
+}
+
+// Service delivers the host events to the matching subscriptions. Events are queued by Publish and delivered in the
+// background, so that the verification and the data fetches are not slowed down by the subscribers.
+type Service struct {
+	subscriptionStore domain.SubscriptionStore
+	hostStore         domain.HostStore
+	httpClient        *http.Client
+	tlsConfig         *tls.Config
+	natsServers       []string
+	natsCredentials   string
+	maxAttempts       int
+	retryBackoff      time.Duration
+
+	natsConn *nats.Conn
+	natsMtx  sync.Mutex
+
+	events chan hvs.HostEvent
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewService creates the notifier and starts its workers
+func NewService(cfg Config) (*Service, error) {
+	defaultLog.Trace("notifier/notifier:NewService() Entering")
+	defer defaultLog.Trace("notifier/notifier:NewService() Leaving")
+
+	if cfg.SubscriptionStore == nil {
+		return nil, errors.New("subscription store cannot be empty")
+	}
+	if cfg.HostStore == nil {
+		return nil, errors.New("host store cannot be empty")
+	}
+
+	svc := &Service{
+		subscriptionStore: cfg.SubscriptionStore,
+		hostStore:         cfg.HostStore,
+		httpClient: &http.Client{
+			Timeout:   deliveryTimeout,
+			Transport: &http.Transport{TLSClientConfig: cfg.TLSConfig},
+		},
+		tlsConfig:       cfg.TLSConfig,
+		natsServers:     cfg.NatsServers,
+		natsCredentials: cfg.NatsCredentials,
+		maxAttempts:     cfg.MaxAttempts,
+		retryBackoff:    cfg.RetryBackoff,
+		events:          make(chan hvs.HostEvent, defaultEventBufferSize),
+		quit:            make(chan struct{}),
+	}
+	if svc.maxAttempts <= 0 {
+		svc.maxAttempts = defaultMaxAttempts
+	}
+	if svc.retryBackoff <= 0 {
+		svc.retryBackoff = defaultRetryBackoff
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	for i := 0; i < workers; i++ {
+		svc.wg.Add(1)
+		go svc.processEvents()
+	}
+	return svc, nil
+}
+
+// Publish queues an event for delivery. The event is dropped if the queue is full.
+func (svc *Service) Publish(event hvs.HostEvent) {
+	if event.Id == uuid.Nil {
+		event.Id = uuid.New()
+	}
+	if event.Created.IsZero() {
+		event.Created = time.Now().UTC()
+	}
+	select {
+	case svc.events <- event:
+	default:
+		defaultLog.Errorf("notifier/notifier:Publish() Event queue is full, dropping %s event of host %s", event.Type, event.HostId)
+	}
+}
+
+// Stop abandons the pending deliveries and waits for the workers to exit
+func (svc *Service) Stop() {
+	defaultLog.Trace("notifier/notifier:Stop() Entering")
+	defer defaultLog.Trace("notifier/notifier:Stop() Leaving")
+
+	close(svc.quit)
+	svc.wg.Wait()
+
+	svc.natsMtx.Lock()
+	defer svc.natsMtx.Unlock()
+	if svc.natsConn != nil {
+		svc.natsConn.Close()
+		svc.natsConn = nil
+	}
+}
+
+func (svc *Service) processEvents() {
+	defer func() {
+		if err := recover(); err != nil {
+			defaultLog.Errorf("Panic occurred: %+v", err)
+			defaultLog.Error(string(debug.Stack()))
+		}
+		svc.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-svc.quit:
+			return
+		case event := <-svc.events:
+			svc.dispatch(event)
+		}
+	}
+}
+
+// dispatch starts a delivery of the event to each matching subscription
+func (svc *Service) dispatch(event hvs.HostEvent) {
+	defaultLog.Trace("notifier/notifier:dispatch() Entering")
+	defer defaultLog.Trace("notifier/notifier:dispatch() Leaving")
+
+	subscriptions, err := svc.subscriptionStore.Search(nil)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("notifier/notifier:dispatch() Failed to retrieve subscriptions, dropping %s event of host %s", event.Type, event.HostId)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	svc.addHostDetails(&event)
+	body, err := json.Marshal(event)
+	if err != nil {
+		defaultLog.WithError(err).Error("notifier/notifier:dispatch() Failed to marshal event")
+		return
+	}
+	for _, subscription := range subscriptions {
+		if !subscription.Filter.Matches(&event) {
+			continue
+		}
+		svc.wg.Add(1)
+		go svc.deliver(subscription, event, body)
+	}
+}
+
+// addHostDetails fills in the name and the flavor groups of the host, they are needed to match the subscription
+// filters and make the event usable without a lookup by the subscriber
+func (svc *Service) addHostDetails(event *hvs.HostEvent) {
+	if event.HostName == "" {
+		if host, err := svc.hostStore.Retrieve(event.HostId, nil); err == nil {
+			event.HostName = host.HostName
+		} else {
+			defaultLog.WithError(err).Debugf("notifier/notifier:addHostDetails() Could not retrieve host %s", event.HostId)
+		}
+	}
+	if len(event.FlavorgroupIds) == 0 {
+		if fgIds, err := svc.hostStore.SearchFlavorgroups(event.HostId); err == nil {
+			event.FlavorgroupIds = fgIds
+		} else {
+			defaultLog.WithError(err).Debugf("notifier/notifier:addHostDetails() Could not retrieve flavor groups of host %s", event.HostId)
+		}
+	}
+}
+
+// deliver sends the event to a subscription, retrying with an exponential backoff until it succeeds or the maximum
+// number of attempts is reached
+func (svc *Service) deliver(subscription hvs.Subscription, event hvs.HostEvent, body []byte) {
+	defer func() {
+		if err := recover(); err != nil {
+			defaultLog.Errorf("Panic occurred: %+v", err)
+			defaultLog.Error(string(debug.Stack()))
+		}
+		svc.wg.Done()
+	}()
+
+	backoff := svc.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := svc.send(subscription, event, body)
+		if err == nil {
+			defaultLog.Debugf("notifier/notifier:deliver() Delivered event %s to subscription %s", event.Id, subscription.Id)
+			return
+		}
+		if attempt >= svc.maxAttempts {
+			defaultLog.WithError(err).Errorf("notifier/notifier:deliver() Giving up delivery of event %s to subscription %s after %d attempts",
+				event.Id, subscription.Id, attempt)
+			return
+		}
+		defaultLog.WithError(err).Warnf("notifier/notifier:deliver() Delivery of event %s to subscription %s failed, retrying in %s",
+			event.Id, subscription.Id, backoff)
+
+		select {
+		case <-svc.quit:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (svc *Service) send(subscription hvs.Subscription, event hvs.HostEvent, body []byte) error {
+	headers := map[string]string{
+		SignatureHeader: Sign(subscription.Secret, time.Now().Unix(), body),
+		EventIdHeader:   event.Id.String(),
+		EventTypeHeader: string(event.Type),
+	}
+
+	switch subscription.DeliveryType {
+	case hvs.SubscriptionDeliveryWebhook:
+		return svc.postWebhook(subscription.Endpoint, body, headers)
+	case hvs.SubscriptionDeliveryNats:
+		return svc.publishNats(subscription.Endpoint, body, headers)
+	}
+	return errors.Errorf("unsupported delivery type %s", subscription.DeliveryType)
+}
+
+func (svc *Service) postWebhook(url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Failed to create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := svc.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Failed to post event to webhook")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			defaultLog.WithError(err).Error("notifier/notifier:postWebhook() Error closing response body")
+		}
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (svc *Service) publishNats(subject string, body []byte, headers map[string]string) error {
+	conn, err := svc.getNatsConnection()
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(subject)
+	msg.Data = body
+	for name, value := range headers {
+		msg.Header.Set(name, value)
+	}
+	if err := conn.PublishMsg(msg); err != nil {
+		return errors.Wrap(err, "Failed to publish event on NATS")
+	}
+	return nil
+}
+
+// getNatsConnection connects to the NATS servers on the first NATS delivery
+func (svc *Service) getNatsConnection() (*nats.Conn, error) {
+	svc.natsMtx.Lock()
+	defer svc.natsMtx.Unlock()
+
+	if svc.natsConn != nil {
+		return svc.natsConn, nil
+	}
+	if len(svc.natsServers) == 0 {
+		return nil, errors.New("No NATS server is configured")
+	}
+	conn, err := nats.Connect(strings.Join(svc.natsServers, ","),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(5*time.Second),
+		nats.Timeout(deliveryTimeout),
+		nats.Secure(svc.tlsConfig),
+		nats.UserCredentials(svc.natsCredentials))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to connect to NATS")
+	}
+	svc.natsConn = conn
+	return conn, nil
+}
+
+// Sign returns the value of the signature header of a delivery made at the given unix time
+func Sign(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package notifier
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookRecorder struct {
+	mtx        sync.Mutex
+	failures   int
+	deliveries []*http.Request
+	bodies     [][]byte
+}
+
+func (rec *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	if rec.failures > 0 {
+		rec.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	rec.deliveries = append(rec.deliveries, r)
+	rec.bodies = append(rec.bodies, body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rec *webhookRecorder) count() int {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	return len(rec.deliveries)
+}
+
+func newTestService(t *testing.T, server *httptest.Server, store *mocks.MockSubscriptionStore) *Service {
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	svc, err := NewService(Config{
+		SubscriptionStore: store,
+		HostStore:         mocks.NewMockHostStore(),
+		TLSConfig:         &tls.Config{RootCAs: pool},
+		MaxAttempts:       3,
+		RetryBackoff:      10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	return svc
+}
+
+func TestNotifierDeliversSignedEvents(t *testing.T) {
+	recorder := &webhookRecorder{failures: 2}
+	server := httptest.NewTLSServer(recorder)
+	defer server.Close()
+
+	hostId := uuid.New()
+	store := mocks.NewMockSubscriptionStore()
+	matching, err := store.Create(&hvs.Subscription{
+		DeliveryType: hvs.SubscriptionDeliveryWebhook,
+		Endpoint:     server.URL + "/events",
+		Filter:       hvs.SubscriptionFilter{HostIds: []uuid.UUID{hostId}},
+		Secret:       "secret",
+	})
+	assert.NoError(t, err)
+	_, err = store.Create(&hvs.Subscription{
+		DeliveryType: hvs.SubscriptionDeliveryWebhook,
+		Endpoint:     server.URL + "/other",
+		Filter:       hvs.SubscriptionFilter{EventTypes: []hvs.HostEventType{hvs.HostEventConnectionFailure}},
+		Secret:       "secret",
+	})
+	assert.NoError(t, err)
+
+	svc := newTestService(t, server, store)
+	trusted := true
+	svc.Publish(hvs.HostEvent{Type: hvs.HostEventTrustChanged, HostId: hostId, HostName: "host1", Trusted: &trusted})
+
+	// the first two attempts fail, the third one succeeds
+	assert.Eventually(t, func() bool { return recorder.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	svc.Stop()
+
+	req := recorder.deliveries[0]
+	body := recorder.bodies[0]
+	assert.Equal(t, "/events", req.URL.Path)
+	assert.Equal(t, string(hvs.HostEventTrustChanged), req.Header.Get(EventTypeHeader))
+
+	var event hvs.HostEvent
+	assert.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, hostId, event.HostId)
+	assert.Equal(t, event.Id.String(), req.Header.Get(EventIdHeader))
+
+	signature := req.Header.Get(SignatureHeader)
+	ts, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, Sign(matching.Secret, ts, body), signature)
+	assert.NotEqual(t, Sign("other secret", ts, body), signature)
+}
+
+func TestNotifierGivesUpAfterMaxAttempts(t *testing.T) {
+	recorder := &webhookRecorder{failures: 3}
+	server := httptest.NewTLSServer(recorder)
+	defer server.Close()
+
+	store := mocks.NewMockSubscriptionStore()
+	_, err := store.Create(&hvs.Subscription{
+		DeliveryType: hvs.SubscriptionDeliveryWebhook,
+		Endpoint:     server.URL,
+		Secret:       "secret",
+	})
+	assert.NoError(t, err)
+
+	svc := newTestService(t, server, store)
+	svc.Publish(hvs.HostEvent{Type: hvs.HostEventNewReport, HostId: uuid.New()})
+
+	assert.Eventually(t, func() bool {
+		recorder.mtx.Lock()
+		defer recorder.mtx.Unlock()
+		return recorder.failures == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	svc.Stop()
+	assert.Equal(t, 0, recorder.count())
+}
@@ -43,6 +43,10 @@ var envHelp = map[string]string{
 	"FVS_NUMBER_OF_VERIFIERS":                "Number of Flavor verification verifier threads",
 	"FVS_NUMBER_OF_DATA_FETCHERS":            "Number of Flavor verification data fetcher threads",
 	"FVS_SKIP_FLAVOR_SIGNATURE_VERIFICATION": "Skips flavor signature verification when set to true",
+	"FVS_RETRY_BACKOFF_BASE":                 "Delay before the first retry of a failed host data fetch",
+	"FVS_RETRY_BACKOFF_MAX":                  "Maximum delay between retries of a failed host data fetch",
+	"FVS_CIRCUIT_BREAKER_THRESHOLD":          "Number of consecutive failed host data fetches after which a host is not contacted for the cool down period",
+	"FVS_CIRCUIT_BREAKER_COOLDOWN":           "Period during which a host that exceeded the circuit breaker threshold is not contacted",
 	"HOST_TRUST_CACHE_THRESHOLD":             "Maximum number of entries to be cached in the Trust/Flavor caches",
 	"SERVER_PORT":                            "The Port on which Server listens to",
 	"SERVER_READ_TIMEOUT":                    "Request Read Timeout Duration in Seconds",
@@ -52,6 +56,10 @@ var envHelp = map[string]string{
 	"SERVER_MAX_HEADER_BYTES":                "Max Length of Request Header in Bytes",
 	"NAT_SERVERS":                            "List of NATs servers to establish connection with outbound TAs",
 	"ENABLE_EKCERT_REVOKE_CHECK":             "If enabled, revocation checks will be performed for EK certs at the time of AIK provisioning",
+	"TRACING_EXPORTER":                       "Exporter of the request traces, one of none or otlp",
+	"TRACING_ENDPOINT":                       "Host and port of the OTLP/HTTP collector the traces are exported to",
+	"TRACING_INSECURE":                       "Export the traces over plain HTTP instead of HTTPS when set to true",
+	"TRACING_SAMPLE_RATIO":                   "Ratio of the requests that are traced, between 0 and 1",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -90,6 +98,10 @@ func (uc UpdateServiceConfig) Run() error {
 		NumberOfDataFetchers:            viper.GetInt(constants.FvsNumberOfDataFetchers),
 		SkipFlavorSignatureVerification: viper.GetBool(constants.FvsSkipFlavorSignatureVerification),
 		HostTrustCacheThreshold:         viper.GetInt(constants.FvsHostTrustCacheThreshold),
+		RetryBackoffBase:                viper.GetDuration(constants.FvsRetryBackoffBase),
+		RetryBackoffMax:                 viper.GetDuration(constants.FvsRetryBackoffMax),
+		CircuitBreakerThreshold:         viper.GetInt(constants.FvsCircuitBreakerThreshold),
+		CircuitBreakerCooldown:          viper.GetDuration(constants.FvsCircuitBreakerCooldown),
 	}
 
 	if uc.NatServers != "" {
@@ -97,6 +109,12 @@ func (uc UpdateServiceConfig) Run() error {
 			Servers: strings.Split(uc.NatServers, ","),
 		}
 	}
+	(*uc.AppConfig).Tracing = commConfig.TracingConfig{
+		Exporter:    viper.GetString(constants.TracingExporter),
+		Endpoint:    viper.GetString(constants.TracingEndpoint),
+		Insecure:    viper.GetBool(constants.TracingInsecure),
+		SampleRatio: viper.GetFloat64(constants.TracingSampleRatio),
+	}
 	(*uc.AppConfig).EnableEkCertRevokeChecks = viper.GetBool(constants.EnableEKCertRevokeCheck)
 	return nil
 }
@@ -17,7 +17,8 @@ func LoadCertificates(certificatePaths *models.CertificatesPathStore) *models.Ce
 	certificateStore := make(models.CertificatesStore)
 	for _, certType := range models.GetUniqueCertTypes() {
 		certloc := (*certificatePaths)[certType]
-		if certType == models.CaCertTypesRootCa.String() || certType == models.CaCertTypesEndorsementCa.String() {
+		if certType == models.CaCertTypesRootCa.String() || certType == models.CaCertTypesEndorsementCa.String() ||
+			certType == models.CaCertTypesFlavorCa.String() || certType == models.CertTypesImportedFlavorSigning.String() {
 			certificateStore[certType] = loadCertificatesFromDir(&certloc)
 		} else {
 			certificateStore[certType] = loadCertificatesFromFile(&certloc)
@@ -83,6 +83,11 @@ func (app *App) Run(args []string) error {
 			return errInvalidCmd
 		}
 		return app.status()
+	case "rotate-master-key":
+		if len(args) != 2 {
+			return errInvalidCmd
+		}
+		return app.rotateMasterKey()
 	case "uninstall":
 		// the only allowed flag is --purge
 		purge := false
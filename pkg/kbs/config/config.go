@@ -6,6 +6,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
 	commConfig "github.com/intel-secl/intel-secl/v4/pkg/lib/common/config"
@@ -26,13 +27,16 @@ type Configuration struct {
 
 	EndpointURL string `yaml:"endpoint-url" mapstructure:"endpoint-url"`
 	KeyManager  string `yaml:"key-manager" mapstructure:"key-manager"`
+	// KeyExpiryCheckInterval is the interval at which keys past their expiry date are deactivated
+	KeyExpiryCheckInterval time.Duration `yaml:"key-expiry-check-interval" mapstructure:"key-expiry-check-interval"`
 
 	TLS    commConfig.TLSCertConfig `yaml:"tls" mapstructure:"tls"`
 	Log    commConfig.LogConfig     `yaml:"log" mapstructure:"log"`
 	Server commConfig.ServerConfig  `yaml:"server" mapstructure:"server"`
 
-	Kmip KmipConfig `yaml:"kmip" mapstructure:"kmip"`
-	Skc  SKCConfig  `yaml:"skc" mapstructure:"skc"`
+	Kmip   KmipConfig   `yaml:"kmip" mapstructure:"kmip"`
+	Pkcs11 Pkcs11Config `yaml:"pkcs11" mapstructure:"pkcs11"`
+	Skc    SKCConfig    `yaml:"skc" mapstructure:"skc"`
 }
 
 type KBSConfig struct {
@@ -52,6 +56,12 @@ type KmipConfig struct {
 	RootCertificateFilePath   string `yaml:"root-cert-path" mapstructure:"root-cert-path"`
 }
 
+type Pkcs11Config struct {
+	ModulePath string `yaml:"module-path" mapstructure:"module-path"`
+	Slot       uint   `yaml:"slot" mapstructure:"slot"`
+	Pin        string `yaml:"pin" mapstructure:"pin"`
+}
+
 type SKCConfig struct {
 	StmLabel          string `yaml:"challenge-type" mapstructure:"challenge-type"`
 	SQVSUrl           string `yaml:"sqvs-url" mapstructure:"sqvs-url"`
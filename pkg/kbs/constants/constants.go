@@ -36,6 +36,9 @@ const (
 	DefaultTransferPolicy     = "urn:intel:trustedcomputing:key-transfer-policy:require-trust-or-authorization"
 	DefaultConfigFilePath     = ConfigDir + "config.yml"
 	DefaultTransferPolicyFile = ConfigDir + "default_transfer_policy"
+	DefaultMasterKeyFile      = ConfigDir + "master.key"
+
+	DefaultKeyExpiryCheckInterval = time.Hour
 
 	// default locations for tls certificate and key
 	DefaultTLSCertPath = ConfigDir + "tls-cert.pem"
@@ -66,7 +69,9 @@ const (
 	DefaultKBSListenerPort   = 9443
 
 	// keymanager constants
-	KmipKeyManager = "kmip"
+	KmipKeyManager      = "kmip"
+	Pkcs11KeyManager    = "pkcs11"
+	DirectoryKeyManager = "directory"
 
 	// algorithm constants
 	CRYPTOALG_AES = "AES"
@@ -14,6 +14,8 @@ const (
 	KeySearch   = "keys:search"
 	KeyRegister = "keys:register"
 	KeyTransfer = "keys:transfer"
+	KeyRotate   = "keys:rotate"
+	KeyUpdate   = "keys:update"
 
 	SamlCertCreate   = "saml_certificates:create"
 	SamlCertRetrieve = "saml_certificates:retrieve"
@@ -31,4 +33,6 @@ const (
 	KeyTransferPolicySearch   = "key_transfer_policies:search"
 
 	SessionCreate = "key-session-api:create"
+
+	MetricsRetrieve = "metrics:retrieve"
 )
@@ -18,6 +18,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -54,10 +55,12 @@ func NewKeyController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyS
 	}
 }
 
-var keySearchParams = map[string]bool{"algorithm": true, "keyLength": true, "curveType": true, "transferPolicyId": true}
+var keySearchParams = map[string]bool{"algorithm": true, "keyLength": true, "curveType": true, "transferPolicyId": true,
+	"state": true, "expiresBefore": true, "expiresAfter": true}
 var allowedAlgorithms = map[string]bool{"AES": true, "RSA": true, "EC": true, "aes": true, "rsa": true, "ec": true}
 var allowedCurveTypes = map[string]bool{"secp256r1": true, "secp384r1": true, "secp521r1": true, "prime256v1": true}
 var allowedKeyLengths = map[int]bool{128: true, 192: true, 256: true, 2048: true, 3072: true, 4096: true, 7680: true}
+var allowedKeyStates = map[string]bool{kbs.KeyStatePreActive: true, kbs.KeyStateActive: true, kbs.KeyStateDeactivated: true, kbs.KeyStateCompromised: true}
 
 // Create : Function to create key
 func (kc KeyController) Create(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
@@ -189,6 +192,74 @@ func (kc KeyController) Delete(responseWriter http.ResponseWriter, request *http
 	return nil, http.StatusNoContent, nil
 }
 
+// Rotate : Function to rotate key
+func (kc KeyController) Rotate(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:Rotate() Entering")
+	defer defaultLog.Trace("controllers/key_controller:Rotate() Leaving")
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	key, err := kc.remoteManager.RotateKey(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:Rotate() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_controller:Rotate() Key rotate failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to rotate key"}
+		}
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:Rotate() %s: Key rotated to version %d by: %s", commLogMsg.PrivilegeModified, key.CurrentVersion, request.RemoteAddr)
+	return key, http.StatusOK, nil
+}
+
+// UpdateState : Function to change the lifecycle state of key
+func (kc KeyController) UpdateState(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:UpdateState() Entering")
+	defer defaultLog.Trace("controllers/key_controller:UpdateState() Leaving")
+
+	if request.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if request.ContentLength == 0 {
+		secLog.Error("controllers/key_controller:UpdateState() The request body was not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var stateRequest kbs.KeyStateRequest
+	dec := json.NewDecoder(request.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&stateRequest); err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:UpdateState() %s : Failed to decode request body as KeyStateRequest", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if !allowedKeyStates[stateRequest.State] {
+		secLog.Errorf("controllers/key_controller:UpdateState() %s : Invalid key state", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Valid state must be specified"}
+	}
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	key, err := kc.remoteManager.SetKeyState(id, stateRequest.State)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:UpdateState() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else if errors.Cause(err) == models.ErrInvalidKeyStateTransition {
+			defaultLog.WithError(err).Error("controllers/key_controller:UpdateState() Invalid key state transition")
+			return nil, http.StatusConflict, &commErr.ResourceError{Message: err.Error()}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_controller:UpdateState() Key state update failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to update key state"}
+		}
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:UpdateState() %s: Key state changed to %s by: %s", commLogMsg.PrivilegeModified, key.State, request.RemoteAddr)
+	return key, http.StatusOK, nil
+}
+
 // Search : Function to search keys
 func (kc KeyController) Search(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_controller:Search() Entering")
@@ -246,16 +317,23 @@ func (kc KeyController) Transfer(responseWriter http.ResponseWriter, request *ht
 	}
 	envelopeKey := key.(*rsa.PublicKey)
 
+	version, err := getKeyVersion(request.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:Transfer() %s : Invalid key version", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
 	// Wrap key with public key
 	id := uuid.MustParse(mux.Vars(request)["id"])
-	wrappedKey, status, err := kc.wrapSecretKey(id, envelopeKey, sha512.New384(), nil)
+	wrappedKey, keyVersion, status, err := kc.wrapSecretKey(id, version, envelopeKey, sha512.New384(), nil)
 	if err != nil {
 		return nil, status, err
 	}
 
 	transferKeyResponse := kbs.KeyTransferAttributes{
-		KeyId:   id,
-		KeyData: base64.StdEncoding.EncodeToString(wrappedKey.([]byte)),
+		KeyId:      id,
+		KeyData:    base64.StdEncoding.EncodeToString(wrappedKey),
+		KeyVersion: keyVersion,
 	}
 
 	secLog.WithField("Id", id).Infof("controllers/key_controller:Transfer() %s: Key transferred using Envelope key by: %s", commLogMsg.PrivilegeModified, request.RemoteAddr)
@@ -291,6 +369,12 @@ func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, req
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to unmarshal saml report"}
 	}
 
+	version, err := getKeyVersion(request.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:TransferWithSaml() %s : Invalid key version", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
 	// Validate saml report in request
 	id := uuid.MustParse(mux.Vars(request)["id"])
 	trusted, bindingCert := keytransfer.IsTrustedByHvs(string(bytes), samlReport, id, kc.config, kc.remoteManager)
@@ -301,7 +385,7 @@ func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, req
 	envelopeKey := bindingCert.PublicKey.(*rsa.PublicKey)
 
 	// Wrap key with binding key
-	wrappedKey, status, err := kc.wrapSecretKey(id, envelopeKey, sha256.New(), []byte("TPM2\000"))
+	wrappedKey, _, status, err := kc.wrapSecretKey(id, version, envelopeKey, sha256.New(), []byte("TPM2\000"))
 	if err != nil {
 		return nil, status, err
 	}
@@ -310,18 +394,24 @@ func (kc KeyController) TransferWithSaml(responseWriter http.ResponseWriter, req
 	return wrappedKey, http.StatusOK, nil
 }
 
-func (kc KeyController) wrapSecretKey(id uuid.UUID, publicKey *rsa.PublicKey, hash hash.Hash, label []byte) (interface{}, int, error) {
+func (kc KeyController) wrapSecretKey(id uuid.UUID, version int, publicKey *rsa.PublicKey, hash hash.Hash, label []byte) ([]byte, int, int, error) {
 	defaultLog.Trace("controllers/key_controller:wrapSecretKey() Entering")
 	defer defaultLog.Trace("controllers/key_controller:wrapSecretKey() Leaving")
 
-	secretKey, err := kc.remoteManager.TransferKey(id)
+	secretKey, keyVersion, err := kc.remoteManager.TransferKey(id, version)
 	if err != nil {
 		if err.Error() == commErr.RecordNotFound {
 			defaultLog.Error("controllers/key_controller:wrapSecretKey() Key with specified id could not be located")
-			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+			return nil, 0, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else if errors.Cause(err) == models.ErrKeyVersionNotFound {
+			defaultLog.Error("controllers/key_controller:wrapSecretKey() Key with specified version could not be located")
+			return nil, 0, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified version does not exist"}
+		} else if errors.Cause(err) == models.ErrKeyNotActive {
+			secLog.WithError(err).Errorf("controllers/key_controller:wrapSecretKey() %s : Transfer of key that is not active", commLogMsg.UnauthorizedAccess)
+			return nil, 0, http.StatusForbidden, &commErr.ResourceError{Message: "Key with specified id is not active"}
 		} else {
 			defaultLog.WithError(err).Error("controllers/key_controller:wrapSecretKey() Key transfer failed")
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to transfer Key"}
+			return nil, 0, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to transfer Key"}
 		}
 	}
 
@@ -329,10 +419,10 @@ func (kc KeyController) wrapSecretKey(id uuid.UUID, publicKey *rsa.PublicKey, ha
 	wrappedKey, err := rsa.EncryptOAEP(hash, rand.Reader, publicKey, secretKey, label)
 	if err != nil {
 		defaultLog.WithError(err).Error("controllers/key_controller:wrapSecretKey() Wrap key failed")
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to wrap key"}
+		return nil, 0, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to wrap key"}
 	}
 
-	return wrappedKey, http.StatusOK, nil
+	return wrappedKey, keyVersion, http.StatusOK, nil
 }
 
 // validateKeyCreateRequest checks for various attributes in the Key Create request and returns a boolean value
@@ -384,6 +474,15 @@ func validateKeyCreateRequest(requestKey kbs.KeyRequest) error {
 			return errors.New("valid contents for usage must be specified")
 		}
 	}
+
+	if requestKey.NotAfter != nil {
+		if !requestKey.NotAfter.After(time.Now()) {
+			return errors.New("not_after must be in the future")
+		}
+		if requestKey.NotBefore != nil && !requestKey.NotAfter.After(*requestKey.NotBefore) {
+			return errors.New("not_after must be later than not_before")
+		}
+	}
 	return nil
 }
 
@@ -430,9 +529,52 @@ func getKeyFilterCriteria(params url.Values) (*models.KeyFilterCriteria, error)
 		}
 		criteria.TransferPolicyId = id
 	}
+
+	// state
+	if param := strings.TrimSpace(params.Get("state")); param != "" {
+		if !allowedKeyStates[param] {
+			return nil, errors.New("Valid state must be specified")
+		}
+		criteria.State = param
+	}
+
+	// expiresBefore
+	if param := strings.TrimSpace(params.Get("expiresBefore")); param != "" {
+		expiresBefore, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid expiresBefore query param value, must be RFC3339 formatted")
+		}
+		criteria.ExpiresBefore = &expiresBefore
+	}
+
+	// expiresAfter
+	if param := strings.TrimSpace(params.Get("expiresAfter")); param != "" {
+		expiresAfter, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid expiresAfter query param value, must be RFC3339 formatted")
+		}
+		criteria.ExpiresAfter = &expiresAfter
+	}
 	return &criteria, nil
 }
 
+// getKeyVersion returns the key version requested through the version query param, 0 if no version was requested
+func getKeyVersion(params url.Values) (int, error) {
+	defaultLog.Trace("controllers/key_controller:getKeyVersion() Entering")
+	defer defaultLog.Trace("controllers/key_controller:getKeyVersion() Leaving")
+
+	param := strings.TrimSpace(params.Get("version"))
+	if param == "" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(param)
+	if err != nil || version < 1 {
+		return 0, errors.New("Invalid version query param value, must be a positive Integer")
+	}
+	return version, nil
+}
+
 func checkValidKeyPermission(privileges []ct.PermissionInfo, requiredPermission []string) bool {
 	defaultLog.Trace("controllers/key_controller:checkValidKeyPermission() Entering")
 	defer defaultLog.Trace("controllers/key_controller:checkValidKeyPermission() Leaving")
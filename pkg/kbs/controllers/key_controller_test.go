@@ -694,5 +694,100 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Get all the Keys with valid state param", func() {
+			It("Should get list of all the filtered Keys", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/keys?state=active", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var keyResponses []kbs.KeyResponse
+				json.Unmarshal(w.Body.Bytes(), &keyResponses)
+				// Verifying mocked data of 3 keys
+				Expect(len(keyResponses)).To(Equal(3))
+			})
+		})
+		Context("Get all the Keys with invalid state param", func() {
+			It("Should fail to get Keys", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/keys?state=destroyed", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Get all the Keys with invalid expiresBefore param", func() {
+			It("Should fail to get Keys", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods("GET")
+				req, err := http.NewRequest("GET", "/keys?expiresBefore=2021-13-01", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Put to "/keys/{id}/state"
+	Describe("Update the state of an existing Key", func() {
+		Context("Mark Key as compromised", func() {
+			It("Should update the Key state and refuse transfer", func() {
+				router.Handle("/keys/{id}/state", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.UpdateState))).Methods("PUT")
+				router.Handle("/keys/{id}/transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Transfer))).Methods("POST")
+				req, err := http.NewRequest("PUT", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/state", strings.NewReader(`{"state": "compromised"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				req, err = http.NewRequest("POST", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/transfer", strings.NewReader(string(validEnvelopeKey)))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypePlain)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+		Context("Activate a deactivated Key", func() {
+			It("Should fail to update the Key state", func() {
+				router.Handle("/keys/{id}/state", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.UpdateState))).Methods("PUT")
+				req, err := http.NewRequest("PUT", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/state", strings.NewReader(`{"state": "deactivated"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				req, err = http.NewRequest("PUT", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/state", strings.NewReader(`{"state": "active"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+		Context("Provide an invalid state", func() {
+			It("Should fail to update the Key state", func() {
+				router.Handle("/keys/{id}/state", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.UpdateState))).Methods("PUT")
+				req, err := http.NewRequest("PUT", "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/state", strings.NewReader(`{"state": "destroyed"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 })
@@ -13,6 +13,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/keytransfer"
 
@@ -141,7 +142,11 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		}
 
 		defaultLog.Debug("Session is valid. Hence directly transfer the key")
-		keyData, err := kc.remoteManager.TransferKey(keyID)
+		keyData, keyVersion, err := kc.remoteManager.TransferKey(keyID, 0)
+		if errors.Cause(err) == models.ErrKeyNotActive {
+			secLog.WithError(err).WithField("id", keyID).Error("controllers/skc_controller:TransferApplicationKey() Key is not active")
+			return nil, http.StatusForbidden, &commErr.ResourceError{Message: "Key is not active"}
+		}
 		if err != nil {
 			defaultLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() Key retrieve failed")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
@@ -158,6 +163,7 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		outputKeyData.KeyInfo.KeyAlgorithm = key.KeyInformation.Algorithm
 		outputKeyData.KeyInfo.CreatedAt = &key.CreatedAt
 		outputKeyData.KeyInfo.KeyId = keyID
+		outputKeyData.KeyInfo.KeyVersion = keyVersion
 		outputKeyData.KeyInfo.KeyData = applicationKey
 		outputKeyData.KeyInfo.KeyLength = key.KeyInformation.KeyLength
 		outputKeyData.KeyInfo.Policy.Link.KeyTransfer.Href = url
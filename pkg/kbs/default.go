@@ -17,6 +17,7 @@ import (
 func init() {
 	viper.SetDefault("endpoint-url", constants.DefaultEndpointUrl)
 	viper.SetDefault("key-manager", constants.DefaultKeyManager)
+	viper.SetDefault("key-expiry-check-interval", constants.DefaultKeyExpiryCheckInterval)
 
 	// Set default values for tls
 	viper.SetDefault("tls-cert-file", constants.DefaultTLSCertPath)
@@ -52,6 +53,8 @@ func defaultConfig() *config.Configuration {
 		EndpointURL: viper.GetString("endpoint-url"),
 		KeyManager:  viper.GetString("key-manager"),
 
+		KeyExpiryCheckInterval: viper.GetDuration("key-expiry-check-interval"),
+
 		KBS: config.KBSConfig{
 			UserName: viper.GetString("kbs-service-username"),
 			Password: viper.GetString("kbs-service-password"),
@@ -86,6 +89,11 @@ func defaultConfig() *config.Configuration {
 			ClientCertificateFilePath: viper.GetString("kmip-client-cert-path"),
 			RootCertificateFilePath:   viper.GetString("kmip-root-cert-path"),
 		},
+		Pkcs11: config.Pkcs11Config{
+			ModulePath: viper.GetString("pkcs11-module-path"),
+			Slot:       viper.GetUint("pkcs11-slot"),
+			Pin:        viper.GetString("pkcs11-pin"),
+		},
 		Skc: config.SKCConfig{
 			StmLabel:          viper.GetString("skc-challenge-type"),
 			SQVSUrl:           viper.GetString("sqvs-url"),
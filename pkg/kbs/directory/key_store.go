@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
@@ -162,5 +163,39 @@ func filterKeys(keys []models.KeyAttributes, criteria *models.KeyFilterCriteria)
 		keys = filteredKeys
 	}
 
+	// State filter
+	if criteria.State != "" {
+		now := time.Now().UTC()
+		var filteredKeys []models.KeyAttributes
+		for _, key := range keys {
+			if key.EffectiveState(now) == criteria.State {
+				filteredKeys = append(filteredKeys, key)
+			}
+		}
+		keys = filteredKeys
+	}
+
+	// ExpiresBefore filter
+	if criteria.ExpiresBefore != nil {
+		var filteredKeys []models.KeyAttributes
+		for _, key := range keys {
+			if key.NotAfter != nil && key.NotAfter.Before(*criteria.ExpiresBefore) {
+				filteredKeys = append(filteredKeys, key)
+			}
+		}
+		keys = filteredKeys
+	}
+
+	// ExpiresAfter filter
+	if criteria.ExpiresAfter != nil {
+		var filteredKeys []models.KeyAttributes
+		for _, key := range keys {
+			if key.NotAfter != nil && key.NotAfter.After(*criteria.ExpiresAfter) {
+				filteredKeys = append(filteredKeys, key)
+			}
+		}
+		keys = filteredKeys
+	}
+
 	return keys
 }
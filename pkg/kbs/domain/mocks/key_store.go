@@ -104,6 +104,39 @@ func (store *MockKeyStore) Search(criteria *models.KeyFilterCriteria) ([]models.
 		keys = kFiltered
 	}
 
+	// State filter
+	if criteria.State != "" {
+		var kFiltered []models.KeyAttributes
+		for _, k := range keys {
+			if k.EffectiveState(time.Now().UTC()) == criteria.State {
+				kFiltered = append(kFiltered, k)
+			}
+		}
+		keys = kFiltered
+	}
+
+	// ExpiresBefore filter
+	if criteria.ExpiresBefore != nil {
+		var kFiltered []models.KeyAttributes
+		for _, k := range keys {
+			if k.NotAfter != nil && k.NotAfter.Before(*criteria.ExpiresBefore) {
+				kFiltered = append(kFiltered, k)
+			}
+		}
+		keys = kFiltered
+	}
+
+	// ExpiresAfter filter
+	if criteria.ExpiresAfter != nil {
+		var kFiltered []models.KeyAttributes
+		for _, k := range keys {
+			if k.NotAfter != nil && k.NotAfter.After(*criteria.ExpiresAfter) {
+				kFiltered = append(kFiltered, k)
+			}
+		}
+		keys = kFiltered
+	}
+
 	return keys, nil
 }
 
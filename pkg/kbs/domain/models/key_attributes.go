@@ -9,6 +9,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrKeyVersionNotFound is returned when a requested key version does not exist
+	ErrKeyVersionNotFound = errors.New("key version not found")
+	// ErrKeyNotActive is returned when key material is requested for a key that is not in the active state
+	ErrKeyNotActive = errors.New("key is not active")
+	// ErrInvalidKeyStateTransition is returned when a key can not be moved to the requested state
+	ErrInvalidKeyStateTransition = errors.New("invalid key state transition")
 )
 
 // KeyAttributes - Contains all possible key attributes.
@@ -21,11 +31,162 @@ type KeyAttributes struct {
 	PublicKey        string    `json:"public_key,omitempty"`
 	PrivateKey       string    `json:"private_key,omitempty"`
 	KmipKeyID        string    `json:"kmip_key_id,omitempty"`
+	Pkcs11KeyID      string    `json:"pkcs11_key_id,omitempty"`
 	TransferPolicyId uuid.UUID `json:"transfer_policy_id,omitempty"`
 	TransferLink     string    `json:"transfer_link,omitempty"`
 	CreatedAt        time.Time `json:"created_at,omitempty"`
 	Label            string    `json:"label,omitempty"`
 	Usage            string    `json:"usage,omitempty"`
+	// CurrentVersion is the version of the key material referenced above, keys that were never rotated have version 1
+	CurrentVersion   int          `json:"current_version,omitempty"`
+	RotatedAt        *time.Time   `json:"rotated_at,omitempty"`
+	PreviousVersions []KeyVersion `json:"previous_versions,omitempty"`
+	// NotBefore and NotAfter bound the period in which the key is active, State holds the lifecycle state
+	// set at creation or by a state change. Keys stored without a state are active.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	State     string     `json:"state,omitempty"`
+}
+
+// KeyVersion - Contains the key material references of a key version replaced by a rotation.
+type KeyVersion struct {
+	Version     int       `json:"version"`
+	KeyData     string    `json:"key,omitempty"`
+	PublicKey   string    `json:"public_key,omitempty"`
+	PrivateKey  string    `json:"private_key,omitempty"`
+	KmipKeyID   string    `json:"kmip_key_id,omitempty"`
+	Pkcs11KeyID string    `json:"pkcs11_key_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetCurrentVersion returns the version of the current key material
+func (ka *KeyAttributes) GetCurrentVersion() int {
+	if ka.CurrentVersion == 0 {
+		return 1
+	}
+	return ka.CurrentVersion
+}
+
+// currentVersionCreatedAt returns the time at which the current key material was created
+func (ka *KeyAttributes) currentVersionCreatedAt() time.Time {
+	if ka.RotatedAt != nil {
+		return *ka.RotatedAt
+	}
+	return ka.CreatedAt
+}
+
+// AtVersion returns the key attributes referencing the key material of the given version,
+// version 0 selects the current version
+func (ka *KeyAttributes) AtVersion(version int) (*KeyAttributes, error) {
+	if version == 0 || version == ka.GetCurrentVersion() {
+		return ka, nil
+	}
+
+	for _, previous := range ka.PreviousVersions {
+		if previous.Version == version {
+			versionAttributes := *ka
+			versionAttributes.KeyData = previous.KeyData
+			versionAttributes.PublicKey = previous.PublicKey
+			versionAttributes.PrivateKey = previous.PrivateKey
+			versionAttributes.KmipKeyID = previous.KmipKeyID
+			versionAttributes.Pkcs11KeyID = previous.Pkcs11KeyID
+			versionAttributes.CurrentVersion = previous.Version
+			versionAttributes.RotatedAt = &previous.CreatedAt
+			versionAttributes.PreviousVersions = nil
+			return &versionAttributes, nil
+		}
+	}
+	return nil, ErrKeyVersionNotFound
+}
+
+// AllVersions returns the key attributes of every version of the key, current version first
+func (ka *KeyAttributes) AllVersions() []*KeyAttributes {
+	versions := []*KeyAttributes{ka}
+	for _, previous := range ka.PreviousVersions {
+		if versionAttributes, err := ka.AtVersion(previous.Version); err == nil {
+			versions = append(versions, versionAttributes)
+		}
+	}
+	return versions
+}
+
+// Rotate makes the key material of newVersion the current version and moves the current key
+// material to the version history
+func (ka *KeyAttributes) Rotate(newVersion *KeyAttributes) {
+	ka.PreviousVersions = append(ka.PreviousVersions, KeyVersion{
+		Version:     ka.GetCurrentVersion(),
+		KeyData:     ka.KeyData,
+		PublicKey:   ka.PublicKey,
+		PrivateKey:  ka.PrivateKey,
+		KmipKeyID:   ka.KmipKeyID,
+		Pkcs11KeyID: ka.Pkcs11KeyID,
+		CreatedAt:   ka.currentVersionCreatedAt(),
+	})
+
+	rotatedAt := newVersion.CreatedAt
+	ka.KeyData = newVersion.KeyData
+	ka.PublicKey = newVersion.PublicKey
+	ka.PrivateKey = newVersion.PrivateKey
+	ka.KmipKeyID = newVersion.KmipKeyID
+	ka.Pkcs11KeyID = newVersion.Pkcs11KeyID
+	ka.CurrentVersion = ka.GetCurrentVersion() + 1
+	ka.RotatedAt = &rotatedAt
+}
+
+// EffectiveState returns the lifecycle state of the key at the given time, taking the
+// activation and expiry dates into account
+func (ka *KeyAttributes) EffectiveState(now time.Time) string {
+	switch ka.State {
+	case kbs.KeyStateDeactivated, kbs.KeyStateCompromised:
+		return ka.State
+	}
+
+	if ka.NotAfter != nil && !now.Before(*ka.NotAfter) {
+		return kbs.KeyStateDeactivated
+	}
+	if ka.NotBefore != nil {
+		if now.Before(*ka.NotBefore) {
+			return kbs.KeyStatePreActive
+		}
+		return kbs.KeyStateActive
+	}
+	if ka.State == kbs.KeyStatePreActive {
+		return kbs.KeyStatePreActive
+	}
+	return kbs.KeyStateActive
+}
+
+// IsActive checks if the key material can be used at the given time
+func (ka *KeyAttributes) IsActive(now time.Time) error {
+	if state := ka.EffectiveState(now); state != kbs.KeyStateActive {
+		return errors.Wrapf(ErrKeyNotActive, "key is %s", state)
+	}
+	return nil
+}
+
+// SetState moves the key to the requested lifecycle state. Keys can be activated only from the
+// pre-active state, deactivated and compromised keys can not be activated again.
+func (ka *KeyAttributes) SetState(state string, now time.Time) error {
+	current := ka.EffectiveState(now)
+	switch state {
+	case kbs.KeyStateActive:
+		if current != kbs.KeyStatePreActive && current != kbs.KeyStateActive {
+			return errors.Wrapf(ErrInvalidKeyStateTransition, "%s key can not be activated", current)
+		}
+		if ka.NotBefore != nil && now.Before(*ka.NotBefore) {
+			ka.NotBefore = &now
+		}
+	case kbs.KeyStateDeactivated:
+		if current == kbs.KeyStateCompromised {
+			return errors.Wrapf(ErrInvalidKeyStateTransition, "%s key can not be deactivated", current)
+		}
+	case kbs.KeyStateCompromised:
+	default:
+		return errors.Wrapf(ErrInvalidKeyStateTransition, "key can not be moved to %s state", state)
+	}
+
+	ka.State = state
+	return nil
 }
 
 func (ka *KeyAttributes) ToKeyResponse() *kbs.KeyResponse {
@@ -45,6 +206,25 @@ func (ka *KeyAttributes) ToKeyResponse() *kbs.KeyResponse {
 		CreatedAt:        ka.CreatedAt,
 		Label:            ka.Label,
 		Usage:            ka.Usage,
+		NotBefore:        ka.NotBefore,
+		NotAfter:         ka.NotAfter,
+		State:            ka.EffectiveState(time.Now().UTC()),
+	}
+
+	// version details are reported only for keys that have been rotated
+	if len(ka.PreviousVersions) > 0 {
+		keyResponse.CurrentVersion = ka.GetCurrentVersion()
+		for _, previous := range ka.PreviousVersions {
+			keyResponse.Versions = append(keyResponse.Versions, kbs.KeyVersion{
+				Version:   previous.Version,
+				CreatedAt: previous.CreatedAt,
+			})
+		}
+		keyResponse.Versions = append(keyResponse.Versions, kbs.KeyVersion{
+			Version:   ka.GetCurrentVersion(),
+			CreatedAt: ka.currentVersionCreatedAt(),
+			Current:   true,
+		})
 	}
 
 	return &keyResponse
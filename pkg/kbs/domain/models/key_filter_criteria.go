@@ -4,7 +4,11 @@
  */
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 //KeyFilterCriteria stores the parameters for filtering the keys
 type KeyFilterCriteria struct {
@@ -12,4 +16,7 @@ type KeyFilterCriteria struct {
 	KeyLength        int
 	CurveType        string
 	TransferPolicyId uuid.UUID
+	State            string
+	ExpiresBefore    *time.Time
+	ExpiresAfter     *time.Time
 }
@@ -20,6 +20,7 @@ Available Commands:
 	start                  Start kbs
 	status                 Show the status of kbs
 	stop                   Stop kbs
+	rotate-master-key      Re-wrap all keys of the directory key manager with a new master key, kbs must be stopped
 	uninstall [--purge]    Uninstall kbs
 		--purge            all configuration and data files will be removed if this flag is set
 
@@ -35,6 +36,7 @@ Available Tasks for setup:
 	download-cert-tls                   Download CA certificate from CMS for tls
 	create-default-key-transfer-policy  Create default key transfer policy for KBS
 	update-service-config               Sets or Updates the Service configuration 
+	create-key-store                    Create the master key used by the directory key manager
 `
 
 func (app *App) printUsage() {
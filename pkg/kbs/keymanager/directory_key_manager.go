@@ -0,0 +1,448 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+const (
+	masterKeyLength = 32
+	// suffix of the master key file written while a rotation is in progress
+	pendingMasterKeySuffix = ".new"
+	// suffix of the file locked by the service while it uses the master key and by a rotation
+	masterKeyLockSuffix = ".lock"
+)
+
+// DirectoryManager keeps key material in the key attributes stored by directory.KeyStore,
+// encrypted with AES-256-GCM under a master key read from masterKeyFile.
+type DirectoryManager struct {
+	masterKeyFile string
+	// lockFile holds the shared lock on the master key taken by LockMasterKey
+	lockFile *os.File
+}
+
+func NewDirectoryManager(masterKeyFile string) *DirectoryManager {
+	return &DirectoryManager{masterKeyFile: masterKeyFile}
+}
+
+// LockMasterKey takes a shared lock on the master key that is held as long as the manager is in use,
+// a master key rotation cannot run while the lock is held
+func (dm *DirectoryManager) LockMasterKey() error {
+	defaultLog.Trace("keymanager/directory_key_manager:LockMasterKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:LockMasterKey() Leaving")
+
+	lockFile, err := lockMasterKey(dm.masterKeyFile, syscall.LOCK_SH)
+	if err != nil {
+		return errors.Wrap(err, "master key is being rotated")
+	}
+	dm.lockFile = lockFile
+	return nil
+}
+
+// CreateMasterKey generates a new master key in masterKeyFile, it fails if the file already exists
+func CreateMasterKey(masterKeyFile string) error {
+	defaultLog.Trace("keymanager/directory_key_manager:CreateMasterKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:CreateMasterKey() Leaving")
+
+	masterKey := make([]byte, masterKeyLength)
+	if _, err := rand.Read(masterKey); err != nil {
+		return errors.Wrap(err, "failed to generate master key")
+	}
+
+	file, err := os.OpenFile(masterKeyFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create master key file")
+	}
+	defer func() {
+		derr := file.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing master key file")
+		}
+	}()
+
+	if _, err = file.Write(masterKey); err != nil {
+		return errors.Wrap(err, "failed to write master key file")
+	}
+	return nil
+}
+
+// ValidateMasterKey checks that masterKeyFile holds a usable master key and that no rotation was left incomplete
+func ValidateMasterKey(masterKeyFile string) error {
+	if _, err := readMasterKey(masterKeyFile); err != nil {
+		return err
+	}
+	if _, err := os.Stat(masterKeyFile + pendingMasterKeySuffix); err == nil {
+		return errors.New("master key rotation is incomplete, rerun the rotation to complete it")
+	}
+	return nil
+}
+
+func (dm *DirectoryManager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:CreateKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:CreateKey() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+
+	keyMaterial, err := generateKeyMaterial(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return dm.storeKey(newUuid, request, request.KeyInformation.KeyLength, keyMaterial)
+}
+
+func (dm *DirectoryManager) RotateKey(attributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:RotateKey() Leaving")
+
+	request := rotationRequest(attributes)
+	keyMaterial, err := generateKeyMaterial(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new version is wrapped for the id of the key it belongs to
+	return dm.storeKey(attributes.ID, request, attributes.KeyLength, keyMaterial)
+}
+
+func generateKeyMaterial(request *kbs.KeyRequest) ([]byte, error) {
+	var keyMaterial []byte
+	switch request.KeyInformation.Algorithm {
+	case constants.CRYPTOALG_AES:
+		keyLength := request.KeyInformation.KeyLength
+		if keyLength != 128 && keyLength != 192 && keyLength != 256 {
+			return nil, errors.Errorf("AES key of length %d is not supported", keyLength)
+		}
+		keyMaterial = make([]byte, keyLength/8)
+		if _, err := rand.Read(keyMaterial); err != nil {
+			return nil, errors.Wrap(err, "failed to create AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		privateKey, err := rsa.GenerateKey(rand.Reader, request.KeyInformation.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RSA key pair")
+		}
+		keyMaterial = x509.MarshalPKCS1PrivateKey(privateKey)
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+
+	return keyMaterial, nil
+}
+
+func (dm *DirectoryManager) DeleteKey(attributes *models.KeyAttributes) error {
+	defaultLog.Trace("keymanager/directory_key_manager:DeleteKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:DeleteKey() Leaving")
+
+	// The wrapped key material lives in the key attributes, removing them from the key store removes the key
+	if attributes.KeyData == "" {
+		return errors.New("key is not created with directory key manager")
+	}
+	return nil
+}
+
+// RegisterKey wraps the PEM encoded key material in key_string. AES keys are expected as the raw
+// key bytes in the PEM block, RSA keys as PKCS#1 or PKCS#8 private keys.
+func (dm *DirectoryManager) RegisterKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:RegisterKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:RegisterKey() Leaving")
+
+	if request.KeyInformation.KeyString == "" {
+		return nil, errors.New("key_string cannot be empty for register operation in directory mode")
+	}
+
+	block, _ := pem.Decode([]byte(request.KeyInformation.KeyString))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM formatted key_string")
+	}
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+
+	switch request.KeyInformation.Algorithm {
+	case constants.CRYPTOALG_AES:
+		keyLength := len(block.Bytes) * 8
+		if keyLength != 128 && keyLength != 192 && keyLength != 256 {
+			return nil, errors.Errorf("AES key of length %d is not supported", keyLength)
+		}
+		return dm.storeKey(newUuid, request, keyLength, block.Bytes)
+	case constants.CRYPTOALG_RSA:
+		privateKey, err := parseRsaPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return dm.storeKey(newUuid, request, privateKey.N.BitLen(), x509.MarshalPKCS1PrivateKey(privateKey))
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+}
+
+func (dm *DirectoryManager) TransferKey(attributes *models.KeyAttributes) ([]byte, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:TransferKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:TransferKey() Leaving")
+
+	if attributes.KeyData == "" {
+		return nil, errors.New("key is not created with directory key manager")
+	}
+
+	if attributes.Algorithm != constants.CRYPTOALG_AES && attributes.Algorithm != constants.CRYPTOALG_RSA {
+		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
+	}
+
+	masterKey, err := readMasterKey(dm.masterKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keyMaterial, err := unwrapKey(masterKey, attributes)
+	if err != nil {
+		// a concurrent rotation may already have re-wrapped this key with the pending master key
+		pendingKey, perr := readMasterKey(dm.masterKeyFile + pendingMasterKeySuffix)
+		if perr != nil {
+			return nil, err
+		}
+		return unwrapKey(pendingKey, attributes)
+	}
+	return keyMaterial, nil
+}
+
+// RotateMasterKey generates a new master key and re-wraps every key in the store with it. The new
+// master key is kept next to the current one until all keys are re-wrapped, so an interrupted
+// rotation can be completed by running it again. The rotation is refused while the master key is
+// locked by a running service.
+func (dm *DirectoryManager) RotateMasterKey(store domain.KeyStore) error {
+	defaultLog.Trace("keymanager/directory_key_manager:RotateMasterKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:RotateMasterKey() Leaving")
+
+	lockFile, err := lockMasterKey(dm.masterKeyFile, syscall.LOCK_EX)
+	if err != nil {
+		return errors.Wrap(err, "master key is in use, stop the service before rotating it")
+	}
+	defer func() {
+		derr := lockFile.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing master key lock file")
+		}
+	}()
+
+	currentKey, err := readMasterKey(dm.masterKeyFile)
+	if err != nil {
+		return err
+	}
+
+	pendingKeyFile := dm.masterKeyFile + pendingMasterKeySuffix
+	if _, err = os.Stat(pendingKeyFile); os.IsNotExist(err) {
+		if err = CreateMasterKey(pendingKeyFile); err != nil {
+			return err
+		}
+	} else {
+		defaultLog.Info("keymanager/directory_key_manager:RotateMasterKey() Resuming incomplete master key rotation")
+	}
+	pendingKey, err := readMasterKey(pendingKeyFile)
+	if err != nil {
+		return err
+	}
+
+	if err = rewrapKeys(store, currentKey, pendingKey); err != nil {
+		return err
+	}
+
+	if err = os.Rename(pendingKeyFile, dm.masterKeyFile); err != nil {
+		return errors.Wrap(err, "failed to replace master key")
+	}
+
+	// keys stored with the current master key while the store was re-wrapped are re-wrapped once more
+	return rewrapKeys(store, currentKey, pendingKey)
+}
+
+// rewrapKeys re-wraps every version of the keys in the store from the current to the pending master key
+func rewrapKeys(store domain.KeyStore, currentKey, pendingKey []byte) error {
+	keys, err := store.Search(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve keys from key store")
+	}
+
+	for index := range keys {
+		key := &keys[index]
+		rewrapped := false
+
+		for _, keyData := range keyDataOfAllVersions(key) {
+			if *keyData == "" {
+				continue
+			}
+			newKeyData, err := rewrapKey(currentKey, pendingKey, key.ID, *keyData)
+			if err != nil {
+				return errors.Wrapf(err, "failed to re-wrap key %s", key.ID)
+			}
+			if newKeyData != *keyData {
+				*keyData = newKeyData
+				rewrapped = true
+			}
+		}
+
+		if rewrapped {
+			if _, err = store.Create(key); err != nil {
+				return errors.Wrapf(err, "failed to store re-wrapped key %s", key.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func (dm *DirectoryManager) storeKey(id uuid.UUID, request *kbs.KeyRequest, keyLength int, keyMaterial []byte) (*models.KeyAttributes, error) {
+	masterKey, err := readMasterKey(dm.masterKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keyData, err := wrapKey(masterKey, id, keyMaterial)
+	if err != nil {
+		return nil, err
+	}
+
+	keyAttributes := &models.KeyAttributes{
+		ID:               id,
+		Algorithm:        request.KeyInformation.Algorithm,
+		KeyLength:        keyLength,
+		KeyData:          keyData,
+		TransferPolicyId: request.TransferPolicyID,
+		CreatedAt:        time.Now().UTC(),
+		Label:            request.Label,
+		Usage:            request.Usage,
+	}
+
+	return keyAttributes, nil
+}
+
+// keyDataOfAllVersions returns references to the wrapped key material of every version of the key
+func keyDataOfAllVersions(key *models.KeyAttributes) []*string {
+	keyData := []*string{&key.KeyData}
+	for index := range key.PreviousVersions {
+		keyData = append(keyData, &key.PreviousVersions[index].KeyData)
+	}
+	return keyData
+}
+
+// rewrapKey re-wraps key material from the current to the pending master key, key material that
+// is already wrapped with the pending master key by an earlier, interrupted rotation is returned as is
+func rewrapKey(currentKey, pendingKey []byte, id uuid.UUID, keyData string) (string, error) {
+	keyMaterial, err := unwrapKeyData(currentKey, id, keyData)
+	if err != nil {
+		if _, perr := unwrapKeyData(pendingKey, id, keyData); perr == nil {
+			return keyData, nil
+		}
+		return "", err
+	}
+	return wrapKey(pendingKey, id, keyMaterial)
+}
+
+// lockMasterKey locks the lock file of the master key with the given flock operation, it fails
+// instead of waiting when the lock is held by another process
+func lockMasterKey(masterKeyFile string, how int) (*os.File, error) {
+	lockFile, err := os.OpenFile(masterKeyFile+masterKeyLockSuffix, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open master key lock file")
+	}
+	if err = syscall.Flock(int(lockFile.Fd()), how|syscall.LOCK_NB); err != nil {
+		derr := lockFile.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing master key lock file")
+		}
+		if err == syscall.EWOULDBLOCK {
+			return nil, errors.New("master key is locked by another process")
+		}
+		return nil, errors.Wrap(err, "failed to lock master key")
+	}
+	return lockFile, nil
+}
+
+func readMasterKey(masterKeyFile string) ([]byte, error) {
+	masterKey, err := ioutil.ReadFile(masterKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read master key file")
+	}
+	if len(masterKey) != masterKeyLength {
+		return nil, errors.Errorf("invalid master key length %d", len(masterKey))
+	}
+	return masterKey, nil
+}
+
+// wrapKey encrypts key material under the master key, the key id is bound as additional data so
+// that wrapped key material cannot be swapped between key records
+func wrapKey(masterKey []byte, id uuid.UUID, keyMaterial []byte) (string, error) {
+	gcm, err := newGCM(masterKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+
+	wrappedKey := gcm.Seal(nonce, nonce, keyMaterial, id[:])
+	return base64.StdEncoding.EncodeToString(wrappedKey), nil
+}
+
+func unwrapKey(masterKey []byte, attributes *models.KeyAttributes) ([]byte, error) {
+	return unwrapKeyData(masterKey, attributes.ID, attributes.KeyData)
+}
+
+func unwrapKeyData(masterKey []byte, id uuid.UUID, keyData string) ([]byte, error) {
+	wrappedKey, err := base64.StdEncoding.DecodeString(keyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode wrapped key")
+	}
+
+	gcm, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrappedKey) < gcm.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+
+	keyMaterial, err := gcm.Open(nil, wrappedKey[:gcm.NonceSize()], wrappedKey[gcm.NonceSize():], id[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unwrap key")
+	}
+	return keyMaterial, nil
+}
+
+func newGCM(masterKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize GCM")
+	}
+	return gcm, nil
+}
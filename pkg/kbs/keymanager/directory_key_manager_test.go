@@ -0,0 +1,476 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+func newTestDirectoryManager(t *testing.T) (*DirectoryManager, *directory.KeyStore, string) {
+	tempDir := t.TempDir()
+	keysDir := filepath.Join(tempDir, "keys")
+	if err := os.Mkdir(keysDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	masterKeyFile := filepath.Join(tempDir, "master.key")
+	if err := CreateMasterKey(masterKeyFile); err != nil {
+		t.Fatalf("CreateMasterKey() error = %v", err)
+	}
+	return NewDirectoryManager(masterKeyFile), directory.NewKeyStore(keysDir), masterKeyFile
+}
+
+func TestDirectoryManager_CreateKey(t *testing.T) {
+	keyManager, _, _ := newTestDirectoryManager(t)
+
+	tests := []struct {
+		name      string
+		algorithm string
+		keyLength int
+		wantErr   bool
+	}{
+		{
+			name:      "create symmetric key",
+			algorithm: "AES",
+			keyLength: 256,
+			wantErr:   false,
+		},
+		{
+			name:      "create asymmetric key",
+			algorithm: "RSA",
+			keyLength: 2048,
+			wantErr:   false,
+		},
+		{
+			name:      "negative test - invalid AES key length",
+			algorithm: "AES",
+			keyLength: 2048,
+			wantErr:   true,
+		},
+		{
+			name:      "negative test - algorithm not supported",
+			algorithm: "EC",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyRequest := &kbs.KeyRequest{
+				KeyInformation: &kbs.KeyInformation{
+					Algorithm: tt.algorithm,
+					KeyLength: tt.keyLength,
+				},
+			}
+			keyAttributes, err := keyManager.CreateKey(keyRequest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			keyMaterial, err := keyManager.TransferKey(keyAttributes)
+			if err != nil {
+				t.Fatalf("TransferKey() error = %v", err)
+			}
+			if bytes.Contains([]byte(keyAttributes.KeyData), keyMaterial) {
+				t.Error("CreateKey() stored key material in the clear")
+			}
+			if tt.algorithm == "AES" && len(keyMaterial) != tt.keyLength/8 {
+				t.Errorf("TransferKey() key length = %d, want %d", len(keyMaterial)*8, tt.keyLength)
+			}
+			if tt.algorithm == "RSA" {
+				if _, err = x509.ParsePKCS1PrivateKey(keyMaterial); err != nil {
+					t.Errorf("TransferKey() returned invalid RSA key: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDirectoryManager_RegisterKey(t *testing.T) {
+	keyManager, _, _ := newTestDirectoryManager(t)
+
+	aesKey := bytes.Repeat([]byte{0x5a}, 16)
+	keyRequest := &kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{
+			Algorithm: "AES",
+			KeyString: string(pem.EncodeToMemory(&pem.Block{Type: "AES KEY", Bytes: aesKey})),
+		},
+	}
+	keyAttributes, err := keyManager.RegisterKey(keyRequest)
+	if err != nil {
+		t.Fatalf("RegisterKey() error = %v", err)
+	}
+	if keyAttributes.KeyLength != 128 {
+		t.Errorf("RegisterKey() key length = %d, want 128", keyAttributes.KeyLength)
+	}
+
+	keyMaterial, err := keyManager.TransferKey(keyAttributes)
+	if err != nil {
+		t.Fatalf("TransferKey() error = %v", err)
+	}
+	if !bytes.Equal(keyMaterial, aesKey) {
+		t.Error("TransferKey() returned key material different from registered key")
+	}
+
+	keyRequest.KeyInformation.KeyString = ""
+	if _, err = keyManager.RegisterKey(keyRequest); err == nil {
+		t.Error("RegisterKey() succeeded without key_string")
+	}
+}
+
+func TestDirectoryManager_TransferKeyWrongMasterKey(t *testing.T) {
+	keyManager, _, masterKeyFile := newTestDirectoryManager(t)
+
+	keyAttributes, err := keyManager.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+
+	if err = ioutil.WriteFile(masterKeyFile, bytes.Repeat([]byte{0x01}, masterKeyLength), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = keyManager.TransferKey(keyAttributes); err == nil {
+		t.Error("TransferKey() succeeded with a different master key")
+	}
+
+	if err = keyManager.DeleteKey(keyAttributes); err != nil {
+		t.Errorf("DeleteKey() error = %v", err)
+	}
+}
+
+func TestDirectoryManager_RotateMasterKey(t *testing.T) {
+	keyManager, keyStore, masterKeyFile := newTestDirectoryManager(t)
+
+	var transferred [][]byte
+	var keyIDs []string
+	for _, request := range []*kbs.KeyRequest{
+		{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}},
+		{KeyInformation: &kbs.KeyInformation{Algorithm: "RSA", KeyLength: 2048}},
+	} {
+		keyAttributes, err := keyManager.CreateKey(request)
+		if err != nil {
+			t.Fatalf("CreateKey() error = %v", err)
+		}
+		if _, err = keyStore.Create(keyAttributes); err != nil {
+			t.Fatal(err)
+		}
+		keyMaterial, err := keyManager.TransferKey(keyAttributes)
+		if err != nil {
+			t.Fatalf("TransferKey() error = %v", err)
+		}
+		transferred = append(transferred, keyMaterial)
+		keyIDs = append(keyIDs, keyAttributes.ID.String())
+	}
+
+	oldMasterKey, err := ioutil.ReadFile(masterKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = keyManager.RotateMasterKey(keyStore); err != nil {
+		t.Fatalf("RotateMasterKey() error = %v", err)
+	}
+
+	newMasterKey, err := ioutil.ReadFile(masterKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(oldMasterKey, newMasterKey) {
+		t.Error("RotateMasterKey() did not replace the master key")
+	}
+	if err = ValidateMasterKey(masterKeyFile); err != nil {
+		t.Errorf("ValidateMasterKey() error = %v", err)
+	}
+
+	keys, err := keyStore.Search(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		key := key
+		if _, err = unwrapKey(oldMasterKey, &key); err == nil {
+			t.Errorf("key %s is still wrapped with the old master key", key.ID)
+		}
+		keyMaterial, err := keyManager.TransferKey(&key)
+		if err != nil {
+			t.Fatalf("TransferKey() after rotation error = %v", err)
+		}
+		for index, id := range keyIDs {
+			if id == key.ID.String() && !bytes.Equal(keyMaterial, transferred[index]) {
+				t.Errorf("key %s changed during master key rotation", id)
+			}
+		}
+	}
+}
+
+func TestDirectoryManager_ResumeRotateMasterKey(t *testing.T) {
+	keyManager, keyStore, masterKeyFile := newTestDirectoryManager(t)
+
+	keyAttributes, err := keyManager.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 128}})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	if _, err = keyStore.Create(keyAttributes); err != nil {
+		t.Fatal(err)
+	}
+	keyMaterial, err := keyManager.TransferKey(keyAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a rotation interrupted after the key was re-wrapped but before the master key was replaced
+	if err = CreateMasterKey(masterKeyFile + pendingMasterKeySuffix); err != nil {
+		t.Fatal(err)
+	}
+	pendingKey, err := readMasterKey(masterKeyFile + pendingMasterKeySuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyAttributes.KeyData, err = wrapKey(pendingKey, keyAttributes.ID, keyMaterial); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = keyStore.Create(keyAttributes); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ValidateMasterKey(masterKeyFile); err == nil {
+		t.Error("ValidateMasterKey() succeeded with incomplete rotation")
+	}
+	if transferredKey, err := keyManager.TransferKey(keyAttributes); err != nil || !bytes.Equal(transferredKey, keyMaterial) {
+		t.Errorf("TransferKey() during rotation error = %v", err)
+	}
+
+	if err = keyManager.RotateMasterKey(keyStore); err != nil {
+		t.Fatalf("RotateMasterKey() error = %v", err)
+	}
+	if err = ValidateMasterKey(masterKeyFile); err != nil {
+		t.Errorf("ValidateMasterKey() error = %v", err)
+	}
+	if transferredKey, err := keyManager.TransferKey(keyAttributes); err != nil || !bytes.Equal(transferredKey, keyMaterial) {
+		t.Errorf("TransferKey() after resumed rotation error = %v", err)
+	}
+}
+
+func TestDirectoryManager_RotateLockedMasterKey(t *testing.T) {
+	keyManager, keyStore, masterKeyFile := newTestDirectoryManager(t)
+
+	// the master key is locked by the running service
+	serviceManager := NewDirectoryManager(masterKeyFile)
+	if err := serviceManager.LockMasterKey(); err != nil {
+		t.Fatalf("LockMasterKey() error = %v", err)
+	}
+	if err := keyManager.RotateMasterKey(keyStore); err == nil {
+		t.Error("RotateMasterKey() succeeded while the master key is locked")
+	}
+	if _, err := os.Stat(masterKeyFile + pendingMasterKeySuffix); !os.IsNotExist(err) {
+		t.Error("RotateMasterKey() created a pending master key while the master key is locked")
+	}
+
+	if err := serviceManager.lockFile.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyManager.RotateMasterKey(keyStore); err != nil {
+		t.Fatalf("RotateMasterKey() error = %v", err)
+	}
+	if err := serviceManager.LockMasterKey(); err != nil {
+		t.Errorf("LockMasterKey() after rotation error = %v", err)
+	}
+}
+
+// concurrentCreateKeyStore stores a key wrapped with the current master key once the keys of the store
+// have been searched, as a key created during a master key rotation would be
+type concurrentCreateKeyStore struct {
+	*directory.KeyStore
+	keyManager *DirectoryManager
+	created    *models.KeyAttributes
+}
+
+func (store *concurrentCreateKeyStore) Search(criteria *models.KeyFilterCriteria) ([]models.KeyAttributes, error) {
+	keys, err := store.KeyStore.Search(criteria)
+	if err != nil || store.created != nil {
+		return keys, err
+	}
+	if store.created, err = store.keyManager.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}}); err != nil {
+		return nil, err
+	}
+	_, err = store.KeyStore.Create(store.created)
+	return keys, err
+}
+
+func TestDirectoryManager_RotateMasterKeyRescan(t *testing.T) {
+	keyManager, keyStore, masterKeyFile := newTestDirectoryManager(t)
+	oldMasterKey, err := ioutil.ReadFile(masterKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &concurrentCreateKeyStore{KeyStore: keyStore, keyManager: keyManager}
+	if err = keyManager.RotateMasterKey(store); err != nil {
+		t.Fatalf("RotateMasterKey() error = %v", err)
+	}
+	keyMaterial, err := unwrapKey(oldMasterKey, store.created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the key created during the rotation is re-wrapped with the new master key
+	rewrapped, err := keyStore.Retrieve(store.created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transferredKey, err := keyManager.TransferKey(rewrapped)
+	if err != nil {
+		t.Fatalf("TransferKey() after rotation error = %v", err)
+	}
+	if !bytes.Equal(transferredKey, keyMaterial) {
+		t.Error("key created during the master key rotation changed")
+	}
+}
+
+func TestRemoteManager_RotateKey(t *testing.T) {
+	keyManager, keyStore, _ := newTestDirectoryManager(t)
+	remoteManager := NewRemoteManager(keyStore, keyManager, "https://kbs.com:9443/kbs/v1/")
+
+	keyResponse, err := remoteManager.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	keyID := keyResponse.KeyInformation.ID
+
+	firstKey, version, err := remoteManager.TransferKey(keyID, 0)
+	if err != nil || version != 1 {
+		t.Fatalf("TransferKey() version = %d, error = %v", version, err)
+	}
+
+	keyResponse, err = remoteManager.RotateKey(keyID)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	if keyResponse.CurrentVersion != 2 || len(keyResponse.Versions) != 2 {
+		t.Errorf("RotateKey() current version = %d, versions = %d, want 2, 2", keyResponse.CurrentVersion, len(keyResponse.Versions))
+	}
+
+	secondKey, version, err := remoteManager.TransferKey(keyID, 0)
+	if err != nil || version != 2 {
+		t.Fatalf("TransferKey() version = %d, error = %v", version, err)
+	}
+	if bytes.Equal(firstKey, secondKey) {
+		t.Error("RotateKey() did not create new key material")
+	}
+
+	previousKey, version, err := remoteManager.TransferKey(keyID, 1)
+	if err != nil || version != 1 {
+		t.Fatalf("TransferKey() version = %d, error = %v", version, err)
+	}
+	if !bytes.Equal(firstKey, previousKey) {
+		t.Error("TransferKey() returned wrong key material for previous version")
+	}
+
+	if _, _, err = remoteManager.TransferKey(keyID, 3); errors.Cause(err) != models.ErrKeyVersionNotFound {
+		t.Errorf("TransferKey() of unknown version error = %v, want %v", err, models.ErrKeyVersionNotFound)
+	}
+
+	if err = remoteManager.DeleteKey(keyID); err != nil {
+		t.Errorf("DeleteKey() error = %v", err)
+	}
+}
+
+func TestRemoteManager_KeyLifecycle(t *testing.T) {
+	keyManager, keyStore, _ := newTestDirectoryManager(t)
+	remoteManager := NewRemoteManager(keyStore, keyManager, "https://kbs.com:9443/kbs/v1/")
+
+	notBefore := time.Now().Add(time.Hour)
+	keyResponse, err := remoteManager.CreateKey(&kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256},
+		NotBefore:      &notBefore,
+	})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	keyID := keyResponse.KeyInformation.ID
+	if keyResponse.State != kbs.KeyStatePreActive {
+		t.Errorf("CreateKey() state = %s, want %s", keyResponse.State, kbs.KeyStatePreActive)
+	}
+	if _, _, err = remoteManager.TransferKey(keyID, 0); errors.Cause(err) != models.ErrKeyNotActive {
+		t.Errorf("TransferKey() of pre-active key error = %v, want %v", err, models.ErrKeyNotActive)
+	}
+
+	if keyResponse, err = remoteManager.SetKeyState(keyID, kbs.KeyStateActive); err != nil || keyResponse.State != kbs.KeyStateActive {
+		t.Fatalf("SetKeyState() state = %v, error = %v", keyResponse, err)
+	}
+	if _, _, err = remoteManager.TransferKey(keyID, 0); err != nil {
+		t.Errorf("TransferKey() of active key error = %v", err)
+	}
+
+	if _, err = remoteManager.SetKeyState(keyID, kbs.KeyStateCompromised); err != nil {
+		t.Fatalf("SetKeyState() error = %v", err)
+	}
+	if _, _, err = remoteManager.TransferKey(keyID, 0); errors.Cause(err) != models.ErrKeyNotActive {
+		t.Errorf("TransferKey() of compromised key error = %v, want %v", err, models.ErrKeyNotActive)
+	}
+	if _, err = remoteManager.SetKeyState(keyID, kbs.KeyStateActive); errors.Cause(err) != models.ErrInvalidKeyStateTransition {
+		t.Errorf("SetKeyState() of compromised key error = %v, want %v", err, models.ErrInvalidKeyStateTransition)
+	}
+}
+
+func TestRemoteManager_DeactivateExpiredKeys(t *testing.T) {
+	keyManager, keyStore, _ := newTestDirectoryManager(t)
+	remoteManager := NewRemoteManager(keyStore, keyManager, "https://kbs.com:9443/kbs/v1/")
+
+	notAfter := time.Now().Add(time.Hour)
+	keyResponse, err := remoteManager.CreateKey(&kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256},
+		NotAfter:       &notAfter,
+	})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	if _, err = remoteManager.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 128}}); err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+
+	deactivated, err := remoteManager.DeactivateExpiredKeys()
+	if err != nil || len(deactivated) != 0 {
+		t.Fatalf("DeactivateExpiredKeys() deactivated = %v, error = %v", deactivated, err)
+	}
+
+	// move the expiry date of the key to the past
+	keyAttributes, err := keyStore.Retrieve(keyResponse.KeyInformation.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := time.Now().Add(-time.Minute)
+	keyAttributes.NotAfter = &expired
+	if _, err = keyStore.Create(keyAttributes); err != nil {
+		t.Fatal(err)
+	}
+
+	deactivated, err = remoteManager.DeactivateExpiredKeys()
+	if err != nil || len(deactivated) != 1 || deactivated[0] != keyAttributes.ID {
+		t.Fatalf("DeactivateExpiredKeys() deactivated = %v, error = %v", deactivated, err)
+	}
+	if keyAttributes, err = keyStore.Retrieve(keyAttributes.ID); err != nil || keyAttributes.State != kbs.KeyStateDeactivated {
+		t.Errorf("DeactivateExpiredKeys() stored state = %v, error = %v", keyAttributes, err)
+	}
+
+	keys, err := remoteManager.SearchKeys(&models.KeyFilterCriteria{State: kbs.KeyStateActive})
+	if err != nil || len(keys) != 1 {
+		t.Errorf("SearchKeys() by state returned %d keys, error = %v", len(keys), err)
+	}
+}
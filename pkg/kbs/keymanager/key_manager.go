@@ -11,6 +11,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/kmipclient"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/pkcs11client"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
 	"github.com/pkg/errors"
@@ -22,7 +23,8 @@ func NewKeyManager(cfg *config.Configuration) (KeyManager, error) {
 	defaultLog.Trace("keymanager/key_manager:NewKeyManager() Entering")
 	defer defaultLog.Trace("keymanager/key_manager:NewKeyManager() Leaving")
 
-	if strings.ToLower(cfg.KeyManager) == constants.KmipKeyManager {
+	switch strings.ToLower(cfg.KeyManager) {
+	case constants.KmipKeyManager:
 		kmipClient := kmipclient.NewKmipClient()
 		err := kmipClient.InitializeClient(cfg.Kmip.Version, cfg.Kmip.ServerIP, cfg.Kmip.ServerPort, cfg.Kmip.Hostname, cfg.Kmip.Username, cfg.Kmip.Password, cfg.Kmip.ClientKeyFilePath, cfg.Kmip.ClientCertificateFilePath, cfg.Kmip.RootCertificateFilePath)
 		if err != nil {
@@ -30,7 +32,26 @@ func NewKeyManager(cfg *config.Configuration) (KeyManager, error) {
 			return nil, errors.New("Failed to initialize KeyManager")
 		}
 		return NewKmipManager(kmipClient), nil
-	} else {
+	case constants.Pkcs11KeyManager:
+		pkcs11Client := pkcs11client.NewPkcs11Client()
+		err := pkcs11Client.InitializeClient(cfg.Pkcs11.ModulePath, cfg.Pkcs11.Slot, cfg.Pkcs11.Pin)
+		if err != nil {
+			defaultLog.WithError(err).Error("keymanager/key_manager:NewKeyManager() Failed to initialize client")
+			return nil, errors.New("Failed to initialize KeyManager")
+		}
+		return NewPkcs11Manager(pkcs11Client), nil
+	case constants.DirectoryKeyManager:
+		if err := ValidateMasterKey(constants.DefaultMasterKeyFile); err != nil {
+			defaultLog.WithError(err).Error("keymanager/key_manager:NewKeyManager() Failed to load master key")
+			return nil, errors.New("Failed to initialize KeyManager")
+		}
+		directoryManager := NewDirectoryManager(constants.DefaultMasterKeyFile)
+		if err := directoryManager.LockMasterKey(); err != nil {
+			defaultLog.WithError(err).Error("keymanager/key_manager:NewKeyManager() Failed to lock master key")
+			return nil, errors.New("Failed to initialize KeyManager")
+		}
+		return directoryManager, nil
+	default:
 		defaultLog.Errorf("keymanager/key_manager:NewKeyManager() No Key Manager supported for provider: %s", cfg.KeyManager)
 		return nil, errors.Errorf("No Key Manager supported for provider: %s", cfg.KeyManager)
 	}
@@ -41,4 +62,19 @@ type KeyManager interface {
 	DeleteKey(*models.KeyAttributes) error
 	RegisterKey(*kbs.KeyRequest) (*models.KeyAttributes, error)
 	TransferKey(*models.KeyAttributes) ([]byte, error)
+	RotateKey(*models.KeyAttributes) (*models.KeyAttributes, error)
+}
+
+// rotationRequest builds the request for creating new key material with the same properties as the given key
+func rotationRequest(attributes *models.KeyAttributes) *kbs.KeyRequest {
+	return &kbs.KeyRequest{
+		KeyInformation: &kbs.KeyInformation{
+			Algorithm: attributes.Algorithm,
+			KeyLength: attributes.KeyLength,
+			CurveType: attributes.CurveType,
+		},
+		TransferPolicyID: attributes.TransferPolicyId,
+		Label:            attributes.Label,
+		Usage:            attributes.Usage,
+	}
 }
@@ -114,3 +114,10 @@ func (km *KmipManager) TransferKey(attributes *models.KeyAttributes) ([]byte, er
 		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
 	}
 }
+
+func (km *KmipManager) RotateKey(attributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/kmip_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/kmip_key_manager:RotateKey() Leaving")
+
+	return km.CreateKey(rotationRequest(attributes))
+}
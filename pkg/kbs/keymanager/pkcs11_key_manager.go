@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/pkcs11client"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+type Pkcs11Manager struct {
+	client pkcs11client.Pkcs11Client
+}
+
+func NewPkcs11Manager(c pkcs11client.Pkcs11Client) *Pkcs11Manager {
+	return &Pkcs11Manager{c}
+}
+
+func (pm *Pkcs11Manager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:CreateKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:CreateKey() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+
+	keyAttributes := &models.KeyAttributes{
+		ID:               newUuid,
+		Algorithm:        request.KeyInformation.Algorithm,
+		TransferPolicyId: request.TransferPolicyID,
+		Label:            request.Label,
+		Usage:            request.Usage,
+	}
+
+	pkcs11KeyID := newUuid.String()
+	switch request.KeyInformation.Algorithm {
+	case constants.CRYPTOALG_AES:
+		err = pm.client.CreateSymmetricKey(pkcs11KeyID, request.KeyInformation.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		err = pm.client.CreateAsymmetricKeyPair(pkcs11KeyID, constants.CRYPTOALG_RSA, request.KeyInformation.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RSA key pair")
+		}
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+
+	keyAttributes.KeyLength = request.KeyInformation.KeyLength
+	keyAttributes.Pkcs11KeyID = pkcs11KeyID
+	keyAttributes.CreatedAt = time.Now().UTC()
+
+	return keyAttributes, nil
+}
+
+func (pm *Pkcs11Manager) DeleteKey(attributes *models.KeyAttributes) error {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:DeleteKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:DeleteKey() Leaving")
+
+	if attributes.Pkcs11KeyID == "" {
+		return errors.New("key is not created with PKCS#11 key manager")
+	}
+
+	return pm.client.DeleteKey(attributes.Pkcs11KeyID)
+}
+
+// RegisterKey imports the PEM encoded key material in key_string into the token. AES keys are
+// expected as the raw key bytes in the PEM block, RSA keys as PKCS#1 or PKCS#8 private keys.
+func (pm *Pkcs11Manager) RegisterKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:RegisterKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:RegisterKey() Leaving")
+
+	if request.KeyInformation.KeyString == "" {
+		return nil, errors.New("key_string cannot be empty for register operation in pkcs11 mode")
+	}
+
+	block, _ := pem.Decode([]byte(request.KeyInformation.KeyString))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM formatted key_string")
+	}
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+	pkcs11KeyID := newUuid.String()
+
+	var keyLength int
+	switch request.KeyInformation.Algorithm {
+	case constants.CRYPTOALG_AES:
+		keyLength = len(block.Bytes) * 8
+		if keyLength != 128 && keyLength != 192 && keyLength != 256 {
+			return nil, errors.Errorf("AES key of length %d is not supported", keyLength)
+		}
+		if err = pm.client.ImportSymmetricKey(pkcs11KeyID, block.Bytes); err != nil {
+			return nil, errors.Wrap(err, "failed to register AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		privateKey, err := parseRsaPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keyLength = privateKey.N.BitLen()
+		if err = pm.client.ImportRsaPrivateKey(pkcs11KeyID, privateKey); err != nil {
+			return nil, errors.Wrap(err, "failed to register RSA key")
+		}
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+
+	keyAttributes := &models.KeyAttributes{
+		ID:               newUuid,
+		Algorithm:        request.KeyInformation.Algorithm,
+		KeyLength:        keyLength,
+		Pkcs11KeyID:      pkcs11KeyID,
+		TransferPolicyId: request.TransferPolicyID,
+		CreatedAt:        time.Now().UTC(),
+		Label:            request.Label,
+		Usage:            request.Usage,
+	}
+
+	return keyAttributes, nil
+}
+
+func (pm *Pkcs11Manager) TransferKey(attributes *models.KeyAttributes) ([]byte, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:TransferKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:TransferKey() Leaving")
+
+	if attributes.Pkcs11KeyID == "" {
+		return nil, errors.New("key is not created with PKCS#11 key manager")
+	}
+
+	if attributes.Algorithm == constants.CRYPTOALG_AES || attributes.Algorithm == constants.CRYPTOALG_RSA {
+		return pm.client.GetKey(attributes.Pkcs11KeyID, attributes.Algorithm)
+	} else {
+		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
+	}
+}
+
+func (pm *Pkcs11Manager) RotateKey(attributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:RotateKey() Leaving")
+
+	return pm.CreateKey(rotationRequest(attributes))
+}
+
+func parseRsaPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if privateKey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return privateKey, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse RSA private key")
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key_string does not contain an RSA private key")
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/pkcs11client"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPkcs11Manager_CreateKey(t *testing.T) {
+
+	type args struct {
+		algorithm string
+		keyLength int
+		funcName  string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "create symmetric key",
+			args: args{
+				algorithm: "AES",
+				keyLength: 256,
+				funcName:  "CreateSymmetricKey",
+			},
+			wantErr: false,
+		},
+		{
+			name: "create asymmetric key",
+			args: args{
+				algorithm: "RSA",
+				keyLength: 2048,
+				funcName:  "CreateAsymmetricKeyPair",
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative test - algorithm not supported",
+			args: args{
+				algorithm: "EC",
+				funcName:  "CreateAsymmetricKeyPair",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			keyRequest := &kbs.KeyRequest{
+				KeyInformation: &kbs.KeyInformation{
+					Algorithm: tt.args.algorithm,
+					KeyLength: tt.args.keyLength,
+				},
+			}
+
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On("CreateSymmetricKey", mock.Anything, mock.Anything).Return(nil)
+			mockClient.On("CreateAsymmetricKeyPair", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			keyManager := &Pkcs11Manager{mockClient}
+			keyAttributes, err := keyManager.CreateKey(keyRequest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && keyAttributes.Pkcs11KeyID != keyAttributes.ID.String() {
+				t.Errorf("CreateKey() pkcs11 key id = %s, want %s", keyAttributes.Pkcs11KeyID, keyAttributes.ID.String())
+			}
+		})
+	}
+}
+
+func TestPkcs11Manager_DeleteKey(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		pkcs11KeyID string
+		wantErr     bool
+	}{
+		{
+			name:        "delete key",
+			pkcs11KeyID: "1",
+			wantErr:     false,
+		},
+		{
+			name:        "negative test - key id is empty",
+			pkcs11KeyID: "",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On("DeleteKey", mock.Anything).Return(nil)
+			keyManager := &Pkcs11Manager{mockClient}
+			err := keyManager.DeleteKey(&models.KeyAttributes{Pkcs11KeyID: tt.pkcs11KeyID})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPkcs11Manager_RegisterKey(t *testing.T) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPem := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	aesPem := string(pem.EncodeToMemory(&pem.Block{Type: "AES KEY", Bytes: make([]byte, 32)}))
+	shortAesPem := string(pem.EncodeToMemory(&pem.Block{Type: "AES KEY", Bytes: make([]byte, 10)}))
+
+	tests := []struct {
+		name          string
+		algorithm     string
+		keyString     string
+		wantKeyLength int
+		wantErr       bool
+	}{
+		{
+			name:          "register AES key",
+			algorithm:     "AES",
+			keyString:     aesPem,
+			wantKeyLength: 256,
+			wantErr:       false,
+		},
+		{
+			name:          "register RSA key",
+			algorithm:     "RSA",
+			keyString:     rsaPem,
+			wantKeyLength: 2048,
+			wantErr:       false,
+		},
+		{
+			name:      "negative test - key string is empty",
+			algorithm: "AES",
+			wantErr:   true,
+		},
+		{
+			name:      "negative test - invalid AES key length",
+			algorithm: "AES",
+			keyString: shortAesPem,
+			wantErr:   true,
+		},
+		{
+			name:      "negative test - AES key registered as RSA",
+			algorithm: "RSA",
+			keyString: aesPem,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			keyRequest := &kbs.KeyRequest{
+				KeyInformation: &kbs.KeyInformation{
+					Algorithm: tt.algorithm,
+					KeyString: tt.keyString,
+				},
+			}
+
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On("ImportSymmetricKey", mock.Anything, mock.Anything).Return(nil)
+			mockClient.On("ImportRsaPrivateKey", mock.Anything, mock.Anything).Return(nil)
+			keyManager := &Pkcs11Manager{mockClient}
+			keyAttributes, err := keyManager.RegisterKey(keyRequest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && keyAttributes.KeyLength != tt.wantKeyLength {
+				t.Errorf("RegisterKey() key length = %d, want %d", keyAttributes.KeyLength, tt.wantKeyLength)
+			}
+		})
+	}
+}
+
+func TestPkcs11Manager_TransferKey(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		algorithm   string
+		pkcs11KeyID string
+		wantErr     bool
+	}{
+		{
+			name:        "get symmetric key",
+			algorithm:   "AES",
+			pkcs11KeyID: "1",
+			wantErr:     false,
+		},
+		{
+			name:        "get asymmetric key",
+			algorithm:   "RSA",
+			pkcs11KeyID: "2",
+			wantErr:     false,
+		},
+		{
+			name:        "negative test - key not created with pkcs11 key manager",
+			algorithm:   "AES",
+			pkcs11KeyID: "",
+			wantErr:     true,
+		},
+		{
+			name:        "negative test - algorithm not supported",
+			algorithm:   "ECB",
+			pkcs11KeyID: "1",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			keyAttributes := &models.KeyAttributes{
+				Algorithm:   tt.algorithm,
+				Pkcs11KeyID: tt.pkcs11KeyID,
+			}
+
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On("GetKey", mock.Anything, mock.Anything).Return([]byte(""), nil)
+			keyManager := &Pkcs11Manager{mockClient}
+			_, err := keyManager.TransferKey(keyAttributes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransferKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,11 +7,13 @@ package keymanager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain/models"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/kbs"
+	"github.com/pkg/errors"
 )
 
 type RemoteManager struct {
@@ -38,6 +40,7 @@ func (rm *RemoteManager) CreateKey(request *kbs.KeyRequest) (*kbs.KeyResponse, e
 	}
 
 	keyAttributes.TransferLink = rm.getTransferLink(keyAttributes.ID)
+	setKeyLifecycle(keyAttributes, request)
 	storedKey, err := rm.store.Create(keyAttributes)
 	if err != nil {
 		return nil, err
@@ -67,8 +70,10 @@ func (rm *RemoteManager) DeleteKey(keyId uuid.UUID) error {
 		return err
 	}
 
-	if err := rm.manager.DeleteKey(keyAttributes); err != nil {
-		return err
+	for _, versionAttributes := range keyAttributes.AllVersions() {
+		if err := rm.manager.DeleteKey(versionAttributes); err != nil {
+			return err
+		}
 	}
 
 	return rm.store.Delete(keyId)
@@ -101,6 +106,7 @@ func (rm *RemoteManager) RegisterKey(request *kbs.KeyRequest) (*kbs.KeyResponse,
 	}
 
 	keyAttributes.TransferLink = rm.getTransferLink(keyAttributes.ID)
+	setKeyLifecycle(keyAttributes, request)
 	storedKey, err := rm.store.Create(keyAttributes)
 	if err != nil {
 		return nil, err
@@ -109,16 +115,116 @@ func (rm *RemoteManager) RegisterKey(request *kbs.KeyRequest) (*kbs.KeyResponse,
 	return storedKey.ToKeyResponse(), nil
 }
 
-func (rm *RemoteManager) TransferKey(keyId uuid.UUID) ([]byte, error) {
+// TransferKey returns the key material of the requested version of a key along with that version,
+// version 0 selects the current version
+func (rm *RemoteManager) TransferKey(keyId uuid.UUID, version int) ([]byte, int, error) {
 	defaultLog.Trace("keymanager/remote_key_manager:TransferKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:TransferKey() Leaving")
 
 	keyAttributes, err := rm.store.Retrieve(keyId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = keyAttributes.IsActive(time.Now().UTC()); err != nil {
+		return nil, 0, err
+	}
+
+	versionAttributes, err := keyAttributes.AtVersion(version)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	secretKey, err := rm.manager.TransferKey(versionAttributes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return secretKey, versionAttributes.GetCurrentVersion(), nil
+}
+
+// RotateKey creates new key material for a key and makes it the current version, previous versions stay transferable
+func (rm *RemoteManager) RotateKey(keyId uuid.UUID) (*kbs.KeyResponse, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:RotateKey() Leaving")
+
+	keyAttributes, err := rm.store.Retrieve(keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	newVersion, err := rm.manager.RotateKey(keyAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	keyAttributes.Rotate(newVersion)
+	storedKey, err := rm.store.Create(keyAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedKey.ToKeyResponse(), nil
+}
+
+// SetKeyState moves a key to the requested lifecycle state
+func (rm *RemoteManager) SetKeyState(keyId uuid.UUID, state string) (*kbs.KeyResponse, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:SetKeyState() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:SetKeyState() Leaving")
+
+	keyAttributes, err := rm.store.Retrieve(keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = keyAttributes.SetState(state, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	storedKey, err := rm.store.Create(keyAttributes)
 	if err != nil {
 		return nil, err
 	}
 
-	return rm.manager.TransferKey(keyAttributes)
+	return storedKey.ToKeyResponse(), nil
+}
+
+// DeactivateExpiredKeys records the deactivated state for all keys that are past their expiry date
+// and returns the ids of the keys that were deactivated
+func (rm *RemoteManager) DeactivateExpiredKeys() ([]uuid.UUID, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:DeactivateExpiredKeys() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:DeactivateExpiredKeys() Leaving")
+
+	now := time.Now().UTC()
+	keyAttributesList, err := rm.store.Search(&models.KeyFilterCriteria{ExpiresBefore: &now})
+	if err != nil {
+		return nil, err
+	}
+
+	var deactivated []uuid.UUID
+	for index := range keyAttributesList {
+		keyAttributes := &keyAttributesList[index]
+		if keyAttributes.State == kbs.KeyStateDeactivated || keyAttributes.State == kbs.KeyStateCompromised {
+			continue
+		}
+
+		keyAttributes.State = kbs.KeyStateDeactivated
+		if _, err = rm.store.Create(keyAttributes); err != nil {
+			return deactivated, errors.Wrapf(err, "failed to deactivate key %s", keyAttributes.ID)
+		}
+		deactivated = append(deactivated, keyAttributes.ID)
+	}
+	return deactivated, nil
+}
+
+// setKeyLifecycle sets the activation and expiry dates requested for a new key along with its initial state
+func setKeyLifecycle(keyAttributes *models.KeyAttributes, request *kbs.KeyRequest) {
+	keyAttributes.NotBefore = request.NotBefore
+	keyAttributes.NotAfter = request.NotAfter
+	if request.NotBefore != nil && time.Now().Before(*request.NotBefore) {
+		keyAttributes.State = kbs.KeyStatePreActive
+	} else {
+		keyAttributes.State = kbs.KeyStateActive
+	}
 }
 
 func (rm *RemoteManager) getTransferLink(keyId uuid.UUID) string {
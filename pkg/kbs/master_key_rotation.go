@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/keymanager"
+	cos "github.com/intel-secl/intel-secl/v4/pkg/lib/common/os"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/utils"
+	"github.com/pkg/errors"
+)
+
+// rotateMasterKey re-wraps all keys held by the directory key manager with a newly generated master key
+func (app *App) rotateMasterKey() error {
+	c := app.configuration()
+	if c == nil {
+		return errors.New("Failed to load configuration file")
+	}
+	if strings.ToLower(c.KeyManager) != constants.DirectoryKeyManager {
+		return errors.Errorf("Master key rotation is not supported for key manager %s", c.KeyManager)
+	}
+
+	fmt.Fprintln(app.consoleWriter(), "Rotating master key")
+	dm := keymanager.NewDirectoryManager(constants.DefaultMasterKeyFile)
+	if err := dm.RotateMasterKey(directory.NewKeyStore(constants.KeysDir)); err != nil {
+		return errors.Wrap(err, "Failed to rotate master key")
+	}
+	fmt.Fprintln(app.consoleWriter(), "Master key rotated successfully")
+
+	// Containers are always run as non root users, does not require changing ownership of rotated files
+	if utils.IsContainerEnv() {
+		return nil
+	}
+	if err := cos.ChownDirForUser(constants.ServiceUserName, constants.KeysDir); err != nil {
+		return err
+	}
+	return cos.ChownDirForUser(constants.ServiceUserName, app.configDir())
+}
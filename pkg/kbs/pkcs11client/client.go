@@ -0,0 +1,18 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import "crypto/rsa"
+
+type Pkcs11Client interface {
+	InitializeClient(string, uint, string) error
+	CreateSymmetricKey(string, int) error
+	CreateAsymmetricKeyPair(string, string, int) error
+	ImportSymmetricKey(string, []byte) error
+	ImportRsaPrivateKey(string, *rsa.PrivateKey) error
+	DeleteKey(string) error
+	GetKey(string, string) ([]byte, error)
+	Close() error
+}
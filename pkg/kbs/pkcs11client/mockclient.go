@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"crypto/rsa"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPkcs11Client is a mock of Pkcs11Client interface
+type MockPkcs11Client struct {
+	mock.Mock
+}
+
+// NewMockPkcs11Client creates a new mock instance
+func NewMockPkcs11Client() *MockPkcs11Client {
+	return &MockPkcs11Client{}
+}
+
+// InitializeClient mocks base method
+func (m *MockPkcs11Client) InitializeClient(modulePath string, slot uint, pin string) error {
+	args := m.Called(modulePath, slot, pin)
+	return args.Error(0)
+}
+
+// CreateSymmetricKey mocks base method
+func (m *MockPkcs11Client) CreateSymmetricKey(id string, length int) error {
+	args := m.Called(id, length)
+	return args.Error(0)
+}
+
+// CreateAsymmetricKeyPair mocks base method
+func (m *MockPkcs11Client) CreateAsymmetricKeyPair(id, algorithm string, length int) error {
+	args := m.Called(id, algorithm, length)
+	return args.Error(0)
+}
+
+// ImportSymmetricKey mocks base method
+func (m *MockPkcs11Client) ImportSymmetricKey(id string, key []byte) error {
+	args := m.Called(id, key)
+	return args.Error(0)
+}
+
+// ImportRsaPrivateKey mocks base method
+func (m *MockPkcs11Client) ImportRsaPrivateKey(id string, key *rsa.PrivateKey) error {
+	args := m.Called(id, key)
+	return args.Error(0)
+}
+
+// DeleteKey mocks base method
+func (m *MockPkcs11Client) DeleteKey(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// GetKey mocks base method
+func (m *MockPkcs11Client) GetKey(id string, algorithm string) ([]byte, error) {
+	args := m.Called(id, algorithm)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// Close mocks base method
+func (m *MockPkcs11Client) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
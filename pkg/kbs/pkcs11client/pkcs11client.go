@@ -0,0 +1,407 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+// defaultRsaPublicExponent is the public exponent (65537) used for generated RSA key pairs
+var defaultRsaPublicExponent = []byte{0x01, 0x00, 0x01}
+
+type pkcs11Client struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	// PKCS#11 sessions must not be used concurrently, all token operations are serialized
+	lock sync.Mutex
+}
+
+func NewPkcs11Client() Pkcs11Client {
+	return &pkcs11Client{}
+}
+
+// InitializeClient loads the PKCS#11 module, opens a read-write session on the given slot and logs in with the user PIN
+func (pc *pkcs11Client) InitializeClient(modulePath string, slot uint, pin string) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:InitializeClient() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:InitializeClient() Leaving")
+
+	if modulePath == "" {
+		return errors.New("pkcs11client/pkcs11client:InitializeClient() PKCS#11 module path is not provided")
+	}
+
+	if pin == "" {
+		return errors.New("pkcs11client/pkcs11client:InitializeClient() PKCS#11 user pin is not provided")
+	}
+
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return errors.Errorf("pkcs11client/pkcs11client:InitializeClient() Failed to load PKCS#11 module %s", modulePath)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to initialize PKCS#11 module")
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		pc.release(ctx)
+		return errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to get PKCS#11 slot list")
+	}
+
+	slotFound := false
+	for _, s := range slots {
+		if s == slot {
+			slotFound = true
+			break
+		}
+	}
+	if !slotFound {
+		pc.release(ctx)
+		return errors.Errorf("pkcs11client/pkcs11client:InitializeClient() PKCS#11 slot %d does not exist or has no token", slot)
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		pc.release(ctx)
+		return errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to open PKCS#11 session")
+	}
+
+	if err = ctx.Login(session, pkcs11.CKU_USER, pin); err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		_ = ctx.CloseSession(session)
+		pc.release(ctx)
+		return errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to login to PKCS#11 token")
+	}
+
+	pc.ctx = ctx
+	pc.session = session
+	return nil
+}
+
+// CreateSymmetricKey generates an AES key on the token identified by keyID
+func (pc *pkcs11Client) CreateSymmetricKey(keyID string, length int) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:CreateSymmetricKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:CreateSymmetricKey() Leaving")
+
+	if length != 128 && length != 192 && length != 256 {
+		return errors.Errorf("unsupported AES key length %d", length)
+	}
+
+	template := append(secretKeyTemplate(keyID), pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, length/8))
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)}
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return errors.New("PKCS#11 client is not initialized")
+	}
+
+	if _, err := pc.ctx.GenerateKey(pc.session, mechanism, template); err != nil {
+		return errors.Wrap(err, "failed to generate AES key")
+	}
+	return nil
+}
+
+// CreateAsymmetricKeyPair generates a key pair on the token identified by keyID
+func (pc *pkcs11Client) CreateAsymmetricKeyPair(keyID, algorithm string, length int) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:CreateAsymmetricKeyPair() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:CreateAsymmetricKeyPair() Leaving")
+
+	if algorithm != constants.CRYPTOALG_RSA {
+		return errors.Errorf("unsupported asymmetric algorithm %s", algorithm)
+	}
+
+	publicKeyTemplate := append(publicKeyTemplate(keyID),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, length),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, defaultRsaPublicExponent),
+	)
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)}
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return errors.New("PKCS#11 client is not initialized")
+	}
+
+	if _, _, err := pc.ctx.GenerateKeyPair(pc.session, mechanism, publicKeyTemplate, privateKeyTemplate(keyID)); err != nil {
+		return errors.Wrap(err, "failed to generate RSA key pair")
+	}
+	return nil
+}
+
+// ImportSymmetricKey stores the provided AES key material on the token identified by keyID
+func (pc *pkcs11Client) ImportSymmetricKey(keyID string, key []byte) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:ImportSymmetricKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:ImportSymmetricKey() Leaving")
+
+	template := append(secretKeyTemplate(keyID), pkcs11.NewAttribute(pkcs11.CKA_VALUE, key))
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return errors.New("PKCS#11 client is not initialized")
+	}
+
+	if _, err := pc.ctx.CreateObject(pc.session, template); err != nil {
+		return errors.Wrap(err, "failed to import AES key")
+	}
+	return nil
+}
+
+// ImportRsaPrivateKey stores the provided RSA key pair on the token identified by keyID
+func (pc *pkcs11Client) ImportRsaPrivateKey(keyID string, key *rsa.PrivateKey) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:ImportRsaPrivateKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:ImportRsaPrivateKey() Leaving")
+
+	if key == nil || len(key.Primes) != 2 {
+		return errors.New("only two prime RSA private keys can be imported")
+	}
+	key.Precompute()
+
+	publicExponent := big.NewInt(int64(key.E)).Bytes()
+	publicTemplate := append(publicKeyTemplate(keyID),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, key.N.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, publicExponent),
+	)
+	privateTemplate := append(privateKeyTemplate(keyID),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, key.N.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, publicExponent),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE_EXPONENT, key.D.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIME_1, key.Primes[0].Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIME_2, key.Primes[1].Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_EXPONENT_1, key.Precomputed.Dp.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_EXPONENT_2, key.Precomputed.Dq.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_COEFFICIENT, key.Precomputed.Qinv.Bytes()),
+	)
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return errors.New("PKCS#11 client is not initialized")
+	}
+
+	privateHandle, err := pc.ctx.CreateObject(pc.session, privateTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to import RSA private key")
+	}
+	if _, err = pc.ctx.CreateObject(pc.session, publicTemplate); err != nil {
+		if derr := pc.ctx.DestroyObject(pc.session, privateHandle); derr != nil {
+			defaultLog.WithError(derr).Error("pkcs11client/pkcs11client:ImportRsaPrivateKey() Failed to remove partially imported key")
+		}
+		return errors.Wrap(err, "failed to import RSA public key")
+	}
+	return nil
+}
+
+// DeleteKey destroys every object on the token identified by keyID
+func (pc *pkcs11Client) DeleteKey(keyID string) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:DeleteKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:DeleteKey() Leaving")
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return errors.New("PKCS#11 client is not initialized")
+	}
+
+	handles, err := pc.findObjects([]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID))})
+	if err != nil {
+		return err
+	}
+	if len(handles) == 0 {
+		return errors.Errorf("no PKCS#11 object found with id %s", keyID)
+	}
+
+	for _, handle := range handles {
+		if err := pc.ctx.DestroyObject(pc.session, handle); err != nil {
+			return errors.Wrap(err, "failed to perform delete key operation")
+		}
+	}
+	return nil
+}
+
+// GetKey retrieves the key material identified by keyID. AES keys are returned as raw bytes
+// and RSA keys as a PKCS#1 DER encoded private key, matching the format returned by the KMIP client.
+func (pc *pkcs11Client) GetKey(keyID, algorithm string) ([]byte, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:GetKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:GetKey() Leaving")
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return nil, errors.New("PKCS#11 client is not initialized")
+	}
+
+	switch algorithm {
+	case constants.CRYPTOALG_AES:
+		handle, err := pc.findKey(keyID, pkcs11.CKO_SECRET_KEY)
+		if err != nil {
+			return nil, err
+		}
+		attributes, err := pc.ctx.GetAttributeValue(pc.session, handle, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil)})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read AES key value")
+		}
+		return attributes[0].Value, nil
+	case constants.CRYPTOALG_RSA:
+		handle, err := pc.findKey(keyID, pkcs11.CKO_PRIVATE_KEY)
+		if err != nil {
+			return nil, err
+		}
+		attributes, err := pc.ctx.GetAttributeValue(pc.session, handle, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE_EXPONENT, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIME_1, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIME_2, nil),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read RSA private key")
+		}
+		privateKey := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: new(big.Int).SetBytes(attributes[0].Value),
+				E: int(new(big.Int).SetBytes(attributes[1].Value).Int64()),
+			},
+			D:      new(big.Int).SetBytes(attributes[2].Value),
+			Primes: []*big.Int{new(big.Int).SetBytes(attributes[3].Value), new(big.Int).SetBytes(attributes[4].Value)},
+		}
+		if err = privateKey.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid RSA private key read from token")
+		}
+		privateKey.Precompute()
+		return x509.MarshalPKCS1PrivateKey(privateKey), nil
+	default:
+		return nil, errors.Errorf("unsupported %s algorithm provided", algorithm)
+	}
+}
+
+// Close logs out of the token and unloads the PKCS#11 module
+func (pc *pkcs11Client) Close() error {
+	defaultLog.Trace("pkcs11client/pkcs11client:Close() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:Close() Leaving")
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.ctx == nil {
+		return nil
+	}
+
+	if err := pc.ctx.Logout(pc.session); err != nil {
+		defaultLog.WithError(err).Warn("pkcs11client/pkcs11client:Close() Failed to logout from PKCS#11 token")
+	}
+	if err := pc.ctx.CloseSession(pc.session); err != nil {
+		defaultLog.WithError(err).Warn("pkcs11client/pkcs11client:Close() Failed to close PKCS#11 session")
+	}
+	pc.release(pc.ctx)
+	pc.ctx = nil
+	return nil
+}
+
+func (pc *pkcs11Client) findKey(keyID string, class uint) (pkcs11.ObjectHandle, error) {
+	handles, err := pc.findObjects([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID)),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(handles) == 0 {
+		return 0, errors.Errorf("no PKCS#11 key found with id %s", keyID)
+	}
+	return handles[0], nil
+}
+
+func (pc *pkcs11Client) findObjects(template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	if err := pc.ctx.FindObjectsInit(pc.session, template); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize PKCS#11 object search")
+	}
+	defer func() {
+		if err := pc.ctx.FindObjectsFinal(pc.session); err != nil {
+			defaultLog.WithError(err).Warn("pkcs11client/pkcs11client:findObjects() Failed to finalize PKCS#11 object search")
+		}
+	}()
+
+	var handles []pkcs11.ObjectHandle
+	for {
+		found, _, err := pc.ctx.FindObjects(pc.session, 16)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search PKCS#11 objects")
+		}
+		if len(found) == 0 {
+			break
+		}
+		handles = append(handles, found...)
+	}
+	return handles, nil
+}
+
+func (pc *pkcs11Client) release(ctx *pkcs11.Ctx) {
+	if err := ctx.Finalize(); err != nil {
+		defaultLog.WithError(err).Warn("pkcs11client/pkcs11client:release() Failed to finalize PKCS#11 module")
+	}
+	ctx.Destroy()
+}
+
+// KBS releases key material to attested clients, so objects are created as persistent
+// token objects that remain extractable in the clear.
+func secretKeyTemplate(keyID string) []*pkcs11.Attribute {
+	return []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID)),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyID),
+	}
+}
+
+func publicKeyTemplate(keyID string) []*pkcs11.Attribute {
+	return []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID)),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyID),
+	}
+}
+
+func privateKeyTemplate(keyID string) []*pkcs11.Attribute {
+	return []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID)),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyID),
+	}
+}
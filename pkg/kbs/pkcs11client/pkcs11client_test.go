@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+)
+
+// These tests run against a SoftHSM (or any other PKCS#11) token that has already been initialized, e.g.
+//   softhsm2-util --init-token --free --label kbs --so-pin 1234 --pin 1234
+// and are skipped unless PKCS11_MODULE_PATH points to the module library.
+func newSoftHsmClient(t *testing.T) Pkcs11Client {
+	modulePath := os.Getenv("PKCS11_MODULE_PATH")
+	if modulePath == "" {
+		t.Skip("PKCS11_MODULE_PATH is not set, skipping SoftHSM tests")
+	}
+	if _, err := os.Stat(modulePath); err != nil {
+		t.Skipf("PKCS#11 module %s is not present, skipping SoftHSM tests", modulePath)
+	}
+
+	slot, err := strconv.ParseUint(os.Getenv("PKCS11_SLOT"), 10, 32)
+	if err != nil {
+		t.Fatalf("PKCS11_SLOT must be set to the initialized token slot: %v", err)
+	}
+
+	client := NewPkcs11Client()
+	if err := client.InitializeClient(modulePath, uint(slot), os.Getenv("PKCS11_PIN")); err != nil {
+		t.Fatalf("InitializeClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return client
+}
+
+func TestPkcs11Client_SymmetricKey(t *testing.T) {
+	client := newSoftHsmClient(t)
+	keyID := uuid.New().String()
+
+	if err := client.CreateSymmetricKey(keyID, 256); err != nil {
+		t.Fatalf("CreateSymmetricKey() error = %v", err)
+	}
+
+	key, err := client.GetKey(keyID, constants.CRYPTOALG_AES)
+	if err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if len(key) != 32 {
+		t.Errorf("GetKey() key length = %d, want 32", len(key))
+	}
+
+	if err = client.DeleteKey(keyID); err != nil {
+		t.Fatalf("DeleteKey() error = %v", err)
+	}
+	if _, err = client.GetKey(keyID, constants.CRYPTOALG_AES); err == nil {
+		t.Error("GetKey() succeeded for deleted key")
+	}
+}
+
+func TestPkcs11Client_ImportSymmetricKey(t *testing.T) {
+	client := newSoftHsmClient(t)
+	keyID := uuid.New().String()
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ImportSymmetricKey(keyID, keyBytes); err != nil {
+		t.Fatalf("ImportSymmetricKey() error = %v", err)
+	}
+	defer client.DeleteKey(keyID)
+
+	key, err := client.GetKey(keyID, constants.CRYPTOALG_AES)
+	if err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if !bytes.Equal(key, keyBytes) {
+		t.Error("GetKey() returned key material different from imported key")
+	}
+}
+
+func TestPkcs11Client_AsymmetricKey(t *testing.T) {
+	client := newSoftHsmClient(t)
+	keyID := uuid.New().String()
+
+	if err := client.CreateAsymmetricKeyPair(keyID, constants.CRYPTOALG_RSA, 2048); err != nil {
+		t.Fatalf("CreateAsymmetricKeyPair() error = %v", err)
+	}
+	defer client.DeleteKey(keyID)
+
+	der, err := client.GetKey(keyID, constants.CRYPTOALG_RSA)
+	if err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(der)
+	if err != nil {
+		t.Fatalf("GetKey() returned invalid PKCS#1 key: %v", err)
+	}
+	if privateKey.N.BitLen() != 2048 {
+		t.Errorf("GetKey() key length = %d, want 2048", privateKey.N.BitLen())
+	}
+}
+
+func TestPkcs11Client_ImportRsaPrivateKey(t *testing.T) {
+	client := newSoftHsmClient(t)
+	keyID := uuid.New().String()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.ImportRsaPrivateKey(keyID, rsaKey); err != nil {
+		t.Fatalf("ImportRsaPrivateKey() error = %v", err)
+	}
+	defer client.DeleteKey(keyID)
+
+	der, err := client.GetKey(keyID, constants.CRYPTOALG_RSA)
+	if err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if !bytes.Equal(der, x509.MarshalPKCS1PrivateKey(rsaKey)) {
+		t.Error("GetKey() returned key material different from imported key")
+	}
+}
+
+func TestPkcs11Client_InitializeClient(t *testing.T) {
+	client := NewPkcs11Client()
+	if err := client.InitializeClient("", 0, "1234"); err == nil {
+		t.Error("InitializeClient() succeeded without module path")
+	}
+	if err := client.InitializeClient("/nonexistent/libsofthsm2.so", 0, "1234"); err == nil {
+		t.Error("InitializeClient() succeeded with missing module")
+	}
+	if err := client.CreateSymmetricKey(uuid.New().String(), 256); err == nil {
+		t.Error("CreateSymmetricKey() succeeded on uninitialized client")
+	}
+}
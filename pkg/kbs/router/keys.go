@@ -44,9 +44,17 @@ func setKeyRoutes(router *mux.Router, endpointUrl string, config domain.KeyContr
 			[]string{constants.KeySearch}))).Methods("GET")
 
 	router.Handle(keyIdExpr+"/transfer",
-		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Transfer),
+		ErrorHandler(permissionsHandler(JsonResponseHandler(countKeyTransfers(transferMethodEnvelope, keyController.Transfer)),
 			[]string{constants.KeyTransfer}))).Methods("POST")
 
+	router.Handle(keyIdExpr+"/rotate",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Rotate),
+			[]string{constants.KeyRotate}))).Methods("POST")
+
+	router.Handle(keyIdExpr+"/state",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.UpdateState),
+			[]string{constants.KeyUpdate}))).Methods("PUT")
+
 	return router
 }
 
@@ -62,7 +70,7 @@ func setKeyTransferRoutes(router *mux.Router, endpointUrl string, config domain.
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle(keyIdExpr+"/transfer",
-		ErrorHandler(ResponseHandler(keyController.TransferWithSaml))).Methods("POST").Headers("Accept", consts.HTTPMediaTypeOctetStream)
+		ErrorHandler(ResponseHandler(countKeyTransfers(transferMethodSaml, keyController.TransferWithSaml)))).Methods("POST").Headers("Accept", consts.HTTPMediaTypeOctetStream)
 
 	return router
 }
@@ -79,7 +87,7 @@ func setSKCKeyTransferRoutes(router *mux.Router, kbsConfig *config.Configuration
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle(keyIdExpr+"/dhsm2-transfer",
-		ErrorHandler(permissionsHandlerUsingTLSMAuth(JsonResponseHandler(skcController.TransferApplicationKey),
+		ErrorHandler(permissionsHandlerUsingTLSMAuth(JsonResponseHandler(countKeyTransfers(transferMethodSkc, skcController.TransferApplicationKey)),
 			kbsConfig.AASApiUrl, kbsConfig.KBS))).Methods("GET")
 
 	return router
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Methods used by clients to transfer a key
+const (
+	transferMethodEnvelope = "envelope"
+	transferMethodSaml     = "saml"
+	transferMethodSkc      = "skc"
+)
+
+var keyTransfers = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "kbs",
+	Name:      "key_transfers_total",
+	Help:      "Number of key transfer requests, by transfer method and outcome",
+}, []string{"method", "outcome"})
+
+func init() {
+	metrics.MustRegister(keyTransfers)
+}
+
+//setMetricsRoutes registers the route exposing the prometheus metrics
+func setMetricsRoutes(router *mux.Router) *mux.Router {
+	defaultLog.Trace("router/metrics:setMetricsRoutes() Entering")
+	defer defaultLog.Trace("router/metrics:setMetricsRoutes() Leaving")
+
+	router.Handle("/metrics",
+		ErrorHandler(permissionsHandler(metrics.WriteMetrics,
+			[]string{constants.MetricsRetrieve}))).Methods("GET")
+	return router
+}
+
+//countKeyTransfers wraps a key transfer handler so that the outcome of each transfer is counted
+func countKeyTransfers(method string, h func(http.ResponseWriter, *http.Request) (interface{}, int, error)) func(http.ResponseWriter, *http.Request) (interface{}, int, error) {
+	return func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+		data, status, err := h(w, r)
+		keyTransfers.WithLabelValues(method, keyTransferOutcome(status)).Inc()
+		return data, status, err
+	}
+}
+
+//keyTransferOutcome maps the status code of a key transfer response to the outcome that is reported
+func keyTransferOutcome(status int) string {
+	switch {
+	case status >= http.StatusOK && status < http.StatusMultipleChoices:
+		return "success"
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return "denied"
+	case status == http.StatusNotFound:
+		return "not_found"
+	case status >= http.StatusBadRequest && status < http.StatusInternalServerError:
+		return "invalid_request"
+	default:
+		return "error"
+	}
+}
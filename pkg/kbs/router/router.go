@@ -19,6 +19,7 @@ import (
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/metrics"
 	cmw "github.com/intel-secl/intel-secl/v4/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v4/pkg/lib/common/os"
 	"github.com/pkg/errors"
@@ -41,6 +42,7 @@ func InitRoutes(cfg *config.Configuration, keyConfig domain.KeyControllerConfig,
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
+	router.Use(metrics.NewRequestMetrics(constants.ServiceName))
 
 	// Define sub routes for path /kbs/v1
 	defineSubRoutes(router, "/"+strings.ToLower(constants.ServiceName)+constants.ApiVersion, cfg, keyConfig, keyManager)
@@ -71,6 +73,7 @@ func defineSubRoutes(router *mux.Router, serviceApi string, cfg *config.Configur
 	subRouter = setKeyTransferPolicyRoutes(subRouter)
 	subRouter = setSamlCertRoutes(subRouter)
 	subRouter = setTpmIdentityCertRoutes(subRouter)
+	subRouter = setMetricsRoutes(subRouter)
 }
 
 // Fetch JWT certificate from AAS
@@ -17,6 +17,7 @@ import (
 
 	"github.com/gorilla/handlers"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/directory"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/router"
@@ -57,6 +58,11 @@ func (app *App) startServer() error {
 	// Initialize routes
 	routes := router.InitRoutes(configuration, kcc, km)
 
+	// Deactivate expired keys periodically
+	remoteManager := keymanager.NewRemoteManager(directory.NewKeyStore(constants.KeysDir), km, configuration.EndpointURL)
+	stopExpiryJob := startKeyExpiryJob(remoteManager, configuration.KeyExpiryCheckInterval)
+	defer close(stopExpiryJob)
+
 	defaultLog.Info("kbs/server:startServer() Starting server")
 	tlsConfig := &tls.Config{
 		MinVersion: tls.VersionTLS12,
@@ -108,6 +114,39 @@ func (app *App) startServer() error {
 	return nil
 }
 
+// startKeyExpiryJob deactivates keys past their expiry date at the given interval until the returned channel is closed
+func startKeyExpiryJob(rm *keymanager.RemoteManager, interval time.Duration) chan struct{} {
+	defaultLog.Trace("server:startKeyExpiryJob() Entering")
+	defer defaultLog.Trace("server:startKeyExpiryJob() Leaving")
+
+	done := make(chan struct{})
+	if interval <= 0 {
+		defaultLog.Info("server:startKeyExpiryJob() Key expiry check is disabled")
+		return done
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			deactivated, err := rm.DeactivateExpiredKeys()
+			if err != nil {
+				defaultLog.WithError(err).Error("server:startKeyExpiryJob() Failed to deactivate expired keys")
+			}
+			for _, id := range deactivated {
+				secLog.WithField("Id", id).Infof("server:startKeyExpiryJob() %s: Expired key deactivated", commLogMsg.PrivilegeModified)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return done
+}
+
 func initKeyControllerConfig() (domain.KeyControllerConfig, error) {
 	defaultLog.Trace("server:initKeyControllerConfig() Entering")
 	defer defaultLog.Trace("server:initKeyControllerConfig() Leaving")
@@ -160,6 +160,12 @@ func (app *App) setupTaskRunner() (*setup.Runner, error) {
 		DefaultPort: constants.DefaultKBSListenerPort,
 		AppConfig:   &app.Config,
 	})
+	runner.AddTask("create-key-store", "", &tasks.CreateKeyStore{
+		AppConfig:     &app.Config,
+		KeysDir:       constants.KeysDir,
+		MasterKeyFile: constants.DefaultMasterKeyFile,
+		ConsoleWriter: app.consoleWriter(),
+	})
 	return runner, nil
 }
 
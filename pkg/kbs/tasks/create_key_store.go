@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/keymanager"
+	"github.com/pkg/errors"
+)
+
+// CreateKeyStore initializes the local key store used by the directory key manager. It is a no-op
+// for the other key managers, which vault the key material externally.
+type CreateKeyStore struct {
+	AppConfig     **config.Configuration
+	KeysDir       string
+	MasterKeyFile string
+	ConsoleWriter io.Writer
+	commandName   string
+}
+
+func (t *CreateKeyStore) Run() error {
+	if !t.isDirectoryKeyManager() {
+		fmt.Fprintln(t.ConsoleWriter, "Key manager is not directory, skipping key store creation")
+		return nil
+	}
+	fmt.Fprintln(t.ConsoleWriter, "Creating directory key store")
+
+	if err := os.MkdirAll(t.KeysDir, 0700); err != nil {
+		return errors.Wrap(err, "tasks/create_key_store:Run() Failed to create keys directory")
+	}
+
+	if _, err := os.Stat(t.MasterKeyFile); err == nil {
+		fmt.Fprintln(t.ConsoleWriter, "Master key already exists, keeping existing master key")
+	} else if os.IsNotExist(err) {
+		if err = keymanager.CreateMasterKey(t.MasterKeyFile); err != nil {
+			return errors.Wrap(err, "tasks/create_key_store:Run() Failed to create master key")
+		}
+	} else {
+		return errors.Wrap(err, "tasks/create_key_store:Run() Failed to access master key file")
+	}
+
+	fmt.Fprintln(t.ConsoleWriter, "Directory key store created")
+	return nil
+}
+
+func (t *CreateKeyStore) Validate() error {
+	if !t.isDirectoryKeyManager() {
+		return nil
+	}
+
+	if _, err := os.Stat(t.KeysDir); err != nil {
+		return errors.Wrap(err, "tasks/create_key_store:Validate() keys directory does not exist")
+	}
+	if err := keymanager.ValidateMasterKey(t.MasterKeyFile); err != nil {
+		return errors.Wrap(err, "tasks/create_key_store:Validate() master key is not valid")
+	}
+	return nil
+}
+
+func (t *CreateKeyStore) PrintHelp(w io.Writer) {
+	fmt.Fprintln(w, "Creates the master key used by the directory key manager when KEY_MANAGER is set to directory")
+}
+
+func (t *CreateKeyStore) SetName(n, e string) {
+	t.commandName = n
+}
+
+func (t *CreateKeyStore) isDirectoryKeyManager() bool {
+	return t.AppConfig != nil && *t.AppConfig != nil &&
+		strings.ToLower((*t.AppConfig).KeyManager) == constants.DirectoryKeyManager
+}
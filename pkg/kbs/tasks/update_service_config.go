@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/intel-secl/intel-secl/v4/pkg/kbs/config"
+	"github.com/intel-secl/intel-secl/v4/pkg/kbs/constants"
 	commConfig "github.com/intel-secl/intel-secl/v4/pkg/lib/common/config"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/setup"
 	"github.com/pkg/errors"
@@ -30,7 +31,7 @@ type UpdateServiceConfig struct {
 const envHelpPrompt = "Following environment variables are required for update-service-config setup:"
 
 var allowedSKCChallengeTypes = map[string]bool{"sgx": true}
-var allowedKeyManagers = map[string]bool{"kmip": true}
+var allowedKeyManagers = map[string]bool{"kmip": true, "pkcs11": true, "directory": true}
 
 var envHelp = map[string]string{
 	"SERVICE_USERNAME":           "The service username as configured in AAS",
@@ -47,6 +48,9 @@ var envHelp = map[string]string{
 	"KMIP_CLIENT_CERT_PATH":      "KMIP Client certificate path",
 	"KMIP_CLIENT_KEY_PATH":       "KMIP Client key path",
 	"KMIP_ROOT_CERT_PATH":        "KMIP Root Certificate path",
+	"PKCS11_MODULE_PATH":         "PKCS11 module library path",
+	"PKCS11_SLOT":                "PKCS11 token slot",
+	"PKCS11_PIN":                 "PKCS11 token user PIN",
 	"SKC_CHALLENGE_TYPE":         "SKC challenge type",
 	"SQVS_URL":                   "SQVS URL",
 	"SESSION_EXPIRY_TIME":        "Session Expiry Time",
@@ -56,6 +60,7 @@ var envHelp = map[string]string{
 	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
 	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes ",
+	"KEY_EXPIRY_CHECK_INTERVAL":  "Interval at which keys past their expiry date are deactivated, e.g. 1h",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -92,12 +97,18 @@ func (uc UpdateServiceConfig) Run() error {
 		ClientCertificateFilePath: viper.GetString("kmip-client-cert-path"),
 		RootCertificateFilePath:   viper.GetString("kmip-root-cert-path"),
 	}
+	(*uc.AppConfig).Pkcs11 = config.Pkcs11Config{
+		ModulePath: viper.GetString("pkcs11-module-path"),
+		Slot:       viper.GetUint("pkcs11-slot"),
+		Pin:        viper.GetString("pkcs11-pin"),
+	}
 	(*uc.AppConfig).Skc = config.SKCConfig{
 		StmLabel:          viper.GetString("skc-challenge-type"),
 		SQVSUrl:           viper.GetString("sqvs-url"),
 		SessionExpiryTime: viper.GetInt("session-expiry-time"),
 	}
 	(*uc.AppConfig).KeyManager = viper.GetString("key-manager")
+	(*uc.AppConfig).KeyExpiryCheckInterval = viper.GetDuration("key-expiry-check-interval")
 	return nil
 }
 
@@ -110,7 +121,12 @@ func (uc UpdateServiceConfig) Validate() error {
 		return errors.New("Configured port is not valid")
 	}
 	if _, validInput := allowedKeyManagers[strings.ToLower((*uc.AppConfig).KeyManager)]; !validInput {
-		return errors.New("Invalid value provided for KEY_MANAGER. Value should be kmip")
+		return errors.New("Invalid value provided for KEY_MANAGER. Value should be kmip, pkcs11 or directory")
+	}
+	if strings.ToLower((*uc.AppConfig).KeyManager) == constants.Pkcs11KeyManager {
+		if (*uc.AppConfig).Pkcs11.ModulePath == "" || (*uc.AppConfig).Pkcs11.Pin == "" {
+			return errors.New("PKCS11_MODULE_PATH and PKCS11_PIN must be provided when KEY_MANAGER is pkcs11")
+		}
 	}
 	if (*uc.AppConfig).Skc.StmLabel != "" {
 		if _, validInput := allowedSKCChallengeTypes[strings.ToLower((*uc.AppConfig).Skc.StmLabel)]; !validInput {
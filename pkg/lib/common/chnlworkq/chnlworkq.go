@@ -29,6 +29,13 @@ var defaultLog = commLog.GetDefaultLogger()
 // procReq is a callback function that can be used to process a request and return an object that is to be stored within
 // the queue data structure.
 func New(reqBufSize, workBufSize int, procReq procReq, procWork procWork, quit chan struct{}, wg *sync.WaitGroup) (chan interface{}, chan interface{}, error) {
+	return NewObserved(reqBufSize, workBufSize, procReq, procWork, nil, quit, wg)
+}
+
+// NewObserved makes a work queue just like New. In addition, depth is called with the number of items held by the queue
+// whenever an item is added to or pulled out of the queue. depth is invoked from the goroutine that manages the queue
+// and must not block.
+func NewObserved(reqBufSize, workBufSize int, procReq procReq, procWork procWork, depth func(int), quit chan struct{}, wg *sync.WaitGroup) (chan interface{}, chan interface{}, error) {
 
 	req, work := make(chan interface{}, reqBufSize), make(chan interface{}, workBufSize)
 	if wg == nil {
@@ -46,7 +53,16 @@ func New(reqBufSize, workBufSize int, procReq procReq, procWork procWork, quit c
 
 		l := list.New()
 		var w interface{}
-		var getNext bool
+		var getNext, pending bool
+		observe := func() {
+			if depth != nil {
+				if pending {
+					depth(l.Len() + 1)
+				} else {
+					depth(l.Len())
+				}
+			}
+		}
 		for {
 			if l.Len() == 0 {
 				select {
@@ -59,12 +75,14 @@ func New(reqBufSize, workBufSize int, procReq procReq, procWork procWork, quit c
 						l.PushBack(r)
 					}
 					getNext = true
+					observe()
 				}
 
 			}
 			if getNext {
 				w = l.Remove(l.Front())
 				getNext = false
+				pending = true
 			}
 
 			select {
@@ -76,7 +94,10 @@ func New(reqBufSize, workBufSize int, procReq procReq, procWork procWork, quit c
 				} else {
 					l.PushBack(r)
 				}
+				observe()
 			case work <- w:
+				pending = false
+				observe()
 				if procWork != nil {
 					procWork(w)
 				}
@@ -0,0 +1,15 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package config
+
+// TracingConfig configures the export of the OpenTelemetry spans recorded by a service. Spans are not exported
+// unless Exporter is set to "otlp"
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" mapstructure:"exporter"`
+	Endpoint    string  `yaml:"endpoint" mapstructure:"endpoint"`
+	Insecure    bool    `yaml:"insecure" mapstructure:"insecure"`
+	SampleRatio float64 `yaml:"sample-ratio" mapstructure:"sample-ratio"`
+}
@@ -12,4 +12,5 @@ const (
 	HTTPMediaTypeSaml        = "application/samlassertion+xml"
 	HTTPMediaTypePemFile     = "application/x-pem-file"
 	HTTPMediaTypeOctetStream = "application/octet-stream"
+	HTTPMediaTypeCsv         = "text/csv"
 )
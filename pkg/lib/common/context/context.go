@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	types "github.com/intel-secl/intel-secl/v4/pkg/model/aas"
 )
@@ -16,6 +17,7 @@ const (
 	UserRoles       = "userroles"
 	UserPermissions = "userpermissions"
 	TokenSubject    = "tokensubject"
+	TokenExpiresAt  = "tokenexpiresat"
 )
 
 func SetUserRoles(r *http.Request, val []types.RoleInfo) *http.Request {
@@ -62,3 +64,18 @@ func GetTokenSubject(r *http.Request) (string, error) {
 	}
 	return "", fmt.Errorf("could not retrieve token subject from context")
 }
+
+func SetTokenExpiresAt(r *http.Request, val time.Time) *http.Request {
+
+	ctx := context.WithValue(r.Context(), TokenExpiresAt, val)
+	return r.WithContext(ctx)
+}
+
+func GetTokenExpiresAt(r *http.Request) (time.Time, error) {
+	if rv := r.Context().Value(TokenExpiresAt); rv != nil {
+		if ur, ok := rv.(time.Time); ok {
+			return ur, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("could not retrieve token expiry from context")
+}
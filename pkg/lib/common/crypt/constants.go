@@ -9,4 +9,9 @@ const (
 	EncryptionHeaderMagicText = "ISecL-VMC"
 	EncryptionHeaderVersion   = "V1"
 	GCMEncryptionAlgorithm    = "GCM-256"
+
+	// EncryptionHeaderVersionChunked is the version of files encrypted in independently authenticated chunks
+	EncryptionHeaderVersionChunked = "V2"
+	DefaultEncryptionChunkSize     = 1 << 20
+	MaxEncryptionChunkSize         = 64 << 20
 )
@@ -19,6 +19,13 @@ type EncryptionHeader struct {
 	EncryptionAlgorithm  [12]byte
 }
 
+// ChunkedEncryptionHeader is appended to files encrypted with EncryptStream. The data following the header is
+// split in chunks of ChunkSizeInLittleEndian bytes that are sealed with their own nonce and authentication tag
+type ChunkedEncryptionHeader struct {
+	EncryptionHeader
+	ChunkSizeInLittleEndian uint32
+}
+
 // EncryptionHeaderExists method is used to check if the file is encryped and returns a boolean value.
 // TODO : move it a different package where all the ISecL specific functions are added
 func EncryptionHeaderExists(encFilePath string) (bool, error) {
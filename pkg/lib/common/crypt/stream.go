@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// chunk nonces are built from the first 7 bytes of the header IV, a 4 byte big endian chunk counter
+// and a final byte that is set only for the last chunk, so that chunks can not be reordered or truncated
+const chunkNoncePrefixSize = 7
+
+// EncryptStream encrypts the data read from plaintext with AES-GCM in chunks of chunkSize bytes and writes
+// the encryption header followed by the encrypted chunks to ciphertext
+func EncryptStream(key []byte, plaintext io.Reader, ciphertext io.Writer, chunkSize int) error {
+	if chunkSize <= 0 || chunkSize > MaxEncryptionChunkSize {
+		return errors.Errorf("Chunk size must be between 1 and %d bytes", MaxEncryptionChunkSize)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	var header ChunkedEncryptionHeader
+	if _, err = io.ReadFull(rand.Reader, header.IV[:]); err != nil {
+		return errors.Wrap(err, "Error creating random IV value")
+	}
+	copy(header.MagicText[:], EncryptionHeaderMagicText)
+	copy(header.EncryptionAlgorithm[:], GCMEncryptionAlgorithm)
+	copy(header.Version[:], EncryptionHeaderVersionChunked)
+	header.OffsetInLittleEndian = uint32(binary.Size(header))
+	header.ChunkSizeInLittleEndian = uint32(chunkSize)
+
+	headerBuffer := &bytes.Buffer{}
+	if err = binary.Write(headerBuffer, binary.LittleEndian, header); err != nil {
+		return errors.Wrap(err, "Error while writing encryption header struct values in to buffer")
+	}
+	headerBytes := headerBuffer.Bytes()
+	if _, err = ciphertext.Write(headerBytes); err != nil {
+		return errors.Wrap(err, "Error writing encryption header")
+	}
+
+	reader := bufio.NewReader(plaintext)
+	chunk := make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+gcm.Overhead())
+	for counter := uint64(0); ; counter++ {
+		if counter > math.MaxUint32 {
+			return errors.New("Data exceeds the maximum number of chunks")
+		}
+
+		n, err := io.ReadFull(reader, chunk)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return errors.Wrap(err, "Error reading data to encrypt")
+		}
+		last := err != nil
+		if !last {
+			if _, err = reader.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return errors.Wrap(err, "Error reading data to encrypt")
+			}
+		}
+
+		sealed = gcm.Seal(sealed[:0], chunkNonce(header.IV[:], uint32(counter), last), chunk[:n], headerBytes)
+		if _, err = ciphertext.Write(sealed); err != nil {
+			return errors.Wrap(err, "Error writing encrypted data")
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// DecryptStream decrypts data written by EncryptStream, as well as single shot encrypted files with
+// a version V1 encryption header, and writes the decrypted data to plaintext
+func DecryptStream(key []byte, ciphertext io.Reader, plaintext io.Writer) error {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(ciphertext)
+	var header EncryptionHeader
+	if err = binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return errors.Wrap(err, "Error reading encryption header")
+	}
+	if !strings.Contains(string(header.MagicText[:]), EncryptionHeaderMagicText) {
+		return errors.New("Data does not contain an encryption header")
+	}
+
+	switch strings.TrimRight(string(header.Version[:]), "\x00") {
+	case EncryptionHeaderVersion:
+		return decryptSingleShot(gcm, header, reader, plaintext)
+	case EncryptionHeaderVersionChunked:
+		return decryptChunked(gcm, header, reader, plaintext)
+	default:
+		return errors.Errorf("Unsupported encryption header version %s", string(header.Version[:]))
+	}
+}
+
+// decryptSingleShot decrypts version V1 data, which is sealed as a single message and has to be held in memory
+func decryptSingleShot(gcm cipher.AEAD, header EncryptionHeader, reader io.Reader, plaintext io.Writer) error {
+	if _, err := io.CopyN(ioutil.Discard, reader, int64(header.OffsetInLittleEndian)-int64(binary.Size(header))); err != nil {
+		return errors.Wrap(err, "Error reading encryption header")
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrap(err, "Error reading encrypted data")
+	}
+
+	decrypted, err := gcm.Open(nil, header.IV[:], data, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error decrypting data")
+	}
+
+	if _, err = plaintext.Write(decrypted); err != nil {
+		return errors.Wrap(err, "Error writing decrypted data")
+	}
+	return nil
+}
+
+func decryptChunked(gcm cipher.AEAD, header EncryptionHeader, reader *bufio.Reader, plaintext io.Writer) error {
+	chunkedHeader := ChunkedEncryptionHeader{EncryptionHeader: header}
+	if err := binary.Read(reader, binary.LittleEndian, &chunkedHeader.ChunkSizeInLittleEndian); err != nil {
+		return errors.Wrap(err, "Error reading encryption header")
+	}
+	chunkSize := int(chunkedHeader.ChunkSizeInLittleEndian)
+	if chunkSize <= 0 || chunkSize > MaxEncryptionChunkSize {
+		return errors.Errorf("Invalid chunk size %d in encryption header", chunkSize)
+	}
+	if int(header.OffsetInLittleEndian) != binary.Size(chunkedHeader) {
+		return errors.New("Invalid data offset in encryption header")
+	}
+
+	headerBuffer := &bytes.Buffer{}
+	if err := binary.Write(headerBuffer, binary.LittleEndian, chunkedHeader); err != nil {
+		return errors.Wrap(err, "Error while writing encryption header struct values in to buffer")
+	}
+	headerBytes := headerBuffer.Bytes()
+
+	chunk := make([]byte, chunkSize+gcm.Overhead())
+	opened := make([]byte, 0, chunkSize)
+	for counter := uint64(0); ; counter++ {
+		if counter > math.MaxUint32 {
+			return errors.New("Data exceeds the maximum number of chunks")
+		}
+
+		n, err := io.ReadFull(reader, chunk)
+		if err == io.EOF {
+			return errors.New("Encrypted data is truncated")
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return errors.Wrap(err, "Error reading encrypted data")
+		}
+		last := err != nil
+		if !last {
+			if _, err = reader.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return errors.Wrap(err, "Error reading encrypted data")
+			}
+		}
+
+		opened, err = gcm.Open(opened[:0], chunkNonce(header.IV[:], uint32(counter), last), chunk[:n], headerBytes)
+		if err != nil {
+			return errors.Wrapf(err, "Error decrypting chunk %d", counter)
+		}
+		if _, err = plaintext.Write(opened); err != nil {
+			return errors.Wrap(err, "Error writing decrypted data")
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+func chunkNonce(iv []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, chunkNoncePrefixSize+5)
+	copy(nonce, iv[:chunkNoncePrefixSize])
+	binary.BigEndian.PutUint32(nonce[chunkNoncePrefixSize:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error initializing cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating a cipher block")
+	}
+	return gcm, nil
+}
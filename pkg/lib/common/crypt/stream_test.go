@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+	return key
+}
+
+func TestEncryptDecryptStream(t *testing.T) {
+	key := newTestKey(t)
+	chunkSize := 64
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17, 4 * chunkSize} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		assert.NoError(t, err)
+
+		encrypted := &bytes.Buffer{}
+		assert.NoError(t, EncryptStream(key, bytes.NewReader(data), encrypted, chunkSize))
+
+		decrypted := &bytes.Buffer{}
+		assert.NoError(t, DecryptStream(key, bytes.NewReader(encrypted.Bytes()), decrypted), "size %d", size)
+		assert.True(t, bytes.Equal(data, decrypted.Bytes()), "size %d", size)
+	}
+}
+
+func TestDecryptStreamTampered(t *testing.T) {
+	key := newTestKey(t)
+	chunkSize := 64
+	data := bytes.Repeat([]byte{0x42}, 3*chunkSize)
+
+	encrypted := &bytes.Buffer{}
+	assert.NoError(t, EncryptStream(key, bytes.NewReader(data), encrypted, chunkSize))
+	encryptedBytes := encrypted.Bytes()
+	headerSize := binary.Size(ChunkedEncryptionHeader{})
+	sealedChunkSize := chunkSize + 16
+
+	// modified data
+	modified := append([]byte{}, encryptedBytes...)
+	modified[headerSize+1] ^= 0x01
+	assert.Error(t, DecryptStream(key, bytes.NewReader(modified), &bytes.Buffer{}))
+
+	// modified header
+	modified = append([]byte{}, encryptedBytes...)
+	modified[len(EncryptionHeaderMagicText)+8] ^= 0x01
+	assert.Error(t, DecryptStream(key, bytes.NewReader(modified), &bytes.Buffer{}))
+
+	// truncated at a chunk boundary
+	truncated := encryptedBytes[:headerSize+2*sealedChunkSize]
+	assert.Error(t, DecryptStream(key, bytes.NewReader(truncated), &bytes.Buffer{}))
+
+	// reordered chunks
+	reordered := append([]byte{}, encryptedBytes[:headerSize]...)
+	reordered = append(reordered, encryptedBytes[headerSize+sealedChunkSize:headerSize+2*sealedChunkSize]...)
+	reordered = append(reordered, encryptedBytes[headerSize:headerSize+sealedChunkSize]...)
+	reordered = append(reordered, encryptedBytes[headerSize+2*sealedChunkSize:]...)
+	assert.Error(t, DecryptStream(key, bytes.NewReader(reordered), &bytes.Buffer{}))
+
+	// wrong key
+	assert.Error(t, DecryptStream(newTestKey(t), bytes.NewReader(encryptedBytes), &bytes.Buffer{}))
+}
+
+func TestDecryptStreamSingleShot(t *testing.T) {
+	key := newTestKey(t)
+	data := []byte("image encrypted with a version V1 encryption header")
+
+	var header EncryptionHeader
+	copy(header.MagicText[:], EncryptionHeaderMagicText)
+	copy(header.EncryptionAlgorithm[:], GCMEncryptionAlgorithm)
+	copy(header.Version[:], EncryptionHeaderVersion)
+	_, err := rand.Read(header.IV[:])
+	assert.NoError(t, err)
+	header.OffsetInLittleEndian = uint32(binary.Size(header))
+
+	encrypted := &bytes.Buffer{}
+	assert.NoError(t, binary.Write(encrypted, binary.LittleEndian, header))
+	gcm, err := newGCM(key)
+	assert.NoError(t, err)
+	encryptedBytes := gcm.Seal(encrypted.Bytes(), header.IV[:], data, nil)
+
+	decrypted := &bytes.Buffer{}
+	assert.NoError(t, DecryptStream(key, bytes.NewReader(encryptedBytes), decrypted))
+	assert.Equal(t, data, decrypted.Bytes())
+}
+
+func TestEncryptStreamInvalidChunkSize(t *testing.T) {
+	key := newTestKey(t)
+	assert.Error(t, EncryptStream(key, bytes.NewReader(nil), &bytes.Buffer{}, 0))
+	assert.Error(t, EncryptStream(key, bytes.NewReader(nil), &bytes.Buffer{}, MaxEncryptionChunkSize+1))
+}
@@ -112,6 +112,14 @@ func (t *Token) GetSubject() string {
 	return t.standardClaims.Subject
 }
 
+// GetExpiresAt returns the expiry time of the token, the zero time when the token does not expire
+func (t *Token) GetExpiresAt() time.Time {
+	if t.standardClaims == nil || t.standardClaims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t.standardClaims.ExpiresAt, 0)
+}
+
 type verifierKey struct {
 	pubKey  crypto.PublicKey
 	expTime time.Time
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// metrics package holds the prometheus registry shared by the services along with the http middleware that records
+// the request latency and status codes of every route
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace is the prefix of all the metrics exposed by the services
+const Namespace = "isecl"
+
+var defaultLog = commLog.GetDefaultLogger()
+
+var registry = prometheus.NewRegistry()
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the http requests served, by route and method",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "route", "method"})
+
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of http requests served, by route, method and status code",
+	}, []string{"service", "route", "method", "code"})
+)
+
+func init() {
+	registry.MustRegister(prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		requestDuration, requestsTotal)
+}
+
+// MustRegister registers the service specific collectors with the shared registry. It panics if a collector cannot
+// be registered, hence it is expected to be called while initializing the package declaring the collector
+func MustRegister(collectors ...prometheus.Collector) {
+	registry.MustRegister(collectors...)
+}
+
+// Register registers a collector that depends on runtime state (such as a database connection) with the shared
+// registry. A collector that is already registered is not considered an error
+func Register(collector prometheus.Collector) error {
+	if err := registry.Register(collector); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns the http handler that exposes the metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorLog: defaultLog,
+	})
+}
+
+// WriteMetrics writes the metrics to the response. It can be used as an endpoint handler by the service routers so
+// that scraping goes through the same authorization as the rest of the API
+func WriteMetrics(w http.ResponseWriter, r *http.Request) error {
+	Handler().ServeHTTP(w, r)
+	return nil
+}
+
+// NewRequestMetrics returns a middleware that records the latency and status code of the requests served by the
+// routes of a service. The route is recorded using its path template so that the cardinality is bounded
+func NewRequestMetrics(service string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			route := "unknown"
+			if current := mux.CurrentRoute(r); current != nil {
+				if tpl, err := current.GetPathTemplate(); err == nil {
+					route = tpl
+				}
+			}
+			requestDuration.WithLabelValues(service, route, r.Method).Observe(time.Since(start).Seconds())
+			requestsTotal.WithLabelValues(service, route, r.Method, strconv.Itoa(sw.status)).Inc()
+		})
+	}
+}
+
+// statusWriter captures the status code written by the handler
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wroteHeader {
+		sw.status = code
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wroteHeader = true
+	return sw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetricsRecordsRouteAndStatus(t *testing.T) {
+	a := assert.New(t)
+
+	router := mux.NewRouter()
+	router.Use(NewRequestMetrics("TEST"))
+	router.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+	router.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}).Methods("GET")
+
+	for _, path := range []string{"/items/1", "/items/2", "/items"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	a.Equal(float64(2), testutil.ToFloat64(requestsTotal.WithLabelValues("TEST", "/items/{id}", "GET", "404")))
+	a.Equal(float64(1), testutil.ToFloat64(requestsTotal.WithLabelValues("TEST", "/items", "GET", "200")))
+}
+
+func TestWriteMetricsExposesRegisteredCollectors(t *testing.T) {
+	a := assert.New(t)
+
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "test_events_total",
+		Help:      "Events counted by the test",
+	})
+	a.NoError(Register(counter))
+	a.NoError(Register(counter))
+	counter.Inc()
+
+	w := httptest.NewRecorder()
+	a.NoError(WriteMetrics(w, httptest.NewRequest("GET", "/metrics", nil)))
+	a.Equal(http.StatusOK, w.Code)
+
+	body, err := ioutil.ReadAll(w.Body)
+	a.NoError(err)
+	a.True(strings.Contains(string(body), "isecl_test_events_total 1"))
+	a.True(strings.Contains(string(body), "go_goroutines"))
+}
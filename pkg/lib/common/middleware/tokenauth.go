@@ -116,6 +116,7 @@ func NewTokenAuth(signingCertsDir, trustedCAsDir string, fnGetJwtCerts RetrieveJ
 			r = context.SetUserRoles(r, claims.Roles)
 			r = context.SetUserPermissions(r, claims.Permissions)
 			r = context.SetTokenSubject(r, token.GetSubject())
+			r = context.SetTokenExpiresAt(r, token.GetExpiresAt())
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// tracing package sets up OpenTelemetry tracing for the services. Spans are propagated through the context and across
+// services using the W3C trace context headers. Unless an exporter is configured, the spans are not recorded.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/config"
+	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported span exporters
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+const instrumentationName = "github.com/intel-secl/intel-secl/v4"
+
+var defaultLog = commLog.GetDefaultLogger()
+
+func init() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Init installs the tracer provider of the service according to the tracing configuration. The returned function
+// flushes the pending spans and must be called when the service is stopped. When no exporter is configured, the
+// default no-op tracer provider is kept so that tracing does not cost anything.
+func Init(serviceName string, cfg config.TracingConfig) (func(context.Context) error, error) {
+	defaultLog.Trace("tracing/tracing:Init() Entering")
+	defer defaultLog.Trace("tracing/tracing:Init() Leaving")
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Exporter)) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+	default:
+		return nil, errors.Errorf("tracing/tracing:Init() Unsupported span exporter %s", cfg.Exporter)
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "tracing/tracing:Init() Could not create OTLP span exporter")
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	defaultLog.Infof("tracing/tracing:Init() Exporting spans to %s", cfg.Endpoint)
+	return provider.Shutdown, nil
+}
+
+// NewInMemoryExporter installs a tracer provider that synchronously records every span in the returned exporter. It
+// is meant to be used by tests asserting on the recorded spans.
+func NewInMemoryExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	return exporter
+}
+
+// StartSpan starts a span that is a child of the span held by ctx, if any
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err, if any, on the span before ending it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/config"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInitWithoutExporter(t *testing.T) {
+	a := assert.New(t)
+
+	shutdown, err := Init("TEST", config.TracingConfig{})
+	a.NoError(err)
+	a.NoError(shutdown(context.Background()))
+
+	shutdown, err = Init("TEST", config.TracingConfig{Exporter: ExporterNone})
+	a.NoError(err)
+	a.NoError(shutdown(context.Background()))
+
+	_, err = Init("TEST", config.TracingConfig{Exporter: "zipkin"})
+	a.Error(err)
+}
+
+func TestEndSpanRecordsError(t *testing.T) {
+	a := assert.New(t)
+	exporter := NewInMemoryExporter()
+
+	_, span := StartSpan(context.Background(), "failing")
+	EndSpan(span, errors.New("failed"))
+	_, span = StartSpan(context.Background(), "succeeding")
+	EndSpan(span, nil)
+
+	spans := exporter.GetSpans()
+	a.Len(spans, 2)
+	a.Equal(codes.Error, spans[0].Status.Code)
+	a.Len(spans[0].Events, 1)
+	a.Equal(codes.Unset, spans[1].Status.Code)
+}
+
+func TestTraceIsPropagatedAcrossServices(t *testing.T) {
+	a := assert.New(t)
+	exporter := NewInMemoryExporter()
+
+	// downstream service continuing the trace of the client
+	var traceParent string
+	downstream := mux.NewRouter()
+	downstream.Use(NewMiddleware("DOWNSTREAM"))
+	downstream.HandleFunc("/hosts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusInternalServerError)
+	}).Methods("GET")
+	server := httptest.NewServer(downstream)
+	defer server.Close()
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/hosts/1", nil)
+	a.NoError(err)
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Do(req)
+	a.NoError(err)
+	a.NoError(resp.Body.Close())
+	parent.End()
+
+	a.NotEmpty(traceParent)
+	spans := exporter.GetSpans()
+	a.Len(spans, 3)
+	serverSpan, clientSpan, parentSpan := spans[0], spans[1], spans[2]
+
+	a.Equal("GET /hosts/{id}", serverSpan.Name)
+	a.Equal(trace.SpanKindServer, serverSpan.SpanKind)
+	a.Equal(codes.Error, serverSpan.Status.Code)
+	a.Equal("HTTP GET", clientSpan.Name)
+	a.Equal(trace.SpanKindClient, clientSpan.SpanKind)
+
+	traceID := parentSpan.SpanContext.TraceID()
+	a.Equal(traceID, clientSpan.SpanContext.TraceID())
+	a.Equal(traceID, serverSpan.SpanContext.TraceID())
+	a.Equal(parentSpan.SpanContext.SpanID(), clientSpan.Parent.SpanID())
+	a.Equal(clientSpan.SpanContext.SpanID(), serverSpan.Parent.SpanID())
+	a.True(serverSpan.Parent.IsRemote())
+}
+
+func TestBaseTransport(t *testing.T) {
+	a := assert.New(t)
+
+	base := &http.Transport{}
+	a.Equal(base, BaseTransport(NewTransport(base)))
+	a.Equal(base, BaseTransport(base))
+}
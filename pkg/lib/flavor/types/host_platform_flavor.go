@@ -71,6 +71,12 @@ func (pf HostPlatformFlavor) GetFlavorPartRaw(name hvs.FlavorPartName) ([]hvs.Fl
 		} else {
 			return nil, cf.UNKNOWN_FLAVOR_PART()
 		}
+	case hvs.FlavorPartIma:
+		if pf.HostManifest.HostInfo.OSType == taModel.OsTypeLinux {
+			return pf.getImaFlavor()
+		} else {
+			return nil, cf.UNKNOWN_FLAVOR_PART()
+		}
 	}
 	return nil, cf.UNKNOWN_FLAVOR_PART()
 }
@@ -84,7 +90,7 @@ func (pf HostPlatformFlavor) GetFlavorPartNames() ([]hvs.FlavorPartName, error)
 		return []hvs.FlavorPartName{
 			hvs.FlavorPartPlatform, hvs.FlavorPartOs,
 			hvs.FlavorPartHostUnique, hvs.FlavorPartSoftware,
-			hvs.FlavorPartAssetTag}, nil
+			hvs.FlavorPartAssetTag, hvs.FlavorPartIma}, nil
 	} else {
 		return []hvs.FlavorPartName{
 			hvs.FlavorPartPlatform, hvs.FlavorPartOs,
@@ -142,6 +148,7 @@ func (pf HostPlatformFlavor) getPlatformFlavor() ([]hvs.Flavor, error) {
 
 	// Assemble the Platform Flavor
 	platformFlavor := hvs.NewFlavor(newMeta, newBios, newHW, allPcrDetails, nil, nil)
+	platformFlavor.CustomRules = pfutil.GetCustomRules(hvs.FlavorPartPlatform, pf.FlavorTemplates)
 
 	log.Debugf("flavor/types/host_platform_flavor:getPlatformFlavor()  New PlatformFlavor: %v", platformFlavor)
 
@@ -181,6 +188,7 @@ func (pf HostPlatformFlavor) getOsFlavor() ([]hvs.Flavor, error) {
 
 	// Assemble the OS Flavor
 	osFlavor := hvs.NewFlavor(newMeta, newBios, nil, allPcrDetails, nil, nil)
+	osFlavor.CustomRules = pfutil.GetCustomRules(hvs.FlavorPartOs, pf.FlavorTemplates)
 
 	log.Debugf("flavor/types/host_platform_flavor:getOSFlavor()  New OS Flavor: %v", osFlavor)
 
@@ -223,6 +231,7 @@ func (pf HostPlatformFlavor) getHostUniqueFlavor() ([]hvs.Flavor, error) {
 
 	// Assemble the Host Unique Flavor
 	hostUniqueFlavor := hvs.NewFlavor(newMeta, newBios, nil, allPcrDetails, nil, nil)
+	hostUniqueFlavor.CustomRules = pfutil.GetCustomRules(hvs.FlavorPartHostUnique, pf.FlavorTemplates)
 
 	log.Debugf("flavor/types/host_platform_flavor:getHostUniqueFlavor() New Host unique flavor: %v", hostUniqueFlavor)
 
@@ -343,6 +352,32 @@ func (pf HostPlatformFlavor) getDefaultSoftwareFlavor() ([]hvs.Flavor, error) {
 	return softwareFlavors, nil
 }
 
+// getImaFlavor creates an IMA flavor having all the file measurements of the host's IMA runtime measurement list
+// in its allowlist. No flavor is created if the host does not report an IMA log.
+func (pf HostPlatformFlavor) getImaFlavor() ([]hvs.Flavor, error) {
+	log.Trace("flavor/types/host_platform_flavor:getImaFlavor() Entering")
+	defer log.Trace("flavor/types/host_platform_flavor:getImaFlavor() Leaving")
+
+	var errorMessage = "Error during creation of IMA flavor"
+	if pf.HostManifest.ImaLog == nil || len(pf.HostManifest.ImaLog.Measurements) == 0 {
+		log.Debug("flavor/types/host_platform_flavor:getImaFlavor() Host manifest does not include an IMA log")
+		return nil, nil
+	}
+
+	newMeta, err := pfutil.GetMetaSectionDetails(pf.HostInfo, nil, "", hvs.FlavorPartIma, pf.getVendorName())
+	if err != nil {
+		return nil, errors.Wrapf(err, "flavor/types/host_platform_flavor:getImaFlavor() %s Failure in Meta section details", errorMessage)
+	}
+	log.Debugf("flavor/types/host_platform_flavor:getImaFlavor() New Meta Section: %v", *newMeta)
+
+	newIma := pfutil.GetImaSectionDetails(pf.HostManifest.ImaLog)
+
+	flavor := hvs.NewFlavor(newMeta, nil, nil, nil, nil, nil)
+	flavor.Ima = newIma
+	log.Debugf("flavor/types/host_platform_flavor:getImaFlavor() New IMA Flavor with %d allowed measurements", len(newIma.Allowlist))
+	return []hvs.Flavor{*flavor}, nil
+}
+
 // getDefaultMeasurement returns a default set of measurements for the Platform Flavor
 func (pf HostPlatformFlavor) getDefaultMeasurement() ([]string, error) {
 	log.Trace("flavor/types/host_platform_flavor:getDefaultMeasurement() Entering")
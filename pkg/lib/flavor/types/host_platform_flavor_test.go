@@ -189,3 +189,43 @@ func TestLinuxPlatformFlavor_GetPcrDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestLinuxPlatformFlavor_GetImaFlavor(t *testing.T) {
+
+	var hm *hvs.HostManifest
+
+	hmBytes, err := ioutil.ReadFile(ManifestPath)
+	if err != nil {
+		t.Fatal("failed to read hostmanifest file : ", err)
+	}
+	err = json.Unmarshal(hmBytes, &hm)
+	if err != nil {
+		t.Fatal("failed to unmarshall hostmanifest : ", err)
+	}
+
+	// no IMA flavor is created for a host without IMA log
+	pf := NewHostPlatformFlavor(hm, nil, nil)
+	flavors, err := pf.GetFlavorPartRaw(hvs.FlavorPartIma)
+	if err != nil || len(flavors) != 0 {
+		t.Errorf("expected no IMA flavor, got %v, error %v", flavors, err)
+	}
+
+	hm.ImaLog, err = hvs.ParseImaLog(`10 91f34b5c671d73504b274a919661cf80dab1e127 ima-ng sha1:1801e1be3e65ef1eaa5c16617bec8f1274eaf6b3 boot_aggregate
+10 8b11bd7aa4efd3e9e8c1c3b1c5cb3f1d3e4bb0e5 ima-ng sha256:2c7dd3a1b0df0b1e0e09c6d1d2cd4e7c6a4b05a3e93e7e1e0ab0a21d9bc0a1f2 /usr/bin/bash
+10 3e4b5a31e8c6b0cbe8d1b0d7a7e0fb4dd2c7b4a1 ima-ng sha256:2c7dd3a1b0df0b1e0e09c6d1d2cd4e7c6a4b05a3e93e7e1e0ab0a21d9bc0a1f2 /usr/bin/bash`)
+	if err != nil {
+		t.Fatal("failed to parse IMA log : ", err)
+	}
+
+	pf = NewHostPlatformFlavor(hm, nil, nil)
+	flavors, err = pf.GetFlavorPartRaw(hvs.FlavorPartIma)
+	if err != nil || len(flavors) != 1 {
+		t.Fatalf("expected one IMA flavor, got %v, error %v", flavors, err)
+	}
+	if flavors[0].Meta.Description[hvs.FlavorPartDescription] != hvs.FlavorPartIma.String() {
+		t.Errorf("unexpected flavor part %v", flavors[0].Meta.Description[hvs.FlavorPartDescription])
+	}
+	if flavors[0].Ima == nil || len(flavors[0].Ima.Allowlist) != 1 || flavors[0].Ima.Allowlist[0].FilePath != "/usr/bin/bash" {
+		t.Errorf("unexpected IMA allowlist %v", flavors[0].Ima)
+	}
+}
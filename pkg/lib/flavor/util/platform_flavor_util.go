@@ -120,6 +120,16 @@ func (pfutil PlatformFlavorUtil) GetMetaSectionDetails(hostDetails *taModel.Host
 		}
 		meta.Schema = pfutil.getSchema()
 
+	case hvs.FlavorPartIma:
+		description[hvs.Label] = pfutil.getLabelFromDetails(meta.Vendor.String(), osName, osVersion,
+			flavorPartName.String(), pfutil.getCurrentTimeStamp())
+		description[hvs.OsName] = osName
+		description[hvs.OsVersion] = osVersion
+		description[hvs.FlavorPartDescription] = flavorPartName.String()
+		if hostDetails != nil && hostDetails.HostName != "" {
+			description[hvs.Source] = strings.TrimSpace(hostDetails.HostName)
+		}
+
 	case hvs.FlavorPartAssetTag:
 		description[hvs.FlavorPartDescription] = flavorPartName.String()
 		if hostDetails != nil {
@@ -181,6 +191,33 @@ func (pfutil PlatformFlavorUtil) GetBiosSectionDetails(hostDetails *taModel.Host
 	return nil
 }
 
+// GetImaSectionDetails creates the allowlist of an IMA flavor from the file measurements in the IMA log
+func (pfutil PlatformFlavorUtil) GetImaSectionDetails(imaLog *hvs.ImaLog) *hvs.Ima {
+	log.Trace("flavor/util/platform_flavor_util:GetImaSectionDetails() Entering")
+	defer log.Trace("flavor/util/platform_flavor_util:GetImaSectionDetails() Leaving")
+
+	var ima hvs.Ima
+	if imaLog == nil {
+		return &ima
+	}
+	measurementsAdded := make(map[hvs.ImaFileMeasurement]bool)
+	for _, measurement := range imaLog.Measurements {
+		// the boot aggregate depends on the boot PCRs that are covered by the PLATFORM and OS flavors
+		if measurement.FileName == hvs.ImaBootAggregate {
+			continue
+		}
+		fileMeasurement := hvs.ImaFileMeasurement{
+			FilePath: measurement.FileName,
+			FileHash: measurement.FileHash,
+		}
+		if !measurementsAdded[fileMeasurement] {
+			measurementsAdded[fileMeasurement] = true
+			ima.Allowlist = append(ima.Allowlist, fileMeasurement)
+		}
+	}
+	return &ima
+}
+
 // getSchema sets the schema for the Meta struct in the flavor
 func (pfutil PlatformFlavorUtil) getSchema() *hvs.Schema {
 	log.Trace("flavor/util/platform_flavor_util:getSchema() Entering")
@@ -483,6 +520,41 @@ func (pfutil PlatformFlavorUtil) GetPcrRulesMap(flavorPart hvs.FlavorPartName, f
 	return pcrRulesForFlavorPart, nil
 }
 
+// GetCustomRules Helper function to collect the custom rules defined for the flavor part in the flavor templates.
+// Rules are identified by name, a rule defined in more than one template is only added once.
+func (pfutil PlatformFlavorUtil) GetCustomRules(flavorPart hvs.FlavorPartName, flavorTemplates []hvs.FlavorTemplate) []hvs.CustomRule {
+	log.Trace("flavor/util/platform_flavor_util:GetCustomRules() Entering")
+	defer log.Trace("flavor/util/platform_flavor_util:GetCustomRules() Leaving")
+
+	var customRules []hvs.CustomRule
+	ruleNames := make(map[string]bool)
+	for _, flavorTemplate := range flavorTemplates {
+		if flavorTemplate.FlavorParts == nil {
+			continue
+		}
+		var templateFlavorPart *hvs.FlavorPart
+		switch flavorPart {
+		case hvs.FlavorPartPlatform:
+			templateFlavorPart = flavorTemplate.FlavorParts.Platform
+		case hvs.FlavorPartOs:
+			templateFlavorPart = flavorTemplate.FlavorParts.OS
+		case hvs.FlavorPartHostUnique:
+			templateFlavorPart = flavorTemplate.FlavorParts.HostUnique
+		}
+		if templateFlavorPart == nil {
+			continue
+		}
+		for _, customRule := range templateFlavorPart.CustomRules {
+			if _, ok := ruleNames[customRule.Name]; !ok {
+				ruleNames[customRule.Name] = true
+				customRules = append(customRules, customRule)
+			}
+		}
+	}
+
+	return customRules
+}
+
 func getPcrRulesForFlavorPart(flavorPart *hvs.FlavorPart, pcrList map[hvs.PcrIndex]hvs.PcrListRules) (map[hvs.PcrIndex]hvs.PcrListRules, error) {
 	log.Trace("flavor/util/platform_flavor_util:getPcrRulesForFlavorPart() Entering")
 	defer log.Trace("flavor/util/platform_flavor_util:getPcrRulesForFlavorPart() Leaving")
@@ -5,6 +5,7 @@
 package host_connector
 
 import (
+	"context"
 	"crypto/x509"
 	commLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector/constants"
@@ -18,6 +19,8 @@ var secLog = commLog.GetSecurityLogger()
 // HostConnectorProvider is an interface implemented by HostConnectorFactory for injecting HostConnector instances at runtime
 type HostConnectorProvider interface {
 	NewHostConnector(string) (HostConnector, error)
+	// NewHostConnectorWithContext returns a HostConnector whose requests to the host are bound to the context
+	NewHostConnectorWithContext(context.Context, string) (HostConnector, error)
 }
 
 type HostConnectorFactory struct {
@@ -31,6 +34,10 @@ func NewHostConnectorFactory(aasApiUrl string, trustedCaCerts []x509.Certificate
 }
 
 func (htcFactory *HostConnectorFactory) NewHostConnector(connectionString string) (HostConnector, error) {
+	return htcFactory.NewHostConnectorWithContext(context.Background(), connectionString)
+}
+
+func (htcFactory *HostConnectorFactory) NewHostConnectorWithContext(ctx context.Context, connectionString string) (HostConnector, error) {
 
 	log.Trace("host_connector/host_connector_factory:NewHostConnector() Entering")
 	defer log.Trace("host_connector/host_connector_factory:NewHostConnector() Leaving")
@@ -50,5 +57,5 @@ func (htcFactory *HostConnectorFactory) NewHostConnector(connectionString string
 	default:
 		return nil, errors.New("host_connector_factory:NewHostConnector() Vendor not supported yet: " + vendorConnector.Vendor.String())
 	}
-	return connectorFactory.GetHostConnector(vendorConnector, htcFactory.aasApiUrl, htcFactory.trustedCaCerts)
+	return connectorFactory.GetHostConnector(ctx, vendorConnector, htcFactory.aasApiUrl, htcFactory.trustedCaCerts)
 }
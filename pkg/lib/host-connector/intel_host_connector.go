@@ -5,9 +5,7 @@
 package host_connector
 
 import (
-	"crypto/x509"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
@@ -55,7 +53,6 @@ func (ic *IntelConnector) GetHostManifestAcceptNonce(nonce string, pcrList []int
 	log.Trace("intel_host_connector:GetHostManifestAcceptNonce() Entering")
 	defer log.Trace("intel_host_connector:GetHostManifestAcceptNonce() Leaving")
 
-	var verificationNonce string
 	var hostManifest hvs.HostManifest
 
 	//Hardcoded pcr list here since there is no use case for customized pcr list
@@ -84,53 +81,11 @@ func (ic *IntelConnector) GetHostManifestAcceptNonce(nonce string, pcrList []int
 			"quote response")
 	}
 
-	nonceInBytes, err := base64.StdEncoding.DecodeString(nonce)
-	if err != nil {
-		return hvs.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Base64 decode of TPM "+
-			"nonce failed")
-	}
-
-	verificationNonce, err = util.GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
-	if err != nil {
-		return hvs.HostManifest{}, err
-	}
-	secLog.Debug("intel_host_connector:GetHostManifestAcceptNonce() Updated Verification nonce is : ", verificationNonce)
-
-	aikCertInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Aik)
-	if err != nil {
-		return hvs.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error decoding"+
-			"AIK certificate to bytes")
-	}
-
-	//Convert base64 encoded AIK to Pem format
-	aikPem, _ := pem.Decode(aikCertInBytes)
-	aikCertificate, err := x509.ParseCertificate(aikPem.Bytes)
-
-	if err != nil {
-		return hvs.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error parsing "+
-			"AIK certicate")
-	}
-
-	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
-	if err != nil {
-		return hvs.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error converting "+
-			"tpm quote to bytes")
-	}
-
-	verificationNonceInBytes, err := base64.StdEncoding.DecodeString(verificationNonce)
-	if err != nil {
-		return hvs.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error converting "+
-			"nonce to bytes")
-	}
-	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Verifying quote and retrieving PCR manifest from TPM quote " +
-		"response ...")
-	pcrManifest, pcrsDigest, err := util.VerifyQuoteAndGetPCRManifest(tpmQuoteResponse.EventLog, verificationNonceInBytes,
-		tpmQuoteInBytes, aikCertificate)
+	quoteManifest, err := util.GetHostManifestFromTpmQuote(nonce, tpmQuoteResponse)
 	if err != nil {
 		return hvs.HostManifest{}, errors.Wrap(err, "intel_host_connector:GetHostManifestAcceptNonce() Error verifying "+
 			"TPM Quote")
 	}
-	log.Info("intel_host_connector:GetHostManifestAcceptNonce() Successfully retrieved PCR manifest from quote")
 
 	isWlaInstalled := false
 	for _, component := range hostManifest.HostInfo.InstalledComponents {
@@ -174,14 +129,9 @@ func (ic *IntelConnector) GetHostManifestAcceptNonce(nonce string, pcrList []int
 		}
 		bindingKeyCertificateBase64 = base64.StdEncoding.EncodeToString(bindingKeyCertificate.Bytes)
 	}
-	aikCertificateBase64 := base64.StdEncoding.EncodeToString(aikPem.Bytes)
-
-	hostManifest.PcrManifest = pcrManifest
-	hostManifest.AIKCertificate = aikCertificateBase64
-	hostManifest.AssetTagDigest = tpmQuoteResponse.AssetTag
-	hostManifest.BindingKeyCertificate = bindingKeyCertificateBase64
-	hostManifest.MeasurementXmls = tpmQuoteResponse.TcbMeasurements.TcbMeasurements
-	hostManifest.QuoteDigest = hex.EncodeToString(pcrsDigest) + hostManifest.AssetTagDigest
+	quoteManifest.HostInfo = hostManifest.HostInfo
+	quoteManifest.BindingKeyCertificate = bindingKeyCertificateBase64
+	hostManifest = quoteManifest
 
 	hostManifestJson, err := json.Marshal(hostManifest)
 	if err != nil {
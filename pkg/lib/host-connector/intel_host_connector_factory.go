@@ -5,6 +5,7 @@
 package host_connector
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"github.com/intel-secl/intel-secl/v4/pkg/hvs/constants"
@@ -21,7 +22,7 @@ type IntelConnectorFactory struct {
 	natsServers []string
 }
 
-func (icf *IntelConnectorFactory) GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string,
+func (icf *IntelConnectorFactory) GetHostConnector(ctx context.Context, vendorConnector types.VendorConnector, aasApiUrl string,
 	trustedCaCerts []x509.Certificate) (HostConnector, error) {
 
 	var taClient client.TAClient
@@ -67,7 +68,7 @@ func (icf *IntelConnectorFactory) GetHostConnector(vendorConnector types.VendorC
 
 	} else {
 
-		taClient, err = client.NewTAClient(aasApiUrl,
+		taClient, err = client.NewTAClientWithContext(ctx, aasApiUrl,
 			taApiURL,
 			vendorConnector.Configuration.Username,
 			vendorConnector.Configuration.Password,
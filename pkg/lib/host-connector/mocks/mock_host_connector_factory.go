@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/xml"
@@ -28,6 +29,11 @@ type MockHostConnectorFactory struct{}
 
 // NewHostConnector returns a mocked instance of VendorConnector passing in a MockedTAClient or a MockVMwareClient as required
 func (htcFactory MockHostConnectorFactory) NewHostConnector(connectionString string) (host_connector.HostConnector, error) {
+	return htcFactory.NewHostConnectorWithContext(context.Background(), connectionString)
+}
+
+// NewHostConnectorWithContext returns a mocked instance of VendorConnector, the context is not used by the mocks
+func (htcFactory MockHostConnectorFactory) NewHostConnectorWithContext(ctx context.Context, connectionString string) (host_connector.HostConnector, error) {
 	vendorConnector, _ := util.GetConnectorDetails(connectionString)
 	var connectorFactory host_connector.VendorHostConnectorFactory
 	switch vendorConnector.Vendor {
@@ -38,14 +44,14 @@ func (htcFactory MockHostConnectorFactory) NewHostConnector(connectionString str
 	default:
 		return nil, errors.New("mock_host_connector_factory:NewHostConnector() Vendor not supported yet: " + vendorConnector.Vendor.String())
 	}
-	return connectorFactory.GetHostConnector(vendorConnector, "", nil)
+	return connectorFactory.GetHostConnector(ctx, vendorConnector, "", nil)
 }
 
 // MockIntelConnectorFactory implements the VendorConnectorFactory interface
 type MockIntelConnectorFactory struct{}
 
 // GetHostConnector returns an instance of IntelConnector passing in a MockedTAClient
-func (micf MockIntelConnectorFactory) GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string, trustedCaCerts []x509.Certificate) (host_connector.HostConnector, error) {
+func (micf MockIntelConnectorFactory) GetHostConnector(ctx context.Context, vendorConnector types.VendorConnector, aasApiUrl string, trustedCaCerts []x509.Certificate) (host_connector.HostConnector, error) {
 	mhc := MockIntelConnector{}
 
 	// AnythingOfType allows us to wildcard the digest hash since this will be computed at runtime
@@ -84,7 +90,7 @@ func (micf MockIntelConnectorFactory) GetHostConnector(vendorConnector types.Ven
 type MockVmwareConnectorFactory struct{}
 
 // GetHostConnector returns an instance of VmwareConnector passing in a MockVMwareClient
-func (micf MockVmwareConnectorFactory) GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string, trustedCaCerts []x509.Certificate) (host_connector.HostConnector, error) {
+func (micf MockVmwareConnectorFactory) GetHostConnector(ctx context.Context, vendorConnector types.VendorConnector, aasApiUrl string, trustedCaCerts []x509.Certificate) (host_connector.HostConnector, error) {
 	vmc := MockVmwareConnector{}
 
 	var hostInfoList []mo.HostSystem
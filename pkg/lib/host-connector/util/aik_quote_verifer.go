@@ -74,19 +74,37 @@ func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []by
 
 	//Get the length of quote
 	index := 0
-	quoteInfoLen := binary.BigEndian.Uint16(tpmQuoteInBytes[0:2])
+	quoteInfoLenBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	quoteInfoLen := binary.BigEndian.Uint16(quoteInfoLenBytes)
 
 	index += 2
-	quoteInfo := tpmQuoteInBytes[index : index+int(quoteInfoLen)]
+	quoteInfo, err := readQuoteBytes(tpmQuoteInBytes, index, int(quoteInfoLen))
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
 
 	index += 6
-	tpm2bNameSize := binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
+	tpm2bNameSizeBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	tpm2bNameSize := binary.BigEndian.Uint16(tpm2bNameSizeBytes)
 
 	index += 2 + int(tpm2bNameSize)
-	tpm2bDataSize := binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
+	tpm2bDataSizeBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	tpm2bDataSize := binary.BigEndian.Uint16(tpm2bDataSizeBytes)
 
 	index += 2
-	tpm2bData := tpmQuoteInBytes[index : index+int(tpm2bDataSize)]
+	tpm2bData, err := readQuoteBytes(tpmQuoteInBytes, index, int(tpm2bDataSize))
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
 		"Received nonce is : %s", base64.StdEncoding.EncodeToString(tpm2bData))
 	if !bytes.EqualFold(tpm2bData, verificationNonce) {
@@ -105,7 +123,11 @@ func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []by
 	index += 17 // skip over the TPMS_CLOCKINFO structure - Not interested
 	index += 8  // skip over the firmware info - Not interested
 
-	pcrBankCount := binary.BigEndian.Uint32(tpmQuoteInBytes[index : index+4])
+	pcrBankCountBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 4)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	pcrBankCount := binary.BigEndian.Uint32(pcrBankCountBytes)
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() PCR bank count is : %v", pcrBankCount)
 	if pcrBankCount > MAX_PCR_BANKS {
 		return hvs.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() AIK Quote " +
@@ -116,18 +138,36 @@ func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []by
 	index += 4
 	pcrSelection := make([]pcrSelection, pcrBankCount)
 	for i := 0; i < int(pcrBankCount); i++ {
-		pcrSelection[i].hashAlg = binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
+		hashAlgBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 2)
+		if err != nil {
+			return hvs.PcrManifest{}, nil, err
+		}
+		pcrSelection[i].hashAlg = binary.BigEndian.Uint16(hashAlgBytes)
 		index += 2
-		pcrSelection[i].size = int(tpmQuoteInBytes[index])
+		sizeBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 1)
+		if err != nil {
+			return hvs.PcrManifest{}, nil, err
+		}
+		pcrSelection[i].size = int(sizeBytes[0])
 		index += 1
-		pcrSelection[i].pcrSelected = tpmQuoteInBytes[index : index+pcrSelection[i].size]
+		pcrSelection[i].pcrSelected, err = readQuoteBytes(tpmQuoteInBytes, index, pcrSelection[i].size)
+		if err != nil {
+			return hvs.PcrManifest{}, nil, err
+		}
 		index += pcrSelection[i].size
 	}
 
-	tpm2bDigestSize := binary.BigEndian.Uint16(tpmQuoteInBytes[index : index+2])
+	tpm2bDigestSizeBytes, err := readQuoteBytes(tpmQuoteInBytes, index, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	tpm2bDigestSize := binary.BigEndian.Uint16(tpm2bDigestSizeBytes)
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() tpm2bDigestSize is : %v", tpm2bDigestSize)
 	index += 2
-	tpm2bDigest := tpmQuoteInBytes[index : index+int(tpm2bDigestSize)]
+	tpm2bDigest, err := readQuoteBytes(tpmQuoteInBytes, index, int(tpm2bDigestSize))
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest()  PCR manifest digest: %v", tpm2bDigest)
 
 	/* PART 2: TPMT_SIGNATURE
@@ -135,44 +175,65 @@ func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []by
 	and extra data. So jump to TPMT_SIGNATURE
 	*/
 
-	tpmtSigIndex := 2 + quoteInfoLen
+	tpmtSigIndex := 2 + int(quoteInfoLen)
 	tpmtSig := tpmQuoteInBytes[tpmtSigIndex:]
-	var pos uint16 = 0
+	pos := 0
 	/* sigAlg -indicates the signature algorithm TPMI_SIG_ALG_SCHEME
 	 * for now, it is TPM_ALG_RSASSA with value 0x0014
 	 */
-	tpmtSignatureAlg := binary.BigEndian.Uint16(tpmtSig[0:2])
+	tpmtSignatureAlgBytes, err := readQuoteBytes(tpmtSig, pos, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	tpmtSignatureAlg := binary.BigEndian.Uint16(tpmtSignatureAlgBytes)
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TPM signature Algorithm: %v", tpmtSignatureAlg)
 	/* hashAlg used by the signature algorithm indicated above
 	 * TPM_ALG_HASH
 	 * for TPM_ALG_RSASSA, the default hash algorithm is TPM_ALG_SHA256 with value 0x000b
 	 */
 	pos += 2
-	tpmtSignatureHashAlg := binary.BigEndian.Uint16(tpmtSig[pos : pos+2])
+	tpmtSignatureHashAlgBytes, err := readQuoteBytes(tpmtSig, pos, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	tpmtSignatureHashAlg := binary.BigEndian.Uint16(tpmtSignatureHashAlgBytes)
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TPM signature Hash Algorithm: %v", tpmtSignatureHashAlg)
 
 	pos += 2
-	tpmtSignatureSize := binary.BigEndian.Uint16(tpmtSig[pos : pos+2])
+	tpmtSignatureSizeBytes, err := readQuoteBytes(tpmtSig, pos, 2)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
+	tpmtSignatureSize := int(binary.BigEndian.Uint16(tpmtSignatureSizeBytes))
 
 	pos += 2
-	tpmtSignature := tpmtSig[pos : pos+tpmtSignatureSize]
+	tpmtSignature, err := readQuoteBytes(tpmtSig, pos, tpmtSignatureSize)
+	if err != nil {
+		return hvs.PcrManifest{}, nil, err
+	}
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() TPMT signature : %v", tpmtSignature)
 
+	aikPublicKey, ok := aikCertificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return hvs.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() " +
+			"AIK certificate does not contain an RSA public key")
+	}
+
 	hash := sha256.New()
-	_, err := hash.Write(quoteInfo)
+	_, err = hash.Write(quoteInfo)
 	if err != nil {
 		return hvs.PcrManifest{}, nil, errors.Wrap(err, "Error writing quote information")
 	}
 	pcrsDigest := hash.Sum(nil)
 	secLog.Debugf("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() Quote signature : %v", pcrsDigest)
-	err = rsa.VerifyPKCS1v15(aikCertificate.PublicKey.(*rsa.PublicKey), crypto.SHA256, pcrsDigest, tpmtSignature)
+	err = rsa.VerifyPKCS1v15(aikPublicKey, crypto.SHA256, pcrsDigest, tpmtSignature)
 	if err != nil {
 		return hvs.PcrManifest{}, nil, errors.Wrap(err, "util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() "+
 			"Error verifying pcrs digest")
 	}
 
 	pos += tpmtSignatureSize
-	pcrLen := uint16(len(tpmQuoteInBytes)) - (pos + tpmtSigIndex)
+	pcrLen := len(tpmtSig) - pos
 	if pcrLen <= 0 {
 		return hvs.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() " +
 			"AIK Quote verification failed, No PCR values included in quote")
@@ -203,6 +264,10 @@ func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []by
 			pcrSelected := pcrSelection[j].pcrSelected
 			selected := pcrSelected[pcr/8] & (1 << (uint16(pcr) % 8))
 			if selected > 0 {
+				if pcrPos+pcrSize > len(pcrs) {
+					return hvs.PcrManifest{}, nil, errors.New("util/aik_quote_verifier:VerifyQuoteAndGetPCRManifest() " +
+						"AIK Quote verification failed, Quote does not include all the selected PCR values")
+				}
 				if (pcrPos + pcrSize) < pcrConcatLen {
 					pcrConcat = append(pcrConcat, pcrs[pcrPos:pcrPos+pcrSize]...)
 				}
@@ -250,6 +315,15 @@ func VerifyQuoteAndGetPCRManifest(decodedEventLog string, verificationNonce []by
 	return pcrManifest, pcrsDigest, nil
 }
 
+// readQuoteBytes returns the n bytes of the quote starting at index, or an error when the quote is too short to hold them
+func readQuoteBytes(tpmQuoteInBytes []byte, index, n int) ([]byte, error) {
+	if index < 0 || n < 0 || index+n > len(tpmQuoteInBytes) {
+		return nil, errors.Errorf("util/aik_quote_verifier:readQuoteBytes() Malformed quote, reading %d bytes at "+
+			"offset %d exceeds the quote length %d", n, index, len(tpmQuoteInBytes))
+	}
+	return tpmQuoteInBytes[index : index+n], nil
+}
+
 func GetVerificationNonce(nonce []byte, quoteResponse taModel.TpmQuoteResponse) (string, error) {
 	log.Trace("util/aik_quote_verifier:GetVerificationNonce() Entering")
 	defer log.Trace("util/aik_quote_verifier:GetVerificationNonce() Leaving")
@@ -371,6 +445,20 @@ func getPcrEventLog(eventLog string) (hvs.PcrEventLogMap, error) {
 		return pcrEventLogMap, nil
 	}
 
+	// agents that do not convert the event log send the base64 encoded binary TCG2 event log instead of the
+	// json event log
+	if !strings.HasPrefix(strings.TrimSpace(eventLog), "[") {
+		binaryEventLog, err := base64.StdEncoding.DecodeString(strings.TrimSpace(eventLog))
+		if err != nil {
+			return hvs.PcrEventLogMap{}, errors.Wrap(err, "util/aik_quote_verifier:getPcrEventLog() Error decoding binary event log")
+		}
+		pcrEventLogMap, err = ParseTcg2EventLog(binaryEventLog)
+		if err != nil {
+			return hvs.PcrEventLogMap{}, errors.Wrap(err, "util/aik_quote_verifier:getPcrEventLog() Error parsing binary event log")
+		}
+		return pcrEventLogMap, nil
+	}
+
 	err := json.Unmarshal([]byte(eventLog), &measureLogs)
 	if err != nil {
 		return hvs.PcrEventLogMap{}, errors.Wrap(err, "util/aik_quote_verifier:getPcrEventLog() Error unmarshalling measureLog")
@@ -92,3 +92,37 @@ func TestGetVerificationNonceAssetTagProvisioned(t *testing.T) {
 	_, err = GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
 	assert.NoError(t, err)
 }
+
+func TestVerifyQuoteAndGetPCRManifestTruncatedQuote(t *testing.T) {
+	var tpmQuoteResponse taModel.TpmQuoteResponse
+	b, err := ioutil.ReadFile("../test/sample_tpm_quote.xml")
+	assert.NoError(t, err)
+	err = xml.Unmarshal(b, &tpmQuoteResponse)
+	assert.NoError(t, err)
+
+	decodedEventLogBytes, err := ioutil.ReadFile("../test/sample_measure_log.json")
+	assert.NoError(t, err)
+
+	aikCertInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Aik)
+	assert.NoError(t, err)
+	aikPem, _ := pem.Decode(aikCertInBytes)
+	aikCertificate, err := x509.ParseCertificate(aikPem.Bytes)
+	assert.NoError(t, err)
+
+	nonceInBytes, err := base64.StdEncoding.DecodeString("ZGVhZGJlZWZkZWFkYmVlZmRlYWRiZWVmZGVhZGJlZWZkZWFkYmVlZiA=")
+	assert.NoError(t, err)
+	verificationNonce, err := GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
+	assert.NoError(t, err)
+	verificationNonceInBytes, err := base64.StdEncoding.DecodeString(verificationNonce)
+	assert.NoError(t, err)
+
+	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
+	assert.NoError(t, err)
+
+	// every truncation of the quote must be rejected without reading past its end
+	for quoteLen := 0; quoteLen < len(tpmQuoteInBytes); quoteLen++ {
+		_, _, err = VerifyQuoteAndGetPCRManifest(string(decodedEventLogBytes), verificationNonceInBytes,
+			tpmQuoteInBytes[:quoteLen], aikCertificate)
+		assert.Error(t, err, "quote truncated to %d bytes", quoteLen)
+	}
+}
@@ -0,0 +1,473 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// Event types defined by the TCG PC Client Platform Firmware Profile Specification
+const (
+	EV_POST_CODE                     = 0x1
+	EV_NO_ACTION                     = 0x3
+	EV_SEPARATOR                     = 0x4
+	EV_ACTION                        = 0x5
+	EV_S_CRTM_CONTENTS               = 0x7
+	EV_IPL                           = 0xd
+	EV_EFI_VARIABLE_DRIVER_CONFIG    = 0x80000001
+	EV_EFI_VARIABLE_BOOT             = 0x80000002
+	EV_EFI_BOOT_SERVICES_APPLICATION = 0x80000003
+	EV_EFI_BOOT_SERVICES_DRIVER      = 0x80000004
+	EV_EFI_RUNTIME_SERVICES_DRIVER   = 0x80000005
+	EV_EFI_ACTION                    = 0x80000007
+	EV_EFI_VARIABLE_BOOT2            = 0x8000000c
+	EV_EFI_VARIABLE_AUTHORITY        = 0x800000e0
+	TBOOT_EVENT                      = 0x501
+	tcg2SpecIdEventSignature         = "Spec ID Event03\x00"
+	tcg2StartupLocalitySignature     = "StartupLocality\x00"
+	tcg2MaxEventSize                 = 1 << 24
+	uefiDevicePathTypeMedia          = 0x04
+	uefiDevicePathSubTypeFilePath    = 0x04
+	uefiDevicePathTypeEnd            = 0x7f
+	uefiVariableDataHeaderSize       = 32
+	uefiImageLoadEventHeaderSize     = 32
+	startupLocalityEventSize         = 17
+)
+
+var tcg2EventTypeNames = map[uint32]string{
+	0x0:        "EV_PREBOOT_CERT",
+	0x1:        "EV_POST_CODE",
+	0x2:        "EV_UNUSED",
+	0x3:        "EV_NO_ACTION",
+	0x4:        "EV_SEPARATOR",
+	0x5:        "EV_ACTION",
+	0x6:        "EV_EVENT_TAG",
+	0x7:        "EV_S_CRTM_CONTENTS",
+	0x8:        "EV_S_CRTM_VERSION",
+	0x9:        "EV_CPU_MICROCODE",
+	0xa:        "EV_PLATFORM_CONFIG_FLAGS",
+	0xb:        "EV_TABLE_OF_DEVICES",
+	0xc:        "EV_COMPACT_HASH",
+	0xd:        "EV_IPL",
+	0xe:        "EV_IPL_PARTITION_DATA",
+	0xf:        "EV_NONHOST_CODE",
+	0x10:       "EV_NONHOST_CONFIG",
+	0x11:       "EV_NONHOST_INFO",
+	0x12:       "EV_OMIT_BOOT_DEVICE_EVENTS",
+	0x80000000: "EV_EFI_EVENT_BASE",
+	0x80000001: "EV_EFI_VARIABLE_DRIVER_CONFIG",
+	0x80000002: "EV_EFI_VARIABLE_BOOT",
+	0x80000003: "EV_EFI_BOOT_SERVICES_APPLICATION",
+	0x80000004: "EV_EFI_BOOT_SERVICES_DRIVER",
+	0x80000005: "EV_EFI_RUNTIME_SERVICES_DRIVER",
+	0x80000006: "EV_EFI_GPT_EVENT",
+	0x80000007: "EV_EFI_ACTION",
+	0x80000008: "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+	0x80000009: "EV_EFI_HANDOFF_TABLES",
+	0x8000000a: "EV_EFI_PLATFORM_FIRMWARE_BLOB2",
+	0x8000000b: "EV_EFI_HANDOFF_TABLES2",
+	0x8000000c: "EV_EFI_VARIABLE_BOOT2",
+	0x80000010: "EV_EFI_HCRTM_EVENT",
+	0x800000e0: "EV_EFI_VARIABLE_AUTHORITY",
+	0x800000e1: "EV_EFI_SPDM_FIRMWARE_BLOB",
+	0x800000e2: "EV_EFI_SPDM_FIRMWARE_CONFIG",
+	// Intel TXT event types
+	0x400: "EVTYPE_BASE",
+	0x401: "PCR_MAPPING",
+	0x402: "HASH_START",
+	0x403: "COMBINED_HASH",
+	0x404: "MLE_HASH",
+	0x40a: "BIOSAC_REG_DATA",
+	0x40b: "CPU_SCRTM_STAT",
+	0x40c: "LCP_CONTROL_HASH",
+	0x40d: "ELEMENTS_HASH",
+	0x40e: "STM_HASH",
+	0x40f: "OSSINITDATA_CAP_HASH",
+	0x410: "SINIT_PUBKEY_HASH",
+	0x411: "LCP_HASH",
+	0x412: "LCP_DETAILS_HASH",
+	0x413: "LCP_AUTHORITIES_HASH",
+	0x414: "NV_INFO_HASH",
+	0x415: "COLD_BOOT_BIOS_HASH",
+	0x416: "KM_HASH",
+	0x417: "BPM_HASH",
+	0x418: "KM_INFO_HASH",
+	0x419: "BPM_INFO_HASH",
+	0x41a: "BOOT_POL_HASH",
+	0x4ff: "CAP_VALUE",
+}
+
+// wellKnownComponents are added as tags to events that measure or load a file with a name starting with them so
+// that flavor templates can select events independent of the distribution specific file names
+var wellKnownComponents = []string{"shim", "grub", "mmx", "vmlinuz", "initrd"}
+
+// tcg2Reader reads the little endian structures of a binary event log
+type tcg2Reader struct {
+	data   []byte
+	offset int
+}
+
+func (r *tcg2Reader) remaining() int {
+	return len(r.data) - r.offset
+}
+
+func (r *tcg2Reader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.remaining() < n {
+		return nil, errors.Errorf("Event log truncated at offset %d", r.offset)
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b, nil
+}
+
+func (r *tcg2Reader) uint16() (uint16, error) {
+	b, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (r *tcg2Reader) uint32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// ParseTcg2EventLog parses a binary event log in the format of the TCG PC Client Platform Firmware Profile, as
+// exported by Linux in /sys/kernel/security/tpm0/binary_bios_measurements, into a PcrEventLogMap. Crypto agile
+// logs (TCG_PCR_EVENT2) provide the events of all the SHA1, SHA256 and SHA384 banks, logs in the legacy format only
+// provide the SHA1 bank.
+func ParseTcg2EventLog(eventLog []byte) (hvs.PcrEventLogMap, error) {
+	log.Trace("util/tcg2_event_log:ParseTcg2EventLog() Entering")
+	defer log.Trace("util/tcg2_event_log:ParseTcg2EventLog() Leaving")
+
+	var pcrEventLogMap hvs.PcrEventLogMap
+	reader := &tcg2Reader{data: eventLog}
+
+	// The first event always uses the legacy TCG_PCR_EVENT format
+	pcrIndex, eventType, digest, eventData, err := readTcgPcrEvent(reader)
+	if err != nil {
+		return pcrEventLogMap, errors.Wrap(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading first event")
+	}
+
+	if eventType != EV_NO_ACTION || !bytes.HasPrefix(eventData, []byte(tcg2SpecIdEventSignature)) {
+		// legacy SHA1 only event log
+		eventNumber := 0
+		for {
+			addTcg2Event(&pcrEventLogMap, SHA1, pcrIndex, eventType, digest, eventData)
+			if reader.remaining() == 0 {
+				break
+			}
+			eventNumber++
+			pcrIndex, eventType, digest, eventData, err = readTcgPcrEvent(reader)
+			if err != nil {
+				return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+			}
+		}
+		return pcrEventLogMap, nil
+	}
+
+	digestSizes, err := parseSpecIdEvent(eventData)
+	if err != nil {
+		return pcrEventLogMap, errors.Wrap(err, "util/tcg2_event_log:ParseTcg2EventLog() Error parsing spec ID event")
+	}
+
+	for eventNumber := 1; reader.remaining() > 0; eventNumber++ {
+		if pcrIndex, err = reader.uint32(); err != nil {
+			return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+		}
+		if eventType, err = reader.uint32(); err != nil {
+			return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+		}
+		digestCount, err := reader.uint32()
+		if err != nil {
+			return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+		}
+		if digestCount > MAX_PCR_BANKS {
+			return pcrEventLogMap, errors.Errorf("util/tcg2_event_log:ParseTcg2EventLog() Event %d has %d digests", eventNumber, digestCount)
+		}
+
+		digests := make(map[string][]byte)
+		for i := 0; i < int(digestCount); i++ {
+			hashAlg, err := reader.uint16()
+			if err != nil {
+				return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+			}
+			digestSize, ok := digestSizes[hashAlg]
+			if !ok {
+				return pcrEventLogMap, errors.Errorf("util/tcg2_event_log:ParseTcg2EventLog() Event %d uses algorithm %#x that is not in the spec ID event", eventNumber, hashAlg)
+			}
+			digest, err := reader.bytes(int(digestSize))
+			if err != nil {
+				return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+			}
+			if bank := getBankForAlgorithm(hashAlg); bank != "" {
+				digests[bank] = digest
+			}
+		}
+
+		eventSize, err := reader.uint32()
+		if err != nil {
+			return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+		}
+		if eventSize > tcg2MaxEventSize {
+			return pcrEventLogMap, errors.Errorf("util/tcg2_event_log:ParseTcg2EventLog() Event %d has invalid size %d", eventNumber, eventSize)
+		}
+		eventData, err := reader.bytes(int(eventSize))
+		if err != nil {
+			return pcrEventLogMap, errors.Wrapf(err, "util/tcg2_event_log:ParseTcg2EventLog() Error reading event %d", eventNumber)
+		}
+
+		for _, bank := range []string{SHA1, SHA256, SHA384} {
+			if digest, ok := digests[bank]; ok {
+				addTcg2Event(&pcrEventLogMap, bank, pcrIndex, eventType, digest, eventData)
+			}
+		}
+	}
+
+	log.Debugf("util/tcg2_event_log:ParseTcg2EventLog() Successfully parsed binary event log")
+	return pcrEventLogMap, nil
+}
+
+// readTcgPcrEvent reads an event in the legacy TCG_PCR_EVENT format having a SHA1 digest
+func readTcgPcrEvent(reader *tcg2Reader) (uint32, uint32, []byte, []byte, error) {
+	pcrIndex, err := reader.uint32()
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	eventType, err := reader.uint32()
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	digest, err := reader.bytes(SHA1_SIZE)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	eventSize, err := reader.uint32()
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	if eventSize > tcg2MaxEventSize {
+		return 0, 0, nil, nil, errors.Errorf("Invalid event size %d", eventSize)
+	}
+	eventData, err := reader.bytes(int(eventSize))
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	return pcrIndex, eventType, digest, eventData, nil
+}
+
+// parseSpecIdEvent returns the digest sizes of the algorithms listed in the TCG_EfiSpecIDEventStruct
+func parseSpecIdEvent(eventData []byte) (map[uint16]uint16, error) {
+	reader := &tcg2Reader{data: eventData}
+	// skip signature, platformClass, specVersionMinor, specVersionMajor, specErrata and uintnSize
+	if _, err := reader.bytes(len(tcg2SpecIdEventSignature) + 8); err != nil {
+		return nil, err
+	}
+	numberOfAlgorithms, err := reader.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if numberOfAlgorithms == 0 || numberOfAlgorithms > MAX_PCR_BANKS {
+		return nil, errors.Errorf("Invalid number of algorithms %d", numberOfAlgorithms)
+	}
+	digestSizes := make(map[uint16]uint16)
+	for i := 0; i < int(numberOfAlgorithms); i++ {
+		algorithmId, err := reader.uint16()
+		if err != nil {
+			return nil, err
+		}
+		digestSize, err := reader.uint16()
+		if err != nil {
+			return nil, err
+		}
+		if expectedSize, ok := map[uint16]uint16{TPM_API_ALG_ID_SHA1: SHA1_SIZE, TPM_API_ALG_ID_SHA256: SHA256_SIZE,
+			TPM_API_ALG_ID_SHA384: SHA384_SIZE}[algorithmId]; ok && expectedSize != digestSize {
+			return nil, errors.Errorf("Invalid digest size %d for algorithm %#x", digestSize, algorithmId)
+		}
+		digestSizes[algorithmId] = digestSize
+	}
+	return digestSizes, nil
+}
+
+func getBankForAlgorithm(hashAlg uint16) string {
+	switch hashAlg {
+	case TPM_API_ALG_ID_SHA1:
+		return SHA1
+	case TPM_API_ALG_ID_SHA256:
+		return SHA256
+	case TPM_API_ALG_ID_SHA384:
+		return SHA384
+	}
+	return ""
+}
+
+// addTcg2Event decodes the event and appends it to the event log of the PCR in the given bank. EV_NO_ACTION events
+// are not extended to the PCRs and are skipped except for the startup locality event needed to replay PCR 0.
+func addTcg2Event(pcrEventLogMap *hvs.PcrEventLogMap, bank string, pcrIndex, eventType uint32, digest, eventData []byte) {
+	typeName, tags := decodeTcg2Event(eventType, eventData)
+	if eventType == EV_NO_ACTION && (pcrIndex != 0 || len(tags) == 0) {
+		return
+	}
+
+	addPcrEntry(hvs.TpmEventLog{
+		Pcr: hvs.Pcr{Index: int(pcrIndex), Bank: bank},
+		TpmEvent: []hvs.EventLog{{
+			TypeID:      fmt.Sprintf("0x%x", eventType),
+			TypeName:    typeName,
+			Tags:        tags,
+			Measurement: hex.EncodeToString(digest),
+		}},
+	}, pcrEventLogMap)
+}
+
+// decodeTcg2Event returns the type name and the tags used by the verifier rules to identify the event
+func decodeTcg2Event(eventType uint32, eventData []byte) (string, []string) {
+	typeName := tcg2EventTypeNames[eventType]
+	var tags []string
+
+	switch eventType {
+	case EV_NO_ACTION:
+		if len(eventData) == startupLocalityEventSize && bytes.HasPrefix(eventData, []byte(tcg2StartupLocalitySignature)) {
+			tags = append(tags, fmt.Sprintf("StartupLocality%d", eventData[len(eventData)-1]))
+		}
+	case EV_EFI_VARIABLE_DRIVER_CONFIG, EV_EFI_VARIABLE_BOOT, EV_EFI_VARIABLE_BOOT2, EV_EFI_VARIABLE_AUTHORITY:
+		if variableName := decodeUefiVariableName(eventData); variableName != "" {
+			tags = appendNameTags(tags, variableName)
+		}
+	case EV_EFI_BOOT_SERVICES_APPLICATION, EV_EFI_BOOT_SERVICES_DRIVER, EV_EFI_RUNTIME_SERVICES_DRIVER:
+		if filePath := decodeUefiImageFilePath(eventData); filePath != "" {
+			tags = appendComponentTags(tags, path.Base(strings.ReplaceAll(filePath, "\\", "/")))
+		}
+	case EV_IPL:
+		if description := decodeEventString(eventData); description != "" {
+			tags = append(tags, description)
+			for _, field := range strings.Fields(description) {
+				tags = appendComponentTags(tags, path.Base(field))
+			}
+		}
+	case EV_POST_CODE, EV_ACTION, EV_S_CRTM_CONTENTS, EV_EFI_ACTION:
+		if description := decodeEventString(eventData); description != "" {
+			tags = append(tags, description)
+		}
+	case TBOOT_EVENT:
+		if description := decodeEventString(eventData); description != "" {
+			typeName = description
+			tags = append(tags, description)
+		}
+	default:
+		if _, ok := tcg2EventTypeNames[eventType]; ok && eventType >= 0x400 && eventType <= 0x4ff {
+			// Intel TXT events are identified by their type
+			tags = append(tags, typeName)
+		}
+	}
+	return typeName, uniqueTags(tags)
+}
+
+// decodeUefiVariableName returns the name of the variable in UEFI_VARIABLE_DATA
+func decodeUefiVariableName(eventData []byte) string {
+	if len(eventData) < uefiVariableDataHeaderSize {
+		return ""
+	}
+	nameLength := binary.LittleEndian.Uint64(eventData[16:24])
+	if nameLength > uint64(len(eventData)-uefiVariableDataHeaderSize)/2 {
+		return ""
+	}
+	return decodeUtf16(eventData[uefiVariableDataHeaderSize : uefiVariableDataHeaderSize+2*int(nameLength)])
+}
+
+// decodeUefiImageFilePath returns the file path in the device path of UEFI_IMAGE_LOAD_EVENT
+func decodeUefiImageFilePath(eventData []byte) string {
+	if len(eventData) < uefiImageLoadEventHeaderSize {
+		return ""
+	}
+	devicePathLength := binary.LittleEndian.Uint64(eventData[24:32])
+	if devicePathLength > uint64(len(eventData)-uefiImageLoadEventHeaderSize) {
+		return ""
+	}
+	devicePath := eventData[uefiImageLoadEventHeaderSize : uefiImageLoadEventHeaderSize+int(devicePathLength)]
+
+	var filePath string
+	for len(devicePath) >= 4 {
+		nodeType := devicePath[0]
+		nodeSubType := devicePath[1]
+		nodeLength := int(binary.LittleEndian.Uint16(devicePath[2:4]))
+		if nodeLength < 4 || nodeLength > len(devicePath) || nodeType == uefiDevicePathTypeEnd {
+			break
+		}
+		if nodeType == uefiDevicePathTypeMedia && nodeSubType == uefiDevicePathSubTypeFilePath {
+			filePath += decodeUtf16(devicePath[4:nodeLength])
+		}
+		devicePath = devicePath[nodeLength:]
+	}
+	return filePath
+}
+
+func decodeUtf16(data []byte) string {
+	codeUnits := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		codeUnit := binary.LittleEndian.Uint16(data[i : i+2])
+		if codeUnit == 0 {
+			break
+		}
+		codeUnits = append(codeUnits, codeUnit)
+	}
+	return string(utf16.Decode(codeUnits))
+}
+
+// decodeEventString returns the event data when it is printable ASCII text
+func decodeEventString(eventData []byte) string {
+	text := strings.TrimRight(string(eventData), "\x00")
+	for _, c := range []byte(text) {
+		if c < 0x20 || c > 0x7e {
+			return ""
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// appendNameTags adds the name and its lower case form, used by the default flavor templates for UEFI variables
+func appendNameTags(tags []string, name string) []string {
+	tags = append(tags, name)
+	if lowerName := strings.ToLower(name); lowerName != name {
+		tags = append(tags, lowerName)
+	}
+	return tags
+}
+
+// appendComponentTags adds the file name and the well known component it belongs to
+func appendComponentTags(tags []string, fileName string) []string {
+	lowerFileName := strings.ToLower(fileName)
+	for _, component := range wellKnownComponents {
+		if strings.HasPrefix(lowerFileName, component) {
+			return append(tags, fileName, component)
+		}
+	}
+	return tags
+}
+
+func uniqueTags(tags []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,163 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func utf16Bytes(s string) []byte {
+	var buf bytes.Buffer
+	for _, c := range utf16.Encode([]rune(s)) {
+		binary.Write(&buf, binary.LittleEndian, c)
+	}
+	return buf.Bytes()
+}
+
+func specIdEvent() []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	binary.Write(&buf, binary.LittleEndian, uint32(EV_NO_ACTION))
+	buf.Write(make([]byte, SHA1_SIZE))
+
+	var data bytes.Buffer
+	data.WriteString(tcg2SpecIdEventSignature)
+	data.Write([]byte{0, 0, 0, 0, 0, 2, 0, 2})
+	binary.Write(&data, binary.LittleEndian, uint32(2))
+	binary.Write(&data, binary.LittleEndian, []uint16{TPM_API_ALG_ID_SHA1, SHA1_SIZE, TPM_API_ALG_ID_SHA256, SHA256_SIZE})
+	data.WriteByte(0)
+
+	binary.Write(&buf, binary.LittleEndian, uint32(data.Len()))
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
+
+func tcgPcrEvent2(pcrIndex, eventType uint32, fill byte, eventData []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, pcrIndex)
+	binary.Write(&buf, binary.LittleEndian, eventType)
+	binary.Write(&buf, binary.LittleEndian, uint32(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(TPM_API_ALG_ID_SHA1))
+	buf.Write(bytes.Repeat([]byte{fill}, SHA1_SIZE))
+	binary.Write(&buf, binary.LittleEndian, uint16(TPM_API_ALG_ID_SHA256))
+	buf.Write(bytes.Repeat([]byte{fill}, SHA256_SIZE))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(eventData)))
+	buf.Write(eventData)
+	return buf.Bytes()
+}
+
+func uefiVariableData(name string) []byte {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 16))
+	binary.Write(&buf, binary.LittleEndian, uint64(len(utf16.Encode([]rune(name)))))
+	binary.Write(&buf, binary.LittleEndian, uint64(1))
+	buf.Write(utf16Bytes(name))
+	buf.WriteByte(1)
+	return buf.Bytes()
+}
+
+func uefiImageLoadEvent(filePath string) []byte {
+	var devicePath bytes.Buffer
+	filePathBytes := append(utf16Bytes(filePath), 0, 0)
+	devicePath.Write([]byte{uefiDevicePathTypeMedia, uefiDevicePathSubTypeFilePath})
+	binary.Write(&devicePath, binary.LittleEndian, uint16(4+len(filePathBytes)))
+	devicePath.Write(filePathBytes)
+	devicePath.Write([]byte{uefiDevicePathTypeEnd, 0xff, 4, 0})
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 24))
+	binary.Write(&buf, binary.LittleEndian, uint64(devicePath.Len()))
+	buf.Write(devicePath.Bytes())
+	return buf.Bytes()
+}
+
+func sampleTcg2EventLog() []byte {
+	var eventLog bytes.Buffer
+	eventLog.Write(specIdEvent())
+	eventLog.Write(tcgPcrEvent2(0, EV_NO_ACTION, 0, append([]byte(tcg2StartupLocalitySignature), 3)))
+	eventLog.Write(tcgPcrEvent2(0, EV_S_CRTM_CONTENTS, 1, []byte("Boot Guard Measured S-CRTM\x00")))
+	eventLog.Write(tcgPcrEvent2(7, EV_EFI_VARIABLE_DRIVER_CONFIG, 2, uefiVariableData("KEK")))
+	eventLog.Write(tcgPcrEvent2(4, EV_EFI_BOOT_SERVICES_APPLICATION, 3, uefiImageLoadEvent("\\EFI\\redhat\\shimx64.efi")))
+	eventLog.Write(tcgPcrEvent2(8, EV_IPL, 4, []byte("kernel_cmdline: /vmlinuz-5.8.0 root=/dev/sda1\x00")))
+	eventLog.Write(tcgPcrEvent2(17, 0x40c, 5, []byte{}))
+	eventLog.Write(tcgPcrEvent2(1, EV_NO_ACTION, 0, []byte("vendor data")))
+	return eventLog.Bytes()
+}
+
+func TestParseTcg2EventLog(t *testing.T) {
+	pcrEventLogMap, err := ParseTcg2EventLog(sampleTcg2EventLog())
+	assert.NoError(t, err)
+	assert.Len(t, pcrEventLogMap.Sha1EventLogs, 5)
+	assert.Len(t, pcrEventLogMap.Sha256EventLogs, 5)
+	assert.Empty(t, pcrEventLogMap.Sha384EventLogs)
+
+	pcr0 := pcrEventLogMap.Sha256EventLogs[0]
+	assert.Equal(t, 0, pcr0.Pcr.Index)
+	assert.Equal(t, SHA256, pcr0.Pcr.Bank)
+	assert.Len(t, pcr0.TpmEvent, 2)
+	assert.Equal(t, "0x3", pcr0.TpmEvent[0].TypeID)
+	assert.Equal(t, "EV_NO_ACTION", pcr0.TpmEvent[0].TypeName)
+	assert.Equal(t, []string{"StartupLocality3"}, pcr0.TpmEvent[0].Tags)
+	assert.Equal(t, []string{"Boot Guard Measured S-CRTM"}, pcr0.TpmEvent[1].Tags)
+	assert.Equal(t, hex.EncodeToString(bytes.Repeat([]byte{1}, SHA256_SIZE)), pcr0.TpmEvent[1].Measurement)
+
+	pcr7 := pcrEventLogMap.Sha1EventLogs[1]
+	assert.Equal(t, 7, pcr7.Pcr.Index)
+	assert.Equal(t, "0x80000001", pcr7.TpmEvent[0].TypeID)
+	assert.Equal(t, "EV_EFI_VARIABLE_DRIVER_CONFIG", pcr7.TpmEvent[0].TypeName)
+	assert.Equal(t, []string{"KEK", "kek"}, pcr7.TpmEvent[0].Tags)
+	assert.Equal(t, hex.EncodeToString(bytes.Repeat([]byte{2}, SHA1_SIZE)), pcr7.TpmEvent[0].Measurement)
+
+	pcr4 := pcrEventLogMap.Sha256EventLogs[2]
+	assert.Equal(t, 4, pcr4.Pcr.Index)
+	assert.Equal(t, []string{"shimx64.efi", "shim"}, pcr4.TpmEvent[0].Tags)
+
+	pcr8 := pcrEventLogMap.Sha256EventLogs[3]
+	assert.Equal(t, 8, pcr8.Pcr.Index)
+	assert.Equal(t, []string{"kernel_cmdline: /vmlinuz-5.8.0 root=/dev/sda1", "vmlinuz-5.8.0", "vmlinuz"}, pcr8.TpmEvent[0].Tags)
+
+	pcr17 := pcrEventLogMap.Sha256EventLogs[4]
+	assert.Equal(t, 17, pcr17.Pcr.Index)
+	assert.Equal(t, "LCP_CONTROL_HASH", pcr17.TpmEvent[0].TypeName)
+	assert.Equal(t, []string{"LCP_CONTROL_HASH"}, pcr17.TpmEvent[0].Tags)
+}
+
+func TestParseTcg2EventLogTruncated(t *testing.T) {
+	eventLog := sampleTcg2EventLog()
+	_, err := ParseTcg2EventLog(eventLog[:len(eventLog)-3])
+	assert.Error(t, err)
+
+	_, err = ParseTcg2EventLog([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestParseLegacyEventLog(t *testing.T) {
+	var eventLog bytes.Buffer
+	binary.Write(&eventLog, binary.LittleEndian, uint32(0))
+	binary.Write(&eventLog, binary.LittleEndian, uint32(EV_S_CRTM_CONTENTS))
+	eventLog.Write(bytes.Repeat([]byte{1}, SHA1_SIZE))
+	binary.Write(&eventLog, binary.LittleEndian, uint32(4))
+	eventLog.WriteString("CRTM")
+
+	pcrEventLogMap, err := ParseTcg2EventLog(eventLog.Bytes())
+	assert.NoError(t, err)
+	assert.Len(t, pcrEventLogMap.Sha1EventLogs, 1)
+	assert.Empty(t, pcrEventLogMap.Sha256EventLogs)
+	assert.Equal(t, []string{"CRTM"}, pcrEventLogMap.Sha1EventLogs[0].TpmEvent[0].Tags)
+}
+
+func TestGetPcrEventLogBinary(t *testing.T) {
+	pcrEventLogMap, err := getPcrEventLog(base64.StdEncoding.EncodeToString(sampleTcg2EventLog()))
+	assert.NoError(t, err)
+	assert.Len(t, pcrEventLogMap.Sha256EventLogs, 5)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package util
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
+	"github.com/pkg/errors"
+)
+
+// GetHostManifestFromTpmQuote verifies the TPM quote response against the nonce it was requested with and returns a
+// host manifest with the PCRs, AIK certificate, asset tag, TCB measurements and IMA log of the quote. The host info and
+// binding key certificate are not part of the quote and are left for the caller to fill in.
+func GetHostManifestFromTpmQuote(nonce string, tpmQuoteResponse taModel.TpmQuoteResponse) (hvs.HostManifest, error) {
+	log.Trace("util/tpm_quote:GetHostManifestFromTpmQuote() Entering")
+	defer log.Trace("util/tpm_quote:GetHostManifestFromTpmQuote() Leaving")
+
+	var hostManifest hvs.HostManifest
+
+	nonceInBytes, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return hvs.HostManifest{}, errors.Wrap(err, "Base64 decode of TPM nonce failed")
+	}
+
+	verificationNonce, err := GetVerificationNonce(nonceInBytes, tpmQuoteResponse)
+	if err != nil {
+		return hvs.HostManifest{}, err
+	}
+	secLog.Debug("util/tpm_quote:GetHostManifestFromTpmQuote() Updated Verification nonce is : ", verificationNonce)
+
+	aikCertInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Aik)
+	if err != nil {
+		return hvs.HostManifest{}, errors.Wrap(err, "Error decoding AIK certificate to bytes")
+	}
+
+	//Convert base64 encoded AIK to Pem format
+	aikPem, _ := pem.Decode(aikCertInBytes)
+	if aikPem == nil {
+		return hvs.HostManifest{}, errors.New("Error decoding AIK certificate from PEM")
+	}
+	aikCertificate, err := x509.ParseCertificate(aikPem.Bytes)
+	if err != nil {
+		return hvs.HostManifest{}, errors.Wrap(err, "Error parsing AIK certicate")
+	}
+
+	tpmQuoteInBytes, err := base64.StdEncoding.DecodeString(tpmQuoteResponse.Quote)
+	if err != nil {
+		return hvs.HostManifest{}, errors.Wrap(err, "Error converting tpm quote to bytes")
+	}
+
+	verificationNonceInBytes, err := base64.StdEncoding.DecodeString(verificationNonce)
+	if err != nil {
+		return hvs.HostManifest{}, errors.Wrap(err, "Error converting nonce to bytes")
+	}
+	log.Info("util/tpm_quote:GetHostManifestFromTpmQuote() Verifying quote and retrieving PCR manifest from TPM quote " +
+		"response ...")
+	pcrManifest, pcrsDigest, err := VerifyQuoteAndGetPCRManifest(tpmQuoteResponse.EventLog, verificationNonceInBytes,
+		tpmQuoteInBytes, aikCertificate)
+	if err != nil {
+		return hvs.HostManifest{}, errors.Wrap(err, "Error verifying TPM Quote")
+	}
+	log.Info("util/tpm_quote:GetHostManifestFromTpmQuote() Successfully retrieved PCR manifest from quote")
+
+	hostManifest.PcrManifest = pcrManifest
+	hostManifest.AIKCertificate = base64.StdEncoding.EncodeToString(aikPem.Bytes)
+	hostManifest.AssetTagDigest = tpmQuoteResponse.AssetTag
+	hostManifest.MeasurementXmls = tpmQuoteResponse.TcbMeasurements.TcbMeasurements
+	hostManifest.QuoteDigest = hex.EncodeToString(pcrsDigest) + hostManifest.AssetTagDigest
+
+	if tpmQuoteResponse.ImaLog != "" {
+		hostManifest.ImaLog, err = hvs.ParseImaLog(tpmQuoteResponse.ImaLog)
+		if err != nil {
+			// the IMA rules report the missing log, the other flavor parts can still be verified
+			log.WithError(err).Warn("util/tpm_quote:GetHostManifestFromTpmQuote() Error parsing IMA log")
+		}
+	}
+
+	return hostManifest, nil
+}
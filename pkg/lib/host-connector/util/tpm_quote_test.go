@@ -0,0 +1,49 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package util
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+func readSampleTpmQuote(t *testing.T) taModel.TpmQuoteResponse {
+	var tpmQuoteResponse taModel.TpmQuoteResponse
+	b, err := ioutil.ReadFile("../test/sample_tpm_quote.xml")
+	assert.NoError(t, err)
+	err = xml.Unmarshal(b, &tpmQuoteResponse)
+	assert.NoError(t, err)
+
+	eventLog, err := ioutil.ReadFile("../test/sample_measure_log.json")
+	assert.NoError(t, err)
+	tpmQuoteResponse.EventLog = string(eventLog)
+	return tpmQuoteResponse
+}
+
+func TestGetHostManifestFromTpmQuote(t *testing.T) {
+	tpmQuoteResponse := readSampleTpmQuote(t)
+
+	hostManifest, err := GetHostManifestFromTpmQuote("ZGVhZGJlZWZkZWFkYmVlZmRlYWRiZWVmZGVhZGJlZWZkZWFkYmVlZiA=", tpmQuoteResponse)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hostManifest.AIKCertificate)
+	assert.NotEmpty(t, hostManifest.QuoteDigest)
+	assert.NotEmpty(t, hostManifest.PcrManifest.Sha256Pcrs)
+}
+
+func TestGetHostManifestFromTpmQuoteInvalid(t *testing.T) {
+	tpmQuoteResponse := readSampleTpmQuote(t)
+
+	_, err := GetHostManifestFromTpmQuote("xxxxxxxxxxxxxxxxxxxxxxxxxxxx", tpmQuoteResponse)
+	assert.Error(t, err)
+
+	tpmQuoteResponse.Aik = "aW52YWxpZA=="
+	_, err = GetHostManifestFromTpmQuote("ZGVhZGJlZWZkZWFkYmVlZmRlYWRiZWVmZGVhZGJlZWZkZWFkYmVlZiA=", tpmQuoteResponse)
+	assert.Error(t, err)
+}
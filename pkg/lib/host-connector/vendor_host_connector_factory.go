@@ -5,10 +5,11 @@
 package host_connector
 
 import (
+	"context"
 	"crypto/x509"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector/types"
 )
 
 type VendorHostConnectorFactory interface {
-	GetHostConnector(vendorConnector types.VendorConnector, aasApiUrl string, trustedCaCerts []x509.Certificate) (HostConnector, error)
+	GetHostConnector(ctx context.Context, vendorConnector types.VendorConnector, aasApiUrl string, trustedCaCerts []x509.Certificate) (HostConnector, error)
 }
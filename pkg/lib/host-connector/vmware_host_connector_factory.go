@@ -5,6 +5,7 @@
 package host_connector
 
 import (
+	"context"
 	"crypto/x509"
 	"github.com/intel-secl/intel-secl/v4/pkg/clients/vmware"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/host-connector/types"
@@ -15,7 +16,7 @@ import (
 type VmwareConnectorFactory struct {
 }
 
-func (vcf *VmwareConnectorFactory) GetHostConnector(vc types.VendorConnector, aasApiUrl string,
+func (vcf *VmwareConnectorFactory) GetHostConnector(ctx context.Context, vc types.VendorConnector, aasApiUrl string,
 	trustedCaCerts []x509.Certificate) (HostConnector, error) {
 	log.Trace("vmware_host_connector_factory:GetHostConnector() Entering")
 	defer log.Trace("vmware_host_connector_factory:GetHostConnector() Leaving")
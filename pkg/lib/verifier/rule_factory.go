@@ -20,6 +20,7 @@ type ruleBuilder interface {
 	GetAssetTagRules() ([]rules.Rule, error)
 	GetAikCertificateTrustedRule(flavormodel.FlavorPartName) ([]rules.Rule, error)
 	GetSoftwareRules() ([]rules.Rule, error)
+	GetImaRules() ([]rules.Rule, error)
 	GetName() string
 }
 
@@ -72,6 +73,8 @@ func (factory *ruleFactory) GetVerificationRules() ([]rules.Rule, string, error)
 		requiredRules, err = ruleBuilder.GetAssetTagRules()
 	case flavormodel.FlavorPartSoftware:
 		requiredRules, err = ruleBuilder.GetSoftwareRules()
+	case flavormodel.FlavorPartIma:
+		requiredRules, err = ruleBuilder.GetImaRules()
 	default:
 		return nil, "", errors.Errorf("Cannot build requiredRules for unknown flavor part %s", flavorPartName)
 
@@ -120,6 +123,15 @@ func (factory *ruleFactory) GetVerificationRules() ([]rules.Rule, string, error)
 		}
 	}
 
+	// Add the custom rules copied to the flavor from the flavor template
+	for _, customRule := range factory.signedFlavor.Flavor.CustomRules {
+		rule, err := rules.NewCustomRule(customRule, flavorPartName)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "Error creating custom rule for flavor '%s'", factory.signedFlavor.Flavor.Meta.ID)
+		}
+		requiredRules = append(requiredRules, rule)
+	}
+
 	// if skip flavor signing verification is enabled, add the FlavorTrusted.
 	if !factory.skipSignedFlavorVerification {
 		var flavorPart flavormodel.FlavorPartName
@@ -128,9 +140,10 @@ func (factory *ruleFactory) GetVerificationRules() ([]rules.Rule, string, error)
 			return nil, "", errors.Wrap(err, "Could not retrieve flavor part name")
 		}
 
-		flavorTrusted, err := rules.NewFlavorTrusted(factory.signedFlavor,
+		flavorTrusted, err := rules.NewFlavorTrustedWithIntermediates(factory.signedFlavor,
 			factory.verifierCertificates.FlavorSigningCertificate,
 			factory.verifierCertificates.FlavorCACertificates,
+			factory.verifierCertificates.FlavorIntermediateCertificates,
 			flavorPart)
 
 		if err != nil {
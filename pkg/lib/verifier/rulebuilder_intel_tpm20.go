@@ -142,3 +142,37 @@ func (builder *ruleBuilderIntelTpm20) GetSoftwareRules() ([]rules.Rule, error) {
 
 	return results, nil
 }
+
+// ImaLogIntegrity
+// ImaMeasurementsAllowed
+// FlavorTrusted (added in verifierimpl)
+func (builder *ruleBuilderIntelTpm20) GetImaRules() ([]rules.Rule, error) {
+
+	var results []rules.Rule
+
+	//
+	// Add 'ImaLogIntegrity' rule...
+	//
+	imaLogIntegrityRule, err := rules.NewImaLogIntegrity(hvs.FlavorPartIma)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error in getting ImaLogIntegrity rule")
+	}
+
+	results = append(results, imaLogIntegrityRule)
+
+	//
+	// Add 'ImaMeasurementsAllowed' rule...
+	//
+	if builder.signedFlavor.Flavor.Ima == nil {
+		return nil, errors.New("'Ima' was not present in the flavor")
+	}
+
+	imaMeasurementsAllowedRule, err := rules.NewImaMeasurementsAllowed(builder.signedFlavor.Flavor.Meta.ID, builder.signedFlavor.Flavor.Ima, hvs.FlavorPartIma)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error in getting ImaMeasurementsAllowed rule")
+	}
+
+	results = append(results, imaMeasurementsAllowedRule)
+
+	return results, nil
+}
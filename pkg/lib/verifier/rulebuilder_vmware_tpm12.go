@@ -64,3 +64,7 @@ func (builder *ruleBuilderVMWare12) GetAikCertificateTrustedRule(fp hvs.FlavorPa
 func (builder *ruleBuilderVMWare12) GetSoftwareRules() ([]rules.Rule, error) {
 	return nil, nil
 }
+
+func (builder *ruleBuilderVMWare12) GetImaRules() ([]rules.Rule, error) {
+	return nil, nil
+}
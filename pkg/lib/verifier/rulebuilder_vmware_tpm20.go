@@ -76,3 +76,7 @@ func (builder *ruleBuilderVMWare20) GetAikCertificateTrustedRule(fp hvs.FlavorPa
 func (builder *ruleBuilderVMWare20) GetSoftwareRules() ([]rules.Rule, error) {
 	return nil, nil
 }
+
+func (builder *ruleBuilderVMWare20) GetImaRules() ([]rules.Rule, error) {
+	return nil, nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that evaluates a 'jsonquery' statement or a CEL expression defined in the flavor against
+// the host manifest.
+//
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/antchfx/jsonquery"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// CustomRuleHostManifestVariable is the name under which the host manifest is made available to CEL expressions
+const CustomRuleHostManifestVariable = "host_manifest"
+
+//NewCustomRule validates and compiles the expression of a custom rule defined in a flavor
+func NewCustomRule(customRule hvs.CustomRule, marker hvs.FlavorPartName) (Rule, error) {
+	if customRule.Name == "" {
+		return nil, errors.New("The custom rule name cannot be empty")
+	}
+
+	rule := customRuleMatches{
+		customRule: customRule,
+		marker:     marker,
+	}
+
+	switch {
+	case customRule.JsonQuery != "" && customRule.Cel != "":
+		return nil, errors.Errorf("Custom rule '%s' cannot have both a jsonquery and a CEL expression", customRule.Name)
+	case customRule.JsonQuery != "":
+		// validate the syntax of the statement against an empty document
+		emptyDoc, err := jsonquery.Parse(strings.NewReader("{}"))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error parsing empty json document")
+		}
+		if _, err = jsonquery.Query(emptyDoc, customRule.JsonQuery); err != nil {
+			return nil, errors.Wrapf(err, "Invalid jsonquery in custom rule '%s'", customRule.Name)
+		}
+	case customRule.Cel != "":
+		env, err := cel.NewEnv(cel.Declarations(decls.NewVar(CustomRuleHostManifestVariable, decls.Dyn)))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating CEL environment")
+		}
+		ast, issues := env.Compile(customRule.Cel)
+		if issues != nil && issues.Err() != nil {
+			return nil, errors.Wrapf(issues.Err(), "Invalid CEL expression in custom rule '%s'", customRule.Name)
+		}
+		rule.program, err = env.Program(ast)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating CEL program for custom rule '%s'", customRule.Name)
+		}
+	default:
+		return nil, errors.Errorf("Custom rule '%s' requires a jsonquery or a CEL expression", customRule.Name)
+	}
+
+	return &rule, nil
+}
+
+type customRuleMatches struct {
+	customRule hvs.CustomRule
+	marker     hvs.FlavorPartName
+	program    cel.Program
+}
+
+// - If the expression cannot be evaluated against the manifest, create a CustomRuleEvaluationFailed fault.
+// - If the jsonquery statement does not select a node or the CEL expression does not evaluate to true,
+//   create a CustomRuleMismatch fault.
+func (rule *customRuleMatches) Apply(hostManifest *hvs.HostManifest) (*hvs.RuleResult, error) {
+	result := hvs.RuleResult{}
+	result.Trusted = true
+	result.Rule.Name = constants.RuleCustomRuleMatches
+	result.Rule.CustomRule = &rule.customRule
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+
+	if hostManifest == nil {
+		return nil, errors.New("The host manifest cannot be nil")
+	}
+
+	hostManifestBytes, err := json.Marshal(hostManifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling host manifest")
+	}
+
+	if rule.program == nil {
+		hostManifestJSON, err := jsonquery.Parse(strings.NewReader(string(hostManifestBytes)))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error parsing host manifest")
+		}
+		node, err := jsonquery.Query(hostManifestJSON, rule.customRule.JsonQuery)
+		if err != nil {
+			result.Faults = append(result.Faults, newCustomRuleEvaluationFailedFault(rule.customRule, err))
+		} else if node == nil {
+			result.Faults = append(result.Faults, newCustomRuleMismatchFault(rule.customRule, nil))
+		}
+	} else {
+		var hostManifestMap map[string]interface{}
+		if err = json.Unmarshal(hostManifestBytes, &hostManifestMap); err != nil {
+			return nil, errors.Wrap(err, "Error unmarshalling host manifest")
+		}
+		out, _, err := rule.program.Eval(map[string]interface{}{CustomRuleHostManifestVariable: hostManifestMap})
+		if err != nil {
+			result.Faults = append(result.Faults, newCustomRuleEvaluationFailedFault(rule.customRule, err))
+		} else if matches, ok := out.Value().(bool); !ok {
+			result.Faults = append(result.Faults, newCustomRuleEvaluationFailedFault(rule.customRule,
+				errors.Errorf("expression evaluated to %v instead of a boolean", out.Value())))
+		} else if !matches {
+			actualValue := "false"
+			result.Faults = append(result.Faults, newCustomRuleMismatchFault(rule.customRule, &actualValue))
+		}
+	}
+
+	return &result, nil
+}
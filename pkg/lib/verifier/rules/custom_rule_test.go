@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"testing"
+
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
+	"github.com/stretchr/testify/assert"
+)
+
+func getCustomRuleHostManifest() *hvs.HostManifest {
+	hostManifest := hvs.HostManifest{}
+	hostManifest.HostInfo.BiosVersion = "SE5C620.86B.00.01.6016.032720190737"
+	hostManifest.HostInfo.HardwareFeatures.TPM = &taModel.TPM{}
+	hostManifest.HostInfo.HardwareFeatures.TPM.Enabled = true
+	hostManifest.HostInfo.HardwareFeatures.TPM.Meta.TPMVersion = "2.0"
+	hostManifest.HostInfo.HardwareFeatures.UEFI = &taModel.UEFI{}
+	hostManifest.HostInfo.HardwareFeatures.UEFI.Meta.SecureBootEnabled = true
+	return &hostManifest
+}
+
+func TestCustomRuleJsonQueryNoFault(t *testing.T) {
+	rule, err := NewCustomRule(hvs.CustomRule{
+		Name:      "tpm_2_0",
+		JsonQuery: "//host_info/hardware_features/TPM/meta/tpm_version[text()='2.0']",
+	}, hvs.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(getCustomRuleHostManifest())
+	assert.NoError(t, err)
+	assert.Equal(t, constants.RuleCustomRuleMatches, result.Rule.Name)
+	assert.Equal(t, "tpm_2_0", result.Rule.CustomRule.Name)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.True(t, result.Trusted)
+}
+
+func TestCustomRuleJsonQueryMismatchFault(t *testing.T) {
+	rule, err := NewCustomRule(hvs.CustomRule{
+		Name:      "tpm_1_2",
+		JsonQuery: "//host_info/hardware_features/TPM/meta/tpm_version[text()='1.2']",
+	}, hvs.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(getCustomRuleHostManifest())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultCustomRuleMismatch, result.Faults[0].Name)
+}
+
+func TestCustomRuleCelNoFault(t *testing.T) {
+	rule, err := NewCustomRule(hvs.CustomRule{
+		Name: "secure_boot_and_bios",
+		Cel: "host_manifest.host_info.hardware_features.UEFI.meta.secure_boot_enabled && " +
+			"host_manifest.host_info.bios_version.startsWith('SE5C620.86B.00.01')",
+	}, hvs.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(getCustomRuleHostManifest())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Faults))
+	assert.True(t, result.Trusted)
+}
+
+func TestCustomRuleCelMismatchFault(t *testing.T) {
+	rule, err := NewCustomRule(hvs.CustomRule{
+		Name: "bios_version_minimum",
+		Cel:  "host_manifest.host_info.bios_version >= 'SE5C620.86B.02'",
+	}, hvs.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(getCustomRuleHostManifest())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultCustomRuleMismatch, result.Faults[0].Name)
+}
+
+func TestCustomRuleCelEvaluationFailedFault(t *testing.T) {
+	rule, err := NewCustomRule(hvs.CustomRule{
+		Name: "missing_feature",
+		Cel:  "host_manifest.host_info.hardware_features.CBNT.enabled == 'true'",
+	}, hvs.FlavorPartPlatform)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(getCustomRuleHostManifest())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Faults))
+	assert.Equal(t, constants.FaultCustomRuleEvaluationFailed, result.Faults[0].Name)
+}
+
+func TestCustomRuleInvalidExpression(t *testing.T) {
+	_, err := NewCustomRule(hvs.CustomRule{Name: "empty"}, hvs.FlavorPartPlatform)
+	assert.Error(t, err)
+
+	_, err = NewCustomRule(hvs.CustomRule{Name: "both", JsonQuery: "//host_info", Cel: "true"}, hvs.FlavorPartPlatform)
+	assert.Error(t, err)
+
+	_, err = NewCustomRule(hvs.CustomRule{Name: "cel_syntax", Cel: "host_manifest.host_info ==="}, hvs.FlavorPartPlatform)
+	assert.Error(t, err)
+
+	_, err = NewCustomRule(hvs.CustomRule{Name: "jsonquery_syntax", JsonQuery: "//host_info["}, hvs.FlavorPartPlatform)
+	assert.Error(t, err)
+}
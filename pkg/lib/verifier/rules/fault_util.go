@@ -105,3 +105,67 @@ func newPcrManifestMissingFault() hvs.Fault {
 		Description: "Host report does not include a PCR Manifest",
 	}
 }
+
+func newCustomRuleMismatchFault(customRule hvs.CustomRule, actualValue *string) hvs.Fault {
+	expectedValue := customRule.JsonQuery
+	if customRule.Cel != "" {
+		expectedValue = customRule.Cel
+	}
+	return hvs.Fault{
+		Name:          faultsConst.FaultCustomRuleMismatch,
+		Description:   fmt.Sprintf("Host manifest does not satisfy custom rule '%s'", customRule.Name),
+		ExpectedValue: &expectedValue,
+		ActualValue:   actualValue,
+	}
+}
+
+func newCustomRuleEvaluationFailedFault(customRule hvs.CustomRule, err error) hvs.Fault {
+	return hvs.Fault{
+		Name:        faultsConst.FaultCustomRuleEvaluationFailed,
+		Description: fmt.Sprintf("Custom rule '%s' could not be evaluated against the host manifest: %s", customRule.Name, err.Error()),
+	}
+}
+
+func newImaLogMissingFault() hvs.Fault {
+	return hvs.Fault{
+		Name:        faultsConst.FaultImaLogMissing,
+		Description: "Host report does not include an IMA log",
+	}
+}
+
+func newImaLogInvalidFault(pcrIndex hvs.PcrIndex, pcrBank hvs.SHAAlgorithm, expectedPcrValue string, calculatedValue string) hvs.Fault {
+	return hvs.Fault{
+		Name:             faultsConst.FaultImaLogInvalid,
+		Description:      fmt.Sprintf("Replay of the IMA log with value '%s' does not match PCR %d of %s with value '%s'", calculatedValue, pcrIndex, pcrBank, expectedPcrValue),
+		PcrIndex:         &pcrIndex,
+		PcrBank:          &pcrBank,
+		ExpectedPcrValue: &expectedPcrValue,
+		CalculatedValue:  &calculatedValue,
+	}
+}
+
+func newImaLogPcrInvalidFault(pcrIndex hvs.PcrIndex) hvs.Fault {
+	return hvs.Fault{
+		Name:        faultsConst.FaultImaLogInvalid,
+		Description: fmt.Sprintf("The IMA log extends PCR %d instead of PCR %d", pcrIndex, hvs.ImaPcrIndex),
+		PcrIndex:    &pcrIndex,
+	}
+}
+
+func newImaMeasurementUnknownFault(measurement hvs.ImaMeasurement) hvs.Fault {
+	return hvs.Fault{
+		Name:           faultsConst.FaultImaMeasurementUnknown,
+		Description:    fmt.Sprintf("IMA measurement '%s' of file '%s' is not in the allowlist", measurement.FileHash, measurement.FileName),
+		ActualValue:    &measurement.FileHash,
+		ImaMeasurement: &measurement,
+	}
+}
+
+func newImaMeasurementDeniedFault(measurement hvs.ImaMeasurement) hvs.Fault {
+	return hvs.Fault{
+		Name:           faultsConst.FaultImaMeasurementDenied,
+		Description:    fmt.Sprintf("IMA measurement '%s' of file '%s' is in the denylist", measurement.FileHash, measurement.FileName),
+		ActualValue:    &measurement.FileHash,
+		ImaMeasurement: &measurement,
+	}
+}
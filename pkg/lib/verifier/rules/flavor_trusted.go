@@ -14,12 +14,20 @@ import (
 )
 
 func NewFlavorTrusted(signedFlavor *hvs.SignedFlavor, flavorSigningCertificate *x509.Certificate, flavorCaCertificates *x509.CertPool, marker hvs.FlavorPartName) (Rule, error) {
+	return NewFlavorTrustedWithIntermediates(signedFlavor, flavorSigningCertificate, flavorCaCertificates, nil, marker)
+}
+
+// NewFlavorTrustedWithIntermediates creates a flavor trusted rule that also uses the
+// intermediate certificates to build the chain of the flavor signing certificate to
+// the flavor CAs.  The intermediates are never trusted as roots.
+func NewFlavorTrustedWithIntermediates(signedFlavor *hvs.SignedFlavor, flavorSigningCertificate *x509.Certificate, flavorCaCertificates *x509.CertPool, intermediateCertificates *x509.CertPool, marker hvs.FlavorPartName) (Rule, error) {
 
 	return &flavorTrusted{
 		signedFlavor:             signedFlavor,
 		flavorId:                 signedFlavor.Flavor.Meta.ID,
 		flavorSigningCertificate: flavorSigningCertificate,
 		flavorCaCertificates:     flavorCaCertificates,
+		intermediateCertificates: intermediateCertificates,
 		marker:                   marker,
 	}, nil
 }
@@ -29,6 +37,7 @@ type flavorTrusted struct {
 	flavorId                 uuid.UUID
 	flavorSigningCertificate *x509.Certificate
 	flavorCaCertificates     *x509.CertPool
+	intermediateCertificates *x509.CertPool
 	marker                   hvs.FlavorPartName
 }
 
@@ -60,7 +69,8 @@ func (rule *flavorTrusted) Apply(hostManifest *hvs.HostManifest) (*hvs.RuleResul
 
 		// verify the cert and ca...
 		opts := x509.VerifyOptions{
-			Roots: rule.flavorCaCertificates,
+			Roots:         rule.flavorCaCertificates,
+			Intermediates: rule.intermediateCertificates,
 		}
 
 		_, err := rule.flavorSigningCertificate.Verify(opts)
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that replays the IMA runtime measurement list against PCR 10.
+//
+
+import (
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+func NewImaLogIntegrity(marker hvs.FlavorPartName) (Rule, error) {
+	rule := imaLogIntegrity{
+		marker: marker,
+	}
+
+	return &rule, nil
+}
+
+type imaLogIntegrity struct {
+	marker hvs.FlavorPartName
+}
+
+// - If the IMA log is missing, create an ImaLogMissing fault.
+// - If the IMA log does not extend PCR 10, create an ImaLogInvalid fault.
+// - If PCR 10 of the bank of the IMA log is not in the manifest, create a PcrValueMissing fault.
+// - If the replay of the IMA log does not match the PCR value, create an ImaLogInvalid fault.
+func (rule *imaLogIntegrity) Apply(hostManifest *hvs.HostManifest) (*hvs.RuleResult, error) {
+	result := hvs.RuleResult{}
+	result.Trusted = true
+	result.Rule.Name = constants.RuleImaLogIntegrity
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+
+	if hostManifest == nil {
+		return nil, errors.New("The host manifest cannot be nil")
+	}
+
+	if hostManifest.ImaLog == nil || len(hostManifest.ImaLog.Measurements) == 0 {
+		result.Faults = append(result.Faults, newImaLogMissingFault())
+		return &result, nil
+	}
+
+	// the log is always replayed against the PCR extended by IMA, a log extending another PCR could be forged
+	// in a PCR that software can reset or extend at will
+	pcrIndex := hvs.ImaPcrIndex
+	pcrBank := hvs.SHAAlgorithm(hostManifest.ImaLog.Pcr.Bank)
+	result.Rule.PCR = &hvs.Pcr{Index: int(pcrIndex), Bank: string(pcrBank)}
+	if hostManifest.ImaLog.Pcr.Index != int(pcrIndex) {
+		result.Faults = append(result.Faults, newImaLogPcrInvalidFault(hvs.PcrIndex(hostManifest.ImaLog.Pcr.Index)))
+		return &result, nil
+	}
+
+	actualPcr, err := hostManifest.PcrManifest.GetPcrValue(pcrBank, pcrIndex)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting PCR %d of %s from the host manifest", pcrIndex, pcrBank)
+	}
+	if actualPcr == nil {
+		result.Faults = append(result.Faults, newPcrValueMissingFault(pcrBank, pcrIndex))
+		return &result, nil
+	}
+
+	replayedMeasurements, calculatedValue, err := hostManifest.ImaLog.Replay(actualPcr.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error replaying the IMA log")
+	}
+	if replayedMeasurements == 0 {
+		result.Faults = append(result.Faults, newImaLogInvalidFault(pcrIndex, pcrBank, actualPcr.Value, calculatedValue))
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+//
+// Rule that checks the file measurements of the IMA log against the allowlist and denylist of an IMA flavor.
+//
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+func NewImaMeasurementsAllowed(flavorID uuid.UUID, ima *hvs.Ima, marker hvs.FlavorPartName) (Rule, error) {
+	if ima == nil {
+		return nil, errors.New("The IMA section of the flavor cannot be nil")
+	}
+
+	rule := imaMeasurementsAllowed{
+		flavorID:     flavorID,
+		marker:       marker,
+		allowlist:    make(map[string]map[string]bool),
+		deniedHashes: make(map[string]map[string]bool),
+		deniedPaths:  make(map[string]bool),
+	}
+
+	for _, allowed := range ima.Allowlist {
+		if allowed.FileHash == "" {
+			return nil, errors.Errorf("The allowlist entry for file '%s' requires a file hash", allowed.FilePath)
+		}
+		addImaFileMeasurement(rule.allowlist, allowed)
+	}
+	for _, denied := range ima.Denylist {
+		if denied.FileHash == "" && denied.FilePath == "" {
+			return nil, errors.New("A denylist entry requires a file hash or a file path")
+		}
+		if denied.FileHash == "" {
+			rule.deniedPaths[denied.FilePath] = true
+		} else {
+			addImaFileMeasurement(rule.deniedHashes, denied)
+		}
+	}
+
+	return &rule, nil
+}
+
+// addImaFileMeasurement adds the file measurement to a map of file hashes to the paths the hash is restricted to, an
+// empty path matches any file
+func addImaFileMeasurement(fileMeasurements map[string]map[string]bool, fileMeasurement hvs.ImaFileMeasurement) {
+	fileHash := strings.ToLower(fileMeasurement.FileHash)
+	if _, ok := fileMeasurements[fileHash]; !ok {
+		fileMeasurements[fileHash] = make(map[string]bool)
+	}
+	fileMeasurements[fileHash][fileMeasurement.FilePath] = true
+}
+
+func matchesImaFileMeasurement(fileMeasurements map[string]map[string]bool, measurement hvs.ImaMeasurement) bool {
+	paths, ok := fileMeasurements[strings.ToLower(measurement.FileHash)]
+	return ok && (paths[""] || paths[measurement.FileName])
+}
+
+type imaMeasurementsAllowed struct {
+	flavorID     uuid.UUID
+	marker       hvs.FlavorPartName
+	allowlist    map[string]map[string]bool
+	deniedHashes map[string]map[string]bool
+	deniedPaths  map[string]bool
+}
+
+// - If the IMA log is missing, create an ImaLogMissing fault.
+// - For each file measurement in the denylist, create an ImaMeasurementDenied fault.
+// - If the flavor has an allowlist, create an ImaMeasurementUnknown fault for each file measurement
+//   that is not in the allowlist.
+func (rule *imaMeasurementsAllowed) Apply(hostManifest *hvs.HostManifest) (*hvs.RuleResult, error) {
+	result := hvs.RuleResult{}
+	result.Trusted = true
+	result.Rule.Name = constants.RuleImaMeasurementsAllowed
+	result.Rule.FlavorID = &rule.flavorID
+	result.Rule.Markers = append(result.Rule.Markers, rule.marker)
+
+	if hostManifest == nil {
+		return nil, errors.New("The host manifest cannot be nil")
+	}
+
+	if hostManifest.ImaLog == nil || len(hostManifest.ImaLog.Measurements) == 0 {
+		result.Faults = append(result.Faults, newImaLogMissingFault())
+		return &result, nil
+	}
+
+	for _, measurement := range hostManifest.ImaLog.Measurements {
+		// the boot aggregate is verified by the ImaLogIntegrity rule and the boot PCR rules
+		if measurement.FileName == hvs.ImaBootAggregate {
+			continue
+		}
+		if rule.deniedPaths[measurement.FileName] || matchesImaFileMeasurement(rule.deniedHashes, measurement) {
+			result.Faults = append(result.Faults, newImaMeasurementDeniedFault(measurement))
+		} else if len(rule.allowlist) > 0 && !matchesImaFileMeasurement(rule.allowlist, measurement) {
+			result.Faults = append(result.Faults, newImaMeasurementUnknownFault(measurement))
+		}
+	}
+
+	return &result, nil
+}
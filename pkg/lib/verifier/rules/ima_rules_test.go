@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package rules
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/intel-secl/intel-secl/v4/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testImaLog = `10 91f34b5c671d73504b274a919661cf80dab1e127 ima-ng sha1:1801e1be3e65ef1eaa5c16617bec8f1274eaf6b3 boot_aggregate
+10 8b11bd7aa4efd3e9e8c1c3b1c5cb3f1d3e4bb0e5 ima-ng sha256:2c7dd3a1b0df0b1e0e09c6d1d2cd4e7c6a4b05a3e93e7e1e0ab0a21d9bc0a1f2 /usr/bin/bash
+10 0000000000000000000000000000000000000000 ima-ng sha256:0000000000000000000000000000000000000000000000000000000000000000 /usr/lib64/libc.so.6
+10 3e4b5a31e8c6b0cbe8d1b0d7a7e0fb4dd2c7b4a1 ima-ng sha256:9f3c1f4e1e2a6d7b0c5a4e3d2c1b0a9f8e7d6c5b4a39281706f5e4d3c2b1a098 /usr/bin/nc`
+
+// replayImaLog calculates the SHA1 PCR value of the first measurements of the IMA log
+func replayImaLog(t *testing.T, imaLog *hvs.ImaLog, measurements int) string {
+	pcr := make([]byte, sha1.Size)
+	for _, measurement := range imaLog.Measurements[:measurements] {
+		templateHash, err := hex.DecodeString(measurement.TemplateHash)
+		assert.NoError(t, err)
+		if measurement.TemplateHash == strings.Repeat("0", sha1.Size*2) {
+			for i := range templateHash {
+				templateHash[i] = 0xff
+			}
+		}
+		hash := sha1.New()
+		hash.Write(pcr)
+		hash.Write(templateHash)
+		pcr = hash.Sum(nil)
+	}
+	return hex.EncodeToString(pcr)
+}
+
+func newImaHostManifest(t *testing.T, replayedMeasurements int) *hvs.HostManifest {
+	imaLog, err := hvs.ParseImaLog(testImaLog)
+	assert.NoError(t, err)
+
+	hostManifest := hvs.HostManifest{ImaLog: imaLog}
+	hostManifest.PcrManifest.Sha1Pcrs = append(hostManifest.PcrManifest.Sha1Pcrs, hvs.HostManifestPcrs{
+		Index:   hvs.PCR10,
+		Value:   replayImaLog(t, imaLog, replayedMeasurements),
+		PcrBank: hvs.SHA1,
+	})
+	return &hostManifest
+}
+
+func TestImaLogIntegrityNoFault(t *testing.T) {
+	rule, err := NewImaLogIntegrity(hvs.FlavorPartIma)
+	assert.NoError(t, err)
+
+	// the full log and a log that was read after the quote both match PCR 10
+	for _, replayedMeasurements := range []int{4, 2} {
+		result, err := rule.Apply(newImaHostManifest(t, replayedMeasurements))
+		assert.NoError(t, err)
+		assert.True(t, result.Trusted)
+		assert.Len(t, result.Faults, 0)
+	}
+}
+
+func TestImaLogIntegrityInvalidFault(t *testing.T) {
+	rule, err := NewImaLogIntegrity(hvs.FlavorPartIma)
+	assert.NoError(t, err)
+
+	hostManifest := newImaHostManifest(t, 4)
+	hostManifest.ImaLog.Measurements[1].TemplateHash = "8b11bd7aa4efd3e9e8c1c3b1c5cb3f1d3e4bb0e6"
+
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Len(t, result.Faults, 1)
+	assert.Equal(t, constants.FaultImaLogInvalid, result.Faults[0].Name)
+}
+
+func TestImaLogIntegrityWrongPcrFault(t *testing.T) {
+	rule, err := NewImaLogIntegrity(hvs.FlavorPartIma)
+	assert.NoError(t, err)
+
+	// a forged log extended into PCR 16 matches that PCR but PCR 10 holds the real measurements
+	imaLog, err := hvs.ParseImaLog(strings.Replace(testImaLog, "10 ", "16 ", -1))
+	assert.NoError(t, err)
+	hostManifest := hvs.HostManifest{ImaLog: imaLog}
+	hostManifest.PcrManifest.Sha1Pcrs = []hvs.HostManifestPcrs{
+		{Index: hvs.PCR10, Value: strings.Repeat("a", sha1.Size*2), PcrBank: hvs.SHA1},
+		{Index: hvs.PCR16, Value: replayImaLog(t, imaLog, 4), PcrBank: hvs.SHA1},
+	}
+
+	result, err := rule.Apply(&hostManifest)
+	assert.NoError(t, err)
+	assert.Len(t, result.Faults, 1)
+	assert.Equal(t, constants.FaultImaLogInvalid, result.Faults[0].Name)
+	assert.Equal(t, int(hvs.ImaPcrIndex), result.Rule.PCR.Index)
+}
+
+func TestImaLogIntegrityMissingFaults(t *testing.T) {
+	rule, err := NewImaLogIntegrity(hvs.FlavorPartIma)
+	assert.NoError(t, err)
+
+	result, err := rule.Apply(&hvs.HostManifest{})
+	assert.NoError(t, err)
+	assert.Len(t, result.Faults, 1)
+	assert.Equal(t, constants.FaultImaLogMissing, result.Faults[0].Name)
+
+	hostManifest := newImaHostManifest(t, 4)
+	hostManifest.PcrManifest = hvs.PcrManifest{}
+	result, err = rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Len(t, result.Faults, 1)
+	assert.Equal(t, constants.FaultPcrValueMissing, result.Faults[0].Name)
+}
+
+func TestImaMeasurementsAllowed(t *testing.T) {
+	hostManifest := newImaHostManifest(t, 4)
+	ima := hvs.Ima{
+		Allowlist: []hvs.ImaFileMeasurement{
+			{FilePath: "/usr/bin/bash", FileHash: "sha256:2c7dd3a1b0df0b1e0e09c6d1d2cd4e7c6a4b05a3e93e7e1e0ab0a21d9bc0a1f2"},
+			{FileHash: "SHA256:0000000000000000000000000000000000000000000000000000000000000000"},
+		},
+		Denylist: []hvs.ImaFileMeasurement{
+			{FilePath: "/usr/bin/nc"},
+		},
+	}
+
+	rule, err := NewImaMeasurementsAllowed(uuid.New(), &ima, hvs.FlavorPartIma)
+	assert.NoError(t, err)
+	result, err := rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Len(t, result.Faults, 1)
+	assert.Equal(t, constants.FaultImaMeasurementDenied, result.Faults[0].Name)
+	assert.Equal(t, "/usr/bin/nc", result.Faults[0].ImaMeasurement.FileName)
+
+	// the allowlist entry of bash is restricted to its path
+	hostManifest.ImaLog.Measurements[1].FileName = "/tmp/bash"
+	ima.Denylist = nil
+	rule, err = NewImaMeasurementsAllowed(uuid.New(), &ima, hvs.FlavorPartIma)
+	assert.NoError(t, err)
+	result, err = rule.Apply(hostManifest)
+	assert.NoError(t, err)
+	assert.Len(t, result.Faults, 2)
+	assert.Equal(t, constants.FaultImaMeasurementUnknown, result.Faults[0].Name)
+	assert.Equal(t, "/tmp/bash", result.Faults[0].ImaMeasurement.FileName)
+	assert.Equal(t, "/usr/bin/nc", result.Faults[1].ImaMeasurement.FileName)
+}
+
+func TestImaMeasurementsAllowedDenylistOnly(t *testing.T) {
+	ima := hvs.Ima{
+		Denylist: []hvs.ImaFileMeasurement{
+			{FileHash: "sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+		},
+	}
+	rule, err := NewImaMeasurementsAllowed(uuid.New(), &ima, hvs.FlavorPartIma)
+	assert.NoError(t, err)
+	result, err := rule.Apply(newImaHostManifest(t, 4))
+	assert.NoError(t, err)
+	assert.True(t, result.Trusted)
+	assert.Len(t, result.Faults, 0)
+
+	_, err = NewImaMeasurementsAllowed(uuid.New(), &hvs.Ima{Denylist: []hvs.ImaFileMeasurement{{}}}, hvs.FlavorPartIma)
+	assert.Error(t, err)
+}
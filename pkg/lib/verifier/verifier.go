@@ -18,11 +18,15 @@ import (
 
 // VerifierCertificates A collection of certificates/certificate pools that
 // must be provide to the Verifier in NewVerifier().
+//
+// FlavorIntermediateCertificates is optional and only used to build the chain
+// of the flavor signing certificate to the FlavorCACertificates.
 type VerifierCertificates struct {
-	PrivacyCACertificates    *x509.CertPool
-	AssetTagCACertificates   *x509.CertPool
-	FlavorSigningCertificate *x509.Certificate
-	FlavorCACertificates     *x509.CertPool
+	PrivacyCACertificates          *x509.CertPool
+	AssetTagCACertificates         *x509.CertPool
+	FlavorSigningCertificate       *x509.Certificate
+	FlavorCACertificates           *x509.CertPool
+	FlavorIntermediateCertificates *x509.CertPool
 }
 
 // Verifier The interface that exposes the verification of a host manifest
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cms
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ACME object states as defined in RFC 8555 section 7.1.6
+const (
+	AcmeStatusPending     = "pending"
+	AcmeStatusReady       = "ready"
+	AcmeStatusProcessing  = "processing"
+	AcmeStatusValid       = "valid"
+	AcmeStatusInvalid     = "invalid"
+	AcmeStatusDeactivated = "deactivated"
+)
+
+// ACME identifier and challenge types supported by CMS
+const (
+	AcmeIdentifierDNS  = "dns"
+	AcmeIdentifierIP   = "ip"
+	AcmeChallengeHTTP1 = "http-01"
+)
+
+// AcmeDirectory - ACME directory object listing the URLs of the ACME resources
+type AcmeDirectory struct {
+	NewNonce   string            `json:"newNonce"`
+	NewAccount string            `json:"newAccount"`
+	NewOrder   string            `json:"newOrder"`
+	RevokeCert string            `json:"revokeCert,omitempty"`
+	KeyChange  string            `json:"keyChange,omitempty"`
+	Meta       AcmeDirectoryMeta `json:"meta"`
+}
+
+// AcmeDirectoryMeta - Metadata about the ACME server
+type AcmeDirectoryMeta struct {
+	Website                 string `json:"website,omitempty"`
+	ExternalAccountRequired bool   `json:"externalAccountRequired"`
+}
+
+// AcmeProblem - Problem document returned for ACME errors as described in RFC 7807
+type AcmeProblem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+// AcmeIdentifier - Identifier for which a certificate is requested
+type AcmeIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// AcmeAccountRequest - Payload of ACME newAccount requests
+type AcmeAccountRequest struct {
+	Contact                []string        `json:"contact,omitempty"`
+	TermsOfServiceAgreed   bool            `json:"termsOfServiceAgreed,omitempty"`
+	OnlyReturnExisting     bool            `json:"onlyReturnExisting,omitempty"`
+	ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
+	Status                 string          `json:"status,omitempty"`
+}
+
+// AcmeAccount - ACME account resource
+type AcmeAccount struct {
+	Status  string   `json:"status"`
+	Contact []string `json:"contact,omitempty"`
+	Orders  string   `json:"orders,omitempty"`
+}
+
+// AcmeOrderRequest - Payload of ACME newOrder requests
+type AcmeOrderRequest struct {
+	Identifiers []AcmeIdentifier `json:"identifiers"`
+	NotBefore   *time.Time       `json:"notBefore,omitempty"`
+	NotAfter    *time.Time       `json:"notAfter,omitempty"`
+}
+
+// AcmeOrder - ACME order resource for a certificate issued by the CMS TLS CA
+type AcmeOrder struct {
+	Status         string           `json:"status"`
+	Expires        time.Time        `json:"expires"`
+	Identifiers    []AcmeIdentifier `json:"identifiers"`
+	Authorizations []string         `json:"authorizations"`
+	Finalize       string           `json:"finalize"`
+	Certificate    string           `json:"certificate,omitempty"`
+	Error          *AcmeProblem     `json:"error,omitempty"`
+}
+
+// AcmeFinalizeRequest - Payload of ACME order finalize requests
+type AcmeFinalizeRequest struct {
+	Csr string `json:"csr"`
+}
+
+// AcmeAuthorization - ACME authorization resource of an account for an identifier
+type AcmeAuthorization struct {
+	Identifier AcmeIdentifier  `json:"identifier"`
+	Status     string          `json:"status"`
+	Expires    time.Time       `json:"expires"`
+	Challenges []AcmeChallenge `json:"challenges"`
+}
+
+// AcmeChallenge - ACME challenge of an authorization
+type AcmeChallenge struct {
+	Type      string     `json:"type"`
+	URL       string     `json:"url"`
+	Status    string     `json:"status"`
+	Token     string     `json:"token"`
+	Validated *time.Time `json:"validated,omitempty"`
+}
+
+// AcmeEabKey - External account binding key that ties an ACME account to the CMS roles of an AAS user. The roles
+// are only honoured until the token that granted them expires.
+type AcmeEabKey struct {
+	KeyID         string    `json:"key_id"`
+	HmacKey       string    `json:"hmac_key,omitempty"`
+	RoleContexts  []string  `json:"role_contexts,omitempty"`
+	RolesExpireAt time.Time `json:"roles_expire_at"`
+	AccountID     string    `json:"account_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
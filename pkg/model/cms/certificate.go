@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cms
+
+import "time"
+
+// Revocation reasons accepted by the revocation API, as named in RFC 5280 section 5.3.1
+const (
+	RevocationReasonUnspecified          = "unspecified"
+	RevocationReasonKeyCompromise        = "keyCompromise"
+	RevocationReasonCACompromise         = "cACompromise"
+	RevocationReasonAffiliationChanged   = "affiliationChanged"
+	RevocationReasonSuperseded           = "superseded"
+	RevocationReasonCessationOfOperation = "cessationOfOperation"
+	RevocationReasonPrivilegeWithdrawn   = "privilegeWithdrawn"
+)
+
+// RevocationRequest - Reason supplied when revoking an issued certificate
+type RevocationRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// IssuedCertificate - Record of a certificate issued by one of the CMS CAs
+type IssuedCertificate struct {
+	SerialNumber     string     `json:"serial_number"`
+	IssuingCa        string     `json:"issuing_ca"`
+	Subject          string     `json:"subject"`
+	NotBefore        time.Time  `json:"not_before"`
+	NotAfter         time.Time  `json:"not_after"`
+	IssuedAt         time.Time  `json:"issued_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+}
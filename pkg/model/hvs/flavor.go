@@ -28,6 +28,10 @@ type Flavor struct {
 	// External section is unique to AssetTag Flavor type
 	External *External `json:"external,omitempty"`
 	Software *Software `json:"software,omitempty"`
+	// Ima section is unique to IMA Flavor type
+	Ima *Ima `json:"ima,omitempty"`
+	// CustomRules are copied from the flavor template and verified against the host manifest
+	CustomRules []CustomRule `json:"custom_rules,omitempty"`
 }
 
 // NewFlavor returns a new instance of Flavor
@@ -79,6 +83,7 @@ type Flavors struct {
 // SignedFlavorCollection is a list of SignedFlavor objects
 type SignedFlavorCollection struct {
 	SignedFlavors []SignedFlavor `json:"signed_flavors"`
+	Next          string         `json:"next,omitempty"`
 }
 
 func (s SignedFlavorCollection) GetFlavors(flavorPart string) []SignedFlavor {
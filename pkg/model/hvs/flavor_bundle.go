@@ -0,0 +1,266 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// FlavorBundle carries the flavors, flavor groups and flavor templates exported from an HVS. The content is signed
+// with the flavor signing key of the exporting HVS, the flavors it holds keep their own signatures.
+type FlavorBundle struct {
+	Content json.RawMessage `json:"content"`
+	// SigningCertificate is the PEM encoded certificate chain of the key that signed the bundle, followed by the
+	// certificates of the other keys that signed its flavors
+	SigningCertificate string `json:"signing_certificate"`
+	Signature          string `json:"signature"`
+}
+
+// FlavorBundleContent is the signed content of a FlavorBundle
+type FlavorBundleContent struct {
+	ExportedAt      time.Time        `json:"exported_at"`
+	SignedFlavors   []SignedFlavor   `json:"signed_flavors"`
+	Flavorgroups    []FlavorGroup    `json:"flavorgroups,omitempty"`
+	FlavorTemplates []FlavorTemplate `json:"flavor_templates,omitempty"`
+}
+
+// NewFlavorBundle signs the content with the flavor signing key, the certificates are the chain of the flavor signing
+// certificate starting with the certificate of the key, followed by the certificates of the other keys that signed
+// the flavors of the content
+func NewFlavorBundle(content FlavorBundleContent, privateKey *rsa.PrivateKey, certificates []x509.Certificate) (*FlavorBundle, error) {
+	if privateKey == nil || privateKey.Validate() != nil {
+		return nil, errors.New("Valid private key must be provided and cannot be nil")
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("The flavor signing certificate must be provided")
+	}
+
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred while marshalling the flavor bundle content")
+	}
+	digest := sha512.Sum384(contentBytes)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA384, digest[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "An error occurred while signing the flavor bundle")
+	}
+
+	var certificatePem bytes.Buffer
+	for _, certificate := range certificates {
+		if err := pem.Encode(&certificatePem, &pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}); err != nil {
+			return nil, errors.Wrap(err, "An error occurred while encoding the flavor signing certificate")
+		}
+	}
+
+	return &FlavorBundle{
+		Content:            contentBytes,
+		SigningCertificate: certificatePem.String(),
+		Signature:          base64.StdEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// Verify checks that the signing certificate of the bundle chains to one of the trusted CAs and that the bundle is
+// signed with its key. Each flavor must be signed with the key of the bundle or with the key of another certificate
+// of the bundle that chains to one of the trusted CAs. It returns the content of the bundle along with the signing
+// certificate of the bundle, the other certificates of the bundle that chain to the trusted CAs and the intermediate
+// CAs of their chains. The certificates that do not verify are left out.
+func (bundle *FlavorBundle) Verify(trustedCAs *x509.CertPool) (*FlavorBundleContent, []x509.Certificate, error) {
+	if trustedCAs == nil {
+		return nil, nil, errors.New("Could not verify the flavor bundle: no trusted flavor CA is configured")
+	}
+
+	var certificates []x509.Certificate
+	rest := []byte(bundle.SigningCertificate)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Could not verify the flavor bundle: the signing certificate could not be parsed")
+		}
+		certificates = append(certificates, *certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, nil, errors.New("Could not verify the flavor bundle: the signing certificate is missing")
+	}
+
+	intermediates := x509.NewCertPool()
+	for i := range certificates[1:] {
+		intermediates.AddCert(&certificates[i+1])
+	}
+	verifyOptions := x509.VerifyOptions{
+		Roots:         trustedCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	chains, err := certificates[0].Verify(verifyOptions)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Could not verify the flavor bundle: the signing certificate is not issued by a trusted flavor CA")
+	}
+	publicKey, ok := certificates[0].PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, nil, errors.New("Could not verify the flavor bundle: the signing certificate does not hold an RSA public key")
+	}
+
+	// the content is compacted the same way it was when the bundle was marshalled by the exporting HVS
+	var contentBytes bytes.Buffer
+	if err := json.Compact(&contentBytes, bundle.Content); err != nil {
+		return nil, nil, errors.Wrap(err, "Could not verify the flavor bundle: invalid content")
+	}
+	signature, err := base64.StdEncoding.DecodeString(bundle.Signature)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Could not verify the flavor bundle: the signature could not be decoded")
+	}
+	digest := sha512.Sum384(contentBytes.Bytes())
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA384, digest[:], signature); err != nil {
+		return nil, nil, errors.Wrap(err, "Could not verify the flavor bundle: PKCS1 verification failed")
+	}
+
+	var content FlavorBundleContent
+	if err := json.Unmarshal(contentBytes.Bytes(), &content); err != nil {
+		return nil, nil, errors.Wrap(err, "Could not verify the flavor bundle: the content could not be decoded")
+	}
+
+	// the flavors imported from another HVS keep the signature of its flavor signing key
+	flavorSigningKeys := []*rsa.PublicKey{publicKey}
+	verifiedCertificates := addVerifiedCertificates(nil, &certificates[0], chains)
+	for i := range certificates[1:] {
+		certificate := &certificates[i+1]
+		certificateKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+		if !ok || certificate.IsCA {
+			continue
+		}
+		if chains, err := certificate.Verify(verifyOptions); err == nil {
+			flavorSigningKeys = append(flavorSigningKeys, certificateKey)
+			verifiedCertificates = addVerifiedCertificates(verifiedCertificates, certificate, chains)
+		}
+	}
+	for _, signedFlavor := range content.SignedFlavors {
+		verified := false
+		for _, flavorSigningKey := range flavorSigningKeys {
+			if signedFlavor.Verify(flavorSigningKey) == nil {
+				verified = true
+				break
+			}
+		}
+		if !verified {
+			return nil, nil, errors.Errorf("Could not verify the flavor bundle: flavor %s is not signed by a trusted certificate of the bundle",
+				signedFlavor.Flavor.Meta.ID)
+		}
+	}
+	return &content, verifiedCertificates, nil
+}
+
+// addVerifiedCertificates adds a verified certificate and the intermediate CAs of its chains to the verified
+// certificates, leaving out the trusted roots the chains end with and the certificates already added.
+func addVerifiedCertificates(verifiedCertificates []x509.Certificate, verifiedCertificate *x509.Certificate,
+	chains [][]*x509.Certificate) []x509.Certificate {
+	for _, chain := range append([][]*x509.Certificate{{verifiedCertificate}}, chains...) {
+		if len(chain) > 1 {
+			chain = chain[:len(chain)-1]
+		}
+		for _, certificate := range chain {
+			added := false
+			for i := range verifiedCertificates {
+				if bytes.Equal(verifiedCertificates[i].Raw, certificate.Raw) {
+					added = true
+					break
+				}
+			}
+			if !added {
+				verifiedCertificates = append(verifiedCertificates, *certificate)
+			}
+		}
+	}
+	return verifiedCertificates
+}
+
+// FlavorImportConflictMode tells how the flavors of a bundle that already exist in the HVS are imported
+type FlavorImportConflictMode string
+
+const (
+	// FlavorImportConflictFail rejects the import when any flavor of the bundle already exists
+	FlavorImportConflictFail FlavorImportConflictMode = "fail"
+	// FlavorImportConflictSkip keeps the existing flavors and skips the ones of the bundle
+	FlavorImportConflictSkip FlavorImportConflictMode = "skip"
+	// FlavorImportConflictReplace deletes the existing flavors and stores the ones of the bundle
+	FlavorImportConflictReplace FlavorImportConflictMode = "replace"
+)
+
+func (mode FlavorImportConflictMode) String() string {
+	return string(mode)
+}
+
+// Parse sets the conflict mode from its name, the empty name is the fail mode
+func (mode *FlavorImportConflictMode) Parse(name string) error {
+	switch FlavorImportConflictMode(name) {
+	case "", FlavorImportConflictFail:
+		*mode = FlavorImportConflictFail
+	case FlavorImportConflictSkip, FlavorImportConflictReplace:
+		*mode = FlavorImportConflictMode(name)
+	default:
+		return errors.Errorf("Invalid conflict mode %q, it must be one of %s, %s or %s", name,
+			FlavorImportConflictFail, FlavorImportConflictSkip, FlavorImportConflictReplace)
+	}
+	return nil
+}
+
+// FlavorImportAction is what the import does, or would do on a dry run, with an entry of the bundle
+type FlavorImportAction string
+
+const (
+	FlavorImportActionCreate   FlavorImportAction = "create"
+	FlavorImportActionReplace  FlavorImportAction = "replace"
+	FlavorImportActionSkip     FlavorImportAction = "skip"
+	FlavorImportActionReuse    FlavorImportAction = "reuse"
+	FlavorImportActionConflict FlavorImportAction = "conflict"
+)
+
+// FlavorImportResult is the outcome of the import of a flavor, flavor group or flavor template of the bundle
+type FlavorImportResult struct {
+	// swagger:strfmt uuid
+	ID uuid.UUID `json:"id"`
+	// Name is the label of a flavor or flavor template, the name of a flavor group
+	Name   string             `json:"name"`
+	Action FlavorImportAction `json:"action"`
+	// swagger:strfmt uuid
+	ExistingID *uuid.UUID `json:"existing_id,omitempty"`
+	Message    string     `json:"message,omitempty"`
+}
+
+// FlavorImportReport lists the outcome of the import of a flavor bundle
+type FlavorImportReport struct {
+	DryRun          bool                     `json:"dry_run"`
+	ConflictMode    FlavorImportConflictMode `json:"conflict_mode"`
+	Flavors         []FlavorImportResult     `json:"flavors"`
+	Flavorgroups    []FlavorImportResult     `json:"flavorgroups"`
+	FlavorTemplates []FlavorImportResult     `json:"flavor_templates"`
+}
+
+// HasConflicts returns true when a flavor of the bundle conflicts with an existing one
+func (report *FlavorImportReport) HasConflicts() bool {
+	for _, result := range report.Flavors {
+		if result.Action == FlavorImportActionConflict {
+			return true
+		}
+	}
+	return false
+}
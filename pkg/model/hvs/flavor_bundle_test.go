@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCertificate issues a certificate for a new RSA key, the certificate is self signed when no issuer is given
+func newTestCertificate(t *testing.T, name string, isCA bool, issuer *x509.Certificate, issuerKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if issuer == nil {
+		issuer, issuerKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return certificate, key
+}
+
+func TestFlavorBundleVerify(t *testing.T) {
+	caCertificate, caKey := newTestCertificate(t, "Flavor CA", true, nil, nil)
+	signingCertificate, signingKey := newTestCertificate(t, "Flavor Signing", false, caCertificate, caKey)
+	otherCaCertificate, _ := newTestCertificate(t, "Other CA", true, nil, nil)
+
+	signedFlavor, err := NewSignedFlavor(&Flavor{
+		Meta: Meta{
+			ID: uuid.New(),
+			Description: map[string]interface{}{
+				Label:                 "<platform>",
+				FlavorPartDescription: FlavorPartPlatform.String(),
+			},
+		},
+	}, signingKey)
+	assert.NoError(t, err)
+	content := FlavorBundleContent{
+		ExportedAt:    time.Now().UTC(),
+		SignedFlavors: []SignedFlavor{*signedFlavor},
+		Flavorgroups:  []FlavorGroup{{ID: uuid.New(), Name: "site", FlavorIds: []uuid.UUID{signedFlavor.Flavor.Meta.ID}}},
+	}
+	bundle, err := NewFlavorBundle(content, signingKey, []x509.Certificate{*signingCertificate, *caCertificate})
+	assert.NoError(t, err)
+
+	// the bundle is verified as it is received by the importing HVS
+	bundleBytes, err := json.MarshalIndent(bundle, "", "  ")
+	assert.NoError(t, err)
+	var received FlavorBundle
+	assert.NoError(t, json.Unmarshal(bundleBytes, &received))
+
+	trustedCAs := x509.NewCertPool()
+	trustedCAs.AddCert(caCertificate)
+	verifiedContent, certificates, err := received.Verify(trustedCAs)
+	assert.NoError(t, err)
+	// the trusted CA is not returned
+	assert.Len(t, certificates, 1)
+	assert.Equal(t, signingCertificate.Raw, certificates[0].Raw)
+	assert.Equal(t, signedFlavor.Signature, verifiedContent.SignedFlavors[0].Signature)
+	assert.Equal(t, "site", verifiedContent.Flavorgroups[0].Name)
+
+	otherCAs := x509.NewCertPool()
+	otherCAs.AddCert(otherCaCertificate)
+	_, _, err = received.Verify(otherCAs)
+	assert.Error(t, err)
+	_, _, err = received.Verify(nil)
+	assert.Error(t, err)
+
+	tampered := received
+	tampered.Content = json.RawMessage(string(received.Content[:len(received.Content)-1]) + `,"extra":true}`)
+	_, _, err = tampered.Verify(trustedCAs)
+	assert.Error(t, err)
+
+	// a flavor whose signature does not match is rejected even when the bundle signature is valid
+	content.SignedFlavors[0].Signature = signedFlavor.Signature[1:]
+	invalidFlavorBundle, err := NewFlavorBundle(content, signingKey, []x509.Certificate{*signingCertificate})
+	assert.NoError(t, err)
+	_, _, err = invalidFlavorBundle.Verify(trustedCAs)
+	assert.Error(t, err)
+}
+
+func TestFlavorBundleVerifyImportedFlavors(t *testing.T) {
+	caCertificate, caKey := newTestCertificate(t, "Flavor CA", true, nil, nil)
+	signingCertificate, signingKey := newTestCertificate(t, "Flavor Signing", false, caCertificate, caKey)
+	importedCertificate, importedKey := newTestCertificate(t, "Other HVS Flavor Signing", false, caCertificate, caKey)
+	otherCaCertificate, otherCaKey := newTestCertificate(t, "Other CA", true, nil, nil)
+	untrustedCertificate, untrustedKey := newTestCertificate(t, "Untrusted Flavor Signing", false, otherCaCertificate, otherCaKey)
+	trustedCAs := x509.NewCertPool()
+	trustedCAs.AddCert(caCertificate)
+
+	newFlavor := func(label string, key *rsa.PrivateKey) SignedFlavor {
+		signedFlavor, err := NewSignedFlavor(&Flavor{
+			Meta: Meta{
+				ID: uuid.New(),
+				Description: map[string]interface{}{
+					Label:                 label,
+					FlavorPartDescription: FlavorPartPlatform.String(),
+				},
+			},
+		}, key)
+		assert.NoError(t, err)
+		return *signedFlavor
+	}
+	ownFlavor := newFlavor("own", signingKey)
+	importedFlavor := newFlavor("imported", importedKey)
+	untrustedFlavor := newFlavor("untrusted", untrustedKey)
+
+	// a flavor imported from another HVS is verified with the certificate of its own signing key
+	bundle, err := NewFlavorBundle(FlavorBundleContent{SignedFlavors: []SignedFlavor{ownFlavor, importedFlavor}},
+		signingKey, []x509.Certificate{*signingCertificate, *importedCertificate})
+	assert.NoError(t, err)
+	content, certificates, err := bundle.Verify(trustedCAs)
+	assert.NoError(t, err)
+	assert.Len(t, content.SignedFlavors, 2)
+	assert.Len(t, certificates, 2)
+
+	// only the certificates that chain to a trusted flavor CA are returned, along with their intermediate CAs
+	intermediateCertificate, intermediateKey := newTestCertificate(t, "Intermediate CA", true, caCertificate, caKey)
+	chainedCertificate, chainedKey := newTestCertificate(t, "Chained Flavor Signing", false, intermediateCertificate, intermediateKey)
+	chainedFlavor := newFlavor("chained", chainedKey)
+	bundle, err = NewFlavorBundle(FlavorBundleContent{SignedFlavors: []SignedFlavor{ownFlavor, chainedFlavor}},
+		signingKey, []x509.Certificate{*signingCertificate, *chainedCertificate, *intermediateCertificate,
+			*untrustedCertificate, *otherCaCertificate})
+	assert.NoError(t, err)
+	_, certificates, err = bundle.Verify(trustedCAs)
+	assert.NoError(t, err)
+	if assert.Len(t, certificates, 3) {
+		assert.Equal(t, signingCertificate.Raw, certificates[0].Raw)
+		assert.Equal(t, chainedCertificate.Raw, certificates[1].Raw)
+		assert.Equal(t, intermediateCertificate.Raw, certificates[2].Raw)
+	}
+
+	// the certificate of the flavor signing key must be in the bundle
+	bundle, err = NewFlavorBundle(FlavorBundleContent{SignedFlavors: []SignedFlavor{ownFlavor, importedFlavor}},
+		signingKey, []x509.Certificate{*signingCertificate})
+	assert.NoError(t, err)
+	_, _, err = bundle.Verify(trustedCAs)
+	assert.Error(t, err)
+
+	// and it must be issued by a trusted flavor CA
+	bundle, err = NewFlavorBundle(FlavorBundleContent{SignedFlavors: []SignedFlavor{ownFlavor, untrustedFlavor}},
+		signingKey, []x509.Certificate{*signingCertificate, *untrustedCertificate, *otherCaCertificate})
+	assert.NoError(t, err)
+	_, _, err = bundle.Verify(trustedCAs)
+	assert.Error(t, err)
+}
+
+func TestFlavorImportConflictModeParse(t *testing.T) {
+	var mode FlavorImportConflictMode
+	assert.NoError(t, mode.Parse(""))
+	assert.Equal(t, FlavorImportConflictFail, mode)
+	assert.NoError(t, mode.Parse("replace"))
+	assert.Equal(t, FlavorImportConflictReplace, mode)
+	assert.Error(t, mode.Parse("merge"))
+}
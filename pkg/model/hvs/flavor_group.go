@@ -13,6 +13,7 @@ import (
 
 type FlavorgroupCollection struct {
 	Flavorgroups []FlavorGroup `json:"flavorgroups" xml:"flavorgroup"`
+	Next         string        `json:"next,omitempty" xml:"next,omitempty"`
 }
 
 type FlavorMatchPolicies []FlavorMatchPolicy
@@ -31,6 +32,9 @@ type FlavorGroup struct {
 	FlavorTemplateIds []uuid.UUID         `json:"flavorTemplateIds,omitempty"`
 	Flavors           []Flavor            `json:"flavors,omitempty"`
 	MatchPolicies     FlavorMatchPolicies `json:"flavor_match_policies,omitempty"`
+	RefreshSchedule   *RefreshSchedule    `json:"refresh_schedule,omitempty"`
+	// HostSelector is a label selector, the flavor group is linked to the hosts whose labels match it
+	HostSelector string `json:"host_selector,omitempty"`
 }
 
 type FlavorMatchPolicy struct {
@@ -88,6 +92,8 @@ func (r FlavorGroup) MarshalJSON() ([]byte, error) {
 		Flavors                     []Flavor                    `json:"flavors,omitempty"`
 		FlavorTemplateIds           []uuid.UUID                 `json:"flavorTemplateIds,omitempty"`
 		FlavorMatchPolicyCollection FlavorMatchPolicyCollection `json:"flavor_match_policy_collection,omitempty"`
+		RefreshSchedule             *RefreshSchedule            `json:"refresh_schedule,omitempty"`
+		HostSelector                string                      `json:"host_selector,omitempty"`
 	}{
 		ID:                          r.ID,
 		Name:                        r.Name,
@@ -95,6 +101,8 @@ func (r FlavorGroup) MarshalJSON() ([]byte, error) {
 		Flavors:                     r.Flavors,
 		FlavorTemplateIds:           r.FlavorTemplateIds,
 		FlavorMatchPolicyCollection: FlavorMatchPolicyCollection{r.MatchPolicies},
+		RefreshSchedule:             r.RefreshSchedule,
+		HostSelector:                r.HostSelector,
 	})
 }
 
@@ -106,6 +114,8 @@ func (r *FlavorGroup) UnmarshalJSON(b []byte) error {
 		FlavorTemplateIds           []uuid.UUID                 `json:"flavorTemplateIds,omitempty"`
 		Flavors                     []Flavor                    `json:"flavors,omitempty"`
 		FlavorMatchPolicyCollection FlavorMatchPolicyCollection `json:"flavor_match_policy_collection,omitempty"`
+		RefreshSchedule             *RefreshSchedule            `json:"refresh_schedule,omitempty"`
+		HostSelector                string                      `json:"host_selector,omitempty"`
 	})
 	err := json.Unmarshal(b, decoded)
 	if err == nil {
@@ -115,6 +125,8 @@ func (r *FlavorGroup) UnmarshalJSON(b []byte) error {
 		r.FlavorTemplateIds = decoded.FlavorTemplateIds
 		r.Flavors = decoded.Flavors
 		r.MatchPolicies = decoded.FlavorMatchPolicyCollection.FlavorMatchPolicies
+		r.RefreshSchedule = decoded.RefreshSchedule
+		r.HostSelector = decoded.HostSelector
 	}
 	return err
 }
@@ -157,8 +169,46 @@ type FlavorgroupFlavorLinkCollection struct {
 	FGFLinks []FlavorgroupFlavorLink `json:"flavor_flavorgroup_links,omitempty"`
 }
 
+// FlavorgroupHostSelector is used to hold the request details of a FlavorGroup host selector update
+type FlavorgroupHostSelector struct {
+	HostSelector string `json:"host_selector"`
+}
+
 // FlavorgroupFlavorLinkCriteria is used to hold the request details of a Flavor-FlavorGroup Link Request
 type FlavorgroupFlavorLinkCriteria struct {
 	// swagger:strfmt uuid
 	FlavorID uuid.UUID `json:"flavor_id"`
 }
+
+// FlavorgroupSimulationRequest holds the candidate changes of a FlavorGroup whose effect on the trust of the linked
+// hosts is to be predicted
+type FlavorgroupSimulationRequest struct {
+	// FlavorIds are the existing flavors to be added to the FlavorGroup
+	// swagger:strfmt uuid
+	FlavorIds []uuid.UUID `json:"flavor_ids,omitempty"`
+	// FlavorCollection holds the content of the new flavors to be added to the FlavorGroup
+	FlavorCollection FlavorCollection `json:"flavor_collection,omitempty"`
+	// MatchPolicies replace the match policies of the FlavorGroup
+	MatchPolicies FlavorMatchPolicies `json:"flavor_match_policies,omitempty"`
+}
+
+// FlavorgroupSimulationReport holds the predicted trust of the hosts linked to a FlavorGroup
+type FlavorgroupSimulationReport struct {
+	// swagger:strfmt uuid
+	FlavorGroupID uuid.UUID             `json:"flavorgroup_id"`
+	Hosts         []HostTrustSimulation `json:"hosts"`
+}
+
+// HostTrustSimulation holds the trust of a host against a FlavorGroup as it is and as it would be with the candidate
+// changes, both evaluated from the last host manifest stored for the host
+type HostTrustSimulation struct {
+	// swagger:strfmt uuid
+	HostID           uuid.UUID `json:"host_id"`
+	CurrentTrusted   *bool     `json:"current_trusted,omitempty"`
+	PredictedTrusted *bool     `json:"predicted_trusted,omitempty"`
+	TrustChanged     bool      `json:"trust_changed"`
+	// UntrustedFlavorParts are the flavor parts the host would not be trusted for
+	UntrustedFlavorParts []string `json:"untrusted_flavor_parts,omitempty"`
+	// Error tells why the trust of the host could not be predicted
+	Error string `json:"error,omitempty"`
+}
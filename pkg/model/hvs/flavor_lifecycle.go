@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// FlavorState is the lifecycle state of a flavor
+type FlavorState string
+
+const (
+	// FlavorStateActive flavors are used to verify the hosts
+	FlavorStateActive FlavorState = "active"
+	// FlavorStateDeprecated flavors still make the hosts trusted until the end of their grace period, the hosts
+	// matching them are flagged with a warning fault
+	FlavorStateDeprecated FlavorState = "deprecated"
+	// FlavorStateRevoked flavors make the hosts matching them untrusted
+	FlavorStateRevoked FlavorState = "revoked"
+)
+
+func (state FlavorState) String() string {
+	return string(state)
+}
+
+// FlavorLifecycle holds the lifecycle of a flavor. It is kept apart from the flavor content, which is signed and
+// cannot be changed.
+type FlavorLifecycle struct {
+	State FlavorState `json:"state"`
+	// GracePeriodEnd is the time until which a deprecated flavor still makes the hosts trusted, a deprecated flavor
+	// without a grace period end stays usable until it is revoked
+	GracePeriodEnd *time.Time `json:"grace_period_end,omitempty"`
+	// swagger:strfmt uuid
+	SupersededBy *uuid.UUID `json:"superseded_by,omitempty"`
+	// ExpiresAt is the time after which the flavor makes the hosts untrusted, whatever its state
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Validate checks the state of the lifecycle and that the grace period is only given to deprecated flavors
+func (lifecycle FlavorLifecycle) Validate() error {
+	switch lifecycle.State {
+	case FlavorStateActive, FlavorStateDeprecated, FlavorStateRevoked:
+	default:
+		return errors.Errorf("Invalid flavor state %q, it must be one of %s, %s or %s", lifecycle.State,
+			FlavorStateActive, FlavorStateDeprecated, FlavorStateRevoked)
+	}
+	if lifecycle.GracePeriodEnd != nil && lifecycle.State != FlavorStateDeprecated {
+		return errors.New("A grace period can only be given to a deprecated flavor")
+	}
+	if lifecycle.SupersededBy != nil && *lifecycle.SupersededBy == uuid.Nil {
+		return errors.New("The superseding flavor must be a valid flavor ID")
+	}
+	return nil
+}
+
+// IsExpired returns true when the expiry date of the flavor has passed
+func (lifecycle FlavorLifecycle) IsExpired(now time.Time) bool {
+	return lifecycle.ExpiresAt != nil && !now.Before(*lifecycle.ExpiresAt)
+}
+
+// IsGracePeriodOver returns true when the flavor is deprecated and its grace period has ended
+func (lifecycle FlavorLifecycle) IsGracePeriodOver(now time.Time) bool {
+	return lifecycle.State == FlavorStateDeprecated && lifecycle.GracePeriodEnd != nil &&
+		!now.Before(*lifecycle.GracePeriodEnd)
+}
+
+// NextDeadline returns the earliest time after now at which the flavor expires or its grace period ends, nil when
+// the lifecycle of the flavor no longer changes with time
+func (lifecycle FlavorLifecycle) NextDeadline(now time.Time) *time.Time {
+	var deadline *time.Time
+	if lifecycle.ExpiresAt != nil && now.Before(*lifecycle.ExpiresAt) {
+		deadline = lifecycle.ExpiresAt
+	}
+	if lifecycle.State == FlavorStateDeprecated && lifecycle.GracePeriodEnd != nil && now.Before(*lifecycle.GracePeriodEnd) &&
+		(deadline == nil || lifecycle.GracePeriodEnd.Before(*deadline)) {
+		deadline = lifecycle.GracePeriodEnd
+	}
+	return deadline
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlavorLifecycleValidate(t *testing.T) {
+	now := time.Now()
+	supersededBy := uuid.New()
+
+	assert.NoError(t, FlavorLifecycle{State: FlavorStateActive}.Validate())
+	assert.NoError(t, FlavorLifecycle{State: FlavorStateActive, ExpiresAt: &now}.Validate())
+	assert.NoError(t, FlavorLifecycle{State: FlavorStateDeprecated, GracePeriodEnd: &now, SupersededBy: &supersededBy}.Validate())
+	assert.NoError(t, FlavorLifecycle{State: FlavorStateRevoked, SupersededBy: &supersededBy}.Validate())
+	assert.Error(t, FlavorLifecycle{}.Validate())
+	assert.Error(t, FlavorLifecycle{State: "retired"}.Validate())
+	assert.Error(t, FlavorLifecycle{State: FlavorStateRevoked, GracePeriodEnd: &now}.Validate())
+	assert.Error(t, FlavorLifecycle{State: FlavorStateDeprecated, SupersededBy: &uuid.Nil}.Validate())
+}
+
+func TestFlavorLifecycleExpiry(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, FlavorLifecycle{State: FlavorStateActive}.IsExpired(now))
+	assert.False(t, FlavorLifecycle{State: FlavorStateActive, ExpiresAt: &future}.IsExpired(now))
+	assert.True(t, FlavorLifecycle{State: FlavorStateActive, ExpiresAt: &past}.IsExpired(now))
+
+	assert.False(t, FlavorLifecycle{State: FlavorStateDeprecated}.IsGracePeriodOver(now))
+	assert.False(t, FlavorLifecycle{State: FlavorStateDeprecated, GracePeriodEnd: &future}.IsGracePeriodOver(now))
+	assert.True(t, FlavorLifecycle{State: FlavorStateDeprecated, GracePeriodEnd: &past}.IsGracePeriodOver(now))
+	assert.False(t, FlavorLifecycle{State: FlavorStateActive, GracePeriodEnd: &past}.IsGracePeriodOver(now))
+}
+
+func TestFlavorLifecycleNextDeadline(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	soon := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+
+	assert.Nil(t, FlavorLifecycle{State: FlavorStateActive}.NextDeadline(now))
+	assert.Nil(t, FlavorLifecycle{State: FlavorStateActive, ExpiresAt: &past}.NextDeadline(now))
+	assert.Equal(t, &soon, FlavorLifecycle{State: FlavorStateActive, ExpiresAt: &soon}.NextDeadline(now))
+	assert.Equal(t, &soon, FlavorLifecycle{State: FlavorStateDeprecated, GracePeriodEnd: &soon, ExpiresAt: &later}.NextDeadline(now))
+	assert.Equal(t, &soon, FlavorLifecycle{State: FlavorStateDeprecated, GracePeriodEnd: &later, ExpiresAt: &soon}.NextDeadline(now))
+	assert.Equal(t, &later, FlavorLifecycle{State: FlavorStateDeprecated, GracePeriodEnd: &past, ExpiresAt: &later}.NextDeadline(now))
+}
@@ -31,10 +31,24 @@ type PcrRules struct {
 	EventlogIncludes []string `json:"eventlog_includes,omitempty"`
 }
 
+// CustomRule is a declarative verification rule that is evaluated against the host manifest. Exactly one of
+// JsonQuery or Cel has to be provided.
+type CustomRule struct {
+	// Unique name of the rule within the flavor part. Sample value: "bios_version_minimum"
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// A 'jsonquery' statement that has to select at least one node of the host manifest. Sample value: "//host_info/hardware_features/TPM/meta/tpm_version[text()='2.0']"
+	JsonQuery string `json:"jsonquery,omitempty"`
+	// A CEL expression that has to evaluate to true, the host manifest is available as 'host_manifest'. Sample value: "host_manifest.host_info.bios_version >= '2.0'"
+	Cel string `json:"cel,omitempty"`
+}
+
 type FlavorPart struct {
 	// Meta is key:value pair section used to define flavorparts with its own meta fields.
 	Meta     map[string]interface{} `json:"meta,omitempty"`
 	PcrRules []PcrRules             `json:"pcr_rules"`
+	// Custom rules are copied to the resulting flavor and verified in addition to the PCR rules.
+	CustomRules []CustomRule `json:"custom_rules,omitempty"`
 }
 
 // swagger:parameters FlavorParts
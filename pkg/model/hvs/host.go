@@ -12,6 +12,7 @@ import (
 
 type HostCollection struct {
 	Hosts []*Host `json:"hosts" xml:"host"`
+	Next  string  `json:"next,omitempty" xml:"next,omitempty"`
 }
 
 type Host struct {
@@ -26,13 +27,17 @@ type Host struct {
 	Report           *TrustReport           `json:"report,omitempty"`
 	Trusted          *bool                  `json:"trusted,omitempty"`
 	ConnectionStatus *HostStatusInformation `json:"status,omitempty"`
+	RefreshSchedule  *RefreshSchedule       `json:"refresh_schedule,omitempty"`
+	Labels           Labels                 `json:"labels,omitempty"`
 }
 
 type HostCreateRequest struct {
-	HostName         string   `json:"host_name"`
-	Description      string   `json:"description,omitempty"`
-	ConnectionString string   `json:"connection_string"`
-	FlavorgroupNames []string `json:"flavorgroup_names,omitempty"`
+	HostName         string           `json:"host_name"`
+	Description      string           `json:"description,omitempty"`
+	ConnectionString string           `json:"connection_string"`
+	FlavorgroupNames []string         `json:"flavorgroup_names,omitempty"`
+	RefreshSchedule  *RefreshSchedule `json:"refresh_schedule,omitempty"`
+	Labels           Labels           `json:"labels,omitempty"`
 }
 
 type HostFlavorgroupCollection struct {
@@ -61,6 +66,9 @@ type HostFilterCriteria struct {
 	IdList         []uuid.UUID
 	Trusted        *bool
 	OrderBy        OrderType
+	Limit          int
+	After          uuid.UUID
+	LabelSelector  string
 }
 
 type OrderType string
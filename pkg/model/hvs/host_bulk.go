@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/google/uuid"
+
+// HostBulkConflictPolicy tells the bulk host registration what to do with hosts that are already registered
+type HostBulkConflictPolicy string
+
+const (
+	// HostBulkConflictFail reports an already registered host as failed
+	HostBulkConflictFail HostBulkConflictPolicy = "fail"
+	// HostBulkConflictSkip leaves an already registered host untouched
+	HostBulkConflictSkip HostBulkConflictPolicy = "skip"
+	// HostBulkConflictUpdate updates the description, connection string and flavorgroups of an already registered host
+	HostBulkConflictUpdate HostBulkConflictPolicy = "update"
+)
+
+// Valid returns true for the known conflict policies
+func (policy HostBulkConflictPolicy) Valid() bool {
+	switch policy {
+	case HostBulkConflictFail, HostBulkConflictSkip, HostBulkConflictUpdate:
+		return true
+	}
+	return false
+}
+
+// HostBulkCreateRequest is the JSON form of a bulk host registration request
+type HostBulkCreateRequest struct {
+	Hosts []HostCreateRequest `json:"hosts"`
+}
+
+// HostBulkResultStatus is the outcome of the registration of a single host of a bulk job
+type HostBulkResultStatus string
+
+const (
+	HostBulkResultPending HostBulkResultStatus = "Pending"
+	HostBulkResultCreated HostBulkResultStatus = "Created"
+	HostBulkResultUpdated HostBulkResultStatus = "Updated"
+	HostBulkResultSkipped HostBulkResultStatus = "Skipped"
+	HostBulkResultFailed  HostBulkResultStatus = "Failed"
+)
+
+// HostBulkResult is the outcome of the registration of a single host of a bulk job
+type HostBulkResult struct {
+	HostName string               `json:"host_name"`
+	Status   HostBulkResultStatus `json:"status"`
+	// swagger:strfmt uuid
+	HostId *uuid.UUID `json:"host_id,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// HostBulkSummary reports the progress and the per host results of a bulk host registration, it is the result of the
+// HOST_BULK_CREATE job
+type HostBulkSummary struct {
+	OnConflict HostBulkConflictPolicy `json:"on_conflict"`
+	Total      int                    `json:"total"`
+	Processed  int                    `json:"processed"`
+	Created    int                    `json:"created"`
+	Updated    int                    `json:"updated"`
+	Skipped    int                    `json:"skipped"`
+	Failed     int                    `json:"failed"`
+	Results    []HostBulkResult       `json:"results"`
+}
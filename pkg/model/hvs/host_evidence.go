@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	taModel "github.com/intel-secl/intel-secl/v4/pkg/model/ta"
+)
+
+// HostNonce is the challenge a host has to bind its TPM quote to when it submits evidence in push mode
+type HostNonce struct {
+	// swagger:strfmt uuid
+	HostId    uuid.UUID `json:"host_id"`
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// HostEvidence is submitted by a host in push mode, it holds the same data HVS otherwise retrieves from the
+// trust agent. The quote has to be bound to the last nonce issued to the host.
+type HostEvidence struct {
+	Nonce            string           `json:"nonce"`
+	HostInfo         taModel.HostInfo `json:"host_info"`
+	Aik              string           `json:"aik"`
+	Quote            string           `json:"quote"`
+	EventLog         string           `json:"event_log"`
+	TcbMeasurements  []string         `json:"tcb_measurements,omitempty"`
+	SelectedPcrBanks []string         `json:"selected_pcr_banks,omitempty"`
+	IsTagProvisioned bool             `json:"is_tag_provisioned"`
+	AssetTag         string           `json:"asset_tag,omitempty"`
+	ImaLog           string           `json:"ima_log,omitempty"`
+	// BindingKeyCertificate is the base64 encoded DER binding key certificate, required when the workload agent is
+	// installed
+	BindingKeyCertificate string `json:"binding_key_certificate,omitempty"`
+}
+
+// GetTpmQuoteResponse returns the evidence in the format of the trust agent's TPM quote response
+func (evidence *HostEvidence) GetTpmQuoteResponse() taModel.TpmQuoteResponse {
+	tpmQuoteResponse := taModel.TpmQuoteResponse{
+		Aik:              evidence.Aik,
+		Quote:            evidence.Quote,
+		EventLog:         evidence.EventLog,
+		IsTagProvisioned: evidence.IsTagProvisioned,
+		AssetTag:         evidence.AssetTag,
+		ImaLog:           evidence.ImaLog,
+	}
+	tpmQuoteResponse.TcbMeasurements.TcbMeasurements = evidence.TcbMeasurements
+	tpmQuoteResponse.SelectedPcrBanks.SelectedPcrBanks = evidence.SelectedPcrBanks
+	return tpmQuoteResponse
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HostFetchState is the state of a host in the host data fetch queue
+type HostFetchState string
+
+const (
+	// HostFetchStatePending host data is to be fetched from the host
+	HostFetchStatePending HostFetchState = "Pending"
+	// HostFetchStateRetrying the last fetch failed, the next attempt is delayed with an exponential backoff
+	HostFetchStateRetrying HostFetchState = "Retrying"
+	// HostFetchStateCircuitOpen the host failed too many consecutive fetches, it is not contacted again before the
+	// circuit breaker cool down elapses
+	HostFetchStateCircuitOpen HostFetchState = "CircuitOpen"
+)
+
+// Valid returns true for the known fetch states
+func (state HostFetchState) Valid() bool {
+	switch state {
+	case HostFetchStatePending, HostFetchStateRetrying, HostFetchStateCircuitOpen:
+		return true
+	}
+	return false
+}
+
+// HostFetchQueueEntry is the pending host data fetch of a host along with its retry state
+type HostFetchQueueEntry struct {
+	// swagger:strfmt uuid
+	HostId          uuid.UUID      `json:"host_id"`
+	State           HostFetchState `json:"state"`
+	PreferHashMatch bool           `json:"prefer_hash_match"`
+	// Attempts is the number of consecutive failed fetches
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+type HostFetchQueueEntryCollection struct {
+	Entries []HostFetchQueueEntry `json:"host_fetch_queue" xml:"host_fetch_queue"`
+}
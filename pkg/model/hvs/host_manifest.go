@@ -20,6 +20,7 @@ type HostManifest struct {
 	BindingKeyCertificate string           `json:"binding_key_certificate,omitempty"`
 	MeasurementXmls       []string         `json:"measurement_xmls,omitempty"`
 	QuoteDigest           string           `json:"quote_digest,omitempty"`
+	ImaLog                *ImaLog          `json:"ima_log,omitempty"`
 }
 
 func (hostManifest *HostManifest) GetAIKCertificate() (*x509.Certificate, error) {
@@ -32,4 +32,5 @@ type HostStatus struct {
 // HostStatusCollection holds a collection of HostStatus in response to an API query
 type HostStatusCollection struct {
 	HostStatuses []HostStatus `json:"host_status" xml:"host_status"`
+	Next         string       `json:"next,omitempty" xml:"next,omitempty"`
 }
@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ImaPcrIndex is the PCR extended by the Linux Integrity Measurement Architecture
+	ImaPcrIndex = PCR10
+	// ImaBootAggregate is the file name of the first IMA log entry that holds the digest of the boot PCRs
+	ImaBootAggregate = "boot_aggregate"
+)
+
+// ImaLog is the IMA runtime measurement list (ascii_runtime_measurements) reported by the host
+type ImaLog struct {
+	Pcr          Pcr              `json:"pcr"`
+	Measurements []ImaMeasurement `json:"measurements"`
+}
+
+// ImaMeasurement is an entry of the IMA runtime measurement list
+type ImaMeasurement struct {
+	TemplateHash string `json:"template_hash"`
+	TemplateName string `json:"template_name"`
+	FileHash     string `json:"file_hash"`
+	FileName     string `json:"file_name"`
+}
+
+// Ima section is unique to the IMA flavor type, it lists the file hashes that are allowed or denied in the IMA
+// runtime measurement list of a host. When the allowlist is empty only the denylist is enforced.
+type Ima struct {
+	Allowlist []ImaFileMeasurement `json:"allowlist,omitempty"`
+	Denylist  []ImaFileMeasurement `json:"denylist,omitempty"`
+}
+
+// ImaFileMeasurement is a file hash in the format of the IMA template (ex. 'sha256:<hex>') optionally restricted to
+// a file path. Denylist entries can also consist of a file path only.
+type ImaFileMeasurement struct {
+	FilePath string `json:"file_path,omitempty"`
+	FileHash string `json:"file_hash,omitempty"`
+}
+
+// ParseImaLog parses the IMA ascii runtime measurement list, each line has the format
+// '<pcr> <template hash> <template name> <file hash> <file name>'. The PCR bank is derived from the size of the
+// template hashes.
+func ParseImaLog(asciiRuntimeMeasurements string) (*ImaLog, error) {
+	imaLog := ImaLog{}
+	pcrIndex := -1
+	scanner := bufio.NewScanner(strings.NewReader(asciiRuntimeMeasurements))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 5)
+		if len(fields) < 4 {
+			return nil, errors.Errorf("Invalid IMA measurement at line %d", lineNumber)
+		}
+
+		index, err := strconv.Atoi(fields[0])
+		if err != nil || index < int(PCR0) || index > int(PCR23) {
+			return nil, errors.Errorf("Invalid PCR index in IMA measurement at line %d", lineNumber)
+		}
+		if pcrIndex != -1 && index != pcrIndex {
+			return nil, errors.Errorf("IMA measurement at line %d extends PCR %d instead of PCR %d", lineNumber, index, pcrIndex)
+		}
+		pcrIndex = index
+
+		templateHash := strings.ToLower(fields[1])
+		if _, err := hex.DecodeString(templateHash); err != nil {
+			return nil, errors.Wrapf(err, "Invalid template hash in IMA measurement at line %d", lineNumber)
+		}
+		bank, err := getImaTemplateHashBank(templateHash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid template hash in IMA measurement at line %d", lineNumber)
+		}
+		if imaLog.Pcr.Bank != "" && imaLog.Pcr.Bank != string(bank) {
+			return nil, errors.Errorf("IMA measurement at line %d uses a different template hash algorithm", lineNumber)
+		}
+		imaLog.Pcr.Bank = string(bank)
+
+		measurement := ImaMeasurement{
+			TemplateHash: templateHash,
+			TemplateName: fields[2],
+			FileHash:     strings.ToLower(fields[3]),
+		}
+		if len(fields) == 5 {
+			measurement.FileName = fields[4]
+		}
+		imaLog.Measurements = append(imaLog.Measurements, measurement)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "Error reading IMA measurements")
+	}
+	if len(imaLog.Measurements) == 0 {
+		return nil, errors.New("The IMA measurement list is empty")
+	}
+	imaLog.Pcr.Index = pcrIndex
+	return &imaLog, nil
+}
+
+func getImaTemplateHashBank(templateHash string) (SHAAlgorithm, error) {
+	switch len(templateHash) {
+	case sha1.Size * 2:
+		return SHA1, nil
+	case sha256.Size * 2:
+		return SHA256, nil
+	case sha512.Size384 * 2:
+		return SHA384, nil
+	}
+	return UNKNOWN, errors.Errorf("Unsupported template hash size %d", len(templateHash)/2)
+}
+
+// Replay extends the template hashes of the measurement list and returns the number of leading measurements
+// whose replay matches the expected PCR value, zero if there is no match. The host reads the measurement list
+// after the quote, so additional measurements at the end of the list are expected. The final calculated value is
+// also returned.
+func (imaLog *ImaLog) Replay(expectedPcrValue string) (int, string, error) {
+	var hasher hash.Hash
+	switch SHAAlgorithm(imaLog.Pcr.Bank) {
+	case SHA1:
+		hasher = sha1.New()
+	case SHA256:
+		hasher = sha256.New()
+	case SHA384:
+		hasher = sha512.New384()
+	default:
+		return 0, "", errors.Errorf("Unsupported IMA log bank '%s'", imaLog.Pcr.Bank)
+	}
+
+	expectedPcrValue = strings.ToLower(expectedPcrValue)
+	cumulativeHash := make([]byte, hasher.Size())
+	matchedMeasurements := 0
+	for i, measurement := range imaLog.Measurements {
+		templateHash, err := hex.DecodeString(measurement.TemplateHash)
+		if err != nil || len(templateHash) != hasher.Size() {
+			return 0, "", errors.Errorf("Invalid template hash in IMA measurement %d", i)
+		}
+		// measurement violations are logged with a zero template hash but extended with all bits set
+		if isZeroDigest(templateHash) {
+			for j := range templateHash {
+				templateHash[j] = 0xff
+			}
+		}
+		hasher.Reset()
+		hasher.Write(cumulativeHash)
+		hasher.Write(templateHash)
+		cumulativeHash = hasher.Sum(nil)
+		if hex.EncodeToString(cumulativeHash) == expectedPcrValue {
+			matchedMeasurements = i + 1
+		}
+	}
+	return matchedMeasurements, hex.EncodeToString(cumulativeHash), nil
+}
+
+func isZeroDigest(digest []byte) bool {
+	for _, b := range digest {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
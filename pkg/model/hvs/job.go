@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobType identifies the long running operation processed by a job
+type JobType string
+
+const (
+	JobTypeHostBulkCreate      JobType = "HOST_BULK_CREATE"
+	JobTypeFlavorCreate        JobType = "FLAVOR_CREATE"
+	JobTypeReportCreate        JobType = "REPORT_CREATE"
+	JobTypeTagDeploy           JobType = "TAG_CERTIFICATE_DEPLOY"
+	JobTypeHostVerify          JobType = "HOST_VERIFY"
+	JobTypeFlavorgroupSimulate JobType = "FLAVORGROUP_SIMULATE"
+)
+
+// JobStatus is the processing status of a job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "Pending"
+	JobStatusRunning   JobStatus = "Running"
+	JobStatusCompleted JobStatus = "Completed"
+	JobStatusFailed    JobStatus = "Failed"
+	JobStatusCancelled JobStatus = "Cancelled"
+)
+
+// Valid returns true for the known job statuses
+func (status JobStatus) Valid() bool {
+	switch status {
+	case JobStatusPending, JobStatusRunning, JobStatusCompleted, JobStatusFailed, JobStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Done returns true once the job has stopped, whatever its outcome
+func (status JobStatus) Done() bool {
+	return status == JobStatusCompleted || status == JobStatusFailed || status == JobStatusCancelled
+}
+
+// Job reports the progress and the outcome of a long running operation. While the job is running, Result holds the
+// partial result reported so far, if any. Owner identifies the service instance running the job.
+type Job struct {
+	// swagger:strfmt uuid
+	Id        uuid.UUID       `json:"id"`
+	Type      JobType         `json:"type"`
+	Status    JobStatus       `json:"status"`
+	Processed int             `json:"processed"`
+	Total     int             `json:"total"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Owner     string          `json:"-"`
+	Created   time.Time       `json:"created"`
+	Updated   time.Time       `json:"updated"`
+}
+
+// JobCollection holds the jobs returned by a job search
+type JobCollection struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// HostVerifyResult is the outcome of the verification of a single host, the result of the HOST_VERIFY job holds one
+// per verified host
+type HostVerifyResult struct {
+	// swagger:strfmt uuid
+	HostId  uuid.UUID `json:"host_id"`
+	Trusted *bool     `json:"trusted,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Labels are the key/value pairs attached to a host to organize hosts, for example rack=r12 or env=prod. The keys and
+// values follow the syntax of Kubernetes labels, a key is a name with an optional DNS subdomain prefix, such as
+// example.com/rack.
+type Labels map[string]string
+
+const (
+	maxLabelNameLength   = 63
+	maxLabelPrefixLength = 253
+)
+
+var (
+	labelNameRegex   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	labelPrefixRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// Validate checks the syntax of the keys and values of the labels
+func (labels Labels) Validate() error {
+	for key, value := range labels {
+		if err := validateLabelKey(key); err != nil {
+			return err
+		}
+		if err := validateLabelValue(value); err != nil {
+			return errors.Wrapf(err, "Invalid value of label %s", key)
+		}
+	}
+	return nil
+}
+
+func validateLabelKey(key string) error {
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) == 0 || len(prefix) > maxLabelPrefixLength || !labelPrefixRegex.MatchString(prefix) {
+			return errors.Errorf("Invalid label key %q, the prefix must be a DNS subdomain", key)
+		}
+	}
+	if len(name) == 0 || len(name) > maxLabelNameLength || !labelNameRegex.MatchString(name) {
+		return errors.Errorf("Invalid label key %q, the name must be 63 characters or less, start and end with an "+
+			"alphanumeric character and only contain alphanumerics, '-', '_' or '.'", key)
+	}
+	return nil
+}
+
+func validateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxLabelNameLength || !labelNameRegex.MatchString(value) {
+		return errors.Errorf("Invalid label value %q, it must be 63 characters or less, start and end with an "+
+			"alphanumeric character and only contain alphanumerics, '-', '_' or '.'", value)
+	}
+	return nil
+}
+
+// SelectorOperator is the operator of a label selector requirement
+type SelectorOperator string
+
+const (
+	SelectorOpEquals       SelectorOperator = "="
+	SelectorOpNotEquals    SelectorOperator = "!="
+	SelectorOpIn           SelectorOperator = "in"
+	SelectorOpNotIn        SelectorOperator = "notin"
+	SelectorOpExists       SelectorOperator = "exists"
+	SelectorOpDoesNotExist SelectorOperator = "!"
+)
+
+// LabelRequirement is a condition on one label of a host. The equality and set operators match the hosts with the
+// label set to one of the values, the inequality operators also match the hosts without the label.
+type LabelRequirement struct {
+	Key      string
+	Operator SelectorOperator
+	Values   []string
+}
+
+// LabelSelector selects the hosts whose labels meet all of its requirements. An empty selector matches every host.
+type LabelSelector []LabelRequirement
+
+var setRequirementRegex = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// ParseLabelSelector parses a comma separated list of requirements with the syntax of Kubernetes label selectors:
+//
+//	key=value, key==value, key!=value   equality and inequality
+//	key in (v1,v2), key notin (v1,v2)   set membership
+//	key, !key                           existence
+//
+// For example, "env=prod,rack!=r3" selects the production hosts that are not in rack r3.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var requirements LabelSelector
+	for _, term := range splitSelectorTerms(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, errors.Errorf("Invalid label selector %q, empty requirement", selector)
+		}
+		requirement, err := parseLabelRequirement(term)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid label selector %q", selector)
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, nil
+}
+
+// splitSelectorTerms splits a selector on the commas that are not within the values of a set requirement
+func splitSelectorTerms(selector string) []string {
+	if strings.TrimSpace(selector) == "" {
+		return nil
+	}
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+func parseLabelRequirement(term string) (LabelRequirement, error) {
+	var requirement LabelRequirement
+	if match := setRequirementRegex.FindStringSubmatch(term); match != nil {
+		requirement = LabelRequirement{Key: match[1], Operator: SelectorOperator(match[2])}
+		for _, value := range strings.Split(match[3], ",") {
+			requirement.Values = append(requirement.Values, strings.TrimSpace(value))
+		}
+	} else if strings.HasPrefix(term, "!") {
+		requirement = LabelRequirement{Key: strings.TrimSpace(term[1:]), Operator: SelectorOpDoesNotExist}
+	} else if i := strings.Index(term, "!="); i >= 0 {
+		requirement = LabelRequirement{Key: strings.TrimSpace(term[:i]), Operator: SelectorOpNotEquals,
+			Values: []string{strings.TrimSpace(term[i+2:])}}
+	} else if i := strings.Index(term, "="); i >= 0 {
+		value := strings.TrimPrefix(term[i+1:], "=")
+		requirement = LabelRequirement{Key: strings.TrimSpace(term[:i]), Operator: SelectorOpEquals,
+			Values: []string{strings.TrimSpace(value)}}
+	} else {
+		requirement = LabelRequirement{Key: term, Operator: SelectorOpExists}
+	}
+
+	if err := validateLabelKey(requirement.Key); err != nil {
+		return requirement, err
+	}
+	for _, value := range requirement.Values {
+		if err := validateLabelValue(value); err != nil {
+			return requirement, err
+		}
+	}
+	return requirement, nil
+}
+
+// Matches returns true when the labels meet the requirement
+func (requirement LabelRequirement) Matches(labels Labels) bool {
+	value, exists := labels[requirement.Key]
+	switch requirement.Operator {
+	case SelectorOpExists:
+		return exists
+	case SelectorOpDoesNotExist:
+		return !exists
+	case SelectorOpEquals, SelectorOpIn:
+		return exists && requirement.hasValue(value)
+	case SelectorOpNotEquals, SelectorOpNotIn:
+		return !exists || !requirement.hasValue(value)
+	}
+	return false
+}
+
+func (requirement LabelRequirement) hasValue(value string) bool {
+	for _, v := range requirement.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the requirement in the label selector syntax
+func (requirement LabelRequirement) String() string {
+	switch requirement.Operator {
+	case SelectorOpExists:
+		return requirement.Key
+	case SelectorOpDoesNotExist:
+		return "!" + requirement.Key
+	case SelectorOpIn, SelectorOpNotIn:
+		values := append([]string{}, requirement.Values...)
+		sort.Strings(values)
+		return requirement.Key + " " + string(requirement.Operator) + " (" + strings.Join(values, ",") + ")"
+	}
+	return requirement.Key + string(requirement.Operator) + strings.Join(requirement.Values, ",")
+}
+
+// Matches returns true when the labels meet all the requirements of the selector
+func (selector LabelSelector) Matches(labels Labels) bool {
+	for _, requirement := range selector {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the label selector syntax
+func (selector LabelSelector) String() string {
+	terms := make([]string, len(selector))
+	for i, requirement := range selector {
+		terms[i] = requirement.String()
+	}
+	return strings.Join(terms, ",")
+}
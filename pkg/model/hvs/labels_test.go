@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelsValidate(t *testing.T) {
+	assert.NoError(t, Labels{}.Validate())
+	assert.NoError(t, Labels{"rack": "r12", "env": "prod", "example.com/tier": "", "zone_1.a": "us-west-2a"}.Validate())
+	assert.Error(t, Labels{"": "prod"}.Validate())
+	assert.Error(t, Labels{"-env": "prod"}.Validate())
+	assert.Error(t, Labels{"Example.com/env": "prod"}.Validate())
+	assert.Error(t, Labels{"/env": "prod"}.Validate())
+	assert.Error(t, Labels{"env": "prod line"}.Validate())
+	assert.Error(t, Labels{"env": "prod'"}.Validate())
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := ParseLabelSelector("env=prod, rack!=r3,tier==web,zone in (a, b),owner notin (x),gpu,!maintenance")
+	assert.NoError(t, err)
+	assert.Equal(t, LabelSelector{
+		{Key: "env", Operator: SelectorOpEquals, Values: []string{"prod"}},
+		{Key: "rack", Operator: SelectorOpNotEquals, Values: []string{"r3"}},
+		{Key: "tier", Operator: SelectorOpEquals, Values: []string{"web"}},
+		{Key: "zone", Operator: SelectorOpIn, Values: []string{"a", "b"}},
+		{Key: "owner", Operator: SelectorOpNotIn, Values: []string{"x"}},
+		{Key: "gpu", Operator: SelectorOpExists},
+		{Key: "maintenance", Operator: SelectorOpDoesNotExist},
+	}, selector)
+	assert.Equal(t, "env=prod,rack!=r3,tier=web,zone in (a,b),owner notin (x),gpu,!maintenance", selector.String())
+
+	selector, err = ParseLabelSelector("")
+	assert.NoError(t, err)
+	assert.Empty(t, selector)
+
+	for _, invalid := range []string{"env=prod,", "env=prod value", "env in (a,b", "=prod", "!", "env=pr'od", "env=(a,b)"} {
+		_, err = ParseLabelSelector(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := Labels{"env": "prod", "rack": "r12"}
+	for selector, matches := range map[string]bool{
+		"":                       true,
+		"env=prod":               true,
+		"env=prod,rack!=r3":      true,
+		"env=prod,rack!=r12":     false,
+		"env=dev":                false,
+		"rack in (r11,r12)":      true,
+		"rack notin (r11,r12)":   false,
+		"zone!=a":                true,
+		"zone notin (a)":         true,
+		"zone in (a)":            false,
+		"env":                    true,
+		"!env":                   false,
+		"!zone":                  true,
+		"env=prod,zone,rack=r12": false,
+	} {
+		parsed, err := ParseLabelSelector(selector)
+		assert.NoError(t, err)
+		assert.Equal(t, matches, parsed.Matches(labels), selector)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// RefreshSchedule is the report refresh policy of a flavor group, or the override of a host. Hosts with a schedule
+// are re-attested by the HRRS once their report is older than the interval, in addition to the refresh of expired
+// reports.
+type RefreshSchedule struct {
+	// IntervalSeconds is the maximum age of a host report before the host is re-attested, zero disables the
+	// scheduled refresh
+	IntervalSeconds int `json:"interval_seconds,omitempty"`
+	// JitterSeconds spreads the refresh of the hosts sharing a schedule, each host is delayed by a stable offset
+	// between zero and the jitter
+	JitterSeconds int `json:"jitter_seconds,omitempty"`
+	// RefreshOnEvent re-attests the hosts with new host data when the flavors that apply to them change, instead of
+	// verifying the last host manifest
+	RefreshOnEvent bool `json:"refresh_on_event,omitempty"`
+	// Disabled turns off both the scheduled and the event driven refresh of a host, whatever the schedules of its
+	// flavor groups. Only the override of a host can be disabled.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Validate checks that the intervals of the schedule are not negative and the jitter does not exceed the interval
+func (schedule *RefreshSchedule) Validate() error {
+	if schedule.Disabled && (schedule.IntervalSeconds != 0 || schedule.JitterSeconds != 0 || schedule.RefreshOnEvent) {
+		return errors.New("A disabled refresh schedule cannot set an interval, a jitter or the refresh on event")
+	}
+	if schedule.IntervalSeconds < 0 || schedule.JitterSeconds < 0 {
+		return errors.New("The refresh interval and jitter cannot be negative")
+	}
+	if schedule.JitterSeconds > schedule.IntervalSeconds {
+		return errors.New("The refresh jitter cannot exceed the refresh interval")
+	}
+	return nil
+}
+
+// IsEmpty returns true when the schedule is unset, it neither sets an interval nor the refresh on event flag and is
+// not disabled
+func (schedule *RefreshSchedule) IsEmpty() bool {
+	return schedule == nil || (schedule.IntervalSeconds == 0 && !schedule.RefreshOnEvent && !schedule.Disabled)
+}
+
+// Interval returns the refresh interval as a duration
+func (schedule *RefreshSchedule) Interval() time.Duration {
+	return time.Duration(schedule.IntervalSeconds) * time.Second
+}
+
+// JitterOffset returns the offset of the host within the jitter of the schedule. The offset is derived from the host
+// id, so it does not change between refreshes or restarts of HVS.
+func (schedule *RefreshSchedule) JitterOffset(hostId uuid.UUID) time.Duration {
+	if schedule.JitterSeconds <= 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write(hostId[:])
+	return time.Duration(hash.Sum32()%uint32(schedule.JitterSeconds+1)) * time.Second
+}
+
+// GetEffectiveRefreshSchedule returns the schedule that applies to a host. The override of the host takes
+// precedence, including a disabled one, otherwise the schedule of the flavor group with the shortest interval applies.
+// The refresh on event flag of the flavor groups applies if any of them sets it.
+func GetEffectiveRefreshSchedule(hostSchedule *RefreshSchedule, flavorgroupSchedules []*RefreshSchedule) *RefreshSchedule {
+	if !hostSchedule.IsEmpty() {
+		return hostSchedule
+	}
+
+	var effective *RefreshSchedule
+	refreshOnEvent := false
+	for _, schedule := range flavorgroupSchedules {
+		if schedule.IsEmpty() {
+			continue
+		}
+		refreshOnEvent = refreshOnEvent || schedule.RefreshOnEvent
+		if schedule.IntervalSeconds > 0 && (effective == nil || effective.IntervalSeconds == 0 ||
+			schedule.IntervalSeconds < effective.IntervalSeconds) {
+			copied := *schedule
+			effective = &copied
+		} else if effective == nil {
+			effective = &RefreshSchedule{}
+		}
+	}
+	if effective != nil {
+		effective.RefreshOnEvent = refreshOnEvent
+	}
+	return effective
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshScheduleValidate(t *testing.T) {
+	assert.NoError(t, (&RefreshSchedule{}).Validate())
+	assert.NoError(t, (&RefreshSchedule{IntervalSeconds: 3600, JitterSeconds: 3600}).Validate())
+	assert.Error(t, (&RefreshSchedule{IntervalSeconds: -1}).Validate())
+	assert.Error(t, (&RefreshSchedule{IntervalSeconds: 60, JitterSeconds: -1}).Validate())
+	assert.Error(t, (&RefreshSchedule{IntervalSeconds: 60, JitterSeconds: 61}).Validate())
+	assert.NoError(t, (&RefreshSchedule{Disabled: true}).Validate())
+	assert.Error(t, (&RefreshSchedule{Disabled: true, IntervalSeconds: 60}).Validate())
+	assert.Error(t, (&RefreshSchedule{Disabled: true, RefreshOnEvent: true}).Validate())
+}
+
+func TestRefreshScheduleJitterOffset(t *testing.T) {
+	hostId := uuid.New()
+	schedule := RefreshSchedule{IntervalSeconds: 600, JitterSeconds: 60}
+
+	offset := schedule.JitterOffset(hostId)
+	assert.True(t, offset >= 0 && offset <= time.Minute)
+	assert.Equal(t, offset, schedule.JitterOffset(hostId))
+	assert.Equal(t, time.Duration(0), (&RefreshSchedule{IntervalSeconds: 600}).JitterOffset(hostId))
+}
+
+func TestGetEffectiveRefreshSchedule(t *testing.T) {
+	hourly := &RefreshSchedule{IntervalSeconds: 3600, JitterSeconds: 60}
+	daily := &RefreshSchedule{IntervalSeconds: 86400}
+	onEvent := &RefreshSchedule{RefreshOnEvent: true}
+
+	// no schedule at all
+	assert.Nil(t, GetEffectiveRefreshSchedule(nil, []*RefreshSchedule{nil, {}}))
+
+	// the override of the host wins
+	override := &RefreshSchedule{IntervalSeconds: 60}
+	assert.Equal(t, override, GetEffectiveRefreshSchedule(override, []*RefreshSchedule{hourly}))
+
+	// the shortest interval of the flavor groups applies and refresh on event is set by any of them
+	effective := GetEffectiveRefreshSchedule(&RefreshSchedule{}, []*RefreshSchedule{daily, onEvent, hourly, nil})
+	assert.Equal(t, &RefreshSchedule{IntervalSeconds: 3600, JitterSeconds: 60, RefreshOnEvent: true}, effective)
+	assert.False(t, hourly.RefreshOnEvent)
+
+	// refresh on event without an interval
+	assert.Equal(t, &RefreshSchedule{RefreshOnEvent: true}, GetEffectiveRefreshSchedule(nil, []*RefreshSchedule{onEvent}))
+
+	// a disabled override turns off the refresh of the host, an unset one does not
+	disabled := &RefreshSchedule{Disabled: true}
+	assert.False(t, disabled.IsEmpty())
+	effective = GetEffectiveRefreshSchedule(disabled, []*RefreshSchedule{hourly, onEvent})
+	assert.Equal(t, 0, effective.IntervalSeconds)
+	assert.False(t, effective.RefreshOnEvent)
+	assert.Equal(t, hourly.IntervalSeconds, GetEffectiveRefreshSchedule(&RefreshSchedule{}, []*RefreshSchedule{hourly}).IntervalSeconds)
+}
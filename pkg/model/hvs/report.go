@@ -14,6 +14,7 @@ import (
 
 type ReportCollection struct {
 	Reports []*Report `json:"reports" xml:"reports"`
+	Next    string    `json:"next,omitempty" xml:"next,omitempty"`
 }
 
 type Report struct {
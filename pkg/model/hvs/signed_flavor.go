@@ -18,10 +18,12 @@ import (
  * @author mullas
  */
 
-// SignedFlavor combines the Flavor along with the cryptographically signed hash that authenticates its source
+// SignedFlavor combines the Flavor along with the cryptographically signed hash that authenticates its source.
+// The lifecycle of a stored flavor is not covered by the signature.
 type SignedFlavor struct {
-	Flavor    Flavor `json:"flavor"`
-	Signature string `json:"signature"`
+	Flavor    Flavor           `json:"flavor"`
+	Signature string           `json:"signature"`
+	Lifecycle *FlavorLifecycle `json:"lifecycle,omitempty"`
 }
 
 // NewSignedFlavor Provided an existing flavor and a privatekey, create a SignedFlavor
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HostEventType is the type of a host event that subscribers can be notified of
+type HostEventType string
+
+const (
+	// HostEventTrustChanged the overall trust status of a host changed, or the host got its first report
+	HostEventTrustChanged HostEventType = "trust_changed"
+	// HostEventConnectionFailure the host data could not be fetched from the host
+	HostEventConnectionFailure HostEventType = "connection_failure"
+	// HostEventNewReport a new trust report was created for a host
+	HostEventNewReport HostEventType = "new_report"
+)
+
+// Valid returns true for the known host event types
+func (eventType HostEventType) Valid() bool {
+	switch eventType {
+	case HostEventTrustChanged, HostEventConnectionFailure, HostEventNewReport:
+		return true
+	}
+	return false
+}
+
+// SubscriptionDeliveryType is the way the events are delivered to a subscriber
+type SubscriptionDeliveryType string
+
+const (
+	// SubscriptionDeliveryWebhook the events are posted to an HTTPS endpoint
+	SubscriptionDeliveryWebhook SubscriptionDeliveryType = "webhook"
+	// SubscriptionDeliveryNats the events are published on a NATS subject
+	SubscriptionDeliveryNats SubscriptionDeliveryType = "nats"
+)
+
+// Valid returns true for the known delivery types
+func (deliveryType SubscriptionDeliveryType) Valid() bool {
+	return deliveryType == SubscriptionDeliveryWebhook || deliveryType == SubscriptionDeliveryNats
+}
+
+// SubscriptionFilter restricts the events delivered to a subscriber. An empty list matches everything.
+type SubscriptionFilter struct {
+	HostIds        []uuid.UUID     `json:"host_ids,omitempty"`
+	FlavorgroupIds []uuid.UUID     `json:"flavorgroup_ids,omitempty"`
+	EventTypes     []HostEventType `json:"event_types,omitempty"`
+}
+
+// Subscription registers an endpoint that is notified of host events
+type Subscription struct {
+	// swagger:strfmt uuid
+	Id           uuid.UUID                `json:"id,omitempty"`
+	DeliveryType SubscriptionDeliveryType `json:"delivery_type"`
+	// Endpoint is the HTTPS URL of a webhook or the subject of a NATS subscription, under hvs.events
+	Endpoint string             `json:"endpoint"`
+	Filter   SubscriptionFilter `json:"filter"`
+	// Secret is the key of the HMAC-SHA256 signature of the deliveries. It is only returned when the subscription
+	// is created.
+	Secret  string    `json:"secret,omitempty"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+type SubscriptionCollection struct {
+	Subscriptions []Subscription `json:"subscriptions" xml:"subscriptions"`
+}
+
+// HostEvent is the payload delivered to the subscribers
+type HostEvent struct {
+	// swagger:strfmt uuid
+	Id   uuid.UUID     `json:"id"`
+	Type HostEventType `json:"event_type"`
+	// swagger:strfmt uuid
+	HostId   uuid.UUID `json:"host_id"`
+	HostName string    `json:"host_name,omitempty"`
+	// FlavorgroupIds are the flavor groups of the host when the event occurred
+	FlavorgroupIds []uuid.UUID `json:"flavorgroup_ids,omitempty"`
+	Trusted        *bool       `json:"trusted,omitempty"`
+	// PreviousTrusted is the trust status of the previous report, it is not set for the first report of a host
+	PreviousTrusted *bool      `json:"previous_trusted,omitempty"`
+	ReportId        *uuid.UUID `json:"report_id,omitempty"`
+	HostState       string     `json:"host_state,omitempty"`
+	Created         time.Time  `json:"created"`
+}
+
+// Matches returns true if the event passes the filter
+func (filter SubscriptionFilter) Matches(event *HostEvent) bool {
+	if len(filter.EventTypes) > 0 && !containsEventType(filter.EventTypes, event.Type) {
+		return false
+	}
+	if len(filter.HostIds) > 0 && !containsUUID(filter.HostIds, event.HostId) {
+		return false
+	}
+	if len(filter.FlavorgroupIds) > 0 {
+		for _, fgId := range event.FlavorgroupIds {
+			if containsUUID(filter.FlavorgroupIds, fgId) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsEventType(eventTypes []HostEventType, eventType HostEventType) bool {
+	for _, t := range eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
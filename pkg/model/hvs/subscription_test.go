@@ -0,0 +1,32 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionFilterMatches(t *testing.T) {
+	hostId := uuid.New()
+	fgId := uuid.New()
+	event := HostEvent{Type: HostEventTrustChanged, HostId: hostId, FlavorgroupIds: []uuid.UUID{uuid.New(), fgId}}
+
+	assert.True(t, SubscriptionFilter{}.Matches(&event))
+	assert.True(t, SubscriptionFilter{EventTypes: []HostEventType{HostEventNewReport, HostEventTrustChanged}}.Matches(&event))
+	assert.False(t, SubscriptionFilter{EventTypes: []HostEventType{HostEventConnectionFailure}}.Matches(&event))
+	assert.True(t, SubscriptionFilter{HostIds: []uuid.UUID{hostId}}.Matches(&event))
+	assert.False(t, SubscriptionFilter{HostIds: []uuid.UUID{uuid.New()}}.Matches(&event))
+	assert.True(t, SubscriptionFilter{FlavorgroupIds: []uuid.UUID{fgId}}.Matches(&event))
+	assert.False(t, SubscriptionFilter{FlavorgroupIds: []uuid.UUID{uuid.New()}}.Matches(&event))
+
+	// all the criteria have to match
+	assert.False(t, SubscriptionFilter{
+		HostIds:    []uuid.UUID{hostId},
+		EventTypes: []HostEventType{HostEventNewReport},
+	}.Matches(&event))
+}
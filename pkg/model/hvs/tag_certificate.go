@@ -30,6 +30,7 @@ type TagCertificate struct {
 // TagCertificateCollection is the response sent by the tag-certificate API
 type TagCertificateCollection struct {
 	TagCertificates []*TagCertificate `json:"certificates" xml:"certificates"`
+	Next            string            `json:"next,omitempty" xml:"next,omitempty"`
 }
 
 // SetAssetTagDigest computes the hash of the Asset Tag certificate
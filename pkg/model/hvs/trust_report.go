@@ -53,11 +53,16 @@ type RuleInfo struct {
 	Exclude_Tags             []string               `json:"excluding_tag,omitempty"`
 	ExpectedTag              []byte                 `json:"expected_tag,omitempty"`
 	Tags                     map[string]string      `json:"tags,omitempty"`
+	CustomRule               *CustomRule            `json:"custom_rule,omitempty"`
 }
 
+// FaultSeverityWarning marks the faults that are reported without making the host untrusted
+const FaultSeverityWarning = "warning"
+
 type Fault struct {
 	Name                   string                 `json:"fault_name"`
 	Description            string                 `json:"description"`
+	Severity               string                 `json:"severity,omitempty"`
 	PcrIndex               *PcrIndex              `json:"pcr_index,omitempty"`
 	PcrBank                *SHAAlgorithm          `json:"pcr_bank,omitempty"`
 	ExpectedPcrValue       *string                `json:"expected_pcrvalue,omitempty"`
@@ -75,6 +80,7 @@ type Fault struct {
 	MeasurementId          *string                `json:"measurement_id,omitempty"`
 	FlavorDigestAlg        *string                `json:"flavor_digest_alg,omitempty"`
 	MeasurementDigestAlg   *string                `json:"measurement_digest_alg,omitempty"`
+	ImaMeasurement         *ImaMeasurement        `json:"ima_measurement,omitempty"`
 }
 
 func NewTrustReport(report TrustReport) *TrustReport {
@@ -138,6 +144,15 @@ func (t *TrustReport) CheckResultExists(targetRuleResult RuleResult) bool {
 				} else {
 					continue
 				}
+			case constants.RuleCustomRuleMatches:
+				// Several custom rules can be defined in one flavor part, they are only the same when their names match
+				if targetRuleResult.Rule.CustomRule == nil || ruleResult.Rule.CustomRule == nil {
+					return false
+				} else if targetRuleResult.Rule.CustomRule.Name == ruleResult.Rule.CustomRule.Name {
+					return true
+				} else {
+					continue
+				}
 			default:
 				if len(targetRuleResult.Faults) > 0 {
 					return false
@@ -178,8 +193,15 @@ func (r *RuleResult) equals(target RuleResult) bool {
 }
 
 func (r *RuleResult) IsTrusted() bool {
-	if r.Faults == nil || len(r.Faults) == 0 {
-		return true
+	for _, fault := range r.Faults {
+		if !fault.IsWarning() {
+			return false
+		}
 	}
-	return false
+	return true
+}
+
+// IsWarning returns true when the fault does not make the host untrusted
+func (f *Fault) IsWarning() bool {
+	return f.Severity == FaultSeverityWarning
 }
@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of rule result changes between two trust reports
+const (
+	RuleResultAdded   = "added"
+	RuleResultRemoved = "removed"
+	RuleResultChanged = "changed"
+)
+
+// TrustReportDiff describes the differences of a trust report compared to an earlier ('against') report of the
+// same host
+type TrustReportDiff struct {
+	// swagger:strfmt uuid
+	ReportID uuid.UUID `json:"report_id"`
+	// swagger:strfmt uuid
+	AgainstReportID uuid.UUID `json:"against_report_id"`
+	// swagger:strfmt uuid
+	HostID           uuid.UUID        `json:"host_id"`
+	CreatedAt        time.Time        `json:"created"`
+	AgainstCreatedAt time.Time        `json:"against_created"`
+	Trusted          bool             `json:"trusted"`
+	AgainstTrusted   bool             `json:"against_trusted"`
+	FlavorParts      []FlavorPartDiff `json:"flavor_parts,omitempty"`
+	PcrValues        []PcrValueDiff   `json:"pcr_values,omitempty"`
+	EventLogs        []EventLogDiff   `json:"event_logs,omitempty"`
+}
+
+// FlavorPartDiff lists the rule results of a flavor part that changed between the reports
+type FlavorPartDiff struct {
+	FlavorPart     string           `json:"flavor_part"`
+	Trusted        bool             `json:"trusted"`
+	AgainstTrusted bool             `json:"against_trusted"`
+	RuleResults    []RuleResultDiff `json:"rule_results"`
+}
+
+// RuleResultDiff holds a rule result that was added, removed or changed its trust status or faults
+type RuleResultDiff struct {
+	RuleName      string      `json:"rule_name"`
+	Change        string      `json:"change"`
+	Result        *RuleResult `json:"result,omitempty"`
+	AgainstResult *RuleResult `json:"against_result,omitempty"`
+}
+
+// PcrValueDiff holds the values of a PCR that differs between the host manifests of the reports, an empty value
+// means the PCR was not part of the manifest
+type PcrValueDiff struct {
+	PcrIndex     PcrIndex     `json:"pcr_index"`
+	PcrBank      SHAAlgorithm `json:"pcr_bank"`
+	Value        string       `json:"value,omitempty"`
+	AgainstValue string       `json:"against_value,omitempty"`
+}
+
+// EventLogDiff lists the events of a PCR that were added to or removed from the event log
+type EventLogDiff struct {
+	Pcr           Pcr        `json:"pcr"`
+	AddedEvents   []EventLog `json:"added_events,omitempty"`
+	RemovedEvents []EventLog `json:"removed_events,omitempty"`
+}
+
+// Diff compares the trust report with an earlier report of the same host
+func (t *TrustReport) Diff(against *TrustReport) *TrustReportDiff {
+	diff := TrustReportDiff{
+		Trusted:        t.Trusted,
+		AgainstTrusted: against.Trusted,
+	}
+
+	diff.FlavorParts = diffRuleResults(t, against)
+	diff.PcrValues = diffPcrValues(&t.HostManifest.PcrManifest, &against.HostManifest.PcrManifest)
+	diff.EventLogs = diffEventLogs(&t.HostManifest.PcrManifest.PcrEventLogMap, &against.HostManifest.PcrManifest.PcrEventLogMap)
+	return &diff
+}
+
+// ruleResultKey identifies a rule result across reports. Flavor IDs are not part of the key so that results are
+// still matched after flavors were replaced.
+func ruleResultKey(result *RuleResult) string {
+	key := result.Rule.Name
+	for _, marker := range result.Rule.Markers {
+		key += "|" + marker.String()
+	}
+	if result.Rule.ExpectedPcr != nil {
+		key += fmt.Sprintf("|%s:%d", result.Rule.ExpectedPcr.Pcr.Bank, result.Rule.ExpectedPcr.Pcr.Index)
+	} else if result.Rule.ExpectedPcrEventLogEntry != nil {
+		key += fmt.Sprintf("|%s:%d", result.Rule.ExpectedPcrEventLogEntry.Pcr.Bank, result.Rule.ExpectedPcrEventLogEntry.Pcr.Index)
+	}
+	if result.Rule.CustomRule != nil {
+		key += "|" + result.Rule.CustomRule.Name
+	}
+	return key
+}
+
+func ruleResultMarker(result *RuleResult) string {
+	if len(result.Rule.Markers) == 0 {
+		return ""
+	}
+	return result.Rule.Markers[0].String()
+}
+
+func faultNames(result *RuleResult) string {
+	var names []string
+	for _, fault := range result.Faults {
+		names = append(names, fault.Name+":"+fault.Description)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func diffRuleResults(report, against *TrustReport) []FlavorPartDiff {
+	againstResults := make(map[string]*RuleResult)
+	for i := range against.Results {
+		againstResults[ruleResultKey(&against.Results[i])] = &against.Results[i]
+	}
+
+	ruleDiffs := make(map[string][]RuleResultDiff)
+	var markers []string
+	addRuleDiff := func(marker string, ruleDiff RuleResultDiff) {
+		if _, ok := ruleDiffs[marker]; !ok {
+			markers = append(markers, marker)
+		}
+		ruleDiffs[marker] = append(ruleDiffs[marker], ruleDiff)
+	}
+
+	matched := make(map[string]bool)
+	for i := range report.Results {
+		result := &report.Results[i]
+		key := ruleResultKey(result)
+		againstResult, ok := againstResults[key]
+		if !ok {
+			addRuleDiff(ruleResultMarker(result), RuleResultDiff{RuleName: result.Rule.Name, Change: RuleResultAdded, Result: result})
+			continue
+		}
+		matched[key] = true
+		if result.IsTrusted() != againstResult.IsTrusted() || faultNames(result) != faultNames(againstResult) {
+			addRuleDiff(ruleResultMarker(result), RuleResultDiff{RuleName: result.Rule.Name, Change: RuleResultChanged,
+				Result: result, AgainstResult: againstResult})
+		}
+	}
+	for i := range against.Results {
+		againstResult := &against.Results[i]
+		if !matched[ruleResultKey(againstResult)] {
+			addRuleDiff(ruleResultMarker(againstResult), RuleResultDiff{RuleName: againstResult.Rule.Name,
+				Change: RuleResultRemoved, AgainstResult: againstResult})
+		}
+	}
+
+	var flavorPartDiffs []FlavorPartDiff
+	for _, marker := range markers {
+		flavorPartDiffs = append(flavorPartDiffs, FlavorPartDiff{
+			FlavorPart:     marker,
+			Trusted:        report.IsTrustedForMarker(marker),
+			AgainstTrusted: against.IsTrustedForMarker(marker),
+			RuleResults:    ruleDiffs[marker],
+		})
+	}
+	return flavorPartDiffs
+}
+
+func diffPcrValues(pcrManifest, againstPcrManifest *PcrManifest) []PcrValueDiff {
+	type pcrKey struct {
+		bank  SHAAlgorithm
+		index PcrIndex
+	}
+	values := make(map[pcrKey]*PcrValueDiff)
+	var keys []pcrKey
+	collect := func(pcrs []HostManifestPcrs, against bool) {
+		for _, pcr := range pcrs {
+			key := pcrKey{bank: pcr.PcrBank, index: pcr.Index}
+			value, ok := values[key]
+			if !ok {
+				value = &PcrValueDiff{PcrIndex: pcr.Index, PcrBank: pcr.PcrBank}
+				values[key] = value
+				keys = append(keys, key)
+			}
+			if against {
+				value.AgainstValue = pcr.Value
+			} else {
+				value.Value = pcr.Value
+			}
+		}
+	}
+	collect(pcrManifest.Sha1Pcrs, false)
+	collect(pcrManifest.Sha256Pcrs, false)
+	collect(pcrManifest.Sha384Pcrs, false)
+	collect(againstPcrManifest.Sha1Pcrs, true)
+	collect(againstPcrManifest.Sha256Pcrs, true)
+	collect(againstPcrManifest.Sha384Pcrs, true)
+
+	var pcrDiffs []PcrValueDiff
+	for _, key := range keys {
+		if values[key].Value != values[key].AgainstValue {
+			pcrDiffs = append(pcrDiffs, *values[key])
+		}
+	}
+	sort.SliceStable(pcrDiffs, func(i, j int) bool {
+		if pcrDiffs[i].PcrBank != pcrDiffs[j].PcrBank {
+			return pcrDiffs[i].PcrBank < pcrDiffs[j].PcrBank
+		}
+		return pcrDiffs[i].PcrIndex < pcrDiffs[j].PcrIndex
+	})
+	return pcrDiffs
+}
+
+func diffEventLogs(eventLogMap, againstEventLogMap *PcrEventLogMap) []EventLogDiff {
+	var eventLogDiffs []EventLogDiff
+	banks := []struct {
+		eventLogs        []TpmEventLog
+		againstEventLogs []TpmEventLog
+	}{
+		{eventLogMap.Sha1EventLogs, againstEventLogMap.Sha1EventLogs},
+		{eventLogMap.Sha256EventLogs, againstEventLogMap.Sha256EventLogs},
+		{eventLogMap.Sha384EventLogs, againstEventLogMap.Sha384EventLogs},
+	}
+	for _, bank := range banks {
+		againstEventLogs := make(map[int]TpmEventLog)
+		for _, eventLog := range bank.againstEventLogs {
+			againstEventLogs[eventLog.Pcr.Index] = eventLog
+		}
+		indexes := make(map[int]bool)
+		for _, eventLog := range bank.eventLogs {
+			indexes[eventLog.Pcr.Index] = true
+			againstEventLog, ok := againstEventLogs[eventLog.Pcr.Index]
+			if !ok {
+				againstEventLog = TpmEventLog{Pcr: eventLog.Pcr}
+			}
+			added, _, addedErr := eventLog.Subtract(&againstEventLog)
+			removed, _, removedErr := againstEventLog.Subtract(&eventLog)
+			if addedErr != nil || removedErr != nil {
+				// the same PCR index is reported for another bank, nothing to compare with
+				continue
+			}
+			if len(added.TpmEvent) > 0 || len(removed.TpmEvent) > 0 {
+				eventLogDiffs = append(eventLogDiffs, EventLogDiff{Pcr: eventLog.Pcr, AddedEvents: added.TpmEvent,
+					RemovedEvents: removed.TpmEvent})
+			}
+		}
+		for _, againstEventLog := range bank.againstEventLogs {
+			if !indexes[againstEventLog.Pcr.Index] && len(againstEventLog.TpmEvent) > 0 {
+				eventLogDiffs = append(eventLogDiffs, EventLogDiff{Pcr: againstEventLog.Pcr,
+					RemovedEvents: againstEventLog.TpmEvent})
+			}
+		}
+	}
+	return eventLogDiffs
+}
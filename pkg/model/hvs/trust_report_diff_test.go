@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"testing"
+
+	constants "github.com/intel-secl/intel-secl/v4/pkg/hvs/constants/verifier-rules-and-faults"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustReportDiff(t *testing.T) {
+	againstReport := TrustReport{
+		Trusted: true,
+		Results: []RuleResult{
+			{
+				Rule: RuleInfo{
+					Name:        constants.RulePcrMatchesConstant,
+					Markers:     []FlavorPartName{FlavorPartPlatform},
+					ExpectedPcr: &FlavorPcrs{Pcr: Pcr{Index: 0, Bank: "SHA256"}, Measurement: "aa"},
+				},
+				Trusted: true,
+			},
+			{
+				Rule:    RuleInfo{Name: constants.RuleAikCertificateTrusted, Markers: []FlavorPartName{FlavorPartOs}},
+				Trusted: true,
+			},
+		},
+		HostManifest: HostManifest{
+			PcrManifest: PcrManifest{
+				Sha256Pcrs: []HostManifestPcrs{
+					{Index: 0, Value: "aa", PcrBank: SHA256},
+					{Index: 7, Value: "cc", PcrBank: SHA256},
+				},
+				PcrEventLogMap: PcrEventLogMap{
+					Sha256EventLogs: []TpmEventLog{
+						{
+							Pcr: Pcr{Index: 0, Bank: "SHA256"},
+							TpmEvent: []EventLog{
+								{TypeID: "0x80000008", Measurement: "11"},
+								{TypeID: "0x80000008", Measurement: "22"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pcrMismatch := "bb"
+	report := TrustReport{
+		Trusted: false,
+		Results: []RuleResult{
+			{
+				Rule: RuleInfo{
+					Name:        constants.RulePcrMatchesConstant,
+					Markers:     []FlavorPartName{FlavorPartPlatform},
+					ExpectedPcr: &FlavorPcrs{Pcr: Pcr{Index: 0, Bank: "SHA256"}, Measurement: "aa"},
+				},
+				Faults:  []Fault{{Name: constants.FaultPcrValueMismatchSHA256, ActualPcrValue: &pcrMismatch}},
+				Trusted: false,
+			},
+			{
+				Rule:    RuleInfo{Name: constants.RuleAikCertificateTrusted, Markers: []FlavorPartName{FlavorPartOs}},
+				Trusted: true,
+			},
+			{
+				Rule:    RuleInfo{Name: constants.RuleCustomRuleMatches, Markers: []FlavorPartName{FlavorPartOs}, CustomRule: &CustomRule{Name: "secure_boot"}},
+				Trusted: true,
+			},
+		},
+		HostManifest: HostManifest{
+			PcrManifest: PcrManifest{
+				Sha256Pcrs: []HostManifestPcrs{
+					{Index: 0, Value: "bb", PcrBank: SHA256},
+					{Index: 7, Value: "cc", PcrBank: SHA256},
+				},
+				PcrEventLogMap: PcrEventLogMap{
+					Sha256EventLogs: []TpmEventLog{
+						{
+							Pcr: Pcr{Index: 0, Bank: "SHA256"},
+							TpmEvent: []EventLog{
+								{TypeID: "0x80000008", Measurement: "11"},
+								{TypeID: "0x80000008", Measurement: "33"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	diff := report.Diff(&againstReport)
+	assert.False(t, diff.Trusted)
+	assert.True(t, diff.AgainstTrusted)
+
+	assert.Len(t, diff.FlavorParts, 2)
+	assert.Equal(t, FlavorPartPlatform.String(), diff.FlavorParts[0].FlavorPart)
+	assert.False(t, diff.FlavorParts[0].Trusted)
+	assert.True(t, diff.FlavorParts[0].AgainstTrusted)
+	assert.Len(t, diff.FlavorParts[0].RuleResults, 1)
+	assert.Equal(t, RuleResultChanged, diff.FlavorParts[0].RuleResults[0].Change)
+	assert.Equal(t, FlavorPartOs.String(), diff.FlavorParts[1].FlavorPart)
+	assert.Len(t, diff.FlavorParts[1].RuleResults, 1)
+	assert.Equal(t, RuleResultAdded, diff.FlavorParts[1].RuleResults[0].Change)
+	assert.Equal(t, constants.RuleCustomRuleMatches, diff.FlavorParts[1].RuleResults[0].RuleName)
+
+	assert.Equal(t, []PcrValueDiff{{PcrIndex: 0, PcrBank: SHA256, Value: "bb", AgainstValue: "aa"}}, diff.PcrValues)
+
+	assert.Len(t, diff.EventLogs, 1)
+	assert.Equal(t, []EventLog{{TypeID: "0x80000008", Measurement: "33"}}, diff.EventLogs[0].AddedEvents)
+	assert.Equal(t, []EventLog{{TypeID: "0x80000008", Measurement: "22"}}, diff.EventLogs[0].RemovedEvents)
+
+	// a report does not differ from itself
+	diff = report.Diff(&report)
+	assert.Empty(t, diff.FlavorParts)
+	assert.Empty(t, diff.PcrValues)
+	assert.Empty(t, diff.EventLogs)
+}
@@ -10,6 +10,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Key lifecycle states, modelled on the KMIP object states
+const (
+	KeyStatePreActive   = "pre-active"
+	KeyStateActive      = "active"
+	KeyStateDeactivated = "deactivated"
+	KeyStateCompromised = "compromised"
+)
+
 // KeyInformation - Contains required key related attributes for key create or register request.
 type KeyInformation struct {
 	// swagger:strfmt uuid
@@ -28,17 +36,37 @@ type KeyRequest struct {
 	TransferPolicyID uuid.UUID `json:"transfer_policy_id,omitempty"`
 	Label            string    `json:"label,omitempty"`
 	Usage            string    `json:"usage,omitempty"`
+	// NotBefore and NotAfter bound the period in which the key can be transferred
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
 }
 
 // KeyResponse - key attributes from key create or register response.
 type KeyResponse struct {
 	KeyInformation *KeyInformation `json:"key_information"`
 	// swagger:strfmt uuid
-	TransferPolicyID uuid.UUID `json:"transfer_policy_id"`
-	TransferLink     string    `json:"transfer_link"`
-	CreatedAt        time.Time `json:"created_at"`
-	Label            string    `json:"label,omitempty"`
-	Usage            string    `json:"usage,omitempty"`
+	TransferPolicyID uuid.UUID    `json:"transfer_policy_id"`
+	TransferLink     string       `json:"transfer_link"`
+	CreatedAt        time.Time    `json:"created_at"`
+	Label            string       `json:"label,omitempty"`
+	Usage            string       `json:"usage,omitempty"`
+	CurrentVersion   int          `json:"current_version,omitempty"`
+	Versions         []KeyVersion `json:"versions,omitempty"`
+	NotBefore        *time.Time   `json:"not_before,omitempty"`
+	NotAfter         *time.Time   `json:"not_after,omitempty"`
+	State            string       `json:"state"`
+}
+
+// KeyStateRequest - Requested lifecycle state change of a key.
+type KeyStateRequest struct {
+	State string `json:"state"`
+}
+
+// KeyVersion - Describes one version of a key that has been rotated.
+type KeyVersion struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Current   bool      `json:"current,omitempty"`
 }
 
 // KeyTransferAttributes - Contains all possible key transfer attributes.
@@ -49,6 +77,7 @@ type KeyTransferAttributes struct {
 	KeyData      string     `json:"payload,omitempty"`
 	KeyAlgorithm string     `json:"algorithm,omitempty"`
 	KeyLength    int        `json:"key_length,omitempty"`
+	KeyVersion   int        `json:"version,omitempty"`
 	CreatedAt    *time.Time `json:"created_at,omitempty"`
 	Policy       struct {
 		Link struct {
@@ -2,6 +2,7 @@ package ocicrypt_keyprovider
 
 type AnnotationPacket struct {
 	KeyUrl     string `json:"key_url"`
+	KeyVersion int    `json:"key_version,omitempty"`
 	WrappedKey []byte `json:"wrapped_key"`
 	WrapType   string `json:"wrap_type"`
 }
@@ -41,4 +41,5 @@ type TpmQuoteResponse struct {
 	}
 	IsTagProvisioned bool   `xml:"isTagProvisioned"`
 	AssetTag         string `xml:"assetTag,omitempty"`
+	ImaLog           string `xml:"imaLog,omitempty"`
 }
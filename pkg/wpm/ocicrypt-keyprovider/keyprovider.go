@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/containers/ocicrypt/keywrap/keyprovider"
+	"github.com/google/uuid"
 	cLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
 	ocicrypt_keyprovider "github.com/intel-secl/intel-secl/v4/pkg/model/ocicrypt"
 	"github.com/intel-secl/intel-secl/v4/pkg/wpm/config"
@@ -15,12 +16,16 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"io"
+	"net/url"
 	"os"
 	"strings"
 )
 
 var log = cLog.GetDefaultLogger()
 
+// fetchKeyVersion transfers a version of a key from KBS, it is a variable so that the KBS transfer can be stubbed
+var fetchKeyVersion = util.FetchKeyVersion
+
 // AES_GCM Helper Functions
 func aesEncrypt(kek []byte, symKey []byte) ([]byte, error) {
 	log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:aesEncrypt() Entering")
@@ -55,6 +60,95 @@ func aesEncrypt(kek []byte, symKey []byte) ([]byte, error) {
 	return json.Marshal(aesp)
 }
 
+func aesDecrypt(kek []byte, packet []byte) ([]byte, error) {
+	log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:aesDecrypt() Entering")
+	defer log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:aesDecrypt() Leaving")
+
+	if len(kek) != 32 {
+		return nil, errors.New("Expected 256 bit key")
+	}
+
+	var aesp ocicrypt_keyprovider.AesPacket
+	if err := json.Unmarshal(packet, &aesp); err != nil {
+		return nil, errors.Wrap(err, "Error while decoding AES packet")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(aesp.Nonce) != aesgcm.NonceSize() {
+		return nil, errors.New("Invalid nonce size in AES packet")
+	}
+
+	return aesgcm.Open(nil, aesp.Nonce, aesp.Ciphertext, nil)
+}
+
+// getKeyId returns the id of the key referenced by a KBS key transfer url of the form <kbs-url>/keys/<key-id>/transfer
+func getKeyId(keyUrl string) (string, error) {
+	log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:getKeyId() Entering")
+	defer log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:getKeyId() Leaving")
+
+	parsedUrl, err := url.Parse(keyUrl)
+	if err != nil {
+		return "", errors.Wrap(err, "Error parsing key url")
+	}
+
+	segments := strings.Split(strings.Trim(parsedUrl.Path, "/"), "/")
+	if len(segments) < 3 || segments[len(segments)-3] != "keys" || segments[len(segments)-1] != "transfer" {
+		return "", errors.Errorf("Key url %s is not a KBS key transfer url", keyUrl)
+	}
+
+	keyId, err := uuid.Parse(segments[len(segments)-2])
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid key id in key url")
+	}
+	return keyId.String(), nil
+}
+
+// unwrapKey transfers the key version referenced by the annotation from KBS and decrypts the layer options data with
+// it. Annotations created before key versions were recorded are unwrapped with the current version of the key.
+func unwrapKey(annotation []byte) ([]byte, error) {
+	log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:unwrapKey() Entering")
+	defer log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:unwrapKey() Leaving")
+
+	var ap ocicrypt_keyprovider.AnnotationPacket
+	if err := json.Unmarshal(annotation, &ap); err != nil {
+		return nil, errors.Wrap(err, "Error while decoding Annotation Packet")
+	}
+
+	if ap.WrapType != constants.KbsEncryptAlgo {
+		return nil, errors.Errorf("Unsupported wrap type %s", ap.WrapType)
+	}
+
+	keyId, err := getKeyId(ap.KeyUrl)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Unwrapping key %s version %d referenced by %s", keyId, ap.KeyVersion, ap.KeyUrl)
+
+	wrappedKey, _, _, err := fetchKeyVersion(keyId, "", ap.KeyVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while fetching key")
+	}
+	symKey, err := util.UnwrapKey(wrappedKey, constants.EnvelopePrivatekeyLocation)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while unwrapping the key")
+	}
+
+	optsData, err := aesDecrypt(symKey, ap.WrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while decrypting key")
+	}
+	return optsData, nil
+}
+
 func GetKey(stdInput *os.File) error {
 	log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:GetKey() Entering")
 	defer log.Trace("pkg/wpm/ocicrypt-keyprovider/keyprovider.go:GetKey() Leaving")
@@ -72,6 +166,7 @@ func GetKey(stdInput *os.File) error {
 	}
 	var symKey, wrappedKey []byte
 	var keyUrlString string
+	var keyVersion int
 
 	if input.Operation == keyprovider.OpKeyWrap {
 		ecParames := input.KeyWrapParams.Ec.Parameters
@@ -93,7 +188,7 @@ func GetKey(stdInput *os.File) error {
 
 			switch encCriteria {
 			case constants.OcicryptKeyProviderAssetTag:
-				wrappedKey, keyUrlString, err = util.FetchKey("", values)
+				wrappedKey, keyUrlString, keyVersion, err = util.FetchKeyVersion("", values, 0)
 				if err != nil {
 					return errors.Wrap(err, "Error while creating key")
 				}
@@ -103,7 +198,7 @@ func GetKey(stdInput *os.File) error {
 				}
 			case constants.OcicryptKeyProviderKeyId:
 				keyId := values
-				wrappedKey, keyUrlString, err = util.FetchKey(keyId, "")
+				wrappedKey, keyUrlString, keyVersion, err = util.FetchKeyVersion(keyId, "", 0)
 				if err != nil {
 					return errors.Wrap(err, "Error while fetching key")
 				}
@@ -113,7 +208,7 @@ func GetKey(stdInput *os.File) error {
 				}
 			default:
 				log.Info("Encryption criteria not provided, falling back to default criteria with creating new key for every layer")
-				wrappedKey, keyUrlString, err = util.FetchKey("", "")
+				wrappedKey, keyUrlString, keyVersion, err = util.FetchKeyVersion("", "", 0)
 				if err != nil {
 					return errors.Wrap(err, "Error while creating key")
 				}
@@ -124,7 +219,7 @@ func GetKey(stdInput *os.File) error {
 			}
 		} else {
 			log.Info("Encryption criteria not provided, falling back to default criteria with creating new key for every layer")
-			wrappedKey, keyUrlString, err = util.FetchKey("", "")
+			wrappedKey, keyUrlString, keyVersion, err = util.FetchKeyVersion("", "", 0)
 			if err != nil {
 				return errors.Wrap(err, "Error while creating key")
 			}
@@ -134,7 +229,21 @@ func GetKey(stdInput *os.File) error {
 			}
 		}
 	} else if input.Operation == keyprovider.OpKeyUnwrap {
-		return errors.Errorf("Operation %v not supported", input.Operation)
+		optsData, err := unwrapKey(input.KeyUnwrapParams.Annotation)
+		if err != nil {
+			return err
+		}
+
+		keyProviderOutput := keyprovider.KeyProviderKeyWrapProtocolOutput{
+			KeyUnwrapResults: keyprovider.KeyUnwrapResults{OptsData: optsData},
+		}
+		serializedKeyProviderOutput, err := json.Marshal(keyProviderOutput)
+		if err != nil {
+			return errors.Wrap(err, "Error while serializing KeyProviderKeyWrapProtocolOutput")
+		}
+
+		fmt.Println(string(serializedKeyProviderOutput))
+		return nil
 	} else {
 		return errors.Errorf("Operation %v not recognized", input.Operation)
 	}
@@ -147,6 +256,7 @@ func GetKey(stdInput *os.File) error {
 
 	ap := ocicrypt_keyprovider.AnnotationPacket{
 		KeyUrl:     keyUrlString,
+		KeyVersion: keyVersion,
 		WrappedKey: wrappedOCIKey,
 		WrapType:   constants.KbsEncryptAlgo,
 	}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ocicrypt_keyprovider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	ocicrypt_keyprovider "github.com/intel-secl/intel-secl/v4/pkg/model/ocicrypt"
+	"github.com/intel-secl/intel-secl/v4/pkg/wpm/constants"
+	"github.com/pkg/errors"
+)
+
+func TestAesEncryptDecrypt(t *testing.T) {
+	kek := make([]byte, 32)
+	if _, err := rand.Read(kek); err != nil {
+		t.Fatal(err)
+	}
+	optsData := []byte(`{"symkey":"c2VjcmV0","cipheroptions":{}}`)
+
+	packet, err := aesEncrypt(kek, optsData)
+	if err != nil {
+		t.Fatalf("aesEncrypt() error = %v", err)
+	}
+
+	decrypted, err := aesDecrypt(kek, packet)
+	if err != nil {
+		t.Fatalf("aesDecrypt() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, optsData) {
+		t.Error("aesDecrypt() returned data different from encrypted data")
+	}
+
+	kek[0] ^= 0xff
+	if _, err = aesDecrypt(kek, packet); err == nil {
+		t.Error("aesDecrypt() succeeded with a different key")
+	}
+	if _, err = aesDecrypt(kek[:16], packet); err == nil {
+		t.Error("aesDecrypt() succeeded with a 128 bit key")
+	}
+}
+
+func TestGetKeyId(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyUrl  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "valid key transfer url",
+			keyUrl: "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+			want:   "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+		},
+		{
+			name:    "url without transfer",
+			keyUrl:  "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+			wantErr: true,
+		},
+		{
+			name:    "invalid key id",
+			keyUrl:  "https://kbs.com:9443/kbs/v1/keys/fc0cc779/transfer",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getKeyId(tt.keyUrl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKeyId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getKeyId() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnwrapKeyVersion(t *testing.T) {
+	defer func(f func(string, string, int) ([]byte, string, int, error)) { fetchKeyVersion = f }(fetchKeyVersion)
+
+	var fetchedKeyId string
+	var fetchedVersion int
+	fetchKeyVersion = func(keyID string, assetTag string, version int) ([]byte, string, int, error) {
+		fetchedKeyId, fetchedVersion = keyID, version
+		return nil, "", 0, errors.New("key transfer stubbed")
+	}
+
+	tests := []struct {
+		name       string
+		keyVersion int
+	}{
+		{
+			name:       "annotation of a rotated key",
+			keyVersion: 2,
+		},
+		{
+			name:       "annotation without key version",
+			keyVersion: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotation, err := json.Marshal(ocicrypt_keyprovider.AnnotationPacket{
+				KeyUrl:     "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+				KeyVersion: tt.keyVersion,
+				WrapType:   constants.KbsEncryptAlgo,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = unwrapKey(annotation); err == nil {
+				t.Fatal("unwrapKey() succeeded with a stubbed key transfer")
+			}
+			if fetchedKeyId != "fc0cc779-22b6-4741-b0d9-e2e69635ad1e" {
+				t.Errorf("unwrapKey() fetched key %s", fetchedKeyId)
+			}
+			if fetchedVersion != tt.keyVersion {
+				t.Errorf("unwrapKey() fetched key version %d, want %d", fetchedVersion, tt.keyVersion)
+			}
+		})
+	}
+}
@@ -5,14 +5,11 @@
 package util
 
 import (
-	"bytes"
-	"crypto/aes"
-	"crypto/cipher"
+	"bufio"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha512"
 	"crypto/x509"
-	"encoding/binary"
 	"encoding/pem"
 	"github.com/intel-secl/intel-secl/v4/pkg/lib/common/crypt"
 	cLog "github.com/intel-secl/intel-secl/v4/pkg/lib/common/log"
@@ -20,68 +17,101 @@ import (
 	"github.com/pkg/errors"
 	"io"
 	"io/ioutil"
-	"unsafe"
+	"os"
 )
 
 var log = cLog.GetDefaultLogger()
 
+// Encrypt encrypts the image with the key wrapped by the envelope key, streaming the image in chunks so
+// that images larger than the available memory can be encrypted
 func Encrypt(imagePath string, privateKeyLocation string, encryptedFileLocation string, wrappedKey []byte) error {
 	log.Trace("pkg/wpm/util/encrypt.go:Encrypt() Entering")
 	defer log.Trace("pkg/wpm/util/encrypt.go:Encrypt() Leaving")
 
-	var encryptionHeader crypt.EncryptionHeader
-
-	// reading image file
-	image, err := ioutil.ReadFile(imagePath)
+	// opening image file
+	image, err := os.Open(imagePath)
 	if err != nil {
 		return errors.Wrap(err, "Error reading the image file")
 	}
+	defer func() {
+		derr := image.Close()
+		if derr != nil {
+			log.WithError(derr).Error("Error closing image file")
+		}
+	}()
 
 	key, err := UnwrapKey(wrappedKey, privateKeyLocation)
 	if err != nil {
 		return errors.Wrap(err, "Error while unwrapping the key")
 	}
-	// creating a new cipher block of 128 bits
-	block, err := aes.NewCipher(key)
+
+	log.Infof("pkg/util/encrypt.go:Encrypt() %s", cMsg.EncKeyUsed)
+
+	err = writeFile(encryptedFileLocation, func(w io.Writer) error {
+		return crypt.EncryptStream(key, image, w, crypt.DefaultEncryptionChunkSize)
+	})
 	if err != nil {
-		return errors.Wrap(err, "Error initializing cipher")
+		return errors.Wrap(err, "Error during writing the encrypted image to file")
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	log.Info("pkg/wpm/util/encrypt.go:Encrypt() Successfully encrypted image")
+	return nil
+}
+
+// Decrypt decrypts an image encrypted by Encrypt with the key wrapped by the envelope key. Images encrypted
+// as a single message by earlier releases are decrypted as well.
+func Decrypt(encryptedImagePath string, privateKeyLocation string, decryptedFileLocation string, wrappedKey []byte) error {
+	log.Trace("pkg/wpm/util/encrypt.go:Decrypt() Entering")
+	defer log.Trace("pkg/wpm/util/encrypt.go:Decrypt() Leaving")
+
+	encryptedImage, err := os.Open(encryptedImagePath)
 	if err != nil {
-		return errors.Wrap(err, "Error creating a cipher block")
+		return errors.Wrap(err, "Error reading the encrypted image file")
 	}
+	defer func() {
+		derr := encryptedImage.Close()
+		if derr != nil {
+			log.WithError(derr).Error("Error closing encrypted image file")
+		}
+	}()
 
-	log.Infof("pkg/util/encrypt.go:Encrypt() %s", cMsg.EncKeyUsed)
+	key, err := UnwrapKey(wrappedKey, privateKeyLocation)
+	if err != nil {
+		return errors.Wrap(err, "Error while unwrapping the key")
+	}
 
-	// assigning a 12 byte empty array to store random value
-	iv := make([]byte, 12)
-	// reading random value into the byte array
-	if _, err = io.ReadFull(rand.Reader, iv); err != nil {
-		return errors.Wrap(err, "Error creating random IV value")
+	err = writeFile(decryptedFileLocation, func(w io.Writer) error {
+		return crypt.DecryptStream(key, encryptedImage, w)
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error during writing the decrypted image to file")
 	}
 
-	copy(encryptionHeader.MagicText[:], crypt.EncryptionHeaderMagicText)
-	copy(encryptionHeader.EncryptionAlgorithm[:], crypt.GCMEncryptionAlgorithm)
-	copy(encryptionHeader.IV[:], iv)
-	copy(encryptionHeader.Version[:], crypt.EncryptionHeaderVersion)
-	encryptionHeader.OffsetInLittleEndian = uint32(unsafe.Sizeof(encryptionHeader))
+	log.Info("pkg/wpm/util/encrypt.go:Decrypt() Successfully decrypted image")
+	return nil
+}
 
-	encryptionHeaderSlice := &bytes.Buffer{}
-	err = binary.Write(encryptionHeaderSlice, binary.LittleEndian, encryptionHeader)
+// writeFile creates the file and streams the output of write into it, the file is removed if write fails
+func writeFile(fileLocation string, write func(io.Writer) error) error {
+	file, err := os.OpenFile(fileLocation, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
 	if err != nil {
-		return errors.Wrap(err, "Error while writing encryption header struc values in to buffer")
+		return err
 	}
 
-	// The first 44 bytes of the encrypted file is the encryption header and
-	// the rest is the data.
-	encryptedDataWithHeader := gcm.Seal(encryptionHeaderSlice.Bytes(), iv, image, nil)
-	err = ioutil.WriteFile(encryptedFileLocation, encryptedDataWithHeader, 0600)
+	writer := bufio.NewWriter(file)
+	err = write(writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
 	if err != nil {
-		return errors.Wrap(err, "Error during writing the encrypted image to file")
+		if rerr := os.Remove(fileLocation); rerr != nil {
+			log.WithError(rerr).Errorf("Error removing incomplete file %s", fileLocation)
+		}
+		return err
 	}
-
-	log.Info("pkg/wpm/util/encrypt.go:Encrypt() Successfully encrypted image")
 	return nil
 }
 
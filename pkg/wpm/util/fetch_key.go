@@ -29,26 +29,36 @@ func FetchKey(keyID string, assetTag string) ([]byte, string, error) {
 	log.Trace("pkg/wpm/util/encrypt.go:FetchKey() Entering")
 	defer log.Trace("pkg/wpm/util/encrypt.go:FetchKey() Leaving")
 
+	wrappedKey, keyUrlString, _, err := FetchKeyVersion(keyID, assetTag, 0)
+	return wrappedKey, keyUrlString, err
+}
+
+//FetchKeyVersion fetches the given version of the key from kbs, the current version when version is 0. The version
+//of the transferred key is returned along with the wrapped key and the key url
+func FetchKeyVersion(keyID string, assetTag string, version int) ([]byte, string, int, error) {
+	log.Trace("pkg/wpm/util/encrypt.go:FetchKeyVersion() Entering")
+	defer log.Trace("pkg/wpm/util/encrypt.go:FetchKeyVersion() Leaving")
+
 	viper.AddConfigPath(consts.ConfigDir)
 	cfg, err := config.LoadConfiguration()
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error loading WPM configuration")
+		return nil, "", 0, errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error loading WPM configuration")
 	}
 
 	aasUrl, err := url.Parse(cfg.AASApiUrl)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error parsing AAS url")
+		return nil, "", 0, errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error parsing AAS url")
 	}
 
 	kbsUrl, err := url.Parse(cfg.KBSApiUrl)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error parsing KBS url")
+		return nil, "", 0, errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error parsing KBS url")
 	}
 
 	//Load trusted CA certificates
 	caCerts, err := crypt.GetCertsFromDir(consts.TrustedCaCertsDir)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error loading CA certificates")
+		return nil, "", 0, errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error loading CA certificates")
 	}
 
 	//Initialize the KBS client
@@ -71,7 +81,7 @@ func FetchKey(keyID string, assetTag string) ([]byte, string, error) {
 		log.Debug("pkg/wpm/util/fetch_key.go:FetchKey() Creating new key")
 		keyResponse, err := kc.CreateKey(&keyRequest)
 		if err != nil {
-			return nil, "", errors.Wrap(err, "pkg/wpm/util/fetch_key.go:FetchKey() Error creating the image encryption key")
+			return nil, "", 0, errors.Wrap(err, "pkg/wpm/util/fetch_key.go:FetchKey() Error creating the image encryption key")
 		}
 
 		keyID = keyResponse.KeyInformation.ID.String()
@@ -82,7 +92,7 @@ func FetchKey(keyID string, assetTag string) ([]byte, string, error) {
 		//Build the key URL, to be inserted later on when the image flavor is created
 		keyUrl, err := url.Parse(cfg.KBSApiUrl + "/keys/" + keyID + "/transfer")
 		if err != nil {
-			return nil, "", errors.Wrap(err, "Error building KBS key URL")
+			return nil, "", 0, errors.Wrap(err, "Error building KBS key URL")
 		}
 		keyUrlString = keyUrl.String()
 	}
@@ -91,19 +101,19 @@ func FetchKey(keyID string, assetTag string) ([]byte, string, error) {
 
 	pubKey, err := ioutil.ReadFile(consts.EnvelopePublickeyLocation)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error reading envelop public key")
+		return nil, "", 0, errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error reading envelop public key")
 	}
 	//Retrieve key using key ID
-	keyValue, err := kc.TransferKey(keyID, string(pubKey))
+	keyValue, err := kc.TransferKeyVersion(keyID, string(pubKey), version)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/wpm/util/fetch_key.go:FetchKey() Error retrieving the image encryption key")
+		return nil, "", 0, errors.Wrap(err, "pkg/wpm/util/fetch_key.go:FetchKey() Error retrieving the image encryption key")
 	}
 	log.Info("pkg/wpm/util/fetch_key.go:FetchKey() Successfully retrieved key")
 	log.Debugf("pkg/util/fetch_key.go:FetchKey() %s", keyUrlString)
 
 	wrappedKey, err := base64.StdEncoding.DecodeString(keyValue.KeyData)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error decoding the image encryption key")
+		return nil, "", 0, errors.Wrap(err, "pkg/util/fetch_key.go:FetchKey() Error decoding the image encryption key")
 	}
-	return wrappedKey, keyUrlString, nil
+	return wrappedKey, keyUrlString, keyValue.KeyVersion, nil
 }
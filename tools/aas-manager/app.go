@@ -273,6 +273,8 @@ func (a *App) GetGlobalAdminUser() *UserAndRolesCreate {
 			urc.Roles = append(urc.Roles, NewRole("AAS", "Administrator", "", []string{"*:*:*"}))
 		}
 	}
+	// CMS is deployed with every component, the global admin revokes the certificates it issued
+	urc.Roles = append(urc.Roles, NewRole("CMS", "CertRevoker", "", nil))
 	return &urc
 }
 